@@ -6,8 +6,6 @@ import (
 	"log"
 	"net"
 	"net/http"
-	"path/filepath"
-	"runtime"
 
 	"github.com/fullstorydev/grpcui/standalone"
 	"github.com/gorilla/mux"
@@ -21,25 +19,42 @@ import (
 
 	handlersV1 "github.com/sergicanet9/go-hexagonal-api/app/handlers/v1"
 	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
 	"github.com/sergicanet9/go-hexagonal-api/core/ports"
 	"github.com/sergicanet9/go-hexagonal-api/core/services"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/cache"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/memory"
 	"github.com/sergicanet9/go-hexagonal-api/infrastructure/mongo"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/notifier"
 	"github.com/sergicanet9/go-hexagonal-api/infrastructure/postgres"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/publisher"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/sqlite"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/storage"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/webhook"
 	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
 	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
 	"github.com/sergicanet9/scv-go-tools/v4/api/middlewares"
-	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
 	"github.com/sergicanet9/scv-go-tools/v4/observability"
 )
 
 type api struct {
-	config      config.Config
-	services    svs
-	newrelicApp *newrelic.Application
+	config         config.Config
+	services       svs
+	newrelicApp    *newrelic.Application
+	userCacheStats func() models.CacheStats
+	replicaStatus  func() []models.ReplicaStatus
 }
 
 type svs struct {
-	user ports.UserService
+	user        ports.UserService
+	idempotency ports.IdempotencyService
+	privacy     ports.PrivacyService
+	audit       ports.AuditService
+	outbox      ports.OutboxService
+	webhook     ports.WebhookService
+	invitation  ports.InvitationService
+	avatar      ports.AvatarService
+	preferences ports.PreferencesService
 }
 
 // New creates a new API
@@ -48,39 +63,240 @@ func New(ctx context.Context, cfg config.Config, nrApp *newrelic.Application) (a
 	a.newrelicApp = nrApp
 
 	var userRepo ports.UserRepository
+	var userWatcher ports.UserWatcher
+	var idempotencyRepo ports.IdempotencyRepository
+	var tombstoneRepo ports.TombstoneRepository
+	var auditRepo ports.AuditRepository
+	var outboxRepo ports.OutboxRepository
+	var webhookSubscriptionRepo ports.WebhookSubscriptionRepository
+	var webhookDeliveryRepo ports.WebhookDeliveryRepository
+	var invitationRepo ports.InvitationRepository
+	var emailChangeRepo ports.EmailChangeRepository
+	var preferencesRepo ports.PreferencesRepository
+	var unitOfWork ports.UnitOfWork
 	switch a.config.Database {
 	case "mongo":
-		db, err := infrastructure.ConnectMongoDB(ctx, a.config.DSN)
+		db, err := connectMongo(ctx, a.config)
 		if err != nil {
 			observability.Logger().Fatal(err)
 		}
 
-		userRepo, err = mongo.NewUserRepository(ctx, db)
-		if err != nil {
-			observability.Logger().Fatal(err)
+		if a.config.MigrateAtStartup {
+			if err = mongo.Migrate(ctx, db); err != nil {
+				observability.Logger().Fatal(err)
+			}
 		}
+
+		userRepo = mongo.NewUserRepository(db)
+		userWatcher = mongo.NewUserWatcher(db)
+		idempotencyRepo = mongo.NewIdempotencyRepository(db)
+		tombstoneRepo = mongo.NewTombstoneRepository(db)
+		auditRepo = mongo.NewAuditRepository(db)
+		outboxRepo = mongo.NewOutboxRepository(db)
+		webhookSubscriptionRepo = mongo.NewWebhookSubscriptionRepository(db)
+		webhookDeliveryRepo = mongo.NewWebhookDeliveryRepository(db)
+		invitationRepo = mongo.NewInvitationRepository(db)
+		emailChangeRepo = mongo.NewEmailChangeRepository(db)
+		preferencesRepo = mongo.NewPreferencesRepository(db)
+		unitOfWork = mongo.NewUnitOfWork(db)
 	case "postgres":
-		db, err := infrastructure.ConnectPostgresDB(ctx, a.config.DSN)
+		db, err := connectPostgres(ctx, a.config)
 		if err != nil {
 			observability.Logger().Fatal(err)
 		}
 
-		_, filePath, _, _ := runtime.Caller(0)
-		migrationsDir := filepath.Join(filePath, "../../..", cfg.PostgresMigrationsDir)
-		err = infrastructure.MigratePostgresDB(db, migrationsDir)
+		if a.config.MigrateAtStartup {
+			if err = migratePostgres(a.config, db); err != nil {
+				observability.Logger().Fatal(err)
+			}
+		}
+
+		var replicas *postgres.Replicas
+		if len(a.config.PostgresReplicas.DSNs) > 0 {
+			replicas, err = postgres.ConnectReplicas(ctx, a.config.PostgresReplicas.DSNs, postgres.ReplicaOptions{
+				CheckInterval: a.config.PostgresReplicas.CheckInterval.Duration,
+				MaxLag:        a.config.PostgresReplicas.MaxLag.Duration,
+				Pool:          postgresPool(a.config),
+			})
+			if err != nil {
+				observability.Logger().Fatal(err)
+			}
+			a.replicaStatus = replicas.Status
+		}
+
+		userRepo = postgres.NewUserRepository(db, replicas)
+		userWatcher = postgres.NewUserWatcher(db, a.config.DSN)
+		idempotencyRepo = postgres.NewIdempotencyRepository(db)
+		tombstoneRepo = postgres.NewTombstoneRepository(db)
+		auditRepo = postgres.NewAuditRepository(db)
+		outboxRepo = postgres.NewOutboxRepository(db)
+		webhookSubscriptionRepo = postgres.NewWebhookSubscriptionRepository(db)
+		webhookDeliveryRepo = postgres.NewWebhookDeliveryRepository(db)
+		invitationRepo = postgres.NewInvitationRepository(db)
+		emailChangeRepo = postgres.NewEmailChangeRepository(db)
+		preferencesRepo = postgres.NewPreferencesRepository(db)
+		unitOfWork = postgres.NewUnitOfWork(db)
+	case "sqlite":
+		db, err := sqlite.Connect(ctx, a.config.DSN)
 		if err != nil {
 			observability.Logger().Fatal(err)
 		}
 
-		userRepo = postgres.NewUserRepository(db)
+		if a.config.MigrateAtStartup {
+			if err = migrateSQLite(a.config, db); err != nil {
+				observability.Logger().Fatal(err)
+			}
+		}
+
+		userRepo = sqlite.NewUserRepository(db)
+		userWatcher = sqlite.NewUserWatcher(db)
+		idempotencyRepo = sqlite.NewIdempotencyRepository(db)
+		tombstoneRepo = sqlite.NewTombstoneRepository(db)
+		auditRepo = sqlite.NewAuditRepository(db)
+		outboxRepo = sqlite.NewOutboxRepository(db)
+		webhookSubscriptionRepo = sqlite.NewWebhookSubscriptionRepository(db)
+		webhookDeliveryRepo = sqlite.NewWebhookDeliveryRepository(db)
+		invitationRepo = sqlite.NewInvitationRepository(db)
+		emailChangeRepo = sqlite.NewEmailChangeRepository(db)
+		preferencesRepo = sqlite.NewPreferencesRepository(db)
+		unitOfWork = sqlite.NewUnitOfWork(db)
+	case "memory":
+		outboxRepo = memory.NewOutboxRepository()
+		preferencesRepo = memory.NewPreferencesRepository()
+		invitationRepo = memory.NewInvitationRepository()
+		emailChangeRepo = memory.NewEmailChangeRepository()
+		userRepo, userWatcher = memory.NewUserRepository(outboxRepo, preferencesRepo, invitationRepo, emailChangeRepo)
+		idempotencyRepo = memory.NewIdempotencyRepository()
+		tombstoneRepo = memory.NewTombstoneRepository()
+		auditRepo = memory.NewAuditRepository()
+		webhookSubscriptionRepo = memory.NewWebhookSubscriptionRepository()
+		webhookDeliveryRepo = memory.NewWebhookDeliveryRepository()
+		unitOfWork = memory.NewUnitOfWork()
 	default:
 		observability.Logger().Fatalf("database flag %s not valid", a.config.Database)
 	}
 
-	a.services.user = services.NewUserService(a.config, userRepo)
+	var userCache ports.Cache
+	switch a.config.UserCache.Store {
+	case "none":
+	case "lru":
+		userCache = cache.NewLRU(a.config.UserCache.LRU.Size)
+	case "redis":
+		var err error
+		userCache, err = cache.NewRedis(ctx, cache.RedisOptions{
+			Addr:     a.config.UserCache.Redis.Addr,
+			Password: a.config.UserCache.Redis.Password,
+			DB:       a.config.UserCache.Redis.DB,
+			Prefix:   a.config.UserCache.Redis.Prefix,
+		})
+		if err != nil {
+			observability.Logger().Fatal(err)
+		}
+	default:
+		observability.Logger().Fatalf("user cache store %s not valid", a.config.UserCache.Store)
+	}
+	if userCache != nil {
+		var metrics *cache.Metrics
+		userRepo, unitOfWork, metrics = cache.NewUserRepository(userRepo, unitOfWork, userCache, cache.UserOptions{
+			TTL:             a.config.UserCache.TTL.Duration,
+			EmailTTL:        a.config.UserCache.EmailTTL.Duration,
+			InvalidationTTL: a.config.UserCache.InvalidationTTL.Duration,
+		})
+		a.userCacheStats = metrics.Stats
+	}
+
+	// the services defer the side effects of their mutations until the commit of the outermost unit of work
+	unitOfWork = services.NewUnitOfWork(unitOfWork)
+
+	if a.config.Idempotency.InMemory {
+		idempotencyRepo = memory.NewIdempotencyRepository()
+	}
+
+	a.services.webhook = services.NewWebhookService(a.config, webhookSubscriptionRepo, webhookDeliveryRepo, webhook.NewHTTPSender(&http.Client{Timeout: a.config.Timeout.Duration}))
+
+	var eventPublisher ports.EventPublisher
+	switch a.config.Outbox.Publisher {
+	case "subscriptions":
+		eventPublisher = a.services.webhook
+	case "log":
+		eventPublisher = publisher.NewLogPublisher()
+	case "memory":
+		eventPublisher = publisher.NewMemoryPublisher()
+	case "webhook":
+		eventPublisher = publisher.NewWebhookPublisher(a.config.Outbox.WebhookURL, &http.Client{Timeout: a.config.Timeout.Duration})
+	default:
+		observability.Logger().Fatalf("outbox publisher %s not valid", a.config.Outbox.Publisher)
+	}
+
+	var invitationNotifier ports.InvitationNotifier
+	switch a.config.Invitations.Notifier {
+	case "log":
+		invitationNotifier = notifier.NewLogNotifier()
+	case "webhook":
+		invitationNotifier = notifier.NewWebhookNotifier(a.config.Invitations.WebhookURL, &http.Client{Timeout: a.config.Timeout.Duration})
+	default:
+		observability.Logger().Fatalf("invitations notifier %s not valid", a.config.Invitations.Notifier)
+	}
+
+	var emailChangeNotifier ports.EmailChangeNotifier
+	switch a.config.EmailChanges.Notifier {
+	case "log":
+		emailChangeNotifier = notifier.NewEmailChangeLogNotifier()
+	case "webhook":
+		emailChangeNotifier = notifier.NewEmailChangeWebhookNotifier(a.config.EmailChanges.WebhookURL, &http.Client{Timeout: a.config.Timeout.Duration})
+	default:
+		observability.Logger().Fatalf("email changes notifier %s not valid", a.config.EmailChanges.Notifier)
+	}
+
+	var blobStorage ports.BlobStorage
+	switch a.config.Avatars.Storage {
+	case "local":
+		blobStorage = storage.NewLocalStorage(a.config.Avatars.Local.Dir, a.config.Avatars.Local.BaseURL)
+	case "s3":
+		var err error
+		blobStorage, err = storage.NewS3Storage(ctx, storage.S3Options{
+			Endpoint:        a.config.Avatars.S3.Endpoint,
+			Region:          a.config.Avatars.S3.Region,
+			Bucket:          a.config.Avatars.S3.Bucket,
+			AccessKeyID:     a.config.Avatars.S3.AccessKeyID,
+			SecretAccessKey: a.config.Avatars.S3.SecretAccessKey,
+			PublicURL:       a.config.Avatars.S3.PublicURL,
+		}, &http.Client{Timeout: a.config.Timeout.Duration})
+		if err != nil {
+			observability.Logger().Fatal(err)
+		}
+	default:
+		observability.Logger().Fatalf("avatars storage %s not valid", a.config.Avatars.Storage)
+	}
+
+	emailChangeService := services.NewEmailChangeService(a.config, userRepo, emailChangeRepo, emailChangeNotifier, unitOfWork)
+	a.services.user = services.NewAuditedUserService(services.NewUserService(a.config, userRepo, outboxRepo, userWatcher, emailChangeService, unitOfWork), auditRepo, unitOfWork)
+	a.services.idempotency = services.NewIdempotencyService(a.config, idempotencyRepo)
+	a.services.audit = services.NewAuditService(a.config, auditRepo)
+	a.services.avatar = services.NewAvatarService(a.config, userRepo, blobStorage, auditRepo, unitOfWork)
+	a.services.preferences = services.NewPreferencesService(a.config, userRepo, preferencesRepo)
+	a.services.outbox = services.NewOutboxService(a.config, outboxRepo, eventPublisher)
+	a.services.invitation = services.NewInvitationService(a.config, userRepo, invitationRepo, invitationNotifier, auditRepo, unitOfWork)
+	a.services.privacy = services.NewPrivacyService(a.config, userRepo, tombstoneRepo, auditRepo, unitOfWork,
+		a.services.audit, a.services.avatar, a.services.preferences, emailChangeService, a.services.invitation, a.services.outbox, a.services.webhook, a.services.user)
 	return a
 }
 
+// OutboxService returns the outbox service, dispatched by the async processes
+func (a *api) OutboxService() ports.OutboxService {
+	return a.services.outbox
+}
+
+// WebhookService returns the webhook service, whose deliveries are sent by the async processes
+func (a *api) WebhookService() ports.WebhookService {
+	return a.services.webhook
+}
+
+// UserService returns the user service, whose changes are pruned by the async processes
+func (a *api) UserService() ports.UserService {
+	return a.services.user
+}
+
 func (a *api) RunGRPC(ctx context.Context, cancel context.CancelFunc, grpcServerReady chan struct{}) func() error {
 	return func() error {
 		defer cancel()
@@ -90,11 +306,23 @@ func (a *api) RunGRPC(ctx context.Context, cancel context.CancelFunc, grpcServer
 			log.Fatalf("failed to listen on gRPC port: %s", err)
 		}
 
-		healthHander := handlersV1.NewHealthHandler(ctx, a.config)
+		healthHander := handlersV1.NewHealthHandler(ctx, a.config, a.userCacheStats, a.replicaStatus)
 		userHandler := handlersV1.NewUserHandler(ctx, a.config, a.services.user)
+		privacyHandler := handlersV1.NewPrivacyHandler(ctx, a.config, a.services.privacy)
+		auditHandler := handlersV1.NewAuditHandler(ctx, a.config, a.services.audit)
+		webhookHandler := handlersV1.NewWebhookHandler(ctx, a.config, a.services.webhook)
+		invitationHandler := handlersV1.NewInvitationHandler(ctx, a.config, a.services.invitation)
+		avatarHandler := handlersV1.NewAvatarHandler(ctx, a.config, a.services.avatar)
+		preferencesHandler := handlersV1.NewPreferencesHandler(ctx, a.config, a.services.preferences)
 
 		methodPolicies := []interceptors.MethodPolicy{}
 		methodPolicies = append(methodPolicies, userHandler.JWTMethodPolicies()...)
+		methodPolicies = append(methodPolicies, privacyHandler.JWTMethodPolicies()...)
+		methodPolicies = append(methodPolicies, auditHandler.JWTMethodPolicies()...)
+		methodPolicies = append(methodPolicies, webhookHandler.JWTMethodPolicies()...)
+		methodPolicies = append(methodPolicies, invitationHandler.JWTMethodPolicies()...)
+		methodPolicies = append(methodPolicies, avatarHandler.JWTMethodPolicies()...)
+		methodPolicies = append(methodPolicies, preferencesHandler.JWTMethodPolicies()...)
 
 		server := grpc.NewServer(
 			grpc.ChainUnaryInterceptor(
@@ -102,17 +330,26 @@ func (a *api) RunGRPC(ctx context.Context, cancel context.CancelFunc, grpcServer
 				interceptors.UnaryRecover(),
 				nrgrpc.UnaryServerInterceptor(a.newrelicApp),
 				interceptors.UnaryJWT(a.config.JWTSecret, methodPolicies),
+				unaryReadYourWrites(),
+				unaryIdempotency(a.services.idempotency, userHandler.IdempotentMethods()),
 			),
 			grpc.ChainStreamInterceptor(
 				interceptors.StreamLogger(),
 				interceptors.StreamRecover(),
 				nrgrpc.StreamServerInterceptor(a.newrelicApp),
 				interceptors.StreamJWT(a.config.JWTSecret, methodPolicies),
+				streamReadYourWrites(),
 			),
 		)
 
 		pb.RegisterHealthServiceServer(server, healthHander)
 		pb.RegisterUserServiceServer(server, userHandler)
+		pb.RegisterPrivacyServiceServer(server, privacyHandler)
+		pb.RegisterAuditServiceServer(server, auditHandler)
+		pb.RegisterWebhookServiceServer(server, webhookHandler)
+		pb.RegisterInvitationServiceServer(server, invitationHandler)
+		pb.RegisterAvatarServiceServer(server, avatarHandler)
+		pb.RegisterPreferencesServiceServer(server, preferencesHandler)
 
 		reflection.Register(server)
 
@@ -139,7 +376,7 @@ func (a *api) RunHTTP(ctx context.Context, cancel context.CancelFunc, grpcServer
 
 		grpcServerAddr := fmt.Sprintf(":%d", a.config.GRPCPort)
 
-		gmux := grpcRuntime.NewServeMux()
+		gmux := grpcRuntime.NewServeMux(grpcRuntime.WithIncomingHeaderMatcher(headerMatcher))
 		opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
 
 		err := pb.RegisterHealthServiceHandlerFromEndpoint(ctx, gmux, grpcServerAddr, opts)
@@ -153,11 +390,35 @@ func (a *api) RunHTTP(ctx context.Context, cancel context.CancelFunc, grpcServer
 
 		}
 
-		router := mux.NewRouter()
-		router.Use(middlewares.Logger("/swagger", "/docs.swagger.json", "/grpcui"))
-		router.Use(middlewares.Recover)
+		err = pb.RegisterPrivacyServiceHandlerFromEndpoint(ctx, gmux, grpcServerAddr, opts)
+		if err != nil {
+			observability.Logger().Fatalf("failed to register privacy handler gateway: %s", err)
+		}
 
-		v1Router := router.PathPrefix("/v1").Subrouter()
+		err = pb.RegisterAuditServiceHandlerFromEndpoint(ctx, gmux, grpcServerAddr, opts)
+		if err != nil {
+			observability.Logger().Fatalf("failed to register audit handler gateway: %s", err)
+		}
+
+		err = pb.RegisterWebhookServiceHandlerFromEndpoint(ctx, gmux, grpcServerAddr, opts)
+		if err != nil {
+			observability.Logger().Fatalf("failed to register webhook handler gateway: %s", err)
+		}
+
+		err = pb.RegisterInvitationServiceHandlerFromEndpoint(ctx, gmux, grpcServerAddr, opts)
+		if err != nil {
+			observability.Logger().Fatalf("failed to register invitation handler gateway: %s", err)
+		}
+
+		err = pb.RegisterAvatarServiceHandlerFromEndpoint(ctx, gmux, grpcServerAddr, opts)
+		if err != nil {
+			observability.Logger().Fatalf("failed to register avatar handler gateway: %s", err)
+		}
+
+		err = pb.RegisterPreferencesServiceHandlerFromEndpoint(ctx, gmux, grpcServerAddr, opts)
+		if err != nil {
+			observability.Logger().Fatalf("failed to register preferences handler gateway: %s", err)
+		}
 
 		conn, err := grpc.NewClient(grpcServerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 		if err != nil {
@@ -165,6 +426,32 @@ func (a *api) RunHTTP(ctx context.Context, cancel context.CancelFunc, grpcServer
 		}
 		defer conn.Close()
 
+		err = gmux.HandlePath(http.MethodPost, importUsersPath, importUsersHandler(gmux, pb.NewUserServiceClient(conn)))
+		if err != nil {
+			observability.Logger().Fatalf("failed to register import users handler: %s", err)
+		}
+
+		err = gmux.HandlePath(http.MethodGet, exportUsersPath, exportUsersHandler(gmux, pb.NewUserServiceClient(conn)))
+		if err != nil {
+			observability.Logger().Fatalf("failed to register export users handler: %s", err)
+		}
+
+		err = gmux.HandlePath(http.MethodGet, watchUsersPath, watchUsersHandler(gmux, pb.NewUserServiceClient(conn)))
+		if err != nil {
+			observability.Logger().Fatalf("failed to register watch users handler: %s", err)
+		}
+
+		err = gmux.HandlePath(http.MethodPut, uploadAvatarPath, uploadAvatarHandler(gmux, pb.NewAvatarServiceClient(conn), a.config.Avatars.MaxSize))
+		if err != nil {
+			observability.Logger().Fatalf("failed to register upload avatar handler: %s", err)
+		}
+
+		router := mux.NewRouter()
+		router.Use(middlewares.Logger("/swagger", "/docs.swagger.json", "/grpcui"))
+		router.Use(middlewares.Recover)
+
+		v1Router := router.PathPrefix("/v1").Subrouter()
+
 		grpcuiHandler, err := standalone.HandlerViaReflection(ctx, conn, grpcServerAddr)
 		if err != nil {
 			observability.Logger().Fatalf("error creating grpcui handler: %s", err)
@@ -175,6 +462,11 @@ func (a *api) RunHTTP(ctx context.Context, cancel context.CancelFunc, grpcServer
 		swaggerHandler := httpSwagger.Handler(httpSwagger.URL("/v1/docs.swagger.json"))
 		v1Router.PathPrefix("/swagger").Handler(swaggerHandler)
 
+		// the avatars of the local storage are served under /v1/blobs, which must be the path of its base URL
+		if a.config.Avatars.Storage == "local" {
+			v1Router.PathPrefix(blobsPathPrefix).Handler(http.StripPrefix("/v1"+blobsPathPrefix, blobsHandler(a.config.Avatars.Local.Dir)))
+		}
+
 		v1Router.PathPrefix("/").Handler(http.StripPrefix("/v1", gmux))
 
 		server := &http.Server{
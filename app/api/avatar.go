@@ -0,0 +1,88 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	grpcRuntime "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+const (
+	uploadAvatarPath      = "/users/{id}/avatar"
+	uploadAvatarFileField = "file"
+	blobsPathPrefix       = "/blobs/"
+)
+
+// uploadAvatarHandler proxies a multipart upload to the UploadAvatar RPC, taking the content type from the file part
+// or sniffing it from the content when the part does not declare a specific one.
+// At most one byte more than the maximum size is read, so that larger files are rejected by the service without being buffered.
+func uploadAvatarHandler(gmux *grpcRuntime.ServeMux, client pb.AvatarServiceClient, maxSize int) grpcRuntime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		_, outbound := grpcRuntime.MarshalerForRequest(gmux, r)
+
+		ctx, err := grpcRuntime.AnnotateContext(r.Context(), gmux, r, pb.AvatarService_UploadAvatar_FullMethodName)
+		if err != nil {
+			grpcRuntime.HTTPError(r.Context(), gmux, outbound, w, r, err)
+			return
+		}
+
+		reader, err := r.MultipartReader()
+		if err != nil {
+			grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, utils.ToGRPC(wrappers.NewValidationErr(err)))
+			return
+		}
+
+		req := &pb.UploadAvatarRequest{Id: pathParams["id"]}
+		for {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				err = fmt.Errorf("multipart field %s is missing", uploadAvatarFileField)
+			}
+			if err != nil {
+				grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, utils.ToGRPC(wrappers.NewValidationErr(err)))
+				return
+			}
+			if part.FormName() != uploadAvatarFileField {
+				continue
+			}
+
+			req.ContentType = part.Header.Get("Content-Type")
+			req.Content, err = io.ReadAll(io.LimitReader(part, int64(maxSize)+1))
+			part.Close()
+			if err != nil {
+				grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, utils.ToGRPC(wrappers.NewValidationErr(err)))
+				return
+			}
+			if req.ContentType == "" || req.ContentType == "application/octet-stream" {
+				req.ContentType = http.DetectContentType(req.Content)
+			}
+			break
+		}
+
+		resp, err := client.UploadAvatar(ctx, req)
+		if err != nil {
+			grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, err)
+			return
+		}
+
+		grpcRuntime.ForwardResponseMessage(ctx, gmux, outbound, w, r, resp)
+	}
+}
+
+// blobsHandler serves the objects of the local blob storage, without listing the directories
+func blobsHandler(dir string) http.Handler {
+	fileServer := http.FileServer(http.Dir(dir))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "" || strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		fileServer.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"runtime"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/mongo"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/postgres"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/sqlite"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	mongoDriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+// Migrate connects to the database of the config and applies its pending migrations, so that they can be run before the API starts.
+// The memory database has nothing to migrate.
+func Migrate(ctx context.Context, cfg config.Config) error {
+	switch cfg.Database {
+	case "mongo":
+		db, err := connectMongo(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		defer db.Client().Disconnect(context.WithoutCancel(ctx))
+
+		return mongo.Migrate(ctx, db)
+	case "postgres":
+		db, err := connectPostgres(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		return migratePostgres(cfg, db)
+	case "sqlite":
+		db, err := sqlite.Connect(ctx, cfg.DSN)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		return migrateSQLite(cfg, db)
+	case "memory":
+		return nil
+	default:
+		return fmt.Errorf("database flag %s not valid", cfg.Database)
+	}
+}
+
+// connectMongo connects to the mongo database of the config, waiting for its primary to be reachable
+func connectMongo(ctx context.Context, cfg config.Config) (*mongoDriver.Database, error) {
+	db, err := mongo.Connect(ctx, cfg.DSN, mongo.ClientOptions{
+		MaxPoolSize:      cfg.MongoClient.MaxPoolSize,
+		MinPoolSize:      cfg.MongoClient.MinPoolSize,
+		MaxConnIdleTime:  cfg.MongoClient.MaxConnIdleTime.Duration,
+		OperationTimeout: cfg.MongoClient.OperationTimeout.Duration,
+		ReadPreference:   cfg.MongoClient.ReadPreference,
+		WriteConcern:     cfg.MongoClient.WriteConcern,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = connectWithRetry(ctx, cfg, "mongo", func(ctx context.Context) error {
+		return mongo.Ping(ctx, db)
+	})
+	return db, err
+}
+
+// connectPostgres opens the postgres database of the config, waiting for it to be reachable
+func connectPostgres(ctx context.Context, cfg config.Config) (*sql.DB, error) {
+	db, err := postgres.Open(cfg.DSN, postgresPool(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	err = connectWithRetry(ctx, cfg, "postgres", db.PingContext)
+	return db, err
+}
+
+func postgresPool(cfg config.Config) postgres.PoolOptions {
+	return postgres.PoolOptions{
+		MaxOpenConns:     cfg.PostgresPool.MaxOpenConns,
+		MaxIdleConns:     cfg.PostgresPool.MaxIdleConns,
+		ConnMaxLifetime:  cfg.PostgresPool.ConnMaxLifetime.Duration,
+		ConnMaxIdleTime:  cfg.PostgresPool.ConnMaxIdleTime.Duration,
+		StatementTimeout: cfg.PostgresPool.StatementTimeout.Duration,
+	}
+}
+
+func migratePostgres(cfg config.Config, db *sql.DB) error {
+	return infrastructure.MigratePostgresDB(db, migrationsDir(cfg.PostgresMigrationsDir))
+}
+
+func migrateSQLite(cfg config.Config, db *sql.DB) error {
+	return sqlite.Migrate(db, migrationsDir(cfg.SQLiteMigrationsDir))
+}
+
+// migrationsDir returns the absolute path of a migrations directory relative to the root of the project
+func migrationsDir(dir string) string {
+	_, filePath, _, _ := runtime.Caller(0)
+	return filepath.Join(filePath, "../../..", dir)
+}
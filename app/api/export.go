@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	grpcRuntime "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const exportUsersPath = "/users/export"
+
+// exportContentTypes content type of the downloaded file of each export format
+var exportContentTypes = map[pb.ExportFormat]string{
+	pb.ExportFormat_EXPORT_FORMAT_CSV:     "text/csv",
+	pb.ExportFormat_EXPORT_FORMAT_NDJSON:  "application/x-ndjson",
+	pb.ExportFormat_EXPORT_FORMAT_PARQUET: "application/vnd.apache.parquet",
+}
+
+// exportUsersHandler proxies the server-streaming ExportUsers RPC as a chunked file download.
+// The query parameters are format (csv by default), columns (comma separated), created_from, created_to (RFC 3339) and claim_id.
+func exportUsersHandler(gmux *grpcRuntime.ServeMux, client pb.UserServiceClient) grpcRuntime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		_, outbound := grpcRuntime.MarshalerForRequest(gmux, r)
+
+		ctx, err := grpcRuntime.AnnotateContext(r.Context(), gmux, r, pb.UserService_ExportUsers_FullMethodName)
+		if err != nil {
+			grpcRuntime.HTTPError(r.Context(), gmux, outbound, w, r, err)
+			return
+		}
+
+		req, err := parseExportUsersRequest(r)
+		if err != nil {
+			grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, utils.ToGRPC(wrappers.NewValidationErr(err)))
+			return
+		}
+
+		stream, err := client.ExportUsers(ctx, req)
+		if err != nil {
+			grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, err)
+			return
+		}
+
+		// the first chunk is awaited so that errors returned before streaming can still be mapped to an HTTP status
+		resp, err := stream.Recv()
+		if err != nil && !errors.Is(err, io.EOF) {
+			grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, err)
+			return
+		}
+
+		format := strings.ToLower(strings.TrimPrefix(req.Format.String(), "EXPORT_FORMAT_"))
+		w.Header().Set("Content-Type", exportContentTypes[req.Format])
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="users.%s"`, format))
+		w.WriteHeader(http.StatusOK)
+
+		rc := http.NewResponseController(w)
+		for err == nil {
+			if _, err = w.Write(resp.Chunk); err != nil {
+				return
+			}
+			rc.Flush()
+
+			resp, err = stream.Recv()
+		}
+
+		// the status has already been sent, so an error at this point can only cut the download short
+		if !errors.Is(err, io.EOF) {
+			observability.Logger().Printf("export of users interrupted: %s", err)
+		}
+	}
+}
+
+func parseExportUsersRequest(r *http.Request) (*pb.ExportUsersRequest, error) {
+	query := r.URL.Query()
+	req := &pb.ExportUsersRequest{
+		Format: pb.ExportFormat_EXPORT_FORMAT_CSV,
+		Filter: &pb.UserFilter{},
+	}
+
+	if format := query.Get("format"); format != "" {
+		value, ok := pb.ExportFormat_value["EXPORT_FORMAT_"+strings.ToUpper(format)]
+		if !ok {
+			return nil, fmt.Errorf("export format %s not valid", format)
+		}
+		req.Format = pb.ExportFormat(value)
+	}
+
+	if columns := query.Get("columns"); columns != "" {
+		for _, column := range strings.Split(columns, ",") {
+			req.Columns = append(req.Columns, strings.TrimSpace(column))
+		}
+	}
+
+	var err error
+	if req.Filter.CreatedFrom, err = parseTimestamp(query.Get("created_from"), "created_from"); err != nil {
+		return nil, err
+	}
+	if req.Filter.CreatedTo, err = parseTimestamp(query.Get("created_to"), "created_to"); err != nil {
+		return nil, err
+	}
+
+	if value := query.Get("claim_id"); value != "" {
+		claimID, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("claim_id %s not valid", value)
+		}
+		id := int32(claimID)
+		req.Filter.ClaimId = &id
+	}
+
+	return req, nil
+}
+
+// parseTimestamp parses an optional RFC 3339 query parameter, returning nil when it is empty
+func parseTimestamp(value, param string) (*timestamppb.Timestamp, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 timestamp", param)
+	}
+	return timestamppb.New(t), nil
+}
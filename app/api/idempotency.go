@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+	grpcRuntime "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	handlersV1 "github.com/sergicanet9/go-hexagonal-api/app/handlers/v1"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// idempotencyKey is the gRPC metadata key, and the HTTP header, that carries the idempotency key of a request
+const idempotencyKey = "idempotency-key"
+
+// unaryIdempotency is a gRPC unary interceptor that replays the stored response of the given methods
+// when a request is retried with an idempotency key that was already used.
+// The key is reserved before the request is handled, so that the retries sent while it is in progress are rejected instead of handled twice.
+// Only successful responses are stored, and the key is released when the request fails, so failed requests can be retried with the same key.
+func unaryIdempotency(svc ports.IdempotencyService, methods []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !slices.Contains(methods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		keys := metadata.ValueFromIncomingContext(ctx, idempotencyKey)
+		if len(keys) == 0 || keys[0] == "" {
+			return handler(ctx, req)
+		}
+		key := keys[0]
+		subject := subject(ctx)
+
+		requestHash, err := hashRequest(info.FullMethod, req)
+		if err != nil {
+			return nil, utils.ToGRPC(err)
+		}
+
+		token, stored, found, err := svc.Reserve(ctx, subject, key, requestHash)
+		if err != nil {
+			return nil, handlersV1.ToGRPC(err)
+		}
+		if found {
+			return unmarshalResponse(stored)
+		}
+
+		// the key is stored or released even when the request gets canceled
+		storeCtx := context.WithoutCancel(ctx)
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			if releaseErr := svc.Release(storeCtx, subject, key, token); releaseErr != nil {
+				observability.Logger().Printf("could not release idempotency key %s: %s", key, releaseErr)
+			}
+			return nil, err
+		}
+
+		b, err := marshalResponse(resp)
+		if err == nil {
+			err = svc.Store(storeCtx, subject, key, token, b)
+		}
+		if err != nil {
+			observability.Logger().Printf("could not store the response for idempotency key %s: %s", key, err)
+		}
+
+		return resp, nil
+	}
+}
+
+// subject returns the user_id claim of the token that authorized the request, or an empty subject for anonymous requests
+func subject(ctx context.Context) string {
+	claims, _ := ctx.Value(interceptors.ClaimsKey).(jwt.MapClaims)
+	ID, _ := claims["user_id"].(string)
+	return ID
+}
+
+// hashRequest computes a hash of the method and the request payload, so that a key reused
+// for a different method or payload can be detected
+func hashRequest(method string, req interface{}) (string, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return "", fmt.Errorf("request of method %s is not a protobuf message", method)
+	}
+
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write(b)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func marshalResponse(resp interface{}) ([]byte, error) {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("response is not a protobuf message")
+	}
+
+	a, err := anypb.New(msg)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(a)
+}
+
+func unmarshalResponse(b []byte) (interface{}, error) {
+	var a anypb.Any
+	if err := proto.Unmarshal(b, &a); err != nil {
+		return nil, utils.ToGRPC(err)
+	}
+
+	resp, err := a.UnmarshalNew()
+	if err != nil {
+		return nil, utils.ToGRPC(err)
+	}
+	return resp, nil
+}
+
+// headerMatcher forwards the idempotency key and request ID HTTP headers to the gRPC server along with the default headers
+func headerMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, idempotencyKey) {
+		return idempotencyKey, true
+	}
+	if strings.EqualFold(key, handlersV1.RequestIDKey) {
+		return handlersV1.RequestIDKey, true
+	}
+	return grpcRuntime.DefaultHeaderMatcher(key)
+}
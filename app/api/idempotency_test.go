@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// idempotentTestContext returns the context of a request of the test-subject user sent with the test-key idempotency key
+func idempotentTestContext() context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKey, "test-key"))
+	return context.WithValue(ctx, interceptors.ClaimsKey, jwt.MapClaims{"user_id": "test-subject"})
+}
+
+var idempotentTestInfo = &grpc.UnaryServerInfo{FullMethod: pb.UserService_Create_FullMethodName}
+
+// TestUnaryIdempotency_Ok checks that unaryIdempotency reserves the key of the subject, handles the request and stores its response
+func TestUnaryIdempotency_Ok(t *testing.T) {
+	// Arrange
+	svc := mocks.NewIdempotencyService(t)
+	svc.On(testutils.FunctionName(t, ports.IdempotencyService.Reserve), mock.Anything, "test-subject", "test-key", mock.AnythingOfType("string")).Return("test-token", nil, false, nil).Once()
+	svc.On(testutils.FunctionName(t, ports.IdempotencyService.Store), mock.Anything, "test-subject", "test-key", "test-token", mock.AnythingOfType("[]uint8")).Return(nil).Once()
+	interceptor := unaryIdempotency(svc, []string{idempotentTestInfo.FullMethod})
+	expectedResp := &pb.CreateUserResponse{Id: "test-id"}
+
+	// Act
+	resp, err := interceptor(idempotentTestContext(), &pb.CreateUserRequest{}, idempotentTestInfo, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return expectedResp, nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResp, resp)
+}
+
+// TestUnaryIdempotency_Replayed checks that unaryIdempotency returns the stored response without handling the request again
+func TestUnaryIdempotency_Replayed(t *testing.T) {
+	// Arrange
+	expectedResp := &pb.CreateUserResponse{Id: "test-id"}
+	stored, err := marshalResponse(expectedResp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := mocks.NewIdempotencyService(t)
+	svc.On(testutils.FunctionName(t, ports.IdempotencyService.Reserve), mock.Anything, "test-subject", "test-key", mock.AnythingOfType("string")).Return("", stored, true, nil).Once()
+	interceptor := unaryIdempotency(svc, []string{idempotentTestInfo.FullMethod})
+
+	// Act
+	resp, err := interceptor(idempotentTestContext(), &pb.CreateUserRequest{}, idempotentTestInfo, func(ctx context.Context, req interface{}) (interface{}, error) {
+		t.Fatal("the request should not be handled")
+		return nil, nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResp.Id, resp.(*pb.CreateUserResponse).Id)
+}
+
+// TestUnaryIdempotency_InProgress checks that unaryIdempotency rejects with codes.Aborted a retry sent while the request is in progress
+func TestUnaryIdempotency_InProgress(t *testing.T) {
+	// Arrange
+	svc := mocks.NewIdempotencyService(t)
+	svc.On(testutils.FunctionName(t, ports.IdempotencyService.Reserve), mock.Anything, "test-subject", "test-key", mock.AnythingOfType("string")).Return("", nil, false, entities.NewConflictErr(errors.New("in progress"))).Once()
+	interceptor := unaryIdempotency(svc, []string{idempotentTestInfo.FullMethod})
+
+	// Act
+	_, err := interceptor(idempotentTestContext(), &pb.CreateUserRequest{}, idempotentTestInfo, func(ctx context.Context, req interface{}) (interface{}, error) {
+		t.Fatal("the request should not be handled")
+		return nil, nil
+	})
+
+	// Assert
+	assert.Equal(t, codes.Aborted, status.Code(err))
+}
+
+// TestUnaryIdempotency_HandlerError checks that unaryIdempotency releases the key when the request fails, so that it can be retried
+func TestUnaryIdempotency_HandlerError(t *testing.T) {
+	// Arrange
+	svc := mocks.NewIdempotencyService(t)
+	svc.On(testutils.FunctionName(t, ports.IdempotencyService.Reserve), mock.Anything, "test-subject", "test-key", mock.AnythingOfType("string")).Return("test-token", nil, false, nil).Once()
+	svc.On(testutils.FunctionName(t, ports.IdempotencyService.Release), mock.Anything, "test-subject", "test-key", "test-token").Return(nil).Once()
+	interceptor := unaryIdempotency(svc, []string{idempotentTestInfo.FullMethod})
+	expectedError := errors.New("handler-error")
+
+	// Act
+	_, err := interceptor(idempotentTestContext(), &pb.CreateUserRequest{}, idempotentTestInfo, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, expectedError
+	})
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
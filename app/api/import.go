@@ -0,0 +1,137 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	grpcRuntime "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+const (
+	importUsersPath   = "/users/import"
+	importChunkSize   = 64 * 1024
+	importFileField   = "file"
+	importFormatField = "format"
+	importDryRunField = "dry_run"
+)
+
+// importUsersHandler proxies a multipart upload to the client-streaming ImportUsers RPC, so that the file is never fully buffered.
+// The form fields format and dry_run must precede the file part, and the format defaults to the file extension when not provided.
+func importUsersHandler(gmux *grpcRuntime.ServeMux, client pb.UserServiceClient) grpcRuntime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		_, outbound := grpcRuntime.MarshalerForRequest(gmux, r)
+
+		ctx, err := grpcRuntime.AnnotateContext(r.Context(), gmux, r, pb.UserService_ImportUsers_FullMethodName)
+		if err != nil {
+			grpcRuntime.HTTPError(r.Context(), gmux, outbound, w, r, err)
+			return
+		}
+
+		reader, err := r.MultipartReader()
+		if err != nil {
+			grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, utils.ToGRPC(wrappers.NewValidationErr(err)))
+			return
+		}
+
+		first := &pb.ImportUsersRequest{}
+		var file *multipart.Part
+		for file == nil {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				err = fmt.Errorf("multipart field %s is missing", importFileField)
+			}
+			if err != nil {
+				grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, utils.ToGRPC(wrappers.NewValidationErr(err)))
+				return
+			}
+
+			switch part.FormName() {
+			case importFileField:
+				file = part
+			case importFormatField:
+				value, _ := io.ReadAll(part)
+				first.Format = parseImportFormat(string(value))
+			case importDryRunField:
+				value, _ := io.ReadAll(part)
+				first.DryRun, err = strconv.ParseBool(string(value))
+				if err != nil {
+					grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, utils.ToGRPC(wrappers.NewValidationErr(fmt.Errorf("multipart field %s not valid", importDryRunField))))
+					return
+				}
+			}
+		}
+		defer file.Close()
+
+		if first.Format == pb.ImportFormat_IMPORT_FORMAT_UNSPECIFIED {
+			first.Format = parseImportFormat(strings.TrimPrefix(filepath.Ext(file.FileName()), "."))
+		}
+
+		stream, err := client.ImportUsers(ctx)
+		if err != nil {
+			grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, err)
+			return
+		}
+
+		req := first
+		sent := false
+		buf := make([]byte, importChunkSize)
+		for {
+			n, readErr := file.Read(buf)
+			if n > 0 {
+				req.Chunk = buf[:n]
+				sent = true
+				if err = stream.Send(req); err != nil {
+					break
+				}
+				req = &pb.ImportUsersRequest{}
+			}
+			if errors.Is(readErr, io.EOF) {
+				break
+			}
+			if readErr != nil {
+				grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, utils.ToGRPC(wrappers.NewValidationErr(readErr)))
+				return
+			}
+		}
+
+		// the first message carries the options even when the file is empty
+		if !sent {
+			if err = stream.Send(first); err != nil && !errors.Is(err, io.EOF) {
+				grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, err)
+				return
+			}
+		}
+
+		// errors while sending are reported by CloseAndRecv with the status returned by the server
+		resp, err := stream.CloseAndRecv()
+		if err != nil {
+			grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, err)
+			return
+		}
+
+		grpcRuntime.ForwardResponseMessage(ctx, gmux, outbound, w, r, resp)
+	}
+}
+
+// parseImportFormat maps a format name such as csv or ndjson to its protobuf value
+func parseImportFormat(format string) pb.ImportFormat {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "jsonl" {
+		format = "ndjson"
+	}
+
+	value, ok := pb.ImportFormat_value["IMPORT_FORMAT_"+strings.ToUpper(format)]
+	if !ok {
+		return pb.ImportFormat_IMPORT_FORMAT_UNSPECIFIED
+	}
+	return pb.ImportFormat(value)
+}
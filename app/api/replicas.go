@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/postgres"
+	"google.golang.org/grpc"
+)
+
+// unaryReadYourWrites is a gRPC unary interceptor that serves the reads of a request from the primary database once it has written to it,
+// so that a request reads its own writes when the reads are served by replicas
+func unaryReadYourWrites() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(postgres.WithReadYourWrites(ctx), req)
+	}
+}
+
+// streamReadYourWrites is the gRPC stream interceptor counterpart of unaryReadYourWrites
+func streamReadYourWrites() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &readYourWritesStream{ServerStream: ss, ctx: postgres.WithReadYourWrites(ss.Context())})
+	}
+}
+
+// readYourWritesStream server stream carrying the context of streamReadYourWrites
+type readYourWritesStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *readYourWritesStream) Context() context.Context {
+	return s.ctx
+}
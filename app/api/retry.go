@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+// connectWithRetry calls connect until it succeeds or ConnectRetry.MaxAttempts are made, so that the API survives databases that are still starting.
+// The delay between the attempts doubles from ConnectRetry.InitialBackoff up to ConnectRetry.MaxBackoff, and every attempt is bounded by the global timeout.
+func connectWithRetry(ctx context.Context, cfg config.Config, name string, connect func(ctx context.Context) error) error {
+	delay := cfg.ConnectRetry.InitialBackoff.Duration
+	for attempt := 1; ; attempt++ {
+		err := connectAttempt(ctx, cfg.Timeout.Duration, connect)
+		if err == nil || attempt >= cfg.ConnectRetry.MaxAttempts {
+			return err
+		}
+
+		observability.Logger().Printf("failed to connect to %s (attempt %d of %d), retrying in %s: %s", name, attempt, cfg.ConnectRetry.MaxAttempts, delay, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, cfg.ConnectRetry.MaxBackoff.Duration)
+	}
+}
+
+func connectAttempt(ctx context.Context, timeout time.Duration, connect func(ctx context.Context) error) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return connect(ctx)
+}
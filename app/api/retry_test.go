@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func retryTestConfig(maxAttempts int) config.Config {
+	cfg := config.Config{}
+	cfg.Timeout = utils.Duration{Duration: time.Second}
+	cfg.ConnectRetry.MaxAttempts = maxAttempts
+	cfg.ConnectRetry.InitialBackoff = utils.Duration{Duration: time.Millisecond}
+	cfg.ConnectRetry.MaxBackoff = utils.Duration{Duration: 2 * time.Millisecond}
+	return cfg
+}
+
+// TestConnectWithRetry_Ok checks that connectWithRetry retries connect until it succeeds
+func TestConnectWithRetry_Ok(t *testing.T) {
+	// Arrange
+	attempts := 0
+	connect := func(ctx context.Context) error {
+		attempts++
+		if _, ok := ctx.Deadline(); !ok {
+			return errors.New("no deadline")
+		}
+		if attempts < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	// Act
+	err := connectWithRetry(context.Background(), retryTestConfig(5), "test", connect)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+// TestConnectWithRetry_MaxAttempts checks that connectWithRetry returns the last error once the max attempts are made
+func TestConnectWithRetry_MaxAttempts(t *testing.T) {
+	// Arrange
+	attempts := 0
+	connect := func(ctx context.Context) error {
+		attempts++
+		return errors.New("connection refused")
+	}
+
+	// Act
+	err := connectWithRetry(context.Background(), retryTestConfig(3), "test", connect)
+
+	// Assert
+	assert.EqualError(t, err, "connection refused")
+	assert.Equal(t, 3, attempts)
+}
+
+// TestConnectWithRetry_Canceled checks that connectWithRetry stops retrying when the context is done
+func TestConnectWithRetry_Canceled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	connect := func(ctx context.Context) error {
+		attempts++
+		cancel()
+		return errors.New("connection refused")
+	}
+
+	// Act
+	err := connectWithRetry(ctx, retryTestConfig(5), "test", connect)
+
+	// Assert
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	grpcRuntime "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+	"google.golang.org/grpc/status"
+)
+
+const watchUsersPath = "/users/watch"
+
+// watchUsersHandler proxies the server-streaming WatchUsers RPC as server-sent events.
+// Every change is sent as an event named after its type, with the change as JSON data and its resume token as ID,
+// so that clients reconnecting with the Last-Event-ID header resume right after the last received change.
+// The resume token can also be given in the resume_token query parameter.
+func watchUsersHandler(gmux *grpcRuntime.ServeMux, client pb.UserServiceClient) grpcRuntime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		_, outbound := grpcRuntime.MarshalerForRequest(gmux, r)
+
+		ctx, err := grpcRuntime.AnnotateContext(r.Context(), gmux, r, pb.UserService_WatchUsers_FullMethodName)
+		if err != nil {
+			grpcRuntime.HTTPError(r.Context(), gmux, outbound, w, r, err)
+			return
+		}
+
+		req := &pb.WatchUsersRequest{
+			ResumeToken: r.Header.Get("Last-Event-ID"),
+		}
+		if resumeToken := r.URL.Query().Get("resume_token"); resumeToken != "" {
+			req.ResumeToken = resumeToken
+		}
+
+		stream, err := client.WatchUsers(ctx, req)
+		if err != nil {
+			grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, err)
+			return
+		}
+
+		// the headers are awaited so that errors returned before watching, such as the authentication ones, can still be mapped to an HTTP status
+		md, err := stream.Header()
+		if err == nil && md == nil {
+			_, err = stream.Recv()
+		}
+		if err != nil {
+			grpcRuntime.HTTPError(ctx, gmux, outbound, w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+
+		rc := http.NewResponseController(w)
+		rc.Flush()
+		for {
+			change, err := stream.Recv()
+			if err != nil {
+				// the status has already been sent, so the error is sent as an event before closing the stream
+				if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+					observability.Logger().Printf("watch of users interrupted: %s", err)
+					writeEvent(w, "", "error", []byte(status.Convert(err).Message()))
+					rc.Flush()
+				}
+				return
+			}
+
+			data, err := outbound.Marshal(change)
+			if err != nil {
+				return
+			}
+
+			eventType := strings.ToLower(strings.TrimPrefix(change.Type.String(), "USER_CHANGE_TYPE_"))
+			if err = writeEvent(w, change.ResumeToken, eventType, data); err != nil {
+				return
+			}
+			rc.Flush()
+		}
+	}
+}
+
+// writeEvent writes a server-sent event, omitting its ID when empty
+func writeEvent(w io.Writer, ID, event string, data []byte) error {
+	var b strings.Builder
+	if ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", ID)
+	}
+	fmt.Fprintf(&b, "event: %s\ndata: %s\n\n", event, data)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
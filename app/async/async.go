@@ -4,18 +4,28 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/sergicanet9/go-hexagonal-api/app/async/deliverer"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/dispatcher"
 	"github.com/sergicanet9/go-hexagonal-api/app/async/healthchecker"
+	"github.com/sergicanet9/go-hexagonal-api/app/async/pruner"
 	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
 	"github.com/sergicanet9/scv-go-tools/v4/observability"
 )
 
 type async struct {
-	config config.Config
+	config   config.Config
+	outbox   ports.OutboxService
+	webhooks ports.WebhookService
+	users    ports.UserService
 }
 
-func New(cfg config.Config) async {
+func New(cfg config.Config, outbox ports.OutboxService, webhooks ports.WebhookService, users ports.UserService) async {
 	return async{
-		config: cfg,
+		config:   cfg,
+		outbox:   outbox,
+		webhooks: webhooks,
+		users:    users,
 	}
 }
 
@@ -23,6 +33,9 @@ func (a async) Run(ctx context.Context, cancel context.CancelFunc) func() error
 	return func() error {
 		go healthchecker.RunHTTP(ctx, cancel, fmt.Sprintf("http://:%d/v1/health", a.config.HTTPPort), a.config.Async.Interval.Duration)
 		go healthchecker.RunGRPC(ctx, cancel, fmt.Sprintf(":%d", a.config.GRPCPort), a.config.Async.Interval.Duration)
+		go dispatcher.Run(ctx, cancel, a.outbox, a.config.Outbox.Interval.Duration)
+		go deliverer.Run(ctx, cancel, a.webhooks, a.config.Webhooks.Interval.Duration)
+		go pruner.Run(ctx, cancel, a.users, a.config.UserChanges.Interval.Duration)
 
 		<-ctx.Done()
 		observability.Logger().Printf("Async process stopped")
@@ -6,26 +6,47 @@ import (
 	"time"
 
 	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // TestNew_Ok checks that New creates a new async struct with the expected values
 func TestNew_Ok(t *testing.T) {
 	// Arrange
 	expectedConfig := config.Config{}
+	expectedOutbox := mocks.NewOutboxService(t)
+	expectedWebhooks := mocks.NewWebhookService(t)
+	expectedUsers := mocks.NewUserService(t)
 
 	// Act
-	async := New(expectedConfig)
+	async := New(expectedConfig, expectedOutbox, expectedWebhooks, expectedUsers)
 
 	// Assert
 	assert.Equal(t, expectedConfig, async.config)
+	assert.Equal(t, expectedOutbox, async.outbox)
+	assert.Equal(t, expectedWebhooks, async.webhooks)
+	assert.Equal(t, expectedUsers, async.users)
 }
 
 // TestRun_ContextCancelled checks that Run finishes when the context gets cancelled
 func TestRun_ContextCancelled(t *testing.T) {
 	// Arrange
+	outboxServiceMock := mocks.NewOutboxService(t)
+	outboxServiceMock.On(testutils.FunctionName(t, ports.OutboxService.Dispatch), mock.Anything).Return(0, nil).Maybe()
+	outboxServiceMock.On(testutils.FunctionName(t, ports.OutboxService.Prune), mock.Anything).Return(0, nil).Maybe()
+	webhookServiceMock := mocks.NewWebhookService(t)
+	webhookServiceMock.On(testutils.FunctionName(t, ports.WebhookService.Deliver), mock.Anything).Return(0, nil).Maybe()
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.PruneChanges), mock.Anything).Return(0, nil).Maybe()
+
 	async := &async{
-		config: config.Config{},
+		config:   config.Config{},
+		outbox:   outboxServiceMock,
+		webhooks: webhookServiceMock,
+		users:    userServiceMock,
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
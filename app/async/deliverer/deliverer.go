@@ -0,0 +1,25 @@
+package deliverer
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/app/async/ticker"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+// Run sends the due webhook deliveries every interval, until the context gets cancelled.
+// Failed deliveries are rescheduled by the service, so they get sent again in a later run.
+func Run(ctx context.Context, cancel context.CancelFunc, svc ports.WebhookService, interval time.Duration) {
+	ticker.Run(ctx, cancel, "webhook deliverer", interval, func(ctx context.Context) {
+		delivered, err := svc.Deliver(ctx)
+		if err != nil {
+			observability.Logger().Printf("webhook deliverer process - error: %s", err)
+		}
+
+		if delivered > 0 {
+			observability.Logger().Printf("webhook deliverer process - %d deliveries sent", delivered)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package deliverer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestRun_ContextCancelled checks that the deliverer sends the due deliveries until the context gets cancelled
+func TestRun_ContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	webhookServiceMock := mocks.NewWebhookService(t)
+	webhookServiceMock.On(testutils.FunctionName(t, ports.WebhookService.Deliver), mock.Anything).Return(1, nil)
+
+	// Act
+	Run(ctx, cancel, webhookServiceMock, time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+}
+
+// TestRun_DeliverError checks that the deliverer keeps running when a delivery run fails
+func TestRun_DeliverError(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	webhookServiceMock := mocks.NewWebhookService(t)
+	webhookServiceMock.On(testutils.FunctionName(t, ports.WebhookService.Deliver), mock.Anything).Return(0, errors.New("deliver error"))
+
+	// Act
+	Run(ctx, cancel, webhookServiceMock, time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+	assert.Greater(t, len(webhookServiceMock.Calls), 1)
+}
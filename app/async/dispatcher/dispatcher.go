@@ -0,0 +1,34 @@
+package dispatcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/app/async/ticker"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+// Run publishes the pending events of the outbox every interval, until the context gets cancelled.
+// Failed events remain pending, so they get published again once their backoff elapses, and the published events
+// older than the retention get pruned.
+func Run(ctx context.Context, cancel context.CancelFunc, svc ports.OutboxService, interval time.Duration) {
+	ticker.Run(ctx, cancel, "outbox dispatcher", interval, func(ctx context.Context) {
+		published, err := svc.Dispatch(ctx)
+		if err != nil {
+			observability.Logger().Printf("outbox dispatcher process - error: %s", err)
+		}
+
+		if published > 0 {
+			observability.Logger().Printf("outbox dispatcher process - %d events published", published)
+		}
+
+		pruned, err := svc.Prune(ctx)
+		if err != nil {
+			observability.Logger().Printf("outbox dispatcher process - prune error: %s", err)
+		}
+		if pruned > 0 {
+			observability.Logger().Printf("outbox dispatcher process - %d published events pruned", pruned)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestRun_ContextCancelled checks that the dispatcher dispatches the outbox until the context gets cancelled
+func TestRun_ContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	outboxServiceMock := mocks.NewOutboxService(t)
+	outboxServiceMock.On(testutils.FunctionName(t, ports.OutboxService.Dispatch), mock.Anything).Return(1, nil)
+	outboxServiceMock.On(testutils.FunctionName(t, ports.OutboxService.Prune), mock.Anything).Return(1, nil).Maybe()
+
+	// Act
+	Run(ctx, cancel, outboxServiceMock, time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+}
+
+// TestRun_DispatchError checks that the dispatcher keeps running when a dispatch or a prune fails
+func TestRun_DispatchError(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	outboxServiceMock := mocks.NewOutboxService(t)
+	outboxServiceMock.On(testutils.FunctionName(t, ports.OutboxService.Dispatch), mock.Anything).Return(0, errors.New("dispatch error"))
+	outboxServiceMock.On(testutils.FunctionName(t, ports.OutboxService.Prune), mock.Anything).Return(0, errors.New("prune error")).Maybe()
+
+	// Act
+	Run(ctx, cancel, outboxServiceMock, time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+	assert.Greater(t, len(outboxServiceMock.Calls), 1)
+}
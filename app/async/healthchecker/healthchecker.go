@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/sergicanet9/go-hexagonal-api/app/async/ticker"
 	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
 	"github.com/sergicanet9/scv-go-tools/v4/observability"
 	"google.golang.org/grpc"
@@ -15,20 +16,11 @@ import (
 const contentType = "application/json"
 
 func RunHTTP(ctx context.Context, cancel context.CancelFunc, url string, interval time.Duration) {
-	defer cancel()
-	defer func() {
-		if rec := recover(); rec != nil {
-			observability.Logger().Printf("FATAL - recovered panic in HTTP healthchecker process: %v", rec)
-		}
-	}()
-
-	for ctx.Err() == nil {
-		<-time.After(interval)
-
+	ticker.Run(ctx, cancel, "HTTP healthchecker", interval, func(ctx context.Context) {
 		req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
 		if err != nil {
 			observability.Logger().Printf("HTTP healthchecker process - error: %s", err)
-			continue
+			return
 		}
 		req.Header.Set("Content-Type", contentType)
 
@@ -38,34 +30,25 @@ func RunHTTP(ctx context.Context, cancel context.CancelFunc, url string, interva
 
 		if err != nil {
 			observability.Logger().Printf("HTTP healthchecker process - error: %s", err)
-			continue
+			return
 		}
 		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
 			observability.Logger().Printf("HTTP healthchecker process - error: %s", err)
-			continue
+			return
 		}
 
 		observability.Logger().Printf("HTTP healthchecker process - health check complete, time elapsed: %s", elapsed)
-	}
+	})
 }
 
 func RunGRPC(ctx context.Context, cancel context.CancelFunc, target string, interval time.Duration) {
-	defer cancel()
-	defer func() {
-		if rec := recover(); rec != nil {
-			observability.Logger().Printf("FATAL - recovered panic in gRPC healthchecker process: %v", rec)
-		}
-	}()
-
-	for ctx.Err() == nil {
-		<-time.After(interval)
-
+	ticker.Run(ctx, cancel, "gRPC healthchecker", interval, func(ctx context.Context) {
 		conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
 		if err != nil {
 			observability.Logger().Printf("gRPC healthchecker process - error: %v", err)
-			continue
+			return
 		}
 		defer conn.Close()
 		client := pb.NewHealthServiceClient(conn)
@@ -76,7 +59,7 @@ func RunGRPC(ctx context.Context, cancel context.CancelFunc, target string, inte
 
 		if err != nil {
 			observability.Logger().Printf("gRPC healthchecker process - error: %v", err)
-			continue
+			return
 		}
 
 		if _, ok := status.FromError(err); !ok {
@@ -84,5 +67,5 @@ func RunGRPC(ctx context.Context, cancel context.CancelFunc, target string, inte
 		}
 
 		observability.Logger().Printf("gRPC healthchecker process - health check complete, time elapsed: %s", elapsed)
-	}
+	})
 }
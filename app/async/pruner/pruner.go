@@ -0,0 +1,24 @@
+package pruner
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/app/async/ticker"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+// Run prunes the user changes older than the retention every interval, until the context gets cancelled
+func Run(ctx context.Context, cancel context.CancelFunc, svc ports.UserService, interval time.Duration) {
+	ticker.Run(ctx, cancel, "user changes pruner", interval, func(ctx context.Context) {
+		pruned, err := svc.PruneChanges(ctx)
+		if err != nil {
+			observability.Logger().Printf("user changes pruner process - error: %s", err)
+		}
+
+		if pruned > 0 {
+			observability.Logger().Printf("user changes pruner process - %d user changes pruned", pruned)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package pruner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestRun_ContextCancelled checks that the pruner prunes the user changes until the context gets cancelled
+func TestRun_ContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.PruneChanges), mock.Anything).Return(1, nil)
+
+	// Act
+	Run(ctx, cancel, userServiceMock, time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+}
+
+// TestRun_PruneError checks that the pruner keeps running when a prune fails
+func TestRun_PruneError(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.PruneChanges), mock.Anything).Return(0, errors.New("prune error"))
+
+	// Act
+	Run(ctx, cancel, userServiceMock, time.Millisecond)
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+	assert.Greater(t, len(userServiceMock.Calls), 1)
+}
@@ -0,0 +1,29 @@
+package ticker
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+// Run calls tick every interval until the context gets cancelled, and cancels it when it returns.
+// A panic in tick is recovered and logged as fatal along with the name of the process, which stops it.
+func Run(ctx context.Context, cancel context.CancelFunc, name string, interval time.Duration, tick func(ctx context.Context)) {
+	defer cancel()
+	defer func() {
+		if rec := recover(); rec != nil {
+			observability.Logger().Printf("FATAL - recovered panic in %s process: %v", name, rec)
+		}
+	}()
+
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		tick(ctx)
+	}
+}
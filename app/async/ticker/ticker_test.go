@@ -0,0 +1,42 @@
+package ticker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRun_ContextCancelled checks that Run ticks every interval until the context gets cancelled
+func TestRun_ContextCancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	expectedError := context.DeadlineExceeded.Error()
+	ticks := 0
+
+	// Act
+	Run(ctx, cancel, "test", time.Millisecond, func(context.Context) { ticks++ })
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+	assert.Greater(t, ticks, 1)
+}
+
+// TestRun_Panic checks that Run recovers from a panic in a tick and cancels the context
+func TestRun_Panic(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	expectedError := context.Canceled.Error()
+	ticks := 0
+
+	// Act
+	Run(ctx, cancel, "test", time.Millisecond, func(context.Context) {
+		ticks++
+		panic("test panic")
+	})
+
+	// Assert
+	assert.Equal(t, expectedError, ctx.Err().Error())
+	assert.Equal(t, 1, ticks)
+}
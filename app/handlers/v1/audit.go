@@ -0,0 +1,144 @@
+package v1
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RequestIDKey metadata key of the ID of the request, generated when not received
+const RequestIDKey = "x-request-id"
+
+type auditHandler struct {
+	ctx context.Context
+	cfg config.Config
+	svc ports.AuditService
+	pb.UnimplementedAuditServiceServer
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(ctx context.Context, cfg config.Config, svc ports.AuditService) *auditHandler {
+	return &auditHandler{
+		ctx: ctx,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+// JWTMethodPolicies defines custom JWT method policies
+func (a *auditHandler) JWTMethodPolicies() []interceptors.MethodPolicy {
+	return []interceptors.MethodPolicy{
+		{
+			MethodName:     pb.AuditService_ListAuditEvents_FullMethodName,
+			RequiredClaims: []string{"admin"},
+		},
+	}
+}
+
+func (a *auditHandler) ListAuditEvents(incomingCtx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, a.ctx), a.cfg.Timeout.Duration)
+	defer cancel()
+
+	listReq := models.ListAuditEventsReq{
+		Filter: models.AuditFilter{
+			ActorID:  req.ActorId,
+			Action:   models.AuditAction(req.Action),
+			TargetID: req.TargetId,
+		},
+		PageSize:  int(req.PageSize),
+		PageToken: req.PageToken,
+	}
+	if req.From != nil {
+		listReq.Filter.From = req.From.AsTime()
+	}
+	if req.To != nil {
+		listReq.Filter.To = req.To.AsTime()
+	}
+
+	resp, err := a.svc.List(ctx, listReq)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	listResp := &pb.ListAuditEventsResponse{
+		Events:        []*pb.AuditEvent{},
+		NextPageToken: resp.NextPageToken,
+	}
+	for _, event := range resp.Events {
+		pbEvent := &pb.AuditEvent{
+			Id:        event.ID,
+			ActorId:   event.ActorID,
+			Action:    string(event.Action),
+			TargetId:  event.TargetID,
+			RequestId: event.RequestID,
+			Timestamp: timestamppb.New(event.Timestamp),
+		}
+		if pbEvent.Before, err = toStruct(event.Before); err != nil {
+			return nil, ToGRPC(err)
+		}
+		if pbEvent.After, err = toStruct(event.After); err != nil {
+			return nil, ToGRPC(err)
+		}
+		listResp.Events = append(listResp.Events, pbEvent)
+	}
+	return listResp, nil
+}
+
+func toStruct(values map[string]interface{}) (*structpb.Struct, error) {
+	if values == nil {
+		return nil, nil
+	}
+	return structpb.NewStruct(values)
+}
+
+// auditContext returns the requestContext of the incoming request carrying its actor and its request ID, so that mutations get audited
+func auditContext(incomingCtx, ctx context.Context) context.Context {
+	return models.WithAuditMetadata(requestContext(incomingCtx, ctx), models.AuditMetadata{
+		ActorID:   actorID(incomingCtx),
+		RequestID: requestID(incomingCtx),
+	})
+}
+
+// actorID returns the user_id claim of the token that authorized the request, if any
+func actorID(ctx context.Context) string {
+	claims, _ := ctx.Value(interceptors.ClaimsKey).(jwt.MapClaims)
+	ID, _ := claims["user_id"].(string)
+	return ID
+}
+
+// authorizeUser returns a permission denied error unless the token that authorized the request belongs to the given user
+// or holds the admin claim, for the methods on the data of a single user
+func authorizeUser(ctx context.Context, userID string) error {
+	claims, _ := ctx.Value(interceptors.ClaimsKey).(jwt.MapClaims)
+	if _, ok := claims["admin"]; ok {
+		return nil
+	}
+	if ID := actorID(ctx); ID == "" || ID != userID {
+		return ToGRPC(wrappers.NewUnauthenticatedErr(fmt.Errorf("insufficient permissions: user %s can only be accessed by itself or an admin", userID)))
+	}
+	return nil
+}
+
+// requestID returns the request ID received in the metadata, or a random one if none was received
+func requestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(RequestIDKey); len(values) > 0 && values[0] != "" {
+		return values[0]
+	}
+
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
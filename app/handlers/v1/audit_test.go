@@ -0,0 +1,168 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TestListAuditEvents_Ok checks that the ListAuditEvents handler maps the request filter and returns the expected response
+func TestListAuditEvents_Ok(t *testing.T) {
+	// Arrange
+	auditService := mocks.NewAuditService(t)
+	from := time.Now().Add(-time.Hour).UTC()
+	expectedReq := models.ListAuditEventsReq{
+		Filter: models.AuditFilter{
+			ActorID: "test-actor",
+			Action:  models.AuditActionDelete,
+			From:    from,
+		},
+		PageSize:  10,
+		PageToken: "10",
+	}
+	expectedResp := models.ListAuditEventsResp{
+		Events: []models.AuditEventResp{
+			{
+				ID:       "test-id",
+				ActorID:  "test-actor",
+				Action:   models.AuditActionDelete,
+				TargetID: "test-target",
+				Before:   map[string]interface{}{"email": "test@test.com"},
+			},
+		},
+		NextPageToken: "20",
+	}
+	auditService.On(testutils.FunctionName(t, ports.AuditService.List), mock.Anything, expectedReq).Return(expectedResp, nil).Once()
+
+	cfg := config.Config{}
+	handler := NewAuditHandler(context.Background(), cfg, auditService)
+
+	// Act
+	resp, err := handler.ListAuditEvents(context.Background(), &pb.ListAuditEventsRequest{
+		ActorId:   "test-actor",
+		Action:    "delete",
+		From:      timestamppb.New(from),
+		PageSize:  10,
+		PageToken: "10",
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResp.NextPageToken, resp.NextPageToken)
+	assert.Len(t, resp.Events, 1)
+	assert.Equal(t, "test-target", resp.Events[0].TargetId)
+	assert.Equal(t, "test@test.com", resp.Events[0].Before.AsMap()["email"])
+	assert.Nil(t, resp.Events[0].After)
+}
+
+// TestListAuditEvents_ServiceError checks that the ListAuditEvents handler returns a gRPC error when the service fails
+func TestListAuditEvents_ServiceError(t *testing.T) {
+	// Arrange
+	auditService := mocks.NewAuditService(t)
+	expectedError := "invalid page token"
+	auditService.On(testutils.FunctionName(t, ports.AuditService.List), mock.Anything, mock.Anything).Return(models.ListAuditEventsResp{}, wrappers.NewValidationErr(errors.New(expectedError))).Once()
+
+	cfg := config.Config{}
+	handler := NewAuditHandler(context.Background(), cfg, auditService)
+
+	// Act
+	resp, err := handler.ListAuditEvents(context.Background(), &pb.ListAuditEventsRequest{})
+
+	// Assert
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+}
+
+// TestAuditContext_Ok checks that auditContext carries the user_id claim and the received request ID
+func TestAuditContext_Ok(t *testing.T) {
+	// Arrange
+	incomingCtx := context.WithValue(context.Background(), interceptors.ClaimsKey, jwt.MapClaims{"user_id": "test-actor"})
+	incomingCtx = metadata.NewIncomingContext(incomingCtx, metadata.Pairs(RequestIDKey, "test-request"))
+
+	// Act
+	ctx := auditContext(incomingCtx, context.Background())
+
+	// Assert
+	assert.Equal(t, models.AuditMetadata{ActorID: "test-actor", RequestID: "test-request"}, models.AuditMetadataFromContext(ctx))
+}
+
+// TestAuditContext_GeneratedRequestID checks that auditContext generates a request ID when none is received
+func TestAuditContext_GeneratedRequestID(t *testing.T) {
+	// Act
+	ctx := auditContext(context.Background(), context.Background())
+
+	// Assert
+	metadata := models.AuditMetadataFromContext(ctx)
+	assert.Empty(t, metadata.ActorID)
+	assert.Len(t, metadata.RequestID, 32)
+}
+
+// claimsContext returns a context carrying the claims of a token of the given user
+func claimsContext(userID string) context.Context {
+	return context.WithValue(context.Background(), interceptors.ClaimsKey, jwt.MapClaims{"user_id": userID})
+}
+
+// TestAuthorizeUser_Ok checks that authorizeUser allows the tokens of the user itself and of the admins
+func TestAuthorizeUser_Ok(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+	}{
+		{"user itself", jwt.MapClaims{"user_id": "test-id"}},
+		{"admin", jwt.MapClaims{"user_id": "admin-id", "admin": true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.WithValue(context.Background(), interceptors.ClaimsKey, test.claims)
+
+			// Act
+			err := authorizeUser(ctx, "test-id")
+
+			// Assert
+			assert.Nil(t, err)
+		})
+	}
+}
+
+// TestAuthorizeUser_Denied checks that authorizeUser denies the tokens of other users and the requests without claims
+func TestAuthorizeUser_Denied(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{"other user", claimsContext("other-id")},
+		{"no claims", context.Background()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Act
+			err := authorizeUser(test.ctx, "test-id")
+
+			// Assert
+			assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type avatarHandler struct {
+	ctx context.Context
+	cfg config.Config
+	svc ports.AvatarService
+	pb.UnimplementedAvatarServiceServer
+}
+
+// NewAvatarHandler creates a new avatar handler
+func NewAvatarHandler(ctx context.Context, cfg config.Config, svc ports.AvatarService) *avatarHandler {
+	return &avatarHandler{
+		ctx: ctx,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+// JWTMethodPolicies defines custom JWT method policies.
+// Any token is accepted, and the methods then require it to belong to the requested user or to hold the admin claim.
+func (a *avatarHandler) JWTMethodPolicies() []interceptors.MethodPolicy {
+	return []interceptors.MethodPolicy{
+		{
+			MethodName:     pb.AvatarService_UploadAvatar_FullMethodName,
+			RequiredClaims: nil,
+		},
+		{
+			MethodName:     pb.AvatarService_DeleteAvatar_FullMethodName,
+			RequiredClaims: nil,
+		},
+	}
+}
+
+func (a *avatarHandler) UploadAvatar(incomingCtx context.Context, req *pb.UploadAvatarRequest) (*pb.UploadAvatarResponse, error) {
+	if err := authorizeUser(incomingCtx, req.Id); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(auditContext(incomingCtx, a.ctx), a.cfg.Timeout.Duration)
+	defer cancel()
+
+	uploadReq := models.UploadAvatarReq{
+		ContentType: req.ContentType,
+		Content:     req.Content,
+	}
+
+	resp, err := a.svc.Upload(ctx, req.Id, uploadReq)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	uploadResp := &pb.UploadAvatarResponse{
+		AvatarUrl:  resp.AvatarURL,
+		Thumbnails: []*pb.AvatarThumbnail{},
+	}
+	for _, thumbnail := range resp.Thumbnails {
+		uploadResp.Thumbnails = append(uploadResp.Thumbnails, &pb.AvatarThumbnail{
+			Size: int32(thumbnail.Size),
+			Url:  thumbnail.URL,
+		})
+	}
+	return uploadResp, nil
+}
+
+func (a *avatarHandler) DeleteAvatar(incomingCtx context.Context, req *pb.DeleteAvatarRequest) (*emptypb.Empty, error) {
+	if err := authorizeUser(incomingCtx, req.Id); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(auditContext(incomingCtx, a.ctx), a.cfg.Timeout.Duration)
+	defer cancel()
+
+	err := a.svc.Delete(ctx, req.Id)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestUploadAvatar_Ok checks that the UploadAvatar handler returns the URLs of the avatar and its thumbnails
+func TestUploadAvatar_Ok(t *testing.T) {
+	// Arrange
+	avatarService := mocks.NewAvatarService(t)
+	req := models.UploadAvatarReq{ContentType: "image/png", Content: []byte("test")}
+	expectedResp := models.UploadAvatarResp{
+		AvatarURL:  "https://test.com/avatar",
+		Thumbnails: []models.AvatarThumbnail{{Size: 64, URL: "https://test.com/avatar_64.png"}},
+	}
+	avatarService.On(testutils.FunctionName(t, ports.AvatarService.Upload), mock.Anything, "test-id", req).Return(expectedResp, nil).Once()
+
+	cfg := config.Config{}
+	handler := NewAvatarHandler(context.Background(), cfg, avatarService)
+
+	// Act
+	resp, err := handler.UploadAvatar(claimsContext("test-id"), &pb.UploadAvatarRequest{Id: "test-id", ContentType: req.ContentType, Content: req.Content})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResp.AvatarURL, resp.AvatarUrl)
+	assert.Len(t, resp.Thumbnails, 1)
+	assert.Equal(t, int32(64), resp.Thumbnails[0].Size)
+	assert.Equal(t, expectedResp.Thumbnails[0].URL, resp.Thumbnails[0].Url)
+}
+
+// TestUploadAvatar_ServiceError checks that the UploadAvatar handler returns a gRPC error when the service fails
+func TestUploadAvatar_ServiceError(t *testing.T) {
+	// Arrange
+	avatarService := mocks.NewAvatarService(t)
+	expectedError := "content cannot be empty"
+	avatarService.On(testutils.FunctionName(t, ports.AvatarService.Upload), mock.Anything, "test-id", mock.Anything).Return(models.UploadAvatarResp{}, wrappers.NewValidationErr(errors.New(expectedError))).Once()
+
+	cfg := config.Config{}
+	handler := NewAvatarHandler(context.Background(), cfg, avatarService)
+
+	// Act
+	resp, err := handler.UploadAvatar(claimsContext("test-id"), &pb.UploadAvatarRequest{Id: "test-id"})
+
+	// Assert
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+}
+
+// TestDeleteAvatar_Ok checks that the DeleteAvatar handler does not return an error when the service succeeds
+func TestDeleteAvatar_Ok(t *testing.T) {
+	// Arrange
+	avatarService := mocks.NewAvatarService(t)
+	avatarService.On(testutils.FunctionName(t, ports.AvatarService.Delete), mock.Anything, "test-id").Return(nil).Once()
+
+	cfg := config.Config{}
+	handler := NewAvatarHandler(context.Background(), cfg, avatarService)
+
+	// Act
+	_, err := handler.DeleteAvatar(claimsContext("test-id"), &pb.DeleteAvatarRequest{Id: "test-id"})
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+// TestDeleteAvatar_ServiceError checks that the DeleteAvatar handler returns a gRPC error when the service fails
+func TestDeleteAvatar_ServiceError(t *testing.T) {
+	// Arrange
+	avatarService := mocks.NewAvatarService(t)
+	expectedError := "user test-id has no avatar"
+	avatarService.On(testutils.FunctionName(t, ports.AvatarService.Delete), mock.Anything, "test-id").Return(wrappers.NewNonExistentErr(errors.New(expectedError))).Once()
+
+	cfg := config.Config{}
+	handler := NewAvatarHandler(context.Background(), cfg, avatarService)
+
+	// Act
+	_, err := handler.DeleteAvatar(claimsContext("test-id"), &pb.DeleteAvatarRequest{Id: "test-id"})
+
+	// Assert
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+}
+
+// TestUploadAvatar_OtherUser checks that the UploadAvatar handler denies the avatar of a user to the tokens of other users
+func TestUploadAvatar_OtherUser(t *testing.T) {
+	// Arrange
+	avatarService := mocks.NewAvatarService(t)
+
+	cfg := config.Config{}
+	handler := NewAvatarHandler(context.Background(), cfg, avatarService)
+
+	// Act
+	resp, err := handler.UploadAvatar(claimsContext("other-id"), &pb.UploadAvatarRequest{Id: "test-id"})
+
+	// Assert
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+// TestDeleteAvatar_OtherUser checks that the DeleteAvatar handler denies the avatar of a user to the tokens of other users
+func TestDeleteAvatar_OtherUser(t *testing.T) {
+	// Arrange
+	avatarService := mocks.NewAvatarService(t)
+
+	cfg := config.Config{}
+	handler := NewAvatarHandler(context.Background(), cfg, avatarService)
+
+	// Act
+	_, err := handler.DeleteAvatar(claimsContext("other-id"), &pb.DeleteAvatarRequest{Id: "test-id"})
+
+	// Assert
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
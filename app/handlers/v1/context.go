@@ -0,0 +1,22 @@
+package v1
+
+import "context"
+
+// requestValuesContext context of the API carrying the values of an incoming request
+type requestValuesContext struct {
+	context.Context
+	request context.Context
+}
+
+// requestContext returns a context canceled along with ctx, the context of the API, rather than when the client goes away,
+// which carries the values of the incoming request, such as its claims or its read-your-writes flag
+func requestContext(incomingCtx, ctx context.Context) context.Context {
+	return requestValuesContext{Context: ctx, request: incomingCtx}
+}
+
+func (c requestValuesContext) Value(key any) any {
+	if value := c.request.Value(key); value != nil {
+		return value
+	}
+	return c.Context.Value(key)
+}
@@ -0,0 +1,27 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testKey struct{}
+
+// TestRequestContext_Ok checks that requestContext carries the values of the incoming request and is only canceled along with the context of the API
+func TestRequestContext_Ok(t *testing.T) {
+	// Arrange
+	incomingCtx, cancelIncoming := context.WithCancel(context.WithValue(context.Background(), testKey{}, "request"))
+	apiCtx, cancelAPI := context.WithCancel(context.Background())
+
+	// Act
+	ctx := requestContext(incomingCtx, apiCtx)
+	cancelIncoming()
+
+	// Assert
+	assert.Equal(t, "request", ctx.Value(testKey{}))
+	assert.Nil(t, ctx.Err())
+	cancelAPI()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
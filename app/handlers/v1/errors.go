@@ -0,0 +1,23 @@
+package v1
+
+import (
+	"errors"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ToGRPC converts the error into a gRPC status error, mapping the conflicts with stored entities to codes.AlreadyExists
+// and the conflicts with concurrent changes to codes.Aborted, which the gateway serves as 409 Conflict,
+// and delegating any other error to utils.ToGRPC
+func ToGRPC(err error) error {
+	if errors.Is(err, entities.AlreadyExistsErr) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	if errors.Is(err, entities.ConflictErr) {
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return utils.ToGRPC(err)
+}
@@ -0,0 +1,57 @@
+package v1
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestToGRPC_AlreadyExists checks that ToGRPC maps an AlreadyExistsErr to codes.AlreadyExists keeping its message
+func TestToGRPC_AlreadyExists(t *testing.T) {
+	// Arrange
+	err := entities.NewEmailInUseErr("test@test.com")
+
+	// Act
+	grpcErr := ToGRPC(err)
+
+	// Assert
+	st, ok := status.FromError(grpcErr)
+	assert.True(t, ok)
+	assert.Equal(t, codes.AlreadyExists, st.Code())
+	assert.Equal(t, "email test@test.com is already in use", st.Message())
+}
+
+// TestToGRPC_Conflict checks that ToGRPC maps a ConflictErr to codes.Aborted keeping its message
+func TestToGRPC_Conflict(t *testing.T) {
+	// Arrange
+	err := entities.NewConflictErr(errors.New("test-conflict"))
+
+	// Act
+	grpcErr := ToGRPC(err)
+
+	// Assert
+	st, ok := status.FromError(grpcErr)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Aborted, st.Code())
+	assert.Equal(t, "test-conflict", st.Message())
+}
+
+// TestToGRPC_OtherErrors checks that ToGRPC delegates any other error to utils.ToGRPC
+func TestToGRPC_OtherErrors(t *testing.T) {
+	// Arrange
+	err := wrappers.NewNonExistentErr(errors.New("not found"))
+
+	// Act
+	grpcErr := ToGRPC(err)
+
+	// Assert
+	st, ok := status.FromError(grpcErr)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "not found", st.Message())
+}
@@ -4,21 +4,27 @@ import (
 	"context"
 
 	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
 	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 type healthHandler struct {
-	ctx context.Context
-	cfg config.Config
+	ctx            context.Context
+	cfg            config.Config
+	userCacheStats func() models.CacheStats
+	replicaStatus  func() []models.ReplicaStatus
 	pb.UnimplementedHealthServiceServer
 }
 
-// NewHealthHandler creates a new health handler
-func NewHealthHandler(ctx context.Context, cfg config.Config) *healthHandler {
+// NewHealthHandler creates a new health handler, userCacheStats is nil when the user cache is disabled
+// and replicaStatus is nil when there are no read replicas
+func NewHealthHandler(ctx context.Context, cfg config.Config, userCacheStats func() models.CacheStats, replicaStatus func() []models.ReplicaStatus) *healthHandler {
 	return &healthHandler{
-		ctx: ctx,
-		cfg: cfg,
+		ctx:            ctx,
+		cfg:            cfg,
+		userCacheStats: userCacheStats,
+		replicaStatus:  replicaStatus,
 	}
 }
 
@@ -35,6 +41,24 @@ func (h *healthHandler) HealthCheck(_ context.Context, _ *emptypb.Empty) (*pb.He
 	if h.cfg.Environment == "local" {
 		response.Dsn = h.cfg.DSN
 	}
+	if h.userCacheStats != nil {
+		stats := h.userCacheStats()
+		response.UserCache = &pb.UserCacheStats{
+			Store:  h.cfg.UserCache.Store,
+			Hits:   stats.Hits,
+			Misses: stats.Misses,
+		}
+	}
+	if h.replicaStatus != nil {
+		for _, replica := range h.replicaStatus() {
+			response.Replicas = append(response.Replicas, &pb.ReplicaStatus{
+				Name:      replica.Name,
+				Healthy:   replica.Healthy,
+				LagMillis: replica.Lag.Milliseconds(),
+				Error:     replica.Error,
+			})
+		}
+	}
 
 	return response, nil
 }
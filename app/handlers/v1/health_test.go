@@ -3,8 +3,10 @@ package v1
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,7 +21,7 @@ func TestHealthCheck_WhenEnvironmentIsNotLocal_DSNIsFiltered(t *testing.T) {
 		GRPCPort:    2,
 		DSN:         "test-dsn",
 	}
-	healthHandler := NewHealthHandler(context.Background(), cfg)
+	healthHandler := NewHealthHandler(context.Background(), cfg, nil, nil)
 	expectedDSN := "***FILTERED***"
 
 	// Act
@@ -46,7 +48,7 @@ func TestHealthCheck_WhenEnvironmentIsLocal_DSNIsNotFiltered(t *testing.T) {
 		GRPCPort:    2,
 		DSN:         "test-dsn",
 	}
-	healthHandler := NewHealthHandler(context.Background(), cfg)
+	healthHandler := NewHealthHandler(context.Background(), cfg, nil, nil)
 
 	// Act
 	resp, err := healthHandler.HealthCheck(context.Background(), nil)
@@ -60,3 +62,49 @@ func TestHealthCheck_WhenEnvironmentIsLocal_DSNIsNotFiltered(t *testing.T) {
 	assert.Equal(t, cfg.GRPCPort, int(resp.GrpcPort))
 	assert.Equal(t, cfg.DSN, resp.Dsn)
 }
+
+// TestHealthCheck_WhenUserCacheIsEnabled_StatsAreReturned checks that healthCheck handler returns the lookups of the user cache when it is enabled
+func TestHealthCheck_WhenUserCacheIsEnabled_StatsAreReturned(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.UserCache.Store = "lru"
+	healthHandler := NewHealthHandler(context.Background(), cfg, func() models.CacheStats {
+		return models.CacheStats{Hits: 3, Misses: 1}
+	}, nil)
+
+	// Act
+	resp, err := healthHandler.HealthCheck(context.Background(), nil)
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.NotNil(t, resp.UserCache) {
+		assert.Equal(t, "lru", resp.UserCache.Store)
+		assert.Equal(t, int64(3), resp.UserCache.Hits)
+		assert.Equal(t, int64(1), resp.UserCache.Misses)
+	}
+}
+
+// TestHealthCheck_WhenReplicasAreConfigured_StatusIsReturned checks that healthCheck handler returns the status of the read replicas when there are any
+func TestHealthCheck_WhenReplicasAreConfigured_StatusIsReturned(t *testing.T) {
+	// Arrange
+	healthHandler := NewHealthHandler(context.Background(), config.Config{}, nil, func() []models.ReplicaStatus {
+		return []models.ReplicaStatus{
+			{Name: "replica-1", Healthy: true, Lag: 1500 * time.Millisecond},
+			{Name: "replica-2", Error: "connection refused"},
+		}
+	})
+
+	// Act
+	resp, err := healthHandler.HealthCheck(context.Background(), nil)
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, resp.Replicas, 2) {
+		assert.Equal(t, "replica-1", resp.Replicas[0].Name)
+		assert.True(t, resp.Replicas[0].Healthy)
+		assert.Equal(t, int64(1500), resp.Replicas[0].LagMillis)
+		assert.Equal(t, "replica-2", resp.Replicas[1].Name)
+		assert.False(t, resp.Replicas[1].Healthy)
+		assert.Equal(t, "connection refused", resp.Replicas[1].Error)
+	}
+}
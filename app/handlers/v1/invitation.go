@@ -0,0 +1,136 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type invitationHandler struct {
+	ctx context.Context
+	cfg config.Config
+	svc ports.InvitationService
+	pb.UnimplementedInvitationServiceServer
+}
+
+// NewInvitationHandler creates a new invitation handler
+func NewInvitationHandler(ctx context.Context, cfg config.Config, svc ports.InvitationService) *invitationHandler {
+	return &invitationHandler{
+		ctx: ctx,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+// JWTMethodPolicies defines custom JWT method policies, AcceptInvite is authenticated by the invite token instead
+func (i *invitationHandler) JWTMethodPolicies() []interceptors.MethodPolicy {
+	methods := []string{
+		pb.InvitationService_InviteUser_FullMethodName,
+		pb.InvitationService_ResendInvite_FullMethodName,
+		pb.InvitationService_RevokeInvite_FullMethodName,
+		pb.InvitationService_GetInvitations_FullMethodName,
+	}
+
+	var policies []interceptors.MethodPolicy
+	for _, method := range methods {
+		policies = append(policies, interceptors.MethodPolicy{
+			MethodName:     method,
+			RequiredClaims: []string{"admin"},
+		})
+	}
+
+	return policies
+}
+
+func (i *invitationHandler) InviteUser(incomingCtx context.Context, req *pb.InviteUserRequest) (*pb.InviteUserResponse, error) {
+	ctx, cancel := context.WithTimeout(auditContext(incomingCtx, i.ctx), i.cfg.Timeout.Duration)
+	defer cancel()
+
+	inviteReq := models.InviteUserReq{
+		Name:     req.Name,
+		Surnames: req.Surnames,
+		Email:    req.Email,
+		ClaimIDs: req.ClaimIds,
+	}
+
+	resp, err := i.svc.Invite(ctx, inviteReq)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	inviteResp := &pb.InviteUserResponse{
+		Id:     resp.ID,
+		UserId: resp.UserID,
+	}
+	return inviteResp, nil
+}
+
+func (i *invitationHandler) AcceptInvite(incomingCtx context.Context, req *pb.AcceptInviteRequest) (*emptypb.Empty, error) {
+	ctx, cancel := context.WithTimeout(auditContext(incomingCtx, i.ctx), i.cfg.Timeout.Duration)
+	defer cancel()
+
+	acceptReq := models.AcceptInviteReq{
+		Token:    req.Token,
+		Password: req.Password,
+	}
+
+	err := i.svc.Accept(ctx, acceptReq)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (i *invitationHandler) ResendInvite(incomingCtx context.Context, req *pb.ResendInviteRequest) (*emptypb.Empty, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, i.ctx), i.cfg.Timeout.Duration)
+	defer cancel()
+
+	err := i.svc.Resend(ctx, req.Id)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (i *invitationHandler) RevokeInvite(incomingCtx context.Context, req *pb.RevokeInviteRequest) (*emptypb.Empty, error) {
+	ctx, cancel := context.WithTimeout(auditContext(incomingCtx, i.ctx), i.cfg.Timeout.Duration)
+	defer cancel()
+
+	err := i.svc.Revoke(ctx, req.Id)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (i *invitationHandler) GetInvitations(incomingCtx context.Context, _ *emptypb.Empty) (*pb.GetInvitationsResponse, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, i.ctx), i.cfg.Timeout.Duration)
+	defer cancel()
+
+	resp, err := i.svc.GetAll(ctx)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	getResp := &pb.GetInvitationsResponse{
+		Invitations: []*pb.Invitation{},
+	}
+	for _, invitation := range resp {
+		getResp.Invitations = append(getResp.Invitations, &pb.Invitation{
+			Id:        invitation.ID,
+			UserId:    invitation.UserID,
+			Email:     invitation.Email,
+			Expired:   invitation.Expired,
+			ExpiresAt: timestamppb.New(invitation.ExpiresAt),
+			CreatedAt: timestamppb.New(invitation.CreatedAt),
+			UpdatedAt: timestamppb.New(invitation.UpdatedAt),
+		})
+	}
+	return getResp, nil
+}
@@ -0,0 +1,168 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// TestInvitationJWTMethodPolicies_Ok checks that every invitation method but AcceptInvite requires the admin claim
+func TestInvitationJWTMethodPolicies_Ok(t *testing.T) {
+	// Arrange
+	handler := NewInvitationHandler(context.Background(), config.Config{}, mocks.NewInvitationService(t))
+
+	// Act
+	policies := handler.JWTMethodPolicies()
+
+	// Assert
+	assert.Len(t, policies, 4)
+	for _, policy := range policies {
+		assert.NotEqual(t, pb.InvitationService_AcceptInvite_FullMethodName, policy.MethodName)
+		assert.Equal(t, []string{"admin"}, policy.RequiredClaims)
+	}
+}
+
+// TestInviteUser_Ok checks that the InviteUser handler returns the expected response when everything goes as expected
+func TestInviteUser_Ok(t *testing.T) {
+	// Arrange
+	invitationService := mocks.NewInvitationService(t)
+	expectedReq := models.InviteUserReq{
+		Name:     "test",
+		Email:    "test@test.com",
+		ClaimIDs: []int32{0},
+	}
+	expectedResp := models.InviteUserResp{
+		ID:     "test-id",
+		UserID: "test-user-id",
+	}
+	invitationService.On(testutils.FunctionName(t, ports.InvitationService.Invite), mock.Anything, expectedReq).Return(expectedResp, nil).Once()
+
+	handler := NewInvitationHandler(context.Background(), config.Config{}, invitationService)
+
+	// Act
+	resp, err := handler.InviteUser(context.Background(), &pb.InviteUserRequest{
+		Name:     expectedReq.Name,
+		Email:    expectedReq.Email,
+		ClaimIds: expectedReq.ClaimIDs,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResp.ID, resp.Id)
+	assert.Equal(t, expectedResp.UserID, resp.UserId)
+}
+
+// TestAcceptInvite_Ok checks that the AcceptInvite handler returns an empty response when everything goes as expected
+func TestAcceptInvite_Ok(t *testing.T) {
+	// Arrange
+	invitationService := mocks.NewInvitationService(t)
+	expectedReq := models.AcceptInviteReq{
+		Token:    "test-token",
+		Password: "test",
+	}
+	invitationService.On(testutils.FunctionName(t, ports.InvitationService.Accept), mock.Anything, expectedReq).Return(nil).Once()
+
+	handler := NewInvitationHandler(context.Background(), config.Config{}, invitationService)
+
+	// Act
+	resp, err := handler.AcceptInvite(context.Background(), &pb.AcceptInviteRequest{
+		Token:    expectedReq.Token,
+		Password: expectedReq.Password,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &emptypb.Empty{}, resp)
+}
+
+// TestAcceptInvite_ServiceError checks that the AcceptInvite handler returns a gRPC error when the service fails
+func TestAcceptInvite_ServiceError(t *testing.T) {
+	// Arrange
+	invitationService := mocks.NewInvitationService(t)
+	expectedError := "invite token has expired"
+	invitationService.On(testutils.FunctionName(t, ports.InvitationService.Accept), mock.Anything, mock.Anything).Return(wrappers.NewValidationErr(errors.New(expectedError))).Once()
+
+	handler := NewInvitationHandler(context.Background(), config.Config{}, invitationService)
+
+	// Act
+	resp, err := handler.AcceptInvite(context.Background(), &pb.AcceptInviteRequest{})
+
+	// Assert
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+}
+
+// TestResendInvite_Ok checks that the ResendInvite handler returns an empty response when everything goes as expected
+func TestResendInvite_Ok(t *testing.T) {
+	// Arrange
+	invitationService := mocks.NewInvitationService(t)
+	invitationService.On(testutils.FunctionName(t, ports.InvitationService.Resend), mock.Anything, "test-id").Return(nil).Once()
+
+	handler := NewInvitationHandler(context.Background(), config.Config{}, invitationService)
+
+	// Act
+	resp, err := handler.ResendInvite(context.Background(), &pb.ResendInviteRequest{Id: "test-id"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &emptypb.Empty{}, resp)
+}
+
+// TestRevokeInvite_NotFound checks that the RevokeInvite handler returns a not found error when the invitation does not exist
+func TestRevokeInvite_NotFound(t *testing.T) {
+	// Arrange
+	invitationService := mocks.NewInvitationService(t)
+	expectedError := "ID test-id not found"
+	invitationService.On(testutils.FunctionName(t, ports.InvitationService.Revoke), mock.Anything, "test-id").Return(wrappers.NewNonExistentErr(errors.New(expectedError))).Once()
+
+	handler := NewInvitationHandler(context.Background(), config.Config{}, invitationService)
+
+	// Act
+	resp, err := handler.RevokeInvite(context.Background(), &pb.RevokeInviteRequest{Id: "test-id"})
+
+	// Assert
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+}
+
+// TestGetInvitations_Ok checks that the GetInvitations handler returns the expected response when everything goes as expected
+func TestGetInvitations_Ok(t *testing.T) {
+	// Arrange
+	invitationService := mocks.NewInvitationService(t)
+	expectedResp := []models.InvitationResp{
+		{ID: "test-id", UserID: "test-user-id", Email: "test@test.com", Expired: true, ExpiresAt: time.Now()},
+	}
+	invitationService.On(testutils.FunctionName(t, ports.InvitationService.GetAll), mock.Anything).Return(expectedResp, nil).Once()
+
+	handler := NewInvitationHandler(context.Background(), config.Config{}, invitationService)
+
+	// Act
+	resp, err := handler.GetInvitations(context.Background(), &emptypb.Empty{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, resp.Invitations, 1)
+	assert.Equal(t, expectedResp[0].ID, resp.Invitations[0].Id)
+	assert.Equal(t, expectedResp[0].Email, resp.Invitations[0].Email)
+	assert.True(t, resp.Invitations[0].Expired)
+}
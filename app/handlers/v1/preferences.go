@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type preferencesHandler struct {
+	ctx context.Context
+	cfg config.Config
+	svc ports.PreferencesService
+	pb.UnimplementedPreferencesServiceServer
+}
+
+// NewPreferencesHandler creates a new preferences handler
+func NewPreferencesHandler(ctx context.Context, cfg config.Config, svc ports.PreferencesService) *preferencesHandler {
+	return &preferencesHandler{
+		ctx: ctx,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+// JWTMethodPolicies defines custom JWT method policies.
+// Any token is accepted, and the methods then require it to belong to the requested user or to hold the admin claim.
+func (p *preferencesHandler) JWTMethodPolicies() []interceptors.MethodPolicy {
+	return []interceptors.MethodPolicy{
+		{
+			MethodName:     pb.PreferencesService_GetPreferences_FullMethodName,
+			RequiredClaims: nil,
+		},
+		{
+			MethodName:     pb.PreferencesService_UpdatePreferences_FullMethodName,
+			RequiredClaims: nil,
+		},
+	}
+}
+
+func (p *preferencesHandler) GetPreferences(incomingCtx context.Context, req *pb.GetPreferencesRequest) (*pb.PreferencesResponse, error) {
+	if err := authorizeUser(incomingCtx, req.Id); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, p.ctx), p.cfg.Timeout.Duration)
+	defer cancel()
+
+	resp, err := p.svc.Get(ctx, req.Id)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	return toPreferencesResponse(resp), nil
+}
+
+func (p *preferencesHandler) UpdatePreferences(incomingCtx context.Context, req *pb.UpdatePreferencesRequest) (*pb.PreferencesResponse, error) {
+	if err := authorizeUser(incomingCtx, req.Id); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, p.ctx), p.cfg.Timeout.Duration)
+	defer cancel()
+
+	updateReq := models.UpdatePreferencesReq{
+		Language: req.Language,
+		Timezone: req.Timezone,
+		Reset:    req.ResetToDefault,
+	}
+	if req.Version != nil {
+		version := int(*req.Version)
+		updateReq.Version = &version
+	}
+	if req.Notifications != nil {
+		updateReq.Notifications = models.UpdateNotificationPreferencesReq{
+			Email:     req.Notifications.Email,
+			Push:      req.Notifications.Push,
+			Marketing: req.Notifications.Marketing,
+		}
+	}
+
+	resp, err := p.svc.Update(ctx, req.Id, updateReq)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	return toPreferencesResponse(resp), nil
+}
+
+func toPreferencesResponse(resp models.PreferencesResp) *pb.PreferencesResponse {
+	preferencesResp := &pb.PreferencesResponse{
+		Version:  int32(resp.Version),
+		Language: resp.Language,
+		Timezone: resp.Timezone,
+		Notifications: &pb.NotificationPreferences{
+			Email:     resp.Notifications.Email,
+			Push:      resp.Notifications.Push,
+			Marketing: resp.Notifications.Marketing,
+		},
+	}
+	if !resp.UpdatedAt.IsZero() {
+		preferencesResp.UpdatedAt = timestamppb.New(resp.UpdatedAt)
+	}
+	return preferencesResp
+}
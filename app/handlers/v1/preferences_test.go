@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestGetPreferences_Ok checks that the GetPreferences handler returns the preferences of the user
+func TestGetPreferences_Ok(t *testing.T) {
+	// Arrange
+	preferencesService := mocks.NewPreferencesService(t)
+	expectedResp := models.PreferencesResp{
+		Version:       2,
+		Language:      "es",
+		Timezone:      "Europe/Madrid",
+		Notifications: models.NotificationPreferencesResp{Email: true},
+		UpdatedAt:     time.Now().UTC(),
+	}
+	preferencesService.On(testutils.FunctionName(t, ports.PreferencesService.Get), mock.Anything, "test-id").Return(expectedResp, nil).Once()
+
+	cfg := config.Config{}
+	handler := NewPreferencesHandler(context.Background(), cfg, preferencesService)
+
+	// Act
+	resp, err := handler.GetPreferences(claimsContext("test-id"), &pb.GetPreferencesRequest{Id: "test-id"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), resp.Version)
+	assert.Equal(t, expectedResp.Language, resp.Language)
+	assert.Equal(t, expectedResp.Timezone, resp.Timezone)
+	assert.True(t, resp.Notifications.Email)
+	assert.False(t, resp.Notifications.Push)
+	assert.Equal(t, expectedResp.UpdatedAt, resp.UpdatedAt.AsTime())
+}
+
+// TestGetPreferences_Defaults checks that the GetPreferences handler returns no update time while the user has not updated any preference
+func TestGetPreferences_Defaults(t *testing.T) {
+	// Arrange
+	preferencesService := mocks.NewPreferencesService(t)
+	preferencesService.On(testutils.FunctionName(t, ports.PreferencesService.Get), mock.Anything, "test-id").Return(models.PreferencesResp{Language: "en"}, nil).Once()
+
+	cfg := config.Config{}
+	handler := NewPreferencesHandler(context.Background(), cfg, preferencesService)
+
+	// Act
+	resp, err := handler.GetPreferences(claimsContext("test-id"), &pb.GetPreferencesRequest{Id: "test-id"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), resp.Version)
+	assert.Nil(t, resp.UpdatedAt)
+}
+
+// TestUpdatePreferences_Ok checks that the UpdatePreferences handler maps the request and returns the updated preferences
+func TestUpdatePreferences_Ok(t *testing.T) {
+	// Arrange
+	preferencesService := mocks.NewPreferencesService(t)
+	version, language, marketing := int32(1), "es", true
+	preferencesService.On(testutils.FunctionName(t, ports.PreferencesService.Update), mock.Anything, "test-id", mock.MatchedBy(func(req models.UpdatePreferencesReq) bool {
+		return *req.Version == 1 && *req.Language == "es" && req.Timezone == nil &&
+			*req.Notifications.Marketing && req.Notifications.Email == nil && len(req.Reset) == 1 && req.Reset[0] == "timezone"
+	})).Return(models.PreferencesResp{Version: 2, Language: "es"}, nil).Once()
+
+	cfg := config.Config{}
+	handler := NewPreferencesHandler(context.Background(), cfg, preferencesService)
+
+	// Act
+	resp, err := handler.UpdatePreferences(claimsContext("test-id"), &pb.UpdatePreferencesRequest{
+		Id:             "test-id",
+		Version:        &version,
+		Language:       &language,
+		Notifications:  &pb.UpdateNotificationPreferences{Marketing: &marketing},
+		ResetToDefault: []string{"timezone"},
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), resp.Version)
+	assert.Equal(t, "es", resp.Language)
+}
+
+// TestUpdatePreferences_ServiceError checks that the UpdatePreferences handler returns a gRPC error when the service fails
+func TestUpdatePreferences_ServiceError(t *testing.T) {
+	// Arrange
+	preferencesService := mocks.NewPreferencesService(t)
+	expectedError := "version 1 is outdated, the current version of the preferences is 2"
+	preferencesService.On(testutils.FunctionName(t, ports.PreferencesService.Update), mock.Anything, "test-id", mock.Anything).Return(models.PreferencesResp{}, wrappers.NewValidationErr(errors.New(expectedError))).Once()
+
+	cfg := config.Config{}
+	handler := NewPreferencesHandler(context.Background(), cfg, preferencesService)
+
+	// Act
+	resp, err := handler.UpdatePreferences(claimsContext("test-id"), &pb.UpdatePreferencesRequest{Id: "test-id"})
+
+	// Assert
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+}
+
+// TestGetPreferences_OtherUser checks that the GetPreferences handler denies the preferences of a user to the tokens of other users
+func TestGetPreferences_OtherUser(t *testing.T) {
+	// Arrange
+	preferencesService := mocks.NewPreferencesService(t)
+
+	cfg := config.Config{}
+	handler := NewPreferencesHandler(context.Background(), cfg, preferencesService)
+
+	// Act
+	resp, err := handler.GetPreferences(claimsContext("other-id"), &pb.GetPreferencesRequest{Id: "test-id"})
+
+	// Assert
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+// TestUpdatePreferences_Admin checks that the UpdatePreferences handler lets the admins update the preferences of any user
+func TestUpdatePreferences_Admin(t *testing.T) {
+	// Arrange
+	preferencesService := mocks.NewPreferencesService(t)
+	preferencesService.On(testutils.FunctionName(t, ports.PreferencesService.Update), mock.Anything, "test-id", mock.Anything).Return(models.PreferencesResp{}, nil).Once()
+
+	cfg := config.Config{}
+	handler := NewPreferencesHandler(context.Background(), cfg, preferencesService)
+	ctx := context.WithValue(context.Background(), interceptors.ClaimsKey, jwt.MapClaims{"user_id": "admin-id", "admin": true})
+
+	// Act
+	_, err := handler.UpdatePreferences(ctx, &pb.UpdatePreferencesRequest{Id: "test-id"})
+
+	// Assert
+	assert.NoError(t, err)
+}
@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type privacyHandler struct {
+	ctx context.Context
+	cfg config.Config
+	svc ports.PrivacyService
+	pb.UnimplementedPrivacyServiceServer
+}
+
+// NewPrivacyHandler creates a new privacy handler
+func NewPrivacyHandler(ctx context.Context, cfg config.Config, svc ports.PrivacyService) *privacyHandler {
+	return &privacyHandler{
+		ctx: ctx,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+// JWTMethodPolicies defines custom JWT method policies
+func (p *privacyHandler) JWTMethodPolicies() []interceptors.MethodPolicy {
+	return []interceptors.MethodPolicy{
+		{
+			MethodName:     pb.PrivacyService_ExportUserData_FullMethodName,
+			RequiredClaims: []string{"admin"},
+		},
+		{
+			MethodName:     pb.PrivacyService_EraseUser_FullMethodName,
+			RequiredClaims: []string{"admin"},
+		},
+	}
+}
+
+func (p *privacyHandler) ExportUserData(incomingCtx context.Context, req *pb.ExportUserDataRequest) (*httpbody.HttpBody, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, p.ctx), p.cfg.Timeout.Duration)
+	defer cancel()
+
+	resp, err := p.svc.ExportUserData(ctx, req.Id)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	exportResp := &httpbody.HttpBody{
+		ContentType: "application/zip",
+		Data:        resp.Content,
+	}
+	return exportResp, nil
+}
+
+func (p *privacyHandler) EraseUser(incomingCtx context.Context, req *pb.EraseUserRequest) (*pb.EraseUserResponse, error) {
+	ctx, cancel := context.WithTimeout(auditContext(incomingCtx, p.ctx), p.cfg.Timeout.Duration)
+	defer cancel()
+
+	resp, err := p.svc.EraseUser(ctx, req.Id, actorID(incomingCtx))
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	eraseResp := &pb.EraseUserResponse{
+		TombstoneId: resp.TombstoneID,
+		Stores:      resp.Stores,
+		ErasedAt:    timestamppb.New(resp.ErasedAt),
+	}
+	return eraseResp, nil
+}
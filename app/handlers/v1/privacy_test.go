@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestExportUserData_Ok checks that the ExportUserData handler returns the archive as a zip body
+func TestExportUserData_Ok(t *testing.T) {
+	// Arrange
+	privacyService := mocks.NewPrivacyService(t)
+	expectedArchive := models.UserDataArchive{Content: []byte("test-archive")}
+	privacyService.On(testutils.FunctionName(t, ports.PrivacyService.ExportUserData), mock.Anything, "test-id").Return(expectedArchive, nil).Once()
+
+	cfg := config.Config{}
+	handler := NewPrivacyHandler(context.Background(), cfg, privacyService)
+
+	// Act
+	resp, err := handler.ExportUserData(context.Background(), &pb.ExportUserDataRequest{Id: "test-id"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "application/zip", resp.ContentType)
+	assert.Equal(t, expectedArchive.Content, resp.Data)
+}
+
+// TestExportUserData_ServiceError checks that the ExportUserData handler returns a gRPC error when the service fails
+func TestExportUserData_ServiceError(t *testing.T) {
+	// Arrange
+	privacyService := mocks.NewPrivacyService(t)
+	expectedError := "not found"
+	privacyService.On(testutils.FunctionName(t, ports.PrivacyService.ExportUserData), mock.Anything, "test-id").Return(models.UserDataArchive{}, wrappers.NewNonExistentErr(errors.New(expectedError))).Once()
+
+	cfg := config.Config{}
+	handler := NewPrivacyHandler(context.Background(), cfg, privacyService)
+
+	// Act
+	resp, err := handler.ExportUserData(context.Background(), &pb.ExportUserDataRequest{Id: "test-id"})
+
+	// Assert
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+}
+
+// TestEraseUser_Ok checks that the EraseUser handler passes the user_id claim of the token as the actor and returns the expected response
+func TestEraseUser_Ok(t *testing.T) {
+	// Arrange
+	privacyService := mocks.NewPrivacyService(t)
+	expectedResp := models.EraseUserResp{
+		TombstoneID: "tombstone-id",
+		Stores:      []string{"users"},
+		ErasedAt:    time.Now(),
+	}
+	privacyService.On(testutils.FunctionName(t, ports.PrivacyService.EraseUser), mock.Anything, "test-id", "admin-id").Return(expectedResp, nil).Once()
+
+	cfg := config.Config{}
+	handler := NewPrivacyHandler(context.Background(), cfg, privacyService)
+	ctx := context.WithValue(context.Background(), interceptors.ClaimsKey, jwt.MapClaims{"user_id": "admin-id"})
+
+	// Act
+	resp, err := handler.EraseUser(ctx, &pb.EraseUserRequest{Id: "test-id"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResp.TombstoneID, resp.TombstoneId)
+	assert.Equal(t, expectedResp.Stores, resp.Stores)
+	assert.True(t, expectedResp.ErasedAt.Equal(resp.ErasedAt.AsTime()))
+}
+
+// TestEraseUser_ServiceError checks that the EraseUser handler returns a gRPC error when the service fails
+func TestEraseUser_ServiceError(t *testing.T) {
+	// Arrange
+	privacyService := mocks.NewPrivacyService(t)
+	expectedError := "service-error"
+	privacyService.On(testutils.FunctionName(t, ports.PrivacyService.EraseUser), mock.Anything, "test-id", "").Return(models.EraseUserResp{}, errors.New(expectedError)).Once()
+
+	cfg := config.Config{}
+	handler := NewPrivacyHandler(context.Background(), cfg, privacyService)
+
+	// Act
+	resp, err := handler.EraseUser(context.Background(), &pb.EraseUserRequest{Id: "test-id"})
+
+	// Assert
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+}
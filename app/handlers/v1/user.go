@@ -1,18 +1,26 @@
 package v1
 
 import (
+	"bufio"
 	"context"
+	"errors"
+	"io"
+	"strings"
 
 	"github.com/sergicanet9/go-hexagonal-api/config"
 	"github.com/sergicanet9/go-hexagonal-api/core/models"
 	"github.com/sergicanet9/go-hexagonal-api/core/ports"
 	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
 	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
-	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// exportChunkSize maximum size of the chunks sent by ExportUsers
+const exportChunkSize = 64 * 1024
+
 type userHandler struct {
 	ctx context.Context
 	cfg config.Config
@@ -32,12 +40,18 @@ func NewUserHandler(ctx context.Context, cfg config.Config, svc ports.UserServic
 // JWTMethodPolicies defines custom JWT method policies
 func (u *userHandler) JWTMethodPolicies() []interceptors.MethodPolicy {
 	methodClaims := map[string][]string{
-		pb.UserService_GetAll_FullMethodName:     nil,
-		pb.UserService_GetByEmail_FullMethodName: nil,
-		pb.UserService_GetByID_FullMethodName:    nil,
-		pb.UserService_Update_FullMethodName:     nil,
-		pb.UserService_GetClaims_FullMethodName:  nil,
-		pb.UserService_Delete_FullMethodName:     {"admin"},
+		pb.UserService_GetAll_FullMethodName:      nil,
+		pb.UserService_GetByEmail_FullMethodName:  nil,
+		pb.UserService_GetByID_FullMethodName:     nil,
+		pb.UserService_Update_FullMethodName:      nil,
+		pb.UserService_UpdateMany_FullMethodName:  {"admin"},
+		pb.UserService_GetClaims_FullMethodName:   nil,
+		pb.UserService_Delete_FullMethodName:      {"admin"},
+		pb.UserService_DeleteMany_FullMethodName:  {"admin"},
+		pb.UserService_ImportUsers_FullMethodName: {"admin"},
+		pb.UserService_ExportUsers_FullMethodName: {"admin"},
+		pb.UserService_WatchUsers_FullMethodName:  {"admin"},
+		pb.UserService_SearchUsers_FullMethodName: nil,
 	}
 
 	var policies []interceptors.MethodPolicy
@@ -51,8 +65,16 @@ func (u *userHandler) JWTMethodPolicies() []interceptors.MethodPolicy {
 	return policies
 }
 
-func (u *userHandler) Login(_ context.Context, req *pb.LoginUserRequest) (*pb.LoginUserResponse, error) {
-	ctx, cancel := context.WithTimeout(u.ctx, u.cfg.Timeout.Duration)
+// IdempotentMethods defines the methods whose responses get replayed when retried with the same idempotency key
+func (u *userHandler) IdempotentMethods() []string {
+	return []string{
+		pb.UserService_Create_FullMethodName,
+		pb.UserService_CreateMany_FullMethodName,
+	}
+}
+
+func (u *userHandler) Login(incomingCtx context.Context, req *pb.LoginUserRequest) (*pb.LoginUserResponse, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, u.ctx), u.cfg.Timeout.Duration)
 	defer cancel()
 
 	loginReq := models.LoginUserReq{
@@ -62,7 +84,7 @@ func (u *userHandler) Login(_ context.Context, req *pb.LoginUserRequest) (*pb.Lo
 
 	resp, err := u.svc.Login(ctx, loginReq)
 	if err != nil {
-		return nil, utils.ToGRPC(err)
+		return nil, ToGRPC(err)
 	}
 
 	loginResp := &pb.LoginUserResponse{
@@ -72,6 +94,7 @@ func (u *userHandler) Login(_ context.Context, req *pb.LoginUserRequest) (*pb.Lo
 			Surnames:  resp.User.Surnames,
 			Email:     resp.User.Email,
 			ClaimIds:  resp.User.ClaimIDs,
+			AvatarUrl: resp.User.AvatarURL,
 			CreatedAt: timestamppb.New(resp.User.CreatedAt),
 			UpdatedAt: timestamppb.New(resp.User.UpdatedAt),
 		},
@@ -80,8 +103,8 @@ func (u *userHandler) Login(_ context.Context, req *pb.LoginUserRequest) (*pb.Lo
 	return loginResp, nil
 }
 
-func (u *userHandler) Create(_ context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
-	ctx, cancel := context.WithTimeout(u.ctx, u.cfg.Timeout.Duration)
+func (u *userHandler) Create(incomingCtx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	ctx, cancel := context.WithTimeout(auditContext(incomingCtx, u.ctx), u.cfg.Timeout.Duration)
 	defer cancel()
 
 	createReq := models.CreateUserReq{
@@ -94,7 +117,7 @@ func (u *userHandler) Create(_ context.Context, req *pb.CreateUserRequest) (*pb.
 
 	resp, err := u.svc.Create(ctx, createReq)
 	if err != nil {
-		return nil, utils.ToGRPC(err)
+		return nil, ToGRPC(err)
 	}
 
 	createResp := &pb.CreateUserResponse{
@@ -103,8 +126,8 @@ func (u *userHandler) Create(_ context.Context, req *pb.CreateUserRequest) (*pb.
 	return createResp, nil
 }
 
-func (u *userHandler) CreateMany(_ context.Context, req *pb.CreateManyUsersRequest) (*pb.CreateManyUsersResponse, error) {
-	ctx, cancel := context.WithTimeout(u.ctx, u.cfg.Timeout.Duration)
+func (u *userHandler) CreateMany(incomingCtx context.Context, req *pb.CreateManyUsersRequest) (*pb.CreateManyUsersResponse, error) {
+	ctx, cancel := context.WithTimeout(auditContext(incomingCtx, u.ctx), u.cfg.Timeout.Duration)
 	defer cancel()
 
 	var createManyReq []models.CreateUserReq
@@ -118,24 +141,58 @@ func (u *userHandler) CreateMany(_ context.Context, req *pb.CreateManyUsersReque
 		})
 	}
 
-	resp, err := u.svc.CreateMany(ctx, createManyReq)
+	resp, err := u.svc.CreateMany(ctx, createManyReq, req.Partial)
 	if err != nil {
-		return nil, utils.ToGRPC(err)
+		return nil, ToGRPC(err)
 	}
 
 	createManyResp := &pb.CreateManyUsersResponse{
-		Ids: resp.IDs,
+		Ids:     resp.IDs,
+		Results: toBulkItemResults(resp.Results),
 	}
 	return createManyResp, nil
 }
 
-func (u *userHandler) GetAll(_ context.Context, _ *emptypb.Empty) (*pb.GetAllUsersResponse, error) {
-	ctx, cancel := context.WithTimeout(u.ctx, u.cfg.Timeout.Duration)
+func (u *userHandler) UpdateMany(incomingCtx context.Context, req *pb.UpdateManyUsersRequest) (*pb.BulkUsersResponse, error) {
+	ctx, cancel := context.WithTimeout(auditContext(incomingCtx, u.ctx), u.cfg.Timeout.Duration)
+	defer cancel()
+
+	var updateManyReq []models.UpdateManyUserReq
+	for _, user := range req.Users {
+		updateReq := models.UpdateManyUserReq{
+			ID: user.Id,
+			UpdateUserReq: models.UpdateUserReq{
+				Name:        user.Name,
+				Surnames:    user.Surnames,
+				Email:       user.Email,
+				OldPassword: user.OldPassword,
+				NewPassword: user.NewPassword,
+			},
+		}
+		if user.Claims != nil {
+			updateReq.ClaimIDs = &user.Claims.Ids
+		}
+		updateManyReq = append(updateManyReq, updateReq)
+	}
+
+	resp, err := u.svc.UpdateMany(ctx, updateManyReq, req.Partial)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	updateManyResp := &pb.BulkUsersResponse{
+		Results: toBulkItemResults(resp.Results),
+	}
+	return updateManyResp, nil
+}
+
+func (u *userHandler) GetAll(incomingCtx context.Context, _ *emptypb.Empty) (*pb.GetAllUsersResponse, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, u.ctx), u.cfg.Timeout.Duration)
 	defer cancel()
 
 	resp, err := u.svc.GetAll(ctx)
 	if err != nil {
-		return nil, utils.ToGRPC(err)
+		return nil, ToGRPC(err)
 	}
 
 	var getAllRespList []*pb.GetUserResponse
@@ -146,6 +203,7 @@ func (u *userHandler) GetAll(_ context.Context, _ *emptypb.Empty) (*pb.GetAllUse
 			Surnames:  user.Surnames,
 			Email:     user.Email,
 			ClaimIds:  user.ClaimIDs,
+			AvatarUrl: user.AvatarURL,
 			CreatedAt: timestamppb.New(user.CreatedAt),
 			UpdatedAt: timestamppb.New(user.UpdatedAt),
 		})
@@ -157,13 +215,13 @@ func (u *userHandler) GetAll(_ context.Context, _ *emptypb.Empty) (*pb.GetAllUse
 	return getAllResp, nil
 }
 
-func (u *userHandler) GetByEmail(_ context.Context, req *pb.GetUserByEmailRequest) (*pb.GetUserResponse, error) {
-	ctx, cancel := context.WithTimeout(u.ctx, u.cfg.Timeout.Duration)
+func (u *userHandler) GetByEmail(incomingCtx context.Context, req *pb.GetUserByEmailRequest) (*pb.GetUserResponse, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, u.ctx), u.cfg.Timeout.Duration)
 	defer cancel()
 
 	resp, err := u.svc.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, utils.ToGRPC(err)
+		return nil, ToGRPC(err)
 	}
 
 	getByEmailResp := &pb.GetUserResponse{
@@ -172,19 +230,20 @@ func (u *userHandler) GetByEmail(_ context.Context, req *pb.GetUserByEmailReques
 		Surnames:  resp.Surnames,
 		Email:     resp.Email,
 		ClaimIds:  resp.ClaimIDs,
+		AvatarUrl: resp.AvatarURL,
 		CreatedAt: timestamppb.New(resp.CreatedAt),
 		UpdatedAt: timestamppb.New(resp.UpdatedAt),
 	}
 	return getByEmailResp, nil
 }
 
-func (u *userHandler) GetByID(_ context.Context, req *pb.GetUserByIDRequest) (*pb.GetUserResponse, error) {
-	ctx, cancel := context.WithTimeout(u.ctx, u.cfg.Timeout.Duration)
+func (u *userHandler) GetByID(incomingCtx context.Context, req *pb.GetUserByIDRequest) (*pb.GetUserResponse, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, u.ctx), u.cfg.Timeout.Duration)
 	defer cancel()
 
 	resp, err := u.svc.GetByID(ctx, req.Id)
 	if err != nil {
-		return nil, utils.ToGRPC(err)
+		return nil, ToGRPC(err)
 	}
 
 	getByIDResp := &pb.GetUserResponse{
@@ -193,14 +252,49 @@ func (u *userHandler) GetByID(_ context.Context, req *pb.GetUserByIDRequest) (*p
 		Surnames:  resp.Surnames,
 		Email:     resp.Email,
 		ClaimIds:  resp.ClaimIDs,
+		AvatarUrl: resp.AvatarURL,
 		CreatedAt: timestamppb.New(resp.CreatedAt),
 		UpdatedAt: timestamppb.New(resp.UpdatedAt),
 	}
 	return getByIDResp, nil
 }
 
-func (u *userHandler) Update(_ context.Context, req *pb.UpdateUserRequest) (*emptypb.Empty, error) {
-	ctx, cancel := context.WithTimeout(u.ctx, u.cfg.Timeout.Duration)
+func (u *userHandler) SearchUsers(incomingCtx context.Context, req *pb.SearchUsersRequest) (*pb.SearchUsersResponse, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, u.ctx), u.cfg.Timeout.Duration)
+	defer cancel()
+
+	searchReq := models.SearchUsersReq{
+		Query:     req.Query,
+		PageSize:  int(req.PageSize),
+		PageToken: req.PageToken,
+	}
+
+	resp, err := u.svc.Search(ctx, searchReq)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	searchResp := &pb.SearchUsersResponse{
+		Users:         []*pb.GetUserResponse{},
+		NextPageToken: resp.NextPageToken,
+	}
+	for _, user := range resp.Users {
+		searchResp.Users = append(searchResp.Users, &pb.GetUserResponse{
+			Id:        user.ID,
+			Name:      user.Name,
+			Surnames:  user.Surnames,
+			Email:     user.Email,
+			ClaimIds:  user.ClaimIDs,
+			AvatarUrl: user.AvatarURL,
+			CreatedAt: timestamppb.New(user.CreatedAt),
+			UpdatedAt: timestamppb.New(user.UpdatedAt),
+		})
+	}
+	return searchResp, nil
+}
+
+func (u *userHandler) Update(incomingCtx context.Context, req *pb.UpdateUserRequest) (*emptypb.Empty, error) {
+	ctx, cancel := context.WithTimeout(auditContext(incomingCtx, u.ctx), u.cfg.Timeout.Duration)
 	defer cancel()
 
 	updateReq := models.UpdateUserReq{
@@ -216,13 +310,24 @@ func (u *userHandler) Update(_ context.Context, req *pb.UpdateUserRequest) (*emp
 
 	err := u.svc.Update(ctx, req.Id, updateReq)
 	if err != nil {
-		return nil, utils.ToGRPC(err)
+		return nil, ToGRPC(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (u *userHandler) ConfirmEmailChange(incomingCtx context.Context, req *pb.ConfirmEmailChangeRequest) (*emptypb.Empty, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, u.ctx), u.cfg.Timeout.Duration)
+	defer cancel()
+
+	_, err := u.svc.ConfirmEmailChange(ctx, models.ConfirmEmailChangeReq{Token: req.Token})
+	if err != nil {
+		return nil, ToGRPC(err)
 	}
 	return &emptypb.Empty{}, nil
 }
 
-func (u *userHandler) GetClaims(_ context.Context, _ *emptypb.Empty) (*pb.GetClaimsResponse, error) {
-	ctx, cancel := context.WithTimeout(u.ctx, u.cfg.Timeout.Duration)
+func (u *userHandler) GetClaims(incomingCtx context.Context, _ *emptypb.Empty) (*pb.GetClaimsResponse, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, u.ctx), u.cfg.Timeout.Duration)
 	defer cancel()
 
 	resp := u.svc.GetUserClaims(ctx)
@@ -240,14 +345,216 @@ func (u *userHandler) GetClaims(_ context.Context, _ *emptypb.Empty) (*pb.GetCla
 	return getClaimsResp, nil
 }
 
-func (u *userHandler) Delete(_ context.Context, req *pb.DeleteUserRequest) (*emptypb.Empty, error) {
-	ctx, cancel := context.WithTimeout(u.ctx, u.cfg.Timeout.Duration)
+func (u *userHandler) Delete(incomingCtx context.Context, req *pb.DeleteUserRequest) (*emptypb.Empty, error) {
+	ctx, cancel := context.WithTimeout(auditContext(incomingCtx, u.ctx), u.cfg.Timeout.Duration)
 	defer cancel()
 
 	err := u.svc.Delete(ctx, req.Id)
 	if err != nil {
-		return nil, utils.ToGRPC(err)
+		return nil, ToGRPC(err)
 	}
 
 	return &emptypb.Empty{}, nil
 }
+
+func (u *userHandler) DeleteMany(incomingCtx context.Context, req *pb.DeleteManyUsersRequest) (*pb.BulkUsersResponse, error) {
+	ctx, cancel := context.WithTimeout(auditContext(incomingCtx, u.ctx), u.cfg.Timeout.Duration)
+	defer cancel()
+
+	resp, err := u.svc.DeleteMany(ctx, req.Ids, req.Partial)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	deleteManyResp := &pb.BulkUsersResponse{
+		Results: toBulkItemResults(resp.Results),
+	}
+	return deleteManyResp, nil
+}
+
+func (u *userHandler) ImportUsers(stream pb.UserService_ImportUsersServer) error {
+	ctx, cancel := context.WithTimeout(auditContext(stream.Context(), u.ctx), u.cfg.Import.Timeout.Duration)
+	defer cancel()
+
+	first, err := stream.Recv()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	source, w := io.Pipe()
+	defer source.Close()
+	go pipeImportChunks(stream, first, w)
+
+	importReq := models.ImportUsersReq{
+		Source: source,
+		Format: toImportFormat(first.GetFormat()),
+		DryRun: first.GetDryRun(),
+	}
+
+	resp, err := u.svc.Import(ctx, importReq)
+	if err != nil {
+		return ToGRPC(err)
+	}
+
+	importResp := &pb.ImportUsersResponse{
+		Total:    int32(resp.Total),
+		Imported: int32(resp.Imported),
+		DryRun:   resp.DryRun,
+		Errors:   toBulkItemResults(resp.Errors),
+	}
+	return stream.SendAndClose(importResp)
+}
+
+// pipeImportChunks writes the chunks received in the stream into the pipe until the client closes the stream
+func pipeImportChunks(stream pb.UserService_ImportUsersServer, first *pb.ImportUsersRequest, w *io.PipeWriter) {
+	if first == nil {
+		w.Close()
+		return
+	}
+
+	req := first
+	for {
+		if _, err := w.Write(req.Chunk); err != nil {
+			return
+		}
+
+		var err error
+		req, err = stream.Recv()
+		if errors.Is(err, io.EOF) {
+			w.Close()
+			return
+		}
+		if err != nil {
+			w.CloseWithError(err)
+			return
+		}
+	}
+}
+
+func toImportFormat(format pb.ImportFormat) models.ImportFormat {
+	switch format {
+	case pb.ImportFormat_IMPORT_FORMAT_CSV:
+		return models.ImportFormatCSV
+	case pb.ImportFormat_IMPORT_FORMAT_NDJSON:
+		return models.ImportFormatNDJSON
+	default:
+		return models.ImportFormat(strings.ToLower(strings.TrimPrefix(format.String(), "IMPORT_FORMAT_")))
+	}
+}
+
+func (u *userHandler) ExportUsers(req *pb.ExportUsersRequest, stream pb.UserService_ExportUsersServer) error {
+	ctx, cancel := context.WithTimeout(requestContext(stream.Context(), u.ctx), u.cfg.Export.Timeout.Duration)
+	defer cancel()
+
+	exportReq := models.ExportUsersReq{
+		Format:  toExportFormat(req.Format),
+		Columns: req.Columns,
+	}
+	if req.Filter != nil {
+		if req.Filter.CreatedFrom != nil {
+			exportReq.Filter.CreatedFrom = req.Filter.CreatedFrom.AsTime()
+		}
+		if req.Filter.CreatedTo != nil {
+			exportReq.Filter.CreatedTo = req.Filter.CreatedTo.AsTime()
+		}
+		exportReq.Filter.ClaimID = req.Filter.ClaimId
+	}
+
+	w := bufio.NewWriterSize(exportChunkWriter{stream}, exportChunkSize)
+	err := u.svc.Export(ctx, exportReq, w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		return ToGRPC(err)
+	}
+	return nil
+}
+
+// exportChunkWriter sends the written bytes as chunks of the stream, splitting the writes larger than exportChunkSize
+type exportChunkWriter struct {
+	stream pb.UserService_ExportUsersServer
+}
+
+func (w exportChunkWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		chunk := p[:min(len(p), exportChunkSize)]
+		if err = w.stream.Send(&pb.ExportUsersResponse{Chunk: chunk}); err != nil {
+			return
+		}
+		n += len(chunk)
+		p = p[len(chunk):]
+	}
+	return
+}
+
+func toExportFormat(format pb.ExportFormat) models.ExportFormat {
+	switch format {
+	case pb.ExportFormat_EXPORT_FORMAT_CSV:
+		return models.ExportFormatCSV
+	case pb.ExportFormat_EXPORT_FORMAT_NDJSON:
+		return models.ExportFormatNDJSON
+	case pb.ExportFormat_EXPORT_FORMAT_PARQUET:
+		return models.ExportFormatParquet
+	default:
+		return models.ExportFormat(strings.ToLower(strings.TrimPrefix(format.String(), "EXPORT_FORMAT_")))
+	}
+}
+
+func (u *userHandler) WatchUsers(req *pb.WatchUsersRequest, stream pb.UserService_WatchUsersServer) error {
+	// the watch lasts until the client closes the stream or the server shuts down, and it is not bounded by any timeout
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	stop := context.AfterFunc(u.ctx, cancel)
+	defer stop()
+
+	// the headers are sent right away, so that the clients know the watch started even when no change happens
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	err := u.svc.Watch(ctx, req.ResumeToken, func(change models.UserChangeResp) error {
+		return stream.Send(toPBUserChange(change))
+	})
+	if err != nil {
+		return ToGRPC(err)
+	}
+	return nil
+}
+
+func toPBUserChange(change models.UserChangeResp) *pb.UserChange {
+	userChange := &pb.UserChange{
+		ResumeToken: change.ResumeToken,
+		Type:        pb.UserChangeType(pb.UserChangeType_value["USER_CHANGE_TYPE_"+strings.ToUpper(change.Type)]),
+		UserId:      change.UserID,
+		OccurredAt:  timestamppb.New(change.OccurredAt),
+	}
+	if change.User != nil {
+		userChange.User = &pb.GetUserResponse{
+			Id:        change.User.ID,
+			Name:      change.User.Name,
+			Surnames:  change.User.Surnames,
+			Email:     change.User.Email,
+			ClaimIds:  change.User.ClaimIDs,
+			AvatarUrl: change.User.AvatarURL,
+			CreatedAt: timestamppb.New(change.User.CreatedAt),
+			UpdatedAt: timestamppb.New(change.User.UpdatedAt),
+		}
+	}
+	return userChange
+}
+
+// toBulkItemResults maps the per-item results of a bulk operation, translating each item error to its gRPC code
+func toBulkItemResults(results []models.BulkItemResult) []*pb.BulkItemResult {
+	var bulkItemResults []*pb.BulkItemResult
+	for _, result := range results {
+		st := status.Convert(ToGRPC(result.Err))
+		bulkItemResults = append(bulkItemResults, &pb.BulkItemResult{
+			Index:   int32(result.Index),
+			Id:      result.ID,
+			Code:    st.Code().String(),
+			Message: st.Message(),
+		})
+	}
+	return bulkItemResults
+}
@@ -2,19 +2,29 @@ package v1
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"io"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
 	"github.com/sergicanet9/go-hexagonal-api/core/models"
 	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/postgres"
 	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
 	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	scvMocks "github.com/sergicanet9/scv-go-tools/v4/mocks"
 	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -146,7 +156,7 @@ func TestCreateManyUsers_Ok(t *testing.T) {
 	expectedResp := models.CreateManyUserResp{
 		IDs: []string{"id-1", "id-2"},
 	}
-	userService.On(testutils.FunctionName(t, ports.UserService.CreateMany), mock.Anything, mock.AnythingOfType("[]models.CreateUserReq")).Return(expectedResp, nil).Once()
+	userService.On(testutils.FunctionName(t, ports.UserService.CreateMany), mock.Anything, mock.AnythingOfType("[]models.CreateUserReq"), false).Return(expectedResp, nil).Once()
 
 	cfg := config.Config{}
 	handler := NewUserHandler(context.Background(), cfg, userService)
@@ -171,7 +181,7 @@ func TestCreateManyUsers_ServiceError(t *testing.T) {
 	// Arrange
 	userService := mocks.NewUserService(t)
 	expectedError := "service-error"
-	userService.On(testutils.FunctionName(t, ports.UserService.CreateMany), mock.Anything, mock.AnythingOfType("[]models.CreateUserReq")).Return(models.CreateManyUserResp{}, errors.New(expectedError)).Once()
+	userService.On(testutils.FunctionName(t, ports.UserService.CreateMany), mock.Anything, mock.AnythingOfType("[]models.CreateUserReq"), false).Return(models.CreateManyUserResp{}, errors.New(expectedError)).Once()
 
 	cfg := config.Config{}
 	handler := NewUserHandler(context.Background(), cfg, userService)
@@ -193,6 +203,31 @@ func TestCreateManyUsers_ServiceError(t *testing.T) {
 	assert.Equal(t, expectedError, st.Message())
 }
 
+// TestCreateManyUsers_EmailInUse checks that the CreateMany handler returns codes.AlreadyExists along with the email in use
+func TestCreateManyUsers_EmailInUse(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	userService.On(testutils.FunctionName(t, ports.UserService.CreateMany), mock.Anything, mock.AnythingOfType("[]models.CreateUserReq"), false).Return(models.CreateManyUserResp{}, entities.NewEmailInUseErr("test1@test.com")).Once()
+
+	cfg := config.Config{}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	req := &pb.CreateManyUsersRequest{
+		Users: []*pb.CreateUserRequest{
+			{Email: "test1@test.com", Password: "test"},
+		},
+	}
+
+	// Act
+	_, err := handler.CreateMany(context.Background(), req)
+
+	// Assert
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.AlreadyExists, st.Code())
+	assert.Equal(t, "email test1@test.com is already in use", st.Message())
+}
+
 // TestGetAllUsers_Ok checks that the GetAll handler returns the expected response
 func TestGetAllUsers_Ok(t *testing.T) {
 	// Arrange
@@ -358,6 +393,95 @@ func TestGetUserByID_ServiceError(t *testing.T) {
 	assert.Equal(t, expectedError, st.Message())
 }
 
+// TestConfirmEmailChange_Ok checks that the ConfirmEmailChange handler does not return an error when everything goes as expected
+func TestConfirmEmailChange_Ok(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	userService.On(testutils.FunctionName(t, ports.UserService.ConfirmEmailChange), mock.Anything, models.ConfirmEmailChangeReq{Token: "test-token"}).Return(models.ConfirmEmailChangeResp{}, nil).Once()
+
+	cfg := config.Config{}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	// Act
+	_, err := handler.ConfirmEmailChange(context.Background(), &pb.ConfirmEmailChangeRequest{Token: "test-token"})
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+// TestConfirmEmailChange_ServiceError checks that the ConfirmEmailChange handler returns a gRPC error when the service fails
+func TestConfirmEmailChange_ServiceError(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	expectedError := "confirmation token has expired"
+	userService.On(testutils.FunctionName(t, ports.UserService.ConfirmEmailChange), mock.Anything, mock.Anything).Return(models.ConfirmEmailChangeResp{}, wrappers.NewValidationErr(errors.New(expectedError))).Once()
+
+	cfg := config.Config{}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	// Act
+	_, err := handler.ConfirmEmailChange(context.Background(), &pb.ConfirmEmailChangeRequest{Token: "test-token"})
+
+	// Assert
+	assert.Error(t, err)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+}
+
+// TestSearchUsers_Ok checks that the SearchUsers handler returns the expected response when everything goes as expected
+func TestSearchUsers_Ok(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	expectedReq := models.SearchUsersReq{
+		Query:     "jose",
+		PageSize:  10,
+		PageToken: "10",
+	}
+	expectedResp := models.SearchUsersResp{
+		Users:         []models.GetUserResp{{ID: "test-id", Name: "José"}},
+		NextPageToken: "20",
+	}
+	userService.On(testutils.FunctionName(t, ports.UserService.Search), mock.Anything, expectedReq).Return(expectedResp, nil).Once()
+
+	cfg := config.Config{}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	req := &pb.SearchUsersRequest{Query: expectedReq.Query, PageSize: 10, PageToken: expectedReq.PageToken}
+
+	// Act
+	resp, err := handler.SearchUsers(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, resp.Users, 1)
+	assert.Equal(t, "test-id", resp.Users[0].Id)
+	assert.Equal(t, "José", resp.Users[0].Name)
+	assert.Equal(t, expectedResp.NextPageToken, resp.NextPageToken)
+}
+
+// TestSearchUsers_ServiceError checks that the SearchUsers handler returns a gRPC error when the service fails
+func TestSearchUsers_ServiceError(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	expectedError := "query cannot be empty"
+	userService.On(testutils.FunctionName(t, ports.UserService.Search), mock.Anything, mock.Anything).Return(models.SearchUsersResp{}, wrappers.NewValidationErr(errors.New(expectedError))).Once()
+
+	cfg := config.Config{}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	// Act
+	_, err := handler.SearchUsers(context.Background(), &pb.SearchUsersRequest{})
+
+	// Assert
+	assert.Error(t, err)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+}
+
 // TestUpdateUser_Ok checks that the Update handler returns no error on a valid request
 func TestUpdateUser_Ok(t *testing.T) {
 	// Arrange
@@ -377,6 +501,50 @@ func TestUpdateUser_Ok(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+// TestUpdateUser_ReadsOwnWrites checks that the reads following a write in the same request are served by the primary instead of the replicas
+func TestUpdateUser_ReadsOwnWrites(t *testing.T) {
+	// Arrange
+	primaryMock, primary := scvMocks.NewSqlDB(t)
+	defer primary.Close()
+	replicaMock, replica := scvMocks.NewSqlDB(t)
+	replicaMock.ExpectQuery("SELECT CASE").WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(0))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	repo := postgres.NewUserRepository(primary, postgres.NewReplicas(ctx, []*sql.DB{replica}, postgres.ReplicaOptions{CheckInterval: time.Hour}))
+
+	testID := "test-id"
+	primaryMock.ExpectBegin()
+	primaryMock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+	primaryMock.ExpectExec("INSERT INTO outbox_events").WillReturnResult(sqlmock.NewResult(0, 1))
+	primaryMock.ExpectCommit()
+	primaryMock.ExpectQuery("FROM users WHERE id").WithArgs(testID).WillReturnRows(sqlmock.NewRows(
+		[]string{"id", "name", "surnames", "email", "email_normalized", "password_hash", "claim_ids", "avatar_url", "created_at", "updated_at"},
+	).AddRow(testID, "name", "surnames", "test@test.com", "test@test.com", "hash", "{}", "", time.Now(), time.Now()))
+
+	userService := mocks.NewUserService(t)
+	userService.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, testID, mock.AnythingOfType("models.UpdateUserReq")).Run(func(args mock.Arguments) {
+		ctx := args.Get(0).(context.Context)
+		assert.Nil(t, repo.Update(ctx, testID, entities.User{}))
+		_, err := repo.GetByID(ctx, testID)
+		assert.Nil(t, err)
+	}).Return(nil).Once()
+
+	cfg := config.Config{}
+	cfg.Timeout = utils.Duration{Duration: time.Second}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	// the flag is set by the read-your-writes interceptor of the API
+	incomingCtx := postgres.WithReadYourWrites(context.Background())
+
+	// Act
+	_, err := handler.Update(incomingCtx, &pb.UpdateUserRequest{Id: testID})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Nil(t, primaryMock.ExpectationsWereMet())
+	assert.Nil(t, replicaMock.ExpectationsWereMet())
+}
+
 // TestUpdateUser_ServiceError checks that the Update handler returns a gRPC error when the service fails
 func TestUpdateUser_ServiceError(t *testing.T) {
 	// Arrange
@@ -472,3 +640,384 @@ func TestDeleteUser_ServiceError(t *testing.T) {
 	assert.Equal(t, codes.Internal, st.Code())
 	assert.Equal(t, expectedError, st.Message())
 }
+
+// TestUpdateManyUsers_Ok checks that the UpdateMany handler returns the per-item results on a valid request
+func TestUpdateManyUsers_Ok(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	expectedResp := models.BulkUserResp{
+		Results: []models.BulkItemResult{
+			{Index: 0, ID: "id-1"},
+			{Index: 1, ID: "id-2", Err: wrappers.NewNonExistentErr(errors.New("ID id-2 not found"))},
+		},
+	}
+	userService.On(testutils.FunctionName(t, ports.UserService.UpdateMany), mock.Anything, mock.AnythingOfType("[]models.UpdateManyUserReq"), true).Return(expectedResp, nil).Once()
+
+	cfg := config.Config{}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	req := &pb.UpdateManyUsersRequest{
+		Users: []*pb.UpdateUserRequest{
+			{Id: "id-1", Claims: &pb.ClaimIds{Ids: []int32{0}}},
+			{Id: "id-2"},
+		},
+		Partial: true,
+	}
+
+	// Act
+	resp, err := handler.UpdateMany(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, resp.Results, 2)
+	assert.Equal(t, codes.OK.String(), resp.Results[0].Code)
+	assert.Equal(t, "id-1", resp.Results[0].Id)
+	assert.Equal(t, codes.NotFound.String(), resp.Results[1].Code)
+	assert.Equal(t, "ID id-2 not found", resp.Results[1].Message)
+}
+
+// TestUpdateManyUsers_ServiceError checks that the UpdateMany handler returns a gRPC error when the service fails
+func TestUpdateManyUsers_ServiceError(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	expectedError := "service-error"
+	userService.On(testutils.FunctionName(t, ports.UserService.UpdateMany), mock.Anything, mock.AnythingOfType("[]models.UpdateManyUserReq"), false).Return(models.BulkUserResp{}, errors.New(expectedError)).Once()
+
+	cfg := config.Config{}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	req := &pb.UpdateManyUsersRequest{
+		Users: []*pb.UpdateUserRequest{{Id: "id-1"}},
+	}
+
+	// Act
+	_, err := handler.UpdateMany(context.Background(), req)
+
+	// Assert
+	assert.Error(t, err)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+}
+
+// TestDeleteManyUsers_Ok checks that the DeleteMany handler returns the per-item results on a valid request
+func TestDeleteManyUsers_Ok(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	testIDs := []string{"id-1", "id-2"}
+	expectedResp := models.BulkUserResp{
+		Results: []models.BulkItemResult{{Index: 0, ID: "id-1"}, {Index: 1, ID: "id-2"}},
+	}
+	userService.On(testutils.FunctionName(t, ports.UserService.DeleteMany), mock.Anything, testIDs, false).Return(expectedResp, nil).Once()
+
+	cfg := config.Config{}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	req := &pb.DeleteManyUsersRequest{Ids: testIDs}
+
+	// Act
+	resp, err := handler.DeleteMany(context.Background(), req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, resp.Results, 2)
+	for i, result := range resp.Results {
+		assert.Equal(t, int32(i), result.Index)
+		assert.Equal(t, testIDs[i], result.Id)
+		assert.Equal(t, codes.OK.String(), result.Code)
+	}
+}
+
+// TestDeleteManyUsers_ServiceError checks that the DeleteMany handler returns a gRPC error when the service fails
+func TestDeleteManyUsers_ServiceError(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	expectedError := "service-error"
+	testIDs := []string{"id-1"}
+	userService.On(testutils.FunctionName(t, ports.UserService.DeleteMany), mock.Anything, testIDs, false).Return(models.BulkUserResp{}, errors.New(expectedError)).Once()
+
+	cfg := config.Config{}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	req := &pb.DeleteManyUsersRequest{Ids: testIDs}
+
+	// Act
+	_, err := handler.DeleteMany(context.Background(), req)
+
+	// Assert
+	assert.Error(t, err)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+}
+
+// TestImportUsers_Ok checks that the ImportUsers handler streams the received chunks to the service and returns the expected response
+func TestImportUsers_Ok(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	expectedSource := "email,password\ntest@test.com,test\n"
+	expectedResp := models.ImportUsersResp{
+		Total:    2,
+		Imported: 1,
+		DryRun:   true,
+		Errors:   []models.BulkItemResult{{Index: 1, Err: wrappers.NewValidationErr(errors.New("password cannot be empty"))}},
+	}
+
+	var source string
+	userService.On(testutils.FunctionName(t, ports.UserService.Import), mock.Anything, mock.MatchedBy(func(req models.ImportUsersReq) bool {
+		return req.Format == models.ImportFormatCSV && req.DryRun
+	})).Run(func(args mock.Arguments) {
+		b, _ := io.ReadAll(args.Get(1).(models.ImportUsersReq).Source)
+		source = string(b)
+	}).Return(expectedResp, nil).Once()
+
+	cfg := config.Config{}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	stream := &importUsersStreamMock{
+		reqs: []*pb.ImportUsersRequest{
+			{Format: pb.ImportFormat_IMPORT_FORMAT_CSV, DryRun: true, Chunk: []byte(expectedSource[:10])},
+			{Chunk: []byte(expectedSource[10:])},
+		},
+	}
+
+	// Act
+	err := handler.ImportUsers(stream)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSource, source)
+	assert.Equal(t, int32(2), stream.resp.Total)
+	assert.Equal(t, int32(1), stream.resp.Imported)
+	assert.True(t, stream.resp.DryRun)
+	assert.Len(t, stream.resp.Errors, 1)
+	assert.Equal(t, int32(1), stream.resp.Errors[0].Index)
+	assert.Equal(t, codes.InvalidArgument.String(), stream.resp.Errors[0].Code)
+}
+
+// TestImportUsers_ServiceError checks that the ImportUsers handler returns a gRPC error when the service fails
+func TestImportUsers_ServiceError(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	expectedError := "service-error"
+	userService.On(testutils.FunctionName(t, ports.UserService.Import), mock.Anything, mock.AnythingOfType("models.ImportUsersReq")).Return(models.ImportUsersResp{}, errors.New(expectedError)).Once()
+
+	cfg := config.Config{}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	stream := &importUsersStreamMock{
+		reqs: []*pb.ImportUsersRequest{{Format: pb.ImportFormat_IMPORT_FORMAT_NDJSON}},
+	}
+
+	// Act
+	err := handler.ImportUsers(stream)
+
+	// Assert
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+	assert.Nil(t, stream.resp)
+}
+
+// importUsersStreamMock client stream that returns the given requests and records the response
+type importUsersStreamMock struct {
+	grpc.ServerStream
+	reqs []*pb.ImportUsersRequest
+	resp *pb.ImportUsersResponse
+}
+
+func (s *importUsersStreamMock) Context() context.Context {
+	return context.Background()
+}
+
+func (s *importUsersStreamMock) Recv() (*pb.ImportUsersRequest, error) {
+	if len(s.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := s.reqs[0]
+	s.reqs = s.reqs[1:]
+	return req, nil
+}
+
+func (s *importUsersStreamMock) SendAndClose(resp *pb.ImportUsersResponse) error {
+	s.resp = resp
+	return nil
+}
+
+// TestExportUsers_Ok checks that the ExportUsers handler streams the output of the service in chunks
+func TestExportUsers_Ok(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	claimID := int32(0)
+	expectedOutput := make([]byte, exportChunkSize+10)
+	userService.On(testutils.FunctionName(t, ports.UserService.Export), mock.Anything, models.ExportUsersReq{
+		Format:  models.ExportFormatNDJSON,
+		Columns: []string{"email"},
+		Filter:  models.UserFilter{ClaimID: &claimID},
+	}, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(2).(io.Writer).Write(expectedOutput)
+	}).Return(nil).Once()
+
+	cfg := config.Config{}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	req := &pb.ExportUsersRequest{
+		Format:  pb.ExportFormat_EXPORT_FORMAT_NDJSON,
+		Columns: []string{"email"},
+		Filter:  &pb.UserFilter{ClaimId: &claimID},
+	}
+	stream := &exportUsersStreamMock{}
+
+	// Act
+	err := handler.ExportUsers(req, stream)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, stream.chunks, 2)
+	var output []byte
+	for _, chunk := range stream.chunks {
+		assert.LessOrEqual(t, len(chunk), exportChunkSize)
+		output = append(output, chunk...)
+	}
+	assert.Equal(t, expectedOutput, output)
+}
+
+// TestExportUsers_ServiceError checks that the ExportUsers handler returns a gRPC error when the service fails
+func TestExportUsers_ServiceError(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	expectedError := "service-error"
+	userService.On(testutils.FunctionName(t, ports.UserService.Export), mock.Anything, mock.AnythingOfType("models.ExportUsersReq"), mock.Anything).Return(wrappers.NewValidationErr(errors.New(expectedError))).Once()
+
+	cfg := config.Config{}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	stream := &exportUsersStreamMock{}
+
+	// Act
+	err := handler.ExportUsers(&pb.ExportUsersRequest{}, stream)
+
+	// Assert
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+	assert.Empty(t, stream.chunks)
+}
+
+// exportUsersStreamMock server stream that records the sent chunks
+type exportUsersStreamMock struct {
+	grpc.ServerStream
+	chunks [][]byte
+}
+
+func (s *exportUsersStreamMock) Context() context.Context {
+	return context.Background()
+}
+
+func (s *exportUsersStreamMock) Send(resp *pb.ExportUsersResponse) error {
+	s.chunks = append(s.chunks, append([]byte(nil), resp.Chunk...))
+	return nil
+}
+
+// TestWatchUsers_Ok checks that the WatchUsers handler sends the headers and then every change received from the service
+func TestWatchUsers_Ok(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	changes := []models.UserChangeResp{
+		{ResumeToken: "1", Type: "created", UserID: "test-id", User: &models.GetUserResp{ID: "test-id", Email: "test@test.com"}},
+		{ResumeToken: "2", Type: "deleted", UserID: "test-id"},
+	}
+	userService.On(testutils.FunctionName(t, ports.UserService.Watch), mock.Anything, "test-token", mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(models.UserChangeResp) error)
+		for _, change := range changes {
+			fn(change)
+		}
+	}).Return(nil).Once()
+
+	cfg := config.Config{}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	stream := &watchUsersStreamMock{}
+
+	// Act
+	err := handler.WatchUsers(&pb.WatchUsersRequest{ResumeToken: "test-token"}, stream)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, stream.headerSent)
+	assert.Len(t, stream.changes, 2)
+	assert.Equal(t, pb.UserChangeType_USER_CHANGE_TYPE_CREATED, stream.changes[0].Type)
+	assert.Equal(t, "test@test.com", stream.changes[0].User.Email)
+	assert.Equal(t, pb.UserChangeType_USER_CHANGE_TYPE_DELETED, stream.changes[1].Type)
+	assert.Equal(t, "2", stream.changes[1].ResumeToken)
+	assert.Nil(t, stream.changes[1].User)
+}
+
+// TestWatchUsers_ServiceError checks that the WatchUsers handler returns a gRPC error when the service fails
+func TestWatchUsers_ServiceError(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	expectedError := "resume token invalid is not valid"
+	userService.On(testutils.FunctionName(t, ports.UserService.Watch), mock.Anything, "invalid", mock.Anything).Return(wrappers.NewValidationErr(errors.New(expectedError))).Once()
+
+	cfg := config.Config{}
+	handler := NewUserHandler(context.Background(), cfg, userService)
+
+	stream := &watchUsersStreamMock{}
+
+	// Act
+	err := handler.WatchUsers(&pb.WatchUsersRequest{ResumeToken: "invalid"}, stream)
+
+	// Assert
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+	assert.Empty(t, stream.changes)
+}
+
+// TestWatchUsers_ServerShutdown checks that the WatchUsers handler stops watching when the server context gets cancelled
+func TestWatchUsers_ServerShutdown(t *testing.T) {
+	// Arrange
+	userService := mocks.NewUserService(t)
+	userService.On(testutils.FunctionName(t, ports.UserService.Watch), mock.Anything, "", mock.Anything).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return(nil).Once()
+
+	serverCtx, cancel := context.WithCancel(context.Background())
+	handler := NewUserHandler(serverCtx, config.Config{}, userService)
+
+	stream := &watchUsersStreamMock{}
+
+	// Act
+	cancel()
+	err := handler.WatchUsers(&pb.WatchUsersRequest{}, stream)
+
+	// Assert
+	assert.NoError(t, err)
+}
+
+// watchUsersStreamMock server stream that records the sent changes
+type watchUsersStreamMock struct {
+	grpc.ServerStream
+	headerSent bool
+	changes    []*pb.UserChange
+}
+
+func (s *watchUsersStreamMock) Context() context.Context {
+	return context.Background()
+}
+
+func (s *watchUsersStreamMock) SendHeader(metadata.MD) error {
+	s.headerSent = true
+	return nil
+}
+
+func (s *watchUsersStreamMock) Send(change *pb.UserChange) error {
+	s.changes = append(s.changes, change)
+	return nil
+}
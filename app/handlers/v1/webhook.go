@@ -0,0 +1,181 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/scv-go-tools/v4/api/interceptors"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type webhookHandler struct {
+	ctx context.Context
+	cfg config.Config
+	svc ports.WebhookService
+	pb.UnimplementedWebhookServiceServer
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(ctx context.Context, cfg config.Config, svc ports.WebhookService) *webhookHandler {
+	return &webhookHandler{
+		ctx: ctx,
+		cfg: cfg,
+		svc: svc,
+	}
+}
+
+// JWTMethodPolicies defines custom JWT method policies
+func (w *webhookHandler) JWTMethodPolicies() []interceptors.MethodPolicy {
+	methods := []string{
+		pb.WebhookService_CreateSubscription_FullMethodName,
+		pb.WebhookService_GetSubscriptions_FullMethodName,
+		pb.WebhookService_GetSubscriptionByID_FullMethodName,
+		pb.WebhookService_UpdateSubscription_FullMethodName,
+		pb.WebhookService_DeleteSubscription_FullMethodName,
+		pb.WebhookService_GetDeadLetters_FullMethodName,
+		pb.WebhookService_ReplayDeadLetter_FullMethodName,
+	}
+
+	var policies []interceptors.MethodPolicy
+	for _, method := range methods {
+		policies = append(policies, interceptors.MethodPolicy{
+			MethodName:     method,
+			RequiredClaims: []string{"admin"},
+		})
+	}
+
+	return policies
+}
+
+func (w *webhookHandler) CreateSubscription(incomingCtx context.Context, req *pb.CreateSubscriptionRequest) (*pb.CreateSubscriptionResponse, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, w.ctx), w.cfg.Timeout.Duration)
+	defer cancel()
+
+	createReq := models.CreateWebhookSubscriptionReq{
+		URL:        req.Url,
+		EventTypes: req.EventTypes,
+		Secret:     req.Secret,
+	}
+
+	resp, err := w.svc.CreateSubscription(ctx, createReq)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	createResp := &pb.CreateSubscriptionResponse{
+		Id:     resp.ID,
+		Secret: resp.Secret,
+	}
+	return createResp, nil
+}
+
+func (w *webhookHandler) GetSubscriptions(incomingCtx context.Context, _ *emptypb.Empty) (*pb.GetSubscriptionsResponse, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, w.ctx), w.cfg.Timeout.Duration)
+	defer cancel()
+
+	resp, err := w.svc.GetSubscriptions(ctx)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	getResp := &pb.GetSubscriptionsResponse{
+		Subscriptions: []*pb.Subscription{},
+	}
+	for _, subscription := range resp {
+		getResp.Subscriptions = append(getResp.Subscriptions, toPBSubscription(subscription))
+	}
+	return getResp, nil
+}
+
+func (w *webhookHandler) GetSubscriptionByID(incomingCtx context.Context, req *pb.GetSubscriptionByIDRequest) (*pb.Subscription, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, w.ctx), w.cfg.Timeout.Duration)
+	defer cancel()
+
+	resp, err := w.svc.GetSubscriptionByID(ctx, req.Id)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	return toPBSubscription(resp), nil
+}
+
+func toPBSubscription(subscription models.WebhookSubscriptionResp) *pb.Subscription {
+	return &pb.Subscription{
+		Id:         subscription.ID,
+		Url:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  timestamppb.New(subscription.CreatedAt),
+		UpdatedAt:  timestamppb.New(subscription.UpdatedAt),
+	}
+}
+
+func (w *webhookHandler) UpdateSubscription(incomingCtx context.Context, req *pb.UpdateSubscriptionRequest) (*emptypb.Empty, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, w.ctx), w.cfg.Timeout.Duration)
+	defer cancel()
+
+	updateReq := models.UpdateWebhookSubscriptionReq{
+		URL:    req.Url,
+		Secret: req.Secret,
+	}
+	if req.EventTypes != nil {
+		updateReq.EventTypes = &req.EventTypes.Types
+	}
+
+	err := w.svc.UpdateSubscription(ctx, req.Id, updateReq)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (w *webhookHandler) DeleteSubscription(incomingCtx context.Context, req *pb.DeleteSubscriptionRequest) (*emptypb.Empty, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, w.ctx), w.cfg.Timeout.Duration)
+	defer cancel()
+
+	err := w.svc.DeleteSubscription(ctx, req.Id)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (w *webhookHandler) GetDeadLetters(incomingCtx context.Context, _ *emptypb.Empty) (*pb.GetDeadLettersResponse, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, w.ctx), w.cfg.Timeout.Duration)
+	defer cancel()
+
+	resp, err := w.svc.GetDeadLetters(ctx)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+
+	getResp := &pb.GetDeadLettersResponse{
+		DeadLetters: []*pb.DeadLetter{},
+	}
+	for _, delivery := range resp {
+		getResp.DeadLetters = append(getResp.DeadLetters, &pb.DeadLetter{
+			Id:             delivery.ID,
+			SubscriptionId: delivery.SubscriptionID,
+			EventId:        delivery.EventID,
+			EventType:      delivery.EventType,
+			Attempts:       int32(delivery.Attempts),
+			LastError:      delivery.LastError,
+			CreatedAt:      timestamppb.New(delivery.CreatedAt),
+		})
+	}
+	return getResp, nil
+}
+
+func (w *webhookHandler) ReplayDeadLetter(incomingCtx context.Context, req *pb.ReplayDeadLetterRequest) (*emptypb.Empty, error) {
+	ctx, cancel := context.WithTimeout(requestContext(incomingCtx, w.ctx), w.cfg.Timeout.Duration)
+	defer cancel()
+
+	err := w.svc.ReplayDeadLetter(ctx, req.Id)
+	if err != nil {
+		return nil, ToGRPC(err)
+	}
+	return &emptypb.Empty{}, nil
+}
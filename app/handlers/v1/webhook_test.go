@@ -0,0 +1,205 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// TestWebhookJWTMethodPolicies_Ok checks that every webhook method requires the admin claim
+func TestWebhookJWTMethodPolicies_Ok(t *testing.T) {
+	// Arrange
+	handler := NewWebhookHandler(context.Background(), config.Config{}, mocks.NewWebhookService(t))
+
+	// Act
+	policies := handler.JWTMethodPolicies()
+
+	// Assert
+	assert.Len(t, policies, 7)
+	for _, policy := range policies {
+		assert.Equal(t, []string{"admin"}, policy.RequiredClaims)
+	}
+}
+
+// TestCreateSubscription_Ok checks that the CreateSubscription handler returns the expected response when everything goes as expected
+func TestCreateSubscription_Ok(t *testing.T) {
+	// Arrange
+	webhookService := mocks.NewWebhookService(t)
+	expectedReq := models.CreateWebhookSubscriptionReq{
+		URL:        "https://test.com/hook",
+		EventTypes: []string{"user.created"},
+	}
+	expectedResp := models.CreateWebhookSubscriptionResp{
+		ID:     "test-id",
+		Secret: "test-secret",
+	}
+	webhookService.On(testutils.FunctionName(t, ports.WebhookService.CreateSubscription), mock.Anything, expectedReq).Return(expectedResp, nil).Once()
+
+	handler := NewWebhookHandler(context.Background(), config.Config{}, webhookService)
+
+	// Act
+	resp, err := handler.CreateSubscription(context.Background(), &pb.CreateSubscriptionRequest{
+		Url:        expectedReq.URL,
+		EventTypes: expectedReq.EventTypes,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResp.ID, resp.Id)
+	assert.Equal(t, expectedResp.Secret, resp.Secret)
+}
+
+// TestCreateSubscription_ServiceError checks that the CreateSubscription handler returns a gRPC error when the service fails
+func TestCreateSubscription_ServiceError(t *testing.T) {
+	// Arrange
+	webhookService := mocks.NewWebhookService(t)
+	expectedError := "url cannot be empty"
+	webhookService.On(testutils.FunctionName(t, ports.WebhookService.CreateSubscription), mock.Anything, mock.Anything).Return(models.CreateWebhookSubscriptionResp{}, wrappers.NewValidationErr(errors.New(expectedError))).Once()
+
+	handler := NewWebhookHandler(context.Background(), config.Config{}, webhookService)
+
+	// Act
+	resp, err := handler.CreateSubscription(context.Background(), &pb.CreateSubscriptionRequest{})
+
+	// Assert
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+}
+
+// TestGetSubscriptions_Ok checks that the GetSubscriptions handler returns the expected response when everything goes as expected
+func TestGetSubscriptions_Ok(t *testing.T) {
+	// Arrange
+	webhookService := mocks.NewWebhookService(t)
+	expectedResp := []models.WebhookSubscriptionResp{
+		{ID: "test-id", URL: "https://test.com/hook", EventTypes: []string{"user.created"}},
+	}
+	webhookService.On(testutils.FunctionName(t, ports.WebhookService.GetSubscriptions), mock.Anything).Return(expectedResp, nil).Once()
+
+	handler := NewWebhookHandler(context.Background(), config.Config{}, webhookService)
+
+	// Act
+	resp, err := handler.GetSubscriptions(context.Background(), &emptypb.Empty{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, resp.Subscriptions, 1)
+	assert.Equal(t, "test-id", resp.Subscriptions[0].Id)
+	assert.Equal(t, []string{"user.created"}, resp.Subscriptions[0].EventTypes)
+}
+
+// TestGetSubscriptionByID_NotFound checks that the GetSubscriptionByID handler returns a not found gRPC error when the subscription does not exist
+func TestGetSubscriptionByID_NotFound(t *testing.T) {
+	// Arrange
+	webhookService := mocks.NewWebhookService(t)
+	webhookService.On(testutils.FunctionName(t, ports.WebhookService.GetSubscriptionByID), mock.Anything, "test-id").Return(models.WebhookSubscriptionResp{}, wrappers.NewNonExistentErr(errors.New("ID test-id not found"))).Once()
+
+	handler := NewWebhookHandler(context.Background(), config.Config{}, webhookService)
+
+	// Act
+	resp, err := handler.GetSubscriptionByID(context.Background(), &pb.GetSubscriptionByIDRequest{Id: "test-id"})
+
+	// Assert
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+}
+
+// TestUpdateSubscription_Ok checks that the UpdateSubscription handler only maps the provided fields
+func TestUpdateSubscription_Ok(t *testing.T) {
+	// Arrange
+	webhookService := mocks.NewWebhookService(t)
+	url := "https://test.com/hook"
+	eventTypes := []string{"user.deleted"}
+	expectedReq := models.UpdateWebhookSubscriptionReq{
+		URL:        &url,
+		EventTypes: &eventTypes,
+	}
+	webhookService.On(testutils.FunctionName(t, ports.WebhookService.UpdateSubscription), mock.Anything, "test-id", expectedReq).Return(nil).Once()
+
+	handler := NewWebhookHandler(context.Background(), config.Config{}, webhookService)
+
+	// Act
+	resp, err := handler.UpdateSubscription(context.Background(), &pb.UpdateSubscriptionRequest{
+		Id:         "test-id",
+		Url:        &url,
+		EventTypes: &pb.EventTypes{Types: eventTypes},
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &emptypb.Empty{}, resp)
+}
+
+// TestDeleteSubscription_Ok checks that the DeleteSubscription handler returns an empty response when everything goes as expected
+func TestDeleteSubscription_Ok(t *testing.T) {
+	// Arrange
+	webhookService := mocks.NewWebhookService(t)
+	webhookService.On(testutils.FunctionName(t, ports.WebhookService.DeleteSubscription), mock.Anything, "test-id").Return(nil).Once()
+
+	handler := NewWebhookHandler(context.Background(), config.Config{}, webhookService)
+
+	// Act
+	resp, err := handler.DeleteSubscription(context.Background(), &pb.DeleteSubscriptionRequest{Id: "test-id"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &emptypb.Empty{}, resp)
+}
+
+// TestGetDeadLetters_Ok checks that the GetDeadLetters handler returns the expected response when everything goes as expected
+func TestGetDeadLetters_Ok(t *testing.T) {
+	// Arrange
+	webhookService := mocks.NewWebhookService(t)
+	expectedResp := []models.WebhookDeliveryResp{
+		{ID: "test-id", SubscriptionID: "test-subscription", EventType: "user.created", Attempts: 8, LastError: "send error"},
+	}
+	webhookService.On(testutils.FunctionName(t, ports.WebhookService.GetDeadLetters), mock.Anything).Return(expectedResp, nil).Once()
+
+	handler := NewWebhookHandler(context.Background(), config.Config{}, webhookService)
+
+	// Act
+	resp, err := handler.GetDeadLetters(context.Background(), &emptypb.Empty{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, resp.DeadLetters, 1)
+	assert.Equal(t, int32(8), resp.DeadLetters[0].Attempts)
+	assert.Equal(t, "send error", resp.DeadLetters[0].LastError)
+}
+
+// TestReplayDeadLetter_ServiceError checks that the ReplayDeadLetter handler returns a gRPC error when the service fails
+func TestReplayDeadLetter_ServiceError(t *testing.T) {
+	// Arrange
+	webhookService := mocks.NewWebhookService(t)
+	expectedError := "delivery test-id is not a dead letter"
+	webhookService.On(testutils.FunctionName(t, ports.WebhookService.ReplayDeadLetter), mock.Anything, "test-id").Return(wrappers.NewValidationErr(errors.New(expectedError))).Once()
+
+	handler := NewWebhookHandler(context.Background(), config.Config{}, webhookService)
+
+	// Act
+	resp, err := handler.ReplayDeadLetter(context.Background(), &pb.ReplayDeadLetterRequest{Id: "test-id"})
+
+	// Assert
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, expectedError, st.Message())
+}
@@ -20,7 +20,7 @@ func main() {
 		Environment string `long:"env" description:"Environment" choice:"local" choice:"prod" required:"true"`
 		HTTPPort    int    `long:"hport" description:"Running HTTP port" required:"true"`
 		GRPCPort    int    `long:"gport" description:"Running gRPC port" required:"true"`
-		Database    string `long:"db" description:"The database adapter to use" choice:"mongo" choice:"postgres" required:"true"`
+		Database    string `long:"db" description:"The database adapter to use" choice:"mongo" choice:"postgres" choice:"sqlite" choice:"memory" required:"true"`
 		DSN         string `long:"dsn" description:"DSN of the selected database" required:"true"`
 		JWTSecret   string `long:"jsecret" description:"Secret used to sign and validate JWT tokens" required:"true"`
 		NewRelicKey string `long:"nrkey" description:"New Relic Key" required:"false"`
@@ -58,7 +58,7 @@ func main() {
 	g.Go(a.RunHTTP(ctx, cancel, grpcServerReady))
 
 	if cfg.Async.Run {
-		async := async.New(cfg)
+		async := async.New(cfg, a.OutboxService(), a.WebhookService(), a.UserService())
 		g.Go(async.Run(ctx, cancel))
 	}
 
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/sergicanet9/go-hexagonal-api/app/api"
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+// main applies the pending migrations of the selected database and exits, so that they can be run before deploying the API
+// with MigrateAtStartup disabled
+func main() {
+	var opts struct {
+		Environment string `long:"env" description:"Environment" choice:"local" choice:"prod" required:"true"`
+		Database    string `long:"db" description:"The database adapter to migrate" choice:"mongo" choice:"postgres" choice:"sqlite" required:"true"`
+		DSN         string `long:"dsn" description:"DSN of the selected database" required:"true"`
+	}
+
+	args, err := flags.Parse(&opts)
+	if err != nil {
+		observability.Logger().Fatal(fmt.Errorf("provided flags not valid: %s, %w", args, err))
+	}
+
+	cfg, err := config.ReadConfig("", opts.Environment, 0, 0, opts.Database, opts.DSN, "", "", "config")
+	if err != nil {
+		observability.Logger().Fatal(fmt.Errorf("cannot parse config file for env %s: %w", opts.Environment, err))
+	}
+
+	if err = api.Migrate(context.Background(), cfg); err != nil {
+		observability.Logger().Fatal(err)
+	}
+	observability.Logger().Printf("%s database migrated", cfg.Database)
+}
@@ -12,6 +12,143 @@ type Async struct {
 	Interval utils.Duration
 }
 
+type Idempotency struct {
+	TTL            utils.Duration
+	ReservationTTL utils.Duration
+	InMemory       bool
+}
+
+type Import struct {
+	BatchSize int
+	Timeout   utils.Duration
+}
+
+type Export struct {
+	Timeout utils.Duration
+}
+
+type Outbox struct {
+	Publisher      string
+	WebhookURL     string
+	BatchSize      int
+	Interval       utils.Duration
+	Lease          utils.Duration
+	MaxAttempts    int
+	InitialBackoff utils.Duration
+	MaxBackoff     utils.Duration
+	Retention      utils.Duration
+}
+
+type Webhooks struct {
+	BatchSize      int
+	Concurrency    int
+	Interval       utils.Duration
+	Lease          utils.Duration
+	MaxAttempts    int
+	InitialBackoff utils.Duration
+	MaxBackoff     utils.Duration
+}
+
+type UserChanges struct {
+	Interval  utils.Duration
+	Retention utils.Duration
+}
+
+type Invitations struct {
+	TTL        utils.Duration
+	Notifier   string
+	WebhookURL string
+}
+
+type EmailChanges struct {
+	TTL        utils.Duration
+	Notifier   string
+	WebhookURL string
+}
+
+type LocalStorage struct {
+	Dir     string
+	BaseURL string
+}
+
+type S3Storage struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PublicURL       string
+}
+
+type Avatars struct {
+	Storage        string
+	MaxSize        int
+	ThumbnailSizes []int
+	Local          LocalStorage
+	S3             S3Storage
+}
+
+type NotificationPreferences struct {
+	Email     bool
+	Push      bool
+	Marketing bool
+}
+
+type Preferences struct {
+	Language      string
+	Timezone      string
+	Notifications NotificationPreferences
+}
+
+type LRUCache struct {
+	Size int
+}
+
+type RedisCache struct {
+	Addr     string
+	Password string
+	DB       int
+	Prefix   string
+}
+
+type UserCache struct {
+	Store           string
+	TTL             utils.Duration
+	EmailTTL        utils.Duration
+	InvalidationTTL utils.Duration
+	LRU             LRUCache
+	Redis           RedisCache
+}
+
+type ConnectRetry struct {
+	MaxAttempts    int
+	InitialBackoff utils.Duration
+	MaxBackoff     utils.Duration
+}
+
+type PostgresPool struct {
+	MaxOpenConns     int
+	MaxIdleConns     int
+	ConnMaxLifetime  utils.Duration
+	ConnMaxIdleTime  utils.Duration
+	StatementTimeout utils.Duration
+}
+
+type MongoClient struct {
+	MaxPoolSize      uint64
+	MinPoolSize      uint64
+	MaxConnIdleTime  utils.Duration
+	OperationTimeout utils.Duration
+	ReadPreference   string
+	WriteConcern     string
+}
+
+type PostgresReplicas struct {
+	DSNs          []string
+	CheckInterval utils.Duration
+	MaxLag        utils.Duration
+}
+
 type Config struct {
 	// set in flags
 	Version     string
@@ -28,9 +165,26 @@ type Config struct {
 }
 
 type config struct {
+	MigrateAtStartup      bool
 	PostgresMigrationsDir string
+	SQLiteMigrationsDir   string
+	ConnectRetry          ConnectRetry
+	PostgresPool          PostgresPool
+	PostgresReplicas      PostgresReplicas
+	MongoClient           MongoClient
 	Timeout               utils.Duration
 	Async                 Async
+	Idempotency           Idempotency
+	Import                Import
+	Export                Export
+	Outbox                Outbox
+	Webhooks              Webhooks
+	UserChanges           UserChanges
+	Invitations           Invitations
+	EmailChanges          EmailChanges
+	Avatars               Avatars
+	Preferences           Preferences
+	UserCache             UserCache
 }
 
 // ReadConfig from the project´s JSON config files.
@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// EntityNameAuditEvent contains the name of the entity
+const EntityNameAuditEvent = "audit_events"
+
+// AuditEvent struct, recorded for every mutation of a user.
+// Before and After hold the state of the target before and after the mutation, with secrets redacted.
+type AuditEvent struct {
+	ID        string                 `bson:"_id,omitempty"`
+	ActorID   string                 `bson:"actor_id"`
+	Action    string                 `bson:"action"`
+	TargetID  string                 `bson:"target_id"`
+	Before    map[string]interface{} `bson:"before"`
+	After     map[string]interface{} `bson:"after"`
+	RequestID string                 `bson:"request_id"`
+	Timestamp time.Time              `bson:"timestamp"`
+}
@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// EntityNameEmailChange contains the name of the entity
+const EntityNameEmailChange = "email_changes"
+
+// EmailChange struct of a pending change of the email of a user, which only takes effect once confirmed from the new email.
+// Only the last change requested for a user is pending, and only until ExpiresAt.
+type EmailChange struct {
+	ID            string    `bson:"_id,omitempty"`
+	UserID        string    `bson:"user_id"`
+	PreviousEmail string    `bson:"previous_email"`
+	Email         string    `bson:"email"`
+	Nonce         string    `bson:"nonce"`
+	ExpiresAt     time.Time `bson:"expires_at"`
+	CreatedAt     time.Time `bson:"created_at"`
+}
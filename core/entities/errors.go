@@ -0,0 +1,71 @@
+package entities
+
+import "strconv"
+
+// AlreadyExistsErr is an error of type alreadyExistsError, returned when an entity conflicts with a stored one
+var AlreadyExistsErr error = alreadyExistsError{msg: "resource already exists"}
+
+// alreadyExistsError is an implementation of error interface
+type alreadyExistsError struct {
+	msg string
+}
+
+// NewAlreadyExistsErr wraps the given error in an alreadyExistsError
+func NewAlreadyExistsErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	return alreadyExistsError{
+		msg: err.Error(),
+	}
+}
+
+// Error returns the error message
+func (e alreadyExistsError) Error() string {
+	return e.msg
+}
+
+// Is returns true if the target error is an alreadyExistsError
+func (e alreadyExistsError) Is(tgt error) bool {
+	_, ok := tgt.(alreadyExistsError)
+	return ok
+}
+
+// NewEmailInUseErr returns the conflict error of a normalized email that already belongs to another user
+func NewEmailInUseErr(emailNormalized string) error {
+	return alreadyExistsError{msg: "email " + emailNormalized + " is already in use"}
+}
+
+// ConflictErr is an error of type conflictError, returned when a change conflicts with a concurrent one, so that it can be retried
+var ConflictErr error = conflictError{msg: "resource changed concurrently"}
+
+// conflictError is an implementation of error interface
+type conflictError struct {
+	msg string
+}
+
+// NewConflictErr wraps the given error in a conflictError
+func NewConflictErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	return conflictError{
+		msg: err.Error(),
+	}
+}
+
+// Error returns the error message
+func (e conflictError) Error() string {
+	return e.msg
+}
+
+// Is returns true if the target error is a conflictError
+func (e conflictError) Is(tgt error) bool {
+	_, ok := tgt.(conflictError)
+	return ok
+}
+
+// NewOutdatedPreferencesErr returns the conflict error of preferences saved on top of an outdated version
+func NewOutdatedPreferencesErr(version int) error {
+	return conflictError{msg: "version " + strconv.Itoa(version) + " is outdated, the preferences were updated concurrently"}
+}
@@ -0,0 +1,66 @@
+package entities
+
+import "time"
+
+// EntityNameEvent contains the name of the entity
+const EntityNameEvent = "outbox_events"
+
+// EventType type of a domain event
+type EventType string
+
+const (
+	EventTypeUserCreated  EventType = "user.created"
+	EventTypeUserUpdated  EventType = "user.updated"
+	EventTypeUserDeleted  EventType = "user.deleted"
+	EventTypeUserLoggedIn EventType = "user.logged_in"
+)
+
+// IsValid reports whether the event type is one of the known types
+func (t EventType) IsValid() bool {
+	switch t {
+	case EventTypeUserCreated, EventTypeUserUpdated, EventTypeUserDeleted, EventTypeUserLoggedIn:
+		return true
+	}
+	return false
+}
+
+// Event struct of a domain event, stored in the outbox in the same transaction as the change that raised it
+// and published afterwards. PublishedAt is nil until the event gets published, and a failed event is attempted again
+// from NextAttemptAt on. DeadAt is set when the event is given up after the maximum attempts, forming the dead-letter store.
+type Event struct {
+	ID            string                 `bson:"_id,omitempty"`
+	Type          EventType              `bson:"type"`
+	AggregateID   string                 `bson:"aggregate_id"`
+	Payload       map[string]interface{} `bson:"payload"`
+	OccurredAt    time.Time              `bson:"occurred_at"`
+	PublishedAt   *time.Time             `bson:"published_at"`
+	Attempts      int                    `bson:"attempts"`
+	NextAttemptAt time.Time              `bson:"next_attempt_at"`
+	LastError     string                 `bson:"last_error"`
+	DeadAt        *time.Time             `bson:"dead_at"`
+}
+
+// NewUserEvent creates a domain event of the user with the given ID, the password hash is never included in the payload.
+// The payload only holds the ID when user is nil.
+func NewUserEvent(eventType EventType, ID string, user *User) Event {
+	payload := map[string]interface{}{
+		"id": ID,
+	}
+	if user != nil {
+		payload["name"] = user.Name
+		payload["surnames"] = user.Surnames
+		payload["email"] = user.Email
+		payload["claim_ids"] = user.ClaimIDs
+		payload["created_at"] = user.CreatedAt
+		payload["updated_at"] = user.UpdatedAt
+	}
+
+	now := time.Now().UTC()
+	return Event{
+		Type:          eventType,
+		AggregateID:   ID,
+		Payload:       payload,
+		OccurredAt:    now,
+		NextAttemptAt: now,
+	}
+}
@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+)
+
+// EntityNameIdempotencyRecord contains the name of the entity
+const EntityNameIdempotencyRecord = "idempotency_keys"
+
+// IdempotencyRecord struct, Response is nil while the request that reserved the key is in progress.
+// Token is a random value of every reservation, so that only the request that reserved the key can complete or release it.
+type IdempotencyRecord struct {
+	Key         string    `bson:"_id"`
+	Token       string    `bson:"token"`
+	RequestHash string    `bson:"request_hash"`
+	Response    []byte    `bson:"response"`
+	CreatedAt   time.Time `bson:"created_at"`
+	ExpiresAt   time.Time `bson:"expires_at"`
+}
@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// EntityNameInvitation contains the name of the entity
+const EntityNameInvitation = "invitations"
+
+// Invitation struct of a pending user, who has no password until the invitation is accepted.
+// Only the last invite token sent is valid, as it is the only one carrying Nonce, and only until ExpiresAt.
+type Invitation struct {
+	ID        string    `bson:"_id,omitempty"`
+	UserID    string    `bson:"user_id"`
+	Email     string    `bson:"email"`
+	Nonce     string    `bson:"nonce"`
+	ExpiresAt time.Time `bson:"expires_at"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// EntityNamePreferences contains the name of the entity
+const EntityNamePreferences = "preferences"
+
+// Preferences struct of the preferences overridden by a user, nil when the default of the configuration applies.
+// Version starts at 1 and is increased on every update, so that concurrent updates can be detected.
+type Preferences struct {
+	UserID        string                  `bson:"_id"`
+	Version       int                     `bson:"version"`
+	Language      *string                 `bson:"language,omitempty"`
+	Timezone      *string                 `bson:"timezone,omitempty"`
+	Notifications NotificationPreferences `bson:"notifications"`
+	UpdatedAt     time.Time               `bson:"updated_at"`
+}
+
+// NotificationPreferences struct of the notification toggles overridden by a user
+type NotificationPreferences struct {
+	Email     *bool `bson:"email,omitempty"`
+	Push      *bool `bson:"push,omitempty"`
+	Marketing *bool `bson:"marketing,omitempty"`
+}
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// EntityNameTombstone contains the name of the entity
+const EntityNameTombstone = "tombstones"
+
+// Tombstone struct, recorded when the personal data of a user is erased.
+// It holds no personal data itself, only the ID of the anonymized user and the stores that were erased.
+type Tombstone struct {
+	ID       string    `bson:"_id,omitempty"`
+	UserID   string    `bson:"user_id"`
+	ErasedBy string    `bson:"erased_by"`
+	Stores   []string  `bson:"stores"`
+	ErasedAt time.Time `bson:"erased_at"`
+}
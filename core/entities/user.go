@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"strings"
 	"time"
 )
 
@@ -30,14 +31,22 @@ func GetUserClaims() map[int]string {
 	return claims
 }
 
-// User struct
+// User struct, Email holds the display value while EmailNormalized is the one used to look up and to enforce uniqueness.
+// AvatarURL is empty when the user has no avatar.
 type User struct {
-	ID           string    `bson:"_id,omitempty"`
-	Name         string    `bson:"name"`
-	Surnames     string    `bson:"surnames"`
-	Email        string    `bson:"email"`
-	PasswordHash string    `bson:"password_hash"`
-	ClaimIDs     []int32   `bson:"claim_ids"`
-	CreatedAt    time.Time `bson:"created_at"`
-	UpdatedAt    time.Time `bson:"updated_at"`
+	ID              string    `bson:"_id,omitempty"`
+	Name            string    `bson:"name"`
+	Surnames        string    `bson:"surnames"`
+	Email           string    `bson:"email"`
+	EmailNormalized string    `bson:"email_normalized"`
+	PasswordHash    string    `bson:"password_hash"`
+	ClaimIDs        []int32   `bson:"claim_ids"`
+	AvatarURL       string    `bson:"avatar_url"`
+	CreatedAt       time.Time `bson:"created_at"`
+	UpdatedAt       time.Time `bson:"updated_at"`
+}
+
+// NormalizeEmail returns the normalized form of an email, so that emails differing only in case belong to the same user
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
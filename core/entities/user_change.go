@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// UserChangeType type of change of a user
+type UserChangeType string
+
+const (
+	UserChangeTypeCreated UserChangeType = "created"
+	UserChangeTypeUpdated UserChangeType = "updated"
+	UserChangeTypeDeleted UserChangeType = "deleted"
+)
+
+// UserChange change of a user, as notified by the database.
+// The resume token identifies the change, so that watching can be resumed right after it.
+// The user holds the state of the user, which is the one left by the change in PostgreSQL and SQLite and the current one in MongoDB,
+// and it is nil when the user no longer exists.
+type UserChange struct {
+	ResumeToken string
+	Type        UserChangeType
+	UserID      string
+	User        *User
+	OccurredAt  time.Time
+}
@@ -0,0 +1,61 @@
+package entities
+
+import "time"
+
+const (
+	// EntityNameWebhookSubscription contains the name of the entity
+	EntityNameWebhookSubscription = "webhook_subscriptions"
+	// EntityNameWebhookDelivery contains the name of the entity
+	EntityNameWebhookDelivery = "webhook_deliveries"
+)
+
+// WebhookSubscription struct, the events whose type is in EventTypes are delivered to URL signed with Secret.
+// Every event is delivered when EventTypes is empty.
+type WebhookSubscription struct {
+	ID         string    `bson:"_id,omitempty"`
+	URL        string    `bson:"url"`
+	EventTypes []string  `bson:"event_types"`
+	Secret     string    `bson:"secret"`
+	CreatedAt  time.Time `bson:"created_at"`
+	UpdatedAt  time.Time `bson:"updated_at"`
+}
+
+// Matches reports whether the events of the given type have to be delivered to the subscription
+func (s WebhookSubscription) Matches(eventType EventType) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range s.EventTypes {
+		if t == string(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus status of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusDead      WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery struct of an event to deliver to a subscription, Body is sent as is on every attempt
+// unless the data of the user the event is about, AggregateID, gets erased.
+// Pending deliveries are attempted from NextAttemptAt on, and dead deliveries form the dead-letter store.
+type WebhookDelivery struct {
+	ID             string                `bson:"_id,omitempty"`
+	SubscriptionID string                `bson:"subscription_id"`
+	EventID        string                `bson:"event_id"`
+	EventType      EventType             `bson:"event_type"`
+	AggregateID    string                `bson:"aggregate_id"`
+	Body           []byte                `bson:"body"`
+	Status         WebhookDeliveryStatus `bson:"status"`
+	Attempts       int                   `bson:"attempts"`
+	NextAttemptAt  time.Time             `bson:"next_attempt_at"`
+	LastError      string                `bson:"last_error"`
+	CreatedAt      time.Time             `bson:"created_at"`
+	DeliveredAt    *time.Time            `bson:"delivered_at"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// AuditAction action of an audit event
+type AuditAction string
+
+const (
+	AuditActionCreate       AuditAction = "create"
+	AuditActionUpdate       AuditAction = "update"
+	AuditActionUpdateClaims AuditAction = "update_claims"
+	AuditActionDelete       AuditAction = "delete"
+	AuditActionImport       AuditAction = "import"
+	AuditActionInvite       AuditAction = "invite"
+	AuditActionAcceptInvite AuditAction = "accept_invite"
+	AuditActionRevokeInvite AuditAction = "revoke_invite"
+	AuditActionUploadAvatar AuditAction = "upload_avatar"
+	AuditActionDeleteAvatar AuditAction = "delete_avatar"
+	AuditActionErase        AuditAction = "erase"
+)
+
+// AuditMetadata metadata of the request that performs a mutation, ActorID is empty for unauthenticated requests
+type AuditMetadata struct {
+	ActorID   string
+	RequestID string
+}
+
+type auditMetadataKey struct{}
+
+// WithAuditMetadata returns a copy of ctx carrying the given audit metadata
+func WithAuditMetadata(ctx context.Context, metadata AuditMetadata) context.Context {
+	return context.WithValue(ctx, auditMetadataKey{}, metadata)
+}
+
+// AuditMetadataFromContext returns the audit metadata carried by ctx, if any
+func AuditMetadataFromContext(ctx context.Context) AuditMetadata {
+	metadata, _ := ctx.Value(auditMetadataKey{}).(AuditMetadata)
+	return metadata
+}
+
+// AuditFilter filter of the audit events to list, zero values are not applied
+type AuditFilter struct {
+	ActorID  string
+	Action   AuditAction
+	TargetID string
+	From     time.Time
+	To       time.Time
+}
+
+// ListAuditEventsReq list audit events request struct, a default page size is applied when PageSize is zero
+type ListAuditEventsReq struct {
+	Filter    AuditFilter
+	PageSize  int
+	PageToken string
+}
+
+// ListAuditEventsResp list audit events response struct, NextPageToken is empty on the last page
+type ListAuditEventsResp struct {
+	Events        []AuditEventResp
+	NextPageToken string
+}
+
+// AuditEventResp audit event response struct
+type AuditEventResp struct {
+	ID        string
+	ActorID   string
+	Action    AuditAction
+	TargetID  string
+	Before    map[string]interface{}
+	After     map[string]interface{}
+	RequestID string
+	Timestamp time.Time
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// AvatarContentTypes content types accepted for the avatars
+var AvatarContentTypes = []string{"image/jpeg", "image/png"}
+
+// UploadAvatarReq upload avatar request struct
+type UploadAvatarReq struct {
+	ContentType string
+	Content     []byte
+}
+
+// Validate checks that a given UploadAvatarReq is valid
+func (req UploadAvatarReq) Validate() error {
+	var msgs []string
+
+	if !isAvatarContentType(req.ContentType) {
+		msgs = append(msgs, fmt.Sprintf("content type %s is not valid, must be one of %s", req.ContentType, strings.Join(AvatarContentTypes, ", ")))
+	}
+	if len(req.Content) == 0 {
+		msgs = append(msgs, "content cannot be empty")
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf("%s", strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+func isAvatarContentType(contentType string) bool {
+	for _, t := range AvatarContentTypes {
+		if contentType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// UploadAvatarResp upload avatar response struct
+type UploadAvatarResp struct {
+	AvatarURL  string
+	Thumbnails []AvatarThumbnail
+}
+
+// AvatarThumbnail thumbnail of an avatar, resized to fit a square of Size pixels
+type AvatarThumbnail struct {
+	Size int
+	URL  string
+}
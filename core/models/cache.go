@@ -0,0 +1,7 @@
+package models
+
+// CacheStats counters of the lookups served by a cache and of the ones that missed it
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// ConfirmEmailChangeReq confirm email change request struct
+type ConfirmEmailChangeReq struct {
+	Token string
+}
+
+// Validate checks that a given ConfirmEmailChangeReq is valid
+func (req ConfirmEmailChangeReq) Validate() error {
+	if req.Token == "" {
+		return wrappers.NewValidationErr(fmt.Errorf("token cannot be empty"))
+	}
+	return nil
+}
+
+// ConfirmEmailChangeResp confirm email change response struct
+type ConfirmEmailChangeResp struct {
+	UserID        string
+	PreviousEmail string
+	Email         string
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// InviteUserReq invite user request struct
+type InviteUserReq struct {
+	Name     string
+	Surnames string
+	Email    string
+	ClaimIDs []int32
+}
+
+// Validate checks that a given InviteUserReq is valid
+func (req InviteUserReq) Validate() error {
+	if req.Email == "" {
+		return wrappers.NewValidationErr(fmt.Errorf("email cannot be empty"))
+	}
+	if err := validateEmail(req.Email); err != nil {
+		return wrappers.NewValidationErr(err)
+	}
+	return nil
+}
+
+// InviteUserResp invite user response struct
+type InviteUserResp struct {
+	ID     string
+	UserID string
+}
+
+// AcceptInviteReq accept invite request struct
+type AcceptInviteReq struct {
+	Token    string
+	Password string
+}
+
+// Validate checks that a given AcceptInviteReq is valid
+func (req AcceptInviteReq) Validate() error {
+	var msgs []string
+
+	if req.Token == "" {
+		msgs = append(msgs, "token cannot be empty")
+	}
+	if req.Password == "" {
+		msgs = append(msgs, "password cannot be empty")
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf("%s", strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// InvitationResp invitation response struct, the invite token is never returned
+type InvitationResp struct {
+	ID        string
+	UserID    string
+	Email     string
+	Expired   bool
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
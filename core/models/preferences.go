@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	// the time zones are embedded so that they are validated the same way on hosts without a time zone database
+	_ "time/tzdata"
+
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// PreferenceNames names of the preferences that can be reset to their defaults
+var PreferenceNames = []string{"language", "timezone", "notifications.email", "notifications.push", "notifications.marketing"}
+
+// languageTag matches the BCP 47 language tags, such as en, es or pt-BR
+var languageTag = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// UpdatePreferencesReq update preferences request struct.
+// Only the non-nil preferences are overridden, and the ones in Reset go back to their defaults.
+// When Version is set, the update is rejected unless it matches the current version of the preferences.
+type UpdatePreferencesReq struct {
+	Version       *int
+	Language      *string
+	Timezone      *string
+	Notifications UpdateNotificationPreferencesReq
+	Reset         []string
+}
+
+// UpdateNotificationPreferencesReq update notification preferences request struct
+type UpdateNotificationPreferencesReq struct {
+	Email     *bool
+	Push      *bool
+	Marketing *bool
+}
+
+// Validate checks that a given UpdatePreferencesReq is valid
+func (req UpdatePreferencesReq) Validate() error {
+	var msgs []string
+
+	if req.Version != nil && *req.Version < 0 {
+		msgs = append(msgs, "version cannot be negative")
+	}
+	if req.Language != nil && !languageTag.MatchString(*req.Language) {
+		msgs = append(msgs, fmt.Sprintf("language %s is not valid", *req.Language))
+	}
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "" || *req.Timezone == "Local" {
+			msgs = append(msgs, fmt.Sprintf("timezone %s is not valid", *req.Timezone))
+		}
+	}
+
+	set := map[string]bool{
+		"language":                req.Language != nil,
+		"timezone":                req.Timezone != nil,
+		"notifications.email":     req.Notifications.Email != nil,
+		"notifications.push":      req.Notifications.Push != nil,
+		"notifications.marketing": req.Notifications.Marketing != nil,
+	}
+	for _, name := range req.Reset {
+		isSet, ok := set[name]
+		if !ok {
+			msgs = append(msgs, fmt.Sprintf("preference %s is not valid, must be one of %s", name, strings.Join(PreferenceNames, ", ")))
+		} else if isSet {
+			msgs = append(msgs, fmt.Sprintf("preference %s cannot be both updated and reset", name))
+		}
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf("%s", strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// PreferencesResp preferences response struct, holding the defaults merged with the overrides of the user.
+// Version is 0 while the user has not updated any preference.
+type PreferencesResp struct {
+	Version       int
+	Language      string
+	Timezone      string
+	Notifications NotificationPreferencesResp
+	UpdatedAt     time.Time
+}
+
+// NotificationPreferencesResp notification preferences response struct
+type NotificationPreferencesResp struct {
+	Email     bool
+	Push      bool
+	Marketing bool
+}
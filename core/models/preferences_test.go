@@ -0,0 +1,50 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateUpdatePreferencesReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateUpdatePreferencesReq_Ok(t *testing.T) {
+	// Arrange
+	language, timezone, version := "pt-BR", "America/Sao_Paulo", 2
+	req := UpdatePreferencesReq{
+		Version:  &version,
+		Language: &language,
+		Timezone: &timezone,
+		Reset:    []string{"notifications.email"},
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateUpdatePreferencesReq_InvalidRequest checks that Validate returns an error when the received request is not valid
+func TestValidateUpdatePreferencesReq_InvalidRequest(t *testing.T) {
+	// Arrange
+	language, timezone, version, push := "english", "Local", -1, true
+	req := UpdatePreferencesReq{
+		Version:       &version,
+		Language:      &language,
+		Timezone:      &timezone,
+		Notifications: UpdateNotificationPreferencesReq{Push: &push},
+		Reset:         []string{"theme", "notifications.push"},
+	}
+	expectedError := "version cannot be negative | language english is not valid | timezone Local is not valid | " +
+		"preference theme is not valid, must be one of language, timezone, notifications.email, notifications.push, notifications.marketing | " +
+		"preference notifications.push cannot be both updated and reset"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
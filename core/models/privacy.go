@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// UserDataArchive user data archive struct, Content is a zip file with a JSON document per store
+type UserDataArchive struct {
+	Content []byte
+}
+
+// EraseUserResp erase user response struct
+type EraseUserResp struct {
+	TombstoneID string
+	Stores      []string
+	ErasedAt    time.Time
+}
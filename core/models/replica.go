@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// ReplicaStatus status of a read replica as of its last health check
+type ReplicaStatus struct {
+	Name    string
+	Healthy bool
+	Lag     time.Duration
+	Error   string
+}
@@ -1,7 +1,10 @@
 package models
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net/mail"
 	"strings"
 	"time"
 
@@ -53,6 +56,8 @@ func (req CreateUserReq) Validate() error {
 
 	if req.Email == "" {
 		msgs = append(msgs, "email cannot be empty")
+	} else if err := validateEmail(req.Email); err != nil {
+		msgs = append(msgs, err.Error())
 	}
 	if req.Password == "" {
 		msgs = append(msgs, "password cannot be empty")
@@ -72,7 +77,101 @@ type CreateUserResp struct {
 
 // CreateManyUserResp create many user response struct
 type CreateManyUserResp struct {
-	IDs []string
+	IDs     []string
+	Results []BulkItemResult
+}
+
+// UpdateManyUserReq update many user request struct
+type UpdateManyUserReq struct {
+	ID string
+	UpdateUserReq
+}
+
+// BulkUserResp bulk user operation response struct
+type BulkUserResp struct {
+	Results []BulkItemResult
+}
+
+// BulkItemResult outcome of a single item of a bulk operation, Err is nil when the item succeeded
+type BulkItemResult struct {
+	Index int
+	ID    string
+	Err   error
+}
+
+// ImportFormat format of the rows of a user import
+type ImportFormat string
+
+const (
+	ImportFormatCSV    ImportFormat = "csv"
+	ImportFormatNDJSON ImportFormat = "ndjson"
+)
+
+// ImportUsersReq import users request struct, Source is read row by row until EOF.
+// Apply, when set, applies every insertion of the import, either a batch or a single row, so that other changes can be applied along with it
+type ImportUsersReq struct {
+	Source io.Reader
+	Format ImportFormat
+	DryRun bool
+	Apply  func(ctx context.Context, insert ImportInsertFunc) error
+}
+
+// ImportInsertFunc inserts imported users, returning the index of their rows along with their IDs
+type ImportInsertFunc func(ctx context.Context) ([]BulkItemResult, error)
+
+// ImportUsersResp import users response struct, Errors holds one item per rejected row
+type ImportUsersResp struct {
+	Total    int
+	Imported int
+	DryRun   bool
+	Errors   []BulkItemResult
+}
+
+// ExportFormat format of the file of a user export
+type ExportFormat string
+
+const (
+	ExportFormatCSV     ExportFormat = "csv"
+	ExportFormatNDJSON  ExportFormat = "ndjson"
+	ExportFormatParquet ExportFormat = "parquet"
+)
+
+// ExportUsersReq export users request struct, all the exportable columns are included when Columns is empty
+type ExportUsersReq struct {
+	Format  ExportFormat
+	Columns []string
+	Filter  UserFilter
+}
+
+// UserFilter filter of the users, zero values are not applied
+type UserFilter struct {
+	Name            string
+	Surnames        string
+	EmailNormalized string
+	CreatedFrom     time.Time
+	CreatedTo       time.Time
+	ClaimID         *int32
+}
+
+// SearchUsersReq search users request struct, a default page size is applied when PageSize is zero
+type SearchUsersReq struct {
+	Query     string
+	PageSize  int
+	PageToken string
+}
+
+// Validate checks that a given SearchUsersReq is valid
+func (req SearchUsersReq) Validate() error {
+	if strings.TrimSpace(req.Query) == "" {
+		return wrappers.NewValidationErr(fmt.Errorf("query cannot be empty"))
+	}
+	return nil
+}
+
+// SearchUsersResp search users response struct, the users are sorted by relevance and NextPageToken is empty on the last page
+type SearchUsersResp struct {
+	Users         []GetUserResp
+	NextPageToken string
 }
 
 // UpdateUserReq update user request struct
@@ -85,14 +184,53 @@ type UpdateUserReq struct {
 	ClaimIDs    *[]int32
 }
 
+// Validate checks that a given UpdateUserReq is valid
+func (req UpdateUserReq) Validate() error {
+	var msgs []string
+
+	if req.Email != nil {
+		if *req.Email == "" {
+			msgs = append(msgs, "email cannot be empty")
+		} else if err := validateEmail(*req.Email); err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf("%s", strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+// validateEmail checks that the email is a bare RFC 5322 address, without display name nor angle brackets
+func validateEmail(email string) error {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return fmt.Errorf("email %s is not valid", email)
+	}
+	return nil
+}
+
 // GetUserResp user response struct
 type GetUserResp struct {
-	ID           string
-	Name         string
-	Surnames     string
-	Email        string
-	PasswordHash string
-	ClaimIDs     []int32
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID              string
+	Name            string
+	Surnames        string
+	Email           string
+	EmailNormalized string
+	PasswordHash    string
+	ClaimIDs        []int32
+	AvatarURL       string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// UserChangeResp user change response struct, the user is nil when it no longer exists
+type UserChangeResp struct {
+	ResumeToken string
+	Type        string
+	UserID      string
+	User        *GetUserResp
+	OccurredAt  time.Time
 }
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
@@ -37,6 +38,61 @@ func TestValidateCreateUserReq_InvalidRequest(t *testing.T) {
 	assert.Equal(t, expectedError, err.Error())
 }
 
+// TestValidateCreateUserReq_InvalidEmail checks that Validate returns an error when the received email is not a bare RFC 5322 address
+func TestValidateCreateUserReq_InvalidEmail(t *testing.T) {
+	for _, email := range []string{"test", "test@", "test@@test.com", "Test <test@test.com>", " test@test.com"} {
+		t.Run(email, func(t *testing.T) {
+			// Arrange
+			req := CreateUserReq{
+				Email:    email,
+				Password: "test",
+			}
+			expectedError := fmt.Sprintf("email %s is not valid", email)
+
+			// Act
+			err := req.Validate()
+
+			// Assert
+			assert.NotEmpty(t, err)
+			assert.IsType(t, wrappers.ValidationErr, err)
+			assert.Equal(t, expectedError, err.Error())
+		})
+	}
+}
+
+// TestValidateUpdateUserReq_Ok checks that Validate does not return an error when a valid request is received
+func TestValidateUpdateUserReq_Ok(t *testing.T) {
+	// Arrange
+	email := "Test.User+tag@Test.com"
+	req := UpdateUserReq{
+		Email: &email,
+	}
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestValidateUpdateUserReq_InvalidEmail checks that Validate returns an error when the received email is not valid
+func TestValidateUpdateUserReq_InvalidEmail(t *testing.T) {
+	// Arrange
+	email := "test.com"
+	req := UpdateUserReq{
+		Email: &email,
+	}
+	expectedError := "email test.com is not valid"
+
+	// Act
+	err := req.Validate()
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
 // TestValidateLoginUserReq_Ok checks that Validate does not return an error when a valid request is received
 func TestValidateLoginUserReq_Ok(t *testing.T) {
 	// Arrange
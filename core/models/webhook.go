@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// CreateWebhookSubscriptionReq create webhook subscription request struct, a random secret is generated when Secret is empty
+type CreateWebhookSubscriptionReq struct {
+	URL        string
+	EventTypes []string
+	Secret     string
+}
+
+// Validate checks that a given CreateWebhookSubscriptionReq is valid
+func (req CreateWebhookSubscriptionReq) Validate() error {
+	if msg := validateWebhookURL(req.URL); msg != "" {
+		return wrappers.NewValidationErr(fmt.Errorf("%s", msg))
+	}
+	return nil
+}
+
+// CreateWebhookSubscriptionResp create webhook subscription response struct, the secret is only returned on creation
+type CreateWebhookSubscriptionResp struct {
+	ID     string
+	Secret string
+}
+
+// UpdateWebhookSubscriptionReq update webhook subscription request struct
+type UpdateWebhookSubscriptionReq struct {
+	URL        *string
+	EventTypes *[]string
+	Secret     *string
+}
+
+// Validate checks that a given UpdateWebhookSubscriptionReq is valid
+func (req UpdateWebhookSubscriptionReq) Validate() error {
+	var msgs []string
+
+	if req.URL != nil {
+		if msg := validateWebhookURL(*req.URL); msg != "" {
+			msgs = append(msgs, msg)
+		}
+	}
+	if req.Secret != nil && *req.Secret == "" {
+		msgs = append(msgs, "secret cannot be empty")
+	}
+
+	if len(msgs) > 0 {
+		return wrappers.NewValidationErr(fmt.Errorf("%s", strings.Join(msgs, " | ")))
+	}
+
+	return nil
+}
+
+func validateWebhookURL(rawURL string) string {
+	if rawURL == "" {
+		return "url cannot be empty"
+	}
+
+	u, err := url.ParseRequestURI(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Sprintf("url %s is not a valid HTTP URL", rawURL)
+	}
+	return ""
+}
+
+// WebhookSubscriptionResp webhook subscription response struct, the secret is never returned
+type WebhookSubscriptionResp struct {
+	ID         string
+	URL        string
+	EventTypes []string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// WebhookDeliveryResp webhook delivery response struct
+type WebhookDeliveryResp struct {
+	ID             string
+	SubscriptionID string
+	EventID        string
+	EventType      string
+	Attempts       int
+	LastError      string
+	CreatedAt      time.Time
+}
+
+// WebhookMessage body of the webhook deliveries, the ID allows receivers to discard the events delivered more than once
+type WebhookMessage struct {
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	AggregateID string                 `json:"aggregate_id"`
+	Payload     map[string]interface{} `json:"payload"`
+	OccurredAt  time.Time              `json:"occurred_at"`
+}
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// AuditRepository interface
+type AuditRepository interface {
+	Create(ctx context.Context, event entities.AuditEvent) (string, error)
+	List(ctx context.Context, filter models.AuditFilter, offset, limit int) ([]entities.AuditEvent, error)
+	RedactTarget(ctx context.Context, targetID string) error
+}
+
+// AuditService interface, it is also a personal data store since audit events hold the values of the audited users
+type AuditService interface {
+	PersonalDataStore
+	List(ctx context.Context, req models.ListAuditEventsReq) (models.ListAuditEventsResp, error)
+}
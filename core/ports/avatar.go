@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// BlobStorage interface, implemented by the stores of binary objects such as the avatars
+type BlobStorage interface {
+	// Put stores the data under the given key, replacing any previous object, and returns the URL it can be downloaded from
+	Put(ctx context.Context, key, contentType string, data []byte) (string, error)
+	// Delete removes the object stored under the given key, it does not fail when there is no such object
+	Delete(ctx context.Context, key string) error
+}
+
+// AvatarService interface, it is also a personal data store since avatars are pictures of the users
+type AvatarService interface {
+	PersonalDataStore
+	Upload(ctx context.Context, userID string, req models.UploadAvatarReq) (models.UploadAvatarResp, error)
+	Delete(ctx context.Context, userID string) error
+}
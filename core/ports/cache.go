@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// Cache interface of a key-value store whose entries expire, backing the read-through caches of the repositories
+type Cache interface {
+	// Get returns the value of the key, reporting false when the key is missing or has expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
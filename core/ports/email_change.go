@@ -0,0 +1,32 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// EmailChangeRepository interface
+type EmailChangeRepository interface {
+	Create(ctx context.Context, change entities.EmailChange) (string, error)
+	GetByID(ctx context.Context, ID string) (entities.EmailChange, error)
+	GetByUserID(ctx context.Context, userID string) ([]entities.EmailChange, error)
+	Delete(ctx context.Context, ID string) error
+	DeleteByUserID(ctx context.Context, userID string) error
+}
+
+// EmailChangeNotifier interface, implemented by the channels the email change notifications are delivered through
+type EmailChangeNotifier interface {
+	// NotifyConfirmation sends the confirmation token to the new email
+	NotifyConfirmation(ctx context.Context, change entities.EmailChange, token string) error
+	// NotifyRequest warns the previous email that a change was requested
+	NotifyRequest(ctx context.Context, change entities.EmailChange) error
+}
+
+// EmailChangeService interface, it is also a personal data store since the pending changes hold the emails of the users
+type EmailChangeService interface {
+	PersonalDataStore
+	Request(ctx context.Context, userID, email string) error
+	Confirm(ctx context.Context, req models.ConfirmEmailChangeReq) (models.ConfirmEmailChangeResp, error)
+}
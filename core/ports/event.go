@@ -0,0 +1,38 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+)
+
+// OutboxRepository interface
+type OutboxRepository interface {
+	Add(ctx context.Context, event entities.Event) error
+	Pending(ctx context.Context, limit int) ([]entities.Event, error)
+	// Claim returns up to limit pending events due at now and postpones their next attempt until leaseUntil in the same atomic
+	// operation, so that no other instance claims them while they are being published. Only the earliest pending event of every
+	// aggregate is claimed, so the events of an aggregate are published in the order they occurred and a failing event only holds
+	// back the following ones of its aggregate. The events left without a mark, as when their publisher stops, are claimed again
+	// once the lease expires.
+	Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]entities.Event, error)
+	MarkPublished(ctx context.Context, ID string) error
+	MarkFailed(ctx context.Context, ID string, cause error, nextAttemptAt time.Time) error
+	MarkDead(ctx context.Context, ID string, cause error) error
+	DeletePublished(ctx context.Context, before time.Time) (int, error)
+	GetByAggregateID(ctx context.Context, aggregateID string) ([]entities.Event, error)
+	RedactPayloads(ctx context.Context, aggregateID string) error
+}
+
+// EventPublisher interface, implemented by every destination the domain events can be published to
+type EventPublisher interface {
+	Publish(ctx context.Context, event entities.Event) error
+}
+
+// OutboxService interface, it is also a personal data store since the payloads of the events hold the values of the users
+type OutboxService interface {
+	PersonalDataStore
+	Dispatch(ctx context.Context) (int, error)
+	Prune(ctx context.Context) (int, error)
+}
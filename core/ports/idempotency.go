@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+)
+
+// IdempotencyRepository interface
+type IdempotencyRepository interface {
+	Get(ctx context.Context, key string) (entities.IdempotencyRecord, error)
+	// Reserve inserts the record unless the key has a record that has not expired, failing with an AlreadyExistsErr then.
+	// The expired records of other keys are deleted along the way.
+	Reserve(ctx context.Context, record entities.IdempotencyRecord) error
+	// Complete stores the response of the record of the key, which then expires at expiresAt, as long as the key is still reserved with the token.
+	// Otherwise it fails with a ConflictErr, as the reservation expired and the key may have been reserved by another request.
+	Complete(ctx context.Context, key, token string, response []byte, expiresAt time.Time) error
+	// Release deletes the record of the key when it is reserved with the token and has no response
+	Release(ctx context.Context, key, token string) error
+}
+
+// IdempotencyService interface
+type IdempotencyService interface {
+	Reserve(ctx context.Context, subject, key, requestHash string) (string, []byte, bool, error)
+	Store(ctx context.Context, subject, key, token string, response []byte) error
+	Release(ctx context.Context, subject, key, token string) error
+}
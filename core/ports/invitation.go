@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// InvitationRepository interface
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation entities.Invitation) (string, error)
+	GetAll(ctx context.Context) ([]entities.Invitation, error)
+	GetByID(ctx context.Context, ID string) (entities.Invitation, error)
+	GetByUserID(ctx context.Context, userID string) ([]entities.Invitation, error)
+	Update(ctx context.Context, ID string, invitation entities.Invitation) error
+	Delete(ctx context.Context, ID string) error
+	DeleteByUserID(ctx context.Context, userID string) error
+}
+
+// InvitationNotifier interface, implemented by the channels the invite tokens are delivered through
+type InvitationNotifier interface {
+	Notify(ctx context.Context, invitation entities.Invitation, token string) error
+}
+
+// InvitationService interface, it is also a personal data store since the invitations hold the emails of the invited users
+type InvitationService interface {
+	PersonalDataStore
+	Invite(ctx context.Context, req models.InviteUserReq) (models.InviteUserResp, error)
+	Accept(ctx context.Context, req models.AcceptInviteReq) error
+	Resend(ctx context.Context, ID string) error
+	Revoke(ctx context.Context, ID string) error
+	GetAll(ctx context.Context) ([]models.InvitationResp, error)
+}
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// PreferencesRepository interface
+type PreferencesRepository interface {
+	GetByUserID(ctx context.Context, userID string) (entities.Preferences, error)
+	// Save stores the preferences only when the stored ones are at the previous version, or when there are none and the version is 1,
+	// failing with a conflict error otherwise
+	Save(ctx context.Context, preferences entities.Preferences) error
+	DeleteByUserID(ctx context.Context, userID string) error
+}
+
+// PreferencesService interface
+type PreferencesService interface {
+	PersonalDataStore
+	Get(ctx context.Context, userID string) (models.PreferencesResp, error)
+	Update(ctx context.Context, userID string, req models.UpdatePreferencesReq) (models.PreferencesResp, error)
+}
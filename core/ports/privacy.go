@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// PersonalDataStore interface, implemented by every store holding data related to a user
+// so that it is covered by data subject exports and erasures
+type PersonalDataStore interface {
+	Name() string
+	ExportUserData(ctx context.Context, userID string) (interface{}, error)
+	EraseUserData(ctx context.Context, userID string) error
+}
+
+// TombstoneRepository interface
+type TombstoneRepository interface {
+	Create(ctx context.Context, tombstone entities.Tombstone) (string, error)
+}
+
+// PrivacyService interface
+type PrivacyService interface {
+	ExportUserData(ctx context.Context, userID string) (models.UserDataArchive, error)
+	EraseUser(ctx context.Context, userID, erasedBy string) (models.EraseUserResp, error)
+}
@@ -0,0 +1,19 @@
+package ports
+
+import "context"
+
+// Repository generic interface of a repository of entities of type T, that can be filtered with the filters of type F
+type Repository[T, F any] interface {
+	Create(ctx context.Context, entity T) (string, error)
+	// Get returns the entities matching the filter, failing with a non existent error when there are none
+	Get(ctx context.Context, filter F, skip, take *int) ([]T, error)
+	GetByID(ctx context.Context, ID string) (T, error)
+	Update(ctx context.Context, ID string, entity T) error
+	Delete(ctx context.Context, ID string) error
+	// CreateMany creates all the entities or none of them
+	CreateMany(ctx context.Context, entities []T) ([]string, error)
+	// UpdateMany updates all the entities or none of them
+	UpdateMany(ctx context.Context, IDs []string, entities []T) error
+	// DeleteMany deletes all the entities or none of them
+	DeleteMany(ctx context.Context, IDs []string) error
+}
@@ -0,0 +1,10 @@
+package ports
+
+import "context"
+
+// UnitOfWork interface, that runs several repository calls atomically
+type UnitOfWork interface {
+	// Do runs fn in a transaction, committed when fn succeeds and rolled back otherwise.
+	// The repositories join the transaction when called with the context given to fn, and so does a nested Do.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -2,26 +2,54 @@ package ports
 
 import (
 	"context"
+	"io"
+	"time"
 
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
 	"github.com/sergicanet9/go-hexagonal-api/core/models"
-	"github.com/sergicanet9/scv-go-tools/v4/repository"
 )
 
 // UserRepositoy interface
 type UserRepository interface {
-	repository.Repository
-	CreateMany(ctx context.Context, entities []interface{}) ([]string, error)
+	Repository[entities.User, models.UserFilter]
+	// Iterate calls fn for every user matching the filter, sorted by ID, without loading all of them into memory
+	Iterate(ctx context.Context, filter models.UserFilter, fn func(user entities.User) error) error
+	// Search returns the users matching the query over their name, surnames and email, sorted by relevance
+	Search(ctx context.Context, query string, offset, limit int) ([]entities.User, error)
+}
+
+// UserWatcher interface
+type UserWatcher interface {
+	// Watch calls fn for every change of the users after the resume token, or after now when empty,
+	// until the context is done or fn returns an error.
+	Watch(ctx context.Context, resumeToken string, fn func(change entities.UserChange) error) error
+	// Prune deletes the changes that occurred before the given time, after which watching can no longer be resumed,
+	// and returns the number of deleted changes
+	Prune(ctx context.Context, before time.Time) (int, error)
+	// GetByUserID returns the changes of the user still recorded, which are none where the changes are not recorded by the watcher
+	GetByUserID(ctx context.Context, userID string) ([]entities.UserChange, error)
+	// EraseStates clears the states of the user recorded along with its changes
+	EraseStates(ctx context.Context, userID string) error
 }
 
 // UserService interface
 type UserService interface {
+	PersonalDataStore
 	Login(ctx context.Context, credentials models.LoginUserReq) (models.LoginUserResp, error)
 	Create(ctx context.Context, user models.CreateUserReq) (models.CreateUserResp, error)
-	CreateMany(ctx context.Context, users []models.CreateUserReq) (models.CreateManyUserResp, error)
+	CreateMany(ctx context.Context, users []models.CreateUserReq, partial bool) (models.CreateManyUserResp, error)
+	UpdateMany(ctx context.Context, users []models.UpdateManyUserReq, partial bool) (models.BulkUserResp, error)
+	DeleteMany(ctx context.Context, IDs []string, partial bool) (models.BulkUserResp, error)
+	Import(ctx context.Context, req models.ImportUsersReq) (models.ImportUsersResp, error)
+	Export(ctx context.Context, req models.ExportUsersReq, w io.Writer) error
 	GetAll(ctx context.Context) ([]models.GetUserResp, error)
 	GetByEmail(ctx context.Context, email string) (models.GetUserResp, error)
 	GetByID(ctx context.Context, ID string) (models.GetUserResp, error)
+	Search(ctx context.Context, req models.SearchUsersReq) (models.SearchUsersResp, error)
 	Update(ctx context.Context, ID string, user models.UpdateUserReq) error
+	ConfirmEmailChange(ctx context.Context, req models.ConfirmEmailChangeReq) (models.ConfirmEmailChangeResp, error)
 	Delete(ctx context.Context, ID string) error
 	GetUserClaims(ctx context.Context) map[int]string
+	Watch(ctx context.Context, resumeToken string, fn func(change models.UserChangeResp) error) error
+	PruneChanges(ctx context.Context) (int, error)
 }
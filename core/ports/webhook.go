@@ -0,0 +1,53 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// WebhookSubscriptionRepository interface
+type WebhookSubscriptionRepository interface {
+	Create(ctx context.Context, subscription entities.WebhookSubscription) (string, error)
+	GetAll(ctx context.Context) ([]entities.WebhookSubscription, error)
+	GetByID(ctx context.Context, ID string) (entities.WebhookSubscription, error)
+	Update(ctx context.Context, ID string, subscription entities.WebhookSubscription) error
+	Delete(ctx context.Context, ID string) error
+}
+
+// WebhookDeliveryRepository interface
+type WebhookDeliveryRepository interface {
+	CreateMany(ctx context.Context, deliveries []entities.WebhookDelivery) error
+	GetByID(ctx context.Context, ID string) (entities.WebhookDelivery, error)
+	// Claim returns up to limit pending deliveries due at now, the earliest first, and postpones their next attempt until leaseUntil
+	// in the same atomic operation, so that no other instance claims them while they are being sent.
+	// The deliveries left without an update, as when their sender stops, are claimed again once the lease expires.
+	Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]entities.WebhookDelivery, error)
+	GetByStatus(ctx context.Context, status entities.WebhookDeliveryStatus) ([]entities.WebhookDelivery, error)
+	GetByAggregateID(ctx context.Context, aggregateID string) ([]entities.WebhookDelivery, error)
+	Update(ctx context.Context, delivery entities.WebhookDelivery) error
+	UpdateBody(ctx context.Context, ID string, body []byte) error
+	DeleteBySubscription(ctx context.Context, subscriptionID string) error
+}
+
+// WebhookSender interface, implemented by the transports the webhook deliveries are sent through
+type WebhookSender interface {
+	Send(ctx context.Context, URL string, headers map[string]string, body []byte) error
+}
+
+// WebhookService interface, it is also an event publisher that enqueues a delivery for every matching subscription,
+// and a personal data store since the bodies of the deliveries hold the values of the users
+type WebhookService interface {
+	EventPublisher
+	PersonalDataStore
+	CreateSubscription(ctx context.Context, req models.CreateWebhookSubscriptionReq) (models.CreateWebhookSubscriptionResp, error)
+	GetSubscriptions(ctx context.Context) ([]models.WebhookSubscriptionResp, error)
+	GetSubscriptionByID(ctx context.Context, ID string) (models.WebhookSubscriptionResp, error)
+	UpdateSubscription(ctx context.Context, ID string, req models.UpdateWebhookSubscriptionReq) error
+	DeleteSubscription(ctx context.Context, ID string) error
+	GetDeadLetters(ctx context.Context) ([]models.WebhookDeliveryResp, error)
+	ReplayDeadLetter(ctx context.Context, ID string) error
+	Deliver(ctx context.Context) (int, error)
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+const (
+	// defaultAuditPageSize page size applied when none is requested
+	defaultAuditPageSize = 50
+	// maxAuditPageSize maximum page size that can be requested
+	maxAuditPageSize = 500
+)
+
+// auditService adapter of an audit service
+type auditService struct {
+	config     config.Config
+	repository ports.AuditRepository
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(cfg config.Config, repo ports.AuditRepository) ports.AuditService {
+	return &auditService{
+		config:     cfg,
+		repository: repo,
+	}
+}
+
+// List returns a page of the audit events matching the filter, from the most recent to the oldest
+func (s *auditService) List(ctx context.Context, req models.ListAuditEventsReq) (resp models.ListAuditEventsResp, err error) {
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = defaultAuditPageSize
+	}
+	if pageSize < 0 || pageSize > maxAuditPageSize {
+		err = wrappers.NewValidationErr(fmt.Errorf("page size must be between 1 and %d", maxAuditPageSize))
+		return
+	}
+
+	offset := 0
+	if req.PageToken != "" {
+		offset, err = strconv.Atoi(req.PageToken)
+		if err != nil || offset < 0 {
+			err = wrappers.NewValidationErr(fmt.Errorf("page token %s is not valid", req.PageToken))
+			return
+		}
+	}
+
+	// one more event is requested to know whether there is a next page
+	events, err := s.repository.List(ctx, req.Filter, offset, pageSize+1)
+	if err != nil {
+		return
+	}
+
+	if len(events) > pageSize {
+		events = events[:pageSize]
+		resp.NextPageToken = strconv.Itoa(offset + pageSize)
+	}
+
+	resp.Events = []models.AuditEventResp{}
+	for _, event := range events {
+		var eventResp models.AuditEventResp
+		eventResp, err = toAuditEventResp(event)
+		if err != nil {
+			return
+		}
+		resp.Events = append(resp.Events, eventResp)
+	}
+	return
+}
+
+func toAuditEventResp(event entities.AuditEvent) (resp models.AuditEventResp, err error) {
+	resp = models.AuditEventResp{
+		ID:        event.ID,
+		ActorID:   event.ActorID,
+		Action:    models.AuditAction(event.Action),
+		TargetID:  event.TargetID,
+		RequestID: event.RequestID,
+		Timestamp: event.Timestamp,
+	}
+
+	if resp.Before, err = normalizeAuditValues(event.Before); err != nil {
+		return
+	}
+	resp.After, err = normalizeAuditValues(event.After)
+	return
+}
+
+// normalizeAuditValues converts the values decoded by the adapters into plain JSON values
+func normalizeAuditValues(values map[string]interface{}) (map[string]interface{}, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized map[string]interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+// Name returns the name of the store, used in the data subject archives and tombstones
+func (s *auditService) Name() string {
+	return entities.EntityNameAuditEvent
+}
+
+// ExportUserData returns every audit event targeting the user
+func (s *auditService) ExportUserData(ctx context.Context, userID string) (interface{}, error) {
+	events, err := s.repository.List(ctx, models.AuditFilter{TargetID: userID}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := []models.AuditEventResp{}
+	for _, event := range events {
+		eventResp, err := toAuditEventResp(event)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, eventResp)
+	}
+	return resp, nil
+}
+
+// EraseUserData removes the values of the user from the audit events targeting it.
+// The events themselves are kept, so the trail of who mutated the user and when remains.
+func (s *auditService) EraseUserData(ctx context.Context, userID string) error {
+	return s.repository.RedactTarget(ctx, userID)
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewAuditService_Ok checks that NewAuditService creates a new auditService struct
+func TestNewAuditService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+
+	// Act
+	service := NewAuditService(cfg, auditRepositoryMock)
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestListAuditEvents_Ok checks that List returns the requested page and the token of the next one when there are more events
+func TestListAuditEvents_Ok(t *testing.T) {
+	// Arrange
+	filter := models.AuditFilter{ActorID: "test-actor"}
+	now := time.Now().UTC()
+	events := []entities.AuditEvent{
+		{ID: "1", ActorID: "test-actor", Action: "create", TargetID: "test-target", After: map[string]interface{}{"claim_ids": []int32{1}, "created_at": now}, Timestamp: now},
+		{ID: "2", ActorID: "test-actor", Action: "delete", TargetID: "test-target", Timestamp: now},
+		{ID: "3", ActorID: "test-actor", Action: "update", TargetID: "test-target", Timestamp: now},
+	}
+
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.List), context.Background(), filter, 2, 3).Return(events, nil).Once()
+
+	service := &auditService{
+		config:     config.Config{},
+		repository: auditRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.List(context.Background(), models.ListAuditEventsReq{Filter: filter, PageSize: 2, PageToken: "2"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp.Events, 2)
+	assert.Equal(t, "4", resp.NextPageToken)
+	assert.Equal(t, models.AuditActionCreate, resp.Events[0].Action)
+	assert.Equal(t, []interface{}{float64(1)}, resp.Events[0].After["claim_ids"])
+	assert.Nil(t, resp.Events[0].Before)
+}
+
+// TestListAuditEvents_LastPage checks that List returns an empty next page token on the last page
+func TestListAuditEvents_LastPage(t *testing.T) {
+	// Arrange
+	events := []entities.AuditEvent{{ID: "1"}}
+
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.List), context.Background(), models.AuditFilter{}, 0, defaultAuditPageSize+1).Return(events, nil).Once()
+
+	service := &auditService{
+		config:     config.Config{},
+		repository: auditRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.List(context.Background(), models.ListAuditEventsReq{})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp.Events, 1)
+	assert.Empty(t, resp.NextPageToken)
+}
+
+// TestListAuditEvents_InvalidPageSize checks that List returns a validation error when the page size is out of range
+func TestListAuditEvents_InvalidPageSize(t *testing.T) {
+	// Arrange
+	service := &auditService{
+		config:     config.Config{},
+		repository: mocks.NewAuditRepository(t),
+	}
+
+	// Act
+	_, err := service.List(context.Background(), models.ListAuditEventsReq{PageSize: maxAuditPageSize + 1})
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.ValidationErr)
+}
+
+// TestListAuditEvents_InvalidPageToken checks that List returns a validation error when the page token is not valid
+func TestListAuditEvents_InvalidPageToken(t *testing.T) {
+	// Arrange
+	service := &auditService{
+		config:     config.Config{},
+		repository: mocks.NewAuditRepository(t),
+	}
+
+	// Act
+	_, err := service.List(context.Background(), models.ListAuditEventsReq{PageToken: "invalid"})
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.ValidationErr)
+	assert.Equal(t, "page token invalid is not valid", err.Error())
+}
+
+// TestListAuditEvents_ListError checks that List returns an error when the repository fails
+func TestListAuditEvents_ListError(t *testing.T) {
+	// Arrange
+	expectedError := "list error"
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.List), context.Background(), mock.Anything, 0, mock.Anything).Return(nil, errors.New(expectedError)).Once()
+
+	service := &auditService{
+		config:     config.Config{},
+		repository: auditRepositoryMock,
+	}
+
+	// Act
+	_, err := service.List(context.Background(), models.ListAuditEventsReq{})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestAuditExportUserData_Ok checks that ExportUserData returns every audit event targeting the user
+func TestAuditExportUserData_Ok(t *testing.T) {
+	// Arrange
+	events := []entities.AuditEvent{{ID: "1", TargetID: "test-id"}, {ID: "2", TargetID: "test-id"}}
+
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.List), context.Background(), models.AuditFilter{TargetID: "test-id"}, 0, 0).Return(events, nil).Once()
+
+	service := &auditService{
+		config:     config.Config{},
+		repository: auditRepositoryMock,
+	}
+
+	// Act
+	data, err := service.ExportUserData(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, entities.EntityNameAuditEvent, service.Name())
+	assert.Len(t, data, 2)
+}
+
+// TestAuditEraseUserData_Ok checks that EraseUserData redacts the audit events targeting the user
+func TestAuditEraseUserData_Ok(t *testing.T) {
+	// Arrange
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.RedactTarget), context.Background(), "test-id").Return(nil).Once()
+
+	service := &auditService{
+		config:     config.Config{},
+		repository: auditRepositoryMock,
+	}
+
+	// Act
+	err := service.EraseUserData(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
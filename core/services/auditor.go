@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// auditor records the audit events of the mutations of the services.
+// The actor and request ID of the events are taken from the audit metadata of the context.
+type auditor struct {
+	audit ports.AuditRepository
+	uow   ports.UnitOfWork
+}
+
+// audited runs the mutation and then records its audit events in a single unit of work, so that no mutation is applied without being audited
+func (a auditor) audited(ctx context.Context, mutate, record func(ctx context.Context) error) error {
+	return a.uow.Do(ctx, func(ctx context.Context) error {
+		if err := mutate(ctx); err != nil {
+			return err
+		}
+		return record(ctx)
+	})
+}
+
+// record stores an audit event targeting the given user
+func (a auditor) record(ctx context.Context, action models.AuditAction, targetID string, before, after map[string]interface{}) error {
+	metadata := models.AuditMetadataFromContext(ctx)
+	event := entities.AuditEvent{
+		ActorID:   metadata.ActorID,
+		Action:    string(action),
+		TargetID:  targetID,
+		Before:    before,
+		After:     after,
+		RequestID: metadata.RequestID,
+		Timestamp: time.Now().UTC(),
+	}
+
+	if _, err := a.audit.Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to record audit event %s of user %s: %w", action, targetID, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newRecordingAuditor returns an auditor whose units of work run the given functions and whose recorded events are appended to events
+func newRecordingAuditor(t *testing.T, events *[]entities.AuditEvent) auditor {
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Create), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*events = append(*events, args.Get(1).(entities.AuditEvent))
+	}).Return("event-id", nil).Maybe()
+
+	return auditor{
+		audit: auditRepositoryMock,
+		uow:   newUnitOfWorkMock(t),
+	}
+}
+
+// TestAudited_MutateError checks that audited does not record any audit event when the mutation fails
+func TestAudited_MutateError(t *testing.T) {
+	// Arrange
+	expectedError := "mutate error"
+	var events []entities.AuditEvent
+	a := newRecordingAuditor(t, &events)
+
+	// Act
+	err := a.audited(context.Background(), func(ctx context.Context) error {
+		return errors.New(expectedError)
+	}, func(ctx context.Context) error {
+		return a.record(ctx, models.AuditActionUpdate, "test-id", nil, nil)
+	})
+
+	// Assert
+	assert.EqualError(t, err, expectedError)
+	assert.Empty(t, events)
+}
+
+// TestRecord_Ok checks that record stores an audit event with the actor and request ID of the context
+func TestRecord_Ok(t *testing.T) {
+	// Arrange
+	ctx := models.WithAuditMetadata(context.Background(), models.AuditMetadata{ActorID: "test-actor", RequestID: "test-request"})
+	var events []entities.AuditEvent
+	a := newRecordingAuditor(t, &events)
+
+	// Act
+	err := a.record(ctx, models.AuditActionErase, "test-id", nil, map[string]interface{}{"tombstone_id": "tombstone-id"})
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "test-actor", events[0].ActorID)
+		assert.Equal(t, "test-request", events[0].RequestID)
+		assert.Equal(t, string(models.AuditActionErase), events[0].Action)
+		assert.Equal(t, "test-id", events[0].TargetID)
+		assert.False(t, events[0].Timestamp.IsZero())
+	}
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/jpeg"
+	"image/png"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+const (
+	// avatarStoreName name of the avatars as a personal data store
+	avatarStoreName = "avatars"
+	// maxAvatarPixels maximum number of pixels of the avatars, checked before decoding them so that decompression bombs are rejected
+	maxAvatarPixels = 4096 * 4096
+)
+
+// avatarService adapter of an avatar service
+type avatarService struct {
+	config  config.Config
+	users   ports.UserRepository
+	storage ports.BlobStorage
+	auditor auditor
+}
+
+// NewAvatarService creates a new avatar service.
+// Every user has a single avatar, stored along with its thumbnails under keys derived from the ID of the user,
+// so that a new upload replaces the previous one. The URL of the avatar is set to the user along with its audit event in a single unit of work.
+func NewAvatarService(cfg config.Config, userRepo ports.UserRepository, storage ports.BlobStorage, audit ports.AuditRepository, uow ports.UnitOfWork) ports.AvatarService {
+	return &avatarService{
+		config:  cfg,
+		users:   userRepo,
+		storage: storage,
+		auditor: auditor{
+			audit: audit,
+			uow:   uow,
+		},
+	}
+}
+
+// Upload avatar, storing it along with a square PNG thumbnail of every configured size and setting its URL to the user.
+// The URLs carry the time of the upload, so that the caches of the previous avatar are not used.
+func (s *avatarService) Upload(ctx context.Context, userID string, req models.UploadAvatarReq) (resp models.UploadAvatarResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if len(req.Content) > s.config.Avatars.MaxSize {
+		err = wrappers.NewValidationErr(fmt.Errorf("avatar cannot be larger than %d bytes", s.config.Avatars.MaxSize))
+		return
+	}
+	if detected := http.DetectContentType(req.Content); detected != req.ContentType {
+		err = wrappers.NewValidationErr(fmt.Errorf("content of the avatar is %s instead of %s", detected, req.ContentType))
+		return
+	}
+
+	img, err := decodeAvatar(req.Content)
+	if err != nil {
+		return
+	}
+
+	// the user is checked up front so that nothing is stored for a user that does not exist
+	if _, err = s.getUser(ctx, userID); err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	url, err := s.storage.Put(ctx, avatarKey(userID), req.ContentType, req.Content)
+	if err != nil {
+		return
+	}
+	resp.AvatarURL = versionedURL(url, now)
+
+	for _, size := range s.config.Avatars.ThumbnailSizes {
+		var thumbnail bytes.Buffer
+		if err = png.Encode(&thumbnail, resizeSquare(img, size)); err != nil {
+			return
+		}
+
+		url, err = s.storage.Put(ctx, thumbnailKey(userID, size), "image/png", thumbnail.Bytes())
+		if err != nil {
+			return
+		}
+		resp.Thumbnails = append(resp.Thumbnails, models.AvatarThumbnail{
+			Size: size,
+			URL:  versionedURL(url, now),
+		})
+	}
+
+	err = s.setAvatarURL(ctx, userID, resp.AvatarURL, now, models.AuditActionUploadAvatar)
+	return
+}
+
+// Delete avatar of the user, along with its thumbnails
+func (s *avatarService) Delete(ctx context.Context, userID string) (err error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return
+	}
+	if user.AvatarURL == "" {
+		return wrappers.NewNonExistentErr(fmt.Errorf("user %s has no avatar", userID))
+	}
+
+	if err = s.deleteAvatar(ctx, userID); err != nil {
+		return
+	}
+
+	return s.setAvatarURL(ctx, userID, "", time.Now().UTC(), models.AuditActionDeleteAvatar)
+}
+
+// setAvatarURL sets the URL of the avatar to the user and records the audit event of the change in a single unit of work.
+// The user is read again within it, as the avatar is stored in between, so that the changes made to the user meanwhile are kept.
+func (s *avatarService) setAvatarURL(ctx context.Context, userID string, avatarURL string, now time.Time, action models.AuditAction) error {
+	var before map[string]interface{}
+	return s.auditor.audited(ctx, func(ctx context.Context) error {
+		user, err := s.getUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		before = map[string]interface{}{"avatar_url": user.AvatarURL}
+
+		user.AvatarURL = avatarURL
+		user.ID = ""
+		user.UpdatedAt = now
+		return s.users.Update(ctx, userID, user)
+	}, func(ctx context.Context) error {
+		return s.auditor.record(ctx, action, userID, before, map[string]interface{}{"avatar_url": avatarURL})
+	})
+}
+
+// Name of the avatars as a personal data store
+func (s *avatarService) Name() string {
+	return avatarStoreName
+}
+
+// avatarData exported representation of the avatar of a user
+type avatarData struct {
+	AvatarURL string `json:"avatar_url"`
+}
+
+// ExportUserData returns the URL of the avatar of the user, empty when the user has no avatar
+func (s *avatarService) ExportUserData(ctx context.Context, userID string) (interface{}, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return avatarData{AvatarURL: user.AvatarURL}, nil
+}
+
+// EraseUserData deletes the avatar of the user from the storage, its URL is removed when the user gets anonymized
+func (s *avatarService) EraseUserData(ctx context.Context, userID string) error {
+	return s.deleteAvatar(ctx, userID)
+}
+
+func (s *avatarService) getUser(ctx context.Context, userID string) (entities.User, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", userID))
+		}
+		return entities.User{}, err
+	}
+	return user, nil
+}
+
+// deleteAvatar deletes the avatar and the thumbnails of every configured size from the storage
+func (s *avatarService) deleteAvatar(ctx context.Context, userID string) error {
+	if err := s.storage.Delete(ctx, avatarKey(userID)); err != nil {
+		return err
+	}
+	for _, size := range s.config.Avatars.ThumbnailSizes {
+		if err := s.storage.Delete(ctx, thumbnailKey(userID, size)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func avatarKey(userID string) string {
+	return fmt.Sprintf("avatars/%s/avatar", userID)
+}
+
+func thumbnailKey(userID string, size int) string {
+	return fmt.Sprintf("avatars/%s/avatar_%d.png", userID, size)
+}
+
+func versionedURL(url string, uploadTime time.Time) string {
+	return url + "?v=" + strconv.FormatInt(uploadTime.UnixMilli(), 10)
+}
+
+// decodeAvatar decodes a JPEG or PNG image, checking its dimensions before decoding it
+func decodeAvatar(content []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, wrappers.NewValidationErr(fmt.Errorf("avatar is not a valid image: %s", err))
+	}
+	if cfg.Width*cfg.Height > maxAvatarPixels {
+		return nil, wrappers.NewValidationErr(fmt.Errorf("avatar cannot have more than %d pixels", maxAvatarPixels))
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, wrappers.NewValidationErr(fmt.Errorf("avatar is not a valid image: %s", err))
+	}
+	return img, nil
+}
+
+// resizeSquare crops the centered square of the image and resizes it to the given size,
+// averaging the source pixels covered by every resized pixel
+func resizeSquare(img image.Image, size int) *image.RGBA {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	crop := image.Rect(0, 0, side, side)
+	src := image.NewRGBA(crop)
+	origin := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+	draw.Draw(src, crop, img, origin, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := y*side/size, max((y+1)*side/size, y*side/size+1)
+		for x := 0; x < size; x++ {
+			x0, x1 := x*side/size, max((x+1)*side/size, x*side/size+1)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					i := src.PixOffset(sx, sy)
+					r += int(src.Pix[i])
+					g += int(src.Pix[i+1])
+					b += int(src.Pix[i+2])
+					a += int(src.Pix[i+3])
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
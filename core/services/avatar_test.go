@@ -0,0 +1,326 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func avatarTestConfig() config.Config {
+	cfg := config.Config{}
+	cfg.Avatars.MaxSize = 1024 * 1024
+	cfg.Avatars.ThumbnailSizes = []int{4, 16}
+	return cfg
+}
+
+// testImage returns an image of the given dimensions, whose left half is red and right half is blue
+func testImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= width/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(width, height)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// TestNewAvatarService_Ok checks that NewAvatarService creates a new avatarService struct
+func TestNewAvatarService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	userRepositoryMock := mocks.NewUserRepository(t)
+	storageMock := mocks.NewBlobStorage(t)
+
+	// Act
+	service := NewAvatarService(cfg, userRepositoryMock, storageMock, mocks.NewAuditRepository(t), mocks.NewUnitOfWork(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestUpload_Ok checks that Upload stores the avatar and a thumbnail of every configured size, and sets the avatar URL to the user
+func TestUpload_Ok(t *testing.T) {
+	// Arrange
+	var events []entities.AuditEvent
+	content := testPNG(t, 40, 20)
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{ID: "test-id", Name: "test"}, nil).Once()
+	// the password of the user changes while the avatar is stored
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{ID: "test-id", Name: "test", PasswordHash: "new-hash"}, nil).Once()
+	var updated entities.User
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), "test-id", mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(2).(entities.User)
+	}).Return(nil).Once()
+
+	thumbnails := map[string][]byte{}
+	storageMock := mocks.NewBlobStorage(t)
+	storageMock.On(testutils.FunctionName(t, ports.BlobStorage.Put), context.Background(), "avatars/test-id/avatar", "image/png", content).Return("https://test.com/avatars/test-id/avatar", nil).Once()
+	for _, key := range []string{"avatars/test-id/avatar_4.png", "avatars/test-id/avatar_16.png"} {
+		storageMock.On(testutils.FunctionName(t, ports.BlobStorage.Put), context.Background(), key, "image/png", mock.Anything).Run(func(args mock.Arguments) {
+			thumbnails[args.String(1)] = args.Get(3).([]byte)
+		}).Return("https://test.com/"+key, nil).Once()
+	}
+
+	service := &avatarService{
+		auditor: newRecordingAuditor(t, &events),
+		config:  avatarTestConfig(),
+		users:   userRepositoryMock,
+		storage: storageMock,
+	}
+
+	// Act
+	resp, err := service.Upload(context.Background(), "test-id", models.UploadAvatarReq{ContentType: "image/png", Content: content})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(resp.AvatarURL, "https://test.com/avatars/test-id/avatar?v="))
+	assert.Len(t, resp.Thumbnails, 2)
+	assert.Equal(t, 4, resp.Thumbnails[0].Size)
+	assert.True(t, strings.HasPrefix(resp.Thumbnails[0].URL, "https://test.com/avatars/test-id/avatar_4.png?v="))
+	assert.Equal(t, resp.AvatarURL, updated.AvatarURL)
+	assert.Equal(t, "test", updated.Name)
+	assert.Equal(t, "new-hash", updated.PasswordHash)
+
+	thumbnail, err := png.Decode(bytes.NewReader(thumbnails["avatars/test-id/avatar_16.png"]))
+	assert.Nil(t, err)
+	assert.Equal(t, image.Rect(0, 0, 16, 16), thumbnail.Bounds())
+	// the centered square of the image is half red and half blue
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, color.RGBAModel.Convert(thumbnail.At(0, 8)))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, color.RGBAModel.Convert(thumbnail.At(15, 8)))
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, string(models.AuditActionUploadAvatar), events[0].Action)
+		assert.Equal(t, "", events[0].Before["avatar_url"])
+		assert.Equal(t, resp.AvatarURL, events[0].After["avatar_url"])
+	}
+}
+
+// TestUpload_JPEG checks that Upload accepts JPEG avatars
+func TestUpload_JPEG(t *testing.T) {
+	// Arrange
+	var events []entities.AuditEvent
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(8, 8), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{ID: "test-id"}, nil).Twice()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), "test-id", mock.Anything).Return(nil).Once()
+
+	storageMock := mocks.NewBlobStorage(t)
+	storageMock.On(testutils.FunctionName(t, ports.BlobStorage.Put), context.Background(), "avatars/test-id/avatar", "image/jpeg", buf.Bytes()).Return("https://test.com/avatar", nil).Once()
+
+	cfg := avatarTestConfig()
+	cfg.Avatars.ThumbnailSizes = nil
+	service := &avatarService{
+		auditor: newRecordingAuditor(t, &events),
+		config:  cfg,
+		users:   userRepositoryMock,
+		storage: storageMock,
+	}
+
+	// Act
+	_, err := service.Upload(context.Background(), "test-id", models.UploadAvatarReq{ContentType: "image/jpeg", Content: buf.Bytes()})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUpload_InvalidRequest checks that Upload returns a validation error when the avatar is not accepted
+func TestUpload_InvalidRequest(t *testing.T) {
+	content := testPNG(t, 4, 4)
+
+	tests := []struct {
+		name          string
+		req           models.UploadAvatarReq
+		expectedError string
+	}{
+		{
+			name:          "unsupported content type",
+			req:           models.UploadAvatarReq{ContentType: "image/gif", Content: content},
+			expectedError: "content type image/gif is not valid, must be one of image/jpeg, image/png",
+		},
+		{
+			name:          "empty content",
+			req:           models.UploadAvatarReq{ContentType: "image/png"},
+			expectedError: "content cannot be empty",
+		},
+		{
+			name:          "too large",
+			req:           models.UploadAvatarReq{ContentType: "image/png", Content: make([]byte, 1024*1024+1)},
+			expectedError: "avatar cannot be larger than 1048576 bytes",
+		},
+		{
+			name:          "mismatching content",
+			req:           models.UploadAvatarReq{ContentType: "image/jpeg", Content: content},
+			expectedError: "content of the avatar is image/png instead of image/jpeg",
+		},
+		{
+			name:          "corrupted image",
+			req:           models.UploadAvatarReq{ContentType: "image/png", Content: content[:20]},
+			expectedError: "avatar is not a valid image: unexpected EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := &avatarService{config: avatarTestConfig()}
+
+			// Act
+			_, err := service.Upload(context.Background(), "test-id", tt.req)
+
+			// Assert
+			assert.True(t, errors.Is(err, wrappers.ValidationErr))
+			assert.Equal(t, tt.expectedError, err.Error())
+		})
+	}
+}
+
+// TestUpload_NotFound checks that Upload returns a non existent error when the user does not exist
+func TestUpload_NotFound(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{}, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+
+	service := &avatarService{
+		config: avatarTestConfig(),
+		users:  userRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Upload(context.Background(), "test-id", models.UploadAvatarReq{ContentType: "image/png", Content: testPNG(t, 4, 4)})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	assert.Equal(t, "ID test-id not found", err.Error())
+}
+
+// TestUpload_StorageError checks that Upload returns an error and leaves the user untouched when the avatar cannot be stored
+func TestUpload_StorageError(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{ID: "test-id"}, nil).Once()
+
+	storageMock := mocks.NewBlobStorage(t)
+	storageMock.On(testutils.FunctionName(t, ports.BlobStorage.Put), context.Background(), "avatars/test-id/avatar", "image/png", mock.Anything).Return("", errors.New("storage error")).Once()
+
+	service := &avatarService{
+		config:  avatarTestConfig(),
+		users:   userRepositoryMock,
+		storage: storageMock,
+	}
+
+	// Act
+	_, err := service.Upload(context.Background(), "test-id", models.UploadAvatarReq{ContentType: "image/png", Content: testPNG(t, 4, 4)})
+
+	// Assert
+	assert.Equal(t, "storage error", err.Error())
+}
+
+// TestDeleteAvatar_Ok checks that Delete removes the avatar and its thumbnails from the storage and the avatar URL from the user
+func TestDeleteAvatar_Ok(t *testing.T) {
+	// Arrange
+	var events []entities.AuditEvent
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{ID: "test-id", AvatarURL: "https://test.com/avatar"}, nil).Once()
+	// the claims of the user change while the avatar is deleted
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{ID: "test-id", AvatarURL: "https://test.com/avatar", ClaimIDs: []int32{0}}, nil).Once()
+	var updated entities.User
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), "test-id", mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(2).(entities.User)
+	}).Return(nil).Once()
+
+	storageMock := mocks.NewBlobStorage(t)
+	for _, key := range []string{"avatars/test-id/avatar", "avatars/test-id/avatar_4.png", "avatars/test-id/avatar_16.png"} {
+		storageMock.On(testutils.FunctionName(t, ports.BlobStorage.Delete), context.Background(), key).Return(nil).Once()
+	}
+
+	service := &avatarService{
+		auditor: newRecordingAuditor(t, &events),
+		config:  avatarTestConfig(),
+		users:   userRepositoryMock,
+		storage: storageMock,
+	}
+
+	// Act
+	err := service.Delete(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, updated.AvatarURL)
+	assert.Equal(t, []int32{0}, updated.ClaimIDs)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, string(models.AuditActionDeleteAvatar), events[0].Action)
+		assert.Equal(t, "https://test.com/avatar", events[0].Before["avatar_url"])
+		assert.Equal(t, "", events[0].After["avatar_url"])
+	}
+}
+
+// TestDeleteAvatar_NoAvatar checks that Delete returns a non existent error when the user has no avatar
+func TestDeleteAvatar_NoAvatar(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{ID: "test-id"}, nil).Once()
+
+	service := &avatarService{
+		config: avatarTestConfig(),
+		users:  userRepositoryMock,
+	}
+
+	// Act
+	err := service.Delete(context.Background(), "test-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	assert.Equal(t, "user test-id has no avatar", err.Error())
+}
+
+// TestEraseAvatarData_Ok checks that EraseUserData removes the avatar and its thumbnails from the storage
+func TestEraseAvatarData_Ok(t *testing.T) {
+	// Arrange
+	storageMock := mocks.NewBlobStorage(t)
+	for _, key := range []string{"avatars/test-id/avatar", "avatars/test-id/avatar_4.png", "avatars/test-id/avatar_16.png"} {
+		storageMock.On(testutils.FunctionName(t, ports.BlobStorage.Delete), context.Background(), key).Return(nil).Once()
+	}
+
+	service := &avatarService{
+		config:  avatarTestConfig(),
+		storage: storageMock,
+	}
+
+	// Act
+	err := service.EraseUserData(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// emailChangeService adapter of an email change service
+type emailChangeService struct {
+	config   config.Config
+	users    ports.UserRepository
+	changes  ports.EmailChangeRepository
+	notifier ports.EmailChangeNotifier
+	uow      ports.UnitOfWork
+}
+
+// NewEmailChangeService creates a new email change service.
+// The confirmation tokens are signed with a key derived from the JWT secret, so that they can never be used as JWTs nor as invite tokens.
+// Requesting a change stores it in a unit of work that notifies it once committed,
+// and confirming a change updates the user and discards the change in a single unit of work.
+func NewEmailChangeService(cfg config.Config, userRepo ports.UserRepository, changeRepo ports.EmailChangeRepository, notifier ports.EmailChangeNotifier, uow ports.UnitOfWork) ports.EmailChangeService {
+	return &emailChangeService{
+		config:   cfg,
+		users:    userRepo,
+		changes:  changeRepo,
+		notifier: notifier,
+		uow:      uow,
+	}
+}
+
+// Request a change of the email of the user, sending a confirmation token to the new email and warning the previous one.
+// Any other pending change of the user is discarded. The change is stored in a unit of work, and its notifications are only sent once it commits,
+// so that no token is sent for a change that is not stored, nor sent twice when the unit of work is retried.
+func (s *emailChangeService) Request(ctx context.Context, userID, email string) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		change, err := s.request(ctx, userID, email)
+		if err != nil {
+			return err
+		}
+
+		return afterCommit(ctx, func(ctx context.Context) error {
+			return s.notify(ctx, change)
+		})
+	})
+}
+
+// request replaces the pending changes of the user by a change to the given email
+func (s *emailChangeService) request(ctx context.Context, userID, email string) (change entities.EmailChange, err error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", userID))
+		}
+		return
+	}
+
+	// the email is checked up front so that no confirmation is sent for an email already in use,
+	// its uniqueness is still enforced by the repository when the change gets confirmed
+	emailNormalized := entities.NormalizeEmail(email)
+	owners, err := s.users.Get(ctx, models.UserFilter{EmailNormalized: emailNormalized}, nil, nil)
+	if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+		return
+	}
+	err = nil
+	for _, owner := range owners {
+		if owner.ID != userID {
+			err = entities.NewEmailInUseErr(emailNormalized)
+			return
+		}
+	}
+
+	if err = s.changes.DeleteByUserID(ctx, userID); err != nil {
+		return
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	change = entities.EmailChange{
+		UserID:        userID,
+		PreviousEmail: user.Email,
+		Email:         email,
+		Nonce:         nonce,
+		ExpiresAt:     now.Add(s.config.EmailChanges.TTL.Duration),
+		CreatedAt:     now,
+	}
+	change.ID, err = s.changes.Create(ctx, change)
+	return
+}
+
+// notify sends the confirmation token of the change to the new email and warns the previous one
+func (s *emailChangeService) notify(ctx context.Context, change entities.EmailChange) error {
+	token, err := s.tokens().sign(tokenClaims{
+		ID:        change.ID,
+		Nonce:     change.Nonce,
+		ExpiresAt: change.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = s.notifier.NotifyConfirmation(ctx, change, token); err != nil {
+		return fmt.Errorf("failed to send the confirmation of email change %s of user %s: %w", change.ID, change.UserID, err)
+	}
+
+	if err = s.notifier.NotifyRequest(ctx, change); err != nil {
+		return fmt.Errorf("failed to warn about email change %s of user %s: %w", change.ID, change.UserID, err)
+	}
+	return nil
+}
+
+// Confirm email change, replacing the email of the user by the new one, and returns the confirmed change
+func (s *emailChangeService) Confirm(ctx context.Context, req models.ConfirmEmailChangeReq) (resp models.ConfirmEmailChangeResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	claims, err := s.tokens().parse(req.Token)
+	if err != nil {
+		return
+	}
+
+	change, err := s.changes.GetByID(ctx, claims.ID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewValidationErr(fmt.Errorf("confirmation token is not valid"))
+		}
+		return
+	}
+	if !hmac.Equal([]byte(claims.Nonce), []byte(change.Nonce)) {
+		err = wrappers.NewValidationErr(fmt.Errorf("confirmation token is not valid"))
+		return
+	}
+	now := time.Now().UTC()
+	if !now.Before(change.ExpiresAt) {
+		err = wrappers.NewValidationErr(fmt.Errorf("confirmation token has expired"))
+		return
+	}
+
+	// the change is deleted along with the update, so that its token cannot be used again
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		user, err := s.users.GetByID(ctx, change.UserID)
+		if err != nil {
+			return err
+		}
+		// the email of the user must not have changed since the request, such as when the user got erased
+		if user.Email != change.PreviousEmail {
+			return wrappers.NewValidationErr(fmt.Errorf("confirmation token is not valid"))
+		}
+
+		user.Email = change.Email
+		user.EmailNormalized = entities.NormalizeEmail(change.Email)
+		user.ID = ""
+		user.UpdatedAt = now
+
+		if err = s.users.Update(ctx, change.UserID, user); err != nil {
+			return err
+		}
+
+		return s.changes.Delete(ctx, change.ID)
+	})
+	if err != nil {
+		return
+	}
+
+	resp = models.ConfirmEmailChangeResp{
+		UserID:        change.UserID,
+		PreviousEmail: change.PreviousEmail,
+		Email:         change.Email,
+	}
+	return
+}
+
+// Name returns the name of the store, used in the data subject archives and tombstones
+func (s *emailChangeService) Name() string {
+	return entities.EntityNameEmailChange
+}
+
+// emailChangeData exported representation of a pending email change, its nonce is never included in the archives
+type emailChangeData struct {
+	PreviousEmail string    `json:"previous_email"`
+	Email         string    `json:"email"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ExportUserData returns the pending email changes of the user
+func (s *emailChangeService) ExportUserData(ctx context.Context, userID string) (interface{}, error) {
+	changes, err := s.changes.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := []emailChangeData{}
+	for _, change := range changes {
+		data = append(data, emailChangeData{
+			PreviousEmail: change.PreviousEmail,
+			Email:         change.Email,
+			ExpiresAt:     change.ExpiresAt,
+			CreatedAt:     change.CreatedAt,
+		})
+	}
+	return data, nil
+}
+
+// EraseUserData cancels the pending email changes of the user, so that their tokens can no longer be confirmed
+func (s *emailChangeService) EraseUserData(ctx context.Context, userID string) error {
+	return s.changes.DeleteByUserID(ctx, userID)
+}
+
+func (s *emailChangeService) tokens() tokenSigner {
+	return newTokenSigner(s.config.JWTSecret, "email_changes", "confirmation token")
+}
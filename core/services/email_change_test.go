@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func emailChangeTestConfig() config.Config {
+	cfg := config.Config{JWTSecret: "test-secret"}
+	cfg.EmailChanges.TTL = utils.Duration{Duration: time.Hour}
+	return cfg
+}
+
+// TestNewEmailChangeService_Ok checks that NewEmailChangeService creates a new emailChangeService struct
+func TestNewEmailChangeService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	userRepositoryMock := mocks.NewUserRepository(t)
+	emailChangeRepositoryMock := mocks.NewEmailChangeRepository(t)
+	notifierMock := mocks.NewEmailChangeNotifier(t)
+
+	// Act
+	service := NewEmailChangeService(cfg, userRepositoryMock, emailChangeRepositoryMock, notifierMock, mocks.NewUnitOfWork(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestRequest_Ok checks that Request replaces the pending changes of the user, sends a valid confirmation token to the new email and warns the previous one
+func TestRequest_Ok(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-user-id").Return(entities.User{ID: "test-user-id", Email: "old@test.com"}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), context.Background(), models.UserFilter{EmailNormalized: "new@test.com"}, mock.Anything, mock.Anything).Return(nil, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+
+	var change entities.EmailChange
+	emailChangeRepositoryMock := mocks.NewEmailChangeRepository(t)
+	emailChangeRepositoryMock.On(testutils.FunctionName(t, ports.EmailChangeRepository.DeleteByUserID), context.Background(), "test-user-id").Return(nil).Once()
+	emailChangeRepositoryMock.On(testutils.FunctionName(t, ports.EmailChangeRepository.Create), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		change = args.Get(1).(entities.EmailChange)
+	}).Return("test-id", nil).Once()
+
+	var token string
+	var warned entities.EmailChange
+	notifierMock := mocks.NewEmailChangeNotifier(t)
+	notifierMock.On(testutils.FunctionName(t, ports.EmailChangeNotifier.NotifyConfirmation), context.Background(), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		token = args.Get(2).(string)
+	}).Return(nil).Once()
+	notifierMock.On(testutils.FunctionName(t, ports.EmailChangeNotifier.NotifyRequest), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		warned = args.Get(1).(entities.EmailChange)
+	}).Return(nil).Once()
+
+	service := &emailChangeService{
+		config:   emailChangeTestConfig(),
+		users:    userRepositoryMock,
+		changes:  emailChangeRepositoryMock,
+		notifier: notifierMock,
+		uow:      newUnitOfWorkMock(t),
+	}
+
+	// Act
+	err := service.Request(context.Background(), "test-user-id", "new@test.com")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "old@test.com", change.PreviousEmail)
+	assert.Equal(t, "new@test.com", change.Email)
+	assert.NotEmpty(t, change.Nonce)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), change.ExpiresAt, time.Minute)
+	assert.Equal(t, "test-id", warned.ID)
+	assert.Equal(t, "old@test.com", warned.PreviousEmail)
+
+	claims, err := service.tokens().parse(token)
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", claims.ID)
+	assert.Equal(t, change.Nonce, claims.Nonce)
+}
+
+// TestRequest_EmailInUse checks that Request returns an already exists error without sending any confirmation when the new email belongs to another user
+func TestRequest_EmailInUse(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-user-id").Return(entities.User{ID: "test-user-id", Email: "old@test.com"}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), context.Background(), models.UserFilter{EmailNormalized: "taken@test.com"}, mock.Anything, mock.Anything).Return([]entities.User{{ID: "other-user-id", Email: "Taken@test.com"}}, nil).Once()
+
+	emailChangeRepositoryMock := mocks.NewEmailChangeRepository(t)
+	notifierMock := mocks.NewEmailChangeNotifier(t)
+
+	service := &emailChangeService{
+		config:   emailChangeTestConfig(),
+		users:    userRepositoryMock,
+		changes:  emailChangeRepositoryMock,
+		notifier: notifierMock,
+		uow:      newUnitOfWorkMock(t),
+	}
+
+	// Act
+	err := service.Request(context.Background(), "test-user-id", " Taken@test.com")
+
+	// Assert
+	assert.True(t, errors.Is(err, entities.AlreadyExistsErr))
+	emailChangeRepositoryMock.AssertNotCalled(t, testutils.FunctionName(t, ports.EmailChangeRepository.Create))
+	notifierMock.AssertNotCalled(t, testutils.FunctionName(t, ports.EmailChangeNotifier.NotifyConfirmation))
+}
+
+// TestRequest_RolledBack checks that Request sends no notification when its unit of work does not commit
+func TestRequest_RolledBack(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, "test-user-id").Return(entities.User{ID: "test-user-id", Email: "old@test.com"}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), mock.Anything, models.UserFilter{EmailNormalized: "new@test.com"}, mock.Anything, mock.Anything).Return(nil, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+
+	emailChangeRepositoryMock := mocks.NewEmailChangeRepository(t)
+	emailChangeRepositoryMock.On(testutils.FunctionName(t, ports.EmailChangeRepository.DeleteByUserID), mock.Anything, "test-user-id").Return(nil).Once()
+	emailChangeRepositoryMock.On(testutils.FunctionName(t, ports.EmailChangeRepository.Create), mock.Anything, mock.Anything).Return("test-id", nil).Once()
+
+	expectedError := "commit error"
+	uowMock := mocks.NewUnitOfWork(t)
+	uowMock.On(testutils.FunctionName(t, ports.UnitOfWork.Do), mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		return errors.New(expectedError)
+	}).Once()
+	notifierMock := mocks.NewEmailChangeNotifier(t)
+
+	service := &emailChangeService{
+		config:   emailChangeTestConfig(),
+		users:    userRepositoryMock,
+		changes:  emailChangeRepositoryMock,
+		notifier: notifierMock,
+		uow:      NewUnitOfWork(uowMock),
+	}
+
+	// Act
+	err := service.Request(context.Background(), "test-user-id", "new@test.com")
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	notifierMock.AssertNotCalled(t, testutils.FunctionName(t, ports.EmailChangeNotifier.NotifyConfirmation))
+	notifierMock.AssertNotCalled(t, testutils.FunctionName(t, ports.EmailChangeNotifier.NotifyRequest))
+}
+
+// TestRequest_NotFound checks that Request returns a non existent error when the user does not exist
+func TestRequest_NotFound(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-user-id").Return(entities.User{}, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+
+	service := &emailChangeService{
+		config: emailChangeTestConfig(),
+		users:  userRepositoryMock,
+		uow:    newUnitOfWorkMock(t),
+	}
+
+	// Act
+	err := service.Request(context.Background(), "test-user-id", "new@test.com")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	assert.Equal(t, "ID test-user-id not found", err.Error())
+}
+
+// TestConfirm_Ok checks that Confirm replaces the email of the user by the new one and deletes the change
+func TestConfirm_Ok(t *testing.T) {
+	// Arrange
+	change := entities.EmailChange{
+		ID:            "test-id",
+		UserID:        "test-user-id",
+		PreviousEmail: "old@test.com",
+		Email:         "New@Test.com",
+		Nonce:         "test-nonce",
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}
+
+	emailChangeRepositoryMock := mocks.NewEmailChangeRepository(t)
+	emailChangeRepositoryMock.On(testutils.FunctionName(t, ports.EmailChangeRepository.GetByID), context.Background(), change.ID).Return(change, nil).Once()
+	emailChangeRepositoryMock.On(testutils.FunctionName(t, ports.EmailChangeRepository.Delete), context.Background(), change.ID).Return(nil).Once()
+
+	var updated entities.User
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), change.UserID).Return(entities.User{ID: change.UserID, Name: "test", Email: "old@test.com"}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), change.UserID, mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(2).(entities.User)
+	}).Return(nil).Once()
+
+	service := &emailChangeService{
+		config:  emailChangeTestConfig(),
+		users:   userRepositoryMock,
+		changes: emailChangeRepositoryMock,
+		uow:     newUnitOfWorkMock(t),
+	}
+	token, _ := service.tokens().sign(tokenClaims{ID: change.ID, Nonce: change.Nonce, ExpiresAt: change.ExpiresAt.Unix()})
+
+	// Act
+	resp, err := service.Confirm(context.Background(), models.ConfirmEmailChangeReq{Token: token})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.ConfirmEmailChangeResp{UserID: change.UserID, PreviousEmail: "old@test.com", Email: "New@Test.com"}, resp)
+	assert.Equal(t, "test", updated.Name)
+	assert.Equal(t, "New@Test.com", updated.Email)
+	assert.Equal(t, "new@test.com", updated.EmailNormalized)
+}
+
+// TestConfirm_ErasedUser checks that Confirm returns a validation error without updating the user when its email changed since the request,
+// so that a pending change cannot re-identify an erased user
+func TestConfirm_ErasedUser(t *testing.T) {
+	// Arrange
+	change := entities.EmailChange{
+		ID:            "test-id",
+		UserID:        "test-user-id",
+		PreviousEmail: "old@test.com",
+		Email:         "new@test.com",
+		Nonce:         "test-nonce",
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}
+
+	emailChangeRepositoryMock := mocks.NewEmailChangeRepository(t)
+	emailChangeRepositoryMock.On(testutils.FunctionName(t, ports.EmailChangeRepository.GetByID), context.Background(), change.ID).Return(change, nil).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), change.UserID).Return(entities.User{ID: change.UserID, Email: "test-user-id@erased.invalid"}, nil).Once()
+
+	service := &emailChangeService{
+		config:  emailChangeTestConfig(),
+		users:   userRepositoryMock,
+		changes: emailChangeRepositoryMock,
+		uow:     newUnitOfWorkMock(t),
+	}
+	token, _ := service.tokens().sign(tokenClaims{ID: change.ID, Nonce: change.Nonce, ExpiresAt: change.ExpiresAt.Unix()})
+
+	// Act
+	_, err := service.Confirm(context.Background(), models.ConfirmEmailChangeReq{Token: token})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "confirmation token is not valid", err.Error())
+	userRepositoryMock.AssertNotCalled(t, testutils.FunctionName(t, ports.UserRepository.Update))
+}
+
+// TestConfirm_InviteToken checks that Confirm returns a validation error when an invite token is received
+func TestConfirm_InviteToken(t *testing.T) {
+	// Arrange
+	invitations := &invitationService{config: emailChangeTestConfig()}
+	token, _ := invitations.signToken(tokenClaims{ID: "test-id", Nonce: "test-nonce", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+
+	service := &emailChangeService{config: emailChangeTestConfig()}
+
+	// Act
+	_, err := service.Confirm(context.Background(), models.ConfirmEmailChangeReq{Token: token})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "confirmation token is not valid", err.Error())
+}
+
+// TestConfirm_ExpiredToken checks that Confirm returns a validation error when the confirmation token has expired
+func TestConfirm_ExpiredToken(t *testing.T) {
+	// Arrange
+	service := &emailChangeService{config: emailChangeTestConfig()}
+	token, _ := service.tokens().sign(tokenClaims{ID: "test-id", Nonce: "test-nonce", ExpiresAt: time.Now().Add(-time.Hour).Unix()})
+
+	// Act
+	_, err := service.Confirm(context.Background(), models.ConfirmEmailChangeReq{Token: token})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "confirmation token has expired", err.Error())
+}
+
+// TestConfirm_SupersededToken checks that Confirm returns a validation error when the change has been superseded by another one
+func TestConfirm_SupersededToken(t *testing.T) {
+	// Arrange
+	emailChangeRepositoryMock := mocks.NewEmailChangeRepository(t)
+	emailChangeRepositoryMock.On(testutils.FunctionName(t, ports.EmailChangeRepository.GetByID), context.Background(), "test-id").Return(entities.EmailChange{}, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+
+	service := &emailChangeService{
+		config:  emailChangeTestConfig(),
+		changes: emailChangeRepositoryMock,
+	}
+	token, _ := service.tokens().sign(tokenClaims{ID: "test-id", Nonce: "test-nonce", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+
+	// Act
+	_, err := service.Confirm(context.Background(), models.ConfirmEmailChangeReq{Token: token})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "confirmation token is not valid", err.Error())
+}
+
+// TestConfirm_InvalidRequest checks that Confirm returns a validation error when the request is not valid
+func TestConfirm_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := &emailChangeService{}
+
+	// Act
+	_, err := service.Confirm(context.Background(), models.ConfirmEmailChangeReq{})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "token cannot be empty", err.Error())
+}
+
+// TestEmailChangeEraseUserData_Ok checks that EraseUserData cancels the pending email changes of the user
+func TestEmailChangeEraseUserData_Ok(t *testing.T) {
+	// Arrange
+	emailChangeRepositoryMock := mocks.NewEmailChangeRepository(t)
+	emailChangeRepositoryMock.On(testutils.FunctionName(t, ports.EmailChangeRepository.DeleteByUserID), context.Background(), "test-user-id").Return(nil).Once()
+
+	service := &emailChangeService{changes: emailChangeRepositoryMock}
+
+	// Act
+	err := service.EraseUserData(context.Background(), "test-user-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
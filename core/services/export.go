@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const parquetRowGroupSize = 8 * 1024 * 1024
+
+// exportColumns columns that can be exported, in their default order.
+// The password hash is deliberately left out so that it can never be selected.
+var exportColumns = []string{"id", "name", "surnames", "email", "claim_ids", "created_at", "updated_at"}
+
+// exportEncoder writes users in a given format, close must be called once all the users have been written
+type exportEncoder interface {
+	write(user entities.User) error
+	close() error
+}
+
+// Export users matching the filter, writing them to w as they are read from the repository
+func (s *userService) Export(ctx context.Context, req models.ExportUsersReq, w io.Writer) error {
+	columns, err := validateExportColumns(req.Columns)
+	if err != nil {
+		return err
+	}
+
+	encoder, err := newExportEncoder(req.Format, columns, w)
+	if err != nil {
+		return err
+	}
+
+	err = s.repository.Iterate(ctx, req.Filter, encoder.write)
+	if err != nil {
+		return err
+	}
+
+	return encoder.close()
+}
+
+func validateExportColumns(columns []string) ([]string, error) {
+	if len(columns) == 0 {
+		return exportColumns, nil
+	}
+
+	var msgs []string
+	for i, column := range columns {
+		if !slices.Contains(exportColumns, column) {
+			msgs = append(msgs, fmt.Sprintf("column %s not valid", column))
+		} else if slices.Contains(columns[:i], column) {
+			msgs = append(msgs, fmt.Sprintf("column %s is duplicated", column))
+		}
+	}
+
+	if len(msgs) > 0 {
+		return nil, wrappers.NewValidationErr(fmt.Errorf("%s", strings.Join(msgs, " | ")))
+	}
+	return columns, nil
+}
+
+func newExportEncoder(format models.ExportFormat, columns []string, w io.Writer) (exportEncoder, error) {
+	switch format {
+	case models.ExportFormatCSV:
+		return newCSVExportEncoder(columns, w)
+	case models.ExportFormatNDJSON:
+		return &ndjsonExportEncoder{columns: columns, w: w}, nil
+	case models.ExportFormatParquet:
+		return newParquetExportEncoder(columns, w)
+	default:
+		return nil, wrappers.NewValidationErr(fmt.Errorf("export format %s not valid", format))
+	}
+}
+
+// exportValue returns the value of the given column as a string, []int32 or time.Time
+func exportValue(user entities.User, column string) interface{} {
+	switch column {
+	case "id":
+		return user.ID
+	case "name":
+		return user.Name
+	case "surnames":
+		return user.Surnames
+	case "email":
+		return user.Email
+	case "claim_ids":
+		return user.ClaimIDs
+	case "created_at":
+		return user.CreatedAt
+	case "updated_at":
+		return user.UpdatedAt
+	default:
+		return nil
+	}
+}
+
+// exportString formats a value as a string, claim IDs are separated by semicolons as in the CSV imports
+func exportString(value interface{}) string {
+	switch v := value.(type) {
+	case []int32:
+		ids := make([]string, len(v))
+		for i, id := range v {
+			ids[i] = strconv.Itoa(int(id))
+		}
+		return strings.Join(ids, csvClaimIDsSeparator)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type csvExportEncoder struct {
+	columns []string
+	w       *csv.Writer
+}
+
+func newCSVExportEncoder(columns []string, w io.Writer) (*csvExportEncoder, error) {
+	e := &csvExportEncoder{columns: columns, w: csv.NewWriter(w)}
+	return e, e.w.Write(columns)
+}
+
+func (e *csvExportEncoder) write(user entities.User) error {
+	record := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		record[i] = exportString(exportValue(user, column))
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvExportEncoder) close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type ndjsonExportEncoder struct {
+	columns []string
+	w       io.Writer
+}
+
+func (e *ndjsonExportEncoder) write(user entities.User) error {
+	// the object is built by hand to keep the order of the selected columns
+	var b strings.Builder
+	b.WriteString("{")
+	for i, column := range e.columns {
+		if i > 0 {
+			b.WriteString(",")
+		}
+
+		value := exportValue(user, column)
+		if ids, ok := value.([]int32); ok && ids == nil {
+			value = []int32{}
+		}
+
+		v, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "%q:%s", column, v)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *ndjsonExportEncoder) close() error {
+	return nil
+}
+
+type parquetExportEncoder struct {
+	columns []string
+	w       *writer.CSVWriter
+}
+
+// newParquetExportEncoder writes a flat parquet schema, with timestamps in milliseconds and claim IDs as a string
+func newParquetExportEncoder(columns []string, w io.Writer) (*parquetExportEncoder, error) {
+	schema := make([]string, len(columns))
+	for i, column := range columns {
+		switch column {
+		case "created_at", "updated_at":
+			schema[i] = fmt.Sprintf("name=%s, type=INT64, convertedtype=TIMESTAMP_MILLIS", column)
+		default:
+			schema[i] = fmt.Sprintf("name=%s, type=BYTE_ARRAY, convertedtype=UTF8", column)
+		}
+	}
+
+	pw, err := writer.NewCSVWriterFromWriter(schema, w, 1)
+	if err != nil {
+		return nil, err
+	}
+	pw.RowGroupSize = parquetRowGroupSize
+
+	return &parquetExportEncoder{columns: columns, w: pw}, nil
+}
+
+func (e *parquetExportEncoder) write(user entities.User) error {
+	record := make([]interface{}, len(e.columns))
+	for i, column := range e.columns {
+		value := exportValue(user, column)
+		if t, ok := value.(time.Time); ok {
+			record[i] = t.UnixMilli()
+		} else {
+			record[i] = exportString(value)
+		}
+	}
+	return e.w.Write(record)
+}
+
+func (e *parquetExportEncoder) close() error {
+	return e.w.WriteStop()
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// iterateUsers returns a mock Run function that yields the given users to the Iterate callback
+func iterateUsers(users ...entities.User) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		fn := args.Get(2).(func(user entities.User) error)
+		for _, user := range users {
+			if err := fn(user); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// TestExport_CSVOk checks that Export writes every user as a CSV row, leaving the password hash out
+func TestExport_CSVOk(t *testing.T) {
+	// Arrange
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	user := entities.User{
+		ID:           "test-id",
+		Name:         "test",
+		Surnames:     "test",
+		Email:        "test@test.com",
+		PasswordHash: "test-hash",
+		ClaimIDs:     []int32{0},
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt,
+	}
+	expectedOutput := "id,name,surnames,email,claim_ids,created_at,updated_at\n" +
+		"test-id,test,test,test@test.com,0,2024-01-02T03:04:05Z,2024-01-02T03:04:05Z\n"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Iterate), context.Background(), models.UserFilter{}, mock.Anything).Run(iterateUsers(user)).Return(nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	var output bytes.Buffer
+
+	// Act
+	err := service.Export(context.Background(), models.ExportUsersReq{Format: models.ExportFormatCSV}, &output)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedOutput, output.String())
+	assert.NotContains(t, output.String(), user.PasswordHash)
+}
+
+// TestExport_NDJSONOk checks that Export writes the selected columns of every user as a JSON object per line
+func TestExport_NDJSONOk(t *testing.T) {
+	// Arrange
+	claimID := int32(0)
+	filter := models.UserFilter{ClaimID: &claimID}
+	users := []entities.User{
+		{ID: "id-1", Email: "test1@test.com", ClaimIDs: []int32{0}},
+		{ID: "id-2", Email: "test2@test.com"},
+	}
+	expectedOutput := `{"email":"test1@test.com","claim_ids":[0]}` + "\n" +
+		`{"email":"test2@test.com","claim_ids":[]}` + "\n"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Iterate), context.Background(), filter, mock.Anything).Run(iterateUsers(users...)).Return(nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	var output bytes.Buffer
+
+	// Act
+	err := service.Export(context.Background(), models.ExportUsersReq{
+		Format:  models.ExportFormatNDJSON,
+		Columns: []string{"email", "claim_ids"},
+		Filter:  filter,
+	}, &output)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedOutput, output.String())
+}
+
+// TestExport_ParquetOk checks that Export writes a parquet file
+func TestExport_ParquetOk(t *testing.T) {
+	// Arrange
+	user := entities.User{ID: "test-id", Email: "test@test.com", PasswordHash: "test-hash", CreatedAt: time.Now().UTC()}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Iterate), context.Background(), models.UserFilter{}, mock.Anything).Run(iterateUsers(user)).Return(nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	var output bytes.Buffer
+
+	// Act
+	err := service.Export(context.Background(), models.ExportUsersReq{Format: models.ExportFormatParquet}, &output)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(output.Bytes(), []byte("PAR1")))
+	assert.True(t, bytes.HasSuffix(output.Bytes(), []byte("PAR1")))
+	assert.NotContains(t, output.String(), user.PasswordHash)
+}
+
+// TestExport_InvalidColumns checks that Export returns a validation error when a column is not exportable or duplicated
+func TestExport_InvalidColumns(t *testing.T) {
+	// Arrange
+	expectedError := "column password_hash not valid | column email is duplicated"
+
+	service := &userService{
+		config:     config.Config{},
+		repository: mocks.NewUserRepository(t),
+	}
+
+	// Act
+	err := service.Export(context.Background(), models.ExportUsersReq{
+		Format:  models.ExportFormatCSV,
+		Columns: []string{"email", "password_hash", "email"},
+	}, &bytes.Buffer{})
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestExport_InvalidFormat checks that Export returns a validation error when the format is not valid
+func TestExport_InvalidFormat(t *testing.T) {
+	// Arrange
+	expectedError := "export format xml not valid"
+
+	service := &userService{
+		config:     config.Config{},
+		repository: mocks.NewUserRepository(t),
+	}
+
+	// Act
+	err := service.Export(context.Background(), models.ExportUsersReq{Format: models.ExportFormat("xml")}, &bytes.Buffer{})
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestExport_IterateError checks that Export returns an error when the Iterate function from the repository fails
+func TestExport_IterateError(t *testing.T) {
+	// Arrange
+	expectedError := "repository-error"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Iterate), context.Background(), models.UserFilter{}, mock.Anything).Return(errors.New(expectedError)).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	err := service.Export(context.Background(), models.ExportUsersReq{Format: models.ExportFormatCSV}, &bytes.Buffer{})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// idempotencyService adapter of an idempotency service
+type idempotencyService struct {
+	config     config.Config
+	repository ports.IdempotencyRepository
+}
+
+// NewIdempotencyService creates a new idempotency service
+func NewIdempotencyService(cfg config.Config, repo ports.IdempotencyRepository) ports.IdempotencyService {
+	return &idempotencyService{
+		config:     cfg,
+		repository: repo,
+	}
+}
+
+// Reserve reserves the key for a request of the subject, so that the retries sent while it is in progress are rejected with a conflict error,
+// returning the token of the reservation that the request has to store or release the key with.
+// Otherwise it returns the stored response of a previous request sent with the same key, if any.
+// Keys are scoped by the subject, which is empty for anonymous requests, and reusing a key with a different request payload is rejected with a validation error.
+func (s *idempotencyService) Reserve(ctx context.Context, subject, key, requestHash string) (token string, response []byte, found bool, err error) {
+	reservation, err := newNonce()
+	if err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	err = s.repository.Reserve(ctx, entities.IdempotencyRecord{
+		Key:         scopedKey(subject, key),
+		Token:       reservation,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.config.Idempotency.ReservationTTL.Duration),
+	})
+	if err == nil {
+		token = reservation
+		return
+	}
+	if !errors.Is(err, entities.AlreadyExistsErr) {
+		return
+	}
+
+	record, err := s.repository.Get(ctx, scopedKey(subject, key))
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			// the reservation expired or was released since it was found
+			err = entities.NewConflictErr(fmt.Errorf("idempotency key %s was released by a concurrent request, retry the request", key))
+		}
+		return
+	}
+
+	if record.RequestHash != requestHash {
+		err = wrappers.NewValidationErr(fmt.Errorf("idempotency key %s was already used with a different payload", key))
+		return
+	}
+	if record.Response == nil {
+		err = entities.NewConflictErr(fmt.Errorf("a request with idempotency key %s is still in progress", key))
+		return
+	}
+
+	return "", record.Response, true, nil
+}
+
+// Store saves the response of a request that reserved the key so that it can be replayed until the configured TTL expires.
+// It fails with a conflict error when the reservation expired while the request was in progress, so that its response does not overwrite the one of a retry.
+func (s *idempotencyService) Store(ctx context.Context, subject, key, token string, response []byte) error {
+	return s.repository.Complete(ctx, scopedKey(subject, key), token, response, time.Now().UTC().Add(s.config.Idempotency.TTL.Duration))
+}
+
+// Release releases the key reserved by a request that failed, so that it can be retried with the same key
+func (s *idempotencyService) Release(ctx context.Context, subject, key, token string) error {
+	return s.repository.Release(ctx, scopedKey(subject, key), token)
+}
+
+// scopedKey returns the key of the record of an idempotency key, so that the subjects cannot replay the responses of each other
+func scopedKey(subject, key string) string {
+	return subject + "/" + key
+}
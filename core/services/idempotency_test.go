@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewIdempotencyService_Ok checks that NewIdempotencyService creates a new idempotencyService struct
+func TestNewIdempotencyService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	idempotencyRepositoryMock := mocks.NewIdempotencyRepository(t)
+
+	// Act
+	service := NewIdempotencyService(cfg, idempotencyRepositoryMock)
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestReserve_Ok checks that Reserve reserves the key scoped by the subject for the reservation TTL when it was not used
+func TestReserve_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Idempotency.ReservationTTL = utils.Duration{Duration: time.Minute}
+	idempotencyRepositoryMock := mocks.NewIdempotencyRepository(t)
+	idempotencyRepositoryMock.On(testutils.FunctionName(t, ports.IdempotencyRepository.Reserve), context.Background(), mock.MatchedBy(func(record entities.IdempotencyRecord) bool {
+		return record.Key == "test-subject/test-key" && record.Token != "" && record.RequestHash == "test-hash" && record.Response == nil && record.ExpiresAt.Sub(record.CreatedAt) == time.Minute
+	})).Return(nil).Once()
+
+	service := &idempotencyService{
+		config:     cfg,
+		repository: idempotencyRepositoryMock,
+	}
+
+	// Act
+	token, response, found, err := service.Reserve(context.Background(), "test-subject", "test-key", "test-hash")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotEmpty(t, token)
+	assert.False(t, found)
+	assert.Nil(t, response)
+}
+
+// TestReserve_Completed checks that Reserve returns the stored response when the key was used by a completed request with the same payload
+func TestReserve_Completed(t *testing.T) {
+	// Arrange
+	record := entities.IdempotencyRecord{
+		Key:         "test-subject/test-key",
+		RequestHash: "test-hash",
+		Response:    []byte("test-response"),
+	}
+
+	cfg := config.Config{}
+	cfg.Idempotency.ReservationTTL = utils.Duration{Duration: time.Minute}
+	idempotencyRepositoryMock := mocks.NewIdempotencyRepository(t)
+	idempotencyRepositoryMock.On(testutils.FunctionName(t, ports.IdempotencyRepository.Reserve), context.Background(), mock.MatchedBy(func(record entities.IdempotencyRecord) bool {
+		return record.Key == "test-subject/test-key" && record.RequestHash == "test-hash" && record.Response == nil && record.ExpiresAt.Sub(record.CreatedAt) == time.Minute
+	})).Return(entities.AlreadyExistsErr).Once()
+	idempotencyRepositoryMock.On(testutils.FunctionName(t, ports.IdempotencyRepository.Get), context.Background(), "test-subject/test-key").Return(record, nil).Once()
+
+	service := &idempotencyService{
+		config:     cfg,
+		repository: idempotencyRepositoryMock,
+	}
+
+	// Act
+	token, response, found, err := service.Reserve(context.Background(), "test-subject", "test-key", "test-hash")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, token)
+	assert.True(t, found)
+	assert.Equal(t, record.Response, response)
+}
+
+// TestReserve_InProgress checks that Reserve returns a conflict error when the key is reserved by a request still in progress
+func TestReserve_InProgress(t *testing.T) {
+	// Arrange
+	record := entities.IdempotencyRecord{
+		Key:         "test-subject/test-key",
+		RequestHash: "test-hash",
+	}
+	expectedError := "a request with idempotency key test-key is still in progress"
+
+	cfg := config.Config{}
+	cfg.Idempotency.ReservationTTL = utils.Duration{Duration: time.Minute}
+	idempotencyRepositoryMock := mocks.NewIdempotencyRepository(t)
+	idempotencyRepositoryMock.On(testutils.FunctionName(t, ports.IdempotencyRepository.Reserve), context.Background(), mock.MatchedBy(func(record entities.IdempotencyRecord) bool {
+		return record.Key == "test-subject/test-key" && record.RequestHash == "test-hash" && record.Response == nil && record.ExpiresAt.Sub(record.CreatedAt) == time.Minute
+	})).Return(entities.AlreadyExistsErr).Once()
+	idempotencyRepositoryMock.On(testutils.FunctionName(t, ports.IdempotencyRepository.Get), context.Background(), "test-subject/test-key").Return(record, nil).Once()
+
+	service := &idempotencyService{
+		config:     cfg,
+		repository: idempotencyRepositoryMock,
+	}
+
+	// Act
+	_, _, found, err := service.Reserve(context.Background(), "test-subject", "test-key", "test-hash")
+
+	// Assert
+	assert.False(t, found)
+	assert.ErrorIs(t, err, entities.ConflictErr)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestReserve_Released checks that Reserve returns a conflict error when the key is released right after failing to reserve it
+func TestReserve_Released(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Idempotency.ReservationTTL = utils.Duration{Duration: time.Minute}
+	idempotencyRepositoryMock := mocks.NewIdempotencyRepository(t)
+	idempotencyRepositoryMock.On(testutils.FunctionName(t, ports.IdempotencyRepository.Reserve), context.Background(), mock.MatchedBy(func(record entities.IdempotencyRecord) bool {
+		return record.Key == "test-subject/test-key" && record.RequestHash == "test-hash" && record.Response == nil && record.ExpiresAt.Sub(record.CreatedAt) == time.Minute
+	})).Return(entities.AlreadyExistsErr).Once()
+	idempotencyRepositoryMock.On(testutils.FunctionName(t, ports.IdempotencyRepository.Get), context.Background(), "test-subject/test-key").Return(entities.IdempotencyRecord{}, wrappers.NonExistentErr).Once()
+
+	service := &idempotencyService{
+		config:     cfg,
+		repository: idempotencyRepositoryMock,
+	}
+
+	// Act
+	_, _, found, err := service.Reserve(context.Background(), "test-subject", "test-key", "test-hash")
+
+	// Assert
+	assert.False(t, found)
+	assert.ErrorIs(t, err, entities.ConflictErr)
+}
+
+// TestReserve_DifferentPayload checks that Reserve returns a validation error when the key was used with a different payload
+func TestReserve_DifferentPayload(t *testing.T) {
+	// Arrange
+	record := entities.IdempotencyRecord{
+		Key:         "test-subject/test-key",
+		RequestHash: "other-hash",
+		Response:    []byte("test-response"),
+	}
+	expectedError := "idempotency key test-key was already used with a different payload"
+
+	cfg := config.Config{}
+	cfg.Idempotency.ReservationTTL = utils.Duration{Duration: time.Minute}
+	idempotencyRepositoryMock := mocks.NewIdempotencyRepository(t)
+	idempotencyRepositoryMock.On(testutils.FunctionName(t, ports.IdempotencyRepository.Reserve), context.Background(), mock.MatchedBy(func(record entities.IdempotencyRecord) bool {
+		return record.Key == "test-subject/test-key" && record.RequestHash == "test-hash" && record.Response == nil && record.ExpiresAt.Sub(record.CreatedAt) == time.Minute
+	})).Return(entities.AlreadyExistsErr).Once()
+	idempotencyRepositoryMock.On(testutils.FunctionName(t, ports.IdempotencyRepository.Get), context.Background(), "test-subject/test-key").Return(record, nil).Once()
+
+	service := &idempotencyService{
+		config:     cfg,
+		repository: idempotencyRepositoryMock,
+	}
+
+	// Act
+	_, _, found, err := service.Reserve(context.Background(), "test-subject", "test-key", "test-hash")
+
+	// Assert
+	assert.False(t, found)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestReserve_ReserveError checks that Reserve returns an error when the Reserve function from the repository fails
+func TestReserve_ReserveError(t *testing.T) {
+	// Arrange
+	expectedError := "repository-error"
+
+	cfg := config.Config{}
+	cfg.Idempotency.ReservationTTL = utils.Duration{Duration: time.Minute}
+	idempotencyRepositoryMock := mocks.NewIdempotencyRepository(t)
+	idempotencyRepositoryMock.On(testutils.FunctionName(t, ports.IdempotencyRepository.Reserve), context.Background(), mock.MatchedBy(func(record entities.IdempotencyRecord) bool {
+		return record.Key == "test-subject/test-key" && record.RequestHash == "test-hash" && record.Response == nil && record.ExpiresAt.Sub(record.CreatedAt) == time.Minute
+	})).Return(errors.New(expectedError)).Once()
+
+	service := &idempotencyService{
+		config:     cfg,
+		repository: idempotencyRepositoryMock,
+	}
+
+	// Act
+	_, _, found, err := service.Reserve(context.Background(), "test-subject", "test-key", "test-hash")
+
+	// Assert
+	assert.False(t, found)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestReserve_GetError checks that Reserve returns an error when the Get function from the repository fails
+func TestReserve_GetError(t *testing.T) {
+	// Arrange
+	expectedError := "repository-error"
+
+	cfg := config.Config{}
+	cfg.Idempotency.ReservationTTL = utils.Duration{Duration: time.Minute}
+	idempotencyRepositoryMock := mocks.NewIdempotencyRepository(t)
+	idempotencyRepositoryMock.On(testutils.FunctionName(t, ports.IdempotencyRepository.Reserve), context.Background(), mock.MatchedBy(func(record entities.IdempotencyRecord) bool {
+		return record.Key == "test-subject/test-key" && record.RequestHash == "test-hash" && record.Response == nil && record.ExpiresAt.Sub(record.CreatedAt) == time.Minute
+	})).Return(entities.AlreadyExistsErr).Once()
+	idempotencyRepositoryMock.On(testutils.FunctionName(t, ports.IdempotencyRepository.Get), context.Background(), "test-subject/test-key").Return(entities.IdempotencyRecord{}, errors.New(expectedError)).Once()
+
+	service := &idempotencyService{
+		config:     cfg,
+		repository: idempotencyRepositoryMock,
+	}
+
+	// Act
+	_, _, found, err := service.Reserve(context.Background(), "test-subject", "test-key", "test-hash")
+
+	// Assert
+	assert.False(t, found)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestStore_Ok checks that Store completes the record of the key scoped by the subject reserved with the token with the configured TTL
+func TestStore_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Idempotency.TTL = utils.Duration{Duration: time.Hour}
+
+	idempotencyRepositoryMock := mocks.NewIdempotencyRepository(t)
+	idempotencyRepositoryMock.On(testutils.FunctionName(t, ports.IdempotencyRepository.Complete), context.Background(), "test-subject/test-key", "test-token", []byte("test-response"), mock.MatchedBy(func(expiresAt time.Time) bool {
+		return time.Until(expiresAt) > 59*time.Minute
+	})).Return(nil).Once()
+
+	service := &idempotencyService{
+		config:     cfg,
+		repository: idempotencyRepositoryMock,
+	}
+
+	// Act
+	err := service.Store(context.Background(), "test-subject", "test-key", "test-token", []byte("test-response"))
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestRelease_Ok checks that Release releases the key scoped by the subject reserved with the token
+func TestRelease_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	idempotencyRepositoryMock := mocks.NewIdempotencyRepository(t)
+	idempotencyRepositoryMock.On(testutils.FunctionName(t, ports.IdempotencyRepository.Release), context.Background(), "test-subject/test-key", "test-token").Return(nil).Once()
+
+	service := &idempotencyService{
+		config:     cfg,
+		repository: idempotencyRepositoryMock,
+	}
+
+	// Act
+	err := service.Release(context.Background(), "test-subject", "test-key", "test-token")
+
+	// Assert
+	assert.Nil(t, err)
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+const (
+	defaultImportBatchSize = 500
+	maxImportLineSize      = 1024 * 1024
+	csvClaimIDsSeparator   = ";"
+)
+
+// rowReader returns the next row of an import, io.EOF when there are no more rows,
+// or a rowError when the current row cannot be parsed but the following ones can still be read
+type rowReader func() (models.CreateUserReq, error)
+
+type rowError struct {
+	err error
+}
+
+func (e rowError) Error() string {
+	return e.err.Error()
+}
+
+// importBatch valid rows pending to be inserted, along with their row indexes
+type importBatch struct {
+	indexes  []int
+	entities []entities.User
+}
+
+// Import users row by row, inserting the valid rows in batches
+func (s *userService) Import(ctx context.Context, req models.ImportUsersReq) (resp models.ImportUsersResp, err error) {
+	next, err := newRowReader(req.Source, req.Format)
+	if err != nil {
+		return
+	}
+
+	batchSize := s.config.Import.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+
+	resp.DryRun = req.DryRun
+	creationTime := time.Now().UTC()
+	var batch importBatch
+
+	for index := 0; ; index++ {
+		user, rowErr := next()
+		if errors.Is(rowErr, io.EOF) {
+			break
+		}
+
+		var parseErr rowError
+		if errors.As(rowErr, &parseErr) {
+			resp.Total++
+			resp.Errors = append(resp.Errors, models.BulkItemResult{Index: index, Err: wrappers.NewValidationErr(parseErr.err)})
+			continue
+		}
+		if rowErr != nil {
+			err = wrappers.NewValidationErr(rowErr)
+			return
+		}
+		resp.Total++
+
+		if req.DryRun {
+			if rowErr = validateImportRow(user); rowErr != nil {
+				resp.Errors = append(resp.Errors, models.BulkItemResult{Index: index, Err: rowErr})
+				continue
+			}
+			resp.Imported++
+			continue
+		}
+
+		entity, rowErr := s.createUserEntity(user, creationTime)
+		if rowErr != nil {
+			resp.Errors = append(resp.Errors, models.BulkItemResult{Index: index, Err: rowErr})
+			continue
+		}
+		batch.indexes = append(batch.indexes, index)
+		batch.entities = append(batch.entities, entity)
+
+		if len(batch.entities) >= batchSize {
+			s.flushImportBatch(ctx, req.Apply, &batch, &resp)
+		}
+	}
+
+	if len(batch.entities) > 0 {
+		s.flushImportBatch(ctx, req.Apply, &batch, &resp)
+	}
+
+	slices.SortFunc(resp.Errors, func(a, b models.BulkItemResult) int {
+		return a.Index - b.Index
+	})
+	return
+}
+
+// flushImportBatch inserts the pending rows at once. As the batch insertion is atomic, when it fails the rows are inserted
+// one by one, so that a single failing row, such as one with an email already in use, does not fail the whole batch
+func (s *userService) flushImportBatch(ctx context.Context, apply func(context.Context, models.ImportInsertFunc) error, batch *importBatch, resp *models.ImportUsersResp) {
+	err := applyImport(ctx, apply, func(ctx context.Context) ([]models.BulkItemResult, error) {
+		ids, err := s.repository.CreateMany(ctx, batch.entities)
+		if err != nil {
+			return nil, err
+		}
+
+		imported := make([]models.BulkItemResult, len(ids))
+		for i, id := range ids {
+			imported[i] = models.BulkItemResult{Index: batch.indexes[i], ID: id}
+		}
+		return imported, nil
+	})
+	if err == nil {
+		resp.Imported += len(batch.entities)
+	} else {
+		for i, entity := range batch.entities {
+			index := batch.indexes[i]
+			err = applyImport(ctx, apply, func(ctx context.Context) ([]models.BulkItemResult, error) {
+				id, err := s.repository.Create(ctx, entity)
+				if err != nil {
+					return nil, err
+				}
+				return []models.BulkItemResult{{Index: index, ID: id}}, nil
+			})
+			if err != nil {
+				resp.Errors = append(resp.Errors, models.BulkItemResult{Index: index, Err: err})
+				continue
+			}
+			resp.Imported++
+		}
+	}
+
+	batch.indexes = batch.indexes[:0]
+	batch.entities = batch.entities[:0]
+}
+
+// applyImport inserts imported users through the apply function of the import, if any
+func applyImport(ctx context.Context, apply func(context.Context, models.ImportInsertFunc) error, insert models.ImportInsertFunc) error {
+	if apply == nil {
+		_, err := insert(ctx)
+		return err
+	}
+	return apply(ctx, insert)
+}
+
+// validateImportRow performs the same validations as createUserEntity without hashing the password
+func validateImportRow(user models.CreateUserReq) error {
+	if err := user.Validate(); err != nil {
+		return err
+	}
+	return validateClaims(user.ClaimIDs)
+}
+
+func newRowReader(source io.Reader, format models.ImportFormat) (rowReader, error) {
+	switch format {
+	case models.ImportFormatCSV:
+		return newCSVRowReader(source)
+	case models.ImportFormatNDJSON:
+		return newNDJSONRowReader(source), nil
+	default:
+		return nil, wrappers.NewValidationErr(fmt.Errorf("import format %s not valid", format))
+	}
+}
+
+// newCSVRowReader reads CSV rows whose columns are given by the header row,
+// claim IDs are separated by semicolons within their column
+func newCSVRowReader(source io.Reader) (rowReader, error) {
+	r := csv.NewReader(source)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, wrappers.NewValidationErr(fmt.Errorf("csv header row is missing"))
+	}
+	if err != nil {
+		return nil, wrappers.NewValidationErr(err)
+	}
+
+	columns := map[string]int{}
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		switch column {
+		case "name", "surnames", "email", "password", "claim_ids":
+			columns[column] = i
+		default:
+			return nil, wrappers.NewValidationErr(fmt.Errorf("csv column %s not valid", column))
+		}
+	}
+	for _, column := range []string{"email", "password"} {
+		if _, ok := columns[column]; !ok {
+			return nil, wrappers.NewValidationErr(fmt.Errorf("csv column %s is missing", column))
+		}
+	}
+
+	return func() (user models.CreateUserReq, err error) {
+		record, err := r.Read()
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				err = rowError{err}
+			}
+			return
+		}
+		if len(record) != len(header) {
+			err = rowError{fmt.Errorf("expected %d columns but got %d", len(header), len(record))}
+			return
+		}
+
+		field := func(column string) string {
+			if i, ok := columns[column]; ok {
+				return record[i]
+			}
+			return ""
+		}
+
+		user = models.CreateUserReq{
+			Name:     field("name"),
+			Surnames: field("surnames"),
+			Email:    field("email"),
+			Password: field("password"),
+		}
+
+		for _, claimID := range strings.Split(field("claim_ids"), csvClaimIDsSeparator) {
+			claimID = strings.TrimSpace(claimID)
+			if claimID == "" {
+				continue
+			}
+			id, parseErr := strconv.ParseInt(claimID, 10, 32)
+			if parseErr != nil {
+				err = rowError{fmt.Errorf("claim ID %s not valid", claimID)}
+				return
+			}
+			user.ClaimIDs = append(user.ClaimIDs, int32(id))
+		}
+		return
+	}, nil
+}
+
+// ndjsonRow JSON representation of a user in an NDJSON import
+type ndjsonRow struct {
+	Name     string  `json:"name"`
+	Surnames string  `json:"surnames"`
+	Email    string  `json:"email"`
+	Password string  `json:"password"`
+	ClaimIDs []int32 `json:"claim_ids"`
+}
+
+// newNDJSONRowReader reads one JSON object per line, skipping blank lines
+func newNDJSONRowReader(source io.Reader) rowReader {
+	scanner := bufio.NewScanner(source)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxImportLineSize)
+
+	return func() (user models.CreateUserReq, err error) {
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+
+			var row ndjsonRow
+			if err = json.Unmarshal([]byte(line), &row); err != nil {
+				err = rowError{err}
+				return
+			}
+
+			user = models.CreateUserReq{
+				Name:     row.Name,
+				Surnames: row.Surnames,
+				Email:    row.Email,
+				Password: row.Password,
+				ClaimIDs: row.ClaimIDs,
+			}
+			return
+		}
+
+		err = scanner.Err()
+		if err == nil {
+			err = io.EOF
+		}
+		return
+	}
+}
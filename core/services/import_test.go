@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestImport_CSVOk checks that Import inserts the rows of a CSV source in batches of the configured size
+func TestImport_CSVOk(t *testing.T) {
+	// Arrange
+	source := "name,email,password,claim_ids\n" +
+		"test,test1@test.com,test,0\n" +
+		"test,test2@test.com,test,\n" +
+		"test,test3@test.com,test,\n"
+
+	cfg := config.Config{}
+	cfg.Import.BatchSize = 2
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.CreateMany), mock.Anything, mock.AnythingOfType("[]entities.User")).Return([]string{"id-1", "id-2"}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.CreateMany), mock.Anything, mock.AnythingOfType("[]entities.User")).Return([]string{"id-3"}, nil).Once()
+
+	service := &userService{
+		config:     cfg,
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), models.ImportUsersReq{
+		Source: strings.NewReader(source),
+		Format: models.ImportFormatCSV,
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.ImportUsersResp{Total: 3, Imported: 3}, resp)
+}
+
+// TestImport_NDJSONOk checks that Import inserts the rows of an NDJSON source, skipping blank lines
+func TestImport_NDJSONOk(t *testing.T) {
+	// Arrange
+	source := `{"email":"test1@test.com","password":"test","claim_ids":[0]}` + "\n\n" +
+		`{"email":"test2@test.com","password":"test"}` + "\n"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.CreateMany), mock.Anything, mock.AnythingOfType("[]entities.User")).Return([]string{"id-1", "id-2"}, nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), models.ImportUsersReq{
+		Source: strings.NewReader(source),
+		Format: models.ImportFormatNDJSON,
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.ImportUsersResp{Total: 2, Imported: 2}, resp)
+}
+
+// TestImport_RowErrors checks that Import reports the invalid rows and inserts the valid ones
+func TestImport_RowErrors(t *testing.T) {
+	// Arrange
+	source := `{"email":"test1@test.com","password":"test"}` + "\n" +
+		`{"email":"test2@test.com"}` + "\n" +
+		`not-json` + "\n" +
+		`{"email":"test3@test.com","password":"test","claim_ids":[99]}` + "\n"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.CreateMany), mock.Anything, mock.AnythingOfType("[]entities.User")).Return([]string{"id-1"}, nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), models.ImportUsersReq{
+		Source: strings.NewReader(source),
+		Format: models.ImportFormatNDJSON,
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 4, resp.Total)
+	assert.Equal(t, 1, resp.Imported)
+	assert.Len(t, resp.Errors, 3)
+	assert.Equal(t, 1, resp.Errors[0].Index)
+	assert.Equal(t, "password cannot be empty", resp.Errors[0].Err.Error())
+	assert.Equal(t, 2, resp.Errors[1].Index)
+	assert.IsType(t, wrappers.ValidationErr, resp.Errors[1].Err)
+	assert.Equal(t, 3, resp.Errors[2].Index)
+	assert.Equal(t, "claim 99 is not valid", resp.Errors[2].Err.Error())
+}
+
+// TestImport_DryRun checks that Import validates the rows without inserting them when dry run is set
+func TestImport_DryRun(t *testing.T) {
+	// Arrange
+	source := "email,password\n" +
+		"test1@test.com,test\n" +
+		",test\n"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), models.ImportUsersReq{
+		Source: strings.NewReader(source),
+		Format: models.ImportFormatCSV,
+		DryRun: true,
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, 2, resp.Total)
+	assert.Equal(t, 1, resp.Imported)
+	assert.Len(t, resp.Errors, 1)
+	assert.Equal(t, 1, resp.Errors[0].Index)
+	userRepositoryMock.AssertNotCalled(t, testutils.FunctionName(t, ports.UserRepository.CreateMany))
+}
+
+// TestImport_CreateManyError checks that Import inserts the rows of a batch one by one when the CreateMany function from the repository fails,
+// only reporting as failed the rows whose insertion fails
+func TestImport_CreateManyError(t *testing.T) {
+	// Arrange
+	source := "email,password\n" +
+		"test1@test.com,test\n" +
+		"test2@test.com,test\n" +
+		"test3@test.com,test\n"
+
+	expectedError := "repository-error"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.CreateMany), mock.Anything, mock.AnythingOfType("[]entities.User")).Return([]string{}, errors.New(expectedError)).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Create), mock.Anything, mock.MatchedBy(func(user entities.User) bool {
+		return user.Email == "test2@test.com"
+	})).Return("", errors.New(expectedError)).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Create), mock.Anything, mock.AnythingOfType("entities.User")).Return("new-id", nil).Twice()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), models.ImportUsersReq{
+		Source: strings.NewReader(source),
+		Format: models.ImportFormatCSV,
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 3, resp.Total)
+	assert.Equal(t, 2, resp.Imported)
+	if assert.Len(t, resp.Errors, 1) {
+		assert.Equal(t, 1, resp.Errors[0].Index)
+		assert.Equal(t, expectedError, resp.Errors[0].Err.Error())
+	}
+}
+
+// TestImport_Apply checks that Import applies every batch through the apply function of the request with the rows and IDs of its users,
+// and inserts the rows one by one through it when a batch cannot be applied
+func TestImport_Apply(t *testing.T) {
+	// Arrange
+	source := "email,password\n" +
+		"test1@test.com,test\n" +
+		"invalid,test\n" +
+		"test2@test.com,test\n"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.CreateMany), mock.Anything, mock.AnythingOfType("[]entities.User")).Return([]string{"id-1", "id-2"}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Create), mock.Anything, mock.AnythingOfType("entities.User")).Return("id-1", nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Create), mock.Anything, mock.AnythingOfType("entities.User")).Return("id-2", nil).Once()
+
+	expectedError := "apply error"
+	var applied [][]models.BulkItemResult
+	apply := func(ctx context.Context, insert models.ImportInsertFunc) error {
+		imported, err := insert(ctx)
+		if err != nil {
+			return err
+		}
+		applied = append(applied, imported)
+		if len(imported) > 1 || imported[0].ID == "id-2" {
+			return errors.New(expectedError)
+		}
+		return nil
+	}
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), models.ImportUsersReq{
+		Source: strings.NewReader(source),
+		Format: models.ImportFormatCSV,
+		Apply:  apply,
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, resp.Imported)
+	assert.Equal(t, [][]models.BulkItemResult{
+		{{Index: 0, ID: "id-1"}, {Index: 2, ID: "id-2"}},
+		{{Index: 0, ID: "id-1"}},
+		{{Index: 2, ID: "id-2"}},
+	}, applied)
+	if assert.Len(t, resp.Errors, 2) {
+		assert.Equal(t, 1, resp.Errors[0].Index)
+		assert.Equal(t, 2, resp.Errors[1].Index)
+		assert.EqualError(t, resp.Errors[1].Err, expectedError)
+	}
+}
+
+// TestImport_InvalidFormat checks that Import returns an error when the format is not valid
+func TestImport_InvalidFormat(t *testing.T) {
+	// Arrange
+	expectedError := "import format xml not valid"
+
+	service := &userService{
+		config:     config.Config{},
+		repository: mocks.NewUserRepository(t),
+	}
+
+	// Act
+	_, err := service.Import(context.Background(), models.ImportUsersReq{
+		Source: strings.NewReader(""),
+		Format: models.ImportFormat("xml"),
+	})
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestImport_MissingCSVColumn checks that Import returns an error when a required CSV column is missing
+func TestImport_MissingCSVColumn(t *testing.T) {
+	// Arrange
+	expectedError := "csv column password is missing"
+
+	service := &userService{
+		config:     config.Config{},
+		repository: mocks.NewUserRepository(t),
+	}
+
+	// Act
+	_, err := service.Import(context.Background(), models.ImportUsersReq{
+		Source: strings.NewReader("name,email\ntest,test@test.com\n"),
+		Format: models.ImportFormatCSV,
+	})
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
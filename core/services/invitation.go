@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// invitationService adapter of an invitation service
+type invitationService struct {
+	config      config.Config
+	users       ports.UserRepository
+	invitations ports.InvitationRepository
+	notifier    ports.InvitationNotifier
+	uow         ports.UnitOfWork
+	auditor     auditor
+}
+
+// NewInvitationService creates a new invitation service.
+// The invite tokens are signed with a key derived from the JWT secret, so that they can never be used as JWTs.
+// Inviting, accepting and revoking an invite apply their changes to the user and the invitation in a single unit of work,
+// along with their audit event.
+func NewInvitationService(cfg config.Config, userRepo ports.UserRepository, invitationRepo ports.InvitationRepository, notifier ports.InvitationNotifier, audit ports.AuditRepository, uow ports.UnitOfWork) ports.InvitationService {
+	return &invitationService{
+		config:      cfg,
+		users:       userRepo,
+		invitations: invitationRepo,
+		notifier:    notifier,
+		uow:         uow,
+		auditor: auditor{
+			audit: audit,
+			uow:   uow,
+		},
+	}
+}
+
+// Invite user, creating it without password and sending it an invite token.
+// The user and the invitation are created in a single unit of work, and the token is only sent once both are committed,
+// so a failed notification leaves a pending invitation that can be resent.
+func (s *invitationService) Invite(ctx context.Context, req models.InviteUserReq) (resp models.InviteUserResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if err = validateClaims(req.ClaimIDs); err != nil {
+		return
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	invitation := entities.Invitation{
+		Email:     req.Email,
+		Nonce:     nonce,
+		ExpiresAt: now.Add(s.config.Invitations.TTL.Duration),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	err = s.uow.Do(ctx, func(ctx context.Context) (err error) {
+		invitation.UserID, err = s.users.Create(ctx, entities.User{
+			Name:            req.Name,
+			Surnames:        req.Surnames,
+			Email:           req.Email,
+			EmailNormalized: entities.NormalizeEmail(req.Email),
+			ClaimIDs:        req.ClaimIDs,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		})
+		if err != nil {
+			return
+		}
+
+		invitation.ID, err = s.invitations.Create(ctx, invitation)
+		if err != nil {
+			return
+		}
+
+		return s.auditor.record(ctx, models.AuditActionInvite, invitation.UserID, nil, map[string]interface{}{
+			"id":            invitation.UserID,
+			"name":          req.Name,
+			"surnames":      req.Surnames,
+			"email":         req.Email,
+			"claim_ids":     req.ClaimIDs,
+			"invitation_id": invitation.ID,
+		})
+	})
+	if err != nil {
+		return
+	}
+
+	if err = s.notify(ctx, invitation); err != nil {
+		return
+	}
+
+	resp = models.InviteUserResp{
+		ID:     invitation.ID,
+		UserID: invitation.UserID,
+	}
+	return
+}
+
+// Accept invite, setting the password of the invited user
+func (s *invitationService) Accept(ctx context.Context, req models.AcceptInviteReq) (err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	claims, err := s.parseToken(req.Token)
+	if err != nil {
+		return
+	}
+
+	invitation, err := s.invitations.GetByID(ctx, claims.ID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewValidationErr(fmt.Errorf("invite token is not valid"))
+		}
+		return
+	}
+	if !hmac.Equal([]byte(claims.Nonce), []byte(invitation.Nonce)) {
+		return wrappers.NewValidationErr(fmt.Errorf("invite token is not valid"))
+	}
+	now := time.Now().UTC()
+	if !now.Before(invitation.ExpiresAt) {
+		return wrappers.NewValidationErr(fmt.Errorf("invite token has expired"))
+	}
+
+	passwordHash, err := hashPassword(req.Password)
+	if err != nil {
+		return
+	}
+
+	// the invitation is deleted along with the update, so that its token cannot be used again
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		user, err := s.users.GetByID(ctx, invitation.UserID)
+		if err != nil {
+			return err
+		}
+
+		user.PasswordHash = passwordHash
+		user.ID = ""
+		user.UpdatedAt = now
+
+		if err = s.users.Update(ctx, invitation.UserID, user); err != nil {
+			return err
+		}
+
+		if err = s.invitations.Delete(ctx, invitation.ID); err != nil {
+			return err
+		}
+
+		return s.auditor.record(ctx, models.AuditActionAcceptInvite, invitation.UserID, nil, map[string]interface{}{
+			"id":            invitation.UserID,
+			"password_hash": redactedValue,
+			"invitation_id": invitation.ID,
+		})
+	})
+}
+
+// Resend invite, invalidating the previous invite tokens and extending the expiration
+func (s *invitationService) Resend(ctx context.Context, ID string) (err error) {
+	invitation, err := s.getInvitation(ctx, ID)
+	if err != nil {
+		return
+	}
+
+	invitation.Nonce, err = newNonce()
+	if err != nil {
+		return
+	}
+	now := time.Now().UTC()
+	invitation.ExpiresAt = now.Add(s.config.Invitations.TTL.Duration)
+	invitation.UpdatedAt = now
+
+	if err = s.invitations.Update(ctx, ID, invitation); err != nil {
+		return
+	}
+
+	return s.notify(ctx, invitation)
+}
+
+// Revoke invite, deleting the invitation and the invited user in a single unit of work along with its audit event
+func (s *invitationService) Revoke(ctx context.Context, ID string) (err error) {
+	invitation, err := s.getInvitation(ctx, ID)
+	if err != nil {
+		return
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.invitations.Delete(ctx, ID); err != nil {
+			return err
+		}
+
+		err := s.users.Delete(ctx, invitation.UserID)
+		if err != nil && !errors.Is(err, wrappers.NonExistentErr) {
+			return err
+		}
+
+		before := map[string]interface{}{
+			"id":            invitation.UserID,
+			"email":         invitation.Email,
+			"invitation_id": invitation.ID,
+		}
+		return s.auditor.record(ctx, models.AuditActionRevokeInvite, invitation.UserID, before, nil)
+	})
+}
+
+// GetAll pending invitations
+func (s *invitationService) GetAll(ctx context.Context) (resp []models.InvitationResp, err error) {
+	invitations, err := s.invitations.GetAll(ctx)
+	if err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	resp = []models.InvitationResp{}
+	for _, invitation := range invitations {
+		resp = append(resp, models.InvitationResp{
+			ID:        invitation.ID,
+			UserID:    invitation.UserID,
+			Email:     invitation.Email,
+			Expired:   !now.Before(invitation.ExpiresAt),
+			ExpiresAt: invitation.ExpiresAt,
+			CreatedAt: invitation.CreatedAt,
+			UpdatedAt: invitation.UpdatedAt,
+		})
+	}
+	return
+}
+
+// Name returns the name of the store, used in the data subject archives and tombstones
+func (s *invitationService) Name() string {
+	return entities.EntityNameInvitation
+}
+
+// invitationData exported representation of an invitation, its nonce is never included in the archives
+type invitationData struct {
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ExportUserData returns the pending invitations of the user
+func (s *invitationService) ExportUserData(ctx context.Context, userID string) (interface{}, error) {
+	invitations, err := s.invitations.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := []invitationData{}
+	for _, invitation := range invitations {
+		data = append(data, invitationData{
+			Email:     invitation.Email,
+			ExpiresAt: invitation.ExpiresAt,
+			CreatedAt: invitation.CreatedAt,
+			UpdatedAt: invitation.UpdatedAt,
+		})
+	}
+	return data, nil
+}
+
+// EraseUserData cancels the pending invitations of the user, so that their tokens can no longer be accepted
+func (s *invitationService) EraseUserData(ctx context.Context, userID string) error {
+	return s.invitations.DeleteByUserID(ctx, userID)
+}
+
+func (s *invitationService) getInvitation(ctx context.Context, ID string) (entities.Invitation, error) {
+	invitation, err := s.invitations.GetByID(ctx, ID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ID))
+	}
+	return invitation, err
+}
+
+func (s *invitationService) notify(ctx context.Context, invitation entities.Invitation) error {
+	token, err := s.signToken(tokenClaims{
+		ID:        invitation.ID,
+		Nonce:     invitation.Nonce,
+		ExpiresAt: invitation.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.notifier.Notify(ctx, invitation, token)
+}
+
+func (s *invitationService) signToken(claims tokenClaims) (string, error) {
+	return s.tokens().sign(claims)
+}
+
+func (s *invitationService) parseToken(token string) (tokenClaims, error) {
+	return s.tokens().parse(token)
+}
+
+func (s *invitationService) tokens() tokenSigner {
+	return newTokenSigner(s.config.JWTSecret, "invitations", "invite token")
+}
@@ -0,0 +1,498 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func invitationTestConfig() config.Config {
+	cfg := config.Config{JWTSecret: "test-secret"}
+	cfg.Invitations.TTL = utils.Duration{Duration: time.Hour}
+	return cfg
+}
+
+// TestNewInvitationService_Ok checks that NewInvitationService creates a new invitationService struct
+func TestNewInvitationService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	userRepositoryMock := mocks.NewUserRepository(t)
+	invitationRepositoryMock := mocks.NewInvitationRepository(t)
+	notifierMock := mocks.NewInvitationNotifier(t)
+
+	// Act
+	service := NewInvitationService(cfg, userRepositoryMock, invitationRepositoryMock, notifierMock, mocks.NewAuditRepository(t), mocks.NewUnitOfWork(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestInvite_Ok checks that Invite creates a user without password along with its invitation, and notifies a valid invite token
+func TestInvite_Ok(t *testing.T) {
+	// Arrange
+	var events []entities.AuditEvent
+	req := models.InviteUserReq{
+		Name:     "test",
+		Email:    "test@test.com",
+		ClaimIDs: []int32{0},
+	}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Create), context.Background(), mock.MatchedBy(func(u entities.User) bool {
+		return u.Email == req.Email && u.PasswordHash == "" && len(u.ClaimIDs) == 1
+	})).Return("test-user-id", nil).Once()
+
+	var invitation entities.Invitation
+	invitationRepositoryMock := mocks.NewInvitationRepository(t)
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.Create), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		invitation = args.Get(1).(entities.Invitation)
+	}).Return("test-id", nil).Once()
+
+	var token string
+	notifierMock := mocks.NewInvitationNotifier(t)
+	notifierMock.On(testutils.FunctionName(t, ports.InvitationNotifier.Notify), context.Background(), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		token = args.Get(2).(string)
+	}).Return(nil).Once()
+
+	service := &invitationService{
+		auditor:     newRecordingAuditor(t, &events),
+		config:      invitationTestConfig(),
+		users:       userRepositoryMock,
+		invitations: invitationRepositoryMock,
+		notifier:    notifierMock,
+		uow:         newUnitOfWorkMock(t),
+	}
+
+	// Act
+	resp, err := service.Invite(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", resp.ID)
+	assert.Equal(t, "test-user-id", resp.UserID)
+	assert.Equal(t, "test-user-id", invitation.UserID)
+	assert.NotEmpty(t, invitation.Nonce)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), invitation.ExpiresAt, time.Minute)
+
+	claims, err := service.parseToken(token)
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", claims.ID)
+	assert.Equal(t, invitation.Nonce, claims.Nonce)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, string(models.AuditActionInvite), events[0].Action)
+		assert.Equal(t, "test-user-id", events[0].TargetID)
+		assert.Equal(t, "test-id", events[0].After["invitation_id"])
+		assert.Equal(t, req.Email, events[0].After["email"])
+	}
+}
+
+// TestInvite_CreateInvitationError checks that Invite returns an error without sending any token when the invitation cannot be created,
+// the invited user being created in the same unit of work
+func TestInvite_CreateInvitationError(t *testing.T) {
+	// Arrange
+	expectedError := "repository-error"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Create), context.Background(), mock.Anything).Return("test-user-id", nil).Once()
+
+	invitationRepositoryMock := mocks.NewInvitationRepository(t)
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.Create), context.Background(), mock.Anything).Return("", errors.New(expectedError)).Once()
+
+	uow := mocks.NewUnitOfWork(t)
+	uow.On(testutils.FunctionName(t, ports.UnitOfWork.Do), context.Background(), mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}).Once()
+
+	notifierMock := mocks.NewInvitationNotifier(t)
+
+	service := &invitationService{
+		config:      invitationTestConfig(),
+		users:       userRepositoryMock,
+		invitations: invitationRepositoryMock,
+		notifier:    notifierMock,
+		uow:         uow,
+	}
+
+	// Act
+	_, err := service.Invite(context.Background(), models.InviteUserReq{Name: "test", Email: "test@test.com"})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	notifierMock.AssertNotCalled(t, testutils.FunctionName(t, ports.InvitationNotifier.Notify))
+}
+
+// TestInvite_InvalidRequest checks that Invite returns a validation error when the request is not valid
+func TestInvite_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := &invitationService{}
+
+	// Act
+	_, err := service.Invite(context.Background(), models.InviteUserReq{})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "email cannot be empty", err.Error())
+}
+
+// TestInvite_InvalidClaims checks that Invite returns a validation error when a claim is not valid
+func TestInvite_InvalidClaims(t *testing.T) {
+	// Arrange
+	service := &invitationService{}
+
+	// Act
+	_, err := service.Invite(context.Background(), models.InviteUserReq{Email: "test@test.com", ClaimIDs: []int32{99}})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "claim 99 is not valid", err.Error())
+}
+
+// TestAccept_Ok checks that Accept sets the password of the invited user and deletes the invitation
+func TestAccept_Ok(t *testing.T) {
+	// Arrange
+	var events []entities.AuditEvent
+	invitation := entities.Invitation{
+		ID:        "test-id",
+		UserID:    "test-user-id",
+		Nonce:     "test-nonce",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	invitationRepositoryMock := mocks.NewInvitationRepository(t)
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.GetByID), context.Background(), invitation.ID).Return(invitation, nil).Once()
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.Delete), context.Background(), invitation.ID).Return(nil).Once()
+
+	var updated entities.User
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), invitation.UserID).Return(entities.User{ID: invitation.UserID, Email: "test@test.com"}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), invitation.UserID, mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(2).(entities.User)
+	}).Return(nil).Once()
+
+	service := &invitationService{
+		auditor:     newRecordingAuditor(t, &events),
+		config:      invitationTestConfig(),
+		users:       userRepositoryMock,
+		invitations: invitationRepositoryMock,
+		uow:         newUnitOfWorkMock(t),
+	}
+	token, _ := service.signToken(tokenClaims{ID: invitation.ID, Nonce: invitation.Nonce, ExpiresAt: invitation.ExpiresAt.Unix()})
+
+	// Act
+	err := service.Accept(context.Background(), models.AcceptInviteReq{Token: token, Password: "test"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test@test.com", updated.Email)
+	assert.Nil(t, bcrypt.CompareHashAndPassword([]byte(updated.PasswordHash), []byte("test")))
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, string(models.AuditActionAcceptInvite), events[0].Action)
+		assert.Equal(t, invitation.UserID, events[0].TargetID)
+		assert.Equal(t, redactedValue, events[0].After["password_hash"])
+	}
+}
+
+// TestAccept_DeleteError checks that Accept fails, rolling back the password of the user, when the invitation cannot be deleted
+func TestAccept_DeleteError(t *testing.T) {
+	// Arrange
+	invitation := entities.Invitation{
+		ID:        "test-id",
+		UserID:    "test-user-id",
+		Nonce:     "test-nonce",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	expectedError := "delete error"
+
+	invitationRepositoryMock := mocks.NewInvitationRepository(t)
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.GetByID), context.Background(), invitation.ID).Return(invitation, nil).Once()
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.Delete), context.Background(), invitation.ID).Return(errors.New(expectedError)).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), invitation.UserID).Return(entities.User{ID: invitation.UserID}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), invitation.UserID, mock.Anything).Return(nil).Once()
+
+	service := &invitationService{
+		config:      invitationTestConfig(),
+		users:       userRepositoryMock,
+		invitations: invitationRepositoryMock,
+		uow:         newUnitOfWorkMock(t),
+	}
+	token, _ := service.signToken(tokenClaims{ID: invitation.ID, Nonce: invitation.Nonce, ExpiresAt: invitation.ExpiresAt.Unix()})
+
+	// Act
+	err := service.Accept(context.Background(), models.AcceptInviteReq{Token: token, Password: "test"})
+
+	// Assert
+	assert.EqualError(t, err, expectedError)
+}
+
+// TestAccept_InvalidSignature checks that Accept returns a validation error when the invite token is not signed with the expected key
+func TestAccept_InvalidSignature(t *testing.T) {
+	// Arrange
+	signer := &invitationService{config: config.Config{JWTSecret: "other-secret"}}
+	token, _ := signer.signToken(tokenClaims{ID: "test-id", Nonce: "test-nonce", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+
+	service := &invitationService{config: invitationTestConfig()}
+
+	// Act
+	err := service.Accept(context.Background(), models.AcceptInviteReq{Token: token, Password: "test"})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "invite token is not valid", err.Error())
+}
+
+// TestAccept_ExpiredToken checks that Accept returns a validation error when the invite token has expired
+func TestAccept_ExpiredToken(t *testing.T) {
+	// Arrange
+	service := &invitationService{config: invitationTestConfig()}
+	token, _ := service.signToken(tokenClaims{ID: "test-id", Nonce: "test-nonce", ExpiresAt: time.Now().Add(-time.Hour).Unix()})
+
+	// Act
+	err := service.Accept(context.Background(), models.AcceptInviteReq{Token: token, Password: "test"})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "invite token has expired", err.Error())
+}
+
+// TestAccept_SupersededToken checks that Accept returns a validation error when the invite token has been superseded by a resend
+func TestAccept_SupersededToken(t *testing.T) {
+	// Arrange
+	invitation := entities.Invitation{
+		ID:        "test-id",
+		Nonce:     "new-nonce",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	invitationRepositoryMock := mocks.NewInvitationRepository(t)
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.GetByID), context.Background(), invitation.ID).Return(invitation, nil).Once()
+
+	service := &invitationService{
+		config:      invitationTestConfig(),
+		invitations: invitationRepositoryMock,
+	}
+	token, _ := service.signToken(tokenClaims{ID: invitation.ID, Nonce: "old-nonce", ExpiresAt: invitation.ExpiresAt.Unix()})
+
+	// Act
+	err := service.Accept(context.Background(), models.AcceptInviteReq{Token: token, Password: "test"})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "invite token is not valid", err.Error())
+}
+
+// TestAccept_RevokedInvitation checks that Accept returns a validation error when the invitation no longer exists
+func TestAccept_RevokedInvitation(t *testing.T) {
+	// Arrange
+	invitationRepositoryMock := mocks.NewInvitationRepository(t)
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.GetByID), context.Background(), "test-id").Return(entities.Invitation{}, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+
+	service := &invitationService{
+		config:      invitationTestConfig(),
+		invitations: invitationRepositoryMock,
+	}
+	token, _ := service.signToken(tokenClaims{ID: "test-id", Nonce: "test-nonce", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+
+	// Act
+	err := service.Accept(context.Background(), models.AcceptInviteReq{Token: token, Password: "test"})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "invite token is not valid", err.Error())
+}
+
+// TestAccept_InvalidRequest checks that Accept returns a validation error when the request is not valid
+func TestAccept_InvalidRequest(t *testing.T) {
+	// Arrange
+	service := &invitationService{}
+
+	// Act
+	err := service.Accept(context.Background(), models.AcceptInviteReq{})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "token cannot be empty | password cannot be empty", err.Error())
+}
+
+// TestResend_Ok checks that Resend renews the nonce and the expiration of the invitation and notifies a new invite token
+func TestResend_Ok(t *testing.T) {
+	// Arrange
+	invitation := entities.Invitation{
+		ID:        "test-id",
+		Nonce:     "old-nonce",
+		ExpiresAt: time.Now().Add(-time.Hour),
+	}
+
+	var updated entities.Invitation
+	invitationRepositoryMock := mocks.NewInvitationRepository(t)
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.GetByID), context.Background(), invitation.ID).Return(invitation, nil).Once()
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.Update), context.Background(), invitation.ID, mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(2).(entities.Invitation)
+	}).Return(nil).Once()
+
+	var token string
+	notifierMock := mocks.NewInvitationNotifier(t)
+	notifierMock.On(testutils.FunctionName(t, ports.InvitationNotifier.Notify), context.Background(), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		token = args.Get(2).(string)
+	}).Return(nil).Once()
+
+	service := &invitationService{
+		config:      invitationTestConfig(),
+		invitations: invitationRepositoryMock,
+		notifier:    notifierMock,
+	}
+
+	// Act
+	err := service.Resend(context.Background(), invitation.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.NotEqual(t, invitation.Nonce, updated.Nonce)
+	assert.True(t, updated.ExpiresAt.After(time.Now()))
+
+	claims, err := service.parseToken(token)
+	assert.Nil(t, err)
+	assert.Equal(t, updated.Nonce, claims.Nonce)
+}
+
+// TestResend_NotFound checks that Resend returns a non existent error when the invitation is not found
+func TestResend_NotFound(t *testing.T) {
+	// Arrange
+	invitationRepositoryMock := mocks.NewInvitationRepository(t)
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.GetByID), context.Background(), "test-id").Return(entities.Invitation{}, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+
+	service := &invitationService{
+		invitations: invitationRepositoryMock,
+	}
+
+	// Act
+	err := service.Resend(context.Background(), "test-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	assert.Equal(t, "ID test-id not found", err.Error())
+}
+
+// TestRevoke_Ok checks that Revoke deletes the invitation along with the invited user
+func TestRevoke_Ok(t *testing.T) {
+	// Arrange
+	var events []entities.AuditEvent
+	invitation := entities.Invitation{
+		ID:     "test-id",
+		UserID: "test-user-id",
+	}
+
+	invitationRepositoryMock := mocks.NewInvitationRepository(t)
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.GetByID), context.Background(), invitation.ID).Return(invitation, nil).Once()
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.Delete), context.Background(), invitation.ID).Return(nil).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Delete), context.Background(), invitation.UserID).Return(nil).Once()
+
+	service := &invitationService{
+		auditor:     newRecordingAuditor(t, &events),
+		users:       userRepositoryMock,
+		invitations: invitationRepositoryMock,
+		uow:         newUnitOfWorkMock(t),
+	}
+
+	// Act
+	err := service.Revoke(context.Background(), invitation.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, string(models.AuditActionRevokeInvite), events[0].Action)
+		assert.Equal(t, invitation.UserID, events[0].TargetID)
+		assert.Nil(t, events[0].After)
+	}
+}
+
+// TestRevoke_DeleteUserError checks that Revoke returns the error of deleting the invited user, which rolls back the deletion of the invitation
+func TestRevoke_DeleteUserError(t *testing.T) {
+	// Arrange
+	invitation := entities.Invitation{
+		ID:     "test-id",
+		UserID: "test-user-id",
+	}
+	expectedError := "repository-error"
+
+	invitationRepositoryMock := mocks.NewInvitationRepository(t)
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.GetByID), context.Background(), invitation.ID).Return(invitation, nil).Once()
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.Delete), context.Background(), invitation.ID).Return(nil).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Delete), context.Background(), invitation.UserID).Return(errors.New(expectedError)).Once()
+
+	uow := mocks.NewUnitOfWork(t)
+	uow.On(testutils.FunctionName(t, ports.UnitOfWork.Do), context.Background(), mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}).Once()
+
+	service := &invitationService{
+		users:       userRepositoryMock,
+		invitations: invitationRepositoryMock,
+		uow:         uow,
+	}
+
+	// Act
+	err := service.Revoke(context.Background(), invitation.ID)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestGetAll_Invitations checks that GetAll returns the pending invitations, flagging the expired ones
+func TestGetAll_Invitations(t *testing.T) {
+	// Arrange
+	invitations := []entities.Invitation{
+		{ID: "1", Email: "active@test.com", ExpiresAt: time.Now().Add(time.Hour)},
+		{ID: "2", Email: "expired@test.com", ExpiresAt: time.Now().Add(-time.Hour)},
+	}
+
+	invitationRepositoryMock := mocks.NewInvitationRepository(t)
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.GetAll), context.Background()).Return(invitations, nil).Once()
+
+	service := &invitationService{
+		invitations: invitationRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.GetAll(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp, 2)
+	assert.False(t, resp[0].Expired)
+	assert.True(t, resp[1].Expired)
+	assert.Equal(t, "expired@test.com", resp[1].Email)
+}
+
+// TestInvitationEraseUserData_Ok checks that EraseUserData cancels the pending invitations of the user
+func TestInvitationEraseUserData_Ok(t *testing.T) {
+	// Arrange
+	invitationRepositoryMock := mocks.NewInvitationRepository(t)
+	invitationRepositoryMock.On(testutils.FunctionName(t, ports.InvitationRepository.DeleteByUserID), context.Background(), "test-user-id").Return(nil).Once()
+
+	service := &invitationService{invitations: invitationRepositoryMock}
+
+	// Act
+	err := service.EraseUserData(context.Background(), "test-user-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
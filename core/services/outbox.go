@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+const (
+	// defaultOutboxLease time the claimed events are postponed by when no lease is configured
+	defaultOutboxLease = 5 * time.Minute
+	// outboxLeaseMargin fraction of the lease left to mark the events published, as no event is published after the rest
+	outboxLeaseMargin = 10
+)
+
+// outboxService adapter of an outbox service
+type outboxService struct {
+	config     config.Config
+	repository ports.OutboxRepository
+	publisher  ports.EventPublisher
+}
+
+// NewOutboxService creates a new outbox service
+func NewOutboxService(cfg config.Config, repo ports.OutboxRepository, publisher ports.EventPublisher) ports.OutboxService {
+	return &outboxService{
+		config:     cfg,
+		repository: repo,
+		publisher:  publisher,
+	}
+}
+
+// Dispatch claims the pending events of the outbox that are due and publishes them, and returns how many got published.
+// An event is only marked as published after being successfully published, so every event is delivered at least once.
+// A failed event is attempted again with exponential backoff, and the following events of its aggregate wait until then, so that
+// the events of an aggregate are never published out of order, while the events of the other aggregates get published.
+// After the maximum attempts the event is moved to the dead letters, and the following ones of its aggregate get published.
+func (s *outboxService) Dispatch(ctx context.Context) (published int, err error) {
+	lease := s.config.Outbox.Lease.Duration
+	if lease <= 0 {
+		lease = defaultOutboxLease
+	}
+
+	now := time.Now().UTC()
+	leaseUntil := now.Add(lease)
+	events, err := s.repository.Claim(ctx, now, leaseUntil, s.config.Outbox.BatchSize)
+	if err != nil {
+		return
+	}
+
+	// the events are only published until shortly before their lease expires, so that none is published or marked once another
+	// instance can claim it again, and the ones left unpublished are claimed again when it expires
+	publishCtx, cancel := context.WithDeadline(ctx, leaseUntil.Add(-lease/outboxLeaseMargin))
+	defer cancel()
+
+	for _, event := range events {
+		publishErr := s.publisher.Publish(publishCtx, event)
+		if publishCtx.Err() != nil {
+			return
+		}
+
+		now := time.Now().UTC()
+		if publishErr != nil {
+			attempts := event.Attempts + 1
+			if attempts >= s.config.Outbox.MaxAttempts {
+				err = errors.Join(err, fmt.Errorf("event %s moved to the dead letters after %d attempts: %w", event.ID, attempts, publishErr))
+				if deadErr := s.repository.MarkDead(ctx, event.ID, publishErr); deadErr != nil {
+					err = errors.Join(err, deadErr)
+					return
+				}
+				continue
+			}
+
+			err = errors.Join(err, publishErr)
+			if failErr := s.repository.MarkFailed(ctx, event.ID, publishErr, now.Add(s.backoff(attempts))); failErr != nil {
+				err = errors.Join(err, failErr)
+				return
+			}
+			continue
+		}
+
+		if markErr := s.repository.MarkPublished(ctx, event.ID); markErr != nil {
+			err = errors.Join(err, markErr)
+			return
+		}
+		published++
+	}
+	return
+}
+
+// Prune deletes the events published before the retention period, and returns how many got deleted.
+// Pending and dead events are never deleted. No event is deleted when the retention is not set.
+func (s *outboxService) Prune(ctx context.Context) (int, error) {
+	if s.config.Outbox.Retention.Duration <= 0 {
+		return 0, nil
+	}
+	return s.repository.DeletePublished(ctx, time.Now().UTC().Add(-s.config.Outbox.Retention.Duration))
+}
+
+// backoff returns the delay before the next attempt of an event that failed the given attempts
+func (s *outboxService) backoff(attempts int) time.Duration {
+	return exponentialBackoff(s.config.Outbox.InitialBackoff.Duration, s.config.Outbox.MaxBackoff.Duration, attempts)
+}
+
+// Name returns the name of the store, used in the data subject archives and tombstones
+func (s *outboxService) Name() string {
+	return entities.EntityNameEvent
+}
+
+// eventData exported representation of an event of a user
+type eventData struct {
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	Payload     map[string]interface{} `json:"payload"`
+	OccurredAt  time.Time              `json:"occurred_at"`
+	PublishedAt *time.Time             `json:"published_at"`
+}
+
+// ExportUserData returns the events of the user kept in the outbox
+func (s *outboxService) ExportUserData(ctx context.Context, userID string) (interface{}, error) {
+	events, err := s.repository.GetByAggregateID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := []eventData{}
+	for _, event := range events {
+		data = append(data, eventData{
+			ID:          event.ID,
+			Type:        string(event.Type),
+			Payload:     event.Payload,
+			OccurredAt:  event.OccurredAt,
+			PublishedAt: event.PublishedAt,
+		})
+	}
+	return data, nil
+}
+
+// EraseUserData replaces the payloads of the events of the user by one only holding its ID.
+// The events themselves are kept, so the pending ones are still published.
+func (s *outboxService) EraseUserData(ctx context.Context, userID string) error {
+	return s.repository.RedactPayloads(ctx, userID)
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewOutboxService_Ok checks that NewOutboxService creates a new outboxService struct
+func TestNewOutboxService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	eventPublisherMock := mocks.NewEventPublisher(t)
+
+	// Act
+	service := NewOutboxService(cfg, outboxRepositoryMock, eventPublisherMock)
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestDispatch_Ok checks that Dispatch publishes and marks as published every pending event
+func TestDispatch_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Outbox.BatchSize = 10
+	events := []entities.Event{
+		{ID: "1", Type: entities.EventTypeUserCreated},
+		{ID: "2", Type: entities.EventTypeUserUpdated},
+	}
+
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.Claim), context.Background(), mock.Anything, mock.MatchedBy(func(leaseUntil time.Time) bool {
+		lease := time.Until(leaseUntil)
+		return lease > defaultOutboxLease-time.Minute && lease <= defaultOutboxLease
+	}), cfg.Outbox.BatchSize).Return(events, nil).Once()
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.MarkPublished), context.Background(), "1").Return(nil).Once()
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.MarkPublished), context.Background(), "2").Return(nil).Once()
+
+	eventPublisherMock := mocks.NewEventPublisher(t)
+	eventPublisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, events[0]).Return(nil).Once()
+	eventPublisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, events[1]).Return(nil).Once()
+
+	service := &outboxService{
+		config:     cfg,
+		repository: outboxRepositoryMock,
+		publisher:  eventPublisherMock,
+	}
+
+	// Act
+	published, err := service.Dispatch(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 2, published)
+}
+
+// TestDispatch_ClaimError checks that Dispatch returns an error when the pending events cannot be claimed
+func TestDispatch_ClaimError(t *testing.T) {
+	// Arrange
+	expectedError := "claim error"
+
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.Claim), context.Background(), mock.Anything, mock.Anything, 0).Return(nil, errors.New(expectedError)).Once()
+
+	service := &outboxService{
+		config:     config.Config{},
+		repository: outboxRepositoryMock,
+		publisher:  mocks.NewEventPublisher(t),
+	}
+
+	// Act
+	published, err := service.Dispatch(context.Background())
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	assert.Zero(t, published)
+}
+
+// TestDispatch_PublishError checks that Dispatch marks an event that cannot be published as failed with a backoff,
+// and goes on publishing the following claimed events, which belong to other aggregates
+func TestDispatch_PublishError(t *testing.T) {
+	// Arrange
+	events := []entities.Event{
+		{ID: "1", Type: entities.EventTypeUserCreated},
+		{ID: "2", Type: entities.EventTypeUserUpdated},
+	}
+	expectedError := errors.New("publish error")
+
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.Claim), context.Background(), mock.Anything, mock.Anything, 0).Return(events, nil).Once()
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.MarkFailed), context.Background(), "1", expectedError, mock.MatchedBy(func(nextAttemptAt time.Time) bool {
+		return nextAttemptAt.After(time.Now().Add(30 * time.Second))
+	})).Return(nil).Once()
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.MarkPublished), context.Background(), "2").Return(nil).Once()
+
+	eventPublisherMock := mocks.NewEventPublisher(t)
+	eventPublisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, events[0]).Return(expectedError).Once()
+	eventPublisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, events[1]).Return(nil).Once()
+
+	cfg := config.Config{}
+	cfg.Outbox.MaxAttempts = 3
+	cfg.Outbox.InitialBackoff = utils.Duration{Duration: time.Minute}
+	cfg.Outbox.MaxBackoff = utils.Duration{Duration: time.Hour}
+	service := &outboxService{
+		config:     cfg,
+		repository: outboxRepositoryMock,
+		publisher:  eventPublisherMock,
+	}
+
+	// Act
+	published, err := service.Dispatch(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, expectedError)
+	assert.Equal(t, 1, published)
+}
+
+// TestDispatch_MarkPublishedError checks that Dispatch returns an error when a published event cannot be marked as published
+func TestDispatch_MarkPublishedError(t *testing.T) {
+	// Arrange
+	events := []entities.Event{{ID: "1", Type: entities.EventTypeUserDeleted}}
+	expectedError := "mark published error"
+
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.Claim), context.Background(), mock.Anything, mock.Anything, 0).Return(events, nil).Once()
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.MarkPublished), context.Background(), "1").Return(errors.New(expectedError)).Once()
+
+	eventPublisherMock := mocks.NewEventPublisher(t)
+	eventPublisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, events[0]).Return(nil).Once()
+
+	service := &outboxService{
+		config:     config.Config{},
+		repository: outboxRepositoryMock,
+		publisher:  eventPublisherMock,
+	}
+
+	// Act
+	published, err := service.Dispatch(context.Background())
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	assert.Zero(t, published)
+}
+
+// TestDispatch_MaxAttemptsDead checks that Dispatch moves an event to the dead letters after the maximum attempts
+// and goes on publishing the following events, so that a poison event does not block the outbox
+func TestDispatch_MaxAttemptsDead(t *testing.T) {
+	// Arrange
+	events := []entities.Event{
+		{ID: "1", Type: entities.EventTypeUserCreated, Attempts: 2},
+		{ID: "2", Type: entities.EventTypeUserUpdated},
+	}
+	expectedError := errors.New("publish error")
+
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.Claim), context.Background(), mock.Anything, mock.Anything, 0).Return(events, nil).Once()
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.MarkDead), context.Background(), "1", expectedError).Return(nil).Once()
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.MarkPublished), context.Background(), "2").Return(nil).Once()
+
+	eventPublisherMock := mocks.NewEventPublisher(t)
+	eventPublisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, events[0]).Return(expectedError).Once()
+	eventPublisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, events[1]).Return(nil).Once()
+
+	cfg := config.Config{}
+	cfg.Outbox.MaxAttempts = 3
+	service := &outboxService{
+		config:     cfg,
+		repository: outboxRepositoryMock,
+		publisher:  eventPublisherMock,
+	}
+
+	// Act
+	published, err := service.Dispatch(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, expectedError)
+	assert.Equal(t, 1, published)
+}
+
+// TestDispatch_LeaseExpiring checks that Dispatch stops publishing the claimed events before their lease expires,
+// leaving the ones cut or not published to be claimed again
+func TestDispatch_LeaseExpiring(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Outbox.Lease = utils.Duration{Duration: 100 * time.Millisecond}
+	events := []entities.Event{
+		{ID: "1", Type: entities.EventTypeUserCreated},
+		{ID: "2", Type: entities.EventTypeUserUpdated},
+	}
+
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.Claim), context.Background(), mock.Anything, mock.Anything, 0).Return(events, nil).Once()
+
+	eventPublisherMock := mocks.NewEventPublisher(t)
+	eventPublisherMock.On(testutils.FunctionName(t, ports.EventPublisher.Publish), mock.Anything, events[0]).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return(context.DeadlineExceeded).Once()
+
+	service := &outboxService{
+		config:     cfg,
+		repository: outboxRepositoryMock,
+		publisher:  eventPublisherMock,
+	}
+
+	// Act
+	published, err := service.Dispatch(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Zero(t, published)
+}
+
+// TestPrune_Ok checks that Prune deletes the events published before the retention period
+func TestPrune_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Outbox.Retention = utils.Duration{Duration: time.Hour}
+
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.DeletePublished), context.Background(), mock.MatchedBy(func(before time.Time) bool {
+		return before.Before(time.Now().Add(-59*time.Minute)) && before.After(time.Now().Add(-61*time.Minute))
+	})).Return(3, nil).Once()
+
+	service := &outboxService{
+		config:     cfg,
+		repository: outboxRepositoryMock,
+	}
+
+	// Act
+	deleted, err := service.Prune(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 3, deleted)
+}
+
+// TestPrune_NoRetention checks that Prune deletes no event when the retention is not set
+func TestPrune_NoRetention(t *testing.T) {
+	// Arrange
+	service := &outboxService{
+		config:     config.Config{},
+		repository: mocks.NewOutboxRepository(t),
+	}
+
+	// Act
+	deleted, err := service.Prune(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Zero(t, deleted)
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// preferencesStoreName name of the preferences as a personal data store
+const preferencesStoreName = "preferences"
+
+// preferencesService adapter of a preferences service
+type preferencesService struct {
+	config      config.Config
+	users       ports.UserRepository
+	preferences ports.PreferencesRepository
+}
+
+// NewPreferencesService creates a new preferences service.
+// Only the preferences overridden by the users are stored, and they are merged with the defaults of the configuration when read,
+// so that changing a default applies to every user who has not overridden it.
+func NewPreferencesService(cfg config.Config, userRepo ports.UserRepository, preferencesRepo ports.PreferencesRepository) ports.PreferencesService {
+	return &preferencesService{
+		config:      cfg,
+		users:       userRepo,
+		preferences: preferencesRepo,
+	}
+}
+
+// Get preferences of the user, merging the defaults with the overrides of the user
+func (s *preferencesService) Get(ctx context.Context, userID string) (resp models.PreferencesResp, err error) {
+	if err = s.checkUser(ctx, userID); err != nil {
+		return
+	}
+
+	preferences, err := s.getOverrides(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	return s.merge(preferences), nil
+}
+
+// Update preferences of the user, increasing their version.
+// An update based on an outdated version, either the given one or the one read while concurrently updated, is rejected with a conflict error.
+func (s *preferencesService) Update(ctx context.Context, userID string, req models.UpdatePreferencesReq) (resp models.PreferencesResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	if err = s.checkUser(ctx, userID); err != nil {
+		return
+	}
+
+	preferences, err := s.getOverrides(ctx, userID)
+	if err != nil {
+		return
+	}
+	if req.Version != nil && *req.Version != preferences.Version {
+		err = entities.NewConflictErr(fmt.Errorf("version %d is outdated, the current version of the preferences is %d", *req.Version, preferences.Version))
+		return
+	}
+
+	if req.Language != nil {
+		preferences.Language = req.Language
+	}
+	if req.Timezone != nil {
+		preferences.Timezone = req.Timezone
+	}
+	if req.Notifications.Email != nil {
+		preferences.Notifications.Email = req.Notifications.Email
+	}
+	if req.Notifications.Push != nil {
+		preferences.Notifications.Push = req.Notifications.Push
+	}
+	if req.Notifications.Marketing != nil {
+		preferences.Notifications.Marketing = req.Notifications.Marketing
+	}
+	for _, name := range req.Reset {
+		switch name {
+		case "language":
+			preferences.Language = nil
+		case "timezone":
+			preferences.Timezone = nil
+		case "notifications.email":
+			preferences.Notifications.Email = nil
+		case "notifications.push":
+			preferences.Notifications.Push = nil
+		case "notifications.marketing":
+			preferences.Notifications.Marketing = nil
+		}
+	}
+
+	preferences.Version++
+	preferences.UpdatedAt = time.Now().UTC()
+
+	if err = s.preferences.Save(ctx, preferences); err != nil {
+		return
+	}
+
+	return s.merge(preferences), nil
+}
+
+// Name of the preferences as a personal data store
+func (s *preferencesService) Name() string {
+	return preferencesStoreName
+}
+
+// preferencesData exported representation of the preferences of a user, holding only the ones overridden by the user
+type preferencesData struct {
+	Version       int                         `json:"version"`
+	Language      *string                     `json:"language"`
+	Timezone      *string                     `json:"timezone"`
+	Notifications notificationPreferencesData `json:"notifications"`
+	UpdatedAt     time.Time                   `json:"updated_at"`
+}
+
+// notificationPreferencesData exported representation of the notification toggles overridden by a user
+type notificationPreferencesData struct {
+	Email     *bool `json:"email"`
+	Push      *bool `json:"push"`
+	Marketing *bool `json:"marketing"`
+}
+
+// ExportUserData returns the preferences overridden by the user, which are null when the defaults apply
+func (s *preferencesService) ExportUserData(ctx context.Context, userID string) (interface{}, error) {
+	preferences, err := s.getOverrides(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return preferencesData{
+		Version:  preferences.Version,
+		Language: preferences.Language,
+		Timezone: preferences.Timezone,
+		Notifications: notificationPreferencesData{
+			Email:     preferences.Notifications.Email,
+			Push:      preferences.Notifications.Push,
+			Marketing: preferences.Notifications.Marketing,
+		},
+		UpdatedAt: preferences.UpdatedAt,
+	}, nil
+}
+
+// EraseUserData deletes the preferences overridden by the user
+func (s *preferencesService) EraseUserData(ctx context.Context, userID string) error {
+	return s.preferences.DeleteByUserID(ctx, userID)
+}
+
+func (s *preferencesService) checkUser(ctx context.Context, userID string) error {
+	_, err := s.users.GetByID(ctx, userID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", userID))
+	}
+	return err
+}
+
+// getOverrides returns the preferences overridden by the user, with no overrides and version 0 when none were stored
+func (s *preferencesService) getOverrides(ctx context.Context, userID string) (entities.Preferences, error) {
+	preferences, err := s.preferences.GetByUserID(ctx, userID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		return entities.Preferences{UserID: userID}, nil
+	}
+	return preferences, err
+}
+
+// merge returns the defaults of the configuration with the overrides of the user applied
+func (s *preferencesService) merge(preferences entities.Preferences) models.PreferencesResp {
+	defaults := s.config.Preferences
+	return models.PreferencesResp{
+		Version:  preferences.Version,
+		Language: valueOr(preferences.Language, defaults.Language),
+		Timezone: valueOr(preferences.Timezone, defaults.Timezone),
+		Notifications: models.NotificationPreferencesResp{
+			Email:     valueOr(preferences.Notifications.Email, defaults.Notifications.Email),
+			Push:      valueOr(preferences.Notifications.Push, defaults.Notifications.Push),
+			Marketing: valueOr(preferences.Notifications.Marketing, defaults.Notifications.Marketing),
+		},
+		UpdatedAt: preferences.UpdatedAt,
+	}
+}
+
+func valueOr[T any](value *T, fallback T) T {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
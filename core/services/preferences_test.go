@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func preferencesTestConfig() config.Config {
+	cfg := config.Config{}
+	cfg.Preferences.Language = "en"
+	cfg.Preferences.Timezone = "UTC"
+	cfg.Preferences.Notifications.Email = true
+	cfg.Preferences.Notifications.Push = true
+	return cfg
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+// TestNewPreferencesService_Ok checks that NewPreferencesService creates a new preferencesService struct
+func TestNewPreferencesService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	userRepositoryMock := mocks.NewUserRepository(t)
+	preferencesRepositoryMock := mocks.NewPreferencesRepository(t)
+
+	// Act
+	service := NewPreferencesService(cfg, userRepositoryMock, preferencesRepositoryMock)
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestGetPreferences_Defaults checks that Get returns the defaults at version 0 when the user has no stored preferences
+func TestGetPreferences_Defaults(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{ID: "test-id"}, nil).Once()
+	preferencesRepositoryMock := mocks.NewPreferencesRepository(t)
+	preferencesRepositoryMock.On(testutils.FunctionName(t, ports.PreferencesRepository.GetByUserID), context.Background(), "test-id").Return(entities.Preferences{}, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+
+	service := NewPreferencesService(preferencesTestConfig(), userRepositoryMock, preferencesRepositoryMock)
+
+	// Act
+	resp, err := service.Get(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.PreferencesResp{
+		Version:       0,
+		Language:      "en",
+		Timezone:      "UTC",
+		Notifications: models.NotificationPreferencesResp{Email: true, Push: true, Marketing: false},
+	}, resp)
+}
+
+// TestGetPreferences_Merged checks that Get returns the defaults with the overrides of the user applied
+func TestGetPreferences_Merged(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{ID: "test-id"}, nil).Once()
+	preferencesRepositoryMock := mocks.NewPreferencesRepository(t)
+	preferencesRepositoryMock.On(testutils.FunctionName(t, ports.PreferencesRepository.GetByUserID), context.Background(), "test-id").Return(entities.Preferences{
+		UserID:        "test-id",
+		Version:       3,
+		Timezone:      ptr("Europe/Madrid"),
+		Notifications: entities.NotificationPreferences{Push: ptr(false)},
+	}, nil).Once()
+
+	service := NewPreferencesService(preferencesTestConfig(), userRepositoryMock, preferencesRepositoryMock)
+
+	// Act
+	resp, err := service.Get(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 3, resp.Version)
+	assert.Equal(t, "en", resp.Language)
+	assert.Equal(t, "Europe/Madrid", resp.Timezone)
+	assert.Equal(t, models.NotificationPreferencesResp{Email: true, Push: false, Marketing: false}, resp.Notifications)
+}
+
+// TestGetPreferences_NotFound checks that Get returns a non existent error when the user does not exist
+func TestGetPreferences_NotFound(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{}, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+	preferencesRepositoryMock := mocks.NewPreferencesRepository(t)
+
+	service := NewPreferencesService(preferencesTestConfig(), userRepositoryMock, preferencesRepositoryMock)
+
+	// Act
+	_, err := service.Get(context.Background(), "test-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	assert.Equal(t, "ID test-id not found", err.Error())
+}
+
+// TestUpdatePreferences_Ok checks that Update applies the overrides and resets, and stores the preferences at the next version
+func TestUpdatePreferences_Ok(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{ID: "test-id"}, nil).Once()
+	preferencesRepositoryMock := mocks.NewPreferencesRepository(t)
+	preferencesRepositoryMock.On(testutils.FunctionName(t, ports.PreferencesRepository.GetByUserID), context.Background(), "test-id").Return(entities.Preferences{
+		UserID:   "test-id",
+		Version:  2,
+		Language: ptr("es"),
+		Timezone: ptr("Europe/Madrid"),
+	}, nil).Once()
+	var saved entities.Preferences
+	preferencesRepositoryMock.On(testutils.FunctionName(t, ports.PreferencesRepository.Save), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(entities.Preferences)
+	}).Return(nil).Once()
+
+	service := NewPreferencesService(preferencesTestConfig(), userRepositoryMock, preferencesRepositoryMock)
+	req := models.UpdatePreferencesReq{
+		Version:       ptr(2),
+		Language:      ptr("ca"),
+		Notifications: models.UpdateNotificationPreferencesReq{Marketing: ptr(true)},
+		Reset:         []string{"timezone"},
+	}
+
+	// Act
+	resp, err := service.Update(context.Background(), "test-id", req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 3, saved.Version)
+	assert.Equal(t, "ca", *saved.Language)
+	assert.Nil(t, saved.Timezone)
+	assert.True(t, *saved.Notifications.Marketing)
+	assert.Nil(t, saved.Notifications.Email)
+	assert.False(t, saved.UpdatedAt.IsZero())
+
+	assert.Equal(t, 3, resp.Version)
+	assert.Equal(t, "ca", resp.Language)
+	assert.Equal(t, "UTC", resp.Timezone)
+	assert.Equal(t, models.NotificationPreferencesResp{Email: true, Push: true, Marketing: true}, resp.Notifications)
+}
+
+// TestUpdatePreferences_First checks that Update stores the first version of the preferences when the user has none
+func TestUpdatePreferences_First(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{ID: "test-id"}, nil).Once()
+	preferencesRepositoryMock := mocks.NewPreferencesRepository(t)
+	preferencesRepositoryMock.On(testutils.FunctionName(t, ports.PreferencesRepository.GetByUserID), context.Background(), "test-id").Return(entities.Preferences{}, wrappers.NewNonExistentErr(errors.New("not found"))).Once()
+	preferencesRepositoryMock.On(testutils.FunctionName(t, ports.PreferencesRepository.Save), context.Background(), mock.MatchedBy(func(p entities.Preferences) bool {
+		return p.UserID == "test-id" && p.Version == 1 && *p.Language == "es"
+	})).Return(nil).Once()
+
+	service := NewPreferencesService(preferencesTestConfig(), userRepositoryMock, preferencesRepositoryMock)
+
+	// Act
+	resp, err := service.Update(context.Background(), "test-id", models.UpdatePreferencesReq{Language: ptr("es")})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, resp.Version)
+	assert.Equal(t, "es", resp.Language)
+}
+
+// TestUpdatePreferences_OutdatedVersion checks that Update returns a conflict error when the given version is not the current one
+func TestUpdatePreferences_OutdatedVersion(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{ID: "test-id"}, nil).Once()
+	preferencesRepositoryMock := mocks.NewPreferencesRepository(t)
+	preferencesRepositoryMock.On(testutils.FunctionName(t, ports.PreferencesRepository.GetByUserID), context.Background(), "test-id").Return(entities.Preferences{UserID: "test-id", Version: 4}, nil).Once()
+
+	service := NewPreferencesService(preferencesTestConfig(), userRepositoryMock, preferencesRepositoryMock)
+
+	// Act
+	_, err := service.Update(context.Background(), "test-id", models.UpdatePreferencesReq{Version: ptr(3), Language: ptr("es")})
+
+	// Assert
+	assert.True(t, errors.Is(err, entities.ConflictErr))
+	assert.Equal(t, "version 3 is outdated, the current version of the preferences is 4", err.Error())
+}
+
+// TestUpdatePreferences_ConcurrentUpdate checks that Update returns the conflict error of the repository when the preferences are updated concurrently
+func TestUpdatePreferences_ConcurrentUpdate(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{ID: "test-id"}, nil).Once()
+	preferencesRepositoryMock := mocks.NewPreferencesRepository(t)
+	preferencesRepositoryMock.On(testutils.FunctionName(t, ports.PreferencesRepository.GetByUserID), context.Background(), "test-id").Return(entities.Preferences{UserID: "test-id", Version: 4}, nil).Once()
+	preferencesRepositoryMock.On(testutils.FunctionName(t, ports.PreferencesRepository.Save), context.Background(), mock.Anything).Return(entities.NewOutdatedPreferencesErr(4)).Once()
+
+	service := NewPreferencesService(preferencesTestConfig(), userRepositoryMock, preferencesRepositoryMock)
+
+	// Act
+	_, err := service.Update(context.Background(), "test-id", models.UpdatePreferencesReq{Language: ptr("es")})
+
+	// Assert
+	assert.True(t, errors.Is(err, entities.ConflictErr))
+	assert.Equal(t, "version 4 is outdated, the preferences were updated concurrently", err.Error())
+}
+
+// TestUpdatePreferences_InvalidRequest checks that Update returns a validation error without reading the user when the request is not valid
+func TestUpdatePreferences_InvalidRequest(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	preferencesRepositoryMock := mocks.NewPreferencesRepository(t)
+
+	service := NewPreferencesService(preferencesTestConfig(), userRepositoryMock, preferencesRepositoryMock)
+
+	// Act
+	_, err := service.Update(context.Background(), "test-id", models.UpdatePreferencesReq{Timezone: ptr("Mars/Olympus")})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
+
+// TestExportPreferencesData_Ok checks that ExportUserData returns only the preferences overridden by the user
+func TestExportPreferencesData_Ok(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	preferencesRepositoryMock := mocks.NewPreferencesRepository(t)
+	preferencesRepositoryMock.On(testutils.FunctionName(t, ports.PreferencesRepository.GetByUserID), context.Background(), "test-id").Return(entities.Preferences{UserID: "test-id", Version: 1, Language: ptr("es")}, nil).Once()
+
+	service := NewPreferencesService(preferencesTestConfig(), userRepositoryMock, preferencesRepositoryMock)
+
+	// Act
+	data, err := service.ExportUserData(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	exported := data.(preferencesData)
+	assert.Equal(t, "es", *exported.Language)
+	assert.Nil(t, exported.Timezone)
+}
+
+// TestErasePreferencesData_Ok checks that EraseUserData deletes the preferences of the user
+func TestErasePreferencesData_Ok(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	preferencesRepositoryMock := mocks.NewPreferencesRepository(t)
+	preferencesRepositoryMock.On(testutils.FunctionName(t, ports.PreferencesRepository.DeleteByUserID), context.Background(), "test-id").Return(nil).Once()
+
+	service := NewPreferencesService(preferencesTestConfig(), userRepositoryMock, preferencesRepositoryMock)
+
+	// Act
+	err := service.EraseUserData(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
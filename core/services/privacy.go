@@ -0,0 +1,203 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// erasedEmailDomain domain of the placeholder emails of erased users, reserved so that it can never belong to a real user
+const erasedEmailDomain = "erased.invalid"
+
+// privacyService adapter of a privacy service
+type privacyService struct {
+	config     config.Config
+	users      ports.UserRepository
+	tombstones ports.TombstoneRepository
+	uow        ports.UnitOfWork
+	auditor    auditor
+	stores     []ports.PersonalDataStore
+}
+
+// NewPrivacyService creates a new privacy service, covering the users and every given personal data store.
+// Erasing a user erases its data in every store, anonymizes it and records the tombstone and the audit event in a single unit of work.
+func NewPrivacyService(cfg config.Config, users ports.UserRepository, tombstones ports.TombstoneRepository, audit ports.AuditRepository, uow ports.UnitOfWork, stores ...ports.PersonalDataStore) ports.PrivacyService {
+	return &privacyService{
+		config:     cfg,
+		users:      users,
+		tombstones: tombstones,
+		uow:        uow,
+		auditor: auditor{
+			audit: audit,
+			uow:   uow,
+		},
+		stores: stores,
+	}
+}
+
+// userData exported representation of a user, the password hash is never included in the archives
+type userData struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Surnames  string    `json:"surnames"`
+	Email     string    `json:"email"`
+	ClaimIDs  []int32   `json:"claim_ids"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// archiveManifest describes the contents of a user data archive
+type archiveManifest struct {
+	UserID      string    `json:"user_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Files       []string  `json:"files"`
+}
+
+// ExportUserData builds a zip archive with a JSON document for the user and for each personal data store
+func (s *privacyService) ExportUserData(ctx context.Context, userID string) (archive models.UserDataArchive, err error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	manifest := archiveManifest{
+		UserID:      userID,
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	data := userData{
+		ID:        user.ID,
+		Name:      user.Name,
+		Surnames:  user.Surnames,
+		Email:     user.Email,
+		ClaimIDs:  user.ClaimIDs,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+	if err = writeArchiveFile(zw, &manifest, entities.EntityNameUser, data); err != nil {
+		return
+	}
+
+	for _, store := range s.stores {
+		var storeData interface{}
+		storeData, err = store.ExportUserData(ctx, userID)
+		if err != nil {
+			return
+		}
+		if err = writeArchiveFile(zw, &manifest, store.Name(), storeData); err != nil {
+			return
+		}
+	}
+
+	if err = writeArchiveFile(zw, nil, "manifest", manifest); err != nil {
+		return
+	}
+	if err = zw.Close(); err != nil {
+		return
+	}
+
+	archive = models.UserDataArchive{
+		Content: buf.Bytes(),
+	}
+	return
+}
+
+func writeArchiveFile(zw *zip.Writer, manifest *archiveManifest, name string, data interface{}) error {
+	fileName := name + ".json"
+	w, err := zw.Create(fileName)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(data); err != nil {
+		return err
+	}
+
+	if manifest != nil {
+		manifest.Files = append(manifest.Files, fileName)
+	}
+	return nil
+}
+
+// EraseUser erases the data of the user in every personal data store, anonymizes the user keeping its ID
+// so that references to it remain valid, and records a tombstone of the erasure, all of it in a single unit of work.
+// The pending email changes and invitations of the user are cancelled along with the rest of its data.
+// Stores outside the databases, such as the avatar storage, cannot be rolled back, so a failed erasure has to be retried.
+func (s *privacyService) EraseUser(ctx context.Context, userID, erasedBy string) (resp models.EraseUserResp, err error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return
+	}
+
+	if strings.HasSuffix(user.Email, "@"+erasedEmailDomain) {
+		err = wrappers.NewValidationErr(fmt.Errorf("user %s was already erased", userID))
+		return
+	}
+
+	now := time.Now().UTC()
+	var stores []string
+	var id string
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// a retried unit of work erases every store again
+		stores = []string{entities.EntityNameUser}
+		for _, store := range s.stores {
+			if err := store.EraseUserData(ctx, userID); err != nil {
+				return err
+			}
+			stores = append(stores, store.Name())
+		}
+
+		email := fmt.Sprintf("%s@%s", user.ID, erasedEmailDomain)
+		anonymized := entities.User{
+			Email:           email,
+			EmailNormalized: entities.NormalizeEmail(email),
+			ClaimIDs:        []int32{},
+			CreatedAt:       user.CreatedAt,
+			UpdatedAt:       now,
+		}
+		if err := s.users.Update(ctx, userID, anonymized); err != nil {
+			return err
+		}
+
+		var err error
+		id, err = s.tombstones.Create(ctx, entities.Tombstone{
+			UserID:   userID,
+			ErasedBy: erasedBy,
+			Stores:   stores,
+			ErasedAt: now,
+		})
+		if err != nil {
+			return err
+		}
+
+		// the event is recorded once the events targeting the user are redacted, and only holds IDs so that it needs no redaction
+		return s.auditor.record(ctx, models.AuditActionErase, userID, nil, map[string]interface{}{
+			"tombstone_id": id,
+			"stores":       stores,
+		})
+	})
+	if err != nil {
+		return
+	}
+
+	resp = models.EraseUserResp{
+		TombstoneID: id,
+		Stores:      stores,
+		ErasedAt:    now,
+	}
+	return
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewPrivacyService_Ok checks that NewPrivacyService creates a new privacyService struct
+func TestNewPrivacyService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	userRepositoryMock := mocks.NewUserRepository(t)
+	tombstoneRepositoryMock := mocks.NewTombstoneRepository(t)
+
+	// Act
+	service := NewPrivacyService(cfg, userRepositoryMock, tombstoneRepositoryMock, mocks.NewAuditRepository(t), mocks.NewUnitOfWork(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestExportUserData_Ok checks that ExportUserData returns a zip archive with the user, every store and a manifest, without the password hash
+func TestExportUserData_Ok(t *testing.T) {
+	// Arrange
+	user := entities.User{
+		ID:           "test-id",
+		Email:        "test@test.com",
+		PasswordHash: "test-hash",
+	}
+	storeData := map[string]string{"key": "value"}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), user.ID).Return(user, nil).Once()
+
+	storeMock := mocks.NewPersonalDataStore(t)
+	storeMock.On(testutils.FunctionName(t, ports.PersonalDataStore.Name)).Return("test-store")
+	storeMock.On(testutils.FunctionName(t, ports.PersonalDataStore.ExportUserData), context.Background(), user.ID).Return(storeData, nil).Once()
+
+	service := &privacyService{
+		config: config.Config{},
+		users:  userRepositoryMock,
+		uow:    newUnitOfWorkMock(t),
+		stores: []ports.PersonalDataStore{storeMock},
+	}
+
+	// Act
+	archive, err := service.ExportUserData(context.Background(), user.ID)
+
+	// Assert
+	assert.Nil(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(archive.Content), int64(len(archive.Content)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+
+	assert.Len(t, files, 3)
+	assert.Contains(t, string(files["users.json"]), user.Email)
+	assert.NotContains(t, string(files["users.json"]), user.PasswordHash)
+	assert.JSONEq(t, `{"key":"value"}`, string(files["test-store.json"]))
+
+	var manifest archiveManifest
+	if err := json.Unmarshal(files["manifest.json"], &manifest); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, user.ID, manifest.UserID)
+	assert.Equal(t, []string{"users.json", "test-store.json"}, manifest.Files)
+}
+
+// TestExportUserData_GetByIDError checks that ExportUserData returns an error when the GetByID function from the repository fails
+func TestExportUserData_GetByIDError(t *testing.T) {
+	// Arrange
+	expectedError := wrappers.NewNonExistentErr(errors.New("not found"))
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "test-id").Return(entities.User{}, expectedError).Once()
+
+	service := &privacyService{
+		config: config.Config{},
+		users:  userRepositoryMock,
+	}
+
+	// Act
+	_, err := service.ExportUserData(context.Background(), "test-id")
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
+
+// TestEraseUser_Ok checks that EraseUser erases every store, anonymizes the user and records a tombstone
+func TestEraseUser_Ok(t *testing.T) {
+	// Arrange
+	var events []entities.AuditEvent
+	user := entities.User{
+		ID:           "test-id",
+		Name:         "test",
+		Surnames:     "test",
+		Email:        "test@test.com",
+		PasswordHash: "test-hash",
+		ClaimIDs:     []int32{0},
+	}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), user.ID).Return(user, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), user.ID, mock.MatchedBy(func(u entities.User) bool {
+		return u.ID == "" && u.Name == "" && u.Surnames == "" && u.PasswordHash == "" && len(u.ClaimIDs) == 0 && u.Email == "test-id@erased.invalid"
+	})).Return(nil).Once()
+
+	storeMock := mocks.NewPersonalDataStore(t)
+	storeMock.On(testutils.FunctionName(t, ports.PersonalDataStore.Name)).Return("test-store")
+	storeMock.On(testutils.FunctionName(t, ports.PersonalDataStore.EraseUserData), context.Background(), user.ID).Return(nil).Once()
+
+	tombstoneRepositoryMock := mocks.NewTombstoneRepository(t)
+	tombstoneRepositoryMock.On(testutils.FunctionName(t, ports.TombstoneRepository.Create), context.Background(), mock.MatchedBy(func(tombstone entities.Tombstone) bool {
+		return tombstone.UserID == user.ID && tombstone.ErasedBy == "admin-id"
+	})).Return("tombstone-id", nil).Once()
+
+	service := &privacyService{
+		auditor:    newRecordingAuditor(t, &events),
+		config:     config.Config{},
+		users:      userRepositoryMock,
+		tombstones: tombstoneRepositoryMock,
+		uow:        newUnitOfWorkMock(t),
+		stores:     []ports.PersonalDataStore{storeMock},
+	}
+
+	// Act
+	resp, err := service.EraseUser(context.Background(), user.ID, "admin-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "tombstone-id", resp.TombstoneID)
+	assert.Equal(t, []string{"users", "test-store"}, resp.Stores)
+	assert.False(t, resp.ErasedAt.IsZero())
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, string(models.AuditActionErase), events[0].Action)
+		assert.Equal(t, user.ID, events[0].TargetID)
+		assert.Equal(t, "tombstone-id", events[0].After["tombstone_id"])
+		assert.NotContains(t, events[0].After, "email")
+	}
+}
+
+// TestEraseUser_Retried checks that EraseUser lists every store once when its unit of work is retried
+func TestEraseUser_Retried(t *testing.T) {
+	// Arrange
+	var events []entities.AuditEvent
+	user := entities.User{ID: "test-id", Email: "test@test.com"}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), user.ID).Return(user, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), user.ID, mock.Anything).Return(nil).Twice()
+
+	storeMock := mocks.NewPersonalDataStore(t)
+	storeMock.On(testutils.FunctionName(t, ports.PersonalDataStore.Name)).Return("test-store")
+	storeMock.On(testutils.FunctionName(t, ports.PersonalDataStore.EraseUserData), context.Background(), user.ID).Return(nil).Twice()
+
+	var tombstone entities.Tombstone
+	tombstoneRepositoryMock := mocks.NewTombstoneRepository(t)
+	tombstoneRepositoryMock.On(testutils.FunctionName(t, ports.TombstoneRepository.Create), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		tombstone = args.Get(1).(entities.Tombstone)
+	}).Return("tombstone-id", nil).Twice()
+
+	unitOfWorkMock := mocks.NewUnitOfWork(t)
+	unitOfWorkMock.On(testutils.FunctionName(t, ports.UnitOfWork.Do), context.Background(), mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		fn(ctx)
+		return fn(ctx)
+	}).Once()
+
+	service := &privacyService{
+		auditor:    newRecordingAuditor(t, &events),
+		config:     config.Config{},
+		users:      userRepositoryMock,
+		tombstones: tombstoneRepositoryMock,
+		uow:        unitOfWorkMock,
+		stores:     []ports.PersonalDataStore{storeMock},
+	}
+
+	// Act
+	resp, err := service.EraseUser(context.Background(), user.ID, "admin-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"users", "test-store"}, resp.Stores)
+	assert.Equal(t, []string{"users", "test-store"}, tombstone.Stores)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, []string{"users", "test-store"}, events[1].After["stores"])
+	}
+}
+
+// TestEraseUser_AlreadyErased checks that EraseUser returns a validation error when the user was already erased
+func TestEraseUser_AlreadyErased(t *testing.T) {
+	// Arrange
+	user := entities.User{
+		ID:    "test-id",
+		Email: "test-id@erased.invalid",
+	}
+	expectedError := "user test-id was already erased"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), user.ID).Return(user, nil).Once()
+
+	service := &privacyService{
+		config: config.Config{},
+		users:  userRepositoryMock,
+	}
+
+	// Act
+	_, err := service.EraseUser(context.Background(), user.ID, "")
+
+	// Assert
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestEraseUser_StoreError checks that EraseUser returns an error without anonymizing the user when a store fails
+func TestEraseUser_StoreError(t *testing.T) {
+	// Arrange
+	user := entities.User{ID: "test-id", Email: "test@test.com"}
+	expectedError := "store-error"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), user.ID).Return(user, nil).Once()
+
+	storeMock := mocks.NewPersonalDataStore(t)
+	storeMock.On(testutils.FunctionName(t, ports.PersonalDataStore.EraseUserData), context.Background(), user.ID).Return(errors.New(expectedError)).Once()
+
+	service := &privacyService{
+		config: config.Config{},
+		users:  userRepositoryMock,
+		uow:    newUnitOfWorkMock(t),
+		stores: []ports.PersonalDataStore{storeMock},
+	}
+
+	// Act
+	_, err := service.EraseUser(context.Background(), user.ID, "")
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	userRepositoryMock.AssertNotCalled(t, testutils.FunctionName(t, ports.UserRepository.Update))
+}
+
+// TestEraseUser_CreateTombstoneError checks that EraseUser returns an error when the Create function from the tombstone repository fails
+func TestEraseUser_CreateTombstoneError(t *testing.T) {
+	// Arrange
+	user := entities.User{ID: "test-id", Email: "test@test.com"}
+	expectedError := "repository-error"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), user.ID).Return(user, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), user.ID, mock.AnythingOfType("entities.User")).Return(nil).Once()
+
+	tombstoneRepositoryMock := mocks.NewTombstoneRepository(t)
+	tombstoneRepositoryMock.On(testutils.FunctionName(t, ports.TombstoneRepository.Create), context.Background(), mock.AnythingOfType("entities.Tombstone")).Return("", errors.New(expectedError)).Once()
+
+	service := &privacyService{
+		config:     config.Config{},
+		users:      userRepositoryMock,
+		tombstones: tombstoneRepositoryMock,
+		uow:        newUnitOfWorkMock(t),
+	}
+
+	// Act
+	_, err := service.EraseUser(context.Background(), user.ID, "")
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// tokenClaims payload of the single-use tokens, such as the invite tokens
+type tokenClaims struct {
+	ID        string `json:"id"`
+	Nonce     string `json:"nonce"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// tokenSigner signs and parses single-use tokens with a key derived from the JWT secret and the purpose of the tokens,
+// so that they can never be used as JWTs nor for another purpose
+type tokenSigner struct {
+	key  []byte
+	name string
+}
+
+// newTokenSigner creates a token signer for the given purpose, name is the one of the tokens in the errors
+func newTokenSigner(secret, purpose, name string) tokenSigner {
+	key := hmac.New(sha256.New, []byte(secret))
+	key.Write([]byte(purpose))
+
+	return tokenSigner{
+		key:  key.Sum(nil),
+		name: name,
+	}
+}
+
+// sign returns the token of the given claims, as {base64url payload}.{base64url HMAC-SHA256 signature}
+func (s tokenSigner) sign(claims tokenClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded)), nil
+}
+
+// parse returns the claims of the given token, checking its signature and expiration
+func (s tokenSigner) parse(token string) (tokenClaims, error) {
+	invalidErr := wrappers.NewValidationErr(fmt.Errorf("%s is not valid", s.name))
+
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return tokenClaims{}, invalidErr
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, s.mac(encoded)) {
+		return tokenClaims{}, invalidErr
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return tokenClaims{}, invalidErr
+	}
+	var claims tokenClaims
+	if err = json.Unmarshal(payload, &claims); err != nil || claims.ID == "" {
+		return tokenClaims{}, invalidErr
+	}
+
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return tokenClaims{}, wrappers.NewValidationErr(fmt.Errorf("%s has expired", s.name))
+	}
+	return claims, nil
+}
+
+func (s tokenSigner) mac(encoded string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+// committedKey context key of the callbacks to run once the running unit of work commits
+type committedKey struct{}
+
+// committedCallbacks callbacks registered within a unit of work
+type committedCallbacks struct {
+	mu  sync.Mutex
+	fns []func(ctx context.Context) error
+}
+
+func (c *committedCallbacks) add(fn func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fns = append(c.fns, fn)
+}
+
+func (c *committedCallbacks) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fns = nil
+}
+
+// unitOfWork decorator of a unit of work that runs the callbacks registered within it by afterCommit once it commits
+type unitOfWork struct {
+	ports.UnitOfWork
+}
+
+// NewUnitOfWork decorates a unit of work so that the services can defer the side effects of their mutations,
+// such as the notifications sent to the users, until the outermost unit of work commits
+func NewUnitOfWork(uow ports.UnitOfWork) ports.UnitOfWork {
+	return &unitOfWork{
+		UnitOfWork: uow,
+	}
+}
+
+// Do runs fn in the decorated unit of work, and then the callbacks registered within it when it commits.
+// The failures of the callbacks are logged, as the mutations they follow are already committed.
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(committedKey{}).(*committedCallbacks); ok {
+		return u.UnitOfWork.Do(ctx, fn)
+	}
+
+	callbacks := &committedCallbacks{}
+	err := u.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+		// a retried unit of work registers its callbacks again
+		callbacks.reset()
+		return fn(context.WithValue(ctx, committedKey{}, callbacks))
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, fn := range callbacks.fns {
+		if err := fn(ctx); err != nil {
+			observability.Logger().Printf("failed to run a callback of a committed unit of work: %s", err)
+		}
+	}
+	return nil
+}
+
+// afterCommit defers fn until the unit of work running in the context commits, and discards it if it rolls back.
+// When no decorated unit of work is running, fn runs right away and its error is returned.
+func afterCommit(ctx context.Context, fn func(ctx context.Context) error) error {
+	if callbacks, ok := ctx.Value(committedKey{}).(*committedCallbacks); ok {
+		callbacks.add(fn)
+		return nil
+	}
+	return fn(ctx)
+}
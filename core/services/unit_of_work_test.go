@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestUnitOfWorkDo_Committed checks that Do runs the callbacks registered within the outermost unit of work once it commits
+func TestUnitOfWorkDo_Committed(t *testing.T) {
+	// Arrange
+	uow := NewUnitOfWork(newUnitOfWorkMock(t))
+	var calls []string
+
+	// Act
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		err := uow.Do(ctx, func(ctx context.Context) error {
+			return afterCommit(ctx, func(ctx context.Context) error {
+				calls = append(calls, "callback")
+				return nil
+			})
+		})
+		calls = append(calls, "nested")
+		return err
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"nested", "callback"}, calls)
+}
+
+// TestUnitOfWorkDo_RolledBack checks that Do discards the callbacks registered within a unit of work that does not commit
+func TestUnitOfWorkDo_RolledBack(t *testing.T) {
+	// Arrange
+	uow := NewUnitOfWork(newUnitOfWorkMock(t))
+	expectedError := "test-error"
+	called := false
+
+	// Act
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		afterCommit(ctx, func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+		return errors.New(expectedError)
+	})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	assert.False(t, called)
+}
+
+// TestUnitOfWorkDo_Retried checks that Do only runs the callbacks registered by the last run of a retried unit of work
+func TestUnitOfWorkDo_Retried(t *testing.T) {
+	// Arrange
+	uowMock := mocks.NewUnitOfWork(t)
+	uowMock.On(testutils.FunctionName(t, ports.UnitOfWork.Do), mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		fn(ctx)
+		return fn(ctx)
+	}).Once()
+	uow := NewUnitOfWork(uowMock)
+	calls := 0
+
+	// Act
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		return afterCommit(ctx, func(ctx context.Context) error {
+			calls++
+			return nil
+		})
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+// TestUnitOfWorkDo_CallbackError checks that Do does not fail when a callback fails, as the unit of work is already committed
+func TestUnitOfWorkDo_CallbackError(t *testing.T) {
+	// Arrange
+	uow := NewUnitOfWork(newUnitOfWorkMock(t))
+	calls := 0
+
+	// Act
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		for i := 0; i < 2; i++ {
+			afterCommit(ctx, func(ctx context.Context) error {
+				calls++
+				return errors.New("callback error")
+			})
+		}
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+// TestAfterCommit_NoUnitOfWork checks that afterCommit runs the callback right away when no unit of work is running
+func TestAfterCommit_NoUnitOfWork(t *testing.T) {
+	// Arrange
+	expectedError := "test-error"
+
+	// Act
+	err := afterCommit(context.Background(), func(ctx context.Context) error {
+		return errors.New(expectedError)
+	})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
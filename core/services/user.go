@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -15,37 +16,66 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// defaultSearchPageSize page size applied when none is requested
+	defaultSearchPageSize = 20
+	// maxSearchPageSize maximum page size that can be requested
+	maxSearchPageSize = 100
+	// userChangesStoreName name of the recorded changes of the users as a personal data store
+	userChangesStoreName = "user_changes"
+)
+
 // userService adapter of an user service
 type userService struct {
-	config     config.Config
-	repository ports.UserRepository
+	config       config.Config
+	repository   ports.UserRepository
+	outbox       ports.OutboxRepository
+	watcher      ports.UserWatcher
+	emailChanges ports.EmailChangeService
+	uow          ports.UnitOfWork
 }
 
-// NewUserService creates a new user service
-func NewUserService(cfg config.Config, repo ports.UserRepository) ports.UserService {
+// NewUserService creates a new user service.
+// The events of the user changes are written to the outbox by the repository, in the same transaction as the changes,
+// while the events without changes, such as logins, are written by the service.
+// Emails are only changed once confirmed from the new email, through the email change service.
+// Updates read and write the users in a unit of work, so that no concurrent change is overwritten in between.
+func NewUserService(cfg config.Config, repo ports.UserRepository, outbox ports.OutboxRepository, watcher ports.UserWatcher, emailChanges ports.EmailChangeService, uow ports.UnitOfWork) ports.UserService {
 	return &userService{
-		config:     cfg,
-		repository: repo,
+		config:       cfg,
+		repository:   repo,
+		outbox:       outbox,
+		watcher:      watcher,
+		emailChanges: emailChanges,
+		uow:          uow,
 	}
 }
 
-// Login user
+// Login user.
+// The credentials are read within a unit of work, as the cached users hold no password hashes.
 func (s *userService) Login(ctx context.Context, credentials models.LoginUserReq) (resp models.LoginUserResp, err error) {
-	user, err := s.validateLogin(ctx, credentials)
-	if err != nil {
-		return
-	}
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		user, err := s.validateLogin(ctx, credentials)
+		if err != nil {
+			return err
+		}
 
-	token, err := createToken(user.ID, s.config.JWTSecret, user.ClaimIDs)
-	if err != nil {
-		return
-	}
+		token, err := createToken(user.ID, s.config.JWTSecret, user.ClaimIDs)
+		if err != nil {
+			return err
+		}
 
-	resp = models.LoginUserResp{
-		User:  user,
-		Token: token,
-	}
+		err = s.outbox.Add(ctx, entities.NewUserEvent(entities.EventTypeUserLoggedIn, user.ID, nil))
+		if err != nil {
+			return err
+		}
 
+		resp = models.LoginUserResp{
+			User:  user,
+			Token: token,
+		}
+		return nil
+	})
 	return
 }
 
@@ -59,6 +89,11 @@ func (s *userService) validateLogin(ctx context.Context, credentials models.Logi
 		return models.GetUserResp{}, err
 	}
 
+	// invited users have no password until they accept their invitation
+	if user.PasswordHash == "" {
+		return models.GetUserResp{}, wrappers.NewValidationErr(fmt.Errorf("invitation of email %s is pending", credentials.Email))
+	}
+
 	err = validatePassword(credentials.Password, user.PasswordHash)
 	if err != nil {
 		return models.GetUserResp{}, err
@@ -139,13 +174,14 @@ func (s *userService) createUserEntity(user models.CreateUserReq, creationTime t
 	}
 
 	entity = entities.User{
-		Name:         user.Name,
-		Surnames:     user.Surnames,
-		Email:        user.Email,
-		PasswordHash: hash,
-		ClaimIDs:     user.ClaimIDs,
-		CreatedAt:    creationTime,
-		UpdatedAt:    creationTime,
+		Name:            user.Name,
+		Surnames:        user.Surnames,
+		Email:           user.Email,
+		EmailNormalized: entities.NormalizeEmail(user.Email),
+		PasswordHash:    hash,
+		ClaimIDs:        user.ClaimIDs,
+		CreatedAt:       creationTime,
+		UpdatedAt:       creationTime,
 	}
 	return
 }
@@ -160,8 +196,13 @@ func hashPassword(password string) (string, error) {
 }
 
 // CreateMany users
-func (s *userService) CreateMany(ctx context.Context, users []models.CreateUserReq) (resp models.CreateManyUserResp, err error) {
-	var create []interface{}
+func (s *userService) CreateMany(ctx context.Context, users []models.CreateUserReq, partial bool) (resp models.CreateManyUserResp, err error) {
+	if partial {
+		resp = s.createManyPartial(ctx, users)
+		return
+	}
+
+	var create []entities.User
 	var entity entities.User
 	creationTime := time.Now().UTC()
 
@@ -179,14 +220,43 @@ func (s *userService) CreateMany(ctx context.Context, users []models.CreateUserR
 	}
 
 	resp = models.CreateManyUserResp{
-		IDs: ids,
+		IDs:     ids,
+		Results: successfulResults(ids),
+	}
+	return
+}
+
+func (s *userService) createManyPartial(ctx context.Context, users []models.CreateUserReq) (resp models.CreateManyUserResp) {
+	creationTime := time.Now().UTC()
+
+	for i, user := range users {
+		result := models.BulkItemResult{Index: i}
+
+		entity, err := s.createUserEntity(user, creationTime)
+		if err == nil {
+			result.ID, err = s.repository.Create(ctx, entity)
+		}
+		if err == nil {
+			resp.IDs = append(resp.IDs, result.ID)
+		}
+
+		result.Err = err
+		resp.Results = append(resp.Results, result)
 	}
 	return
 }
 
+func successfulResults(IDs []string) []models.BulkItemResult {
+	results := make([]models.BulkItemResult, len(IDs))
+	for i, ID := range IDs {
+		results[i] = models.BulkItemResult{Index: i, ID: ID}
+	}
+	return results
+}
+
 // GetAll users
 func (s *userService) GetAll(ctx context.Context) (resp []models.GetUserResp, err error) {
-	result, err := s.repository.Get(ctx, map[string]interface{}{}, nil, nil)
+	result, err := s.repository.Get(ctx, models.UserFilter{}, nil, nil)
 	if err != nil {
 		if errors.Is(err, wrappers.NonExistentErr) {
 			err = nil
@@ -196,7 +266,7 @@ func (s *userService) GetAll(ctx context.Context) (resp []models.GetUserResp, er
 
 	resp = make([]models.GetUserResp, len(result))
 	for i, v := range result {
-		resp[i] = models.GetUserResp(*(v.(*entities.User)))
+		resp[i] = models.GetUserResp(v)
 	}
 
 	return
@@ -204,7 +274,7 @@ func (s *userService) GetAll(ctx context.Context) (resp []models.GetUserResp, er
 
 // GetByEmail user
 func (s *userService) GetByEmail(ctx context.Context, email string) (resp models.GetUserResp, err error) {
-	filter := map[string]interface{}{"email": email}
+	filter := models.UserFilter{EmailNormalized: entities.NormalizeEmail(email)}
 	result, err := s.repository.Get(ctx, filter, nil, nil)
 	if err != nil {
 		if errors.Is(err, wrappers.NonExistentErr) {
@@ -213,7 +283,7 @@ func (s *userService) GetByEmail(ctx context.Context, email string) (resp models
 		return
 	}
 
-	resp = models.GetUserResp(*(result[0].(*entities.User)))
+	resp = models.GetUserResp(result[0])
 
 	return
 }
@@ -228,13 +298,83 @@ func (s *userService) GetByID(ctx context.Context, ID string) (resp models.GetUs
 		return
 	}
 
-	resp = models.GetUserResp(*user.(*entities.User))
+	resp = models.GetUserResp(user)
+
+	return
+}
+
+// Search returns a page of the users matching the query, from the most to the least relevant
+func (s *userService) Search(ctx context.Context, req models.SearchUsersReq) (resp models.SearchUsersResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = defaultSearchPageSize
+	}
+	if pageSize < 0 || pageSize > maxSearchPageSize {
+		err = wrappers.NewValidationErr(fmt.Errorf("page size must be between 1 and %d", maxSearchPageSize))
+		return
+	}
+
+	offset := 0
+	if req.PageToken != "" {
+		offset, err = strconv.Atoi(req.PageToken)
+		if err != nil || offset < 0 {
+			err = wrappers.NewValidationErr(fmt.Errorf("page token %s is not valid", req.PageToken))
+			return
+		}
+	}
+
+	// one more user is requested to know whether there is a next page
+	users, err := s.repository.Search(ctx, req.Query, offset, pageSize+1)
+	if err != nil {
+		return
+	}
+
+	if len(users) > pageSize {
+		users = users[:pageSize]
+		resp.NextPageToken = strconv.Itoa(offset + pageSize)
+	}
 
+	resp.Users = []models.GetUserResp{}
+	for _, user := range users {
+		resp.Users = append(resp.Users, models.GetUserResp(user))
+	}
 	return
 }
 
 // Update user
 func (s *userService) Update(ctx context.Context, ID string, user models.UpdateUserReq) (err error) {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		entity, err := s.updateUserEntity(ctx, ID, user, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if err = s.repository.Update(ctx, ID, entity); err != nil {
+			return err
+		}
+		return s.requestEmailChange(ctx, ID, user, entity)
+	})
+}
+
+// requestEmailChange requests the change of the email of the user when it is not only a change of case,
+// as the new email does not replace the current one until confirmed.
+// It runs in the unit of work of the update, so that the update is rolled back when the new email is already in use,
+// while the notifications of the change are only sent once it commits.
+func (s *userService) requestEmailChange(ctx context.Context, ID string, user models.UpdateUserReq, entity entities.User) error {
+	if user.Email == nil || entities.NormalizeEmail(*user.Email) == entity.EmailNormalized {
+		return nil
+	}
+	return s.emailChanges.Request(ctx, ID, *user.Email)
+}
+
+func (s *userService) updateUserEntity(ctx context.Context, ID string, user models.UpdateUserReq, updateTime time.Time) (entity entities.User, err error) {
+	if err = user.Validate(); err != nil {
+		return
+	}
+
 	dbUser, err := s.GetByID(ctx, ID)
 	if err != nil {
 		return
@@ -246,7 +386,8 @@ func (s *userService) Update(ctx context.Context, ID string, user models.UpdateU
 	if user.Surnames != nil {
 		dbUser.Surnames = *user.Surnames
 	}
-	if user.Email != nil {
+	dbUser.EmailNormalized = entities.NormalizeEmail(dbUser.Email)
+	if user.Email != nil && entities.NormalizeEmail(*user.Email) == dbUser.EmailNormalized {
 		dbUser.Email = *user.Email
 	}
 	if user.NewPassword != nil {
@@ -266,15 +407,70 @@ func (s *userService) Update(ctx context.Context, ID string, user models.UpdateU
 	if user.ClaimIDs != nil {
 		err = validateClaims(*user.ClaimIDs)
 		if err != nil {
-			return err
+			return
 		}
 		dbUser.ClaimIDs = *user.ClaimIDs
 	}
 	dbUser.ID = ""
-	dbUser.UpdatedAt = time.Now().UTC()
+	dbUser.UpdatedAt = updateTime
+
+	entity = entities.User(dbUser)
+	return
+}
+
+// UpdateMany users
+func (s *userService) UpdateMany(ctx context.Context, users []models.UpdateManyUserReq, partial bool) (resp models.BulkUserResp, err error) {
+	// every item of a partial update is applied in its own unit of work by Update
+	if partial {
+		for i, user := range users {
+			resp.Results = append(resp.Results, models.BulkItemResult{
+				Index: i,
+				ID:    user.ID,
+				Err:   s.Update(ctx, user.ID, user.UpdateUserReq),
+			})
+		}
+		return
+	}
+
+	var IDs []string
+	updateTime := time.Now().UTC()
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// a retried unit of work reads the users again
+		IDs = nil
+		var update []entities.User
+		for _, user := range users {
+			entity, err := s.updateUserEntity(ctx, user.ID, user.UpdateUserReq, updateTime)
+			if err != nil {
+				return err
+			}
+			IDs = append(IDs, user.ID)
+			update = append(update, entity)
+		}
+		if err := s.repository.UpdateMany(ctx, IDs, update); err != nil {
+			return err
+		}
+
+		for i, user := range users {
+			if err := s.requestEmailChange(ctx, user.ID, user.UpdateUserReq, update[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	resp = models.BulkUserResp{
+		Results: successfulResults(IDs),
+	}
+	return
+}
 
-	err = s.repository.Update(ctx, ID, entities.User(dbUser))
-	return err
+// ConfirmEmailChange of a user, replacing its email by the confirmed one
+func (s *userService) ConfirmEmailChange(ctx context.Context, req models.ConfirmEmailChangeReq) (models.ConfirmEmailChangeResp, error) {
+	return s.emailChanges.Confirm(ctx, req)
 }
 
 // Delete user
@@ -287,8 +483,105 @@ func (s *userService) Delete(ctx context.Context, ID string) (err error) {
 	return
 }
 
+// DeleteMany users
+func (s *userService) DeleteMany(ctx context.Context, IDs []string, partial bool) (resp models.BulkUserResp, err error) {
+	if partial {
+		for i, ID := range IDs {
+			resp.Results = append(resp.Results, models.BulkItemResult{
+				Index: i,
+				ID:    ID,
+				Err: s.uow.Do(ctx, func(ctx context.Context) error {
+					return s.Delete(ctx, ID)
+				}),
+			})
+		}
+		return
+	}
+
+	err = s.repository.DeleteMany(ctx, IDs)
+	if err != nil {
+		return
+	}
+
+	resp = models.BulkUserResp{
+		Results: successfulResults(IDs),
+	}
+	return
+}
+
 // GetClaims user
 func (s *userService) GetUserClaims(ctx context.Context) (claims map[int]string) {
 	claims = entities.GetUserClaims()
 	return
 }
+
+// Watch user changes after the resume token, or after now when empty, until the context is done
+func (s *userService) Watch(ctx context.Context, resumeToken string, fn func(change models.UserChangeResp) error) error {
+	return s.watcher.Watch(ctx, resumeToken, func(change entities.UserChange) error {
+		resp := models.UserChangeResp{
+			ResumeToken: change.ResumeToken,
+			Type:        string(change.Type),
+			UserID:      change.UserID,
+			OccurredAt:  change.OccurredAt,
+		}
+		if change.User != nil {
+			user := models.GetUserResp(*change.User)
+			resp.User = &user
+		}
+		return fn(resp)
+	})
+}
+
+// Name of the recorded changes of the users as a personal data store
+func (s *userService) Name() string {
+	return userChangesStoreName
+}
+
+// userChangeData exported representation of a recorded change of a user, along with the state of the user it left, if still recorded
+type userChangeData struct {
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	User       *userData `json:"user"`
+}
+
+// ExportUserData returns the recorded changes of the user, along with the states of the user they left
+func (s *userService) ExportUserData(ctx context.Context, userID string) (interface{}, error) {
+	changes, err := s.watcher.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]userChangeData, 0, len(changes))
+	for _, change := range changes {
+		changeData := userChangeData{
+			Type:       string(change.Type),
+			OccurredAt: change.OccurredAt,
+		}
+		if change.User != nil {
+			changeData.User = &userData{
+				ID:        change.User.ID,
+				Name:      change.User.Name,
+				Surnames:  change.User.Surnames,
+				Email:     change.User.Email,
+				ClaimIDs:  change.User.ClaimIDs,
+				CreatedAt: change.User.CreatedAt,
+				UpdatedAt: change.User.UpdatedAt,
+			}
+		}
+		data = append(data, changeData)
+	}
+	return data, nil
+}
+
+// EraseUserData clears the states of the user recorded along with its changes, which are kept so that watching can be resumed
+func (s *userService) EraseUserData(ctx context.Context, userID string) error {
+	return s.watcher.EraseStates(ctx, userID)
+}
+
+// PruneChanges deletes the user changes older than the retention, after which watching can no longer be resumed
+func (s *userService) PruneChanges(ctx context.Context) (int, error) {
+	if s.config.UserChanges.Retention.Duration <= 0 {
+		return 0, nil
+	}
+	return s.watcher.Prune(ctx, time.Now().UTC().Add(-s.config.UserChanges.Retention.Duration))
+}
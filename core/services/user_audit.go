@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// redactedValue placeholder of the secrets in the audit events
+const redactedValue = "[REDACTED]"
+
+// auditedUserService decorator of a user service that records an audit event for every mutation
+type auditedUserService struct {
+	ports.UserService
+	auditor
+}
+
+// NewAuditedUserService decorates a user service so that its mutations get audited.
+// The actor and request ID of the events are taken from the audit metadata of the context.
+// Mutations are applied in a single unit of work along with their audit events. Partial bulk mutations apply every item
+// in its own unit of work along with its event, and imports every batch along with the events of its users,
+// so that an item whose event cannot be recorded fails as any other failing item.
+func NewAuditedUserService(svc ports.UserService, audit ports.AuditRepository, uow ports.UnitOfWork) ports.UserService {
+	return &auditedUserService{
+		UserService: svc,
+		auditor: auditor{
+			audit: audit,
+			uow:   uow,
+		},
+	}
+}
+
+// Create user and audit it
+func (s *auditedUserService) Create(ctx context.Context, user models.CreateUserReq) (resp models.CreateUserResp, err error) {
+	err = s.audited(ctx, func(ctx context.Context) (err error) {
+		resp, err = s.UserService.Create(ctx, user)
+		return
+	}, func(ctx context.Context) error {
+		return s.record(ctx, models.AuditActionCreate, resp.ID, nil, createdSnapshot(resp.ID, user))
+	})
+	return
+}
+
+// CreateMany users and audit every created one
+func (s *auditedUserService) CreateMany(ctx context.Context, users []models.CreateUserReq, partial bool) (resp models.CreateManyUserResp, err error) {
+	if partial {
+		for i, user := range users {
+			result := models.BulkItemResult{Index: i}
+			var created models.CreateUserResp
+			created, result.Err = s.Create(ctx, user)
+			if result.Err == nil {
+				result.ID = created.ID
+				resp.IDs = append(resp.IDs, created.ID)
+			}
+			resp.Results = append(resp.Results, result)
+		}
+		return
+	}
+
+	err = s.audited(ctx, func(ctx context.Context) (err error) {
+		resp, err = s.UserService.CreateMany(ctx, users, partial)
+		return
+	}, func(ctx context.Context) (err error) {
+		for _, result := range resp.Results {
+			if result.Err == nil {
+				err = errors.Join(err, s.record(ctx, models.AuditActionCreate, result.ID, nil, createdSnapshot(result.ID, users[result.Index])))
+			}
+		}
+		return
+	})
+	return
+}
+
+// UpdateMany users and audit every updated one
+func (s *auditedUserService) UpdateMany(ctx context.Context, users []models.UpdateManyUserReq, partial bool) (resp models.BulkUserResp, err error) {
+	if partial {
+		for i, user := range users {
+			resp.Results = append(resp.Results, models.BulkItemResult{
+				Index: i,
+				ID:    user.ID,
+				Err:   s.Update(ctx, user.ID, user.UpdateUserReq),
+			})
+		}
+		return
+	}
+
+	befores := make([]map[string]interface{}, len(users))
+	for i, user := range users {
+		befores[i] = s.snapshot(ctx, user.ID)
+	}
+
+	err = s.audited(ctx, func(ctx context.Context) (err error) {
+		resp, err = s.UserService.UpdateMany(ctx, users, partial)
+		return
+	}, func(ctx context.Context) (err error) {
+		for _, result := range resp.Results {
+			if result.Err == nil {
+				user := users[result.Index]
+				before := befores[result.Index]
+				err = errors.Join(err, s.record(ctx, updateAction(user.UpdateUserReq, before), user.ID, before, s.snapshot(ctx, user.ID)))
+			}
+		}
+		return
+	})
+	return
+}
+
+// DeleteMany users and audit every deleted one
+func (s *auditedUserService) DeleteMany(ctx context.Context, IDs []string, partial bool) (resp models.BulkUserResp, err error) {
+	if partial {
+		for i, ID := range IDs {
+			resp.Results = append(resp.Results, models.BulkItemResult{
+				Index: i,
+				ID:    ID,
+				Err:   s.Delete(ctx, ID),
+			})
+		}
+		return
+	}
+
+	befores := make([]map[string]interface{}, len(IDs))
+	for i, ID := range IDs {
+		befores[i] = s.snapshot(ctx, ID)
+	}
+
+	err = s.audited(ctx, func(ctx context.Context) (err error) {
+		resp, err = s.UserService.DeleteMany(ctx, IDs, partial)
+		return
+	}, func(ctx context.Context) (err error) {
+		for _, result := range resp.Results {
+			if result.Err == nil {
+				err = errors.Join(err, s.record(ctx, models.AuditActionDelete, result.ID, befores[result.Index], nil))
+			}
+		}
+		return
+	})
+	return
+}
+
+// Import users and audit every imported one, dry runs are not audited since they do not mutate any user.
+// Every insertion of the import, either a batch or a single row, is applied in a unit of work along with the events of its users.
+func (s *auditedUserService) Import(ctx context.Context, req models.ImportUsersReq) (models.ImportUsersResp, error) {
+	if req.DryRun {
+		return s.UserService.Import(ctx, req)
+	}
+
+	req.Apply = func(ctx context.Context, insert models.ImportInsertFunc) error {
+		return s.uow.Do(ctx, func(ctx context.Context) error {
+			imported, err := insert(ctx)
+			if err != nil {
+				return err
+			}
+
+			for _, result := range imported {
+				after := map[string]interface{}{
+					"id":     result.ID,
+					"row":    result.Index,
+					"format": string(req.Format),
+				}
+				if err = s.record(ctx, models.AuditActionImport, result.ID, nil, after); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	return s.UserService.Import(ctx, req)
+}
+
+// Update user and audit it
+func (s *auditedUserService) Update(ctx context.Context, ID string, user models.UpdateUserReq) (err error) {
+	before := s.snapshot(ctx, ID)
+
+	return s.audited(ctx, func(ctx context.Context) error {
+		return s.UserService.Update(ctx, ID, user)
+	}, func(ctx context.Context) error {
+		return s.record(ctx, updateAction(user, before), ID, before, s.snapshot(ctx, ID))
+	})
+}
+
+// ConfirmEmailChange of a user and audit it. The user is only known once the token is verified,
+// so the event records the change of the email instead of the whole state of the user.
+func (s *auditedUserService) ConfirmEmailChange(ctx context.Context, req models.ConfirmEmailChangeReq) (resp models.ConfirmEmailChangeResp, err error) {
+	err = s.audited(ctx, func(ctx context.Context) (err error) {
+		resp, err = s.UserService.ConfirmEmailChange(ctx, req)
+		return
+	}, func(ctx context.Context) error {
+		return s.record(ctx, models.AuditActionUpdate, resp.UserID, map[string]interface{}{"email": resp.PreviousEmail}, map[string]interface{}{"email": resp.Email})
+	})
+	return
+}
+
+// Delete user and audit it
+func (s *auditedUserService) Delete(ctx context.Context, ID string) (err error) {
+	before := s.snapshot(ctx, ID)
+
+	return s.audited(ctx, func(ctx context.Context) error {
+		return s.UserService.Delete(ctx, ID)
+	}, func(ctx context.Context) error {
+		return s.record(ctx, models.AuditActionDelete, ID, before, nil)
+	})
+}
+
+// snapshot returns the redacted state of a user, or nil if it cannot be read.
+// The states before the mutations are read outside of their unit of work, as a failing read would abort the transactions of some databases.
+func (s *auditedUserService) snapshot(ctx context.Context, ID string) map[string]interface{} {
+	user, err := s.UserService.GetByID(ctx, ID)
+	if err != nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"id":            user.ID,
+		"name":          user.Name,
+		"surnames":      user.Surnames,
+		"email":         user.Email,
+		"password_hash": redactedValue,
+		"claim_ids":     user.ClaimIDs,
+		"created_at":    user.CreatedAt,
+		"updated_at":    user.UpdatedAt,
+	}
+}
+
+// createdSnapshot returns the redacted state of a user created from the given request
+func createdSnapshot(ID string, user models.CreateUserReq) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            ID,
+		"name":          user.Name,
+		"surnames":      user.Surnames,
+		"email":         user.Email,
+		"password_hash": redactedValue,
+		"claim_ids":     user.ClaimIDs,
+	}
+}
+
+// updateAction returns update_claims when the update changes the claims of the user, and update otherwise
+func updateAction(user models.UpdateUserReq, before map[string]interface{}) models.AuditAction {
+	if user.ClaimIDs == nil {
+		return models.AuditActionUpdate
+	}
+
+	claimIDs, _ := before["claim_ids"].([]int32)
+	if slices.Equal(claimIDs, *user.ClaimIDs) {
+		return models.AuditActionUpdate
+	}
+	return models.AuditActionUpdateClaims
+}
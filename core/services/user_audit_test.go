@@ -0,0 +1,492 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newUnitOfWorkMock returns a unit of work mock that runs the given functions
+func newUnitOfWorkMock(t *testing.T) *mocks.UnitOfWork {
+	uow := mocks.NewUnitOfWork(t)
+	uow.On(testutils.FunctionName(t, ports.UnitOfWork.Do), mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}).Maybe()
+	return uow
+}
+
+// TestNewAuditedUserService_Ok checks that NewAuditedUserService creates a new auditedUserService struct
+func TestNewAuditedUserService_Ok(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+
+	// Act
+	service := NewAuditedUserService(userServiceMock, auditRepositoryMock, mocks.NewUnitOfWork(t))
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestAuditedCreate_Ok checks that Create records an audit event with the actor and request ID of the context and the password redacted
+func TestAuditedCreate_Ok(t *testing.T) {
+	// Arrange
+	ctx := models.WithAuditMetadata(context.Background(), models.AuditMetadata{ActorID: "test-actor", RequestID: "test-request"})
+	req := models.CreateUserReq{Email: "test@test.com", Password: "test-password"}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Create), ctx, req).Return(models.CreateUserResp{ID: "test-id"}, nil).Once()
+
+	var event entities.AuditEvent
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Create), ctx, mock.Anything).Run(func(args mock.Arguments) {
+		event = args.Get(1).(entities.AuditEvent)
+	}).Return("event-id", nil).Once()
+
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: auditRepositoryMock, uow: newUnitOfWorkMock(t)},
+	}
+
+	// Act
+	resp, err := service.Create(ctx, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", resp.ID)
+	assert.Equal(t, "test-actor", event.ActorID)
+	assert.Equal(t, "test-request", event.RequestID)
+	assert.Equal(t, string(models.AuditActionCreate), event.Action)
+	assert.Equal(t, "test-id", event.TargetID)
+	assert.Nil(t, event.Before)
+	assert.Equal(t, redactedValue, event.After["password_hash"])
+	assert.NotContains(t, event.After, "password")
+}
+
+// TestAuditedCreate_CreateError checks that Create does not record any audit event when the user is not created
+func TestAuditedCreate_CreateError(t *testing.T) {
+	// Arrange
+	expectedError := "create error"
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Create), mock.Anything, mock.Anything).Return(models.CreateUserResp{}, errors.New(expectedError)).Once()
+
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: mocks.NewAuditRepository(t), uow: newUnitOfWorkMock(t)},
+	}
+
+	// Act
+	_, err := service.Create(context.Background(), models.CreateUserReq{})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestAuditedCreate_RecordError checks that Create fails, rolling back the created user, when the audit event cannot be recorded
+func TestAuditedCreate_RecordError(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Create), mock.Anything, mock.Anything).Return(models.CreateUserResp{ID: "test-id"}, nil).Once()
+
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Create), mock.Anything, mock.Anything).Return("", errors.New("record error")).Once()
+
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: auditRepositoryMock, uow: newUnitOfWorkMock(t)},
+	}
+
+	// Act
+	_, err := service.Create(context.Background(), models.CreateUserReq{})
+
+	// Assert
+	assert.EqualError(t, err, "failed to record audit event create of user test-id: record error")
+}
+
+// TestAuditedCreateMany_Partial checks that a partial CreateMany creates every user in its own unit of work along with its audit event,
+// recording an audit event only for the created users
+func TestAuditedCreateMany_Partial(t *testing.T) {
+	// Arrange
+	users := []models.CreateUserReq{{Email: "test1@test.com"}, {Email: "test2@test.com"}}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Create), mock.Anything, users[0]).Return(models.CreateUserResp{}, errors.New("create error")).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Create), mock.Anything, users[1]).Return(models.CreateUserResp{ID: "test-id"}, nil).Once()
+
+	var events []entities.AuditEvent
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Create), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		events = append(events, args.Get(1).(entities.AuditEvent))
+	}).Return("event-id", nil).Once()
+
+	uow := newUnitOfWorkMock(t)
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: auditRepositoryMock, uow: uow},
+	}
+
+	// Act
+	resp, err := service.CreateMany(context.Background(), users, true)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"test-id"}, resp.IDs)
+	if assert.Len(t, resp.Results, 2) {
+		assert.EqualError(t, resp.Results[0].Err, "create error")
+		assert.Equal(t, "test-id", resp.Results[1].ID)
+	}
+	assert.Len(t, events, 1)
+	assert.Equal(t, "test-id", events[0].TargetID)
+	assert.Equal(t, users[1].Email, events[0].After["email"])
+	uow.AssertNumberOfCalls(t, testutils.FunctionName(t, ports.UnitOfWork.Do), 2)
+}
+
+// TestAuditedCreateMany_RecordError checks that CreateMany fails, rolling back the created users, when an audit event cannot be recorded
+func TestAuditedCreateMany_RecordError(t *testing.T) {
+	// Arrange
+	users := []models.CreateUserReq{{Email: "test1@test.com"}}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.CreateMany), mock.Anything, users, false).Return(models.CreateManyUserResp{
+		IDs:     []string{"test-id"},
+		Results: []models.BulkItemResult{{Index: 0, ID: "test-id"}},
+	}, nil).Once()
+
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Create), mock.Anything, mock.Anything).Return("", errors.New("record error")).Once()
+
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: auditRepositoryMock, uow: newUnitOfWorkMock(t)},
+	}
+
+	// Act
+	_, err := service.CreateMany(context.Background(), users, false)
+
+	// Assert
+	assert.EqualError(t, err, "failed to record audit event create of user test-id: record error")
+}
+
+// TestAuditedCreateMany_PartialRecordError checks that a partial CreateMany reports as failed, rolling it back,
+// a user whose audit event cannot be recorded
+func TestAuditedCreateMany_PartialRecordError(t *testing.T) {
+	// Arrange
+	users := []models.CreateUserReq{{Email: "test1@test.com"}}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Create), mock.Anything, users[0]).Return(models.CreateUserResp{ID: "test-id"}, nil).Once()
+
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Create), mock.Anything, mock.Anything).Return("", errors.New("record error")).Once()
+
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: auditRepositoryMock, uow: newUnitOfWorkMock(t)},
+	}
+
+	// Act
+	resp, err := service.CreateMany(context.Background(), users, true)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, resp.IDs)
+	if assert.Len(t, resp.Results, 1) {
+		assert.EqualError(t, resp.Results[0].Err, "failed to record audit event create of user test-id: record error")
+	}
+}
+
+// TestAuditedDeleteMany_Partial checks that a partial DeleteMany deletes every user in its own unit of work along with its audit event
+func TestAuditedDeleteMany_Partial(t *testing.T) {
+	// Arrange
+	IDs := []string{"test-id-1", "test-id-2"}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, mock.Anything).Return(models.GetUserResp{}, nil).Times(2)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Delete), mock.Anything, IDs[0]).Return(nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Delete), mock.Anything, IDs[1]).Return(errors.New("delete error")).Once()
+
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Create), mock.Anything, mock.Anything).Return("event-id", nil).Once()
+
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: auditRepositoryMock, uow: newUnitOfWorkMock(t)},
+	}
+
+	// Act
+	resp, err := service.DeleteMany(context.Background(), IDs, true)
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, resp.Results, 2) {
+		assert.Nil(t, resp.Results[0].Err)
+		assert.EqualError(t, resp.Results[1].Err, "delete error")
+	}
+}
+
+// TestAuditedUpdate_Ok checks that Update records an audit event with the state of the user before and after the update
+func TestAuditedUpdate_Ok(t *testing.T) {
+	// Arrange
+	name := "new-name"
+	req := models.UpdateUserReq{Name: &name}
+	before := models.GetUserResp{ID: "test-id", Name: "old-name", PasswordHash: "test-hash"}
+	after := models.GetUserResp{ID: "test-id", Name: name, PasswordHash: "test-hash"}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(before, nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-id", req).Return(nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(after, nil).Once()
+
+	var event entities.AuditEvent
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Create), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		event = args.Get(1).(entities.AuditEvent)
+	}).Return("event-id", nil).Once()
+
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: auditRepositoryMock, uow: newUnitOfWorkMock(t)},
+	}
+
+	// Act
+	err := service.Update(context.Background(), "test-id", req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, string(models.AuditActionUpdate), event.Action)
+	assert.Equal(t, "old-name", event.Before["name"])
+	assert.Equal(t, name, event.After["name"])
+	assert.Equal(t, redactedValue, event.Before["password_hash"])
+	assert.Equal(t, redactedValue, event.After["password_hash"])
+}
+
+// TestAuditedUpdate_UpdateClaims checks that Update records an update_claims audit event when the claims of the user change
+func TestAuditedUpdate_UpdateClaims(t *testing.T) {
+	// Arrange
+	claimIDs := []int32{1}
+	req := models.UpdateUserReq{ClaimIDs: &claimIDs}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(models.GetUserResp{ID: "test-id", ClaimIDs: []int32{}}, nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Update), mock.Anything, "test-id", req).Return(nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(models.GetUserResp{ID: "test-id", ClaimIDs: claimIDs}, nil).Once()
+
+	var event entities.AuditEvent
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Create), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		event = args.Get(1).(entities.AuditEvent)
+	}).Return("event-id", nil).Once()
+
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: auditRepositoryMock, uow: newUnitOfWorkMock(t)},
+	}
+
+	// Act
+	err := service.Update(context.Background(), "test-id", req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, string(models.AuditActionUpdateClaims), event.Action)
+}
+
+// TestAuditedDelete_Ok checks that Delete records an audit event with the state of the user before the deletion
+func TestAuditedDelete_Ok(t *testing.T) {
+	// Arrange
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, "test-id").Return(models.GetUserResp{ID: "test-id", Email: "test@test.com"}, nil).Once()
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Delete), mock.Anything, "test-id").Return(nil).Once()
+
+	var event entities.AuditEvent
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Create), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		event = args.Get(1).(entities.AuditEvent)
+	}).Return("event-id", nil).Once()
+
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: auditRepositoryMock, uow: newUnitOfWorkMock(t)},
+	}
+
+	// Act
+	err := service.Delete(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, string(models.AuditActionDelete), event.Action)
+	assert.Equal(t, "test@test.com", event.Before["email"])
+	assert.Nil(t, event.After)
+}
+
+// TestAuditedConfirmEmailChange_Ok checks that ConfirmEmailChange audits the change of the email of the confirmed user
+func TestAuditedConfirmEmailChange_Ok(t *testing.T) {
+	// Arrange
+	req := models.ConfirmEmailChangeReq{Token: "test-token"}
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.ConfirmEmailChange), mock.Anything, req).Return(models.ConfirmEmailChangeResp{UserID: "test-id", PreviousEmail: "old@test.com", Email: "new@test.com"}, nil).Once()
+
+	var event entities.AuditEvent
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Create), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		event = args.Get(1).(entities.AuditEvent)
+	}).Return("event-id", nil).Once()
+
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: auditRepositoryMock, uow: newUnitOfWorkMock(t)},
+	}
+
+	// Act
+	_, err := service.ConfirmEmailChange(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, string(models.AuditActionUpdate), event.Action)
+	assert.Equal(t, "test-id", event.TargetID)
+	assert.Equal(t, "old@test.com", event.Before["email"])
+	assert.Equal(t, "new@test.com", event.After["email"])
+}
+
+// TestAuditedConfirmEmailChange_ConfirmError checks that ConfirmEmailChange records no audit event when the confirmation fails
+func TestAuditedConfirmEmailChange_ConfirmError(t *testing.T) {
+	// Arrange
+	expectedError := "confirm-error"
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.ConfirmEmailChange), mock.Anything, mock.Anything).Return(models.ConfirmEmailChangeResp{}, errors.New(expectedError)).Once()
+
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: auditRepositoryMock, uow: newUnitOfWorkMock(t)},
+	}
+
+	// Act
+	_, err := service.ConfirmEmailChange(context.Background(), models.ConfirmEmailChangeReq{Token: "test-token"})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	auditRepositoryMock.AssertNotCalled(t, testutils.FunctionName(t, ports.AuditRepository.Create))
+}
+
+// TestAuditedDeleteMany_Ok checks that DeleteMany records an audit event for every deleted user
+func TestAuditedDeleteMany_Ok(t *testing.T) {
+	// Arrange
+	IDs := []string{"test-id-1", "test-id-2"}
+	deleteResp := models.BulkUserResp{Results: []models.BulkItemResult{{Index: 0, ID: IDs[0]}, {Index: 1, ID: IDs[1]}}}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.GetByID), mock.Anything, mock.Anything).Return(models.GetUserResp{}, nil).Times(2)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.DeleteMany), mock.Anything, IDs, false).Return(deleteResp, nil).Once()
+
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Create), mock.Anything, mock.Anything).Return("event-id", nil).Times(2)
+
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: auditRepositoryMock, uow: newUnitOfWorkMock(t)},
+	}
+
+	// Act
+	resp, err := service.DeleteMany(context.Background(), IDs, false)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, deleteResp, resp)
+}
+
+// TestAuditedImport_DryRun checks that Import does not record any audit event on dry runs
+func TestAuditedImport_DryRun(t *testing.T) {
+	// Arrange
+	req := models.ImportUsersReq{Format: models.ImportFormatCSV, DryRun: true}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Import), mock.Anything, req).Return(models.ImportUsersResp{Total: 1, DryRun: true}, nil).Once()
+
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: mocks.NewAuditRepository(t), uow: mocks.NewUnitOfWork(t)},
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, resp.DryRun)
+}
+
+// TestAuditedImport_Ok checks that Import records an audit event for every imported user in the unit of work of its insertion
+func TestAuditedImport_Ok(t *testing.T) {
+	// Arrange
+	req := models.ImportUsersReq{Format: models.ImportFormatCSV}
+
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Import), mock.Anything, mock.Anything).Return(func(ctx context.Context, req models.ImportUsersReq) (models.ImportUsersResp, error) {
+		err := req.Apply(ctx, func(ctx context.Context) ([]models.BulkItemResult, error) {
+			return []models.BulkItemResult{{Index: 0, ID: "test-id-1"}, {Index: 2, ID: "test-id-2"}}, nil
+		})
+		return models.ImportUsersResp{Total: 2, Imported: 2}, err
+	}).Once()
+
+	var events []entities.AuditEvent
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Create), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		events = append(events, args.Get(1).(entities.AuditEvent))
+	}).Return("event-id", nil).Times(2)
+
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: auditRepositoryMock, uow: newUnitOfWorkMock(t)},
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 2, resp.Imported)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, string(models.AuditActionImport), events[0].Action)
+		assert.Equal(t, "test-id-1", events[0].TargetID)
+		assert.Equal(t, "test-id-2", events[1].TargetID)
+		assert.Equal(t, 2, events[1].After["row"])
+	}
+}
+
+// TestAuditedImport_RecordError checks that Import fails the insertion of the users whose audit events cannot be recorded
+func TestAuditedImport_RecordError(t *testing.T) {
+	// Arrange
+	expectedError := "failed to record audit event import of user test-id: record error"
+
+	var applyErr error
+	userServiceMock := mocks.NewUserService(t)
+	userServiceMock.On(testutils.FunctionName(t, ports.UserService.Import), mock.Anything, mock.Anything).Return(func(ctx context.Context, req models.ImportUsersReq) (models.ImportUsersResp, error) {
+		applyErr = req.Apply(ctx, func(ctx context.Context) ([]models.BulkItemResult, error) {
+			return []models.BulkItemResult{{Index: 0, ID: "test-id"}}, nil
+		})
+		return models.ImportUsersResp{Total: 1, Errors: []models.BulkItemResult{{Index: 0, Err: applyErr}}}, nil
+	}).Once()
+
+	auditRepositoryMock := mocks.NewAuditRepository(t)
+	auditRepositoryMock.On(testutils.FunctionName(t, ports.AuditRepository.Create), mock.Anything, mock.Anything).Return("", errors.New("record error")).Once()
+
+	service := &auditedUserService{
+		UserService: userServiceMock,
+		auditor:     auditor{audit: auditRepositoryMock, uow: newUnitOfWorkMock(t)},
+	}
+
+	// Act
+	resp, err := service.Import(context.Background(), models.ImportUsersReq{Format: models.ImportFormatCSV})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.EqualError(t, applyErr, expectedError)
+	assert.Zero(t, resp.Imported)
+}
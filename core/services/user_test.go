@@ -5,26 +5,34 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/sergicanet9/go-hexagonal-api/config"
 	"github.com/sergicanet9/go-hexagonal-api/core/entities"
 	"github.com/sergicanet9/go-hexagonal-api/core/models"
 	"github.com/sergicanet9/go-hexagonal-api/core/ports"
 	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
 	"github.com/sergicanet9/scv-go-tools/v4/testutils"
 	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// txCtxKey key of the context values telling the units of work apart from the outer contexts
+type txCtxKey struct{}
+
 // TestNewUserService_Ok checks that NewUserService creates a new userService struct
 func TestNewUserService_Ok(t *testing.T) {
 	// Arrange
 	cfg := config.Config{}
 	userRepositoryMock := mocks.NewUserRepository(t)
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	userWatcherMock := mocks.NewUserWatcher(t)
+	emailChangeServiceMock := mocks.NewEmailChangeService(t)
 
 	// Act
-	service := NewUserService(cfg, userRepositoryMock)
+	service := NewUserService(cfg, userRepositoryMock, outboxRepositoryMock, userWatcherMock, emailChangeServiceMock, mocks.NewUnitOfWork(t))
 
 	// Assert
 	assert.NotEmpty(t, service)
@@ -38,22 +46,30 @@ func TestLogin_Ok(t *testing.T) {
 		Password: "test",
 	}
 
-	filter := map[string]interface{}{"email": req.Email}
-	var result []interface{}
+	filter := models.UserFilter{EmailNormalized: req.Email}
+	var result []entities.User
 	expectedUser := entities.User{
 		Email:        req.Email,
 		PasswordHash: "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
 		ClaimIDs:     []int32{0},
 	}
-	result = append(result, &expectedUser)
+	result = append(result, expectedUser)
 
 	var nilPointer *int
 	userRepositoryMock := mocks.NewUserRepository(t)
 	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), context.Background(), filter, nilPointer, nilPointer).Return(result, nil).Once()
 
+	var event entities.Event
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.Add), context.Background(), mock.Anything).Run(func(args mock.Arguments) {
+		event = args.Get(1).(entities.Event)
+	}).Return(nil).Once()
+
 	service := &userService{
+		uow:        newUnitOfWorkMock(t),
 		config:     config.Config{},
 		repository: userRepositoryMock,
+		outbox:     outboxRepositoryMock,
 	}
 
 	// Act
@@ -61,9 +77,48 @@ func TestLogin_Ok(t *testing.T) {
 
 	// Assert
 	assert.Nil(t, err)
+	assert.Equal(t, entities.EventTypeUserLoggedIn, event.Type)
+	assert.Equal(t, expectedUser.ID, event.AggregateID)
 	assert.Equal(t, models.GetUserResp(expectedUser), resp.User)
 }
 
+// TestLogin_NormalizedEmail checks that Login looks up the user by its normalized email, so that the case of the received email does not matter
+func TestLogin_NormalizedEmail(t *testing.T) {
+	// Arrange
+	req := models.LoginUserReq{
+		Email:    " Test@Test.COM",
+		Password: "test",
+	}
+
+	filter := models.UserFilter{EmailNormalized: "test@test.com"}
+	expectedUser := entities.User{
+		Email:           "test@test.com",
+		EmailNormalized: "test@test.com",
+		PasswordHash:    "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
+	}
+
+	var nilPointer *int
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), context.Background(), filter, nilPointer, nilPointer).Return([]entities.User{expectedUser}, nil).Once()
+
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.Add), context.Background(), mock.Anything).Return(nil).Once()
+
+	service := &userService{
+		uow:        newUnitOfWorkMock(t),
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		outbox:     outboxRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Login(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedUser.Email, resp.User.Email)
+}
+
 // TestLogin_NotFound checks that Login returns an error when the user is not found
 func TestLogin_NotFound(t *testing.T) {
 	// Arrange
@@ -72,7 +127,7 @@ func TestLogin_NotFound(t *testing.T) {
 		Password: "test",
 	}
 
-	filter := map[string]interface{}{"email": req.Email}
+	filter := models.UserFilter{EmailNormalized: req.Email}
 	expectedError := fmt.Sprintf("email %s not found", req.Email)
 
 	var nilPointer *int
@@ -80,6 +135,7 @@ func TestLogin_NotFound(t *testing.T) {
 	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), context.Background(), filter, nilPointer, nilPointer).Return(nil, wrappers.NonExistentErr).Once()
 
 	service := &userService{
+		uow:        newUnitOfWorkMock(t),
 		config:     config.Config{},
 		repository: userRepositoryMock,
 	}
@@ -104,6 +160,7 @@ func TestLogin_InvalidRequest(t *testing.T) {
 	expectedError := "email cannot be empty | password cannot be empty"
 
 	service := &userService{
+		uow:        newUnitOfWorkMock(t),
 		config:     config.Config{},
 		repository: nil,
 	}
@@ -125,13 +182,13 @@ func TestLogin_IncorrectPassword(t *testing.T) {
 		Password: "incorrect-password",
 	}
 
-	filter := map[string]interface{}{"email": req.Email}
-	var result []interface{}
+	filter := models.UserFilter{EmailNormalized: req.Email}
+	var result []entities.User
 	expectedUser := entities.User{
 		Email:        req.Email,
 		PasswordHash: "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
 	}
-	result = append(result, &expectedUser)
+	result = append(result, expectedUser)
 
 	expectedError := "password incorrect"
 
@@ -140,6 +197,43 @@ func TestLogin_IncorrectPassword(t *testing.T) {
 	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), context.Background(), filter, nilPointer, nilPointer).Return(result, nil).Once()
 
 	service := &userService{
+		uow:        newUnitOfWorkMock(t),
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	_, err := service.Login(context.Background(), req)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.ValidationErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestLogin_PendingInvitation checks that Login returns a validation error when the user has not accepted its invitation yet
+func TestLogin_PendingInvitation(t *testing.T) {
+	// Arrange
+	req := models.LoginUserReq{
+		Email:    "test@test.com",
+		Password: "test",
+	}
+
+	filter := models.UserFilter{EmailNormalized: req.Email}
+	var result []entities.User
+	expectedUser := entities.User{
+		Email: req.Email,
+	}
+	result = append(result, expectedUser)
+
+	expectedError := "invitation of email test@test.com is pending"
+
+	var nilPointer *int
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), context.Background(), filter, nilPointer, nilPointer).Return(result, nil).Once()
+
+	service := &userService{
+		uow:        newUnitOfWorkMock(t),
 		config:     config.Config{},
 		repository: userRepositoryMock,
 	}
@@ -153,6 +247,46 @@ func TestLogin_IncorrectPassword(t *testing.T) {
 	assert.Equal(t, expectedError, err.Error())
 }
 
+// TestLogin_OutboxError checks that Login returns an error when the login event cannot be written to the outbox
+func TestLogin_OutboxError(t *testing.T) {
+	// Arrange
+	req := models.LoginUserReq{
+		Email:    "test@test.com",
+		Password: "test",
+	}
+
+	filter := models.UserFilter{EmailNormalized: req.Email}
+	var result []entities.User
+	expectedUser := entities.User{
+		Email:        req.Email,
+		PasswordHash: "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
+	}
+	result = append(result, expectedUser)
+
+	expectedError := "outbox error"
+
+	var nilPointer *int
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), context.Background(), filter, nilPointer, nilPointer).Return(result, nil).Once()
+
+	outboxRepositoryMock := mocks.NewOutboxRepository(t)
+	outboxRepositoryMock.On(testutils.FunctionName(t, ports.OutboxRepository.Add), context.Background(), mock.Anything).Return(errors.New(expectedError)).Once()
+
+	service := &userService{
+		uow:        newUnitOfWorkMock(t),
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		outbox:     outboxRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Login(context.Background(), req)
+
+	// Assert
+	assert.Empty(t, resp.Token)
+	assert.Equal(t, expectedError, err.Error())
+}
+
 // TestLogin_InvalidClaims checks that Login returns an error when the claims returned from the repository are not valid
 func TestLogin_InvalidClaims(t *testing.T) {
 	// Arrange
@@ -161,14 +295,14 @@ func TestLogin_InvalidClaims(t *testing.T) {
 		Password: "test",
 	}
 
-	filter := map[string]interface{}{"email": req.Email}
-	var result []interface{}
+	filter := models.UserFilter{EmailNormalized: req.Email}
+	var result []entities.User
 	expectedUser := entities.User{
 		Email:        req.Email,
 		PasswordHash: "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
 		ClaimIDs:     []int32{3},
 	}
-	result = append(result, &expectedUser)
+	result = append(result, expectedUser)
 
 	expectedError := "claim 3 is not valid"
 
@@ -177,6 +311,7 @@ func TestLogin_InvalidClaims(t *testing.T) {
 	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), context.Background(), filter, nilPointer, nilPointer).Return(result, nil).Once()
 
 	service := &userService{
+		uow:        newUnitOfWorkMock(t),
 		config:     config.Config{},
 		repository: userRepositoryMock,
 	}
@@ -304,11 +439,12 @@ func TestCreateMany_Ok(t *testing.T) {
 	}
 
 	expectedResponse := models.CreateManyUserResp{
-		IDs: []string{"new-id"},
+		IDs:     []string{"new-id"},
+		Results: []models.BulkItemResult{{Index: 0, ID: "new-id"}},
 	}
 
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.CreateMany), mock.Anything, mock.AnythingOfType("[]interface {}")).Return(expectedResponse.IDs, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.CreateMany), mock.Anything, mock.AnythingOfType("[]entities.User")).Return(expectedResponse.IDs, nil).Once()
 
 	service := &userService{
 		config:     config.Config{},
@@ -316,7 +452,7 @@ func TestCreateMany_Ok(t *testing.T) {
 	}
 
 	// Act
-	resp, err := service.CreateMany(context.Background(), req)
+	resp, err := service.CreateMany(context.Background(), req, false)
 
 	// Assert
 	assert.Nil(t, err)
@@ -336,7 +472,7 @@ func TestCreateMany_CreateManyError(t *testing.T) {
 	expectedError := "repository-error"
 
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.CreateMany), mock.Anything, mock.AnythingOfType("[]interface {}")).Return([]string{}, errors.New(expectedError)).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.CreateMany), mock.Anything, mock.AnythingOfType("[]entities.User")).Return([]string{}, errors.New(expectedError)).Once()
 
 	service := &userService{
 		config:     config.Config{},
@@ -344,7 +480,7 @@ func TestCreateMany_CreateManyError(t *testing.T) {
 	}
 
 	// Act
-	_, err := service.CreateMany(context.Background(), req)
+	_, err := service.CreateMany(context.Background(), req, false)
 
 	// Assert
 	assert.NotEmpty(t, err)
@@ -369,7 +505,7 @@ func TestCreateMany_InvalidRequest(t *testing.T) {
 	}
 
 	// Act
-	_, err := service.CreateMany(context.Background(), req)
+	_, err := service.CreateMany(context.Background(), req, false)
 
 	// Assert
 	assert.NotEmpty(t, err)
@@ -396,7 +532,7 @@ func TestCreateMany_InvalidClaims(t *testing.T) {
 	}
 
 	// Act
-	_, err := service.CreateMany(context.Background(), req)
+	_, err := service.CreateMany(context.Background(), req, false)
 
 	// Assert
 	assert.NotEmpty(t, err)
@@ -404,20 +540,55 @@ func TestCreateMany_InvalidClaims(t *testing.T) {
 	assert.Equal(t, expectedError, err.Error())
 }
 
+// TestCreateMany_Partial checks that CreateMany in partial mode creates the valid users and reports the failing ones
+func TestCreateMany_Partial(t *testing.T) {
+	// Arrange
+	req := []models.CreateUserReq{
+		{
+			Email:    "test@test.com",
+			Password: "test",
+		},
+		{
+			Email:    "",
+			Password: "test",
+		},
+	}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Create), mock.Anything, mock.AnythingOfType("entities.User")).Return("new-id", nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.CreateMany(context.Background(), req, true)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"new-id"}, resp.IDs)
+	assert.Len(t, resp.Results, 2)
+	assert.Nil(t, resp.Results[0].Err)
+	assert.Equal(t, "new-id", resp.Results[0].ID)
+	assert.Equal(t, 1, resp.Results[1].Index)
+	assert.IsType(t, wrappers.ValidationErr, resp.Results[1].Err)
+}
+
 // TestGetAll_Ok checks that GetAll returns the expected response when everything goes as expected
 func TestGetAll_Ok(t *testing.T) {
 	// Arrange
-	var result []interface{}
+	var result []entities.User
 	expectedUser := entities.User{
 		Email:        "test@test.com",
 		PasswordHash: "$2a$10$NexA3QvmeUMPME6GVhFaX.C4A.y2VIPBwRNrV0c2DncjCAWSBnINK",
 		ClaimIDs:     []int32{0},
 	}
-	result = append(result, &expectedUser)
+	result = append(result, expectedUser)
 
 	var nilPointer *int
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), mock.Anything, map[string]interface{}{}, nilPointer, nilPointer).Return(result, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), mock.Anything, models.UserFilter{}, nilPointer, nilPointer).Return(result, nil).Once()
 
 	service := &userService{
 		config:     config.Config{},
@@ -437,7 +608,7 @@ func TestGetAll_NoResourcesFound(t *testing.T) {
 	// Arrange
 	var nilPointer *int
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), mock.Anything, map[string]interface{}{}, nilPointer, nilPointer).Return(nil, wrappers.NonExistentErr).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), mock.Anything, models.UserFilter{}, nilPointer, nilPointer).Return(nil, wrappers.NonExistentErr).Once()
 
 	service := &userService{
 		config:     config.Config{},
@@ -452,6 +623,75 @@ func TestGetAll_NoResourcesFound(t *testing.T) {
 	assert.Equal(t, 0, len(resp))
 }
 
+// TestSearch_Ok checks that Search returns a page of the found users along with the token of the next page
+func TestSearch_Ok(t *testing.T) {
+	// Arrange
+	users := []entities.User{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Search), context.Background(), "jose", 2, 3).Return(users, nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Search(context.Background(), models.SearchUsersReq{Query: "jose", PageSize: 2, PageToken: "2"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.GetUserResp{{ID: "1"}, {ID: "2"}}, resp.Users)
+	assert.Equal(t, "4", resp.NextPageToken)
+}
+
+// TestSearch_LastPage checks that Search returns an empty next page token on the last page, applying the default page size
+func TestSearch_LastPage(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Search), context.Background(), "jose", 0, defaultSearchPageSize+1).Return([]entities.User{}, nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.Search(context.Background(), models.SearchUsersReq{Query: "jose"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Empty(t, resp.Users)
+	assert.Empty(t, resp.NextPageToken)
+}
+
+// TestSearch_InvalidRequest checks that Search returns a validation error when the query, the page size or the page token are not valid
+func TestSearch_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           models.SearchUsersReq
+		expectedError string
+	}{
+		{name: "empty query", req: models.SearchUsersReq{Query: " "}, expectedError: "query cannot be empty"},
+		{name: "page size", req: models.SearchUsersReq{Query: "jose", PageSize: maxSearchPageSize + 1}, expectedError: "page size must be between 1 and 100"},
+		{name: "page token", req: models.SearchUsersReq{Query: "jose", PageToken: "invalid"}, expectedError: "page token invalid is not valid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := &userService{}
+
+			// Act
+			_, err := service.Search(context.Background(), tt.req)
+
+			// Assert
+			assert.True(t, errors.Is(err, wrappers.ValidationErr))
+			assert.Equal(t, tt.expectedError, err.Error())
+		})
+	}
+}
+
 // TestGetByID_Ok checks that GetByID returns the expected response when a valid ID is received
 func TestGetByID_Ok(t *testing.T) {
 	// Arrange
@@ -463,7 +703,7 @@ func TestGetByID_Ok(t *testing.T) {
 	}
 
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), expectedUser.ID).Return(&expectedUser, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), expectedUser.ID).Return(expectedUser, nil).Once()
 
 	service := &userService{
 		config:     config.Config{},
@@ -485,7 +725,7 @@ func TestGetByID_NotFound(t *testing.T) {
 	expectedError := fmt.Sprintf("ID %s not found", nonExistentID)
 
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), nonExistentID).Return(nil, wrappers.NonExistentErr).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), nonExistentID).Return(entities.User{}, wrappers.NonExistentErr).Once()
 
 	service := &userService{
 		config:     config.Config{},
@@ -524,12 +764,17 @@ func TestUpdate_Ok(t *testing.T) {
 	}
 
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), id).Return(&existingUser, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), id).Return(existingUser, nil).Once()
 	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), id, mock.AnythingOfType("entities.User")).Return(nil).Once()
 
+	emailChangeServiceMock := mocks.NewEmailChangeService(t)
+	emailChangeServiceMock.On(testutils.FunctionName(t, ports.EmailChangeService.Request), context.Background(), id, testEmail).Return(nil).Once()
+
 	service := &userService{
-		config:     config.Config{},
-		repository: userRepositoryMock,
+		config:       config.Config{},
+		repository:   userRepositoryMock,
+		emailChanges: emailChangeServiceMock,
+		uow:          newUnitOfWorkMock(t),
 	}
 
 	// Act
@@ -539,6 +784,124 @@ func TestUpdate_Ok(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+// TestUpdate_EmailCase checks that Update applies at once a new email differing only in case, as it belongs to the same user
+func TestUpdate_EmailCase(t *testing.T) {
+	// Arrange
+	testEmail := "Bob@X.com"
+	id := "test-id"
+
+	req := models.UpdateUserReq{
+		Email: &testEmail,
+	}
+
+	existingUser := entities.User{
+		Email:           "bob@x.com",
+		EmailNormalized: "bob@x.com",
+	}
+
+	var updated entities.User
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), id).Return(existingUser, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), id, mock.AnythingOfType("entities.User")).Run(func(args mock.Arguments) {
+		updated = args.Get(2).(entities.User)
+	}).Return(nil).Once()
+
+	service := &userService{
+		config:       config.Config{},
+		repository:   userRepositoryMock,
+		emailChanges: mocks.NewEmailChangeService(t),
+		uow:          newUnitOfWorkMock(t),
+	}
+
+	// Act
+	err := service.Update(context.Background(), id, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "Bob@X.com", updated.Email)
+	assert.Equal(t, "bob@x.com", updated.EmailNormalized)
+}
+
+// TestUpdate_EmailChangeRequested checks that Update keeps the current email and requests the change when a new email is received
+func TestUpdate_EmailChangeRequested(t *testing.T) {
+	// Arrange
+	testEmail := "alice@x.com"
+	id := "test-id"
+
+	req := models.UpdateUserReq{
+		Email: &testEmail,
+	}
+
+	existingUser := entities.User{
+		Email:           "Bob@X.com",
+		EmailNormalized: "bob@x.com",
+	}
+
+	var updated entities.User
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), id).Return(existingUser, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), id, mock.AnythingOfType("entities.User")).Run(func(args mock.Arguments) {
+		updated = args.Get(2).(entities.User)
+	}).Return(nil).Once()
+
+	emailChangeServiceMock := mocks.NewEmailChangeService(t)
+	emailChangeServiceMock.On(testutils.FunctionName(t, ports.EmailChangeService.Request), context.Background(), id, testEmail).Return(nil).Once()
+
+	service := &userService{
+		config:       config.Config{},
+		repository:   userRepositoryMock,
+		emailChanges: emailChangeServiceMock,
+		uow:          newUnitOfWorkMock(t),
+	}
+
+	// Act
+	err := service.Update(context.Background(), id, req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "Bob@X.com", updated.Email)
+	assert.Equal(t, "bob@x.com", updated.EmailNormalized)
+}
+
+// TestConfirmEmailChange_Ok checks that ConfirmEmailChange confirms the change through the email change service
+func TestConfirmEmailChange_Ok(t *testing.T) {
+	// Arrange
+	req := models.ConfirmEmailChangeReq{Token: "test-token"}
+
+	emailChangeServiceMock := mocks.NewEmailChangeService(t)
+	emailChangeServiceMock.On(testutils.FunctionName(t, ports.EmailChangeService.Confirm), context.Background(), req).Return(models.ConfirmEmailChangeResp{UserID: "test-id"}, nil).Once()
+
+	service := &userService{
+		config:       config.Config{},
+		emailChanges: emailChangeServiceMock,
+	}
+
+	// Act
+	resp, err := service.ConfirmEmailChange(context.Background(), req)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", resp.UserID)
+}
+
+// TestUpdate_InvalidEmail checks that Update returns a validation error when the received email is not valid
+func TestUpdate_InvalidEmail(t *testing.T) {
+	// Arrange
+	testEmail := "bob"
+
+	service := &userService{
+		config: config.Config{},
+		uow:    newUnitOfWorkMock(t),
+	}
+
+	// Act
+	err := service.Update(context.Background(), "test-id", models.UpdateUserReq{Email: &testEmail})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "email bob is not valid", err.Error())
+}
+
 // TestUpdate_NotFound checks that Update returns an error when the provided ID does not exist
 func TestUpdate_NotFound(t *testing.T) {
 	// Arrange
@@ -546,11 +909,12 @@ func TestUpdate_NotFound(t *testing.T) {
 	expectedError := fmt.Sprintf("ID %s not found", nonExistentID)
 
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), nonExistentID).Return(nil, wrappers.NonExistentErr).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), nonExistentID).Return(entities.User{}, wrappers.NonExistentErr).Once()
 
 	service := &userService{
 		config:     config.Config{},
 		repository: userRepositoryMock,
+		uow:        newUnitOfWorkMock(t),
 	}
 
 	// Act
@@ -581,11 +945,12 @@ func TestUpdate_IncorrectPassword(t *testing.T) {
 	expectedError := "password incorrect"
 
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), id).Return(&existingUser, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), id).Return(existingUser, nil).Once()
 
 	service := &userService{
 		config:     config.Config{},
 		repository: userRepositoryMock,
+		uow:        newUnitOfWorkMock(t),
 	}
 
 	// Act
@@ -610,11 +975,12 @@ func TestUpdate_InvalidClaims(t *testing.T) {
 	expectedError := "claim 3 is not valid"
 
 	userRepositoryMock := mocks.NewUserRepository(t)
-	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), id).Return(&entities.User{}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), id).Return(entities.User{}, nil).Once()
 
 	service := &userService{
 		config:     config.Config{},
 		repository: userRepositoryMock,
+		uow:        newUnitOfWorkMock(t),
 	}
 
 	// Act
@@ -626,6 +992,189 @@ func TestUpdate_InvalidClaims(t *testing.T) {
 	assert.Equal(t, expectedError, err.Error())
 }
 
+// TestUpdateMany_Ok checks that UpdateMany updates all the users in a single repository call
+func TestUpdateMany_Ok(t *testing.T) {
+	// Arrange
+	testName := "test"
+	req := []models.UpdateManyUserReq{
+		{ID: "id-1", UpdateUserReq: models.UpdateUserReq{Name: &testName}},
+		{ID: "id-2", UpdateUserReq: models.UpdateUserReq{Name: &testName}},
+	}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "id-1").Return(entities.User{}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "id-2").Return(entities.User{}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateMany), context.Background(), []string{"id-1", "id-2"}, mock.AnythingOfType("[]entities.User")).Return(nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		uow:        newUnitOfWorkMock(t),
+	}
+
+	// Act
+	resp, err := service.UpdateMany(context.Background(), req, false)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.BulkItemResult{{Index: 0, ID: "id-1"}, {Index: 1, ID: "id-2"}}, resp.Results)
+}
+
+// TestUpdateMany_NotFound checks that UpdateMany does not update any user when one of them does not exist
+func TestUpdateMany_NotFound(t *testing.T) {
+	// Arrange
+	req := []models.UpdateManyUserReq{
+		{ID: "id-1"},
+		{ID: "non-existent-id"},
+	}
+	expectedError := "ID non-existent-id not found"
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "id-1").Return(entities.User{}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "non-existent-id").Return(entities.User{}, wrappers.NonExistentErr).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		uow:        newUnitOfWorkMock(t),
+	}
+
+	// Act
+	_, err := service.UpdateMany(context.Background(), req, false)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.IsType(t, wrappers.NonExistentErr, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestUpdateMany_Partial checks that UpdateMany in partial mode updates the users one by one and reports the failing ones
+func TestUpdateMany_Partial(t *testing.T) {
+	// Arrange
+	req := []models.UpdateManyUserReq{
+		{ID: "id-1"},
+		{ID: "non-existent-id"},
+	}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "id-1").Return(entities.User{}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), context.Background(), "id-1", mock.AnythingOfType("entities.User")).Return(nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), "non-existent-id").Return(entities.User{}, wrappers.NonExistentErr).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		uow:        newUnitOfWorkMock(t),
+	}
+
+	// Act
+	resp, err := service.UpdateMany(context.Background(), req, true)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp.Results, 2)
+	assert.Nil(t, resp.Results[0].Err)
+	assert.IsType(t, wrappers.NonExistentErr, resp.Results[1].Err)
+}
+
+// TestUpdateMany_UnitOfWork checks that UpdateMany reads and updates the users in the same unit of work
+func TestUpdateMany_UnitOfWork(t *testing.T) {
+	// Arrange
+	testName := "test"
+	req := []models.UpdateManyUserReq{{ID: "id-1", UpdateUserReq: models.UpdateUserReq{Name: &testName}}}
+	txCtx := context.WithValue(context.Background(), txCtxKey{}, "tx")
+
+	unitOfWorkMock := mocks.NewUnitOfWork(t)
+	unitOfWorkMock.On(testutils.FunctionName(t, ports.UnitOfWork.Do), context.Background(), mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(txCtx)
+	}).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), txCtx, "id-1").Return(entities.User{}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateMany), txCtx, []string{"id-1"}, mock.AnythingOfType("[]entities.User")).Return(nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		uow:        unitOfWorkMock,
+	}
+
+	// Act
+	_, err := service.UpdateMany(context.Background(), req, false)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestUpdateMany_Retried checks that UpdateMany updates every user once when its unit of work is retried
+func TestUpdateMany_Retried(t *testing.T) {
+	// Arrange
+	testName := "test"
+	req := []models.UpdateManyUserReq{
+		{ID: "id-1", UpdateUserReq: models.UpdateUserReq{Name: &testName}},
+		{ID: "id-2", UpdateUserReq: models.UpdateUserReq{Name: &testName}},
+	}
+
+	unitOfWorkMock := mocks.NewUnitOfWork(t)
+	unitOfWorkMock.On(testutils.FunctionName(t, ports.UnitOfWork.Do), context.Background(), mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		fn(ctx)
+		return fn(ctx)
+	}).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), context.Background(), mock.Anything).Return(entities.User{}, nil).Times(4)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateMany), context.Background(), []string{"id-1", "id-2"}, mock.MatchedBy(func(users []entities.User) bool {
+		return len(users) == 2
+	})).Return(nil).Twice()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		uow:        unitOfWorkMock,
+	}
+
+	// Act
+	resp, err := service.UpdateMany(context.Background(), req, false)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp.Results, 2)
+}
+
+// TestUpdateMany_EmailInUse checks that UpdateMany requests the email changes in its unit of work, so that a new email in use fails every update
+func TestUpdateMany_EmailInUse(t *testing.T) {
+	// Arrange
+	testEmail := "taken@x.com"
+	req := []models.UpdateManyUserReq{{ID: "id-1", UpdateUserReq: models.UpdateUserReq{Email: &testEmail}}}
+	txCtx := context.WithValue(context.Background(), txCtxKey{}, "tx")
+	expectedError := entities.NewEmailInUseErr(testEmail)
+
+	unitOfWorkMock := mocks.NewUnitOfWork(t)
+	unitOfWorkMock.On(testutils.FunctionName(t, ports.UnitOfWork.Do), context.Background(), mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(txCtx)
+	}).Once()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), txCtx, "id-1").Return(entities.User{Email: "bob@x.com"}, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.UpdateMany), txCtx, []string{"id-1"}, mock.AnythingOfType("[]entities.User")).Return(nil).Once()
+
+	emailChangeServiceMock := mocks.NewEmailChangeService(t)
+	emailChangeServiceMock.On(testutils.FunctionName(t, ports.EmailChangeService.Request), txCtx, "id-1", testEmail).Return(expectedError).Once()
+
+	service := &userService{
+		config:       config.Config{},
+		repository:   userRepositoryMock,
+		emailChanges: emailChangeServiceMock,
+		uow:          unitOfWorkMock,
+	}
+
+	// Act
+	_, err := service.UpdateMany(context.Background(), req, false)
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
+
 // TestDelete_Ok checks that Delete does not return an error when everything goes as expected
 func TestDelete_Ok(t *testing.T) {
 	// Arrange
@@ -668,6 +1217,98 @@ func TestDelete_NotFound(t *testing.T) {
 	assert.Equal(t, expectedError, err.Error())
 }
 
+// TestDeleteMany_Ok checks that DeleteMany deletes all the users in a single repository call
+func TestDeleteMany_Ok(t *testing.T) {
+	// Arrange
+	IDs := []string{"id-1", "id-2"}
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.DeleteMany), context.Background(), IDs).Return(nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.DeleteMany(context.Background(), IDs, false)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.BulkItemResult{{Index: 0, ID: "id-1"}, {Index: 1, ID: "id-2"}}, resp.Results)
+}
+
+// TestDeleteMany_DeleteManyError checks that DeleteMany returns an error when the DeleteMany function from the repository fails
+func TestDeleteMany_DeleteManyError(t *testing.T) {
+	// Arrange
+	IDs := []string{"id-1"}
+	expectedError := "repository-error"
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.DeleteMany), context.Background(), IDs).Return(errors.New(expectedError)).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+	}
+
+	// Act
+	_, err := service.DeleteMany(context.Background(), IDs, false)
+
+	// Assert
+	assert.NotEmpty(t, err)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestDeleteMany_Partial checks that DeleteMany in partial mode deletes the users one by one and reports the failing ones
+func TestDeleteMany_Partial(t *testing.T) {
+	// Arrange
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Delete), context.Background(), "id-1").Return(nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Delete), context.Background(), "non-existent-id").Return(wrappers.NonExistentErr).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		uow:        newUnitOfWorkMock(t),
+	}
+
+	// Act
+	resp, err := service.DeleteMany(context.Background(), []string{"id-1", "non-existent-id"}, true)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resp.Results, 2)
+	assert.Nil(t, resp.Results[0].Err)
+	assert.Equal(t, "ID non-existent-id not found", resp.Results[1].Err.Error())
+}
+
+// TestDeleteMany_PartialUnitOfWork checks that DeleteMany in partial mode deletes every user in its own unit of work
+func TestDeleteMany_PartialUnitOfWork(t *testing.T) {
+	// Arrange
+	txCtx := context.WithValue(context.Background(), txCtxKey{}, "tx")
+
+	unitOfWorkMock := mocks.NewUnitOfWork(t)
+	unitOfWorkMock.On(testutils.FunctionName(t, ports.UnitOfWork.Do), context.Background(), mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(txCtx)
+	}).Twice()
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Delete), txCtx, "id-1").Return(nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Delete), txCtx, "id-2").Return(nil).Once()
+
+	service := &userService{
+		config:     config.Config{},
+		repository: userRepositoryMock,
+		uow:        unitOfWorkMock,
+	}
+
+	// Act
+	resp, err := service.DeleteMany(context.Background(), []string{"id-1", "id-2"}, true)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []models.BulkItemResult{{Index: 0, ID: "id-1"}, {Index: 1, ID: "id-2"}}, resp.Results)
+}
+
 // TestGetUserClaims_Ok checks that GetUserClaims returns the expected response when everything goes as expected
 func TestGetUserClaims_Ok(t *testing.T) {
 	// Arrange
@@ -684,3 +1325,152 @@ func TestGetUserClaims_Ok(t *testing.T) {
 	// Assert
 	assert.Equal(t, expectedClaims, resp)
 }
+
+// TestWatch_Ok checks that Watch maps every change received from the watcher, with no user when it no longer exists
+func TestWatch_Ok(t *testing.T) {
+	// Arrange
+	changes := []entities.UserChange{
+		{ResumeToken: "1", Type: entities.UserChangeTypeCreated, UserID: "test-id", User: &entities.User{ID: "test-id", Email: "test@test.com"}},
+		{ResumeToken: "2", Type: entities.UserChangeTypeDeleted, UserID: "test-id"},
+	}
+
+	userWatcherMock := mocks.NewUserWatcher(t)
+	userWatcherMock.On(testutils.FunctionName(t, ports.UserWatcher.Watch), context.Background(), "test-token", mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(entities.UserChange) error)
+		for _, change := range changes {
+			fn(change)
+		}
+	}).Return(nil).Once()
+
+	service := &userService{
+		config:  config.Config{},
+		watcher: userWatcherMock,
+	}
+
+	var resps []models.UserChangeResp
+
+	// Act
+	err := service.Watch(context.Background(), "test-token", func(change models.UserChangeResp) error {
+		resps = append(resps, change)
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, resps, 2)
+	assert.Equal(t, "created", resps[0].Type)
+	assert.Equal(t, "test@test.com", resps[0].User.Email)
+	assert.Equal(t, "2", resps[1].ResumeToken)
+	assert.Nil(t, resps[1].User)
+}
+
+// TestWatch_WatcherError checks that Watch returns an error when the watcher fails
+func TestWatch_WatcherError(t *testing.T) {
+	// Arrange
+	expectedError := "resume token invalid is not valid"
+
+	userWatcherMock := mocks.NewUserWatcher(t)
+	userWatcherMock.On(testutils.FunctionName(t, ports.UserWatcher.Watch), context.Background(), "invalid", mock.Anything).Return(wrappers.NewValidationErr(errors.New(expectedError))).Once()
+
+	service := &userService{
+		config:  config.Config{},
+		watcher: userWatcherMock,
+	}
+
+	// Act
+	err := service.Watch(context.Background(), "invalid", func(change models.UserChangeResp) error {
+		return nil
+	})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestPruneChanges_Ok checks that PruneChanges deletes the user changes older than the retention
+func TestPruneChanges_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.UserChanges.Retention = utils.Duration{Duration: time.Hour}
+
+	userWatcherMock := mocks.NewUserWatcher(t)
+	userWatcherMock.On(testutils.FunctionName(t, ports.UserWatcher.Prune), context.Background(), mock.MatchedBy(func(before time.Time) bool {
+		return before.Before(time.Now().Add(-59*time.Minute)) && before.After(time.Now().Add(-61*time.Minute))
+	})).Return(3, nil).Once()
+
+	service := &userService{
+		config:  cfg,
+		watcher: userWatcherMock,
+	}
+
+	// Act
+	pruned, err := service.PruneChanges(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 3, pruned)
+}
+
+// TestPruneChanges_NoRetention checks that PruneChanges deletes no user change when the retention is not set
+func TestPruneChanges_NoRetention(t *testing.T) {
+	// Arrange
+	service := &userService{
+		config:  config.Config{},
+		watcher: mocks.NewUserWatcher(t),
+	}
+
+	// Act
+	pruned, err := service.PruneChanges(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Zero(t, pruned)
+}
+
+// TestUserChangesExportUserData_Ok checks that ExportUserData returns the recorded changes of the user along with the states they left
+func TestUserChangesExportUserData_Ok(t *testing.T) {
+	// Arrange
+	changes := []entities.UserChange{
+		{Type: entities.UserChangeTypeCreated, UserID: "test-id", User: &entities.User{ID: "test-id", Name: "test", PasswordHash: "test-hash"}},
+		{Type: entities.UserChangeTypeUpdated, UserID: "test-id"},
+	}
+
+	userWatcherMock := mocks.NewUserWatcher(t)
+	userWatcherMock.On(testutils.FunctionName(t, ports.UserWatcher.GetByUserID), context.Background(), "test-id").Return(changes, nil).Once()
+
+	service := &userService{
+		config:  config.Config{},
+		watcher: userWatcherMock,
+	}
+
+	// Act
+	data, err := service.ExportUserData(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, userChangesStoreName, service.Name())
+	if assert.Len(t, data, 2) {
+		exported := data.([]userChangeData)
+		assert.Equal(t, string(entities.UserChangeTypeCreated), exported[0].Type)
+		assert.Equal(t, "test", exported[0].User.Name)
+		assert.Nil(t, exported[1].User)
+	}
+}
+
+// TestUserChangesEraseUserData_Ok checks that EraseUserData clears the states of the user recorded along with its changes
+func TestUserChangesEraseUserData_Ok(t *testing.T) {
+	// Arrange
+	userWatcherMock := mocks.NewUserWatcher(t)
+	userWatcherMock.On(testutils.FunctionName(t, ports.UserWatcher.EraseStates), context.Background(), "test-id").Return(nil).Once()
+
+	service := &userService{
+		config:  config.Config{},
+		watcher: userWatcherMock,
+	}
+
+	// Act
+	err := service.EraseUserData(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
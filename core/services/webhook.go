@@ -0,0 +1,478 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+const (
+	// WebhookSignatureHeader header holding the HMAC-SHA256 signature of the deliveries, as sha256={hex}
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	// WebhookTimestampHeader header holding the unix timestamp the delivery was signed at
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	// WebhookDeliveryIDHeader header holding the ID of the delivery, which is kept across retries
+	WebhookDeliveryIDHeader = "X-Webhook-Delivery-Id"
+	// WebhookEventTypeHeader header holding the type of the delivered event
+	WebhookEventTypeHeader = "X-Webhook-Event-Type"
+
+	// defaultWebhookLease time the claimed deliveries are postponed by when no lease is configured
+	defaultWebhookLease = 5 * time.Minute
+	// defaultWebhookConcurrency number of the claimed deliveries sent at once when no concurrency is configured
+	defaultWebhookConcurrency = 10
+	// webhookLeaseMargin fraction of the lease left to record the outcome of the deliveries sent, as no delivery is sent after the rest
+	webhookLeaseMargin = 10
+)
+
+// webhookService adapter of a webhook service
+type webhookService struct {
+	config        config.Config
+	subscriptions ports.WebhookSubscriptionRepository
+	deliveries    ports.WebhookDeliveryRepository
+	sender        ports.WebhookSender
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(cfg config.Config, subscriptionRepo ports.WebhookSubscriptionRepository, deliveryRepo ports.WebhookDeliveryRepository, sender ports.WebhookSender) ports.WebhookService {
+	return &webhookService{
+		config:        cfg,
+		subscriptions: subscriptionRepo,
+		deliveries:    deliveryRepo,
+		sender:        sender,
+	}
+}
+
+// CreateSubscription webhook subscription
+func (s *webhookService) CreateSubscription(ctx context.Context, req models.CreateWebhookSubscriptionReq) (resp models.CreateWebhookSubscriptionResp, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if err = validateEventTypes(req.EventTypes); err != nil {
+		return
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		secret, err = newWebhookSecret()
+		if err != nil {
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	ID, err := s.subscriptions.Create(ctx, entities.WebhookSubscription{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     secret,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		return
+	}
+
+	resp = models.CreateWebhookSubscriptionResp{
+		ID:     ID,
+		Secret: secret,
+	}
+	return
+}
+
+func validateEventTypes(eventTypes []string) error {
+	for _, eventType := range eventTypes {
+		if !entities.EventType(eventType).IsValid() {
+			return wrappers.NewValidationErr(fmt.Errorf("event type %s is not valid", eventType))
+		}
+	}
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// GetSubscriptions webhook subscriptions
+func (s *webhookService) GetSubscriptions(ctx context.Context) (resp []models.WebhookSubscriptionResp, err error) {
+	subscriptions, err := s.subscriptions.GetAll(ctx)
+	if err != nil {
+		return
+	}
+
+	resp = []models.WebhookSubscriptionResp{}
+	for _, subscription := range subscriptions {
+		resp = append(resp, toWebhookSubscriptionResp(subscription))
+	}
+	return
+}
+
+// GetSubscriptionByID webhook subscription
+func (s *webhookService) GetSubscriptionByID(ctx context.Context, ID string) (resp models.WebhookSubscriptionResp, err error) {
+	subscription, err := s.getSubscription(ctx, ID)
+	if err != nil {
+		return
+	}
+
+	resp = toWebhookSubscriptionResp(subscription)
+	return
+}
+
+func (s *webhookService) getSubscription(ctx context.Context, ID string) (entities.WebhookSubscription, error) {
+	subscription, err := s.subscriptions.GetByID(ctx, ID)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ID))
+	}
+	return subscription, err
+}
+
+func toWebhookSubscriptionResp(subscription entities.WebhookSubscription) models.WebhookSubscriptionResp {
+	return models.WebhookSubscriptionResp{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+	}
+}
+
+// UpdateSubscription webhook subscription
+func (s *webhookService) UpdateSubscription(ctx context.Context, ID string, req models.UpdateWebhookSubscriptionReq) (err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+
+	subscription, err := s.getSubscription(ctx, ID)
+	if err != nil {
+		return
+	}
+
+	if req.URL != nil {
+		subscription.URL = *req.URL
+	}
+	if req.EventTypes != nil {
+		if err = validateEventTypes(*req.EventTypes); err != nil {
+			return
+		}
+		subscription.EventTypes = *req.EventTypes
+	}
+	if req.Secret != nil {
+		subscription.Secret = *req.Secret
+	}
+	subscription.ID = ""
+	subscription.UpdatedAt = time.Now().UTC()
+
+	err = s.subscriptions.Update(ctx, ID, subscription)
+	return
+}
+
+// DeleteSubscription webhook subscription, along with its deliveries
+func (s *webhookService) DeleteSubscription(ctx context.Context, ID string) (err error) {
+	err = s.subscriptions.Delete(ctx, ID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ID))
+		}
+		return
+	}
+
+	err = s.deliveries.DeleteBySubscription(ctx, ID)
+	return
+}
+
+// GetDeadLetters returns the deliveries that ran out of attempts
+func (s *webhookService) GetDeadLetters(ctx context.Context) (resp []models.WebhookDeliveryResp, err error) {
+	deliveries, err := s.deliveries.GetByStatus(ctx, entities.WebhookDeliveryStatusDead)
+	if err != nil {
+		return
+	}
+
+	resp = []models.WebhookDeliveryResp{}
+	for _, delivery := range deliveries {
+		resp = append(resp, models.WebhookDeliveryResp{
+			ID:             delivery.ID,
+			SubscriptionID: delivery.SubscriptionID,
+			EventID:        delivery.EventID,
+			EventType:      string(delivery.EventType),
+			Attempts:       delivery.Attempts,
+			LastError:      delivery.LastError,
+			CreatedAt:      delivery.CreatedAt,
+		})
+	}
+	return
+}
+
+// ReplayDeadLetter makes a dead delivery pending again, with a fresh set of attempts
+func (s *webhookService) ReplayDeadLetter(ctx context.Context, ID string) (err error) {
+	delivery, err := s.deliveries.GetByID(ctx, ID)
+	if err != nil {
+		if errors.Is(err, wrappers.NonExistentErr) {
+			err = wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ID))
+		}
+		return
+	}
+
+	if delivery.Status != entities.WebhookDeliveryStatusDead {
+		err = wrappers.NewValidationErr(fmt.Errorf("delivery %s is not a dead letter", ID))
+		return
+	}
+
+	delivery.Status = entities.WebhookDeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now().UTC()
+	delivery.LastError = ""
+
+	err = s.deliveries.Update(ctx, delivery)
+	return
+}
+
+// Publish enqueues a delivery of the event for every subscription matching its type.
+// Failing to enqueue them fails the publication, so the outbox publishes the event again.
+func (s *webhookService) Publish(ctx context.Context, event entities.Event) error {
+	subscriptions, err := s.subscriptions.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(models.WebhookMessage{
+		ID:          event.ID,
+		Type:        string(event.Type),
+		AggregateID: event.AggregateID,
+		Payload:     event.Payload,
+		OccurredAt:  event.OccurredAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var deliveries []entities.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !subscription.Matches(event.Type) {
+			continue
+		}
+		deliveries = append(deliveries, entities.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			AggregateID:    event.AggregateID,
+			Body:           body,
+			Status:         entities.WebhookDeliveryStatusPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return s.deliveries.CreateMany(ctx, deliveries)
+}
+
+// Deliver sends the pending deliveries that are due, up to the configured concurrency at once, and returns how many got delivered.
+// A failed delivery is retried with exponential backoff, and moved to the dead letters after the maximum attempts.
+func (s *webhookService) Deliver(ctx context.Context) (delivered int, err error) {
+	lease := s.config.Webhooks.Lease.Duration
+	if lease <= 0 {
+		lease = defaultWebhookLease
+	}
+
+	concurrency := s.config.Webhooks.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultWebhookConcurrency
+	}
+
+	now := time.Now().UTC()
+	leaseUntil := now.Add(lease)
+	deliveries, err := s.deliveries.Claim(ctx, now, leaseUntil, s.config.Webhooks.BatchSize)
+	if err != nil {
+		return
+	}
+
+	// the deliveries are only sent until shortly before their lease expires, so that none is sent or updated once another instance
+	// can claim it again, and the ones left unsent are claimed again when it expires
+	sendCtx, cancel := context.WithDeadline(ctx, leaseUntil.Add(-lease/webhookLeaseMargin))
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	pending := make(chan entities.WebhookDelivery)
+	for i := 0; i < concurrency && i < len(deliveries); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for delivery := range pending {
+				ok, deliverErr := s.deliver(ctx, sendCtx, delivery)
+
+				mu.Lock()
+				if ok {
+					delivered++
+				}
+				if deliverErr != nil && err == nil {
+					err = deliverErr
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, delivery := range deliveries {
+		select {
+		case pending <- delivery:
+		case <-sendCtx.Done():
+			break dispatch
+		}
+	}
+	close(pending)
+	wg.Wait()
+	return
+}
+
+// deliver sends a claimed delivery within sendCtx and records its outcome, reporting whether it was delivered.
+// A delivery whose send is cut by sendCtx is left as claimed, to be claimed again once its lease expires.
+func (s *webhookService) deliver(ctx, sendCtx context.Context, delivery entities.WebhookDelivery) (bool, error) {
+	now := time.Now().UTC()
+	sendErr := s.send(sendCtx, delivery, now)
+	if sendCtx.Err() != nil {
+		return false, nil
+	}
+
+	delivery.Attempts++
+	if sendErr == nil {
+		delivery.Status = entities.WebhookDeliveryStatusDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	} else {
+		delivery.LastError = sendErr.Error()
+		if delivery.Attempts >= s.config.Webhooks.MaxAttempts {
+			delivery.Status = entities.WebhookDeliveryStatusDead
+		} else {
+			delivery.NextAttemptAt = now.Add(s.backoff(delivery.Attempts))
+		}
+	}
+
+	if err := s.deliveries.Update(ctx, delivery); err != nil {
+		return false, err
+	}
+	return sendErr == nil, nil
+}
+
+func (s *webhookService) send(ctx context.Context, delivery entities.WebhookDelivery, now time.Time) error {
+	subscription, err := s.getSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	headers := map[string]string{
+		WebhookSignatureHeader:  SignWebhook(subscription.Secret, timestamp, delivery.Body),
+		WebhookTimestampHeader:  timestamp,
+		WebhookDeliveryIDHeader: delivery.ID,
+		WebhookEventTypeHeader:  string(delivery.EventType),
+	}
+	return s.sender.Send(ctx, subscription.URL, headers, delivery.Body)
+}
+
+// Name returns the name of the store, used in the data subject archives and tombstones
+func (s *webhookService) Name() string {
+	return entities.EntityNameWebhookDelivery
+}
+
+// webhookDeliveryData exported representation of a delivery of an event of a user
+type webhookDeliveryData struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscription_id"`
+	EventType      string          `json:"event_type"`
+	Status         string          `json:"status"`
+	Body           json.RawMessage `json:"body"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+}
+
+// ExportUserData returns the deliveries of the events of the user, with the bodies sent to the subscriptions
+func (s *webhookService) ExportUserData(ctx context.Context, userID string) (interface{}, error) {
+	deliveries, err := s.deliveries.GetByAggregateID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := []webhookDeliveryData{}
+	for _, delivery := range deliveries {
+		data = append(data, webhookDeliveryData{
+			ID:             delivery.ID,
+			SubscriptionID: delivery.SubscriptionID,
+			EventType:      string(delivery.EventType),
+			Status:         string(delivery.Status),
+			Body:           delivery.Body,
+			CreatedAt:      delivery.CreatedAt,
+			DeliveredAt:    delivery.DeliveredAt,
+		})
+	}
+	return data, nil
+}
+
+// EraseUserData replaces the payloads in the bodies of the deliveries of the events of the user by one only holding its ID.
+// The deliveries themselves are kept, so the pending ones are still delivered.
+func (s *webhookService) EraseUserData(ctx context.Context, userID string) error {
+	deliveries, err := s.deliveries.GetByAggregateID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		var message models.WebhookMessage
+		if err = json.Unmarshal(delivery.Body, &message); err != nil {
+			return err
+		}
+		message.Payload = map[string]interface{}{"id": userID}
+
+		body, err := json.Marshal(message)
+		if err != nil {
+			return err
+		}
+		if err = s.deliveries.UpdateBody(ctx, delivery.ID, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backoff returns the delay before the next attempt of a delivery that failed the given attempts
+func (s *webhookService) backoff(attempts int) time.Duration {
+	return exponentialBackoff(s.config.Webhooks.InitialBackoff.Duration, s.config.Webhooks.MaxBackoff.Duration, attempts)
+}
+
+// exponentialBackoff returns the delay before the next attempt, doubling the initial backoff on every failed attempt up to the maximum
+func exponentialBackoff(initial, maximum time.Duration, attempts int) time.Duration {
+	delay := initial
+	for i := 1; i < attempts && delay < maximum; i++ {
+		delay *= 2
+	}
+	return min(delay, maximum)
+}
+
+// SignWebhook returns the signature of a delivery, the HMAC-SHA256 of "{timestamp}.{body}" keyed with the subscription secret
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
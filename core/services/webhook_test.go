@@ -0,0 +1,530 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNewWebhookService_Ok checks that NewWebhookService creates a new webhookService struct
+func TestNewWebhookService_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	subscriptionRepositoryMock := mocks.NewWebhookSubscriptionRepository(t)
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	senderMock := mocks.NewWebhookSender(t)
+
+	// Act
+	service := NewWebhookService(cfg, subscriptionRepositoryMock, deliveryRepositoryMock, senderMock)
+
+	// Assert
+	assert.NotEmpty(t, service)
+}
+
+// TestCreateSubscription_GeneratedSecret checks that CreateSubscription generates a secret when none is provided and returns it
+func TestCreateSubscription_GeneratedSecret(t *testing.T) {
+	// Arrange
+	subscriptionRepositoryMock := mocks.NewWebhookSubscriptionRepository(t)
+	subscriptionRepositoryMock.On(testutils.FunctionName(t, ports.WebhookSubscriptionRepository.Create), context.Background(), mock.MatchedBy(func(s entities.WebhookSubscription) bool {
+		return s.URL == "https://test.com/hook" && len(s.Secret) == 64
+	})).Return("test-id", nil).Once()
+
+	service := &webhookService{
+		subscriptions: subscriptionRepositoryMock,
+	}
+
+	// Act
+	resp, err := service.CreateSubscription(context.Background(), models.CreateWebhookSubscriptionReq{
+		URL:        "https://test.com/hook",
+		EventTypes: []string{string(entities.EventTypeUserCreated)},
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", resp.ID)
+	assert.Len(t, resp.Secret, 64)
+}
+
+// TestCreateSubscription_InvalidURL checks that CreateSubscription returns a validation error when the URL is not valid
+func TestCreateSubscription_InvalidURL(t *testing.T) {
+	// Arrange
+	service := &webhookService{}
+
+	// Act
+	_, err := service.CreateSubscription(context.Background(), models.CreateWebhookSubscriptionReq{URL: "ftp://test.com"})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
+
+// TestCreateSubscription_InvalidEventType checks that CreateSubscription returns a validation error when an event type is not valid
+func TestCreateSubscription_InvalidEventType(t *testing.T) {
+	// Arrange
+	service := &webhookService{}
+
+	// Act
+	_, err := service.CreateSubscription(context.Background(), models.CreateWebhookSubscriptionReq{
+		URL:        "https://test.com/hook",
+		EventTypes: []string{"invalid"},
+	})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "event type invalid is not valid", err.Error())
+}
+
+// TestGetSubscriptionByID_NotFound checks that GetSubscriptionByID returns a non existent error when the subscription is not found
+func TestGetSubscriptionByID_NotFound(t *testing.T) {
+	// Arrange
+	subscriptionRepositoryMock := mocks.NewWebhookSubscriptionRepository(t)
+	subscriptionRepositoryMock.On(testutils.FunctionName(t, ports.WebhookSubscriptionRepository.GetByID), context.Background(), "test-id").Return(entities.WebhookSubscription{}, wrappers.NonExistentErr).Once()
+
+	service := &webhookService{
+		subscriptions: subscriptionRepositoryMock,
+	}
+
+	// Act
+	_, err := service.GetSubscriptionByID(context.Background(), "test-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	assert.Equal(t, "ID test-id not found", err.Error())
+}
+
+// TestUpdateSubscription_Ok checks that UpdateSubscription only changes the provided fields
+func TestUpdateSubscription_Ok(t *testing.T) {
+	// Arrange
+	existing := entities.WebhookSubscription{
+		ID:         "test-id",
+		URL:        "https://test.com/hook",
+		EventTypes: []string{string(entities.EventTypeUserCreated)},
+		Secret:     "test-secret",
+	}
+	newURL := "https://new.com/hook"
+
+	subscriptionRepositoryMock := mocks.NewWebhookSubscriptionRepository(t)
+	subscriptionRepositoryMock.On(testutils.FunctionName(t, ports.WebhookSubscriptionRepository.GetByID), context.Background(), "test-id").Return(existing, nil).Once()
+	subscriptionRepositoryMock.On(testutils.FunctionName(t, ports.WebhookSubscriptionRepository.Update), context.Background(), "test-id", mock.MatchedBy(func(s entities.WebhookSubscription) bool {
+		return s.URL == newURL && s.Secret == existing.Secret && len(s.EventTypes) == 1
+	})).Return(nil).Once()
+
+	service := &webhookService{
+		subscriptions: subscriptionRepositoryMock,
+	}
+
+	// Act
+	err := service.UpdateSubscription(context.Background(), "test-id", models.UpdateWebhookSubscriptionReq{URL: &newURL})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDeleteSubscription_Ok checks that DeleteSubscription deletes the subscription along with its deliveries
+func TestDeleteSubscription_Ok(t *testing.T) {
+	// Arrange
+	subscriptionRepositoryMock := mocks.NewWebhookSubscriptionRepository(t)
+	subscriptionRepositoryMock.On(testutils.FunctionName(t, ports.WebhookSubscriptionRepository.Delete), context.Background(), "test-id").Return(nil).Once()
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.DeleteBySubscription), context.Background(), "test-id").Return(nil).Once()
+
+	service := &webhookService{
+		subscriptions: subscriptionRepositoryMock,
+		deliveries:    deliveryRepositoryMock,
+	}
+
+	// Act
+	err := service.DeleteSubscription(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestReplayDeadLetter_Ok checks that ReplayDeadLetter makes a dead delivery pending again with a fresh set of attempts
+func TestReplayDeadLetter_Ok(t *testing.T) {
+	// Arrange
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.GetByID), context.Background(), "test-id").Return(entities.WebhookDelivery{
+		ID:        "test-id",
+		Status:    entities.WebhookDeliveryStatusDead,
+		Attempts:  8,
+		LastError: "send error",
+	}, nil).Once()
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Update), context.Background(), mock.MatchedBy(func(d entities.WebhookDelivery) bool {
+		return d.Status == entities.WebhookDeliveryStatusPending && d.Attempts == 0 && d.LastError == ""
+	})).Return(nil).Once()
+
+	service := &webhookService{
+		deliveries: deliveryRepositoryMock,
+	}
+
+	// Act
+	err := service.ReplayDeadLetter(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestReplayDeadLetter_NotDead checks that ReplayDeadLetter returns a validation error when the delivery is not a dead letter
+func TestReplayDeadLetter_NotDead(t *testing.T) {
+	// Arrange
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.GetByID), context.Background(), "test-id").Return(entities.WebhookDelivery{
+		ID:     "test-id",
+		Status: entities.WebhookDeliveryStatusPending,
+	}, nil).Once()
+
+	service := &webhookService{
+		deliveries: deliveryRepositoryMock,
+	}
+
+	// Act
+	err := service.ReplayDeadLetter(context.Background(), "test-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+}
+
+// TestPublish_Ok checks that Publish enqueues a delivery only for the subscriptions matching the event type
+func TestPublish_Ok(t *testing.T) {
+	// Arrange
+	event := entities.NewUserEvent(entities.EventTypeUserCreated, "test-user", &entities.User{Email: "test@test.com"})
+	subscriptions := []entities.WebhookSubscription{
+		{ID: "all"},
+		{ID: "created", EventTypes: []string{string(entities.EventTypeUserCreated)}},
+		{ID: "deleted", EventTypes: []string{string(entities.EventTypeUserDeleted)}},
+	}
+
+	subscriptionRepositoryMock := mocks.NewWebhookSubscriptionRepository(t)
+	subscriptionRepositoryMock.On(testutils.FunctionName(t, ports.WebhookSubscriptionRepository.GetAll), context.Background()).Return(subscriptions, nil).Once()
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.CreateMany), context.Background(), mock.MatchedBy(func(d []entities.WebhookDelivery) bool {
+		var message models.WebhookMessage
+		return len(d) == 2 && d[0].SubscriptionID == "all" && d[1].SubscriptionID == "created" &&
+			d[0].Status == entities.WebhookDeliveryStatusPending &&
+			json.Unmarshal(d[0].Body, &message) == nil && message.Type == string(entities.EventTypeUserCreated)
+	})).Return(nil).Once()
+
+	service := &webhookService{
+		subscriptions: subscriptionRepositoryMock,
+		deliveries:    deliveryRepositoryMock,
+	}
+
+	// Act
+	err := service.Publish(context.Background(), event)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestPublish_NoMatchingSubscriptions checks that Publish does not enqueue anything when no subscription matches the event type
+func TestPublish_NoMatchingSubscriptions(t *testing.T) {
+	// Arrange
+	subscriptionRepositoryMock := mocks.NewWebhookSubscriptionRepository(t)
+	subscriptionRepositoryMock.On(testutils.FunctionName(t, ports.WebhookSubscriptionRepository.GetAll), context.Background()).Return([]entities.WebhookSubscription{
+		{ID: "deleted", EventTypes: []string{string(entities.EventTypeUserDeleted)}},
+	}, nil).Once()
+
+	service := &webhookService{
+		subscriptions: subscriptionRepositoryMock,
+		deliveries:    mocks.NewWebhookDeliveryRepository(t),
+	}
+
+	// Act
+	err := service.Publish(context.Background(), entities.NewUserEvent(entities.EventTypeUserCreated, "test-user", nil))
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestDeliver_Ok checks that Deliver sends the claimed deliveries signed with the subscription secret and marks them as delivered
+func TestDeliver_Ok(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Webhooks.BatchSize = 10
+	cfg.Webhooks.Lease.Duration = 2 * time.Minute
+	delivery := entities.WebhookDelivery{
+		ID:             "test-id",
+		SubscriptionID: "test-subscription",
+		EventType:      entities.EventTypeUserCreated,
+		Body:           []byte(`{"id":"test-event"}`),
+		Status:         entities.WebhookDeliveryStatusPending,
+	}
+
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Claim), context.Background(), mock.Anything, mock.MatchedBy(func(leaseUntil time.Time) bool {
+		lease := time.Until(leaseUntil)
+		return lease > time.Minute && lease <= 2*time.Minute
+	}), cfg.Webhooks.BatchSize).Return([]entities.WebhookDelivery{delivery}, nil).Once()
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Update), context.Background(), mock.MatchedBy(func(d entities.WebhookDelivery) bool {
+		return d.Status == entities.WebhookDeliveryStatusDelivered && d.Attempts == 1 && d.DeliveredAt != nil
+	})).Return(nil).Once()
+	subscriptionRepositoryMock := mocks.NewWebhookSubscriptionRepository(t)
+	subscriptionRepositoryMock.On(testutils.FunctionName(t, ports.WebhookSubscriptionRepository.GetByID), mock.Anything, "test-subscription").Return(entities.WebhookSubscription{
+		ID:     "test-subscription",
+		URL:    "https://test.com/hook",
+		Secret: "test-secret",
+	}, nil).Once()
+	senderMock := mocks.NewWebhookSender(t)
+	senderMock.On(testutils.FunctionName(t, ports.WebhookSender.Send), mock.Anything, "https://test.com/hook", mock.MatchedBy(func(headers map[string]string) bool {
+		return headers[WebhookSignatureHeader] == SignWebhook("test-secret", headers[WebhookTimestampHeader], delivery.Body) &&
+			headers[WebhookDeliveryIDHeader] == "test-id" &&
+			headers[WebhookEventTypeHeader] == string(entities.EventTypeUserCreated)
+	}), delivery.Body).Return(nil).Once()
+
+	service := &webhookService{
+		config:        cfg,
+		subscriptions: subscriptionRepositoryMock,
+		deliveries:    deliveryRepositoryMock,
+		sender:        senderMock,
+	}
+
+	// Act
+	delivered, err := service.Deliver(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, delivered)
+}
+
+// TestDeliver_SendErrorRetried checks that Deliver reschedules a failed delivery with exponential backoff, claiming it with the default lease
+func TestDeliver_SendErrorRetried(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Webhooks.MaxAttempts = 8
+	cfg.Webhooks.InitialBackoff.Duration = time.Minute
+	cfg.Webhooks.MaxBackoff.Duration = time.Hour
+	delivery := entities.WebhookDelivery{
+		ID:             "test-id",
+		SubscriptionID: "test-subscription",
+		Status:         entities.WebhookDeliveryStatusPending,
+		Attempts:       2,
+	}
+
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Claim), context.Background(), mock.Anything, mock.MatchedBy(func(leaseUntil time.Time) bool {
+		lease := time.Until(leaseUntil)
+		return lease > defaultWebhookLease-time.Minute && lease <= defaultWebhookLease
+	}), 0).Return([]entities.WebhookDelivery{delivery}, nil).Once()
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Update), context.Background(), mock.MatchedBy(func(d entities.WebhookDelivery) bool {
+		delay := time.Until(d.NextAttemptAt)
+		return d.Status == entities.WebhookDeliveryStatusPending && d.Attempts == 3 && d.LastError == "send error" &&
+			delay > 3*time.Minute && delay <= 4*time.Minute
+	})).Return(nil).Once()
+	subscriptionRepositoryMock := mocks.NewWebhookSubscriptionRepository(t)
+	subscriptionRepositoryMock.On(testutils.FunctionName(t, ports.WebhookSubscriptionRepository.GetByID), mock.Anything, "test-subscription").Return(entities.WebhookSubscription{}, nil).Once()
+	senderMock := mocks.NewWebhookSender(t)
+	senderMock.On(testutils.FunctionName(t, ports.WebhookSender.Send), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("send error")).Once()
+
+	service := &webhookService{
+		config:        cfg,
+		subscriptions: subscriptionRepositoryMock,
+		deliveries:    deliveryRepositoryMock,
+		sender:        senderMock,
+	}
+
+	// Act
+	delivered, err := service.Deliver(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 0, delivered)
+}
+
+// TestDeliver_MaxAttemptsDead checks that Deliver moves a delivery to the dead letters when it runs out of attempts
+func TestDeliver_MaxAttemptsDead(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Webhooks.MaxAttempts = 3
+	delivery := entities.WebhookDelivery{
+		ID:             "test-id",
+		SubscriptionID: "test-subscription",
+		Status:         entities.WebhookDeliveryStatusPending,
+		Attempts:       2,
+	}
+
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Claim), context.Background(), mock.Anything, mock.Anything, 0).Return([]entities.WebhookDelivery{delivery}, nil).Once()
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Update), context.Background(), mock.MatchedBy(func(d entities.WebhookDelivery) bool {
+		return d.Status == entities.WebhookDeliveryStatusDead && d.Attempts == 3
+	})).Return(nil).Once()
+	subscriptionRepositoryMock := mocks.NewWebhookSubscriptionRepository(t)
+	subscriptionRepositoryMock.On(testutils.FunctionName(t, ports.WebhookSubscriptionRepository.GetByID), mock.Anything, "test-subscription").Return(entities.WebhookSubscription{}, nil).Once()
+	senderMock := mocks.NewWebhookSender(t)
+	senderMock.On(testutils.FunctionName(t, ports.WebhookSender.Send), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("send error")).Once()
+
+	service := &webhookService{
+		config:        cfg,
+		subscriptions: subscriptionRepositoryMock,
+		deliveries:    deliveryRepositoryMock,
+		sender:        senderMock,
+	}
+
+	// Act
+	delivered, err := service.Deliver(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 0, delivered)
+}
+
+// TestDeliver_Concurrent checks that Deliver sends the claimed deliveries at once, up to the configured concurrency
+func TestDeliver_Concurrent(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Webhooks.Concurrency = 2
+	deliveries := []entities.WebhookDelivery{
+		{ID: "test-id-1", SubscriptionID: "test-subscription"},
+		{ID: "test-id-2", SubscriptionID: "test-subscription"},
+		{ID: "test-id-3", SubscriptionID: "test-subscription"},
+	}
+
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Claim), context.Background(), mock.Anything, mock.Anything, 0).Return(deliveries, nil).Once()
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Update), context.Background(), mock.Anything).Return(nil).Times(3)
+	subscriptionRepositoryMock := mocks.NewWebhookSubscriptionRepository(t)
+	subscriptionRepositoryMock.On(testutils.FunctionName(t, ports.WebhookSubscriptionRepository.GetByID), mock.Anything, "test-subscription").Return(entities.WebhookSubscription{}, nil).Times(3)
+
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	senderMock := mocks.NewWebhookSender(t)
+	senderMock.On(testutils.FunctionName(t, ports.WebhookSender.Send), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}).Return(nil).Times(3)
+
+	service := &webhookService{
+		config:        cfg,
+		subscriptions: subscriptionRepositoryMock,
+		deliveries:    deliveryRepositoryMock,
+		sender:        senderMock,
+	}
+
+	// Act
+	delivered, err := service.Deliver(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 3, delivered)
+	assert.Equal(t, 2, maxInFlight)
+}
+
+// TestDeliver_LeaseExpiring checks that Deliver stops sending the deliveries before their lease expires,
+// leaving the ones cut or not sent to be claimed again
+func TestDeliver_LeaseExpiring(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Webhooks.Concurrency = 1
+	cfg.Webhooks.Lease.Duration = 100 * time.Millisecond
+	deliveries := []entities.WebhookDelivery{
+		{ID: "test-id-1", SubscriptionID: "test-subscription"},
+		{ID: "test-id-2", SubscriptionID: "test-subscription"},
+	}
+
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.Claim), context.Background(), mock.Anything, mock.Anything, 0).Return(deliveries, nil).Once()
+	subscriptionRepositoryMock := mocks.NewWebhookSubscriptionRepository(t)
+	subscriptionRepositoryMock.On(testutils.FunctionName(t, ports.WebhookSubscriptionRepository.GetByID), mock.Anything, "test-subscription").Return(entities.WebhookSubscription{}, nil).Once()
+	senderMock := mocks.NewWebhookSender(t)
+	senderMock.On(testutils.FunctionName(t, ports.WebhookSender.Send), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return(context.DeadlineExceeded).Once()
+
+	service := &webhookService{
+		config:        cfg,
+		subscriptions: subscriptionRepositoryMock,
+		deliveries:    deliveryRepositoryMock,
+		sender:        senderMock,
+	}
+
+	// Act
+	delivered, err := service.Deliver(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 0, delivered)
+}
+
+// TestBackoff_Capped checks that the backoff doubles on every attempt without exceeding the maximum
+func TestBackoff_Capped(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.Webhooks.InitialBackoff.Duration = 30 * time.Second
+	cfg.Webhooks.MaxBackoff.Duration = 5 * time.Minute
+	service := &webhookService{
+		config: cfg,
+	}
+
+	expected := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, delay := range expected {
+		t.Run(fmt.Sprintf("attempt %d", i+1), func(t *testing.T) {
+			// Act
+			got := service.backoff(i + 1)
+
+			// Assert
+			assert.Equal(t, delay, got)
+		})
+	}
+}
+
+// TestSignWebhook_Ok checks that SignWebhook returns the expected HMAC-SHA256 signature
+func TestSignWebhook_Ok(t *testing.T) {
+	// Arrange
+	expected := "sha256=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"
+
+	// Act
+	got := SignWebhook("secret", "1700000000", []byte(`{}`))
+
+	// Assert
+	assert.Equal(t, expected, got)
+}
+
+// TestWebhookEraseUserData_Ok checks that EraseUserData only keeps the ID of the user in the payloads of the bodies of its deliveries
+func TestWebhookEraseUserData_Ok(t *testing.T) {
+	// Arrange
+	body, _ := json.Marshal(models.WebhookMessage{
+		ID:          "test-event",
+		Type:        string(entities.EventTypeUserCreated),
+		AggregateID: "test-user",
+		Payload:     map[string]interface{}{"id": "test-user", "email": "test@test.com"},
+	})
+	delivery := entities.WebhookDelivery{ID: "test-id", AggregateID: "test-user", Body: body}
+
+	var redacted models.WebhookMessage
+	deliveryRepositoryMock := mocks.NewWebhookDeliveryRepository(t)
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.GetByAggregateID), context.Background(), "test-user").Return([]entities.WebhookDelivery{delivery}, nil).Once()
+	deliveryRepositoryMock.On(testutils.FunctionName(t, ports.WebhookDeliveryRepository.UpdateBody), context.Background(), delivery.ID, mock.Anything).Run(func(args mock.Arguments) {
+		_ = json.Unmarshal(args.Get(2).([]byte), &redacted)
+	}).Return(nil).Once()
+
+	service := &webhookService{deliveries: deliveryRepositoryMock}
+
+	// Act
+	err := service.EraseUserData(context.Background(), "test-user")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-event", redacted.ID)
+	assert.Equal(t, map[string]interface{}{"id": "test-user"}, redacted.Payload)
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLRU_SetAndGet checks that Get returns a copy of the value stored by Set
+func TestLRU_SetAndGet(t *testing.T) {
+	// Arrange
+	c := NewLRU(10)
+	value := []byte("test-value")
+	_ = c.Set(context.Background(), "test-key", value, time.Minute)
+	value[0] = 'X'
+
+	// Act
+	got, ok, err := c.Get(context.Background(), "test-key")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("test-value"), got)
+}
+
+// TestLRU_Expired checks that Get misses the entries whose TTL has elapsed
+func TestLRU_Expired(t *testing.T) {
+	// Arrange
+	c := NewLRU(10)
+	_ = c.Set(context.Background(), "test-key", []byte("test-value"), -time.Second)
+
+	// Act
+	_, ok, err := c.Get(context.Background(), "test-key")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+// TestLRU_Eviction checks that Set evicts the least recently used entry once the cache is full
+func TestLRU_Eviction(t *testing.T) {
+	// Arrange
+	c := NewLRU(2)
+	_ = c.Set(context.Background(), "test-key-1", []byte("1"), time.Minute)
+	_ = c.Set(context.Background(), "test-key-2", []byte("2"), time.Minute)
+	_, _, _ = c.Get(context.Background(), "test-key-1")
+
+	// Act
+	_ = c.Set(context.Background(), "test-key-3", []byte("3"), time.Minute)
+
+	// Assert
+	_, ok, _ := c.Get(context.Background(), "test-key-2")
+	assert.False(t, ok)
+	_, ok, _ = c.Get(context.Background(), "test-key-1")
+	assert.True(t, ok)
+	_, ok, _ = c.Get(context.Background(), "test-key-3")
+	assert.True(t, ok)
+}
+
+// TestLRU_Delete checks that Delete removes the given keys
+func TestLRU_Delete(t *testing.T) {
+	// Arrange
+	c := NewLRU(10)
+	_ = c.Set(context.Background(), "test-key-1", []byte("1"), time.Minute)
+	_ = c.Set(context.Background(), "test-key-2", []byte("2"), time.Minute)
+
+	// Act
+	err := c.Delete(context.Background(), "test-key-1", "test-key-3")
+
+	// Assert
+	assert.Nil(t, err)
+	_, ok, _ := c.Get(context.Background(), "test-key-1")
+	assert.False(t, ok)
+	_, ok, _ = c.Get(context.Background(), "test-key-2")
+	assert.True(t, ok)
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// RedisOptions options of a redis cache
+type RedisOptions struct {
+	Addr     string
+	Password string
+	DB       int
+	// Prefix prepended to every key, so that several applications can share the same database
+	Prefix string
+}
+
+// redisCache adapter of a cache kept in redis, shared between every instance of the API
+type redisCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedis creates a redis cache, failing when redis cannot be reached
+func NewRedis(ctx context.Context, opts RedisOptions) (ports.Cache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     opts.Addr,
+		Password: opts.Password,
+		DB:       opts.DB,
+	})
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &redisCache{
+		client: client,
+		prefix: opts.Prefix,
+	}, nil
+}
+
+func (c *redisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (c *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+}
+
+func (c *redisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+	return c.client.Del(ctx, prefixed...).Err()
+}
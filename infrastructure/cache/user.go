@@ -0,0 +1,308 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// userIDKeyPrefix prefix of the keys of the users, cached by ID
+	userIDKeyPrefix = "user:id:"
+	// userEmailKeyPrefix prefix of the keys of the normalized emails, cached as the IDs of their users
+	userEmailKeyPrefix = "user:email:"
+	// userInvalidatedKeyPrefix prefix of the keys marking the users recently invalidated, which are not cached until they expire
+	userInvalidatedKeyPrefix = "user:invalidated:"
+
+	// defaultInvalidationTTL time the invalidated users are not cached for when no time is configured
+	defaultInvalidationTTL = 5 * time.Second
+)
+
+// UserOptions options of the user cache
+type UserOptions struct {
+	// TTL of the users cached by ID
+	TTL time.Duration
+	// EmailTTL of the emails cached as the IDs of their users
+	EmailTTL time.Duration
+	// InvalidationTTL time the users are not cached for after being invalidated, so that the loads reading them before the write,
+	// or from a replica lagging behind it, do not cache them again. It should exceed the lag of the replicas.
+	InvalidationTTL time.Duration
+}
+
+// Metrics counters of the lookups of a cache, safe for concurrent use
+type Metrics struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// Stats returns the current counters
+func (m *Metrics) Stats() models.CacheStats {
+	return models.CacheStats{
+		Hits:   m.hits.Load(),
+		Misses: m.misses.Load(),
+	}
+}
+
+// userRepository decorator of a user repository with a read-through cache of the users looked up by ID and by normalized email.
+// Emails are cached as the IDs of their users, whose current email is checked on every lookup,
+// so invalidating the ID of a changed user is enough to never serve it from a stale entry.
+// The users are cached, and served out of a unit of work, without their password hashes, which are only read within one.
+type userRepository struct {
+	ports.UserRepository
+	store   ports.Cache
+	opts    UserOptions
+	metrics *Metrics
+	// group loads the users once for all the concurrent misses of the same key, so that an expired entry does not stampede the database
+	group singleflight.Group
+}
+
+// unitOfWork decorator of a unit of work running a cached user repository
+type unitOfWork struct {
+	ports.UnitOfWork
+	users *userRepository
+}
+
+// NewUserRepository decorates a user repository with a read-through cache kept in the given store, along with the metrics of its lookups.
+// The unit of work running the repository is decorated as well, so that the users read within a transaction, which may not get committed,
+// are never cached, and the users written within it are invalidated again once it ends.
+func NewUserRepository(repo ports.UserRepository, uow ports.UnitOfWork, store ports.Cache, opts UserOptions) (ports.UserRepository, ports.UnitOfWork, *Metrics) {
+	if opts.InvalidationTTL <= 0 {
+		opts.InvalidationTTL = defaultInvalidationTTL
+	}
+
+	r := &userRepository{
+		UserRepository: repo,
+		store:          store,
+		opts:           opts,
+		metrics:        &Metrics{},
+	}
+	return r, &unitOfWork{UnitOfWork: uow, users: r}, r.metrics
+}
+
+func (r *userRepository) GetByID(ctx context.Context, ID string) (entities.User, error) {
+	if inUnitOfWork(ctx) {
+		return r.UserRepository.GetByID(ctx, ID)
+	}
+
+	if user, ok := r.cachedUser(ctx, ID); ok {
+		r.metrics.hits.Add(1)
+		return user, nil
+	}
+
+	r.metrics.misses.Add(1)
+	return r.load(ctx, userIDKeyPrefix+ID, func(ctx context.Context) (entities.User, error) {
+		return r.UserRepository.GetByID(ctx, ID)
+	})
+}
+
+// Get serves from the cache the lookups of a user by its normalized email, and every other filter from the repository
+func (r *userRepository) Get(ctx context.Context, filter models.UserFilter, skip, take *int) ([]entities.User, error) {
+	email := filter.EmailNormalized
+	if inUnitOfWork(ctx) || email == "" || filter != (models.UserFilter{EmailNormalized: email}) || skip != nil || take != nil {
+		return r.UserRepository.Get(ctx, filter, skip, take)
+	}
+
+	if ID, ok := r.cachedID(ctx, email); ok {
+		if user, ok := r.cachedUser(ctx, ID); ok && user.EmailNormalized == email {
+			r.metrics.hits.Add(1)
+			return []entities.User{user}, nil
+		}
+	}
+
+	r.metrics.misses.Add(1)
+	user, err := r.load(ctx, userEmailKeyPrefix+email, func(ctx context.Context) (entities.User, error) {
+		users, err := r.UserRepository.Get(ctx, filter, nil, nil)
+		if err != nil {
+			return entities.User{}, err
+		}
+		return users[0], nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []entities.User{user}, nil
+}
+
+func (r *userRepository) Update(ctx context.Context, ID string, user entities.User) error {
+	err := r.UserRepository.Update(ctx, ID, user)
+	r.invalidate(ctx, ID)
+	return err
+}
+
+func (r *userRepository) Delete(ctx context.Context, ID string) error {
+	err := r.UserRepository.Delete(ctx, ID)
+	r.invalidate(ctx, ID)
+	return err
+}
+
+func (r *userRepository) UpdateMany(ctx context.Context, IDs []string, users []entities.User) error {
+	err := r.UserRepository.UpdateMany(ctx, IDs, users)
+	r.invalidate(ctx, IDs...)
+	return err
+}
+
+func (r *userRepository) DeleteMany(ctx context.Context, IDs []string) error {
+	err := r.UserRepository.DeleteMany(ctx, IDs)
+	r.invalidate(ctx, IDs...)
+	return err
+}
+
+// load reads a user missing from the cache and caches it, sharing a single read among the concurrent misses of the same key.
+// The read is not cancelled along with the caller that started it, as the rest of the callers wait for it as well.
+func (r *userRepository) load(ctx context.Context, key string, read func(ctx context.Context) (entities.User, error)) (entities.User, error) {
+	result, err, _ := r.group.Do(key, func() (interface{}, error) {
+		ctx := context.WithoutCancel(ctx)
+		user, err := read(ctx)
+		if err != nil {
+			return nil, err
+		}
+		user.PasswordHash = ""
+		r.set(ctx, user)
+		return user, nil
+	})
+	if err != nil {
+		return entities.User{}, err
+	}
+
+	// the users are shared among the callers of the same load, so they do not share their claims either
+	user := result.(entities.User)
+	if user.ClaimIDs != nil {
+		user.ClaimIDs = append([]int32{}, user.ClaimIDs...)
+	}
+	return user, nil
+}
+
+func (r *userRepository) cachedUser(ctx context.Context, ID string) (entities.User, bool) {
+	value, ok := r.get(ctx, userIDKeyPrefix+ID)
+	if !ok {
+		return entities.User{}, false
+	}
+
+	var user entities.User
+	if err := json.Unmarshal(value, &user); err != nil {
+		observability.Logger().Printf("failed to decode cached user %s: %s", ID, err)
+		return entities.User{}, false
+	}
+	return user, true
+}
+
+func (r *userRepository) cachedID(ctx context.Context, email string) (string, bool) {
+	value, ok := r.get(ctx, userEmailKeyPrefix+email)
+	return string(value), ok
+}
+
+// get reads a key of the store, where failures are logged and handled as misses so that the users are still served by the repository
+func (r *userRepository) get(ctx context.Context, key string) ([]byte, bool) {
+	value, ok, err := r.store.Get(ctx, key)
+	if err != nil {
+		observability.Logger().Printf("failed to read user cache key %s: %s", key, err)
+		return nil, false
+	}
+	return value, ok
+}
+
+// set caches a user unless it was recently invalidated, and removes it again when it gets invalidated while being cached,
+// so that a user read before a write, or from a lagging replica, is never cached after the write invalidated it
+func (r *userRepository) set(ctx context.Context, user entities.User) {
+	if r.invalidated(ctx, user.ID) {
+		return
+	}
+
+	value, err := json.Marshal(user)
+	if err != nil {
+		observability.Logger().Printf("failed to encode user %s to cache it: %s", user.ID, err)
+		return
+	}
+
+	if err = r.store.Set(ctx, userIDKeyPrefix+user.ID, value, r.opts.TTL); err != nil {
+		observability.Logger().Printf("failed to cache user %s: %s", user.ID, err)
+		return
+	}
+	if err = r.store.Set(ctx, userEmailKeyPrefix+user.EmailNormalized, []byte(user.ID), r.opts.EmailTTL); err != nil {
+		observability.Logger().Printf("failed to cache email of user %s: %s", user.ID, err)
+	}
+
+	if r.invalidated(ctx, user.ID) {
+		if err = r.store.Delete(ctx, userIDKeyPrefix+user.ID); err != nil {
+			observability.Logger().Printf("failed to invalidate cached user %s: %s", user.ID, err)
+		}
+	}
+}
+
+// invalidated tells whether the user was invalidated within the invalidation TTL, which is assumed when it cannot be told
+func (r *userRepository) invalidated(ctx context.Context, ID string) bool {
+	_, ok, err := r.store.Get(ctx, userInvalidatedKeyPrefix+ID)
+	if err != nil {
+		observability.Logger().Printf("failed to read user cache key %s: %s", userInvalidatedKeyPrefix+ID, err)
+		return true
+	}
+	return ok
+}
+
+// invalidate removes the users from the cache, marking them as invalidated first so that the loads in progress do not cache them again,
+// and records them to be removed again once the running unit of work ends.
+// A failure is logged, as the write is already applied, leaving the users stale for at most the TTL.
+func (r *userRepository) invalidate(ctx context.Context, IDs ...string) {
+	if len(IDs) == 0 {
+		return
+	}
+
+	for _, ID := range IDs {
+		if err := r.store.Set(ctx, userInvalidatedKeyPrefix+ID, []byte{1}, r.opts.InvalidationTTL); err != nil {
+			observability.Logger().Printf("failed to mark cached user %s as invalidated: %s", ID, err)
+		}
+	}
+
+	keys := make([]string, len(IDs))
+	for i, ID := range IDs {
+		keys[i] = userIDKeyPrefix + ID
+	}
+	if err := r.store.Delete(ctx, keys...); err != nil {
+		observability.Logger().Printf("failed to invalidate cached users %v: %s", IDs, err)
+	}
+
+	if written, ok := ctx.Value(uowKey{}).(*writtenUsers); ok {
+		written.add(IDs...)
+	}
+}
+
+// uowKey context key of the users written within the running unit of work
+type uowKey struct{}
+
+// writtenUsers IDs of the users written within a unit of work
+type writtenUsers struct {
+	mu  sync.Mutex
+	IDs []string
+}
+
+func (w *writtenUsers) add(IDs ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.IDs = append(w.IDs, IDs...)
+}
+
+func inUnitOfWork(ctx context.Context) bool {
+	_, ok := ctx.Value(uowKey{}).(*writtenUsers)
+	return ok
+}
+
+// Do runs fn in the decorated unit of work, invalidating the users written within it once it ends,
+// so that the entries cached by other requests while it was running do not outlive it
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inUnitOfWork(ctx) {
+		return u.UnitOfWork.Do(ctx, fn)
+	}
+
+	written := &writtenUsers{}
+	err := u.UnitOfWork.Do(context.WithValue(ctx, uowKey{}, written), fn)
+	u.users.invalidate(ctx, written.IDs...)
+	return err
+}
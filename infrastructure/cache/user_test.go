@@ -0,0 +1,331 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/test/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testUserOptions = UserOptions{TTL: time.Minute, EmailTTL: time.Minute}
+
+func newTestUser() entities.User {
+	return entities.User{ID: "test-id", Name: "test", Email: "Test@Test.com", EmailNormalized: "test@test.com", ClaimIDs: []int32{1}}
+}
+
+// TestGetByID_Cached checks that GetByID reads a user from the repository only once, serving the next lookups from the cache
+func TestGetByID_Cached(t *testing.T) {
+	// Arrange
+	expectedUser := newTestUser()
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, expectedUser.ID).Return(expectedUser, nil).Once()
+	repo, _, metrics := NewUserRepository(userRepositoryMock, mocks.NewUnitOfWork(t), NewLRU(10), testUserOptions)
+
+	// Act
+	_, err := repo.GetByID(context.Background(), expectedUser.ID)
+	user, cachedErr := repo.GetByID(context.Background(), expectedUser.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, cachedErr)
+	assert.Equal(t, expectedUser, user)
+	assert.Equal(t, models.CacheStats{Hits: 1, Misses: 1}, metrics.Stats())
+}
+
+// TestGetByID_Error checks that GetByID returns the errors of the repository without caching anything
+func TestGetByID_Error(t *testing.T) {
+	// Arrange
+	expectedError := errors.New("repository-error")
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, "test-id").Return(entities.User{}, expectedError).Twice()
+	repo, _, _ := NewUserRepository(userRepositoryMock, mocks.NewUnitOfWork(t), NewLRU(10), testUserOptions)
+
+	// Act
+	_, _ = repo.GetByID(context.Background(), "test-id")
+	_, err := repo.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+}
+
+// TestGetByID_Concurrent checks that GetByID reads a user from the repository only once for concurrent misses
+func TestGetByID_Concurrent(t *testing.T) {
+	// Arrange
+	expectedUser := newTestUser()
+	release := make(chan struct{})
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, expectedUser.ID).WaitUntil(time.After(100*time.Millisecond)).Return(expectedUser, nil).Once()
+	repo, _, _ := NewUserRepository(userRepositoryMock, mocks.NewUnitOfWork(t), NewLRU(10), testUserOptions)
+
+	// Act
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-release
+			user, err := repo.GetByID(context.Background(), expectedUser.ID)
+			assert.Nil(t, err)
+			assert.Equal(t, expectedUser, user)
+		}()
+	}
+	close(release)
+	wg.Wait()
+
+	// Assert
+	userRepositoryMock.AssertNumberOfCalls(t, testutils.FunctionName(t, ports.UserRepository.GetByID), 1)
+}
+
+// TestGet_ByEmailCached checks that Get serves the lookups of a user by its normalized email from the cache
+func TestGet_ByEmailCached(t *testing.T) {
+	// Arrange
+	expectedUser := newTestUser()
+	filter := models.UserFilter{EmailNormalized: expectedUser.EmailNormalized}
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), mock.Anything, filter, (*int)(nil), (*int)(nil)).Return([]entities.User{expectedUser}, nil).Once()
+	repo, _, metrics := NewUserRepository(userRepositoryMock, mocks.NewUnitOfWork(t), NewLRU(10), testUserOptions)
+
+	// Act
+	_, err := repo.Get(context.Background(), filter, nil, nil)
+	users, cachedErr := repo.Get(context.Background(), filter, nil, nil)
+	user, byIDErr := repo.GetByID(context.Background(), expectedUser.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, cachedErr)
+	assert.Nil(t, byIDErr)
+	assert.Equal(t, []entities.User{expectedUser}, users)
+	assert.Equal(t, expectedUser, user)
+	assert.Equal(t, models.CacheStats{Hits: 2, Misses: 1}, metrics.Stats())
+}
+
+// TestGet_OtherFilters checks that Get reads from the repository every filter other than a normalized email alone
+func TestGet_OtherFilters(t *testing.T) {
+	// Arrange
+	take := 1
+	filter := models.UserFilter{EmailNormalized: "test@test.com"}
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), mock.Anything, filter, (*int)(nil), &take).Return([]entities.User{newTestUser()}, nil).Twice()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), mock.Anything, models.UserFilter{}, (*int)(nil), (*int)(nil)).Return([]entities.User{newTestUser()}, nil).Twice()
+	repo, _, metrics := NewUserRepository(userRepositoryMock, mocks.NewUnitOfWork(t), NewLRU(10), testUserOptions)
+
+	// Act
+	for i := 0; i < 2; i++ {
+		_, _ = repo.Get(context.Background(), filter, nil, &take)
+		_, _ = repo.Get(context.Background(), models.UserFilter{}, nil, nil)
+	}
+
+	// Assert
+	assert.Equal(t, models.CacheStats{}, metrics.Stats())
+}
+
+// TestGet_ChangedEmail checks that Get does not serve a user whose email has changed since its email was cached
+func TestGet_ChangedEmail(t *testing.T) {
+	// Arrange
+	store := NewLRU(10)
+	changedUser := newTestUser()
+	changedUser.EmailNormalized = "new@test.com"
+	filter := models.UserFilter{EmailNormalized: "test@test.com"}
+
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, changedUser.ID).Return(changedUser, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Get), mock.Anything, filter, (*int)(nil), (*int)(nil)).Return(nil, errors.New("not found")).Once()
+	repo, _, _ := NewUserRepository(userRepositoryMock, mocks.NewUnitOfWork(t), store, testUserOptions)
+	_ = store.Set(context.Background(), userEmailKeyPrefix+filter.EmailNormalized, []byte(changedUser.ID), time.Minute)
+	_, _ = repo.GetByID(context.Background(), changedUser.ID)
+
+	// Act
+	_, err := repo.Get(context.Background(), filter, nil, nil)
+
+	// Assert
+	assert.EqualError(t, err, "not found")
+}
+
+// TestUpdate_Invalidates checks that Update removes the updated user from the cache, even when the update fails
+func TestUpdate_Invalidates(t *testing.T) {
+	// Arrange
+	expectedUser := newTestUser()
+	expectedError := errors.New("repository-error")
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, expectedUser.ID).Return(expectedUser, nil).Twice()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), mock.Anything, expectedUser.ID, expectedUser).Return(expectedError).Once()
+	repo, _, metrics := NewUserRepository(userRepositoryMock, mocks.NewUnitOfWork(t), NewLRU(10), testUserOptions)
+	_, _ = repo.GetByID(context.Background(), expectedUser.ID)
+
+	// Act
+	err := repo.Update(context.Background(), expectedUser.ID, expectedUser)
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+	_, _ = repo.GetByID(context.Background(), expectedUser.ID)
+	assert.Equal(t, models.CacheStats{Misses: 2}, metrics.Stats())
+}
+
+// TestDeleteMany_Invalidates checks that DeleteMany removes the deleted users from the cache
+func TestDeleteMany_Invalidates(t *testing.T) {
+	// Arrange
+	expectedUser := newTestUser()
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, expectedUser.ID).Return(expectedUser, nil).Twice()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.DeleteMany), mock.Anything, []string{expectedUser.ID}).Return(nil).Once()
+	repo, _, metrics := NewUserRepository(userRepositoryMock, mocks.NewUnitOfWork(t), NewLRU(10), testUserOptions)
+	_, _ = repo.GetByID(context.Background(), expectedUser.ID)
+
+	// Act
+	err := repo.DeleteMany(context.Background(), []string{expectedUser.ID})
+
+	// Assert
+	assert.Nil(t, err)
+	_, _ = repo.GetByID(context.Background(), expectedUser.ID)
+	assert.Equal(t, models.CacheStats{Misses: 2}, metrics.Stats())
+}
+
+// TestDo_BypassesAndInvalidates checks that the users read within a unit of work are not cached,
+// and that the users written within it are invalidated again once it ends
+func TestDo_BypassesAndInvalidates(t *testing.T) {
+	// Arrange
+	store := NewLRU(10)
+	expectedUser := newTestUser()
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, expectedUser.ID).Return(expectedUser, nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), mock.Anything, expectedUser.ID, expectedUser).Return(nil).Once()
+	unitOfWorkMock := mocks.NewUnitOfWork(t)
+	unitOfWorkMock.On(testutils.FunctionName(t, ports.UnitOfWork.Do), mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}).Once()
+	repo, uow, metrics := NewUserRepository(userRepositoryMock, unitOfWorkMock, store, testUserOptions)
+
+	// Act
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		if _, err := repo.GetByID(ctx, expectedUser.ID); err != nil {
+			return err
+		}
+		if err := repo.Update(ctx, expectedUser.ID, expectedUser); err != nil {
+			return err
+		}
+		// an entry cached by a concurrent request while the unit of work is running
+		return store.Set(ctx, userIDKeyPrefix+expectedUser.ID, []byte("{}"), time.Minute)
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.CacheStats{}, metrics.Stats())
+	_, ok, _ := store.Get(context.Background(), userIDKeyPrefix+expectedUser.ID)
+	assert.False(t, ok)
+}
+
+// TestGetByID_StoreError checks that GetByID reads a user from the repository when the store fails,
+// without caching it as the store cannot tell whether it was invalidated
+func TestGetByID_StoreError(t *testing.T) {
+	// Arrange
+	expectedUser := newTestUser()
+	cacheMock := mocks.NewCache(t)
+	cacheMock.On(testutils.FunctionName(t, ports.Cache.Get), mock.Anything, userIDKeyPrefix+expectedUser.ID).Return(nil, false, errors.New("store-error")).Once()
+	cacheMock.On(testutils.FunctionName(t, ports.Cache.Get), mock.Anything, userInvalidatedKeyPrefix+expectedUser.ID).Return(nil, false, errors.New("store-error")).Once()
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, expectedUser.ID).Return(expectedUser, nil).Once()
+	repo, _, _ := NewUserRepository(userRepositoryMock, mocks.NewUnitOfWork(t), cacheMock, testUserOptions)
+
+	// Act
+	user, err := repo.GetByID(context.Background(), expectedUser.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedUser, user)
+}
+
+// TestGetByID_PasswordHash checks that GetByID neither caches nor serves the password hashes of the users out of a unit of work
+func TestGetByID_PasswordHash(t *testing.T) {
+	// Arrange
+	store := NewLRU(10)
+	user := newTestUser()
+	user.PasswordHash = "test-hash"
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, user.ID).Return(user, nil).Once()
+	repo, _, _ := NewUserRepository(userRepositoryMock, mocks.NewUnitOfWork(t), store, testUserOptions)
+
+	// Act
+	loaded, err := repo.GetByID(context.Background(), user.ID)
+	cached, cachedErr := repo.GetByID(context.Background(), user.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, cachedErr)
+	assert.Empty(t, loaded.PasswordHash)
+	assert.Empty(t, cached.PasswordHash)
+	value, _, _ := store.Get(context.Background(), userIDKeyPrefix+user.ID)
+	assert.NotContains(t, string(value), "test-hash")
+}
+
+// TestGetByID_InvalidatedWhileLoading checks that GetByID does not keep cached a user invalidated while it was being read
+func TestGetByID_InvalidatedWhileLoading(t *testing.T) {
+	// Arrange
+	store := NewLRU(10)
+	expectedUser := newTestUser()
+	userRepositoryMock := mocks.NewUserRepository(t)
+	repo, _, _ := NewUserRepository(userRepositoryMock, mocks.NewUnitOfWork(t), store, testUserOptions)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Update), mock.Anything, expectedUser.ID, expectedUser).Return(nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, expectedUser.ID).Run(func(mock.Arguments) {
+		// a write committed after the user was read
+		_ = repo.Update(context.Background(), expectedUser.ID, expectedUser)
+	}).Return(expectedUser, nil).Once()
+
+	// Act
+	_, err := repo.GetByID(context.Background(), expectedUser.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	_, ok, _ := store.Get(context.Background(), userIDKeyPrefix+expectedUser.ID)
+	assert.False(t, ok)
+}
+
+// TestGetByID_RecentlyInvalidated checks that GetByID does not cache a user read shortly after it was invalidated,
+// as it may have been read from a replica lagging behind the write
+func TestGetByID_RecentlyInvalidated(t *testing.T) {
+	// Arrange
+	expectedUser := newTestUser()
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.Delete), mock.Anything, expectedUser.ID).Return(nil).Once()
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.Anything, expectedUser.ID).Return(expectedUser, nil).Twice()
+	repo, _, metrics := NewUserRepository(userRepositoryMock, mocks.NewUnitOfWork(t), NewLRU(10), testUserOptions)
+	if err := repo.Delete(context.Background(), expectedUser.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, _ = repo.GetByID(context.Background(), expectedUser.ID)
+	_, err := repo.GetByID(context.Background(), expectedUser.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, models.CacheStats{Misses: 2}, metrics.Stats())
+}
+
+// TestGetByID_CancelledCaller checks that GetByID does not cancel the read shared by the concurrent misses along with its caller
+func TestGetByID_CancelledCaller(t *testing.T) {
+	// Arrange
+	expectedUser := newTestUser()
+	ctx, cancel := context.WithCancel(context.Background())
+	userRepositoryMock := mocks.NewUserRepository(t)
+	userRepositoryMock.On(testutils.FunctionName(t, ports.UserRepository.GetByID), mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Err() == nil
+	}), expectedUser.ID).Return(expectedUser, nil).Once()
+	repo, _, _ := NewUserRepository(userRepositoryMock, mocks.NewUnitOfWork(t), NewLRU(10), testUserOptions)
+	cancel()
+
+	// Act
+	user, err := repo.GetByID(ctx, expectedUser.ID)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedUser, user)
+}
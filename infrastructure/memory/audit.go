@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// auditRepository adapter of an audit repository kept in memory, events are kept in the order they were created
+type auditRepository struct {
+	mu     sync.Mutex
+	events []entities.AuditEvent
+}
+
+// NewAuditRepository creates an in-memory audit repository
+func NewAuditRepository() ports.AuditRepository {
+	return &auditRepository{}
+}
+
+func (r *auditRepository) Create(ctx context.Context, event entities.AuditEvent) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event.ID = newID()
+	r.events = append(r.events, event)
+
+	onRollback(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.events = removeByID(r.events, event.ID, func(event entities.AuditEvent) string { return event.ID })
+	})
+	return event.ID, nil
+}
+
+// List returns the events matching the filter from the newest to the oldest, the ones created later first when they share a timestamp
+func (r *auditRepository) List(_ context.Context, filter models.AuditFilter, offset, limit int) ([]entities.AuditEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := []entities.AuditEvent{}
+	for i := len(r.events) - 1; i >= 0; i-- {
+		event := r.events[i]
+		if (filter.ActorID == "" || event.ActorID == filter.ActorID) &&
+			(filter.Action == "" || event.Action == string(filter.Action)) &&
+			(filter.TargetID == "" || event.TargetID == filter.TargetID) &&
+			(filter.From.IsZero() || !event.Timestamp.Before(filter.From)) &&
+			(filter.To.IsZero() || event.Timestamp.Before(filter.To)) {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.After(events[j].Timestamp)
+	})
+
+	return page(events, offset, limit), nil
+}
+
+func (r *auditRepository) RedactTarget(ctx context.Context, targetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	redacted := make(map[string]entities.AuditEvent)
+	for i := range r.events {
+		if r.events[i].TargetID == targetID {
+			redacted[r.events[i].ID] = r.events[i]
+			r.events[i].Before = nil
+			r.events[i].After = nil
+		}
+	}
+
+	onRollback(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		for i := range r.events {
+			if event, ok := redacted[r.events[i].ID]; ok {
+				r.events[i].Before = event.Before
+				r.events[i].After = event.After
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAuditList_Ok checks that List returns the events matching the filter from the newest to the oldest
+func TestAuditList_Ok(t *testing.T) {
+	// Arrange
+	repo := NewAuditRepository()
+	now := time.Now().UTC()
+	for _, event := range []entities.AuditEvent{
+		{ActorID: "actor", RequestID: "old", Timestamp: now},
+		{ActorID: "other", RequestID: "other", Timestamp: now.Add(time.Second)},
+		{ActorID: "actor", RequestID: "new", Timestamp: now.Add(2 * time.Second)},
+	} {
+		if _, err := repo.Create(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Act
+	events, err := repo.List(context.Background(), models.AuditFilter{ActorID: "actor"}, 0, 10)
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, "new", events[0].RequestID)
+		assert.Equal(t, "old", events[1].RequestID)
+	}
+}
+
+// TestAuditRedactTarget_Ok checks that RedactTarget clears the values of the events targeting the given ID
+func TestAuditRedactTarget_Ok(t *testing.T) {
+	// Arrange
+	repo := NewAuditRepository()
+	_, err := repo.Create(context.Background(), entities.AuditEvent{
+		TargetID: "target",
+		Before:   map[string]interface{}{"name": "before"},
+		After:    map[string]interface{}{"name": "after"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = repo.RedactTarget(context.Background(), "target")
+
+	// Assert
+	assert.Nil(t, err)
+	events, _ := repo.List(context.Background(), models.AuditFilter{TargetID: "target"}, 0, 10)
+	if assert.Len(t, events, 1) {
+		assert.Nil(t, events[0].Before)
+		assert.Nil(t, events[0].After)
+	}
+}
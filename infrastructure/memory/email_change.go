@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// emailChangeRepository adapter of an email change repository kept in memory
+type emailChangeRepository struct {
+	mu      sync.Mutex
+	changes map[string]entities.EmailChange
+}
+
+// NewEmailChangeRepository creates an in-memory email change repository
+func NewEmailChangeRepository() ports.EmailChangeRepository {
+	return &emailChangeRepository{
+		changes: make(map[string]entities.EmailChange),
+	}
+}
+
+func (r *emailChangeRepository) Create(ctx context.Context, change entities.EmailChange) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	change.ID = newID()
+	restoreOnRollback(ctx, &r.mu, r.changes, change.ID)
+	r.changes[change.ID] = change
+	return change.ID, nil
+}
+
+func (r *emailChangeRepository) GetByID(_ context.Context, ID string) (entities.EmailChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	change, ok := r.changes[ID]
+	if !ok {
+		return entities.EmailChange{}, wrappers.NewNonExistentErr(errNotFound)
+	}
+	return change, nil
+}
+
+func (r *emailChangeRepository) GetByUserID(_ context.Context, userID string) ([]entities.EmailChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changes := []entities.EmailChange{}
+	for _, change := range r.changes {
+		if change.UserID == userID {
+			changes = append(changes, change)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].CreatedAt.Before(changes[j].CreatedAt)
+	})
+	return changes, nil
+}
+
+func (r *emailChangeRepository) Delete(ctx context.Context, ID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.changes[ID]; !ok {
+		return wrappers.NewNonExistentErr(errNotFound)
+	}
+	restoreOnRollback(ctx, &r.mu, r.changes, ID)
+	delete(r.changes, ID)
+	return nil
+}
+
+func (r *emailChangeRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var IDs []string
+	for ID, change := range r.changes {
+		if change.UserID == userID {
+			IDs = append(IDs, ID)
+		}
+	}
+	restoreOnRollback(ctx, &r.mu, r.changes, IDs...)
+	for _, ID := range IDs {
+		delete(r.changes, ID)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// idempotencyRepository adapter of an idempotency repository kept in memory.
+// Records are lost on restart and are not shared between instances.
+type idempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]entities.IdempotencyRecord
+	expiry  expiryHeap
+}
+
+// NewIdempotencyRepository creates an in-memory idempotency repository
+func NewIdempotencyRepository() ports.IdempotencyRepository {
+	return &idempotencyRepository{
+		records: make(map[string]entities.IdempotencyRecord),
+	}
+}
+
+func (r *idempotencyRepository) Get(_ context.Context, key string) (entities.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.records[key]
+	if !ok || !record.ExpiresAt.After(time.Now().UTC()) {
+		return entities.IdempotencyRecord{}, wrappers.NewNonExistentErr(errors.New("idempotency key not found"))
+	}
+
+	return record, nil
+}
+
+func (r *idempotencyRepository) Reserve(_ context.Context, record entities.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deleteExpired(record.CreatedAt)
+	if stored, ok := r.records[record.Key]; ok && stored.ExpiresAt.After(record.CreatedAt) {
+		return entities.NewAlreadyExistsErr(fmt.Errorf("idempotency key %s is reserved", record.Key))
+	}
+
+	record.Response = nil
+	r.set(record)
+	return nil
+}
+
+// Complete only updates the record while it is reserved with the token, so that a request whose reservation expired does not overwrite the one of a retry
+func (r *idempotencyRepository) Complete(_ context.Context, key, token string, response []byte, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.records[key]
+	if !ok || record.Token != token || record.Response != nil {
+		return entities.NewConflictErr(fmt.Errorf("idempotency key %s is no longer reserved by the request", key))
+	}
+
+	record.Response = response
+	record.ExpiresAt = expiresAt
+	r.set(record)
+	return nil
+}
+
+func (r *idempotencyRepository) Release(_ context.Context, key, token string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if record, ok := r.records[key]; ok && record.Token == token && record.Response == nil {
+		delete(r.records, key)
+	}
+	return nil
+}
+
+// set stores the record, indexing it by its expiration date
+func (r *idempotencyRepository) set(record entities.IdempotencyRecord) {
+	r.records[record.Key] = record
+	heap.Push(&r.expiry, expiryEntry{key: record.Key, expiresAt: record.ExpiresAt})
+}
+
+// deleteExpired deletes the records expired as of now, only visiting the expired entries of the index.
+// Entries left behind by records that were deleted or whose expiration changed are discarded.
+func (r *idempotencyRepository) deleteExpired(now time.Time) {
+	for r.expiry.Len() > 0 && !r.expiry[0].expiresAt.After(now) {
+		entry := heap.Pop(&r.expiry).(expiryEntry)
+		if record, ok := r.records[entry.key]; ok && record.ExpiresAt.Equal(entry.expiresAt) {
+			delete(r.records, entry.key)
+		}
+	}
+}
+
+// expiryEntry entry of the index of the records by their expiration date
+type expiryEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// expiryHeap min-heap of the records by their expiration date, implementing heap.Interface
+type expiryHeap []expiryEntry
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].expiresAt.Before(h[j].expiresAt) }
+func (h expiryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *expiryHeap) Push(x any) {
+	*h = append(*h, x.(expiryEntry))
+}
+
+func (h *expiryHeap) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
+}
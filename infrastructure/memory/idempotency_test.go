@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewIdempotencyRepository_Ok checks that NewIdempotencyRepository creates a new idempotencyRepository struct
+func TestNewIdempotencyRepository_Ok(t *testing.T) {
+	// Act
+	repo := NewIdempotencyRepository()
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestIdempotencyReserveAndGet_Ok checks that a reserved record can be retrieved in progress and once completed, before it expires
+func TestIdempotencyReserveAndGet_Ok(t *testing.T) {
+	// Arrange
+	repo := NewIdempotencyRepository()
+	now := time.Now().UTC()
+	record := entities.IdempotencyRecord{
+		Key:         "test-key",
+		Token:       "test-token",
+		RequestHash: "test-hash",
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Minute),
+	}
+	expiresAt := now.Add(time.Hour)
+
+	// Act
+	err := repo.Reserve(context.Background(), record)
+	reserved, getReservedErr := repo.Get(context.Background(), record.Key)
+	completeErr := repo.Complete(context.Background(), record.Key, record.Token, []byte("test-response"), expiresAt)
+	completed, getCompletedErr := repo.Get(context.Background(), record.Key)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, getReservedErr)
+	assert.Equal(t, record, reserved)
+	assert.Nil(t, completeErr)
+	assert.Nil(t, getCompletedErr)
+	assert.Equal(t, []byte("test-response"), completed.Response)
+	assert.Equal(t, expiresAt, completed.ExpiresAt)
+}
+
+// TestIdempotencyReserve_Reserved checks that Reserve returns an AlreadyExistsErr while the key has a record that has not expired
+func TestIdempotencyReserve_Reserved(t *testing.T) {
+	// Arrange
+	repo := NewIdempotencyRepository()
+	now := time.Now().UTC()
+	record := entities.IdempotencyRecord{Key: "test-key", CreatedAt: now, ExpiresAt: now.Add(time.Minute)}
+	if err := repo.Reserve(context.Background(), record); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err := repo.Reserve(context.Background(), record)
+
+	// Assert
+	assert.ErrorIs(t, err, entities.AlreadyExistsErr)
+}
+
+// TestIdempotencyReserve_Expired checks that Reserve replaces a record that has expired
+func TestIdempotencyReserve_Expired(t *testing.T) {
+	// Arrange
+	repo := NewIdempotencyRepository()
+	now := time.Now().UTC()
+	if err := repo.Reserve(context.Background(), entities.IdempotencyRecord{Key: "test-key", CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err := repo.Reserve(context.Background(), entities.IdempotencyRecord{Key: "test-key", CreatedAt: now, ExpiresAt: now.Add(time.Minute)})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestIdempotencyRelease_Ok checks that Release deletes a record in progress but keeps a completed one
+func TestIdempotencyRelease_Ok(t *testing.T) {
+	// Arrange
+	repo := NewIdempotencyRepository()
+	now := time.Now().UTC()
+	for _, key := range []string{"in-progress", "completed"} {
+		if err := repo.Reserve(context.Background(), entities.IdempotencyRecord{Key: key, Token: "test-token", CreatedAt: now, ExpiresAt: now.Add(time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.Complete(context.Background(), "completed", "test-token", []byte("test-response"), now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	inProgressErr := repo.Release(context.Background(), "in-progress", "test-token")
+	completedErr := repo.Release(context.Background(), "completed", "test-token")
+
+	// Assert
+	assert.Nil(t, inProgressErr)
+	assert.Nil(t, completedErr)
+	_, err := repo.Get(context.Background(), "in-progress")
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	_, err = repo.Get(context.Background(), "completed")
+	assert.Nil(t, err)
+}
+
+// TestIdempotencyComplete_OtherToken checks that Complete returns a ConflictErr and keeps the record when the key is reserved with another token
+func TestIdempotencyComplete_OtherToken(t *testing.T) {
+	// Arrange
+	repo := NewIdempotencyRepository()
+	now := time.Now().UTC()
+	if err := repo.Reserve(context.Background(), entities.IdempotencyRecord{Key: "test-key", Token: "test-token", CreatedAt: now, ExpiresAt: now.Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err := repo.Complete(context.Background(), "test-key", "other-token", []byte("test-response"), now.Add(time.Hour))
+
+	// Assert
+	assert.ErrorIs(t, err, entities.ConflictErr)
+	record, getErr := repo.Get(context.Background(), "test-key")
+	assert.Nil(t, getErr)
+	assert.Nil(t, record.Response)
+}
+
+// TestIdempotencyRelease_OtherToken checks that Release keeps the record when the key is reserved with another token
+func TestIdempotencyRelease_OtherToken(t *testing.T) {
+	// Arrange
+	repo := NewIdempotencyRepository()
+	now := time.Now().UTC()
+	if err := repo.Reserve(context.Background(), entities.IdempotencyRecord{Key: "test-key", Token: "test-token", CreatedAt: now, ExpiresAt: now.Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err := repo.Release(context.Background(), "test-key", "other-token")
+
+	// Assert
+	assert.Nil(t, err)
+	_, getErr := repo.Get(context.Background(), "test-key")
+	assert.Nil(t, getErr)
+}
+
+// TestIdempotencyGet_Expired checks that Get returns an error when the record has expired
+func TestIdempotencyGet_Expired(t *testing.T) {
+	// Arrange
+	repo := NewIdempotencyRepository()
+	now := time.Now().UTC()
+	err := repo.Reserve(context.Background(), entities.IdempotencyRecord{
+		Key:       "test-key",
+		CreatedAt: now.Add(-time.Minute),
+		ExpiresAt: now.Add(-time.Second),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, err = repo.Get(context.Background(), "test-key")
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+}
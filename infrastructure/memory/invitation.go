@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// invitationRepository adapter of an invitation repository kept in memory
+type invitationRepository struct {
+	mu          sync.Mutex
+	invitations map[string]entities.Invitation
+}
+
+// NewInvitationRepository creates an in-memory invitation repository
+func NewInvitationRepository() ports.InvitationRepository {
+	return &invitationRepository{
+		invitations: make(map[string]entities.Invitation),
+	}
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation entities.Invitation) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	invitation.ID = newID()
+	restoreOnRollback(ctx, &r.mu, r.invitations, invitation.ID)
+	r.invitations[invitation.ID] = invitation
+	return invitation.ID, nil
+}
+
+func (r *invitationRepository) GetAll(_ context.Context) ([]entities.Invitation, error) {
+	return r.find(func(entities.Invitation) bool { return true }), nil
+}
+
+func (r *invitationRepository) GetByUserID(_ context.Context, userID string) ([]entities.Invitation, error) {
+	return r.find(func(invitation entities.Invitation) bool { return invitation.UserID == userID }), nil
+}
+
+func (r *invitationRepository) GetByID(_ context.Context, ID string) (entities.Invitation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	invitation, ok := r.invitations[ID]
+	if !ok {
+		return entities.Invitation{}, wrappers.NewNonExistentErr(errNotFound)
+	}
+	return invitation, nil
+}
+
+func (r *invitationRepository) Update(ctx context.Context, ID string, invitation entities.Invitation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.invitations[ID]; !ok {
+		return wrappers.NewNonExistentErr(errNotFound)
+	}
+	restoreOnRollback(ctx, &r.mu, r.invitations, ID)
+	invitation.ID = ID
+	r.invitations[ID] = invitation
+	return nil
+}
+
+func (r *invitationRepository) Delete(ctx context.Context, ID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.invitations[ID]; !ok {
+		return wrappers.NewNonExistentErr(errNotFound)
+	}
+	restoreOnRollback(ctx, &r.mu, r.invitations, ID)
+	delete(r.invitations, ID)
+	return nil
+}
+
+func (r *invitationRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var IDs []string
+	for ID, invitation := range r.invitations {
+		if invitation.UserID == userID {
+			IDs = append(IDs, ID)
+		}
+	}
+	restoreOnRollback(ctx, &r.mu, r.invitations, IDs...)
+	for _, ID := range IDs {
+		delete(r.invitations, ID)
+	}
+	return nil
+}
+
+// find returns the invitations matching the given function, sorted by creation date
+func (r *invitationRepository) find(match func(invitation entities.Invitation) bool) []entities.Invitation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	invitations := []entities.Invitation{}
+	for _, invitation := range r.invitations {
+		if match(invitation) {
+			invitations = append(invitations, invitation)
+		}
+	}
+	sort.Slice(invitations, func(i, j int) bool {
+		if invitations[i].CreatedAt.Equal(invitations[j].CreatedAt) {
+			return invitations[i].ID < invitations[j].ID
+		}
+		return invitations[i].CreatedAt.Before(invitations[j].CreatedAt)
+	})
+	return invitations
+}
@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// errNotFound underlying error of the non existent errors returned by the in-memory repositories
+var errNotFound = errors.New("not found")
+
+// newID returns a random version 4 UUID, the same kind of IDs generated by postgres
+func newID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// page returns the items after skipping offset of them, up to limit items unless limit is not positive
+func page[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return items[:0]
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// removeByID returns the items without the one with the given ID, keeping the order of the rest
+func removeByID[T any](items []T, ID string, id func(item T) string) []T {
+	for i, item := range items {
+		if id(item) == ID {
+			return append(items[:i], items[i+1:]...)
+		}
+	}
+	return items
+}
@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// outboxRepository adapter of an outbox repository kept in memory, events are kept in the order they were added
+type outboxRepository struct {
+	mu     sync.Mutex
+	events []entities.Event
+}
+
+// NewOutboxRepository creates an in-memory outbox repository
+func NewOutboxRepository() ports.OutboxRepository {
+	return &outboxRepository{}
+}
+
+func (r *outboxRepository) Add(ctx context.Context, event entities.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event.ID = newID()
+	if event.NextAttemptAt.IsZero() {
+		event.NextAttemptAt = event.OccurredAt
+	}
+	r.events = append(r.events, event)
+
+	onRollback(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.events = removeByID(r.events, event.ID, func(event entities.Event) string { return event.ID })
+	})
+	return nil
+}
+
+func (r *outboxRepository) Pending(_ context.Context, limit int) ([]entities.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := []entities.Event{}
+	for _, event := range r.events {
+		if event.PublishedAt == nil && event.DeadAt == nil {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+
+	return events, nil
+}
+
+func (r *outboxRepository) Claim(_ context.Context, now, leaseUntil time.Time, limit int) ([]entities.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pending []int
+	for i, event := range r.events {
+		if event.PublishedAt == nil && event.DeadAt == nil {
+			pending = append(pending, i)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return r.events[pending[i]].OccurredAt.Before(r.events[pending[j]].OccurredAt)
+	})
+
+	// only the earliest pending event of every aggregate can be claimed
+	var due []int
+	held := make(map[string]bool)
+	for _, i := range pending {
+		if held[r.events[i].AggregateID] {
+			continue
+		}
+		held[r.events[i].AggregateID] = true
+		if !r.events[i].NextAttemptAt.After(now) {
+			due = append(due, i)
+		}
+	}
+
+	events := []entities.Event{}
+	for _, i := range page(due, 0, limit) {
+		r.events[i].NextAttemptAt = leaseUntil
+		events = append(events, r.events[i])
+	}
+	return events, nil
+}
+
+func (r *outboxRepository) MarkPublished(_ context.Context, ID string) error {
+	return r.update(ID, func(event *entities.Event) {
+		now := time.Now().UTC()
+		event.PublishedAt = &now
+		event.LastError = ""
+		event.Attempts++
+	})
+}
+
+func (r *outboxRepository) MarkFailed(_ context.Context, ID string, cause error, nextAttemptAt time.Time) error {
+	return r.update(ID, func(event *entities.Event) {
+		event.LastError = cause.Error()
+		event.NextAttemptAt = nextAttemptAt
+		event.Attempts++
+	})
+}
+
+func (r *outboxRepository) MarkDead(_ context.Context, ID string, cause error) error {
+	return r.update(ID, func(event *entities.Event) {
+		now := time.Now().UTC()
+		event.DeadAt = &now
+		event.LastError = cause.Error()
+		event.Attempts++
+	})
+}
+
+func (r *outboxRepository) DeletePublished(_ context.Context, before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.events[:0]
+	for _, event := range r.events {
+		if event.PublishedAt == nil || !event.PublishedAt.Before(before) {
+			kept = append(kept, event)
+		}
+	}
+	deleted := len(r.events) - len(kept)
+	r.events = kept
+
+	return deleted, nil
+}
+
+func (r *outboxRepository) GetByAggregateID(_ context.Context, aggregateID string) ([]entities.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := []entities.Event{}
+	for _, event := range r.events {
+		if event.AggregateID == aggregateID {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+
+	return events, nil
+}
+
+// RedactPayloads replaces the payloads of the events of the aggregate by one only holding its ID
+func (r *outboxRepository) RedactPayloads(ctx context.Context, aggregateID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payloads := make(map[string]map[string]interface{})
+	for i := range r.events {
+		if r.events[i].AggregateID == aggregateID {
+			payloads[r.events[i].ID] = r.events[i].Payload
+			r.events[i].Payload = map[string]interface{}{"id": aggregateID}
+		}
+	}
+
+	onRollback(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		for i := range r.events {
+			if payload, ok := payloads[r.events[i].ID]; ok {
+				r.events[i].Payload = payload
+			}
+		}
+	})
+	return nil
+}
+
+func (r *outboxRepository) update(ID string, fn func(event *entities.Event)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.events {
+		if r.events[i].ID == ID {
+			fn(&r.events[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// preferencesRepository adapter of a preferences repository kept in memory, keyed by the ID of the user
+type preferencesRepository struct {
+	mu          sync.Mutex
+	preferences map[string]entities.Preferences
+}
+
+// NewPreferencesRepository creates an in-memory preferences repository
+func NewPreferencesRepository() ports.PreferencesRepository {
+	return &preferencesRepository{
+		preferences: make(map[string]entities.Preferences),
+	}
+}
+
+func (r *preferencesRepository) GetByUserID(_ context.Context, userID string) (entities.Preferences, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	preferences, ok := r.preferences[userID]
+	if !ok {
+		return entities.Preferences{}, wrappers.NewNonExistentErr(errNotFound)
+	}
+	return preferences, nil
+}
+
+// Save stores the preferences only when the stored ones are at the previous version, or when there are none and the version is 1,
+// failing with a conflict error otherwise
+func (r *preferencesRepository) Save(ctx context.Context, preferences entities.Preferences) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.preferences[preferences.UserID]
+	if (!ok && preferences.Version != 1) || (ok && stored.Version != preferences.Version-1) {
+		return entities.NewOutdatedPreferencesErr(preferences.Version - 1)
+	}
+	restoreOnRollback(ctx, &r.mu, r.preferences, preferences.UserID)
+	r.preferences[preferences.UserID] = preferences
+	return nil
+}
+
+func (r *preferencesRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	restoreOnRollback(ctx, &r.mu, r.preferences, userID)
+	delete(r.preferences, userID)
+	return nil
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// tombstoneRepository adapter of a tombstone repository kept in memory
+type tombstoneRepository struct {
+	mu         sync.Mutex
+	tombstones []entities.Tombstone
+}
+
+// NewTombstoneRepository creates an in-memory tombstone repository
+func NewTombstoneRepository() ports.TombstoneRepository {
+	return &tombstoneRepository{}
+}
+
+func (r *tombstoneRepository) Create(ctx context.Context, tombstone entities.Tombstone) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tombstone.ID = newID()
+	r.tombstones = append(r.tombstones, tombstone)
+
+	onRollback(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.tombstones = removeByID(r.tombstones, tombstone.ID, func(tombstone entities.Tombstone) string { return tombstone.ID })
+	})
+	return tombstone.ID, nil
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// txKey context key of the transaction of the running unit of work
+type txKey struct{}
+
+// unitOfWork adapter of a unit of work for the in-memory repositories.
+// The writes made within a unit of work register how to undo them, and are undone in reverse order when it fails,
+// while the changes of the users are only published once it succeeds. Units of work are serialized, so that a failed one
+// never undoes the writes of another one, but the items it wrote lose the writes made to them meanwhile out of a unit of work.
+// The writes of the workers, of the webhook subscriptions and of the idempotency records are never made within a unit of work,
+// so they are not undone.
+type unitOfWork struct {
+	mu sync.Mutex
+}
+
+// NewUnitOfWork creates a unit of work for the in-memory repositories
+func NewUnitOfWork() ports.UnitOfWork {
+	return &unitOfWork{}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// a nested unit of work joins the running one
+	if _, ok := ctx.Value(txKey{}).(*transaction); ok {
+		return fn(ctx)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	tx := &transaction{}
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		tx.rollback()
+		return err
+	}
+	tx.commit()
+	return nil
+}
+
+// transaction of a unit of work, holding the functions undoing its writes and the ones to run once it commits
+type transaction struct {
+	mu        sync.Mutex
+	undos     []func()
+	committed []func()
+}
+
+// rollback undoes the writes, without holding the lock of the transaction as the undos take the ones of the repositories
+func (tx *transaction) rollback() {
+	tx.mu.Lock()
+	undos := tx.undos
+	tx.mu.Unlock()
+
+	for i := len(undos) - 1; i >= 0; i-- {
+		undos[i]()
+	}
+}
+
+func (tx *transaction) commit() {
+	tx.mu.Lock()
+	committed := tx.committed
+	tx.mu.Unlock()
+
+	for _, fn := range committed {
+		fn()
+	}
+}
+
+// onRollback registers undo to revert a write when the unit of work running in the context fails.
+// The write is final when no unit of work is running. undo runs without the locks of the repositories, so it must take them.
+func onRollback(ctx context.Context, undo func()) {
+	if tx, ok := ctx.Value(txKey{}).(*transaction); ok {
+		tx.mu.Lock()
+		defer tx.mu.Unlock()
+		tx.undos = append(tx.undos, undo)
+	}
+}
+
+// onCommit defers fn until the unit of work running in the context succeeds, and discards it if it fails.
+// When no unit of work is running, fn runs right away.
+func onCommit(ctx context.Context, fn func()) {
+	if tx, ok := ctx.Value(txKey{}).(*transaction); ok {
+		tx.mu.Lock()
+		defer tx.mu.Unlock()
+		tx.committed = append(tx.committed, fn)
+		return
+	}
+	fn()
+}
+
+// restoreOnRollback registers the restoring of the given items of the map to their current values when the unit of work
+// running in the context fails, deleting the ones that do not exist yet. It must be called holding mu, before writing the items.
+func restoreOnRollback[K comparable, V any](ctx context.Context, mu sync.Locker, items map[K]V, keys ...K) {
+	if _, ok := ctx.Value(txKey{}).(*transaction); !ok {
+		return
+	}
+
+	type previous struct {
+		value V
+		ok    bool
+	}
+	previousItems := make(map[K]previous, len(keys))
+	for _, key := range keys {
+		value, ok := items[key]
+		previousItems[key] = previous{value: value, ok: ok}
+	}
+
+	onRollback(ctx, func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for key, p := range previousItems {
+			if p.ok {
+				items[key] = p.value
+			} else {
+				delete(items, key)
+			}
+		}
+	})
+}
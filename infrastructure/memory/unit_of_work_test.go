@@ -0,0 +1,206 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUnitOfWorkDo_Ok checks that Do keeps the writes made within it and publishes the changes of the users when it succeeds
+func TestUnitOfWorkDo_Ok(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	outbox := NewOutboxRepository()
+	audit := NewAuditRepository()
+	repo, watcher := NewUserRepository(outbox)
+	uow := NewUnitOfWork()
+	var ID string
+
+	// Act
+	err := uow.Do(ctx, func(ctx context.Context) (err error) {
+		if ID, err = repo.Create(ctx, entities.User{EmailNormalized: "test@test.com"}); err != nil {
+			return err
+		}
+		_, err = audit.Create(ctx, entities.AuditEvent{TargetID: ID})
+		return err
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	_, err = repo.GetByID(ctx, ID)
+	assert.Nil(t, err)
+	events, _ := outbox.Pending(ctx, 10)
+	assert.Len(t, events, 1)
+	auditEvents, _ := audit.List(ctx, models.AuditFilter{TargetID: ID}, 0, 10)
+	assert.Len(t, auditEvents, 1)
+	changes, _ := watcher.GetByUserID(ctx, ID)
+	assert.Len(t, changes, 1)
+}
+
+// TestUnitOfWorkDo_Rollback checks that Do undoes the writes made within it and publishes no changes of the users when it fails
+func TestUnitOfWorkDo_Rollback(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	outbox := NewOutboxRepository()
+	audit := NewAuditRepository()
+	tombstones := NewTombstoneRepository().(*tombstoneRepository)
+	repo, watcher := NewUserRepository(outbox)
+	uow := NewUnitOfWork()
+	expectedErr := errors.New("uow-error")
+	var ID string
+
+	// Act
+	err := uow.Do(ctx, func(ctx context.Context) (err error) {
+		if ID, err = repo.Create(ctx, entities.User{EmailNormalized: "test@test.com"}); err != nil {
+			return err
+		}
+		if _, err = audit.Create(ctx, entities.AuditEvent{TargetID: ID}); err != nil {
+			return err
+		}
+		if _, err = tombstones.Create(ctx, entities.Tombstone{UserID: ID}); err != nil {
+			return err
+		}
+		return expectedErr
+	})
+
+	// Assert
+	assert.Equal(t, expectedErr, err)
+	_, err = repo.GetByID(ctx, ID)
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	events, _ := outbox.Pending(ctx, 10)
+	assert.Empty(t, events)
+	auditEvents, _ := audit.List(ctx, models.AuditFilter{TargetID: ID}, 0, 10)
+	assert.Empty(t, auditEvents)
+	assert.Empty(t, tombstones.tombstones)
+	changes, _ := watcher.GetByUserID(ctx, ID)
+	assert.Empty(t, changes)
+}
+
+// TestUnitOfWorkDo_RollbackDeletion checks that Do restores the users deleted within it along with their data when it fails
+func TestUnitOfWorkDo_RollbackDeletion(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	preferences := NewPreferencesRepository()
+	invitations := NewInvitationRepository()
+	emailChanges := NewEmailChangeRepository()
+	repo, _ := NewUserRepository(NewOutboxRepository(), preferences, invitations, emailChanges)
+	uow := NewUnitOfWork()
+	user := entities.User{Name: "test", EmailNormalized: "test@test.com"}
+	ID, _ := repo.Create(ctx, user)
+	_ = preferences.Save(ctx, entities.Preferences{UserID: ID, Version: 1})
+	invitationID, _ := invitations.Create(ctx, entities.Invitation{UserID: ID})
+	emailChangeID, _ := emailChanges.Create(ctx, entities.EmailChange{UserID: ID})
+
+	// Act
+	err := uow.Do(ctx, func(ctx context.Context) error {
+		if err := repo.Update(ctx, ID, entities.User{Name: "updated", EmailNormalized: "test@test.com"}); err != nil {
+			return err
+		}
+		if err := repo.Delete(ctx, ID); err != nil {
+			return err
+		}
+		return errors.New("uow-error")
+	})
+
+	// Assert
+	assert.NotNil(t, err)
+	restored, err := repo.GetByID(ctx, ID)
+	assert.Nil(t, err)
+	assert.Equal(t, user.Name, restored.Name)
+	_, err = preferences.GetByUserID(ctx, ID)
+	assert.Nil(t, err)
+	_, err = invitations.GetByID(ctx, invitationID)
+	assert.Nil(t, err)
+	_, err = emailChanges.GetByID(ctx, emailChangeID)
+	assert.Nil(t, err)
+}
+
+// TestUnitOfWorkDo_RollbackErasure checks that Do restores the redacted data of a user when it fails
+func TestUnitOfWorkDo_RollbackErasure(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	outbox := NewOutboxRepository()
+	audit := NewAuditRepository()
+	repo, watcher := NewUserRepository(outbox)
+	uow := NewUnitOfWork()
+	ID, _ := repo.Create(ctx, entities.User{Name: "test", EmailNormalized: "test@test.com"})
+	_, _ = audit.Create(ctx, entities.AuditEvent{TargetID: ID, After: map[string]interface{}{"name": "test"}})
+
+	// Act
+	err := uow.Do(ctx, func(ctx context.Context) error {
+		if err := outbox.RedactPayloads(ctx, ID); err != nil {
+			return err
+		}
+		if err := audit.RedactTarget(ctx, ID); err != nil {
+			return err
+		}
+		if err := watcher.EraseStates(ctx, ID); err != nil {
+			return err
+		}
+		return errors.New("uow-error")
+	})
+
+	// Assert
+	assert.NotNil(t, err)
+	events, _ := outbox.GetByAggregateID(ctx, ID)
+	if assert.Len(t, events, 1) {
+		assert.Contains(t, events[0].Payload, "name")
+	}
+	auditEvents, _ := audit.List(ctx, models.AuditFilter{TargetID: ID}, 0, 10)
+	if assert.Len(t, auditEvents, 1) {
+		assert.NotNil(t, auditEvents[0].After)
+	}
+	changes, _ := watcher.GetByUserID(ctx, ID)
+	if assert.Len(t, changes, 1) {
+		assert.NotNil(t, changes[0].User)
+	}
+}
+
+// TestUnitOfWorkDo_Nested checks that a nested Do joins the running unit of work, so that its writes are undone along with it
+func TestUnitOfWorkDo_Nested(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo, _ := NewUserRepository(NewOutboxRepository())
+	uow := NewUnitOfWork()
+	var ID string
+
+	// Act
+	err := uow.Do(ctx, func(ctx context.Context) error {
+		err := uow.Do(ctx, func(ctx context.Context) (err error) {
+			ID, err = repo.Create(ctx, entities.User{EmailNormalized: "test@test.com"})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		return errors.New("uow-error")
+	})
+
+	// Assert
+	assert.NotNil(t, err)
+	_, err = repo.GetByID(ctx, ID)
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+}
+
+// TestUnitOfWorkDo_Cancelled checks that Do does not run fn when the context is already cancelled
+func TestUnitOfWorkDo_Cancelled(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var called bool
+
+	// Act
+	err := NewUnitOfWork().Do(ctx, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, called)
+}
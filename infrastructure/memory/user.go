@@ -0,0 +1,424 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// maxUserChanges number of the last changes of the users kept to be watched
+const maxUserChanges = 1000
+
+// storedUser user kept in memory along with the sequence number of its creation, which sorts the users by insertion
+type storedUser struct {
+	seq  int64
+	user entities.User
+}
+
+// userRepository adapter of an user repository kept in memory.
+// Every operation runs as a transaction over a snapshot of the users that is only applied when the whole operation succeeds,
+// and emails are unique regardless of case. Users are lost on restart and are not shared between instances.
+type userRepository struct {
+	mu         sync.RWMutex
+	users      map[string]storedUser
+	seq        int64
+	outbox     ports.OutboxRepository
+	changes    *userChanges
+	dependents []userDependent
+}
+
+// userDependent repository of the data belonging to the users, deleted along with them
+type userDependent interface {
+	DeleteByUserID(ctx context.Context, userID string) error
+}
+
+// NewUserRepository creates an in-memory user repository along with the watcher of its changes.
+// The events of the user changes are added to the given outbox once the changes are applied,
+// and the data of the deleted users is removed from the given dependent repositories.
+func NewUserRepository(outbox ports.OutboxRepository, dependents ...userDependent) (ports.UserRepository, ports.UserWatcher) {
+	changes := newUserChanges(maxUserChanges)
+	return &userRepository{
+		users:      make(map[string]storedUser),
+		outbox:     outbox,
+		changes:    changes,
+		dependents: dependents,
+	}, changes
+}
+
+func (r *userRepository) Create(ctx context.Context, user entities.User) (string, error) {
+	var ID string
+	err := r.withTransaction(ctx, func(tx *userTx) (err error) {
+		ID, err = tx.create(user)
+		return
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return ID, nil
+}
+
+// Get returns the users matching the filter in order of creation
+func (r *userRepository) Get(_ context.Context, filter models.UserFilter, skip, take *int) ([]entities.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []storedUser
+	for _, stored := range r.users {
+		if matchesFilter(stored.user, filter) {
+			matched = append(matched, stored)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].seq < matched[j].seq
+	})
+
+	var offset, limit int
+	if skip != nil {
+		offset = *skip
+	}
+	if take != nil {
+		limit = *take
+	}
+
+	var users []entities.User
+	for _, stored := range page(matched, offset, limit) {
+		users = append(users, copyUser(stored.user))
+	}
+
+	if len(users) < 1 {
+		return nil, wrappers.NewNonExistentErr(errNotFound)
+	}
+
+	return users, nil
+}
+
+func (r *userRepository) GetByID(_ context.Context, ID string) (entities.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.users[ID]
+	if !ok {
+		return entities.User{}, wrappers.NewNonExistentErr(errNotFound)
+	}
+
+	return copyUser(stored.user), nil
+}
+
+func (r *userRepository) Update(ctx context.Context, ID string, user entities.User) error {
+	return r.withTransaction(ctx, func(tx *userTx) error {
+		return tx.update(ID, user)
+	})
+}
+
+func (r *userRepository) Delete(ctx context.Context, ID string) error {
+	return r.withTransaction(ctx, func(tx *userTx) error {
+		return tx.delete(ID)
+	})
+}
+
+func (r *userRepository) CreateMany(ctx context.Context, users []entities.User) ([]string, error) {
+	var result []string
+	err := r.withTransaction(ctx, func(tx *userTx) error {
+		for _, user := range users {
+			ID, err := tx.create(user)
+			if err != nil {
+				return err
+			}
+			result = append(result, ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *userRepository) UpdateMany(ctx context.Context, IDs []string, users []entities.User) error {
+	return r.withTransaction(ctx, func(tx *userTx) error {
+		for i, user := range users {
+			if err := tx.update(IDs[i], user); err != nil {
+				return withID(err, IDs[i])
+			}
+		}
+		return nil
+	})
+}
+
+func (r *userRepository) DeleteMany(ctx context.Context, IDs []string) error {
+	return r.withTransaction(ctx, func(tx *userTx) error {
+		for _, ID := range IDs {
+			if err := tx.delete(ID); err != nil {
+				return withID(err, ID)
+			}
+		}
+		return nil
+	})
+}
+
+// Iterate calls fn for every user matching the filter sorted by ID, over a snapshot of the users taken when called
+func (r *userRepository) Iterate(ctx context.Context, filter models.UserFilter, fn func(user entities.User) error) error {
+	r.mu.RLock()
+	var users []entities.User
+	for _, stored := range r.users {
+		if matchesFilter(stored.user, filter) {
+			users = append(users, copyUser(stored.user))
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+
+	for _, u := range users {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Search matches every word of the query as a case-insensitive substring of the name, the surnames and the email of the users,
+// ranking the matches in the name or the surnames above the ones in the email
+func (r *userRepository) Search(_ context.Context, query string, offset, limit int) ([]entities.User, error) {
+	terms := strings.Fields(strings.ToLower(query))
+
+	type scoredUser struct {
+		score int
+		user  entities.User
+	}
+
+	r.mu.RLock()
+	var matched []scoredUser
+	for _, stored := range r.users {
+		u := stored.user
+		var score int
+		for _, term := range terms {
+			if strings.Contains(strings.ToLower(u.Name), term) || strings.Contains(strings.ToLower(u.Surnames), term) {
+				score += 3
+			}
+			if strings.Contains(strings.ToLower(u.Email), term) {
+				score++
+			}
+		}
+		if score > 0 {
+			matched = append(matched, scoredUser{score: score, user: copyUser(u)})
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		if matched[i].score != matched[j].score {
+			return matched[i].score > matched[j].score
+		}
+		return matched[i].user.ID < matched[j].user.ID
+	})
+
+	users := []entities.User{}
+	for _, m := range page(matched, offset, limit) {
+		users = append(users, m.user)
+	}
+	return users, nil
+}
+
+// withTransaction runs fn over a transaction, whose writes are applied when fn succeeds and discarded otherwise.
+// Transactions are serialized, so they never conflict with each other. Within a unit of work, the applied writes are undone
+// when it fails, and the changes they raise are only published once it succeeds.
+func (r *userRepository) withTransaction(ctx context.Context, fn func(tx *userTx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx := &userTx{
+		repo:   r,
+		writes: make(map[string]*storedUser),
+		seq:    r.seq,
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	IDs := make([]string, 0, len(tx.writes))
+	for ID := range tx.writes {
+		IDs = append(IDs, ID)
+	}
+	restoreOnRollback(ctx, &r.mu, r.users, IDs...)
+	for ID, stored := range tx.writes {
+		if stored == nil {
+			delete(r.users, ID)
+		} else {
+			r.users[ID] = *stored
+		}
+	}
+	r.seq = tx.seq
+
+	for ID, stored := range tx.writes {
+		if stored != nil {
+			continue
+		}
+		for _, dependent := range r.dependents {
+			if err := dependent.DeleteByUserID(ctx, ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, event := range tx.events {
+		if err := r.outbox.Add(ctx, event); err != nil {
+			return err
+		}
+	}
+	onCommit(ctx, func() {
+		r.changes.add(tx.changes...)
+	})
+	return nil
+}
+
+// userTx transaction of the user repository, holding the writes to apply over the stored users,
+// where a nil user stands for a deleted one, along with the events and the changes they raise
+type userTx struct {
+	repo    *userRepository
+	writes  map[string]*storedUser
+	seq     int64
+	events  []entities.Event
+	changes []entities.UserChange
+}
+
+func (tx *userTx) get(ID string) (storedUser, bool) {
+	if stored, ok := tx.writes[ID]; ok {
+		if stored == nil {
+			return storedUser{}, false
+		}
+		return *stored, true
+	}
+	stored, ok := tx.repo.users[ID]
+	return stored, ok
+}
+
+func (tx *userTx) create(user entities.User) (string, error) {
+	if err := tx.checkEmail("", user.EmailNormalized); err != nil {
+		return "", err
+	}
+
+	tx.seq++
+	user = copyUser(user)
+	user.ID = newID()
+	tx.writes[user.ID] = &storedUser{seq: tx.seq, user: user}
+
+	tx.raise(entities.EventTypeUserCreated, entities.UserChangeTypeCreated, user.ID, &user)
+	return user.ID, nil
+}
+
+// update replaces every field of the user but its ID and its creation time
+func (tx *userTx) update(ID string, user entities.User) error {
+	stored, ok := tx.get(ID)
+	if !ok {
+		return wrappers.NewNonExistentErr(errNotFound)
+	}
+	if err := tx.checkEmail(ID, user.EmailNormalized); err != nil {
+		return err
+	}
+
+	user = copyUser(user)
+	user.ID = ID
+	user.CreatedAt = stored.user.CreatedAt
+	stored.user = user
+	tx.writes[ID] = &stored
+
+	tx.raise(entities.EventTypeUserUpdated, entities.UserChangeTypeUpdated, ID, &user)
+	return nil
+}
+
+func (tx *userTx) delete(ID string) error {
+	if _, ok := tx.get(ID); !ok {
+		return wrappers.NewNonExistentErr(errNotFound)
+	}
+	tx.writes[ID] = nil
+
+	tx.raise(entities.EventTypeUserDeleted, entities.UserChangeTypeDeleted, ID, nil)
+	return nil
+}
+
+// checkEmail fails when the normalized email belongs to a user other than the one with the given ID
+func (tx *userTx) checkEmail(ID, emailNormalized string) error {
+	for otherID, stored := range tx.writes {
+		if stored != nil && otherID != ID && stored.user.EmailNormalized == emailNormalized {
+			return entities.NewEmailInUseErr(emailNormalized)
+		}
+	}
+	for otherID, stored := range tx.repo.users {
+		if _, written := tx.writes[otherID]; !written && otherID != ID && stored.user.EmailNormalized == emailNormalized {
+			return entities.NewEmailInUseErr(emailNormalized)
+		}
+	}
+	return nil
+}
+
+// raise records the event and the change of the user, the user is nil when it got deleted
+func (tx *userTx) raise(eventType entities.EventType, changeType entities.UserChangeType, ID string, user *entities.User) {
+	tx.events = append(tx.events, entities.NewUserEvent(eventType, ID, user))
+
+	change := entities.UserChange{
+		Type:       changeType,
+		UserID:     ID,
+		OccurredAt: time.Now().UTC(),
+	}
+	if user != nil {
+		current := copyUser(*user)
+		change.User = &current
+	}
+	tx.changes = append(tx.changes, change)
+}
+
+// matchesFilter reports whether the user matches every field of the filter that is not a zero value
+func matchesFilter(u entities.User, filter models.UserFilter) bool {
+	return (filter.Name == "" || u.Name == filter.Name) &&
+		(filter.Surnames == "" || u.Surnames == filter.Surnames) &&
+		(filter.EmailNormalized == "" || u.EmailNormalized == filter.EmailNormalized) &&
+		(filter.CreatedFrom.IsZero() || !u.CreatedAt.Before(filter.CreatedFrom)) &&
+		(filter.CreatedTo.IsZero() || u.CreatedAt.Before(filter.CreatedTo)) &&
+		(filter.ClaimID == nil || hasClaim(u.ClaimIDs, *filter.ClaimID))
+}
+
+func hasClaim(claimIDs []int32, claimID int32) bool {
+	for _, ID := range claimIDs {
+		if ID == claimID {
+			return true
+		}
+	}
+	return false
+}
+
+// copyUser returns a copy of the user that does not share its claims, so that the stored users are never modified from outside
+func copyUser(user entities.User) entities.User {
+	if user.ClaimIDs != nil {
+		user.ClaimIDs = append([]int32{}, user.ClaimIDs...)
+	}
+	return user
+}
+
+// withID adds the affected ID to the message of NonExistentErr errors
+func withID(err error, ID string) error {
+	if errors.Is(err, wrappers.NonExistentErr) {
+		return wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ID))
+	}
+	return err
+}
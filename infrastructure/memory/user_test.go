@@ -0,0 +1,172 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/test/repositorytest"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUserRepository_Suite checks that the in-memory user repository passes the conformance test suite of the user repositories
+func TestUserRepository_Suite(t *testing.T) {
+	repo, _ := NewUserRepository(NewOutboxRepository())
+	repositorytest.UserRepository(t, repo)
+}
+
+// TestUserCreate_OutboxEvent checks that Create adds the created event of the user to the outbox
+func TestUserCreate_OutboxEvent(t *testing.T) {
+	// Arrange
+	outbox := NewOutboxRepository()
+	repo, _ := NewUserRepository(outbox)
+
+	// Act
+	ID, err := repo.Create(context.Background(), entities.User{EmailNormalized: "test@test.com"})
+
+	// Assert
+	assert.Nil(t, err)
+	events, _ := outbox.Pending(context.Background(), 10)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, entities.EventTypeUserCreated, events[0].Type)
+		assert.Equal(t, ID, events[0].AggregateID)
+	}
+}
+
+// TestUserCreateMany_RollbackDiscardsEvents checks that CreateMany adds no events to the outbox when it fails
+func TestUserCreateMany_RollbackDiscardsEvents(t *testing.T) {
+	// Arrange
+	outbox := NewOutboxRepository()
+	repo, _ := NewUserRepository(outbox)
+	users := []entities.User{{EmailNormalized: "test@test.com"}, {EmailNormalized: "test@test.com"}}
+
+	// Act
+	_, err := repo.CreateMany(context.Background(), users)
+
+	// Assert
+	assert.NotNil(t, err)
+	events, _ := outbox.Pending(context.Background(), 10)
+	assert.Empty(t, events)
+}
+
+// TestUserDeleteMany_Dependents checks that DeleteMany removes the data of the deleted users from the dependent repositories
+func TestUserDeleteMany_Dependents(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	preferences := NewPreferencesRepository()
+	invitations := NewInvitationRepository()
+	emailChanges := NewEmailChangeRepository()
+	repo, _ := NewUserRepository(NewOutboxRepository(), preferences, invitations, emailChanges)
+	deletedID, _ := repo.Create(ctx, entities.User{EmailNormalized: "deleted@test.com"})
+	keptID, _ := repo.Create(ctx, entities.User{EmailNormalized: "kept@test.com"})
+	for _, ID := range []string{deletedID, keptID} {
+		_ = preferences.Save(ctx, entities.Preferences{UserID: ID, Version: 1})
+		_, _ = invitations.Create(ctx, entities.Invitation{UserID: ID})
+		_, _ = emailChanges.Create(ctx, entities.EmailChange{UserID: ID})
+	}
+
+	// Act
+	err := repo.DeleteMany(ctx, []string{deletedID})
+
+	// Assert
+	assert.Nil(t, err)
+	_, err = preferences.GetByUserID(ctx, deletedID)
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	_, err = preferences.GetByUserID(ctx, keptID)
+	assert.Nil(t, err)
+	deleted, _ := invitations.GetByUserID(ctx, deletedID)
+	assert.Empty(t, deleted)
+	kept, _ := invitations.GetByUserID(ctx, keptID)
+	assert.Len(t, kept, 1)
+	deletedChanges, _ := emailChanges.GetByUserID(ctx, deletedID)
+	assert.Empty(t, deletedChanges)
+	keptChanges, _ := emailChanges.GetByUserID(ctx, keptID)
+	assert.Len(t, keptChanges, 1)
+}
+
+// TestUserIterate_Filter checks that Iterate only calls fn for the users matching the filter, sorted by ID
+func TestUserIterate_Filter(t *testing.T) {
+	// Arrange
+	repo, _ := NewUserRepository(NewOutboxRepository())
+	claimID := int32(0)
+	for _, user := range []entities.User{
+		{EmailNormalized: "a@test.com", ClaimIDs: []int32{claimID}},
+		{EmailNormalized: "b@test.com"},
+		{EmailNormalized: "c@test.com", ClaimIDs: []int32{claimID}},
+	} {
+		if _, err := repo.Create(context.Background(), user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Act
+	var emails []string
+	err := repo.Iterate(context.Background(), models.UserFilter{ClaimID: &claimID}, func(user entities.User) error {
+		emails = append(emails, user.EmailNormalized)
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"a@test.com", "c@test.com"}, emails)
+}
+
+// TestUserWatch_Ok checks that Watch calls fn for the changes made after it started, and resumes after the given token
+func TestUserWatch_Ok(t *testing.T) {
+	// Arrange
+	repo, watcher := NewUserRepository(NewOutboxRepository())
+	ID, err := repo.Create(context.Background(), entities.User{EmailNormalized: "test@test.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.Delete(context.Background(), ID); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Act
+	var changes []entities.UserChange
+	err = watcher.Watch(ctx, "1", func(change entities.UserChange) error {
+		changes = append(changes, change)
+		cancel()
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, changes, 1) {
+		assert.Equal(t, entities.UserChangeTypeDeleted, changes[0].Type)
+		assert.Equal(t, ID, changes[0].UserID)
+		assert.Equal(t, "2", changes[0].ResumeToken)
+		assert.Nil(t, changes[0].User)
+	}
+}
+
+// TestUserWatch_InvalidResumeToken checks that Watch returns a validation error when the resume token is not valid
+func TestUserWatch_InvalidResumeToken(t *testing.T) {
+	// Arrange
+	_, watcher := NewUserRepository(NewOutboxRepository())
+
+	// Act
+	err := watcher.Watch(context.Background(), "invalid", func(change entities.UserChange) error { return nil })
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.ValidationErr)
+}
+
+// TestUserWatch_Expired checks that Watch returns a validation error when the changes after the resume token are no longer kept
+func TestUserWatch_Expired(t *testing.T) {
+	// Arrange
+	changes := newUserChanges(1)
+	changes.add(entities.UserChange{}, entities.UserChange{})
+
+	// Act
+	err := changes.Watch(context.Background(), "0", func(change entities.UserChange) error { return nil })
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.ValidationErr)
+}
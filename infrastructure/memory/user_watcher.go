@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// userChanges adapter of a user watcher kept in memory, holding the last changes of the users.
+// The resume tokens are the sequence numbers of the changes, so watching can only be resumed after the changes still kept.
+type userChanges struct {
+	mu      sync.Mutex
+	changes []entities.UserChange
+	last    int64
+	max     int
+	// notify is closed and replaced whenever changes are added
+	notify chan struct{}
+}
+
+func newUserChanges(max int) *userChanges {
+	return &userChanges{
+		max:    max,
+		notify: make(chan struct{}),
+	}
+}
+
+// add keeps the changes, dropping the oldest ones beyond the maximum, and wakes up the watchers
+func (c *userChanges) add(changes ...entities.UserChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, change := range changes {
+		c.last++
+		change.ResumeToken = strconv.FormatInt(c.last, 10)
+		c.changes = append(c.changes, change)
+	}
+	if len(c.changes) > c.max {
+		c.changes = append([]entities.UserChange{}, c.changes[len(c.changes)-c.max:]...)
+	}
+
+	close(c.notify)
+	c.notify = make(chan struct{})
+}
+
+func (c *userChanges) Watch(ctx context.Context, resumeToken string, fn func(change entities.UserChange) error) error {
+	after, err := c.resumeAfter(resumeToken)
+	if err != nil {
+		return err
+	}
+
+	for {
+		c.mu.Lock()
+		first := c.last - int64(len(c.changes)) + 1
+		if after+1 < first {
+			c.mu.Unlock()
+			return wrappers.NewValidationErr(fmt.Errorf("resume token %d has expired", after))
+		}
+		pending := append([]entities.UserChange{}, c.changes[after+1-first:]...)
+		notify := c.notify
+		c.mu.Unlock()
+
+		for _, change := range pending {
+			if err := fn(change); err != nil {
+				return err
+			}
+			after++
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notify:
+		}
+	}
+}
+
+// Prune deletes no change, as the changes kept in memory are already bounded
+func (c *userChanges) Prune(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+// GetByUserID returns the changes of the user still kept
+func (c *userChanges) GetByUserID(ctx context.Context, userID string) ([]entities.UserChange, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var changes []entities.UserChange
+	for _, change := range c.changes {
+		if change.UserID == userID {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// EraseStates clears the states of the user kept along with its changes
+func (c *userChanges) EraseStates(ctx context.Context, userID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	states := make(map[string]*entities.User)
+	for i := range c.changes {
+		if c.changes[i].UserID == userID {
+			states[c.changes[i].ResumeToken] = c.changes[i].User
+			c.changes[i].User = nil
+		}
+	}
+
+	onRollback(ctx, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		for i := range c.changes {
+			if state, ok := states[c.changes[i].ResumeToken]; ok {
+				c.changes[i].User = state
+			}
+		}
+	})
+	return nil
+}
+
+// resumeAfter returns the sequence number of the change to resume after, which is the last one when the resume token is empty
+func (c *userChanges) resumeAfter(resumeToken string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if resumeToken == "" {
+		return c.last, nil
+	}
+
+	after, err := strconv.ParseInt(resumeToken, 10, 64)
+	if err != nil || after < c.last-int64(len(c.changes)) || after > c.last {
+		return 0, wrappers.NewValidationErr(fmt.Errorf("resume token %s is not valid or has expired", resumeToken))
+	}
+	return after, nil
+}
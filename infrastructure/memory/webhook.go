@@ -0,0 +1,236 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// webhookSubscriptionRepository adapter of a webhook subscription repository kept in memory
+type webhookSubscriptionRepository struct {
+	mu            sync.Mutex
+	subscriptions []entities.WebhookSubscription
+}
+
+// NewWebhookSubscriptionRepository creates an in-memory webhook subscription repository
+func NewWebhookSubscriptionRepository() ports.WebhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{}
+}
+
+func (r *webhookSubscriptionRepository) Create(_ context.Context, subscription entities.WebhookSubscription) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	subscription.ID = newID()
+	r.subscriptions = append(r.subscriptions, subscription)
+	return subscription.ID, nil
+}
+
+func (r *webhookSubscriptionRepository) GetAll(_ context.Context) ([]entities.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	subscriptions := append([]entities.WebhookSubscription{}, r.subscriptions...)
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+	})
+	return subscriptions, nil
+}
+
+func (r *webhookSubscriptionRepository) GetByID(_ context.Context, ID string) (entities.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(ID)
+	if i < 0 {
+		return entities.WebhookSubscription{}, wrappers.NewNonExistentErr(errNotFound)
+	}
+	return r.subscriptions[i], nil
+}
+
+func (r *webhookSubscriptionRepository) Update(_ context.Context, ID string, subscription entities.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(ID)
+	if i < 0 {
+		return wrappers.NewNonExistentErr(errNotFound)
+	}
+	subscription.ID = ID
+	r.subscriptions[i] = subscription
+	return nil
+}
+
+func (r *webhookSubscriptionRepository) Delete(_ context.Context, ID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(ID)
+	if i < 0 {
+		return wrappers.NewNonExistentErr(errNotFound)
+	}
+	r.subscriptions = append(r.subscriptions[:i], r.subscriptions[i+1:]...)
+	return nil
+}
+
+func (r *webhookSubscriptionRepository) index(ID string) int {
+	for i, subscription := range r.subscriptions {
+		if subscription.ID == ID {
+			return i
+		}
+	}
+	return -1
+}
+
+// webhookDeliveryRepository adapter of a webhook delivery repository kept in memory
+type webhookDeliveryRepository struct {
+	mu         sync.Mutex
+	deliveries []entities.WebhookDelivery
+}
+
+// NewWebhookDeliveryRepository creates an in-memory webhook delivery repository
+func NewWebhookDeliveryRepository() ports.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{}
+}
+
+func (r *webhookDeliveryRepository) CreateMany(_ context.Context, deliveries []entities.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range deliveries {
+		delivery.ID = newID()
+		r.deliveries = append(r.deliveries, delivery)
+	}
+	return nil
+}
+
+func (r *webhookDeliveryRepository) GetByID(_ context.Context, ID string) (entities.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range r.deliveries {
+		if delivery.ID == ID {
+			return delivery, nil
+		}
+	}
+	return entities.WebhookDelivery{}, wrappers.NewNonExistentErr(errNotFound)
+}
+
+// Claim finds and postpones the due deliveries under the same lock, so that they are claimed only once
+func (r *webhookDeliveryRepository) Claim(_ context.Context, now, leaseUntil time.Time, limit int) ([]entities.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []int
+	for i, delivery := range r.deliveries {
+		if delivery.Status == entities.WebhookDeliveryStatusPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, i)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return r.deliveries[due[i]].NextAttemptAt.Before(r.deliveries[due[j]].NextAttemptAt)
+	})
+
+	deliveries := []entities.WebhookDelivery{}
+	for _, i := range page(due, 0, limit) {
+		r.deliveries[i].NextAttemptAt = leaseUntil
+		deliveries = append(deliveries, r.deliveries[i])
+	}
+	return deliveries, nil
+}
+
+func (r *webhookDeliveryRepository) GetByStatus(_ context.Context, status entities.WebhookDeliveryStatus) ([]entities.WebhookDelivery, error) {
+	deliveries := r.find(func(delivery entities.WebhookDelivery) bool {
+		return delivery.Status == status
+	})
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+	})
+
+	return deliveries, nil
+}
+
+func (r *webhookDeliveryRepository) GetByAggregateID(_ context.Context, aggregateID string) ([]entities.WebhookDelivery, error) {
+	deliveries := r.find(func(delivery entities.WebhookDelivery) bool {
+		return delivery.AggregateID == aggregateID
+	})
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+	})
+
+	return deliveries, nil
+}
+
+// Update sets the state of the delivery after an attempt, the rest of its fields are never updated
+func (r *webhookDeliveryRepository) Update(_ context.Context, delivery entities.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.deliveries {
+		if r.deliveries[i].ID == delivery.ID {
+			r.deliveries[i].Status = delivery.Status
+			r.deliveries[i].Attempts = delivery.Attempts
+			r.deliveries[i].NextAttemptAt = delivery.NextAttemptAt
+			r.deliveries[i].LastError = delivery.LastError
+			r.deliveries[i].DeliveredAt = delivery.DeliveredAt
+			return nil
+		}
+	}
+	return wrappers.NewNonExistentErr(errNotFound)
+}
+
+func (r *webhookDeliveryRepository) UpdateBody(ctx context.Context, ID string, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.deliveries {
+		if r.deliveries[i].ID == ID {
+			previous := r.deliveries[i].Body
+			r.deliveries[i].Body = body
+
+			onRollback(ctx, func() {
+				r.mu.Lock()
+				defer r.mu.Unlock()
+				for i := range r.deliveries {
+					if r.deliveries[i].ID == ID {
+						r.deliveries[i].Body = previous
+					}
+				}
+			})
+			return nil
+		}
+	}
+	return wrappers.NewNonExistentErr(errNotFound)
+}
+
+func (r *webhookDeliveryRepository) DeleteBySubscription(_ context.Context, subscriptionID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deliveries := r.deliveries[:0]
+	for _, delivery := range r.deliveries {
+		if delivery.SubscriptionID != subscriptionID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	r.deliveries = deliveries
+	return nil
+}
+
+func (r *webhookDeliveryRepository) find(match func(delivery entities.WebhookDelivery) bool) []entities.WebhookDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deliveries := []entities.WebhookDelivery{}
+	for _, delivery := range r.deliveries {
+		if match(delivery) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries
+}
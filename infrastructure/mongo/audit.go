@@ -0,0 +1,84 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// auditRepository adapter of an audit repository for mongo
+type auditRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAuditRepository creates an audit repository for mongo
+func NewAuditRepository(db *mongo.Database) ports.AuditRepository {
+	return &auditRepository{
+		collection: db.Collection(entities.EntityNameAuditEvent),
+	}
+}
+
+func (r *auditRepository) Create(ctx context.Context, event entities.AuditEvent) (string, error) {
+	result, err := r.collection.InsertOne(ctx, event)
+	if err != nil {
+		return "", err
+	}
+
+	return result.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *auditRepository) List(ctx context.Context, filter models.AuditFilter, offset, limit int) ([]entities.AuditEvent, error) {
+	query := bson.M{}
+	if filter.ActorID != "" {
+		query["actor_id"] = filter.ActorID
+	}
+	if filter.Action != "" {
+		query["action"] = string(filter.Action)
+	}
+	if filter.TargetID != "" {
+		query["target_id"] = filter.TargetID
+	}
+	timestamp := bson.M{}
+	if !filter.From.IsZero() {
+		timestamp["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		timestamp["$lt"] = filter.To
+	}
+	if len(timestamp) > 0 {
+		query["timestamp"] = timestamp
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(offset))
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []entities.AuditEvent{}
+	if err = cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *auditRepository) RedactTarget(ctx context.Context, targetID string) error {
+	filter := bson.M{"target_id": targetID}
+	update := bson.M{"$set": bson.M{"before": nil, "after": nil}}
+	_, err := r.collection.UpdateMany(ctx, filter, update)
+	return err
+}
@@ -0,0 +1,141 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewAuditRepository_Ok checks that NewAuditRepository creates a new auditRepository struct
+func TestNewAuditRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Act
+		repo := NewAuditRepository(mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+	})
+}
+
+// TestAuditCreate_Ok checks that Create returns the generated ID when a valid event is received
+func TestAuditCreate_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := auditRepository{
+			collection: mt.DB.Collection(entities.EntityNameAuditEvent),
+		}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		id, err := repo.Create(context.Background(), entities.AuditEvent{
+			ActorID:   "test-actor",
+			Action:    "create",
+			TargetID:  "test-target",
+			Timestamp: time.Now(),
+		})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.NotEmpty(t, id)
+	})
+}
+
+// TestAuditCreate_InsertError checks that Create returns an error when the insert fails
+func TestAuditCreate_InsertError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := auditRepository{
+			collection: mt.DB.Collection(entities.EntityNameAuditEvent),
+		}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 1, Message: "insert error"}))
+
+		// Act
+		id, err := repo.Create(context.Background(), entities.AuditEvent{})
+
+		// Assert
+		assert.Empty(t, id)
+		assert.NotNil(t, err)
+	})
+}
+
+// TestAuditList_Ok checks that List returns the events found
+func TestAuditList_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := auditRepository{
+			collection: mt.DB.Collection(entities.EntityNameAuditEvent),
+		}
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.audit_events", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: id},
+			{Key: "actor_id", Value: "test-actor"},
+			{Key: "action", Value: "update"},
+			{Key: "before", Value: bson.D{{Key: "name", Value: "old"}}},
+		}))
+
+		// Act
+		events, err := repo.List(context.Background(), models.AuditFilter{ActorID: "test-actor", From: time.Now().Add(-time.Hour)}, 0, 10)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, events, 1)
+		assert.Equal(t, id.Hex(), events[0].ID)
+		assert.Equal(t, "old", events[0].Before["name"])
+		assert.Nil(t, events[0].After)
+	})
+}
+
+// TestAuditList_FindError checks that List returns an error when the find fails
+func TestAuditList_FindError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := auditRepository{
+			collection: mt.DB.Collection(entities.EntityNameAuditEvent),
+		}
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "find error"}))
+
+		// Act
+		events, err := repo.List(context.Background(), models.AuditFilter{}, 0, 0)
+
+		// Assert
+		assert.Nil(t, events)
+		assert.NotNil(t, err)
+	})
+}
+
+// TestAuditRedactTarget_Ok checks that RedactTarget clears the values of the events targeting the given ID
+func TestAuditRedactTarget_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := auditRepository{
+			collection: mt.DB.Collection(entities.EntityNameAuditEvent),
+		}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}))
+
+		// Act
+		err := repo.RedactTarget(context.Background(), "test-target")
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
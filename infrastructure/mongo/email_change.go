@@ -0,0 +1,89 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// emailChangeRepository adapter of an email change repository for mongo
+type emailChangeRepository struct {
+	collection *mongo.Collection
+}
+
+// NewEmailChangeRepository creates an email change repository for mongo
+func NewEmailChangeRepository(db *mongo.Database) ports.EmailChangeRepository {
+	return &emailChangeRepository{
+		collection: db.Collection(entities.EntityNameEmailChange),
+	}
+}
+
+func (r *emailChangeRepository) Create(ctx context.Context, change entities.EmailChange) (string, error) {
+	result, err := r.collection.InsertOne(ctx, change)
+	if err != nil {
+		return "", err
+	}
+
+	return result.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *emailChangeRepository) GetByID(ctx context.Context, ID string) (entities.EmailChange, error) {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return entities.EmailChange{}, err
+	}
+
+	var change entities.EmailChange
+	err = r.collection.FindOne(ctx, bson.M{"_id": _id}).Decode(&change)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return entities.EmailChange{}, err
+	}
+
+	return change, nil
+}
+
+func (r *emailChangeRepository) GetByUserID(ctx context.Context, userID string) ([]entities.EmailChange, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	changes := []entities.EmailChange{}
+	if err = cursor.All(ctx, &changes); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func (r *emailChangeRepository) Delete(ctx context.Context, ID string) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": _id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount < 1 {
+		return wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+func (r *emailChangeRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
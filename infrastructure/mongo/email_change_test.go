@@ -0,0 +1,132 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewEmailChangeRepository_Ok checks that NewEmailChangeRepository creates a new emailChangeRepository struct
+func TestNewEmailChangeRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Act
+		repo := NewEmailChangeRepository(mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+	})
+}
+
+// TestEmailChangeCreate_Ok checks that Create returns the ID of the inserted email change
+func TestEmailChangeCreate_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := emailChangeRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		ID, err := repo.Create(context.Background(), entities.EmailChange{Email: "new@test.com"})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.NotEmpty(t, ID)
+	})
+}
+
+// TestEmailChangeGetByID_Ok checks that GetByID returns the email change found
+func TestEmailChangeGetByID_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := emailChangeRepository{
+			collection: mt.Coll,
+		}
+
+		ID := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.email_changes", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: ID},
+			{Key: "user_id", Value: "test-user-id"},
+			{Key: "email", Value: "new@test.com"},
+		}))
+
+		// Act
+		change, err := repo.GetByID(context.Background(), ID.Hex())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, ID.Hex(), change.ID)
+		assert.Equal(t, "new@test.com", change.Email)
+	})
+}
+
+// TestEmailChangeGetByID_NotFound checks that GetByID returns a non existent error when no email change is found
+func TestEmailChangeGetByID_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := emailChangeRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.email_changes", mtest.FirstBatch))
+
+		// Act
+		_, err := repo.GetByID(context.Background(), primitive.NewObjectID().Hex())
+
+		// Assert
+		assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	})
+}
+
+// TestEmailChangeDelete_NotFound checks that Delete returns a non existent error when no email change is deleted
+func TestEmailChangeDelete_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := emailChangeRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}})
+
+		// Act
+		err := repo.Delete(context.Background(), primitive.NewObjectID().Hex())
+
+		// Assert
+		assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	})
+}
+
+// TestEmailChangeDeleteByUserID_Ok checks that DeleteByUserID does not return an error when the user has no pending changes
+func TestEmailChangeDeleteByUserID_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := emailChangeRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}})
+
+		// Act
+		err := repo.DeleteByUserID(context.Background(), "test-user-id")
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,85 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// idempotencyRepository adapter of an idempotency repository for mongo
+type idempotencyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewIdempotencyRepository creates an idempotency repository for mongo.
+// Expired records are removed by the TTL index on the expiration date created by the migrations.
+func NewIdempotencyRepository(db *mongo.Database) ports.IdempotencyRepository {
+	return &idempotencyRepository{
+		collection: db.Collection(entities.EntityNameIdempotencyRecord),
+	}
+}
+
+func (r *idempotencyRepository) Get(ctx context.Context, key string) (entities.IdempotencyRecord, error) {
+	// the TTL monitor runs periodically, so expired records still need to be filtered out
+	filter := bson.M{"_id": key, "expires_at": bson.M{"$gt": time.Now().UTC()}}
+
+	var record entities.IdempotencyRecord
+	err := r.collection.FindOne(ctx, filter).Decode(&record)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return entities.IdempotencyRecord{}, err
+	}
+
+	return record, nil
+}
+
+// Reserve is an upsert only matching an expired record, so that it fails with a duplicate key while the key is reserved
+func (r *idempotencyRepository) Reserve(ctx context.Context, record entities.IdempotencyRecord) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": record.Key, "expires_at": bson.M{"$lte": record.CreatedAt}},
+		bson.M{"$set": bson.M{
+			"token":        record.Token,
+			"request_hash": record.RequestHash,
+			"response":     nil,
+			"created_at":   record.CreatedAt,
+			"expires_at":   record.ExpiresAt,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		err = entities.NewAlreadyExistsErr(fmt.Errorf("idempotency key %s is reserved", record.Key))
+	}
+	return err
+}
+
+// Complete only updates the record while it is reserved with the token, so that a request whose reservation expired does not overwrite the one of a retry
+func (r *idempotencyRepository) Complete(ctx context.Context, key, token string, response []byte, expiresAt time.Time) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": key, "token": token, "response": nil},
+		bson.M{"$set": bson.M{"response": response, "expires_at": expiresAt}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount < 1 {
+		return entities.NewConflictErr(fmt.Errorf("idempotency key %s is no longer reserved by the request", key))
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, key, token string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": key, "token": token, "response": nil})
+	return err
+}
@@ -0,0 +1,172 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewIdempotencyRepository_Ok checks that NewIdempotencyRepository creates a new idempotencyRepository struct
+func TestNewIdempotencyRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Act
+		repo := NewIdempotencyRepository(mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+	})
+}
+
+// TestIdempotencyGet_Ok checks that Get returns the expected record when the key exists
+func TestIdempotencyGet_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := idempotencyRepository{
+			collection: mt.DB.Collection(entities.EntityNameIdempotencyRecord),
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "test.idempotency_keys", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: "test-key"},
+			{Key: "request_hash", Value: "test-hash"},
+		}))
+
+		// Act
+		record, err := repo.Get(context.Background(), "test-key")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, "test-key", record.Key)
+		assert.Equal(t, "test-hash", record.RequestHash)
+	})
+}
+
+// TestIdempotencyGet_ResourceNotFound checks that Get returns an error when the key does not exist or has expired
+func TestIdempotencyGet_ResourceNotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := idempotencyRepository{
+			collection: mt.DB.Collection(entities.EntityNameIdempotencyRecord),
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.idempotency_keys", mtest.FirstBatch))
+
+		// Act
+		_, err := repo.Get(context.Background(), "test-key")
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	})
+}
+
+// TestIdempotencyReserve_Ok checks that Reserve does not return an error when the upsert succeeds
+func TestIdempotencyReserve_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := idempotencyRepository{
+			collection: mt.DB.Collection(entities.EntityNameIdempotencyRecord),
+		}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.Reserve(context.Background(), entities.IdempotencyRecord{Key: "test-key"})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestIdempotencyReserve_Reserved checks that Reserve returns an AlreadyExistsErr when the upsert fails with a duplicate key
+func TestIdempotencyReserve_Reserved(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := idempotencyRepository{
+			collection: mt.DB.Collection(entities.EntityNameIdempotencyRecord),
+		}
+
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Code: 11000, Message: "duplicate key error"}))
+
+		// Act
+		err := repo.Reserve(context.Background(), entities.IdempotencyRecord{Key: "test-key"})
+
+		// Assert
+		assert.ErrorIs(t, err, entities.AlreadyExistsErr)
+	})
+}
+
+// TestIdempotencyComplete_Ok checks that Complete does not return an error when the update succeeds
+func TestIdempotencyComplete_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := idempotencyRepository{
+			collection: mt.DB.Collection(entities.EntityNameIdempotencyRecord),
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.Complete(context.Background(), "test-key", "test-token", []byte("test-response"), time.Now())
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestIdempotencyComplete_NotReserved checks that Complete returns a ConflictErr when the key is no longer reserved with the token
+func TestIdempotencyComplete_NotReserved(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := idempotencyRepository{
+			collection: mt.DB.Collection(entities.EntityNameIdempotencyRecord),
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		// Act
+		err := repo.Complete(context.Background(), "test-key", "test-token", []byte("test-response"), time.Now())
+
+		// Assert
+		assert.ErrorIs(t, err, entities.ConflictErr)
+	})
+}
+
+// TestIdempotencyRelease_Ok checks that Release does not return an error when the delete succeeds
+func TestIdempotencyRelease_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := idempotencyRepository{
+			collection: mt.DB.Collection(entities.EntityNameIdempotencyRecord),
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}})
+
+		// Act
+		err := repo.Release(context.Background(), "test-key", "test-token")
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,114 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// invitationRepository adapter of an invitation repository for mongo
+type invitationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewInvitationRepository creates an invitation repository for mongo
+func NewInvitationRepository(db *mongo.Database) ports.InvitationRepository {
+	return &invitationRepository{
+		collection: db.Collection(entities.EntityNameInvitation),
+	}
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation entities.Invitation) (string, error) {
+	result, err := r.collection.InsertOne(ctx, invitation)
+	if err != nil {
+		return "", err
+	}
+
+	return result.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *invitationRepository) GetAll(ctx context.Context) ([]entities.Invitation, error) {
+	return r.find(ctx, bson.M{})
+}
+
+func (r *invitationRepository) GetByUserID(ctx context.Context, userID string) ([]entities.Invitation, error) {
+	return r.find(ctx, bson.M{"user_id": userID})
+}
+
+func (r *invitationRepository) GetByID(ctx context.Context, ID string) (entities.Invitation, error) {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return entities.Invitation{}, err
+	}
+
+	var invitation entities.Invitation
+	err = r.collection.FindOne(ctx, bson.M{"_id": _id}).Decode(&invitation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return entities.Invitation{}, err
+	}
+
+	return invitation, nil
+}
+
+func (r *invitationRepository) Update(ctx context.Context, ID string, invitation entities.Invitation) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	invitation.ID = ""
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": _id}, invitation)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount < 1 {
+		return wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+func (r *invitationRepository) Delete(ctx context.Context, ID string) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": _id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount < 1 {
+		return wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+func (r *invitationRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
+
+func (r *invitationRepository) find(ctx context.Context, filter bson.M) ([]entities.Invitation, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	invitations := []entities.Invitation{}
+	if err = cursor.All(ctx, &invitations); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
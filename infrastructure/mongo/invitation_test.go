@@ -0,0 +1,135 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewInvitationRepository_Ok checks that NewInvitationRepository creates a new invitationRepository struct
+func TestNewInvitationRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Act
+		repo := NewInvitationRepository(mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+	})
+}
+
+// TestInvitationCreate_Ok checks that Create returns the ID of the inserted invitation
+func TestInvitationCreate_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := invitationRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		ID, err := repo.Create(context.Background(), entities.Invitation{Email: "test@test.com"})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.NotEmpty(t, ID)
+	})
+}
+
+// TestInvitationGetAll_Ok checks that GetAll returns the invitations found
+func TestInvitationGetAll_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := invitationRepository{
+			collection: mt.Coll,
+		}
+
+		ID := primitive.NewObjectID()
+		first := mtest.CreateCursorResponse(1, "test.invitations", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: ID},
+			{Key: "user_id", Value: "test-user-id"},
+			{Key: "email", Value: "test@test.com"},
+		})
+		killCursors := mtest.CreateCursorResponse(0, "test.invitations", mtest.NextBatch)
+		mt.AddMockResponses(first, killCursors)
+
+		// Act
+		invitations, err := repo.GetAll(context.Background())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, invitations, 1)
+		assert.Equal(t, ID.Hex(), invitations[0].ID)
+		assert.Equal(t, "test-user-id", invitations[0].UserID)
+	})
+}
+
+// TestInvitationGetByID_NotFound checks that GetByID returns a non existent error when no invitation is found
+func TestInvitationGetByID_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := invitationRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.invitations", mtest.FirstBatch))
+
+		// Act
+		_, err := repo.GetByID(context.Background(), primitive.NewObjectID().Hex())
+
+		// Assert
+		assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	})
+}
+
+// TestInvitationUpdate_NotFound checks that Update returns a non existent error when no invitation is matched
+func TestInvitationUpdate_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := invitationRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}})
+
+		// Act
+		err := repo.Update(context.Background(), primitive.NewObjectID().Hex(), entities.Invitation{})
+
+		// Assert
+		assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	})
+}
+
+// TestInvitationDelete_NotFound checks that Delete returns a non existent error when no invitation is deleted
+func TestInvitationDelete_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := invitationRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}})
+
+		// Act
+		err := repo.Delete(context.Background(), primitive.NewObjectID().Hex())
+
+		// Assert
+		assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	})
+}
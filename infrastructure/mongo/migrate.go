@@ -0,0 +1,167 @@
+package mongo
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// migrationsCollection collection tracking the applied migrations
+	migrationsCollection = "schema_migrations"
+	// migrationsLockCollection collection holding the lock of the migrations while they run
+	migrationsLockCollection = "schema_migrations_lock"
+	// migrationsLockID ID of the lock document
+	migrationsLockID = "migrations"
+)
+
+// migration versioned change of the indexes, the validators or the data of the database, applied once and in the order of the versions.
+// Migrations are not transactional, so up must be safe to run again after failing halfway.
+type migration struct {
+	version     int64
+	description string
+	up          func(ctx context.Context, db *mongo.Database) error
+}
+
+// appliedMigration document of an applied migration in the migrations collection
+type appliedMigration struct {
+	Version     int64     `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+// lockOptions options of the lock of the migrations
+type lockOptions struct {
+	// TTL after which a lock that was not released, for instance because its instance crashed, can be taken over.
+	// It is renewed after every migration, so it must exceed the duration of the slowest one.
+	TTL time.Duration
+	// RetryInterval between the attempts to acquire a lock held by another instance
+	RetryInterval time.Duration
+}
+
+// Migrate applies the pending migrations to the database in the order of their versions.
+// A lock is held while they run, so that the instances starting at the same time wait for the first one instead of applying them twice.
+func Migrate(ctx context.Context, db *mongo.Database) error {
+	return migrate(ctx, db, migrations, lockOptions{TTL: 10 * time.Minute, RetryInterval: time.Second})
+}
+
+func migrate(ctx context.Context, db *mongo.Database, migrations []migration, opts lockOptions) (err error) {
+	migrations = slices.SortedFunc(slices.Values(migrations), func(a, b migration) int {
+		return cmp.Compare(a.version, b.version)
+	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].version == migrations[i-1].version {
+			return fmt.Errorf("mongo migration version %d is duplicated", migrations[i].version)
+		}
+	}
+
+	owner := uuid.NewString()
+	lock := db.Collection(migrationsLockCollection)
+	if err = acquireLock(ctx, lock, owner, opts); err != nil {
+		return err
+	}
+	defer func() {
+		if _, releaseErr := lock.DeleteOne(context.WithoutCancel(ctx), bson.M{"_id": migrationsLockID, "owner": owner}); releaseErr != nil && err == nil {
+			err = fmt.Errorf("failed to release the mongo migrations lock: %w", releaseErr)
+		}
+	}()
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+
+		if err = m.up(ctx, db); err != nil {
+			return fmt.Errorf("mongo migration %d (%s) failed: %w", m.version, m.description, err)
+		}
+		_, err = db.Collection(migrationsCollection).InsertOne(ctx, appliedMigration{
+			Version:     m.version,
+			Description: m.description,
+			AppliedAt:   time.Now().UTC(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record mongo migration %d: %w", m.version, err)
+		}
+		observability.Logger().Printf("applied mongo migration %d (%s)", m.version, m.description)
+
+		if err = renewLock(ctx, lock, owner, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appliedVersions(ctx context.Context, db *mongo.Database) (map[int64]bool, error) {
+	cursor, err := db.Collection(migrationsCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []appliedMigration
+	if err = cursor.All(ctx, &migrations); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]bool, len(migrations))
+	for _, m := range migrations {
+		applied[m.Version] = true
+	}
+	return applied, nil
+}
+
+// acquireLock waits until the lock is free or expired to take it.
+// Taking it is an upsert only matching an expired lock, so that it fails with a duplicate key while another instance holds it.
+func acquireLock(ctx context.Context, lock *mongo.Collection, owner string, opts lockOptions) error {
+	for {
+		now := time.Now().UTC()
+		_, err := lock.UpdateOne(
+			ctx,
+			bson.M{"_id": migrationsLockID, "expires_at": bson.M{"$lt": now}},
+			bson.M{"$set": bson.M{"owner": owner, "expires_at": now.Add(opts.TTL)}},
+			options.Update().SetUpsert(true),
+		)
+		if err == nil {
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("failed to acquire the mongo migrations lock: %w", err)
+		}
+
+		observability.Logger().Printf("mongo migrations are locked by another instance, retrying in %s", opts.RetryInterval)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to acquire the mongo migrations lock: %w", ctx.Err())
+		case <-time.After(opts.RetryInterval):
+		}
+	}
+}
+
+// renewLock extends the lock, failing when it expired and was taken over by another instance
+func renewLock(ctx context.Context, lock *mongo.Collection, owner string, opts lockOptions) error {
+	result, err := lock.UpdateOne(
+		ctx,
+		bson.M{"_id": migrationsLockID, "owner": owner},
+		bson.M{"$set": bson.M{"expires_at": time.Now().UTC().Add(opts.TTL)}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to renew the mongo migrations lock: %w", err)
+	}
+	if result.MatchedCount < 1 {
+		return errors.New("the mongo migrations lock expired and was taken over by another instance")
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+var testLockOptions = lockOptions{TTL: time.Minute, RetryInterval: time.Millisecond}
+
+// TestMigrate_Ok checks that migrate applies the pending migrations in the order of their versions, skipping the applied ones
+func TestMigrate_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		var applied []int64
+		up := func(version int64) func(ctx context.Context, db *mongo.Database) error {
+			return func(ctx context.Context, db *mongo.Database) error {
+				applied = append(applied, version)
+				return nil
+			}
+		}
+		migrations := []migration{
+			{version: 3, description: "third", up: up(3)},
+			{version: 1, description: "first", up: up(1)},
+			{version: 2, description: "second", up: up(2)},
+		}
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCursorResponse(0, "test.schema_migrations", mtest.FirstBatch, bson.D{{Key: "_id", Value: int64(1)}}),
+			mtest.CreateSuccessResponse(),
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}},
+			mtest.CreateSuccessResponse(),
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}},
+			mtest.CreateSuccessResponse(),
+		)
+
+		// Act
+		err := migrate(context.Background(), mt.DB, migrations, testLockOptions)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, []int64{2, 3}, applied)
+	})
+}
+
+// TestMigrate_DuplicatedVersion checks that migrate returns an error without applying any migration when two of them share a version
+func TestMigrate_DuplicatedVersion(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		up := func(ctx context.Context, db *mongo.Database) error {
+			t.Fatal("no migration should be applied")
+			return nil
+		}
+		migrations := []migration{
+			{version: 1, description: "first", up: up},
+			{version: 1, description: "other first", up: up},
+		}
+		expectedError := "mongo migration version 1 is duplicated"
+
+		// Act
+		err := migrate(context.Background(), mt.DB, migrations, testLockOptions)
+
+		// Assert
+		assert.NotNil(t, err)
+		assert.Equal(t, expectedError, err.Error())
+	})
+}
+
+// TestMigrate_LockHeld checks that migrate waits for the lock held by another instance and then applies the pending migrations
+func TestMigrate_LockHeld(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{Code: 11000, Message: "duplicate key error"}),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCursorResponse(0, "test.schema_migrations", mtest.FirstBatch, bson.D{{Key: "_id", Value: int64(1)}}),
+			mtest.CreateSuccessResponse(),
+		)
+		migrations := []migration{
+			{version: 1, description: "first", up: func(ctx context.Context, db *mongo.Database) error { return nil }},
+		}
+
+		// Act
+		err := migrate(context.Background(), mt.DB, migrations, testLockOptions)
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestMigrate_LockContextCanceled checks that migrate returns an error when the context is done while the lock is held by another instance
+func TestMigrate_LockContextCanceled(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{Code: 11000, Message: "duplicate key error"}),
+		)
+		ctx, cancel := context.WithCancel(context.Background())
+		opts := lockOptions{TTL: time.Minute, RetryInterval: time.Minute}
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		// Act
+		err := migrate(ctx, mt.DB, nil, opts)
+
+		// Assert
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+// TestMigrate_UpError checks that migrate returns the error of a failed migration without recording it
+func TestMigrate_UpError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCursorResponse(0, "test.schema_migrations", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(),
+		)
+		migrations := []migration{
+			{version: 1, description: "first", up: func(ctx context.Context, db *mongo.Database) error { return errors.New("up error") }},
+		}
+		expectedError := "mongo migration 1 (first) failed: up error"
+
+		// Act
+		err := migrate(context.Background(), mt.DB, migrations, testLockOptions)
+
+		// Assert
+		assert.NotNil(t, err)
+		assert.Equal(t, expectedError, err.Error())
+	})
+}
+
+// TestMigrate_LockTakenOver checks that migrate stops when its lock expired and was taken over by another instance
+func TestMigrate_LockTakenOver(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCursorResponse(0, "test.schema_migrations", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(),
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}},
+			mtest.CreateSuccessResponse(),
+		)
+		var applied int
+		up := func(ctx context.Context, db *mongo.Database) error {
+			applied++
+			return nil
+		}
+		migrations := []migration{
+			{version: 1, description: "first", up: up},
+			{version: 2, description: "second", up: up},
+		}
+		expectedError := "the mongo migrations lock expired and was taken over by another instance"
+
+		// Act
+		err := migrate(context.Background(), mt.DB, migrations, testLockOptions)
+
+		// Assert
+		assert.NotNil(t, err)
+		assert.Equal(t, expectedError, err.Error())
+		assert.Equal(t, 1, applied)
+	})
+}
@@ -0,0 +1,258 @@
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// migrations of the mongo database. Their versions follow the ones of the postgres migrations making the same change.
+// New migrations are appended with a greater version, and applied migrations must never be modified.
+var migrations = []migration{
+	{
+		version:     20261019090000,
+		description: "create idempotency keys TTL index",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			// expired records are removed by a TTL index on the expiration date
+			_, err := db.Collection(entities.EntityNameIdempotencyRecord).Indexes().CreateOne(
+				ctx,
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "expires_at", Value: 1}},
+					Options: options.Index().SetExpireAfterSeconds(0),
+				},
+			)
+			return err
+		},
+	},
+	{
+		version:     20261019110000,
+		description: "create audit events indexes",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection(entities.EntityNameAuditEvent).Indexes().CreateMany(
+				ctx,
+				[]mongo.IndexModel{
+					{Keys: bson.D{{Key: "timestamp", Value: -1}}},
+					{Keys: bson.D{{Key: "actor_id", Value: 1}}},
+					{Keys: bson.D{{Key: "target_id", Value: 1}}},
+				},
+			)
+			return err
+		},
+	},
+	{
+		version:     20261019120000,
+		description: "create outbox events index",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection(entities.EntityNameEvent).Indexes().CreateOne(
+				ctx,
+				mongo.IndexModel{
+					Keys: bson.D{{Key: "published_at", Value: 1}, {Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}},
+				},
+			)
+			return err
+		},
+	},
+	{
+		version:     20261019130000,
+		description: "create webhook deliveries indexes",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection(entities.EntityNameWebhookDelivery).Indexes().CreateMany(
+				ctx,
+				[]mongo.IndexModel{
+					{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+					{Keys: bson.D{{Key: "subscription_id", Value: 1}}},
+				},
+			)
+			return err
+		},
+	},
+	{
+		version:     20261019160000,
+		description: "create users search index",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection(entities.EntityNameUser).Indexes().CreateOne(
+				ctx,
+				mongo.IndexModel{
+					// no language is set so that names are not stemmed, text indexes are always case and diacritic insensitive
+					Keys: bson.D{{Key: "name", Value: "text"}, {Key: "surnames", Value: "text"}, {Key: "email", Value: "text"}},
+					Options: options.Index().
+						SetName("users_search").
+						SetDefaultLanguage("none").
+						SetWeights(bson.D{{Key: "name", Value: 3}, {Key: "surnames", Value: 3}, {Key: "email", Value: 1}}),
+				},
+			)
+			return err
+		},
+	},
+	{
+		version:     20261019170000,
+		description: "normalize user emails",
+		up:          normalizeUserEmails,
+	},
+	{
+		version:     20261019210000,
+		description: "validate user emails",
+		up:          validateUserEmails,
+	},
+	{
+		version:     20261019220000,
+		description: "index personal data",
+		up:          indexPersonalData,
+	},
+	{
+		version:     20261019230000,
+		description: "index pending outbox events out of the dead letters",
+		up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection(entities.EntityNameEvent).Indexes().CreateMany(
+				ctx,
+				[]mongo.IndexModel{
+					{Keys: bson.D{{Key: "published_at", Value: 1}, {Key: "dead_at", Value: 1}, {Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}}},
+				},
+			)
+			return err
+		},
+	},
+}
+
+// normalizeUserEmails sets the normalized email of the users created before it existed and enforces its uniqueness,
+// which used to be enforced on the display email. Users sharing a normalized email are reported, as they must be fixed first.
+func normalizeUserEmails(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection(entities.EntityNameUser)
+	_, err := collection.UpdateMany(
+		ctx,
+		bson.M{"email_normalized": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"email_normalized": bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$email"}}}}}}},
+	)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$email_normalized", "ids": bson.M{"$push": "$_id"}, "count": bson.M{"$sum": 1}}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	var collisions []struct {
+		Email string               `bson:"_id"`
+		IDs   []primitive.ObjectID `bson:"ids"`
+	}
+	if err = cursor.All(ctx, &collisions); err != nil {
+		return err
+	}
+	if len(collisions) > 0 {
+		var msgs []string
+		for _, collision := range collisions {
+			var IDs []string
+			for _, ID := range collision.IDs {
+				IDs = append(IDs, ID.Hex())
+			}
+			msgs = append(msgs, fmt.Sprintf("email %s is shared by users %s", collision.Email, strings.Join(IDs, ", ")))
+		}
+		return fmt.Errorf("users must have unique emails regardless of case: %s", strings.Join(msgs, " | "))
+	}
+
+	_, err = collection.Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "email_normalized", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.Indexes().DropOne(ctx, "email_1")
+	if isNotFound(err) {
+		err = nil
+	}
+	return err
+}
+
+// validateUserEmails rejects the users without an email or a normalized email. The validation level is moderate,
+// so that updates of existing documents that are already not valid are not rejected.
+func validateUserEmails(ctx context.Context, db *mongo.Database) error {
+	validator := bson.M{"$jsonSchema": bson.M{
+		"bsonType": "object",
+		"required": bson.A{"email", "email_normalized"},
+		"properties": bson.M{
+			"email":            bson.M{"bsonType": "string"},
+			"email_normalized": bson.M{"bsonType": "string"},
+		},
+	}}
+
+	err := db.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: entities.EntityNameUser},
+		{Key: "validator", Value: validator},
+		{Key: "validationLevel", Value: "moderate"},
+	}).Err()
+	if !isNotFound(err) {
+		return err
+	}
+
+	return db.CreateCollection(ctx, entities.EntityNameUser, options.CreateCollection().
+		SetValidator(validator).
+		SetValidationLevel("moderate"))
+}
+
+// indexPersonalData sets the aggregate ID of the webhook deliveries created before it existed, reading it from their bodies,
+// and indexes the stores holding personal data by the user they belong to, so that it can be exported and erased
+func indexPersonalData(ctx context.Context, db *mongo.Database) error {
+	deliveries := db.Collection(entities.EntityNameWebhookDelivery)
+	cursor, err := deliveries.Find(ctx, bson.M{"aggregate_id": bson.M{"$exists": false}}, options.Find().SetProjection(bson.M{"body": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var delivery struct {
+			ID   primitive.ObjectID `bson:"_id"`
+			Body []byte             `bson:"body"`
+		}
+		if err = cursor.Decode(&delivery); err != nil {
+			return err
+		}
+		var message struct {
+			AggregateID string `json:"aggregate_id"`
+		}
+		if err = json.Unmarshal(delivery.Body, &message); err != nil {
+			return fmt.Errorf("body of webhook delivery %s is not valid: %w", delivery.ID.Hex(), err)
+		}
+		if _, err = deliveries.UpdateByID(ctx, delivery.ID, bson.M{"$set": bson.M{"aggregate_id": message.AggregateID}}); err != nil {
+			return err
+		}
+	}
+	if err = cursor.Err(); err != nil {
+		return err
+	}
+
+	if _, err = deliveries.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "aggregate_id", Value: 1}}}); err != nil {
+		return err
+	}
+	if _, err = db.Collection(entities.EntityNameEvent).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "aggregate_id", Value: 1}}}); err != nil {
+		return err
+	}
+	if _, err = db.Collection(entities.EntityNameInvitation).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}}}); err != nil {
+		return err
+	}
+	_, err = db.Collection(entities.EntityNameEmailChange).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}}})
+	return err
+}
+
+// isNotFound tells whether a command failed because its collection or index does not exist
+func isNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && (cmdErr.Name == "IndexNotFound" || cmdErr.Name == "NamespaceNotFound")
+}
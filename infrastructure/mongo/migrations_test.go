@@ -0,0 +1,83 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestMigrations_VersionsAreAscending checks that the migrations are listed in the strictly ascending order of their versions
+func TestMigrations_VersionsAreAscending(t *testing.T) {
+	for i := 1; i < len(migrations); i++ {
+		assert.Greater(t, migrations[i].version, migrations[i-1].version)
+	}
+}
+
+// TestNormalizeUserEmails_Ok checks that normalizeUserEmails succeeds when the previous unique index of the display email does not exist
+func TestNormalizeUserEmails_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}},
+			mtest.CreateCursorResponse(0, "test.users", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 27, Name: "IndexNotFound", Message: "index not found with name [email_1]"}),
+		)
+
+		// Act
+		err := normalizeUserEmails(context.Background(), mt.DB)
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestNormalizeUserEmails_EmailCollision checks that normalizeUserEmails returns an error reporting the users sharing a normalized email
+func TestNormalizeUserEmails_EmailCollision(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		ID1 := primitive.NewObjectID()
+		ID2 := primitive.NewObjectID()
+		mt.AddMockResponses(
+			bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}, {Key: "nModified", Value: 2}},
+			mtest.CreateCursorResponse(0, "test.users", mtest.FirstBatch, bson.D{{Key: "_id", Value: "bob@x.com"}, {Key: "ids", Value: bson.A{ID1, ID2}}, {Key: "count", Value: 2}}),
+		)
+		expectedError := fmt.Sprintf("users must have unique emails regardless of case: email bob@x.com is shared by users %s, %s", ID1.Hex(), ID2.Hex())
+
+		// Act
+		err := normalizeUserEmails(context.Background(), mt.DB)
+
+		// Assert
+		assert.NotNil(t, err)
+		assert.Equal(t, expectedError, err.Error())
+	})
+}
+
+// TestValidateUserEmails_CollectionNotFound checks that validateUserEmails creates the collection with the validator when it does not exist
+func TestValidateUserEmails_CollectionNotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		mt.AddMockResponses(
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 26, Name: "NamespaceNotFound", Message: "ns does not exist"}),
+			mtest.CreateSuccessResponse(),
+		)
+
+		// Act
+		err := validateUserEmails(context.Background(), mt.DB)
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,95 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+)
+
+// ClientOptions options of the client of a mongo database, where zero values keep the ones of the DSN or the driver defaults
+type ClientOptions struct {
+	MaxPoolSize     uint64
+	MinPoolSize     uint64
+	MaxConnIdleTime time.Duration
+	// OperationTimeout after which an operation without a deadline of its own fails
+	OperationTimeout time.Duration
+	// ReadPreference mode: primary, primaryPreferred, secondary, secondaryPreferred or nearest
+	ReadPreference string
+	// WriteConcern acknowledgment: majority or the number of members
+	WriteConcern string
+}
+
+// Connect creates a client of the mongo database of the DSN with the given options and returns the database.
+// The client connects in the background, so Ping needs to be used to ensure that the database is reachable.
+func Connect(ctx context.Context, dsn string, opts ClientOptions) (*mongo.Database, error) {
+	clientOptions, database, err := clientOptions(dsn, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("an unexpected error happened while opening the connection: %w", err)
+	}
+	return client.Database(database), nil
+}
+
+// Ping ensures that the primary of the database is reachable, regardless of the read preference of the client
+func Ping(ctx context.Context, db *mongo.Database) error {
+	return db.Client().Ping(ctx, readpref.Primary())
+}
+
+// clientOptions returns the options of a client of the DSN along with the name of its database
+func clientOptions(dsn string, opts ClientOptions) (*options.ClientOptions, string, error) {
+	cs, err := connstring.ParseAndValidate(dsn)
+	if err != nil {
+		return nil, "", fmt.Errorf("DSN not valid: %w", err)
+	}
+
+	clientOptions := options.Client().ApplyURI(dsn)
+	if opts.MaxPoolSize != 0 {
+		clientOptions.SetMaxPoolSize(opts.MaxPoolSize)
+	}
+	if opts.MinPoolSize != 0 {
+		clientOptions.SetMinPoolSize(opts.MinPoolSize)
+	}
+	if opts.MaxConnIdleTime != 0 {
+		clientOptions.SetMaxConnIdleTime(opts.MaxConnIdleTime)
+	}
+	if opts.OperationTimeout != 0 {
+		clientOptions.SetTimeout(opts.OperationTimeout)
+	}
+
+	if opts.ReadPreference != "" {
+		mode, err := readpref.ModeFromString(opts.ReadPreference)
+		if err != nil {
+			return nil, "", fmt.Errorf("read preference %s not valid", opts.ReadPreference)
+		}
+		rp, err := readpref.New(mode)
+		if err != nil {
+			return nil, "", err
+		}
+		clientOptions.SetReadPreference(rp)
+	}
+
+	switch opts.WriteConcern {
+	case "":
+	case "majority":
+		clientOptions.SetWriteConcern(writeconcern.Majority())
+	default:
+		w, err := strconv.Atoi(opts.WriteConcern)
+		if err != nil || w < 0 {
+			return nil, "", fmt.Errorf("write concern %s not valid", opts.WriteConcern)
+		}
+		clientOptions.SetWriteConcern(&writeconcern.WriteConcern{W: w})
+	}
+
+	return clientOptions, cs.Database, nil
+}
@@ -0,0 +1,74 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// TestClientOptions_Ok checks that clientOptions applies the given options over the ones of the DSN and returns its database
+func TestClientOptions_Ok(t *testing.T) {
+	// Arrange
+	opts := ClientOptions{
+		MaxPoolSize:      10,
+		MinPoolSize:      2,
+		MaxConnIdleTime:  time.Minute,
+		OperationTimeout: time.Second,
+		ReadPreference:   "secondaryPreferred",
+		WriteConcern:     "2",
+	}
+
+	// Act
+	clientOptions, database, err := clientOptions("mongodb://localhost:27017/test-db?maxPoolSize=50", opts)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-db", database)
+	assert.Equal(t, uint64(10), *clientOptions.MaxPoolSize)
+	assert.Equal(t, uint64(2), *clientOptions.MinPoolSize)
+	assert.Equal(t, time.Minute, *clientOptions.MaxConnIdleTime)
+	assert.Equal(t, time.Second, *clientOptions.Timeout)
+	assert.Equal(t, readpref.SecondaryPreferredMode, clientOptions.ReadPreference.Mode())
+	assert.Equal(t, 2, clientOptions.WriteConcern.W)
+}
+
+// TestClientOptions_Defaults checks that clientOptions keeps the options of the DSN when none are given
+func TestClientOptions_Defaults(t *testing.T) {
+	// Act
+	clientOptions, _, err := clientOptions("mongodb://localhost:27017/test-db?maxPoolSize=50&w=majority", ClientOptions{})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(50), *clientOptions.MaxPoolSize)
+	assert.Nil(t, clientOptions.Timeout)
+	assert.Nil(t, clientOptions.ReadPreference)
+	assert.Equal(t, "majority", clientOptions.WriteConcern.W)
+}
+
+// TestClientOptions_Invalid checks that clientOptions returns an error when an option is not valid
+func TestClientOptions_Invalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		dsn           string
+		opts          ClientOptions
+		expectedError string
+	}{
+		{"DSN", "localhost", ClientOptions{}, "DSN not valid"},
+		{"read preference", "mongodb://localhost/test-db", ClientOptions{ReadPreference: "fastest"}, "read preference fastest not valid"},
+		{"write concern", "mongodb://localhost/test-db", ClientOptions{WriteConcern: "all"}, "write concern all not valid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, _, err := clientOptions(tt.dsn, tt.opts)
+
+			// Assert
+			if assert.NotNil(t, err) {
+				assert.Contains(t, err.Error(), tt.expectedError)
+			}
+		})
+	}
+}
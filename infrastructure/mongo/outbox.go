@@ -0,0 +1,175 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// outboxRepository adapter of an outbox repository for mongo
+type outboxRepository struct {
+	db *mongo.Database
+}
+
+// NewOutboxRepository creates an outbox repository for mongo
+func NewOutboxRepository(db *mongo.Database) ports.OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+func (r *outboxRepository) Add(ctx context.Context, event entities.Event) error {
+	return insertEvent(ctx, r.db, event)
+}
+
+// insertEvent writes an event to the outbox, within the transaction of ctx when it has to be atomic with a change
+func insertEvent(ctx context.Context, db *mongo.Database, event entities.Event) error {
+	if event.NextAttemptAt.IsZero() {
+		event.NextAttemptAt = event.OccurredAt
+	}
+	_, err := db.Collection(entities.EntityNameEvent).InsertOne(ctx, event)
+	return err
+}
+
+func (r *outboxRepository) Pending(ctx context.Context, limit int) ([]entities.Event, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	return r.find(ctx, bson.M{"published_at": nil, "dead_at": nil}, opts)
+}
+
+// Claim finds the earliest pending event of every aggregate and claims the ones due one by one,
+// each only while it is still pending and due, so that an event claimed or published by another instance in between is skipped
+func (r *outboxRepository) Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]entities.Event, error) {
+	collection := r.db.Collection(entities.EntityNameEvent)
+	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"published_at": nil, "dead_at": nil}}},
+		{{Key: "$sort", Value: bson.D{{Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$aggregate_id", "event": bson.M{"$first": "$$ROOT"}}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$event"}}},
+		{{Key: "$match", Value: bson.M{"next_attempt_at": bson.M{"$lte": now}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var heads []entities.Event
+	if err = cursor.All(ctx, &heads); err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$set": bson.M{"next_attempt_at": leaseUntil}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	events := []entities.Event{}
+	for _, head := range heads {
+		_id, err := primitive.ObjectIDFromHex(head.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		filter := bson.M{"_id": _id, "published_at": nil, "dead_at": nil, "next_attempt_at": bson.M{"$lte": now}}
+		var event entities.Event
+		err = collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&event)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, ID string) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{"published_at": time.Now().UTC(), "last_error": ""},
+		"$inc": bson.M{"attempts": 1},
+	}
+	_, err = r.db.Collection(entities.EntityNameEvent).UpdateByID(ctx, _id, update)
+	return err
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, ID string, cause error, nextAttemptAt time.Time) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{"last_error": cause.Error(), "next_attempt_at": nextAttemptAt.UTC()},
+		"$inc": bson.M{"attempts": 1},
+	}
+	_, err = r.db.Collection(entities.EntityNameEvent).UpdateByID(ctx, _id, update)
+	return err
+}
+
+func (r *outboxRepository) MarkDead(ctx context.Context, ID string, cause error) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{"dead_at": time.Now().UTC(), "last_error": cause.Error()},
+		"$inc": bson.M{"attempts": 1},
+	}
+	_, err = r.db.Collection(entities.EntityNameEvent).UpdateByID(ctx, _id, update)
+	return err
+}
+
+func (r *outboxRepository) DeletePublished(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.db.Collection(entities.EntityNameEvent).DeleteMany(ctx, bson.M{"published_at": bson.M{"$lt": before.UTC()}})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.DeletedCount), nil
+}
+
+func (r *outboxRepository) GetByAggregateID(ctx context.Context, aggregateID string) ([]entities.Event, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}})
+	return r.find(ctx, bson.M{"aggregate_id": aggregateID}, opts)
+}
+
+// RedactPayloads replaces the payloads of the events of the aggregate by one only holding its ID
+func (r *outboxRepository) RedactPayloads(ctx context.Context, aggregateID string) error {
+	update := bson.M{
+		"$set": bson.M{"payload": bson.M{"id": aggregateID}},
+	}
+	_, err := r.db.Collection(entities.EntityNameEvent).UpdateMany(ctx, bson.M{"aggregate_id": aggregateID}, update)
+	return err
+}
+
+func (r *outboxRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]entities.Event, error) {
+	cursor, err := r.db.Collection(entities.EntityNameEvent).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []entities.Event{}
+	if err = cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,285 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewOutboxRepository_Ok checks that NewOutboxRepository creates a new outboxRepository struct
+func TestNewOutboxRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Act
+		repo := NewOutboxRepository(mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+	})
+}
+
+// TestOutboxAdd_Ok checks that Add does not return an error when everything goes as expected
+func TestOutboxAdd_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := outboxRepository{
+			db: mt.DB,
+		}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.Add(context.Background(), entities.NewUserEvent(entities.EventTypeUserLoggedIn, "test-id", nil))
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestOutboxAdd_InsertError checks that Add returns an error when the insert fails
+func TestOutboxAdd_InsertError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := outboxRepository{
+			db: mt.DB,
+		}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 1, Message: "insert error"}))
+
+		// Act
+		err := repo.Add(context.Background(), entities.Event{})
+
+		// Assert
+		assert.NotNil(t, err)
+	})
+}
+
+// TestOutboxPending_Ok checks that Pending returns the unpublished events found
+func TestOutboxPending_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := outboxRepository{
+			db: mt.DB,
+		}
+
+		ID := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.outbox_events", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: ID},
+				{Key: "type", Value: "user.created"},
+				{Key: "aggregate_id", Value: "test-user"},
+				{Key: "payload", Value: bson.D{{Key: "email", Value: "test@test.com"}}},
+				{Key: "occurred_at", Value: time.Now()},
+			}),
+		)
+
+		// Act
+		events, err := repo.Pending(context.Background(), 10)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, events, 1)
+		assert.Equal(t, ID.Hex(), events[0].ID)
+		assert.Equal(t, entities.EventTypeUserCreated, events[0].Type)
+		assert.Equal(t, "test@test.com", events[0].Payload["email"])
+	})
+}
+
+// TestOutboxPending_FindError checks that Pending returns an error when the find fails
+func TestOutboxPending_FindError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := outboxRepository{
+			db: mt.DB,
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+
+		// Act
+		events, err := repo.Pending(context.Background(), 10)
+
+		// Assert
+		assert.Nil(t, events)
+		assert.NotNil(t, err)
+	})
+}
+
+// TestOutboxClaim_Ok checks that Claim claims the earliest pending events of the aggregates found,
+// skipping the ones claimed or published by another instance in between
+func TestOutboxClaim_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := outboxRepository{
+			db: mt.DB,
+		}
+
+		now := time.Now()
+		ID1, ID2 := primitive.NewObjectID(), primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.outbox_events", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: ID1}, {Key: "aggregate_id", Value: "test-user-1"}, {Key: "occurred_at", Value: now}},
+				bson.D{{Key: "_id", Value: ID2}, {Key: "aggregate_id", Value: "test-user-2"}, {Key: "occurred_at", Value: now}},
+			),
+			bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: bson.D{{Key: "_id", Value: ID1}, {Key: "aggregate_id", Value: "test-user-1"}}}},
+			bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}},
+		)
+
+		// Act
+		events, err := repo.Claim(context.Background(), now, now.Add(time.Minute), 10)
+
+		// Assert
+		assert.Nil(t, err)
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, ID1.Hex(), events[0].ID)
+		}
+	})
+}
+
+// TestOutboxClaim_AggregateError checks that Claim returns an error when the pending events cannot be found
+func TestOutboxClaim_AggregateError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := outboxRepository{
+			db: mt.DB,
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+
+		// Act
+		events, err := repo.Claim(context.Background(), time.Now(), time.Now().Add(time.Minute), 10)
+
+		// Assert
+		assert.Nil(t, events)
+		assert.NotNil(t, err)
+	})
+}
+
+// TestOutboxMarkPublished_Ok checks that MarkPublished does not return an error when everything goes as expected
+func TestOutboxMarkPublished_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := outboxRepository{
+			db: mt.DB,
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.MarkPublished(context.Background(), primitive.NewObjectID().Hex())
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestOutboxMarkPublished_InvalidID checks that MarkPublished returns an error when the ID is not a valid ObjectID
+func TestOutboxMarkPublished_InvalidID(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := outboxRepository{
+			db: mt.DB,
+		}
+
+		// Act
+		err := repo.MarkPublished(context.Background(), "invalid-id")
+
+		// Assert
+		assert.NotNil(t, err)
+	})
+}
+
+// TestOutboxMarkFailed_Ok checks that MarkFailed does not return an error when everything goes as expected
+func TestOutboxMarkFailed_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := outboxRepository{
+			db: mt.DB,
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.MarkFailed(context.Background(), primitive.NewObjectID().Hex(), errors.New("publish error"), time.Now().Add(time.Minute))
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestOutboxMarkDead_Ok checks that MarkDead does not return an error when everything goes as expected
+func TestOutboxMarkDead_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := outboxRepository{
+			db: mt.DB,
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.MarkDead(context.Background(), primitive.NewObjectID().Hex(), errors.New("publish error"))
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestOutboxMarkDead_InvalidID checks that MarkDead returns an error when the ID is not a valid ObjectID
+func TestOutboxMarkDead_InvalidID(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := outboxRepository{
+			db: mt.DB,
+		}
+
+		// Act
+		err := repo.MarkDead(context.Background(), "invalid-id", errors.New("publish error"))
+
+		// Assert
+		assert.NotNil(t, err)
+	})
+}
+
+// TestOutboxDeletePublished_Ok checks that DeletePublished returns the number of deleted events
+func TestOutboxDeletePublished_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := outboxRepository{
+			db: mt.DB,
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 3}})
+
+		// Act
+		deleted, err := repo.DeletePublished(context.Background(), time.Now())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, 3, deleted)
+	})
+}
@@ -0,0 +1,63 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// preferencesRepository adapter of a preferences repository for mongo, keyed by the ID of the user
+type preferencesRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPreferencesRepository creates a preferences repository for mongo
+func NewPreferencesRepository(db *mongo.Database) ports.PreferencesRepository {
+	return &preferencesRepository{
+		collection: db.Collection(entities.EntityNamePreferences),
+	}
+}
+
+func (r *preferencesRepository) GetByUserID(ctx context.Context, userID string) (entities.Preferences, error) {
+	var preferences entities.Preferences
+	err := r.collection.FindOne(ctx, bson.M{"_id": userID}).Decode(&preferences)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return entities.Preferences{}, err
+	}
+
+	return preferences, nil
+}
+
+// Save inserts the first version of the preferences, relying on the uniqueness of the ID,
+// and replaces the following ones only while the stored document is at the previous version, failing with a conflict error otherwise
+func (r *preferencesRepository) Save(ctx context.Context, preferences entities.Preferences) error {
+	if preferences.Version == 1 {
+		_, err := r.collection.InsertOne(ctx, preferences)
+		if mongo.IsDuplicateKeyError(err) {
+			err = entities.NewOutdatedPreferencesErr(0)
+		}
+		return err
+	}
+
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": preferences.UserID, "version": preferences.Version - 1}, preferences)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount < 1 {
+		return entities.NewOutdatedPreferencesErr(preferences.Version - 1)
+	}
+	return nil
+}
+
+func (r *preferencesRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": userID})
+	return err
+}
@@ -0,0 +1,171 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewPreferencesRepository_Ok checks that NewPreferencesRepository creates a new preferencesRepository struct
+func TestNewPreferencesRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Act
+		repo := NewPreferencesRepository(mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+	})
+}
+
+// TestPreferencesGetByUserID_Ok checks that GetByUserID returns the preferences found, leaving nil the ones not overridden
+func TestPreferencesGetByUserID_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := preferencesRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.preferences", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: "test-user-id"},
+			{Key: "version", Value: 2},
+			{Key: "language", Value: "es"},
+			{Key: "notifications", Value: bson.D{{Key: "push", Value: false}}},
+		}))
+
+		// Act
+		preferences, err := repo.GetByUserID(context.Background(), "test-user-id")
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, "test-user-id", preferences.UserID)
+		assert.Equal(t, 2, preferences.Version)
+		assert.Equal(t, "es", *preferences.Language)
+		assert.Nil(t, preferences.Timezone)
+		assert.False(t, *preferences.Notifications.Push)
+		assert.Nil(t, preferences.Notifications.Email)
+	})
+}
+
+// TestPreferencesGetByUserID_NotFound checks that GetByUserID returns a non existent error when the user has no preferences
+func TestPreferencesGetByUserID_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := preferencesRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.preferences", mtest.FirstBatch))
+
+		// Act
+		_, err := repo.GetByUserID(context.Background(), "test-user-id")
+
+		// Assert
+		assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	})
+}
+
+// TestPreferencesSave_First checks that Save inserts the first version of the preferences
+func TestPreferencesSave_First(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := preferencesRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.Save(context.Background(), entities.Preferences{UserID: "test-user-id", Version: 1})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestPreferencesSave_FirstExists checks that Save returns a conflict error when the first version is inserted but preferences already exist
+func TestPreferencesSave_FirstExists(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := preferencesRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key error"}))
+
+		// Act
+		err := repo.Save(context.Background(), entities.Preferences{UserID: "test-user-id", Version: 1})
+
+		// Assert
+		assert.True(t, errors.Is(err, entities.ConflictErr))
+	})
+}
+
+// TestPreferencesSave_Ok checks that Save replaces the preferences stored at the previous version
+func TestPreferencesSave_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := preferencesRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.Save(context.Background(), entities.Preferences{UserID: "test-user-id", Version: 3})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestPreferencesSave_OutdatedVersion checks that Save returns a conflict error when the stored preferences are not at the previous version
+func TestPreferencesSave_OutdatedVersion(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := preferencesRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		// Act
+		err := repo.Save(context.Background(), entities.Preferences{UserID: "test-user-id", Version: 3})
+
+		// Assert
+		assert.True(t, errors.Is(err, entities.ConflictErr))
+	})
+}
+
+// TestPreferencesDeleteByUserID_Ok checks that DeleteByUserID does not return an error when the user has no preferences
+func TestPreferencesDeleteByUserID_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := preferencesRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}})
+
+		// Act
+		err := repo.DeleteByUserID(context.Background(), "test-user-id")
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,31 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// tombstoneRepository adapter of a tombstone repository for mongo
+type tombstoneRepository struct {
+	collection *mongo.Collection
+}
+
+// NewTombstoneRepository creates a tombstone repository for mongo
+func NewTombstoneRepository(db *mongo.Database) ports.TombstoneRepository {
+	return &tombstoneRepository{
+		collection: db.Collection(entities.EntityNameTombstone),
+	}
+}
+
+func (r *tombstoneRepository) Create(ctx context.Context, tombstone entities.Tombstone) (string, error) {
+	result, err := r.collection.InsertOne(ctx, tombstone)
+	if err != nil {
+		return "", err
+	}
+
+	return result.InsertedID.(primitive.ObjectID).Hex(), nil
+}
@@ -0,0 +1,70 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewTombstoneRepository_Ok checks that NewTombstoneRepository creates a new tombstoneRepository struct
+func TestNewTombstoneRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Act
+		repo := NewTombstoneRepository(mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+	})
+}
+
+// TestTombstoneCreate_Ok checks that Create returns the generated ID when a valid tombstone is received
+func TestTombstoneCreate_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := tombstoneRepository{
+			collection: mt.DB.Collection(entities.EntityNameTombstone),
+		}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		id, err := repo.Create(context.Background(), entities.Tombstone{
+			UserID:   "test-user-id",
+			ErasedBy: "test-admin-id",
+			Stores:   []string{"users"},
+			ErasedAt: time.Now(),
+		})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.NotEmpty(t, id)
+	})
+}
+
+// TestTombstoneCreate_InsertError checks that Create returns an error when the insert fails
+func TestTombstoneCreate_InsertError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := tombstoneRepository{
+			collection: mt.DB.Collection(entities.EntityNameTombstone),
+		}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 1, Message: "insert error"}))
+
+		// Act
+		id, err := repo.Create(context.Background(), entities.Tombstone{})
+
+		// Assert
+		assert.Empty(t, id)
+		assert.NotNil(t, err)
+	})
+}
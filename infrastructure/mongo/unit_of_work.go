@@ -0,0 +1,54 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// unitOfWork adapter of a unit of work for mongo, whose session is propagated through the context
+type unitOfWork struct {
+	client *mongo.Client
+}
+
+// NewUnitOfWork creates a unit of work for mongo, whose transactions require a replica set
+func NewUnitOfWork(db *mongo.Database) ports.UnitOfWork {
+	return &unitOfWork{
+		client: db.Client(),
+	}
+}
+
+// Do runs fn in a transaction of a new session. The transaction is retried on transient errors, so fn can run more than once.
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withTransaction(ctx, u.client, func(sessionContext mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionContext)
+	})
+}
+
+// withTransaction runs callback in the transaction of the unit of work running in the context, which is left to the unit of work to commit.
+// Otherwise callback runs in a transaction of a new session, that gets committed when callback succeeds and aborted otherwise.
+func withTransaction(ctx context.Context, client *mongo.Client, callback func(mongo.SessionContext) (interface{}, error)) error {
+	if session := mongo.SessionFromContext(ctx); session != nil {
+		_, err := callback(mongo.NewSessionContext(ctx, session))
+		return err
+	}
+
+	wc := writeconcern.New(writeconcern.WMajority())
+	rc := readconcern.Snapshot()
+	// transactions must read from the primary, whatever the read preference of the client
+	txnOpts := options.Transaction().SetWriteConcern(wc).SetReadConcern(rc).SetReadPreference(readpref.Primary())
+
+	session, err := client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, callback, txnOpts)
+	return err
+}
@@ -0,0 +1,94 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestUnitOfWorkDo_Ok checks that Do runs the calls of every repository, including the ones of a nested transaction, in a single transaction that gets committed
+func TestUnitOfWorkDo_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		uow := NewUnitOfWork(mt.DB)
+		users := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+		audit := auditRepository{
+			collection: mt.DB.Collection(entities.EntityNameAuditEvent),
+		}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mt.ClearEvents()
+
+		// Act
+		err := uow.Do(context.Background(), func(ctx context.Context) error {
+			if _, err := users.CreateMany(ctx, []entities.User{{}}); err != nil {
+				return err
+			}
+			_, err := audit.Create(ctx, entities.AuditEvent{})
+			return err
+		})
+
+		// Assert
+		assert.Nil(t, err)
+		var commands []string
+		for _, event := range mt.GetAllStartedEvents() {
+			commands = append(commands, event.CommandName)
+			if event.CommandName == "insert" {
+				_, err = event.Command.LookupErr("txnNumber")
+				assert.Nil(t, err)
+			}
+		}
+		assert.Equal(t, []string{"insert", "insert", "insert", "commitTransaction"}, commands)
+	})
+}
+
+// TestUnitOfWorkDo_Abort checks that Do aborts the transaction when fn fails
+func TestUnitOfWorkDo_Abort(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		uow := NewUnitOfWork(mt.DB)
+		audit := auditRepository{
+			collection: mt.DB.Collection(entities.EntityNameAuditEvent),
+		}
+		expectedError := errors.New("fn error")
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mt.ClearEvents()
+
+		// Act
+		err := uow.Do(context.Background(), func(ctx context.Context) error {
+			if _, err := audit.Create(ctx, entities.AuditEvent{}); err != nil {
+				return err
+			}
+			return expectedError
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, expectedError)
+		var commands []string
+		for _, event := range mt.GetAllStartedEvents() {
+			commands = append(commands, event.CommandName)
+		}
+		assert.Equal(t, []string{"insert", "abortTransaction"}, commands)
+	})
+}
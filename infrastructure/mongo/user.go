@@ -2,15 +2,18 @@ package mongo
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
 	"github.com/sergicanet9/go-hexagonal-api/core/ports"
 	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.mongodb.org/mongo-driver/mongo/readconcern"
-	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // userRepository adapter of an user repository for mongo.
@@ -19,40 +22,96 @@ type userRepository struct {
 }
 
 // NewUserRepository creates a user repository for mongo
-func NewUserRepository(ctx context.Context, db *mongo.Database) (ports.UserRepository, error) {
-	r := &userRepository{
+func NewUserRepository(db *mongo.Database) ports.UserRepository {
+	return &userRepository{
 		infrastructure.MongoRepository{
 			DB:         db,
 			Collection: db.Collection(entities.EntityNameUser),
 			Target:     entities.User{},
 		},
 	}
+}
 
-	_, err := r.Collection.Indexes().CreateOne(
-		ctx,
-		mongo.IndexModel{
-			Keys:    bson.D{{Key: "email", Value: 1}},
-			Options: options.Index().SetUnique(true),
-		},
-	)
-	return r, err
+func (r *userRepository) Create(ctx context.Context, user entities.User) (string, error) {
+	var ID string
+	callback := func(sessionContext mongo.SessionContext) (_ interface{}, err error) {
+		ID, err = r.create(sessionContext, user)
+		return
+	}
+
+	err := withTransaction(ctx, r.DB.Client(), callback)
+	if err != nil {
+		return "", err
+	}
+
+	return ID, nil
 }
 
-func (r *userRepository) CreateMany(ctx context.Context, users []interface{}) ([]string, error) {
-	wc := writeconcern.New(writeconcern.WMajority())
-	rc := readconcern.Snapshot()
-	txnOpts := options.Transaction().SetWriteConcern(wc).SetReadConcern(rc)
+// Get sorts the users by ID, so that the pages are stable
+func (r *userRepository) Get(ctx context.Context, filter models.UserFilter, skip, take *int) ([]entities.User, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if skip != nil {
+		opts.SetSkip(int64(*skip))
+	}
+	if take != nil {
+		opts.SetLimit(int64(*take))
+	}
 
-	session, err := r.DB.Client().StartSession()
+	cursor, err := r.Collection.Find(ctx, userQuery(filter), opts)
 	if err != nil {
 		return nil, err
 	}
-	defer session.EndSession(ctx)
+	defer cursor.Close(ctx)
+
+	var users []entities.User
+	if err = cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	if len(users) < 1 {
+		return nil, wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+
+	return users, nil
+}
+
+func (r *userRepository) GetByID(ctx context.Context, ID string) (entities.User, error) {
+	var u entities.User
+	_id, err := objectID(ID)
+	if err != nil {
+		return u, err
+	}
+
+	err = r.Collection.FindOne(ctx, bson.M{"_id": _id}).Decode(&u)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = wrappers.NewNonExistentErr(err)
+	}
+	return u, err
+}
+
+func (r *userRepository) Update(ctx context.Context, ID string, user entities.User) error {
+	callback := func(sessionContext mongo.SessionContext) (interface{}, error) {
+		return nil, r.update(sessionContext, ID, user)
+	}
+
+	return withTransaction(ctx, r.DB.Client(), callback)
+}
+
+func (r *userRepository) Delete(ctx context.Context, ID string) error {
+	callback := func(sessionContext mongo.SessionContext) (interface{}, error) {
+		return nil, r.delete(sessionContext, ID)
+	}
 
+	return withTransaction(ctx, r.DB.Client(), callback)
+}
+
+func (r *userRepository) CreateMany(ctx context.Context, users []entities.User) ([]string, error) {
 	var result []string
 	callback := func(sessionContext mongo.SessionContext) (interface{}, error) {
-		for _, entity := range users {
-			id, err := r.Create(sessionContext, entity)
+		// the callback can be retried on transient errors, so the IDs of previous attempts are discarded
+		result = nil
+		for _, user := range users {
+			id, err := r.create(sessionContext, user)
 			if err != nil {
 				return nil, err
 			}
@@ -61,6 +120,196 @@ func (r *userRepository) CreateMany(ctx context.Context, users []interface{}) ([
 		return nil, nil
 	}
 
-	_, err = session.WithTransaction(context.Background(), callback, txnOpts)
-	return result, err
+	err := withTransaction(ctx, r.DB.Client(), callback)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *userRepository) UpdateMany(ctx context.Context, IDs []string, users []entities.User) error {
+	callback := func(sessionContext mongo.SessionContext) (interface{}, error) {
+		for i, user := range users {
+			err := r.update(sessionContext, IDs[i], user)
+			if err != nil {
+				return nil, withID(err, IDs[i])
+			}
+		}
+		return nil, nil
+	}
+
+	return withTransaction(ctx, r.DB.Client(), callback)
+}
+
+func (r *userRepository) DeleteMany(ctx context.Context, IDs []string) error {
+	callback := func(sessionContext mongo.SessionContext) (interface{}, error) {
+		for _, ID := range IDs {
+			err := r.delete(sessionContext, ID)
+			if err != nil {
+				return nil, withID(err, ID)
+			}
+		}
+		return nil, nil
+	}
+
+	return withTransaction(ctx, r.DB.Client(), callback)
+}
+
+func (r *userRepository) Iterate(ctx context.Context, filter models.UserFilter, fn func(user entities.User) error) error {
+	cursor, err := r.Collection.Find(ctx, userQuery(filter), options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var u entities.User
+		if err = cursor.Decode(&u); err != nil {
+			return err
+		}
+		if err = fn(u); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+func (r *userRepository) Search(ctx context.Context, query string, offset, limit int) ([]entities.User, error) {
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.Collection.Find(ctx, bson.M{"$text": bson.M{"$search": query}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	users := []entities.User{}
+	if err = cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// userQuery builds the query of the users matching the filter
+func userQuery(filter models.UserFilter) bson.M {
+	query := bson.M{}
+	if filter.Name != "" {
+		query["name"] = filter.Name
+	}
+	if filter.Surnames != "" {
+		query["surnames"] = filter.Surnames
+	}
+	if filter.EmailNormalized != "" {
+		query["email_normalized"] = filter.EmailNormalized
+	}
+	createdAt := bson.M{}
+	if !filter.CreatedFrom.IsZero() {
+		createdAt["$gte"] = filter.CreatedFrom
+	}
+	if !filter.CreatedTo.IsZero() {
+		createdAt["$lt"] = filter.CreatedTo
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+	if filter.ClaimID != nil {
+		query["claim_ids"] = *filter.ClaimID
+	}
+	return query
+}
+
+// create inserts the user and its created event to the outbox, it has to be called within a transaction
+func (r *userRepository) create(ctx context.Context, user entities.User) (string, error) {
+	ID, err := r.MongoRepository.Create(ctx, user)
+	if err != nil {
+		return "", emailInUseIfDuplicate(err, user.EmailNormalized)
+	}
+
+	return ID, insertEvent(ctx, r.DB, entities.NewUserEvent(entities.EventTypeUserCreated, ID, &user))
+}
+
+// update updates the user and writes its updated event to the outbox, it has to be called within a transaction.
+// The user is found by the matched count, as the modified one is zero when nothing changes.
+func (r *userRepository) update(ctx context.Context, ID string, user entities.User) error {
+	_id, err := objectID(ID)
+	if err != nil {
+		return err
+	}
+
+	// the ID of the user is left out of the update, as _id is immutable and stored as an ObjectID
+	fields := user
+	fields.ID = ""
+	result, err := r.Collection.UpdateOne(ctx, bson.M{"_id": _id}, bson.M{"$set": fields})
+	if err != nil {
+		return emailInUseIfDuplicate(err, user.EmailNormalized)
+	}
+	if result.MatchedCount < 1 {
+		return wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+
+	return insertEvent(ctx, r.DB, entities.NewUserEvent(entities.EventTypeUserUpdated, ID, &user))
+}
+
+// delete deletes the user and writes its deleted event to the outbox, it has to be called within a transaction
+func (r *userRepository) delete(ctx context.Context, ID string) error {
+	if _, err := objectID(ID); err != nil {
+		return err
+	}
+
+	err := r.MongoRepository.Delete(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	for _, dependent := range userDependents {
+		if _, err := r.DB.Collection(dependent.collection).DeleteMany(ctx, bson.M{dependent.field: ID}); err != nil {
+			return err
+		}
+	}
+
+	return insertEvent(ctx, r.DB, entities.NewUserEvent(entities.EventTypeUserDeleted, ID, nil))
+}
+
+// userDependents collections of the documents belonging to a user, by the field holding its ID,
+// deleted in the same transaction as the user so that none of them outlives it
+var userDependents = []struct {
+	collection string
+	field      string
+}{
+	{collection: entities.EntityNamePreferences, field: "_id"},
+	{collection: entities.EntityNameInvitation, field: "user_id"},
+	{collection: entities.EntityNameEmailChange, field: "user_id"},
+}
+
+// objectID parses the ID of a document, a malformed one cannot belong to any document so it is reported as not found
+func objectID(ID string) (primitive.ObjectID, error) {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return _id, wrappers.NewNonExistentErr(err)
+	}
+	return _id, nil
+}
+
+// emailInUseIfDuplicate maps the duplicate key errors, only raised by the normalized emails of the users, to an AlreadyExistsErr
+func emailInUseIfDuplicate(err error, emailNormalized string) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return entities.NewEmailInUseErr(emailNormalized)
+	}
+	return err
+}
+
+// withID adds the affected ID to the message of NonExistentErr errors
+func withID(err error, ID string) error {
+	if errors.Is(err, wrappers.NonExistentErr) {
+		return wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ID))
+	}
+	return err
 }
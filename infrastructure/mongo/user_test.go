@@ -2,13 +2,17 @@ package mongo
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
 	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
 	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
 )
 
@@ -16,16 +20,87 @@ import (
 func TestNewUserRepository_Ok(t *testing.T) {
 	mt := mocks.NewMongoDB(t)
 
+	mt.Run("", func(mt *mtest.T) {
+		// Act
+		repo := NewUserRepository(mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+	})
+}
+
+// TestGet_Ok checks that Get returns the users found by Find
+func TestGet_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
 	mt.Run("", func(mt *mtest.T) {
 		// Arrange
-		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		repo := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		ID := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.users", mtest.FirstBatch, bson.D{{Key: "_id", Value: ID}, {Key: "name", Value: "test"}}))
+		skip, take := 1, 1
 
 		// Act
-		repo, err := NewUserRepository(context.Background(), mt.DB)
+		users, err := repo.Get(context.Background(), models.UserFilter{Name: "test"}, &skip, &take)
 
 		// Assert
-		assert.NotEmpty(t, repo)
 		assert.Nil(t, err)
+		if assert.Len(t, users, 1) {
+			assert.Equal(t, ID.Hex(), users[0].ID)
+		}
+	})
+}
+
+// TestGet_NotFound checks that Get returns a non existent error when no users are found
+func TestGet_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.users", mtest.FirstBatch))
+
+		// Act
+		_, err := repo.Get(context.Background(), models.UserFilter{}, nil, nil)
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	})
+}
+
+// TestGetByID_MalformedID checks that GetByID returns a non existent error when the ID is not a valid ObjectID
+func TestGetByID_MalformedID(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		// Act
+		_, err := repo.GetByID(context.Background(), "malformed-id")
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
 	})
 }
 
@@ -43,11 +118,12 @@ func TestCreateMany_Ok(t *testing.T) {
 			},
 		}
 
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
 		mt.AddMockResponses(mtest.CreateSuccessResponse())
 		mt.AddMockResponses(mtest.CreateSuccessResponse())
 
 		entityToAdd := entities.User{}
-		newEntities := []interface{}{entityToAdd}
+		newEntities := []entities.User{entityToAdd}
 
 		// Act
 		ids, err := repo.CreateMany(context.Background(), newEntities)
@@ -75,7 +151,7 @@ func TestInsertMany_CreateError(t *testing.T) {
 
 		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
 
-		newEntities := []interface{}{entities.User{}}
+		newEntities := []entities.User{{}}
 
 		// Act
 		_, err := repo.CreateMany(context.Background(), newEntities)
@@ -84,3 +160,329 @@ func TestInsertMany_CreateError(t *testing.T) {
 		assert.NotEmpty(t, err)
 	})
 }
+
+// TestCreateMany_EmailInUse checks that CreateMany returns an AlreadyExistsErr including the email that is already in use
+func TestCreateMany_EmailInUse(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key error"}))
+
+		newEntities := []entities.User{{EmailNormalized: "test@test.com"}}
+
+		// Act
+		_, err := repo.CreateMany(context.Background(), newEntities)
+
+		// Assert
+		assert.ErrorIs(t, err, entities.AlreadyExistsErr)
+		assert.Equal(t, "email test@test.com is already in use", err.Error())
+	})
+}
+
+// TestUpdateMany_Ok checks that UpdateMany does not return an error when everything goes as expected
+func TestUpdateMany_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.UpdateMany(context.Background(), []string{primitive.NewObjectID().Hex()}, []entities.User{{}})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestUpdateMany_Unchanged checks that UpdateMany does not return an error when a user is found but nothing changes
+func TestUpdateMany_Unchanged(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 0}})
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.UpdateMany(context.Background(), []string{primitive.NewObjectID().Hex()}, []entities.User{{}})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestUpdateMany_EmailInUse checks that UpdateMany returns an AlreadyExistsErr including the email that is already in use
+func TestUpdateMany_EmailInUse(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key error"}))
+
+		// Act
+		err := repo.UpdateMany(context.Background(), []string{primitive.NewObjectID().Hex()}, []entities.User{{EmailNormalized: "test@test.com"}})
+
+		// Assert
+		assert.ErrorIs(t, err, entities.AlreadyExistsErr)
+		assert.Equal(t, "email test@test.com is already in use", err.Error())
+	})
+}
+
+// TestUpdateMany_NotFound checks that UpdateMany returns an error including the ID when one of the users does not exist
+func TestUpdateMany_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		ID := primitive.NewObjectID().Hex()
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.UpdateMany(context.Background(), []string{ID}, []entities.User{{}})
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
+		assert.Equal(t, fmt.Sprintf("ID %s not found", ID), err.Error())
+	})
+}
+
+// TestDeleteMany_Ok checks that DeleteMany does not return an error when everything goes as expected
+func TestDeleteMany_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}})
+		for range userDependents {
+			mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}})
+		}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.DeleteMany(context.Background(), []string{primitive.NewObjectID().Hex()})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestDeleteMany_DependentError checks that DeleteMany returns an error when deleting the documents belonging to a user fails
+func TestDeleteMany_DependentError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}})
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+
+		// Act
+		err := repo.DeleteMany(context.Background(), []string{primitive.NewObjectID().Hex()})
+
+		// Assert
+		assert.NotNil(t, err)
+	})
+}
+
+// TestDeleteMany_DeleteError checks that DeleteMany returns an error when Delete fails
+func TestDeleteMany_DeleteError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+
+		// Act
+		err := repo.DeleteMany(context.Background(), []string{primitive.NewObjectID().Hex()})
+
+		// Assert
+		assert.NotEmpty(t, err)
+	})
+}
+
+// TestIterate_Ok checks that Iterate calls the given function with every user returned by the cursor
+func TestIterate_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		claimID := int32(0)
+		ID1 := primitive.NewObjectID()
+		ID2 := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(1, "test.users", mtest.FirstBatch, bson.D{{Key: "_id", Value: ID1}, {Key: "email", Value: "test1@test.com"}}),
+			mtest.CreateCursorResponse(0, "test.users", mtest.NextBatch, bson.D{{Key: "_id", Value: ID2}, {Key: "email", Value: "test2@test.com"}}),
+		)
+
+		var users []entities.User
+
+		// Act
+		err := repo.Iterate(context.Background(), models.UserFilter{ClaimID: &claimID}, func(user entities.User) error {
+			users = append(users, user)
+			return nil
+		})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, users, 2)
+		assert.Equal(t, ID1.Hex(), users[0].ID)
+		assert.Equal(t, "test2@test.com", users[1].Email)
+	})
+}
+
+// TestIterate_FindError checks that Iterate returns an error when Find fails
+func TestIterate_FindError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+
+		// Act
+		err := repo.Iterate(context.Background(), models.UserFilter{}, func(user entities.User) error { return nil })
+
+		// Assert
+		assert.NotEmpty(t, err)
+	})
+}
+
+// TestSearch_Ok checks that Search returns the users matched by the text index
+func TestSearch_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		ID := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(1, "test.users", mtest.FirstBatch, bson.D{{Key: "_id", Value: ID}, {Key: "name", Value: "José"}, {Key: "score", Value: 1.5}}),
+			mtest.CreateCursorResponse(0, "test.users", mtest.NextBatch),
+		)
+
+		// Act
+		users, err := repo.Search(context.Background(), "jose", 0, 10)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, ID.Hex(), users[0].ID)
+		assert.Equal(t, "José", users[0].Name)
+	})
+}
+
+// TestSearch_FindError checks that Search returns an error when Find fails
+func TestSearch_FindError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := userRepository{
+			infrastructure.MongoRepository{
+				DB:         mt.DB,
+				Collection: mt.DB.Collection(entities.EntityNameUser),
+				Target:     entities.User{},
+			},
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+
+		// Act
+		_, err := repo.Search(context.Background(), "jose", 0, 10)
+
+		// Assert
+		assert.NotEmpty(t, err)
+	})
+}
@@ -0,0 +1,120 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// invalidResumeTokenCodes server error codes of the resume tokens that are malformed or no longer in the oplog
+var invalidResumeTokenCodes = []int32{260, 280, 286}
+
+// userWatcher adapter of a user watcher for mongo, based on change streams.
+// The resume tokens are the _data of the change stream resume tokens.
+type userWatcher struct {
+	collection *mongo.Collection
+}
+
+// NewUserWatcher creates a user watcher for mongo
+func NewUserWatcher(db *mongo.Database) ports.UserWatcher {
+	return &userWatcher{
+		collection: db.Collection(entities.EntityNameUser),
+	}
+}
+
+// changeEvent change stream event of a user
+type changeEvent struct {
+	OperationType string `bson:"operationType"`
+	DocumentKey   struct {
+		ID string `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument *entities.User      `bson:"fullDocument"`
+	ClusterTime  primitive.Timestamp `bson:"clusterTime"`
+}
+
+func (w *userWatcher) Watch(ctx context.Context, resumeToken string, fn func(change entities.UserChange) error) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}}}}},
+	}
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if resumeToken != "" {
+		opts.SetResumeAfter(bson.M{"_data": resumeToken})
+	}
+
+	stream, err := w.collection.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return resumeErr(err, resumeToken)
+	}
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		var event changeEvent
+		if err = stream.Decode(&event); err != nil {
+			return err
+		}
+
+		change := entities.UserChange{
+			ResumeToken: stream.ResumeToken().Lookup("_data").StringValue(),
+			Type:        toUserChangeType(event.OperationType),
+			UserID:      event.DocumentKey.ID,
+			User:        event.FullDocument,
+			OccurredAt:  time.Unix(int64(event.ClusterTime.T), 0).UTC(),
+		}
+		if err = fn(change); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return resumeErr(stream.Err(), resumeToken)
+}
+
+// Prune deletes no change, as the changes are read from the oplog, whose size bounds the time they can be resumed after
+func (w *userWatcher) Prune(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+// GetByUserID returns no change, as the changes are only kept in the oplog, which cannot be read by user
+func (w *userWatcher) GetByUserID(ctx context.Context, userID string) ([]entities.UserChange, error) {
+	return nil, nil
+}
+
+// EraseStates clears no state, as the changes are only kept in the oplog, which cannot be changed
+func (w *userWatcher) EraseStates(ctx context.Context, userID string) error {
+	return nil
+}
+
+func toUserChangeType(operationType string) entities.UserChangeType {
+	switch operationType {
+	case "insert":
+		return entities.UserChangeTypeCreated
+	case "delete":
+		return entities.UserChangeTypeDeleted
+	default:
+		return entities.UserChangeTypeUpdated
+	}
+}
+
+// resumeErr maps the errors caused by the resume token to validation errors
+func resumeErr(err error, resumeToken string) error {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		for _, code := range invalidResumeTokenCodes {
+			if cmdErr.Code == code {
+				return wrappers.NewValidationErr(fmt.Errorf("resume token %s is not valid or has expired", resumeToken))
+			}
+		}
+	}
+	return err
+}
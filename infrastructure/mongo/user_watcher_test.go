@@ -0,0 +1,137 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewUserWatcher_Ok checks that NewUserWatcher creates a new userWatcher struct
+func TestNewUserWatcher_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Act
+		watcher := NewUserWatcher(mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, watcher)
+	})
+}
+
+// TestWatch_Ok checks that Watch calls fn for every change of the change stream, with its resume token
+func TestWatch_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		watcher := userWatcher{
+			collection: mt.Coll,
+		}
+
+		ID := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "test.users", mtest.FirstBatch,
+			bson.D{
+				{Key: "_id", Value: bson.D{{Key: "_data", Value: "token-1"}}},
+				{Key: "operationType", Value: "insert"},
+				{Key: "documentKey", Value: bson.D{{Key: "_id", Value: ID}}},
+				{Key: "fullDocument", Value: bson.D{{Key: "_id", Value: ID}, {Key: "email", Value: "test@test.com"}}},
+				{Key: "clusterTime", Value: primitive.Timestamp{T: 1700000000}},
+			},
+			bson.D{
+				{Key: "_id", Value: bson.D{{Key: "_data", Value: "token-2"}}},
+				{Key: "operationType", Value: "delete"},
+				{Key: "documentKey", Value: bson.D{{Key: "_id", Value: ID}}},
+				{Key: "clusterTime", Value: primitive.Timestamp{T: 1700000001}},
+			},
+		))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var changes []entities.UserChange
+		fn := func(change entities.UserChange) error {
+			changes = append(changes, change)
+			if len(changes) == 2 {
+				cancel()
+			}
+			return nil
+		}
+
+		// Act
+		err := watcher.Watch(ctx, "token-0", fn)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, changes, 2)
+		assert.Equal(t, "token-1", changes[0].ResumeToken)
+		assert.Equal(t, entities.UserChangeTypeCreated, changes[0].Type)
+		assert.Equal(t, ID.Hex(), changes[0].UserID)
+		assert.Equal(t, "test@test.com", changes[0].User.Email)
+		assert.Equal(t, int64(1700000000), changes[0].OccurredAt.Unix())
+		assert.Equal(t, "token-2", changes[1].ResumeToken)
+		assert.Equal(t, entities.UserChangeTypeDeleted, changes[1].Type)
+		assert.Nil(t, changes[1].User)
+	})
+}
+
+// TestWatch_InvalidResumeToken checks that Watch returns a validation error when the resume token is no longer in the oplog
+func TestWatch_InvalidResumeToken(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		watcher := userWatcher{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code:    286,
+			Name:    "ChangeStreamHistoryLost",
+			Message: "history lost",
+		}))
+
+		// Act
+		err := watcher.Watch(context.Background(), "expired", nil)
+
+		// Assert
+		assert.True(t, errors.Is(err, wrappers.ValidationErr))
+		assert.Equal(t, "resume token expired is not valid or has expired", err.Error())
+	})
+}
+
+// TestWatch_CallbackError checks that Watch returns the error of fn, stopping the watch
+func TestWatch_CallbackError(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		watcher := userWatcher{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "test.users", mtest.FirstBatch,
+			bson.D{
+				{Key: "_id", Value: bson.D{{Key: "_data", Value: "token-1"}}},
+				{Key: "operationType", Value: "update"},
+				{Key: "documentKey", Value: bson.D{{Key: "_id", Value: primitive.NewObjectID()}}},
+			},
+		))
+
+		expectedError := "send error"
+
+		// Act
+		err := watcher.Watch(context.Background(), "", func(change entities.UserChange) error {
+			return errors.New(expectedError)
+		})
+
+		// Assert
+		assert.Equal(t, expectedError, err.Error())
+	})
+}
@@ -0,0 +1,240 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// webhookSubscriptionRepository adapter of a webhook subscription repository for mongo
+type webhookSubscriptionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewWebhookSubscriptionRepository creates a webhook subscription repository for mongo
+func NewWebhookSubscriptionRepository(db *mongo.Database) ports.WebhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{
+		collection: db.Collection(entities.EntityNameWebhookSubscription),
+	}
+}
+
+func (r *webhookSubscriptionRepository) Create(ctx context.Context, subscription entities.WebhookSubscription) (string, error) {
+	result, err := r.collection.InsertOne(ctx, subscription)
+	if err != nil {
+		return "", err
+	}
+
+	return result.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *webhookSubscriptionRepository) GetAll(ctx context.Context) ([]entities.WebhookSubscription, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	subscriptions := []entities.WebhookSubscription{}
+	if err = cursor.All(ctx, &subscriptions); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (r *webhookSubscriptionRepository) GetByID(ctx context.Context, ID string) (entities.WebhookSubscription, error) {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return entities.WebhookSubscription{}, err
+	}
+
+	var subscription entities.WebhookSubscription
+	err = r.collection.FindOne(ctx, bson.M{"_id": _id}).Decode(&subscription)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return entities.WebhookSubscription{}, err
+	}
+
+	return subscription, nil
+}
+
+func (r *webhookSubscriptionRepository) Update(ctx context.Context, ID string, subscription entities.WebhookSubscription) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	subscription.ID = ""
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": _id}, subscription)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount < 1 {
+		return wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+func (r *webhookSubscriptionRepository) Delete(ctx context.Context, ID string) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": _id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount < 1 {
+		return wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+// webhookDeliveryRepository adapter of a webhook delivery repository for mongo
+type webhookDeliveryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewWebhookDeliveryRepository creates a webhook delivery repository for mongo
+func NewWebhookDeliveryRepository(db *mongo.Database) ports.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{
+		collection: db.Collection(entities.EntityNameWebhookDelivery),
+	}
+}
+
+func (r *webhookDeliveryRepository) CreateMany(ctx context.Context, deliveries []entities.WebhookDelivery) error {
+	documents := make([]interface{}, len(deliveries))
+	for i, delivery := range deliveries {
+		documents[i] = delivery
+	}
+
+	_, err := r.collection.InsertMany(ctx, documents)
+	return err
+}
+
+func (r *webhookDeliveryRepository) GetByID(ctx context.Context, ID string) (entities.WebhookDelivery, error) {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return entities.WebhookDelivery{}, err
+	}
+
+	var delivery entities.WebhookDelivery
+	err = r.collection.FindOne(ctx, bson.M{"_id": _id}).Decode(&delivery)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return entities.WebhookDelivery{}, err
+	}
+
+	return delivery, nil
+}
+
+// Claim claims the due deliveries one by one, as every find and update of a single document is atomic
+func (r *webhookDeliveryRepository) Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]entities.WebhookDelivery, error) {
+	filter := bson.M{
+		"status":          entities.WebhookDeliveryStatusPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"next_attempt_at": leaseUntil}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	deliveries := []entities.WebhookDelivery{}
+	for len(deliveries) < limit {
+		var delivery entities.WebhookDelivery
+		err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhookDeliveryRepository) GetByStatus(ctx context.Context, status entities.WebhookDeliveryStatus) ([]entities.WebhookDelivery, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	return r.find(ctx, bson.M{"status": status}, opts)
+}
+
+func (r *webhookDeliveryRepository) GetByAggregateID(ctx context.Context, aggregateID string) ([]entities.WebhookDelivery, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	return r.find(ctx, bson.M{"aggregate_id": aggregateID}, opts)
+}
+
+func (r *webhookDeliveryRepository) Update(ctx context.Context, delivery entities.WebhookDelivery) error {
+	_id, err := primitive.ObjectIDFromHex(delivery.ID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"last_error":      delivery.LastError,
+			"delivered_at":    delivery.DeliveredAt,
+		},
+	}
+	result, err := r.collection.UpdateByID(ctx, _id, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount < 1 {
+		return wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+func (r *webhookDeliveryRepository) UpdateBody(ctx context.Context, ID string, body []byte) error {
+	_id, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateByID(ctx, _id, bson.M{"$set": bson.M{"body": body}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount < 1 {
+		return wrappers.NewNonExistentErr(mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+func (r *webhookDeliveryRepository) DeleteBySubscription(ctx context.Context, subscriptionID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"subscription_id": subscriptionID})
+	return err
+}
+
+func (r *webhookDeliveryRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]entities.WebhookDelivery, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []entities.WebhookDelivery{}
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
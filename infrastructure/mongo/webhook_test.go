@@ -0,0 +1,243 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestNewWebhookSubscriptionRepository_Ok checks that NewWebhookSubscriptionRepository creates a new webhookSubscriptionRepository struct
+func TestNewWebhookSubscriptionRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Act
+		repo := NewWebhookSubscriptionRepository(mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+	})
+}
+
+// TestWebhookSubscriptionCreate_Ok checks that Create returns the ID of the inserted subscription
+func TestWebhookSubscriptionCreate_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := webhookSubscriptionRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		ID, err := repo.Create(context.Background(), entities.WebhookSubscription{URL: "https://test.com/hook"})
+
+		// Assert
+		assert.Nil(t, err)
+		assert.NotEmpty(t, ID)
+	})
+}
+
+// TestWebhookSubscriptionGetByID_Ok checks that GetByID returns the subscription found
+func TestWebhookSubscriptionGetByID_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := webhookSubscriptionRepository{
+			collection: mt.Coll,
+		}
+
+		ID := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.webhook_subscriptions", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: ID},
+			{Key: "url", Value: "https://test.com/hook"},
+			{Key: "event_types", Value: bson.A{"user.created"}},
+		}))
+
+		// Act
+		subscription, err := repo.GetByID(context.Background(), ID.Hex())
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Equal(t, ID.Hex(), subscription.ID)
+		assert.Equal(t, []string{"user.created"}, subscription.EventTypes)
+	})
+}
+
+// TestWebhookSubscriptionGetByID_NotFound checks that GetByID returns a non existent error when no subscription is found
+func TestWebhookSubscriptionGetByID_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := webhookSubscriptionRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.webhook_subscriptions", mtest.FirstBatch))
+
+		// Act
+		_, err := repo.GetByID(context.Background(), primitive.NewObjectID().Hex())
+
+		// Assert
+		assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	})
+}
+
+// TestWebhookSubscriptionDelete_NotFound checks that Delete returns a non existent error when no subscription is deleted
+func TestWebhookSubscriptionDelete_NotFound(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := webhookSubscriptionRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}})
+
+		// Act
+		err := repo.Delete(context.Background(), primitive.NewObjectID().Hex())
+
+		// Assert
+		assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	})
+}
+
+// TestNewWebhookDeliveryRepository_Ok checks that NewWebhookDeliveryRepository creates a new webhookDeliveryRepository struct
+func TestNewWebhookDeliveryRepository_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Act
+		repo := NewWebhookDeliveryRepository(mt.DB)
+
+		// Assert
+		assert.NotEmpty(t, repo)
+	})
+}
+
+// TestWebhookDeliveryCreateMany_Ok checks that CreateMany does not return an error when everything goes as expected
+func TestWebhookDeliveryCreateMany_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := webhookDeliveryRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		// Act
+		err := repo.CreateMany(context.Background(), []entities.WebhookDelivery{{SubscriptionID: "1"}, {SubscriptionID: "2"}})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestWebhookDeliveryClaim_Ok checks that Claim returns the deliveries claimed until none is due
+func TestWebhookDeliveryClaim_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := webhookDeliveryRepository{
+			collection: mt.Coll,
+		}
+
+		ID := primitive.NewObjectID()
+		leaseUntil := time.Now().Add(time.Minute).UTC().Truncate(time.Millisecond)
+		mt.AddMockResponses(
+			bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: bson.D{
+				{Key: "_id", Value: ID},
+				{Key: "subscription_id", Value: "test-subscription"},
+				{Key: "status", Value: "pending"},
+				{Key: "attempts", Value: 2},
+				{Key: "next_attempt_at", Value: leaseUntil},
+			}}},
+			bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}},
+		)
+
+		// Act
+		deliveries, err := repo.Claim(context.Background(), time.Now(), leaseUntil, 10)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, ID.Hex(), deliveries[0].ID)
+		assert.Equal(t, entities.WebhookDeliveryStatusPending, deliveries[0].Status)
+		assert.Equal(t, 2, deliveries[0].Attempts)
+		assert.True(t, leaseUntil.Equal(deliveries[0].NextAttemptAt))
+	})
+}
+
+// TestWebhookDeliveryClaim_Limit checks that Claim stops claiming once it reaches the limit
+func TestWebhookDeliveryClaim_Limit(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := webhookDeliveryRepository{
+			collection: mt.Coll,
+		}
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "status", Value: "pending"},
+		}}})
+
+		// Act
+		deliveries, err := repo.Claim(context.Background(), time.Now(), time.Now(), 1)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.Len(t, deliveries, 1)
+	})
+}
+
+// TestWebhookDeliveryUpdate_Ok checks that Update does not return an error when everything goes as expected
+func TestWebhookDeliveryUpdate_Ok(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := webhookDeliveryRepository{
+			collection: mt.Coll,
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		// Act
+		err := repo.Update(context.Background(), entities.WebhookDelivery{ID: primitive.NewObjectID().Hex()})
+
+		// Assert
+		assert.Nil(t, err)
+	})
+}
+
+// TestWebhookDeliveryUpdate_InvalidID checks that Update returns an error when the ID is not a valid ObjectID
+func TestWebhookDeliveryUpdate_InvalidID(t *testing.T) {
+	mt := mocks.NewMongoDB(t)
+
+	mt.Run("", func(mt *mtest.T) {
+		// Arrange
+		repo := webhookDeliveryRepository{
+			collection: mt.Coll,
+		}
+
+		// Act
+		err := repo.Update(context.Background(), entities.WebhookDelivery{ID: "invalid-id"})
+
+		// Assert
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,40 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+// logNotifier adapter of an invitation and an email change notifier that writes the tokens to the logger
+type logNotifier struct{}
+
+// NewLogNotifier creates an invitation notifier that writes the invite tokens to the logger.
+// Anyone with access to the logs can accept the invitations, so it is only meant for development.
+func NewLogNotifier() ports.InvitationNotifier {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) Notify(_ context.Context, invitation entities.Invitation, token string) error {
+	observability.Logger().Printf("invitation %s of email %s sent, expiring at %s: %s", invitation.ID, invitation.Email, invitation.ExpiresAt.Format(time.RFC3339), token)
+	return nil
+}
+
+// NewEmailChangeLogNotifier creates an email change notifier that writes the confirmation tokens to the logger.
+// Anyone with access to the logs can confirm the email changes, so it is only meant for development.
+func NewEmailChangeLogNotifier() ports.EmailChangeNotifier {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) NotifyConfirmation(_ context.Context, change entities.EmailChange, token string) error {
+	observability.Logger().Printf("email change %s of user %s to email %s sent, expiring at %s: %s", change.ID, change.UserID, change.Email, change.ExpiresAt.Format(time.RFC3339), token)
+	return nil
+}
+
+func (n *logNotifier) NotifyRequest(_ context.Context, change entities.EmailChange) error {
+	observability.Logger().Printf("email change %s of user %s requested, warning email %s", change.ID, change.UserID, change.PreviousEmail)
+	return nil
+}
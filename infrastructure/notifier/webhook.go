@@ -0,0 +1,124 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// webhookNotifier adapter of an invitation and an email change notifier that posts the notifications to an HTTP endpoint
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates an invitation notifier that posts every invite token as JSON to the given URL,
+// typically a mailing service that sends it to the invited email.
+// Any response other than 2xx is considered a failure.
+func NewWebhookNotifier(url string, client *http.Client) ports.InvitationNotifier {
+	return &webhookNotifier{
+		url:    url,
+		client: client,
+	}
+}
+
+// message body of the invite notifications
+type message struct {
+	InvitationID string    `json:"invitation_id"`
+	Email        string    `json:"email"`
+	Token        string    `json:"token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, invitation entities.Invitation, token string) error {
+	return n.post(ctx, message{
+		InvitationID: invitation.ID,
+		Email:        invitation.Email,
+		Token:        token,
+		ExpiresAt:    invitation.ExpiresAt,
+	})
+}
+
+// NewEmailChangeWebhookNotifier creates an email change notifier that posts every notification as JSON to the given URL,
+// typically a mailing service that sends it to the email of the notification.
+// Any response other than 2xx is considered a failure.
+func NewEmailChangeWebhookNotifier(url string, client *http.Client) ports.EmailChangeNotifier {
+	return &webhookNotifier{
+		url:    url,
+		client: client,
+	}
+}
+
+const (
+	// emailChangeConfirmation type of the notifications sent to the new email, carrying the confirmation token
+	emailChangeConfirmation = "email_change.confirmation"
+	// emailChangeRequest type of the notifications sent to the previous email, warning about the requested change
+	emailChangeRequest = "email_change.request"
+)
+
+// emailChangeMessage body of the email change notifications
+type emailChangeMessage struct {
+	Type          string    `json:"type"`
+	EmailChangeID string    `json:"email_change_id"`
+	UserID        string    `json:"user_id"`
+	Email         string    `json:"email"`
+	NewEmail      string    `json:"new_email"`
+	Token         string    `json:"token,omitempty"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+func (n *webhookNotifier) NotifyConfirmation(ctx context.Context, change entities.EmailChange, token string) error {
+	return n.post(ctx, emailChangeMessage{
+		Type:          emailChangeConfirmation,
+		EmailChangeID: change.ID,
+		UserID:        change.UserID,
+		Email:         change.Email,
+		NewEmail:      change.Email,
+		Token:         token,
+		ExpiresAt:     change.ExpiresAt,
+	})
+}
+
+func (n *webhookNotifier) NotifyRequest(ctx context.Context, change entities.EmailChange) error {
+	return n.post(ctx, emailChangeMessage{
+		Type:          emailChangeRequest,
+		EmailChangeID: change.ID,
+		UserID:        change.UserID,
+		Email:         change.PreviousEmail,
+		NewEmail:      change.Email,
+		ExpiresAt:     change.ExpiresAt,
+	})
+}
+
+// post sends the given message as JSON to the webhook
+func (n *webhookNotifier) post(ctx context.Context, msg interface{}) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook %s responded with status %d", n.url, resp.StatusCode)
+	}
+	return nil
+}
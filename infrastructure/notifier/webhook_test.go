@@ -0,0 +1,116 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWebhookNotify_Ok checks that Notify posts the invite token as JSON to the webhook
+func TestWebhookNotify_Ok(t *testing.T) {
+	// Arrange
+	invitation := entities.Invitation{
+		ID:    "test-id",
+		Email: "test@test.com",
+	}
+
+	var received message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, server.Client())
+
+	// Act
+	err := notifier.Notify(context.Background(), invitation, "test-token")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, invitation.ID, received.InvitationID)
+	assert.Equal(t, invitation.Email, received.Email)
+	assert.Equal(t, "test-token", received.Token)
+}
+
+// TestWebhookNotify_StatusError checks that Notify returns an error when the webhook does not respond with a 2xx status
+func TestWebhookNotify_StatusError(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, server.Client())
+
+	// Act
+	err := notifier.Notify(context.Background(), entities.Invitation{}, "test-token")
+
+	// Assert
+	assert.NotNil(t, err)
+}
+
+// TestWebhookNotifyConfirmation_Ok checks that NotifyConfirmation posts the confirmation token addressed to the new email
+func TestWebhookNotifyConfirmation_Ok(t *testing.T) {
+	// Arrange
+	change := entities.EmailChange{
+		ID:            "test-id",
+		UserID:        "test-user-id",
+		PreviousEmail: "old@test.com",
+		Email:         "new@test.com",
+	}
+
+	var received emailChangeMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewEmailChangeWebhookNotifier(server.URL, server.Client())
+
+	// Act
+	err := notifier.NotifyConfirmation(context.Background(), change, "test-token")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, emailChangeConfirmation, received.Type)
+	assert.Equal(t, change.ID, received.EmailChangeID)
+	assert.Equal(t, change.Email, received.Email)
+	assert.Equal(t, "test-token", received.Token)
+}
+
+// TestWebhookNotifyRequest_Ok checks that NotifyRequest posts a warning without token addressed to the previous email
+func TestWebhookNotifyRequest_Ok(t *testing.T) {
+	// Arrange
+	change := entities.EmailChange{
+		ID:            "test-id",
+		UserID:        "test-user-id",
+		PreviousEmail: "old@test.com",
+		Email:         "new@test.com",
+	}
+
+	var received emailChangeMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewEmailChangeWebhookNotifier(server.URL, server.Client())
+
+	// Act
+	err := notifier.NotifyRequest(context.Background(), change)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, emailChangeRequest, received.Type)
+	assert.Equal(t, change.PreviousEmail, received.Email)
+	assert.Equal(t, change.Email, received.NewEmail)
+	assert.Empty(t, received.Token)
+}
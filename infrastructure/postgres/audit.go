@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+)
+
+// auditRepository adapter of an audit repository for postgres
+type auditRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewAuditRepository creates an audit repository for postgres
+func NewAuditRepository(db *sql.DB) ports.AuditRepository {
+	return &auditRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *auditRepository) Create(ctx context.Context, event entities.AuditEvent) (string, error) {
+	q := `
+	INSERT INTO audit_events (actor_id, action, target_id, before, after, request_id, timestamp)
+	    VALUES ($1, $2, $3, $4, $5, $6, $7)
+	    RETURNING id;
+	`
+
+	before, err := json.Marshal(event.Before)
+	if err != nil {
+		return "", err
+	}
+	after, err := json.Marshal(event.After)
+	if err != nil {
+		return "", err
+	}
+
+	row := conn(ctx, r.DB).QueryRowContext(ctx, q, event.ActorID, event.Action, event.TargetID, before, after, event.RequestID, event.Timestamp)
+
+	err = row.Scan(&event.ID)
+	if err != nil {
+		return "", err
+	}
+
+	return event.ID, nil
+}
+
+func (r *auditRepository) List(ctx context.Context, filter models.AuditFilter, offset, limit int) ([]entities.AuditEvent, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.ActorID != "" {
+		args = append(args, filter.ActorID)
+		conditions = append(conditions, fmt.Sprintf("actor_id = $%d", len(args)))
+	}
+	if filter.Action != "" {
+		args = append(args, string(filter.Action))
+		conditions = append(conditions, fmt.Sprintf("action = $%d", len(args)))
+	}
+	if filter.TargetID != "" {
+		args = append(args, filter.TargetID)
+		conditions = append(conditions, fmt.Sprintf("target_id = $%d", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conditions = append(conditions, fmt.Sprintf("timestamp >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conditions = append(conditions, fmt.Sprintf("timestamp < $%d", len(args)))
+	}
+
+	var where string
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, offset)
+	pagination := fmt.Sprintf("OFFSET $%d", len(args))
+	if limit > 0 {
+		args = append(args, limit)
+		pagination = fmt.Sprintf("%s LIMIT $%d", pagination, len(args))
+	}
+
+	q := fmt.Sprintf(`
+	SELECT id, actor_id, action, target_id, before, after, request_id, timestamp
+	    FROM audit_events %s ORDER BY timestamp DESC, id DESC %s;
+	`, where, pagination)
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	events := []entities.AuditEvent{}
+	for rows.Next() {
+		var event entities.AuditEvent
+		var before, after []byte
+		err = rows.Scan(&event.ID, &event.ActorID, &event.Action, &event.TargetID, &before, &after, &event.RequestID, &event.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		if err = unmarshalAuditValues(before, &event.Before); err != nil {
+			return nil, err
+		}
+		if err = unmarshalAuditValues(after, &event.After); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *auditRepository) RedactTarget(ctx context.Context, targetID string) error {
+	q := `
+	UPDATE audit_events SET before = NULL, after = NULL
+	    WHERE target_id = $1;
+	`
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, q, targetID)
+	return err
+}
+
+// unmarshalAuditValues decodes a nullable jsonb column
+func unmarshalAuditValues(data []byte, values *map[string]interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, values)
+}
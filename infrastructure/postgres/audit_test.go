@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewAuditRepository_Ok checks that NewAuditRepository creates a new auditRepository struct
+func TestNewAuditRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewAuditRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestAuditCreate_Ok checks that Create returns the expected response when a valid event is received
+func TestAuditCreate_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &auditRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedID := "new-id"
+	event := entities.AuditEvent{
+		ActorID:   "test-actor",
+		Action:    "create",
+		TargetID:  "test-target",
+		After:     map[string]interface{}{"email": "test@test.com"},
+		RequestID: "test-request",
+		Timestamp: time.Now(),
+	}
+	mock.ExpectQuery("INSERT INTO audit_events").
+		WithArgs(event.ActorID, event.Action, event.TargetID, []byte("null"), []byte(`{"email":"test@test.com"}`), event.RequestID, event.Timestamp).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
+
+	// Act
+	id, err := repo.Create(context.Background(), event)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedID, id)
+}
+
+// TestAuditCreate_InsertError checks that Create returns an error when the insert fails
+func TestAuditCreate_InsertError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &auditRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "insert error"
+	mock.ExpectQuery("INSERT INTO audit_events").WillReturnError(errors.New(expectedError))
+
+	// Act
+	id, err := repo.Create(context.Background(), entities.AuditEvent{})
+
+	// Assert
+	assert.Empty(t, id)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestAuditList_Ok checks that List applies the filter and pagination as query arguments and decodes the values of the events
+func TestAuditList_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &auditRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	from := time.Now().Add(-time.Hour)
+	filter := models.AuditFilter{ActorID: "test-actor", Action: models.AuditActionUpdate, From: from}
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM audit_events WHERE actor_id = \$1 AND action = \$2 AND timestamp >= \$3 ORDER BY timestamp DESC, id DESC OFFSET \$4 LIMIT \$5`).
+		WithArgs("test-actor", "update", from, 10, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "actor_id", "action", "target_id", "before", "after", "request_id", "timestamp"}).
+			AddRow("test-id", "test-actor", "update", "test-target", []byte(`{"name":"old"}`), []byte(`{"name":"new"}`), "test-request", now).
+			AddRow("test-id-2", "test-actor", "update", "test-target", nil, nil, "test-request", now))
+
+	// Act
+	events, err := repo.List(context.Background(), filter, 10, 5)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "old", events[0].Before["name"])
+	assert.Equal(t, "new", events[0].After["name"])
+	assert.Nil(t, events[1].Before)
+	assert.Nil(t, events[1].After)
+}
+
+// TestAuditList_QueryError checks that List returns an error when the query fails
+func TestAuditList_QueryError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &auditRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "query error"
+	mock.ExpectQuery("SELECT (.+) FROM audit_events").WillReturnError(errors.New(expectedError))
+
+	// Act
+	events, err := repo.List(context.Background(), models.AuditFilter{}, 0, 0)
+
+	// Assert
+	assert.Nil(t, events)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestAuditRedactTarget_Ok checks that RedactTarget clears the values of the events targeting the given ID
+func TestAuditRedactTarget_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &auditRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("UPDATE audit_events SET before = NULL, after = NULL").WithArgs("test-target").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	// Act
+	err := repo.RedactTarget(context.Background(), "test-target")
+
+	// Assert
+	assert.Nil(t, err)
+}
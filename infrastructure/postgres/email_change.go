@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// emailChangeRepository adapter of an email change repository for postgres
+type emailChangeRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewEmailChangeRepository creates an email change repository for postgres
+func NewEmailChangeRepository(db *sql.DB) ports.EmailChangeRepository {
+	return &emailChangeRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *emailChangeRepository) Create(ctx context.Context, change entities.EmailChange) (string, error) {
+	q := `
+	INSERT INTO email_changes (user_id, previous_email, email, nonce, expires_at, created_at)
+	    VALUES ($1, $2, $3, $4, $5, $6)
+	    RETURNING id;
+	`
+
+	row := conn(ctx, r.DB).QueryRowContext(
+		ctx, q, change.UserID, change.PreviousEmail, change.Email, change.Nonce, change.ExpiresAt, change.CreatedAt,
+	)
+
+	err := row.Scan(&change.ID)
+	if err != nil {
+		return "", notFoundIfNoUser(err, change.UserID)
+	}
+
+	return change.ID, nil
+}
+
+func (r *emailChangeRepository) GetByID(ctx context.Context, ID string) (entities.EmailChange, error) {
+	q := `
+	SELECT id, user_id, previous_email, email, nonce, expires_at, created_at
+	    FROM email_changes WHERE id = $1;
+	`
+
+	row := conn(ctx, r.DB).QueryRowContext(ctx, q, ID)
+
+	var c entities.EmailChange
+	err := row.Scan(&c.ID, &c.UserID, &c.PreviousEmail, &c.Email, &c.Nonce, &c.ExpiresAt, &c.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return entities.EmailChange{}, err
+	}
+
+	return c, nil
+}
+
+func (r *emailChangeRepository) GetByUserID(ctx context.Context, userID string) ([]entities.EmailChange, error) {
+	q := `
+	SELECT id, user_id, previous_email, email, nonce, expires_at, created_at
+	    FROM email_changes WHERE user_id = $1 ORDER BY created_at;
+	`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	changes := []entities.EmailChange{}
+	for rows.Next() {
+		var c entities.EmailChange
+		err = rows.Scan(&c.ID, &c.UserID, &c.PreviousEmail, &c.Email, &c.Nonce, &c.ExpiresAt, &c.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+
+	return changes, rows.Err()
+}
+
+func (r *emailChangeRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM email_changes WHERE id=$1;`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(result)
+}
+
+func (r *emailChangeRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	q := `DELETE FROM email_changes WHERE user_id=$1;`
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, q, userID)
+	return err
+}
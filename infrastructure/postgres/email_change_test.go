@@ -0,0 +1,172 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewEmailChangeRepository_Ok checks that NewEmailChangeRepository creates a new emailChangeRepository struct
+func TestNewEmailChangeRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewEmailChangeRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestEmailChangeCreate_Ok checks that Create returns the ID of the inserted email change
+func TestEmailChangeCreate_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &emailChangeRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	change := entities.EmailChange{
+		UserID:        "test-user-id",
+		PreviousEmail: "old@test.com",
+		Email:         "new@test.com",
+		Nonce:         "test-nonce",
+		ExpiresAt:     time.Now(),
+	}
+	mock.ExpectQuery("INSERT INTO email_changes").
+		WithArgs(change.UserID, change.PreviousEmail, change.Email, change.Nonce, change.ExpiresAt, change.CreatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("test-id"))
+
+	// Act
+	ID, err := repo.Create(context.Background(), change)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", ID)
+}
+
+// TestEmailChangeCreate_NoUser checks that Create returns a non existent error when the user of the email change does not exist
+func TestEmailChangeCreate_NoUser(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &emailChangeRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("INSERT INTO email_changes").WillReturnError(&pq.Error{Code: "23503", Constraint: "email_changes_user_id_fkey"})
+
+	// Act
+	_, err := repo.Create(context.Background(), entities.EmailChange{UserID: "test-user-id"})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	assert.Equal(t, "ID test-user-id not found", err.Error())
+}
+
+// TestEmailChangeGetByID_Ok checks that GetByID returns the email change found
+func TestEmailChangeGetByID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &emailChangeRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM email_changes WHERE id").WithArgs("test-id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "previous_email", "email", "nonce", "expires_at", "created_at"}).
+			AddRow("test-id", "test-user-id", "old@test.com", "new@test.com", "test-nonce", now, now))
+
+	// Act
+	change, err := repo.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-user-id", change.UserID)
+	assert.Equal(t, "new@test.com", change.Email)
+}
+
+// TestEmailChangeGetByID_NotFound checks that GetByID returns a non existent error when no email change is found
+func TestEmailChangeGetByID_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &emailChangeRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM email_changes WHERE id").WithArgs("test-id").WillReturnError(sql.ErrNoRows)
+
+	// Act
+	_, err := repo.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestEmailChangeDelete_NotFound checks that Delete returns a non existent error when no rows are affected
+func TestEmailChangeDelete_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &emailChangeRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM email_changes WHERE id").WithArgs("test-id").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Delete(context.Background(), "test-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestEmailChangeDeleteByUserID_Ok checks that DeleteByUserID does not return an error when the user has no pending changes
+func TestEmailChangeDeleteByUserID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &emailChangeRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM email_changes WHERE user_id").WithArgs("test-user-id").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.DeleteByUserID(context.Background(), "test-user-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
@@ -0,0 +1,214 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewIdempotencyRepository_Ok checks that NewIdempotencyRepository creates a new idempotencyRepository struct
+func TestNewIdempotencyRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewIdempotencyRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestIdempotencyGet_Ok checks that Get returns the expected record when the key exists
+func TestIdempotencyGet_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &idempotencyRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now().UTC()
+	expectedRecord := entities.IdempotencyRecord{
+		Key:         "test-key",
+		RequestHash: "test-hash",
+		Response:    []byte("test-response"),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Hour),
+	}
+	mock.ExpectQuery("SELECT (.+) FROM idempotency_keys").WillReturnRows(sqlmock.NewRows([]string{"key", "request_hash", "response", "created_at", "expires_at"}).
+		AddRow(expectedRecord.Key, expectedRecord.RequestHash, expectedRecord.Response, expectedRecord.CreatedAt, expectedRecord.ExpiresAt))
+
+	// Act
+	record, err := repo.Get(context.Background(), expectedRecord.Key)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedRecord, record)
+}
+
+// TestIdempotencyGet_ResourceNotFound checks that Get returns an error when the key does not exist or has expired
+func TestIdempotencyGet_ResourceNotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &idempotencyRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM idempotency_keys").WillReturnError(sql.ErrNoRows)
+
+	// Act
+	_, err := repo.Get(context.Background(), "test-key")
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
+}
+
+// TestIdempotencyReserve_Ok checks that Reserve deletes a batch of expired records and does not return an error when the upsert inserts the record
+func TestIdempotencyReserve_Ok(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &idempotencyRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`DELETE FROM idempotency_keys WHERE key IN \( SELECT key FROM idempotency_keys WHERE expires_at <= \$1 LIMIT \$2 \)`).WithArgs(now, idempotencyPruneBatchSize).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("INSERT INTO idempotency_keys").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.Reserve(context.Background(), entities.IdempotencyRecord{Key: "test-key", CreatedAt: now})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestIdempotencyReserve_Reserved checks that Reserve returns an AlreadyExistsErr when the upsert does not replace a record that has not expired
+func TestIdempotencyReserve_Reserved(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &idempotencyRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`DELETE FROM idempotency_keys WHERE key IN \( SELECT key FROM idempotency_keys WHERE expires_at <= \$1 LIMIT \$2 \)`).WithArgs(now, idempotencyPruneBatchSize).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("INSERT INTO idempotency_keys").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Reserve(context.Background(), entities.IdempotencyRecord{Key: "test-key", CreatedAt: now})
+
+	// Assert
+	assert.ErrorIs(t, err, entities.AlreadyExistsErr)
+}
+
+// TestIdempotencyReserve_InsertError checks that Reserve returns an error when the upsert fails
+func TestIdempotencyReserve_InsertError(t *testing.T) {
+	// Arrange
+	now := time.Now().UTC()
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &idempotencyRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "insert error"
+	mock.ExpectExec(`DELETE FROM idempotency_keys WHERE key IN \( SELECT key FROM idempotency_keys WHERE expires_at <= \$1 LIMIT \$2 \)`).WithArgs(now, idempotencyPruneBatchSize).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("INSERT INTO idempotency_keys").WillReturnError(errors.New(expectedError))
+
+	// Act
+	err := repo.Reserve(context.Background(), entities.IdempotencyRecord{Key: "test-key", CreatedAt: now})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestIdempotencyComplete_Ok checks that Complete does not return an error when the update succeeds
+func TestIdempotencyComplete_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &idempotencyRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`UPDATE idempotency_keys .* WHERE key = \$1 AND token = \$2 AND response IS NULL`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.Complete(context.Background(), "test-key", "test-token", []byte("test-response"), time.Now())
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestIdempotencyComplete_NotReserved checks that Complete returns a ConflictErr when the key is no longer reserved with the token
+func TestIdempotencyComplete_NotReserved(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &idempotencyRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("UPDATE idempotency_keys").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Complete(context.Background(), "test-key", "test-token", []byte("test-response"), time.Now())
+
+	// Assert
+	assert.ErrorIs(t, err, entities.ConflictErr)
+}
+
+// TestIdempotencyRelease_Ok checks that Release does not return an error when the delete succeeds
+func TestIdempotencyRelease_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &idempotencyRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM idempotency_keys WHERE key = \\$1 AND token = \\$2").WithArgs("test-key", "test-token").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.Release(context.Background(), "test-key", "test-token")
+
+	// Assert
+	assert.Nil(t, err)
+}
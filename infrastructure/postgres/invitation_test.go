@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewInvitationRepository_Ok checks that NewInvitationRepository creates a new invitationRepository struct
+func TestNewInvitationRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewInvitationRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestInvitationCreate_Ok checks that Create returns the ID of the inserted invitation
+func TestInvitationCreate_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &invitationRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	invitation := entities.Invitation{
+		UserID:    "test-user-id",
+		Email:     "test@test.com",
+		Nonce:     "test-nonce",
+		ExpiresAt: time.Now(),
+	}
+	mock.ExpectQuery("INSERT INTO invitations").
+		WithArgs(invitation.UserID, invitation.Email, invitation.Nonce, invitation.ExpiresAt, invitation.CreatedAt, invitation.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("test-id"))
+
+	// Act
+	ID, err := repo.Create(context.Background(), invitation)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", ID)
+}
+
+// TestInvitationCreate_NoUser checks that Create returns a non existent error when the user of the invitation does not exist
+func TestInvitationCreate_NoUser(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &invitationRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("INSERT INTO invitations").WillReturnError(&pq.Error{Code: "23503", Constraint: "invitations_user_id_fkey"})
+
+	// Act
+	_, err := repo.Create(context.Background(), entities.Invitation{UserID: "test-user-id"})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	assert.Equal(t, "ID test-user-id not found", err.Error())
+}
+
+// TestInvitationGetAll_Ok checks that GetAll returns the invitations found
+func TestInvitationGetAll_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &invitationRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM invitations ORDER BY created_at").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "email", "nonce", "expires_at", "created_at", "updated_at"}).
+			AddRow("test-id", "test-user-id", "test@test.com", "test-nonce", now, now, now))
+
+	// Act
+	invitations, err := repo.GetAll(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, invitations, 1)
+	assert.Equal(t, "test-user-id", invitations[0].UserID)
+	assert.Equal(t, "test-nonce", invitations[0].Nonce)
+}
+
+// TestInvitationGetByID_NotFound checks that GetByID returns a non existent error when no invitation is found
+func TestInvitationGetByID_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &invitationRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM invitations WHERE id").WithArgs("test-id").WillReturnError(sql.ErrNoRows)
+
+	// Act
+	_, err := repo.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestInvitationUpdate_Ok checks that Update renews the nonce and the expiration of the invitation
+func TestInvitationUpdate_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &invitationRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	invitation := entities.Invitation{
+		Nonce:     "test-nonce",
+		ExpiresAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	mock.ExpectExec("UPDATE invitations").
+		WithArgs(invitation.Nonce, invitation.ExpiresAt, invitation.UpdatedAt, "test-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.Update(context.Background(), "test-id", invitation)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestInvitationDelete_NotFound checks that Delete returns a non existent error when no rows are affected
+func TestInvitationDelete_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &invitationRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM invitations").WithArgs("test-id").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Delete(context.Background(), "test-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
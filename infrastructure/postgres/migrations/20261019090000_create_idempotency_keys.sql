@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE public.idempotency_keys (
+    key varchar,
+    request_hash varchar NOT NULL,
+    response bytea,
+    created_at timestamp,
+    expires_at timestamp,
+    PRIMARY KEY(key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON public.idempotency_keys (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE public.idempotency_keys;
+-- +goose StatementEnd
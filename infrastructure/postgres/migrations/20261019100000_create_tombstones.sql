@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE public.tombstones (
+    id uuid DEFAULT uuid_generate_v4 (),
+    user_id uuid NOT NULL,
+    erased_by varchar,
+    stores varchar[],
+    erased_at timestamp,
+    PRIMARY KEY(id)
+);
+
+CREATE INDEX tombstones_user_id_idx ON public.tombstones (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE public.tombstones;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE public.audit_events (
+    id uuid DEFAULT uuid_generate_v4 (),
+    actor_id varchar,
+    action varchar NOT NULL,
+    target_id varchar,
+    before jsonb,
+    after jsonb,
+    request_id varchar,
+    timestamp timestamp NOT NULL,
+    PRIMARY KEY(id)
+);
+
+CREATE INDEX audit_events_timestamp_idx ON public.audit_events (timestamp DESC);
+CREATE INDEX audit_events_actor_id_idx ON public.audit_events (actor_id);
+CREATE INDEX audit_events_target_id_idx ON public.audit_events (target_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE public.audit_events;
+-- +goose StatementEnd
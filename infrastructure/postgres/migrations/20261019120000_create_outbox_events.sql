@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE public.outbox_events (
+    id uuid DEFAULT uuid_generate_v4 (),
+    seq bigserial,
+    type varchar NOT NULL,
+    aggregate_id varchar NOT NULL,
+    payload jsonb,
+    occurred_at timestamp NOT NULL,
+    published_at timestamp,
+    attempts int NOT NULL DEFAULT 0,
+    last_error varchar NOT NULL DEFAULT '',
+    PRIMARY KEY(id)
+);
+
+CREATE INDEX outbox_events_pending_idx ON public.outbox_events (occurred_at, seq) WHERE published_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE public.outbox_events;
+-- +goose StatementEnd
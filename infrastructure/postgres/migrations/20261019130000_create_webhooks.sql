@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE public.webhook_subscriptions (
+    id uuid DEFAULT uuid_generate_v4 (),
+    url varchar NOT NULL,
+    event_types varchar[],
+    secret varchar NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY(id)
+);
+
+CREATE TABLE public.webhook_deliveries (
+    id uuid DEFAULT uuid_generate_v4 (),
+    subscription_id varchar NOT NULL,
+    event_id varchar NOT NULL,
+    event_type varchar NOT NULL,
+    body bytea NOT NULL,
+    status varchar NOT NULL,
+    attempts int NOT NULL DEFAULT 0,
+    next_attempt_at timestamp NOT NULL,
+    last_error varchar NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL,
+    delivered_at timestamp,
+    PRIMARY KEY(id)
+);
+
+CREATE INDEX webhook_deliveries_status_idx ON public.webhook_deliveries (status, next_attempt_at);
+CREATE INDEX webhook_deliveries_subscription_id_idx ON public.webhook_deliveries (subscription_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE public.webhook_deliveries;
+DROP TABLE public.webhook_subscriptions;
+-- +goose StatementEnd
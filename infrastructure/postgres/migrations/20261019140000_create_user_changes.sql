@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE public.user_changes (
+    seq bigserial,
+    type varchar NOT NULL,
+    user_id uuid NOT NULL,
+    occurred_at timestamp NOT NULL DEFAULT (now() at time zone 'utc'),
+    PRIMARY KEY(seq)
+);
+
+CREATE FUNCTION public.notify_user_change() RETURNS trigger AS $$
+DECLARE
+    change_seq bigint;
+BEGIN
+    -- serializes the transactions changing users until they commit, so that changes become visible in sequence order
+    -- and watchers resuming after a sequence number never skip a change committed later
+    PERFORM pg_advisory_xact_lock(hashtext('user_changes'));
+
+    INSERT INTO public.user_changes (type, user_id)
+        VALUES (
+            CASE TG_OP WHEN 'INSERT' THEN 'created' WHEN 'UPDATE' THEN 'updated' ELSE 'deleted' END,
+            CASE TG_OP WHEN 'DELETE' THEN OLD.id ELSE NEW.id END
+        )
+        RETURNING seq INTO change_seq;
+
+    PERFORM pg_notify('user_changes', change_seq::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_notify_change
+    AFTER INSERT OR UPDATE OR DELETE ON public.users
+    FOR EACH ROW EXECUTE FUNCTION public.notify_user_change();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER users_notify_change ON public.users;
+DROP FUNCTION public.notify_user_change();
+DROP TABLE public.user_changes;
+-- +goose StatementEnd
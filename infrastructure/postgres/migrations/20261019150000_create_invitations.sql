@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE public.invitations (
+    id uuid DEFAULT uuid_generate_v4 (),
+    user_id varchar NOT NULL,
+    email varchar NOT NULL,
+    nonce varchar NOT NULL,
+    expires_at timestamp NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY(id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE public.invitations;
+-- +goose StatementEnd
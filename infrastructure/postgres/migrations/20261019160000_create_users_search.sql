@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent is only stable as it depends on the search path, so it is called with an explicit dictionary
+-- in an immutable function that can be used in indexes
+CREATE FUNCTION public.search_normalize(value text) RETURNS text AS $$
+    SELECT lower(public.unaccent('public.unaccent'::regdictionary, value));
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+CREATE FUNCTION public.users_search_document(name varchar, surnames varchar, email varchar) RETURNS text AS $$
+    SELECT public.search_normalize(coalesce(name, '') || ' ' || coalesce(surnames, '') || ' ' || coalesce(email, ''));
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+CREATE INDEX users_search_tsv_idx ON public.users
+    USING gin (to_tsvector('simple', public.users_search_document(name, surnames, email)));
+CREATE INDEX users_search_trgm_idx ON public.users
+    USING gin (public.users_search_document(name, surnames, email) gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX public.users_search_trgm_idx;
+DROP INDEX public.users_search_tsv_idx;
+DROP FUNCTION public.users_search_document;
+DROP FUNCTION public.search_normalize;
+DROP EXTENSION IF EXISTS pg_trgm;
+DROP EXTENSION IF EXISTS unaccent;
+-- +goose StatementEnd
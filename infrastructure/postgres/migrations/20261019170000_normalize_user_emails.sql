@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.users ADD COLUMN email_normalized varchar;
+
+UPDATE public.users SET email_normalized = lower(trim(email));
+
+-- the users sharing a normalized email are reported and the migration is aborted, as they must be fixed by hand
+DO $$
+DECLARE
+    collisions text;
+BEGIN
+    SELECT string_agg(format('email %s is shared by users %s', email_normalized, ids), ' | ')
+        INTO collisions
+        FROM (
+            SELECT email_normalized, string_agg(id::text, ', ' ORDER BY created_at) AS ids
+                FROM public.users
+                WHERE email_normalized IS NOT NULL
+                GROUP BY email_normalized
+                HAVING count(*) > 1
+        ) c;
+
+    IF collisions IS NOT NULL THEN
+        RAISE EXCEPTION 'users must have unique emails regardless of case: %', collisions;
+    END IF;
+END
+$$;
+
+ALTER TABLE ONLY public.users
+    DROP CONSTRAINT email_unique;
+ALTER TABLE ONLY public.users
+    ADD CONSTRAINT email_normalized_unique UNIQUE (email_normalized);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE ONLY public.users
+    DROP CONSTRAINT email_normalized_unique;
+ALTER TABLE ONLY public.users
+    ADD CONSTRAINT email_unique UNIQUE (email);
+ALTER TABLE public.users DROP COLUMN email_normalized;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE public.email_changes (
+    id uuid DEFAULT uuid_generate_v4 (),
+    user_id varchar NOT NULL,
+    previous_email varchar NOT NULL,
+    email varchar NOT NULL,
+    nonce varchar NOT NULL,
+    expires_at timestamp NOT NULL,
+    created_at timestamp NOT NULL,
+    PRIMARY KEY(id)
+);
+
+CREATE INDEX email_changes_user_id_idx ON public.email_changes (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE public.email_changes;
+-- +goose StatementEnd
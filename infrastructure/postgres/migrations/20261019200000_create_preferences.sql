@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE public.preferences (
+    user_id varchar NOT NULL,
+    version integer NOT NULL,
+    language varchar,
+    timezone varchar,
+    email_notifications boolean,
+    push_notifications boolean,
+    marketing_notifications boolean,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY(user_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE public.preferences;
+-- +goose StatementEnd
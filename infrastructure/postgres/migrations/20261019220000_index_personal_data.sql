@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.webhook_deliveries ADD COLUMN aggregate_id varchar NOT NULL DEFAULT '';
+UPDATE public.webhook_deliveries SET aggregate_id = COALESCE(convert_from(body, 'UTF8')::jsonb->>'aggregate_id', '');
+
+CREATE INDEX webhook_deliveries_aggregate_id_idx ON public.webhook_deliveries (aggregate_id);
+CREATE INDEX outbox_events_aggregate_id_idx ON public.outbox_events (aggregate_id);
+CREATE INDEX invitations_user_id_idx ON public.invitations (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX public.invitations_user_id_idx;
+DROP INDEX public.outbox_events_aggregate_id_idx;
+ALTER TABLE public.webhook_deliveries DROP COLUMN aggregate_id;
+-- +goose StatementEnd
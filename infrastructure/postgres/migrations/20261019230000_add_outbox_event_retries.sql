@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.outbox_events ADD COLUMN next_attempt_at timestamp NOT NULL DEFAULT (now() AT TIME ZONE 'utc');
+ALTER TABLE public.outbox_events ADD COLUMN dead_at timestamp;
+UPDATE public.outbox_events SET next_attempt_at = occurred_at;
+
+DROP INDEX public.outbox_events_pending_idx;
+CREATE INDEX outbox_events_pending_idx ON public.outbox_events (occurred_at, seq) WHERE published_at IS NULL AND dead_at IS NULL;
+CREATE INDEX outbox_events_published_at_idx ON public.outbox_events (published_at) WHERE published_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX public.outbox_events_published_at_idx;
+DROP INDEX public.outbox_events_pending_idx;
+CREATE INDEX outbox_events_pending_idx ON public.outbox_events (occurred_at, seq) WHERE published_at IS NULL;
+ALTER TABLE public.outbox_events DROP COLUMN dead_at;
+ALTER TABLE public.outbox_events DROP COLUMN next_attempt_at;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the single row holds the sequence number of the last pruned change, so that watching can only be resumed after it
+CREATE TABLE public.user_changes_pruned (
+    id boolean NOT NULL DEFAULT true CHECK (id),
+    seq bigint NOT NULL,
+    PRIMARY KEY(id)
+);
+INSERT INTO public.user_changes_pruned (seq) VALUES (0);
+
+CREATE INDEX user_changes_occurred_at_idx ON public.user_changes (occurred_at);
+
+COMMENT ON FUNCTION public.notify_user_change() IS
+    'Records the changes of the users in user_changes. The transaction-level advisory lock taken by every change is held until '
+    'the transaction ends, so the transactions changing users commit one at a time and the changes become visible in sequence order. '
+    'This bounds the throughput of the writes to the users, and a long transaction changing users delays every other one.';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+COMMENT ON FUNCTION public.notify_user_change() IS NULL;
+DROP INDEX public.user_changes_occurred_at_idx;
+DROP TABLE public.user_changes_pruned;
+-- +goose StatementEnd
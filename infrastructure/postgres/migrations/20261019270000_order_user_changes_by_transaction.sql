@@ -0,0 +1,102 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the changes follow the order of the transactions that made them, and are only read once no earlier transaction is in progress,
+-- so that they become visible in order without serializing the transactions changing users
+ALTER TABLE public.user_changes ADD COLUMN xid xid8 NOT NULL DEFAULT '0';
+ALTER TABLE public.user_changes ALTER COLUMN xid SET DEFAULT pg_current_xact_id();
+ALTER TABLE public.user_changes_pruned ADD COLUMN xid xid8 NOT NULL DEFAULT '0';
+
+-- the changes record the state of the user they leave but its password hash, which is cleared when the user is deleted or erased
+ALTER TABLE public.user_changes
+    ADD COLUMN name varchar,
+    ADD COLUMN surnames varchar,
+    ADD COLUMN email varchar,
+    ADD COLUMN email_normalized varchar,
+    ADD COLUMN claim_ids integer[],
+    ADD COLUMN avatar_url varchar,
+    ADD COLUMN created_at timestamp,
+    ADD COLUMN updated_at timestamp;
+
+UPDATE public.user_changes c
+    SET name = u.name, surnames = u.surnames, email = u.email, email_normalized = u.email_normalized,
+        claim_ids = u.claim_ids, avatar_url = u.avatar_url, created_at = u.created_at, updated_at = u.updated_at
+    FROM public.users u
+    WHERE u.id = c.user_id;
+
+CREATE INDEX user_changes_xid_seq_idx ON public.user_changes (xid, seq);
+CREATE INDEX user_changes_user_id_idx ON public.user_changes (user_id);
+
+CREATE OR REPLACE FUNCTION public.notify_user_change() RETURNS trigger AS $$
+DECLARE
+    change_seq bigint;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE public.user_changes
+            SET name = NULL, surnames = NULL, email = NULL, email_normalized = NULL,
+                claim_ids = NULL, avatar_url = NULL, created_at = NULL, updated_at = NULL
+            WHERE user_id = OLD.id;
+
+        INSERT INTO public.user_changes (type, user_id)
+            VALUES ('deleted', OLD.id)
+            RETURNING seq INTO change_seq;
+    ELSE
+        INSERT INTO public.user_changes (type, user_id, name, surnames, email, email_normalized, claim_ids, avatar_url, created_at, updated_at)
+            VALUES (
+                CASE TG_OP WHEN 'INSERT' THEN 'created' ELSE 'updated' END,
+                NEW.id, NEW.name, NEW.surnames, NEW.email, NEW.email_normalized, NEW.claim_ids, NEW.avatar_url, NEW.created_at, NEW.updated_at
+            )
+            RETURNING seq INTO change_seq;
+    END IF;
+
+    PERFORM pg_notify('user_changes', change_seq::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION public.notify_user_change() IS
+    'Records the changes of the users in user_changes, along with the state of the user after every change. '
+    'The changes are read in (xid, seq) order, and only those of the transactions older than the xmin of the current snapshot, '
+    'so a long transaction delays the changes made after it started, but does not block the transactions changing users.';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION public.notify_user_change() RETURNS trigger AS $$
+DECLARE
+    change_seq bigint;
+BEGIN
+    -- serializes the transactions changing users until they commit, so that changes become visible in sequence order
+    -- and watchers resuming after a sequence number never skip a change committed later
+    PERFORM pg_advisory_xact_lock(hashtext('user_changes'));
+
+    INSERT INTO public.user_changes (type, user_id)
+        VALUES (
+            CASE TG_OP WHEN 'INSERT' THEN 'created' WHEN 'UPDATE' THEN 'updated' ELSE 'deleted' END,
+            CASE TG_OP WHEN 'DELETE' THEN OLD.id ELSE NEW.id END
+        )
+        RETURNING seq INTO change_seq;
+
+    PERFORM pg_notify('user_changes', change_seq::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON FUNCTION public.notify_user_change() IS
+    'Records the changes of the users in user_changes. The transaction-level advisory lock taken by every change is held until '
+    'the transaction ends, so the transactions changing users commit one at a time and the changes become visible in sequence order. '
+    'This bounds the throughput of the writes to the users, and a long transaction changing users delays every other one.';
+
+DROP INDEX public.user_changes_user_id_idx;
+DROP INDEX public.user_changes_xid_seq_idx;
+ALTER TABLE public.user_changes
+    DROP COLUMN updated_at,
+    DROP COLUMN created_at,
+    DROP COLUMN avatar_url,
+    DROP COLUMN claim_ids,
+    DROP COLUMN email_normalized,
+    DROP COLUMN email,
+    DROP COLUMN surnames,
+    DROP COLUMN name;
+ALTER TABLE public.user_changes_pruned DROP COLUMN xid;
+ALTER TABLE public.user_changes DROP COLUMN xid;
+-- +goose StatementEnd
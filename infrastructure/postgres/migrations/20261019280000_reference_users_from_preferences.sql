@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the preferences left behind by the users deleted so far are dropped, as they cannot reference them
+DELETE FROM public.preferences p WHERE NOT EXISTS (SELECT 1 FROM public.users u WHERE u.id::text = p.user_id);
+
+ALTER TABLE public.preferences ALTER COLUMN user_id TYPE uuid USING user_id::uuid;
+ALTER TABLE public.preferences
+    ADD CONSTRAINT preferences_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users (id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.preferences DROP CONSTRAINT preferences_user_id_fkey;
+ALTER TABLE public.preferences ALTER COLUMN user_id TYPE varchar;
+-- +goose StatementEnd
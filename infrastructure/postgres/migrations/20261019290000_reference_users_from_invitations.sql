@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the invitations left behind by the users deleted so far are dropped, as they cannot reference them
+DELETE FROM public.invitations i WHERE NOT EXISTS (SELECT 1 FROM public.users u WHERE u.id::text = i.user_id);
+
+ALTER TABLE public.invitations ALTER COLUMN user_id TYPE uuid USING user_id::uuid;
+ALTER TABLE public.invitations
+    ADD CONSTRAINT invitations_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users (id) ON DELETE CASCADE;
+CREATE INDEX invitations_user_id_idx ON public.invitations (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX public.invitations_user_id_idx;
+ALTER TABLE public.invitations DROP CONSTRAINT invitations_user_id_fkey;
+ALTER TABLE public.invitations ALTER COLUMN user_id TYPE varchar;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the email changes left behind by the users deleted so far are dropped, as they cannot reference them
+DELETE FROM public.email_changes c WHERE NOT EXISTS (SELECT 1 FROM public.users u WHERE u.id::text = c.user_id);
+
+ALTER TABLE public.email_changes ALTER COLUMN user_id TYPE uuid USING user_id::uuid;
+ALTER TABLE public.email_changes
+    ADD CONSTRAINT email_changes_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users (id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.email_changes DROP CONSTRAINT email_changes_user_id_fkey;
+ALTER TABLE public.email_changes ALTER COLUMN user_id TYPE varchar;
+-- +goose StatementEnd
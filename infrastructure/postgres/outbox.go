@@ -0,0 +1,183 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+)
+
+// execer is implemented by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// outboxRepository adapter of an outbox repository for postgres
+type outboxRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewOutboxRepository creates an outbox repository for postgres
+func NewOutboxRepository(db *sql.DB) ports.OutboxRepository {
+	return &outboxRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *outboxRepository) Add(ctx context.Context, event entities.Event) error {
+	return insertEvent(ctx, conn(ctx, r.DB), event)
+}
+
+// insertEvent writes an event to the outbox, through a transaction when it has to be atomic with a change
+func insertEvent(ctx context.Context, e execer, event entities.Event) error {
+	q := `
+	INSERT INTO outbox_events (type, aggregate_id, payload, occurred_at, next_attempt_at)
+	    VALUES ($1, $2, $3, $4, $5);
+	`
+
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.ExecContext(ctx, q, string(event.Type), event.AggregateID, payload, event.OccurredAt, nextAttemptAt(event))
+	return err
+}
+
+// nextAttemptAt returns when the event is first due, which is when it occurred unless set otherwise
+func nextAttemptAt(event entities.Event) time.Time {
+	if event.NextAttemptAt.IsZero() {
+		return event.OccurredAt
+	}
+	return event.NextAttemptAt
+}
+
+func (r *outboxRepository) Pending(ctx context.Context, limit int) ([]entities.Event, error) {
+	q := `
+	SELECT id, type, aggregate_id, payload, occurred_at, published_at, attempts, last_error, next_attempt_at, dead_at
+	    FROM outbox_events WHERE published_at IS NULL AND dead_at IS NULL
+	    ORDER BY occurred_at, seq LIMIT $1;
+	`
+
+	return r.query(ctx, q, limit)
+}
+
+func (r *outboxRepository) Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]entities.Event, error) {
+	q := `
+	WITH claimed AS (
+	    UPDATE outbox_events SET next_attempt_at = $3
+	        WHERE id IN (
+	            SELECT e.id FROM outbox_events e
+	                WHERE e.published_at IS NULL AND e.dead_at IS NULL AND e.next_attempt_at <= $1
+	                AND NOT EXISTS (
+	                    SELECT 1 FROM outbox_events p
+	                        WHERE p.aggregate_id = e.aggregate_id AND p.published_at IS NULL AND p.dead_at IS NULL
+	                        AND (p.occurred_at, p.seq) < (e.occurred_at, e.seq)
+	                )
+	                ORDER BY e.occurred_at, e.seq LIMIT $2
+	                FOR UPDATE SKIP LOCKED
+	        )
+	        RETURNING *
+	)
+	SELECT id, type, aggregate_id, payload, occurred_at, published_at, attempts, last_error, next_attempt_at, dead_at
+	    FROM claimed ORDER BY occurred_at, seq;
+	`
+
+	return r.query(ctx, q, now, limit, leaseUntil)
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, ID string) error {
+	q := `
+	UPDATE outbox_events SET published_at = now() AT TIME ZONE 'utc', attempts = attempts + 1, last_error = ''
+	    WHERE id = $1;
+	`
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, q, ID)
+	return err
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, ID string, cause error, nextAttemptAt time.Time) error {
+	q := `
+	UPDATE outbox_events SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2
+	    WHERE id = $3;
+	`
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, q, cause.Error(), nextAttemptAt, ID)
+	return err
+}
+
+func (r *outboxRepository) MarkDead(ctx context.Context, ID string, cause error) error {
+	q := `
+	UPDATE outbox_events SET dead_at = now() AT TIME ZONE 'utc', attempts = attempts + 1, last_error = $1
+	    WHERE id = $2;
+	`
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, q, cause.Error(), ID)
+	return err
+}
+
+func (r *outboxRepository) DeletePublished(ctx context.Context, before time.Time) (int, error) {
+	q := `
+	DELETE FROM outbox_events WHERE published_at < $1;
+	`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, q, before)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
+
+func (r *outboxRepository) GetByAggregateID(ctx context.Context, aggregateID string) ([]entities.Event, error) {
+	q := `
+	SELECT id, type, aggregate_id, payload, occurred_at, published_at, attempts, last_error, next_attempt_at, dead_at
+	    FROM outbox_events WHERE aggregate_id = $1
+	    ORDER BY occurred_at, seq;
+	`
+
+	return r.query(ctx, q, aggregateID)
+}
+
+// RedactPayloads replaces the payloads of the events of the aggregate by one only holding its ID
+func (r *outboxRepository) RedactPayloads(ctx context.Context, aggregateID string) error {
+	q := `
+	UPDATE outbox_events SET payload = jsonb_build_object('id', aggregate_id)
+	    WHERE aggregate_id = $1;
+	`
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, q, aggregateID)
+	return err
+}
+
+func (r *outboxRepository) query(ctx context.Context, q string, args ...interface{}) ([]entities.Event, error) {
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	events := []entities.Event{}
+	for rows.Next() {
+		var event entities.Event
+		var payload []byte
+		err = rows.Scan(&event.ID, &event.Type, &event.AggregateID, &payload, &event.OccurredAt, &event.PublishedAt, &event.Attempts, &event.LastError, &event.NextAttemptAt, &event.DeadAt)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(payload, &event.Payload); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
@@ -0,0 +1,292 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewOutboxRepository_Ok checks that NewOutboxRepository creates a new outboxRepository struct
+func TestNewOutboxRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewOutboxRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestOutboxAdd_Ok checks that Add inserts the event with its payload encoded as JSON
+func TestOutboxAdd_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &outboxRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	event := entities.NewUserEvent(entities.EventTypeUserLoggedIn, "test-id", nil)
+	mock.ExpectExec("INSERT INTO outbox_events").
+		WithArgs(string(event.Type), event.AggregateID, []byte(`{"id":"test-id"}`), event.OccurredAt, event.NextAttemptAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.Add(context.Background(), event)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestOutboxAdd_InsertError checks that Add returns an error when the insert fails
+func TestOutboxAdd_InsertError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &outboxRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "insert error"
+	mock.ExpectExec("INSERT INTO outbox_events").WillReturnError(errors.New(expectedError))
+
+	// Act
+	err := repo.Add(context.Background(), entities.Event{})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestOutboxPending_Ok checks that Pending returns the unpublished events in order and decodes their payload
+func TestOutboxPending_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &outboxRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM outbox_events WHERE published_at IS NULL AND dead_at IS NULL ORDER BY occurred_at, seq LIMIT \$1`).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type", "aggregate_id", "payload", "occurred_at", "published_at", "attempts", "last_error", "next_attempt_at", "dead_at"}).
+			AddRow("test-id", "user.created", "test-user", []byte(`{"email":"test@test.com"}`), now, nil, 0, "", now, nil).
+			AddRow("test-id-2", "user.deleted", "test-user", []byte(`{"id":"test-user"}`), now, nil, 2, "publish error", now.Add(time.Minute), nil))
+
+	// Act
+	events, err := repo.Pending(context.Background(), 10)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, entities.EventTypeUserCreated, events[0].Type)
+	assert.Equal(t, "test@test.com", events[0].Payload["email"])
+	assert.Equal(t, 2, events[1].Attempts)
+	assert.Equal(t, "publish error", events[1].LastError)
+}
+
+// TestOutboxPending_QueryError checks that Pending returns an error when the query fails
+func TestOutboxPending_QueryError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &outboxRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "query error"
+	mock.ExpectQuery("SELECT (.+) FROM outbox_events").WillReturnError(errors.New(expectedError))
+
+	// Act
+	events, err := repo.Pending(context.Background(), 10)
+
+	// Assert
+	assert.Nil(t, events)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestOutboxClaim_Ok checks that Claim postpones the earliest pending event of every aggregate that is due until the lease,
+// skipping the ones locked by other instances, and returns them in the order they occurred
+func TestOutboxClaim_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &outboxRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now()
+	leaseUntil := now.Add(time.Minute)
+	mock.ExpectQuery(`UPDATE outbox_events SET next_attempt_at = \$3 WHERE id IN \( SELECT e.id FROM outbox_events e WHERE e.published_at IS NULL AND e.dead_at IS NULL AND e.next_attempt_at <= \$1 AND NOT EXISTS \( (.+) AND \(p.occurred_at, p.seq\) < \(e.occurred_at, e.seq\) \) ORDER BY e.occurred_at, e.seq LIMIT \$2 FOR UPDATE SKIP LOCKED \) RETURNING \* \) SELECT (.+) FROM claimed ORDER BY occurred_at, seq`).
+		WithArgs(now, 10, leaseUntil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type", "aggregate_id", "payload", "occurred_at", "published_at", "attempts", "last_error", "next_attempt_at", "dead_at"}).
+			AddRow("test-id", "user.created", "test-user", []byte(`{"email":"test@test.com"}`), now, nil, 0, "", leaseUntil, nil))
+
+	// Act
+	events, err := repo.Claim(context.Background(), now, leaseUntil, 10)
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "test-id", events[0].ID)
+		assert.Equal(t, leaseUntil, events[0].NextAttemptAt)
+	}
+}
+
+// TestOutboxMarkPublished_Ok checks that MarkPublished sets the published timestamp of the event
+func TestOutboxMarkPublished_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &outboxRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("UPDATE outbox_events SET published_at").WithArgs("test-id").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.MarkPublished(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestOutboxMarkFailed_Ok checks that MarkFailed records the cause of the failure, keeping the event pending
+func TestOutboxMarkFailed_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &outboxRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	nextAttemptAt := time.Now().Add(time.Minute)
+	mock.ExpectExec("UPDATE outbox_events SET attempts").WithArgs("publish error", nextAttemptAt, "test-id").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.MarkFailed(context.Background(), "test-id", errors.New("publish error"), nextAttemptAt)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestOutboxMarkDead_Ok checks that MarkDead sets the dead timestamp of the event along with the cause of the failure
+func TestOutboxMarkDead_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &outboxRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("UPDATE outbox_events SET dead_at").WithArgs("publish error", "test-id").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.MarkDead(context.Background(), "test-id", errors.New("publish error"))
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestOutboxDeletePublished_Ok checks that DeletePublished returns the number of events published before the given time
+func TestOutboxDeletePublished_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &outboxRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	before := time.Now()
+	mock.ExpectExec(`DELETE FROM outbox_events WHERE published_at < \$1`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	// Act
+	deleted, err := repo.DeletePublished(context.Background(), before)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 3, deleted)
+}
+
+// TestOutboxDeletePublished_DeleteError checks that DeletePublished returns an error when the delete fails
+func TestOutboxDeletePublished_DeleteError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &outboxRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "delete error"
+	mock.ExpectExec("DELETE FROM outbox_events").WillReturnError(errors.New(expectedError))
+
+	// Act
+	deleted, err := repo.DeletePublished(context.Background(), time.Now())
+
+	// Assert
+	assert.Zero(t, deleted)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestOutboxRedactPayloads_Ok checks that RedactPayloads replaces the payloads of the events of the aggregate by its ID
+func TestOutboxRedactPayloads_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &outboxRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec(`UPDATE outbox_events SET payload = jsonb_build_object\('id', aggregate_id\) WHERE aggregate_id = \$1`).
+		WithArgs("test-user").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	// Act
+	err := repo.RedactPayloads(context.Background(), "test-user")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
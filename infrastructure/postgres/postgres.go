@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PoolOptions options of the connection pool of a postgres database, where zero values keep the defaults of database/sql
+type PoolOptions struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// StatementTimeout after which the server cancels a statement
+	StatementTimeout time.Duration
+}
+
+// Open opens the postgres database of the DSN with the given pool options.
+// It does not connect to the database, so PingContext needs to be used to ensure that it is reachable.
+func Open(dsn string, opts PoolOptions) (*sql.DB, error) {
+	dsn, err := withStatementTimeout(dsn, opts.StatementTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.MaxOpenConns != 0 {
+		db.SetMaxOpenConns(opts.MaxOpenConns)
+	}
+	if opts.MaxIdleConns != 0 {
+		db.SetMaxIdleConns(opts.MaxIdleConns)
+	}
+	if opts.ConnMaxLifetime != 0 {
+		db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	}
+	if opts.ConnMaxIdleTime != 0 {
+		db.SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+	}
+	return db, nil
+}
+
+// withStatementTimeout adds the statement_timeout run-time parameter to the DSN, unless it is already set.
+// URL DSNs are converted to the key/value format first, where the driver sends unknown keys as run-time parameters.
+func withStatementTimeout(dsn string, timeout time.Duration) (string, error) {
+	if timeout <= 0 || strings.Contains(dsn, "statement_timeout") {
+		return dsn, nil
+	}
+
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		var err error
+		if dsn, err = pq.ParseURL(dsn); err != nil {
+			return "", fmt.Errorf("DSN not valid: %w", err)
+		}
+	}
+	return fmt.Sprintf("%s statement_timeout=%d", dsn, timeout.Milliseconds()), nil
+}
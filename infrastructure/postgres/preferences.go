@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// preferencesRepository adapter of a preferences repository for postgres, keyed by the ID of the user
+type preferencesRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewPreferencesRepository creates a preferences repository for postgres
+func NewPreferencesRepository(db *sql.DB) ports.PreferencesRepository {
+	return &preferencesRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *preferencesRepository) GetByUserID(ctx context.Context, userID string) (entities.Preferences, error) {
+	q := `
+	SELECT user_id, version, language, timezone, email_notifications, push_notifications, marketing_notifications, updated_at
+	    FROM preferences WHERE user_id = $1;
+	`
+
+	row := conn(ctx, r.DB).QueryRowContext(ctx, q, userID)
+
+	var p entities.Preferences
+	var language, timezone sql.NullString
+	var email, push, marketing sql.NullBool
+	err := row.Scan(&p.UserID, &p.Version, &language, &timezone, &email, &push, &marketing, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return entities.Preferences{}, notFoundIfMalformed(err)
+	}
+
+	p.Language = nullable(language.String, language.Valid)
+	p.Timezone = nullable(timezone.String, timezone.Valid)
+	p.Notifications.Email = nullable(email.Bool, email.Valid)
+	p.Notifications.Push = nullable(push.Bool, push.Valid)
+	p.Notifications.Marketing = nullable(marketing.Bool, marketing.Valid)
+	return p, nil
+}
+
+// Save inserts the first version of the preferences unless a row already exists,
+// and updates the following ones only while the stored row is at the previous version, failing with a conflict error otherwise.
+// The preferences reference their user, so saving them for a user that does not exist fails with a NonExistentErr.
+func (r *preferencesRepository) Save(ctx context.Context, preferences entities.Preferences) error {
+	q := `
+	UPDATE preferences SET version=$2, language=$3, timezone=$4, email_notifications=$5, push_notifications=$6, marketing_notifications=$7, updated_at=$8
+	    WHERE user_id=$1 AND version=$2 - 1;
+	`
+	if preferences.Version == 1 {
+		q = `
+		INSERT INTO preferences (user_id, version, language, timezone, email_notifications, push_notifications, marketing_notifications, updated_at)
+		    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		    ON CONFLICT (user_id) DO NOTHING;
+		`
+	}
+
+	result, err := conn(ctx, r.DB).ExecContext(
+		ctx, q, preferences.UserID, preferences.Version, preferences.Language, preferences.Timezone,
+		preferences.Notifications.Email, preferences.Notifications.Push, preferences.Notifications.Marketing, preferences.UpdatedAt,
+	)
+	if err != nil {
+		return notFoundIfNoUser(notFoundIfMalformed(err), preferences.UserID)
+	}
+
+	err = checkAffected(result)
+	if errors.Is(err, wrappers.NonExistentErr) {
+		err = entities.NewOutdatedPreferencesErr(preferences.Version - 1)
+	}
+	return err
+}
+
+func (r *preferencesRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	q := `DELETE FROM preferences WHERE user_id=$1;`
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, q, userID)
+	return err
+}
+
+// nullable returns a pointer to the value when it is valid, nil otherwise
+func nullable[T any](value T, valid bool) *T {
+	if !valid {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewPreferencesRepository_Ok checks that NewPreferencesRepository creates a new preferencesRepository struct
+func TestNewPreferencesRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewPreferencesRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestPreferencesGetByUserID_Ok checks that GetByUserID returns the preferences found, leaving nil the ones not overridden
+func TestPreferencesGetByUserID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &preferencesRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM preferences WHERE user_id").WithArgs("test-user-id").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "version", "language", "timezone", "email_notifications", "push_notifications", "marketing_notifications", "updated_at"}).
+			AddRow("test-user-id", 2, "es", nil, nil, false, nil, time.Now()))
+
+	// Act
+	preferences, err := repo.GetByUserID(context.Background(), "test-user-id")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 2, preferences.Version)
+	assert.Equal(t, "es", *preferences.Language)
+	assert.Nil(t, preferences.Timezone)
+	assert.False(t, *preferences.Notifications.Push)
+	assert.Nil(t, preferences.Notifications.Email)
+}
+
+// TestPreferencesGetByUserID_NotFound checks that GetByUserID returns a non existent error when the user has no preferences
+func TestPreferencesGetByUserID_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &preferencesRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM preferences WHERE user_id").WithArgs("test-user-id").WillReturnError(sql.ErrNoRows)
+
+	// Act
+	_, err := repo.GetByUserID(context.Background(), "test-user-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestPreferencesSave_First checks that Save inserts the first version of the preferences
+func TestPreferencesSave_First(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &preferencesRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	language := "es"
+	preferences := entities.Preferences{UserID: "test-user-id", Version: 1, Language: &language, UpdatedAt: time.Now()}
+	mock.ExpectExec("INSERT INTO preferences (.+) ON CONFLICT").
+		WithArgs(preferences.UserID, preferences.Version, "es", nil, nil, nil, nil, preferences.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.Save(context.Background(), preferences)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestPreferencesSave_OutdatedVersion checks that Save returns a conflict error when the stored preferences are not at the previous version
+func TestPreferencesSave_OutdatedVersion(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &preferencesRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("UPDATE preferences SET (.+) WHERE user_id=(.+) AND version").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Save(context.Background(), entities.Preferences{UserID: "test-user-id", Version: 3})
+
+	// Assert
+	assert.True(t, errors.Is(err, entities.ConflictErr))
+}
+
+// TestPreferencesSave_NoUser checks that Save returns a non existent error when the user of the preferences does not exist
+func TestPreferencesSave_NoUser(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &preferencesRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("INSERT INTO preferences (.+) ON CONFLICT").WillReturnError(&pq.Error{Code: "23503", Constraint: "preferences_user_id_fkey"})
+
+	// Act
+	err := repo.Save(context.Background(), entities.Preferences{UserID: "test-user-id", Version: 1})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+	assert.Equal(t, "ID test-user-id not found", err.Error())
+}
+
+// TestPreferencesDeleteByUserID_Ok checks that DeleteByUserID does not return an error when the user has no preferences
+func TestPreferencesDeleteByUserID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &preferencesRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM preferences WHERE user_id").WithArgs("test-user-id").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.DeleteByUserID(context.Background(), "test-user-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
@@ -0,0 +1,235 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+// lagQuery returns the replication lag of a replica in seconds, which is 0 when it has replayed all the WAL received from the primary
+// and when the database is not a replica
+const lagQuery = `
+SELECT CASE
+    WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+    ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END;
+`
+
+// defaultReplicaCheckInterval interval between the health checks of the replicas when none is set
+const defaultReplicaCheckInterval = 5 * time.Second
+
+// ReplicaOptions options of the read replicas
+type ReplicaOptions struct {
+	// CheckInterval between the health checks of the replicas, 5s when not positive
+	CheckInterval time.Duration
+	// MaxLag beyond which a replica is ejected, or 0 to never eject a replica because of its lag
+	MaxLag time.Duration
+	// Pool options of every replica
+	Pool PoolOptions
+}
+
+// Replicas read replicas of a postgres database, which serve the reads in round-robin while they are healthy.
+// A replica is ejected when it cannot be reached or lags behind the primary more than the max lag, and readmitted once it recovers.
+type Replicas struct {
+	replicas []*replica
+	opts     ReplicaOptions
+	next     atomic.Uint64
+}
+
+// replica read replica along with the status of its last health check
+type replica struct {
+	db     *sql.DB
+	mu     sync.RWMutex
+	status models.ReplicaStatus
+}
+
+// ConnectReplicas opens the read replicas with the given DSNs and checks their health every CheckInterval until the context is done.
+// Replicas that cannot be reached do not fail, as they stay ejected until they recover.
+func ConnectReplicas(ctx context.Context, dsns []string, opts ReplicaOptions) (*Replicas, error) {
+	replicas := make([]*replica, len(dsns))
+	for i, dsn := range dsns {
+		db, err := Open(dsn, opts.Pool)
+		if err != nil {
+			for _, opened := range replicas[:i] {
+				opened.db.Close()
+			}
+			return nil, fmt.Errorf("failed to open replica %s: %w", replicaName(dsn, i), err)
+		}
+		replicas[i] = newReplica(db, replicaName(dsn, i))
+	}
+	return startReplicas(ctx, replicas, opts), nil
+}
+
+// NewReplicas creates the read replicas of databases already opened, named after their position,
+// and checks their health every CheckInterval until the context is done
+func NewReplicas(ctx context.Context, dbs []*sql.DB, opts ReplicaOptions) *Replicas {
+	replicas := make([]*replica, len(dbs))
+	for i, db := range dbs {
+		replicas[i] = newReplica(db, fmt.Sprintf("replica-%d", i))
+	}
+	return startReplicas(ctx, replicas, opts)
+}
+
+func startReplicas(ctx context.Context, replicas []*replica, opts ReplicaOptions) *Replicas {
+	r := newReplicas(ctx, replicas, opts)
+	go r.run(ctx)
+	return r
+}
+
+// newReplicas creates the read replicas, running their first health check
+func newReplicas(ctx context.Context, replicas []*replica, opts ReplicaOptions) *Replicas {
+	if opts.CheckInterval <= 0 {
+		opts.CheckInterval = defaultReplicaCheckInterval
+	}
+
+	r := &Replicas{
+		replicas: replicas,
+		opts:     opts,
+	}
+	r.check(ctx)
+	return r
+}
+
+// newReplica creates a read replica, admitted until its first health check
+func newReplica(db *sql.DB, name string) *replica {
+	return &replica{
+		db:     db,
+		status: models.ReplicaStatus{Name: name, Healthy: true},
+	}
+}
+
+// replicaName returns the host of a replica, without the credentials of its DSN, or its position when the DSN is not a URL
+func replicaName(dsn string, i int) string {
+	if u, err := url.Parse(dsn); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return fmt.Sprintf("replica-%d", i)
+}
+
+// Status returns the status of every replica as of its last health check
+func (r *Replicas) Status() []models.ReplicaStatus {
+	status := make([]models.ReplicaStatus, len(r.replicas))
+	for i, rep := range r.replicas {
+		status[i] = rep.current()
+	}
+	return status
+}
+
+// reader returns the connection serving a read: the transaction of the unit of work running in the context, or the primary once
+// the context has written to it, so that a request reads its own writes. Otherwise the next healthy replica serves it,
+// or the primary when there is none. A nil Replicas always reads from the primary.
+func (r *Replicas) reader(ctx context.Context, db *sql.DB) querier {
+	if r == nil || len(r.replicas) == 0 || hasWritten(ctx) {
+		return conn(ctx, db)
+	}
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return conn(ctx, db)
+	}
+
+	n := uint64(len(r.replicas))
+	start := r.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		rep := r.replicas[(start+i)%n]
+		if rep.current().Healthy {
+			return rep.db
+		}
+	}
+	return db
+}
+
+func (r *Replicas) run(ctx context.Context) {
+	ticker := time.NewTicker(r.opts.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			for _, rep := range r.replicas {
+				rep.db.Close()
+			}
+			return
+		case <-ticker.C:
+			r.check(ctx)
+		}
+	}
+}
+
+// check runs the health check of every replica concurrently, so that an unreachable one does not delay the others
+func (r *Replicas) check(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, rep := range r.replicas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rep.check(ctx, r.opts)
+		}()
+	}
+	wg.Wait()
+}
+
+func (rep *replica) current() models.ReplicaStatus {
+	rep.mu.RLock()
+	defer rep.mu.RUnlock()
+	return rep.status
+}
+
+// check queries the lag of the replica, ejecting it when the query fails or the lag exceeds the max lag
+func (rep *replica) check(ctx context.Context, opts ReplicaOptions) {
+	if opts.CheckInterval > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.CheckInterval)
+		defer cancel()
+	}
+
+	status := models.ReplicaStatus{Name: rep.current().Name}
+	var seconds float64
+	if err := rep.db.QueryRowContext(ctx, lagQuery).Scan(&seconds); err != nil {
+		status.Error = err.Error()
+	} else {
+		status.Lag = time.Duration(seconds * float64(time.Second))
+		status.Healthy = opts.MaxLag <= 0 || status.Lag <= opts.MaxLag
+		if !status.Healthy {
+			status.Error = fmt.Sprintf("lag %s exceeds %s", status.Lag, opts.MaxLag)
+		}
+	}
+
+	rep.mu.Lock()
+	wasHealthy := rep.status.Healthy
+	rep.status = status
+	rep.mu.Unlock()
+
+	switch {
+	case wasHealthy && !status.Healthy:
+		observability.Logger().Printf("postgres replica %s ejected: %s", status.Name, status.Error)
+	case !wasHealthy && status.Healthy:
+		observability.Logger().Printf("postgres replica %s readmitted", status.Name)
+	}
+}
+
+// writtenKey context key of the flag telling whether a request has written to the primary
+type writtenKey struct{}
+
+// WithReadYourWrites returns a context whose reads are served by the primary once it has been used to write,
+// so that a request reads its own writes regardless of the lag of the replicas. It is meant to be set once per request.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, writtenKey{}, &atomic.Bool{})
+}
+
+// markWritten flags the context as having written to the primary
+func markWritten(ctx context.Context) {
+	if written, ok := ctx.Value(writtenKey{}).(*atomic.Bool); ok {
+		written.Store(true)
+	}
+}
+
+func hasWritten(ctx context.Context) bool {
+	written, ok := ctx.Value(writtenKey{}).(*atomic.Bool)
+	return ok && written.Load()
+}
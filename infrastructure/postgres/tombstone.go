@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+)
+
+// tombstoneRepository adapter of a tombstone repository for postgres
+type tombstoneRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewTombstoneRepository creates a tombstone repository for postgres
+func NewTombstoneRepository(db *sql.DB) ports.TombstoneRepository {
+	return &tombstoneRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *tombstoneRepository) Create(ctx context.Context, tombstone entities.Tombstone) (string, error) {
+	q := `
+	INSERT INTO tombstones (user_id, erased_by, stores, erased_at)
+	    VALUES ($1, $2, $3, $4)
+	    RETURNING id;
+	`
+
+	row := conn(ctx, r.DB).QueryRowContext(ctx, q, tombstone.UserID, tombstone.ErasedBy, pq.Array(tombstone.Stores), tombstone.ErasedAt)
+
+	err := row.Scan(&tombstone.ID)
+	if err != nil {
+		return "", err
+	}
+
+	return tombstone.ID, nil
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewTombstoneRepository_Ok checks that NewTombstoneRepository creates a new tombstoneRepository struct
+func TestNewTombstoneRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewTombstoneRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestTombstoneCreate_Ok checks that Create returns the expected response when a valid tombstone is received
+func TestTombstoneCreate_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &tombstoneRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedID := "new-id"
+	mock.ExpectQuery("INSERT INTO tombstones").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
+
+	// Act
+	id, err := repo.Create(context.Background(), entities.Tombstone{
+		UserID:   "test-user-id",
+		ErasedBy: "test-admin-id",
+		Stores:   []string{"users"},
+		ErasedAt: time.Now(),
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedID, id)
+}
+
+// TestTombstoneCreate_InsertError checks that Create returns an error when the insert fails
+func TestTombstoneCreate_InsertError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &tombstoneRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "insert error"
+	mock.ExpectQuery("INSERT INTO tombstones").WillReturnError(errors.New(expectedError))
+
+	// Act
+	id, err := repo.Create(context.Background(), entities.Tombstone{})
+
+	// Assert
+	assert.Empty(t, id)
+	assert.Equal(t, expectedError, err.Error())
+}
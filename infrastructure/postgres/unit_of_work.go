@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// txKey context key of the transaction of the running unit of work
+type txKey struct{}
+
+// querier is implemented by both *sql.DB and *sql.Tx
+type querier interface {
+	execer
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// unitOfWork adapter of a unit of work for postgres, whose transaction is propagated through the context
+type unitOfWork struct {
+	db *sql.DB
+}
+
+// NewUnitOfWork creates a unit of work for postgres
+func NewUnitOfWork(db *sql.DB) ports.UnitOfWork {
+	return &unitOfWork{
+		db: db,
+	}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withTransaction(ctx, u.db, func(tx *sql.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the transaction of the unit of work running in the context, or the database when there is none
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// withTransaction runs fn in the transaction of the unit of work running in the context, which is left to the unit of work to commit.
+// Otherwise fn runs in a new transaction, that gets committed when fn succeeds and rolled back otherwise.
+// Either way, the reads of the context are served by the primary from then on.
+func withTransaction(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	markWritten(ctx)
+
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(tx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
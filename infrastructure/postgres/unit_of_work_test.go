@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUnitOfWorkDo_Ok checks that Do runs the calls of every repository, including the ones of a nested Do, in a single transaction that gets committed
+func TestUnitOfWorkDo_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	uow := NewUnitOfWork(db)
+	users := NewUserRepository(db, nil)
+	audit := NewAuditRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO users").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("test-id"))
+	mock.ExpectExec("INSERT INTO outbox_events").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO audit_events").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	mock.ExpectCommit()
+
+	// Act
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		err := uow.Do(ctx, func(ctx context.Context) error {
+			_, err := users.Create(ctx, entities.User{})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		_, err = audit.Create(ctx, entities.AuditEvent{Timestamp: time.Now().UTC()})
+		return err
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestUnitOfWorkDo_Rollback checks that Do rolls back the transaction when fn fails
+func TestUnitOfWorkDo_Rollback(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	uow := NewUnitOfWork(db)
+	audit := NewAuditRepository(db)
+	expectedError := errors.New("fn error")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO audit_events").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	mock.ExpectRollback()
+
+	// Act
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		if _, err := audit.Create(ctx, entities.AuditEvent{Timestamp: time.Now().UTC()}); err != nil {
+			return err
+		}
+		return expectedError
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, expectedError)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestUnitOfWorkDo_BeginError checks that Do does not run fn when the transaction cannot begin
+func TestUnitOfWorkDo_BeginError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	uow := NewUnitOfWork(db)
+	expectedError := "begin error"
+	mock.ExpectBegin().WillReturnError(errors.New(expectedError))
+
+	// Act
+	var called bool
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	// Assert
+	assert.EqualError(t, err, expectedError)
+	assert.False(t, called)
+}
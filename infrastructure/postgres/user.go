@@ -5,58 +5,53 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/lib/pq"
 	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
 	"github.com/sergicanet9/go-hexagonal-api/core/ports"
 	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
 	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
 )
 
+// userIteratePageSize number of users read at once by Iterate
+const userIteratePageSize = 500
+
 // userRepository adapter of an user repository for postgres
 type userRepository struct {
 	infrastructure.PostgresRepository
+	replicas *Replicas
 }
 
-// NewUserRepository creates a user repository for postgres
-func NewUserRepository(db *sql.DB) ports.UserRepository {
+// NewUserRepository creates a user repository for postgres, whose Get and GetByID are served by the given read replicas,
+// or by the primary when replicas is nil
+func NewUserRepository(db *sql.DB, replicas *Replicas) ports.UserRepository {
 	return &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
+		replicas: replicas,
 	}
 }
 
-func (r *userRepository) Create(ctx context.Context, user interface{}) (string, error) {
-	q := `
-	INSERT INTO users (name, surnames, email, password_hash, claim_ids, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
-        RETURNING id;
-    `
-
-	u := user.(entities.User)
-	row := r.DB.QueryRowContext(
-		ctx, q, u.Name, u.Surnames, u.Email, u.PasswordHash, pq.Array(u.ClaimIDs), u.CreatedAt, u.UpdatedAt,
-	)
-
-	err := row.Scan(&u.ID)
+func (r *userRepository) Create(ctx context.Context, user entities.User) (string, error) {
+	var ID string
+	err := withTransaction(ctx, r.DB, func(tx *sql.Tx) (err error) {
+		ID, err = createUser(ctx, tx, user)
+		return
+	})
 	if err != nil {
 		return "", err
 	}
 
-	return u.ID, nil
+	return ID, nil
 }
 
-func (r *userRepository) Get(ctx context.Context, filter map[string]interface{}, skip, take *int) ([]interface{}, error) {
-	var where string
-	for k, v := range filter {
-		if where == "" {
-			where = "WHERE"
-		} else {
-			where = fmt.Sprintf("%s AND", where)
-		}
-		where = fmt.Sprintf("%s %s = '%v'", where, k, v)
-	}
+func (r *userRepository) Get(ctx context.Context, filter models.UserFilter, skip, take *int) ([]entities.User, error) {
+	where, args := userWhere(filter)
+	// the users are sorted so that the pages are stable
+	where = fmt.Sprintf("%s ORDER BY id", where)
 	if skip != nil {
 		where = fmt.Sprintf("%s OFFSET %d", where, *skip)
 	}
@@ -65,25 +60,28 @@ func (r *userRepository) Get(ctx context.Context, filter map[string]interface{},
 	}
 
 	q := fmt.Sprintf(`
-	SELECT id, name, surnames, email, password_hash, claim_ids, created_at, updated_at
+	SELECT id, name, surnames, email, email_normalized, password_hash, claim_ids, avatar_url, created_at, updated_at
 	    FROM users %s;
 	`, where)
 
-	rows, err := r.DB.QueryContext(ctx, q)
+	rows, err := r.replicas.reader(ctx, r.DB).QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
 
 	defer rows.Close()
 
-	var users []interface{}
+	var users []entities.User
 	for rows.Next() {
 		var u entities.User
-		err = rows.Scan(&u.ID, &u.Name, &u.Surnames, &u.Email, &u.PasswordHash, pq.Array(&u.ClaimIDs), &u.CreatedAt, &u.UpdatedAt)
+		err = rows.Scan(&u.ID, &u.Name, &u.Surnames, &u.Email, &u.EmailNormalized, &u.PasswordHash, pq.Array(&u.ClaimIDs), &u.AvatarURL, &u.CreatedAt, &u.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
-		users = append(users, &u)
+		users = append(users, u)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
 
 	if len(users) < 1 {
@@ -93,58 +91,267 @@ func (r *userRepository) Get(ctx context.Context, filter map[string]interface{},
 	return users, nil
 }
 
-func (r *userRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+func (r *userRepository) GetByID(ctx context.Context, ID string) (entities.User, error) {
 	q := `
-    SELECT id, name, surnames, email, password_hash, claim_ids, created_at, updated_at
+    SELECT id, name, surnames, email, email_normalized, password_hash, claim_ids, avatar_url, created_at, updated_at
         FROM users WHERE id = $1;
     `
 
-	row := r.DB.QueryRowContext(ctx, q, ID)
+	row := r.replicas.reader(ctx, r.DB).QueryRowContext(ctx, q, ID)
 
 	var u entities.User
-	err := row.Scan(&u.ID, &u.Name, &u.Surnames, &u.Email, &u.PasswordHash, pq.Array(&u.ClaimIDs), &u.CreatedAt, &u.UpdatedAt)
+	err := row.Scan(&u.ID, &u.Name, &u.Surnames, &u.Email, &u.EmailNormalized, &u.PasswordHash, pq.Array(&u.ClaimIDs), &u.AvatarURL, &u.CreatedAt, &u.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = wrappers.NewNonExistentErr(err)
 		}
+		return entities.User{}, notFoundIfMalformed(err)
+	}
+
+	return u, nil
+}
+
+func (r *userRepository) Update(ctx context.Context, ID string, user entities.User) error {
+	return withTransaction(ctx, r.DB, func(tx *sql.Tx) error {
+		return updateUser(ctx, tx, ID, user)
+	})
+}
+
+func (r *userRepository) Delete(ctx context.Context, ID string) error {
+	return withTransaction(ctx, r.DB, func(tx *sql.Tx) error {
+		return deleteUser(ctx, tx, ID)
+	})
+}
+
+func (r *userRepository) CreateMany(ctx context.Context, users []entities.User) ([]string, error) {
+	var result []string
+	err := withTransaction(ctx, r.DB, func(tx *sql.Tx) error {
+		for _, user := range users {
+			ID, err := createUser(ctx, tx, user)
+			if err != nil {
+				return err
+			}
+			result = append(result, ID)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &u, nil
+	return result, nil
+}
+
+func (r *userRepository) UpdateMany(ctx context.Context, IDs []string, users []entities.User) error {
+	return withTransaction(ctx, r.DB, func(tx *sql.Tx) error {
+		for i, user := range users {
+			if err := updateUser(ctx, tx, IDs[i], user); err != nil {
+				return withID(err, IDs[i])
+			}
+		}
+		return nil
+	})
+}
+
+func (r *userRepository) DeleteMany(ctx context.Context, IDs []string) error {
+	return withTransaction(ctx, r.DB, func(tx *sql.Tx) error {
+		for _, ID := range IDs {
+			if err := deleteUser(ctx, tx, ID); err != nil {
+				return withID(err, ID)
+			}
+		}
+		return nil
+	})
 }
 
-func (r *userRepository) Update(ctx context.Context, ID string, user interface{}) error {
+// Search matches the query both as full-text, ranked by ts_rank, and as fuzzy text, ranked by trigram word similarity,
+// over the normalized search document of the users, so that the users_search indexes are used
+func (r *userRepository) Search(ctx context.Context, query string, offset, limit int) ([]entities.User, error) {
 	q := `
-	UPDATE users set name=$1, surnames=$2, email=$3, password_hash=$4, claim_ids=$5, updated_at=$6
-	    WHERE id=$7;
+	SELECT id, name, surnames, email, email_normalized, password_hash, claim_ids, avatar_url, created_at, updated_at
+	    FROM users
+	    WHERE to_tsvector('simple', users_search_document(name, surnames, email)) @@ plainto_tsquery('simple', search_normalize($1))
+	        OR search_normalize($1) <% users_search_document(name, surnames, email)
+	    ORDER BY ts_rank(to_tsvector('simple', users_search_document(name, surnames, email)), plainto_tsquery('simple', search_normalize($1)))
+	        + word_similarity(search_normalize($1), users_search_document(name, surnames, email)) DESC, id
+	    OFFSET $2 LIMIT $3;
 	`
 
-	u := user.(entities.User)
-	result, err := r.DB.ExecContext(
-		ctx, q, u.Name, u.Surnames, u.Email, u.PasswordHash, pq.Array(u.ClaimIDs), u.UpdatedAt, ID,
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, q, query, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	users := []entities.User{}
+	for rows.Next() {
+		var u entities.User
+		err = rows.Scan(&u.ID, &u.Name, &u.Surnames, &u.Email, &u.EmailNormalized, &u.PasswordHash, pq.Array(&u.ClaimIDs), &u.AvatarURL, &u.CreatedAt, &u.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+// Iterate reads the users in pages sorted by ID, each one starting after the ID of the last user of the previous one,
+// so that neither the result set is loaded into memory at once nor a connection is held while fn is called.
+// The pages are read in separate statements, so the users changed during the iteration may be seen as of any of them.
+func (r *userRepository) Iterate(ctx context.Context, filter models.UserFilter, fn func(user entities.User) error) error {
+	where, args := userWhere(filter)
+
+	var after string
+	for {
+		users, err := r.iteratePage(ctx, where, args, after)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range users {
+			if err = fn(u); err != nil {
+				return err
+			}
+		}
+
+		if len(users) < userIteratePageSize {
+			return nil
+		}
+		after = users[len(users)-1].ID
+	}
+}
+
+// iteratePage reads a page of the users matching the WHERE clause whose ID comes after the given one, or the first page when empty
+func (r *userRepository) iteratePage(ctx context.Context, where string, args []interface{}, after string) ([]entities.User, error) {
+	if after != "" {
+		args = append(args, after)
+		condition := fmt.Sprintf("id > $%d", len(args))
+		if where == "" {
+			where = "WHERE " + condition
+		} else {
+			where += " AND " + condition
+		}
+	}
+	args = append(args, userIteratePageSize)
+
+	q := fmt.Sprintf(`
+	SELECT id, name, surnames, email, email_normalized, password_hash, claim_ids, avatar_url, created_at, updated_at
+	    FROM users %s ORDER BY id LIMIT $%d;
+	`, where, len(args))
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var users []entities.User
+	for rows.Next() {
+		var u entities.User
+		err = rows.Scan(&u.ID, &u.Name, &u.Surnames, &u.Email, &u.EmailNormalized, &u.PasswordHash, pq.Array(&u.ClaimIDs), &u.AvatarURL, &u.CreatedAt, &u.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+// userWhere builds the WHERE clause of the users matching the filter, along with its arguments
+func userWhere(filter models.UserFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Name != "" {
+		add("name = $%d", filter.Name)
+	}
+	if filter.Surnames != "" {
+		add("surnames = $%d", filter.Surnames)
+	}
+	if filter.EmailNormalized != "" {
+		add("email_normalized = $%d", filter.EmailNormalized)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		add("created_at >= $%d", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		add("created_at < $%d", filter.CreatedTo)
+	}
+	if filter.ClaimID != nil {
+		add("$%d = ANY(claim_ids)", *filter.ClaimID)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// createUser inserts the user and its created event to the outbox in the given transaction
+func createUser(ctx context.Context, tx *sql.Tx, u entities.User) (string, error) {
+	q := `
+	INSERT INTO users (name, surnames, email, email_normalized, password_hash, claim_ids, avatar_url, created_at, updated_at)
+	    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	    RETURNING id;
+	`
+
+	row := tx.QueryRowContext(
+		ctx, q, u.Name, u.Surnames, u.Email, u.EmailNormalized, u.PasswordHash, pq.Array(u.ClaimIDs), u.AvatarURL, u.CreatedAt, u.UpdatedAt,
 	)
+
+	err := row.Scan(&u.ID)
 	if err != nil {
-		return err
+		return "", emailInUseIfDuplicate(err, u.EmailNormalized)
 	}
 
-	rows, err := result.RowsAffected()
+	return u.ID, insertEvent(ctx, tx, entities.NewUserEvent(entities.EventTypeUserCreated, u.ID, &u))
+}
+
+// updateUser updates the user and writes its updated event to the outbox in the given transaction
+func updateUser(ctx context.Context, tx *sql.Tx, ID string, u entities.User) error {
+	q := `
+	UPDATE users set name=$1, surnames=$2, email=$3, email_normalized=$4, password_hash=$5, claim_ids=$6, avatar_url=$7, updated_at=$8
+	    WHERE id=$9;
+	`
+
+	result, err := tx.ExecContext(
+		ctx, q, u.Name, u.Surnames, u.Email, u.EmailNormalized, u.PasswordHash, pq.Array(u.ClaimIDs), u.AvatarURL, u.UpdatedAt, ID,
+	)
 	if err != nil {
-		return err
+		return emailInUseIfDuplicate(notFoundIfMalformed(err), u.EmailNormalized)
 	}
-	if rows < 1 {
-		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	if err = checkAffected(result); err != nil {
+		return err
 	}
-	return nil
+
+	return insertEvent(ctx, tx, entities.NewUserEvent(entities.EventTypeUserUpdated, ID, &u))
 }
 
-func (r *userRepository) Delete(ctx context.Context, ID string) error {
+// deleteUser deletes the user and writes its deleted event to the outbox in the given transaction
+func deleteUser(ctx context.Context, tx *sql.Tx, ID string) error {
 	q := `DELETE FROM users WHERE id=$1;`
 
-	result, err := r.DB.ExecContext(ctx, q, ID)
+	result, err := tx.ExecContext(ctx, q, ID)
 	if err != nil {
+		return notFoundIfMalformed(err)
+	}
+	if err = checkAffected(result); err != nil {
 		return err
 	}
 
+	return insertEvent(ctx, tx, entities.NewUserEvent(entities.EventTypeUserDeleted, ID, nil))
+}
+
+// checkAffected returns a NonExistentErr when the statement did not touch any row
+func checkAffected(result sql.Result) error {
 	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
@@ -155,39 +362,37 @@ func (r *userRepository) Delete(ctx context.Context, ID string) error {
 	return nil
 }
 
-func (r *userRepository) CreateMany(ctx context.Context, users []interface{}) ([]string, error) {
-	// `tx` is an instance of `*sql.Tx` through which we can execute our queries
-	tx, err := r.DB.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, err
+// notFoundIfMalformed reports the errors of malformed user IDs as not found, as they cannot belong to any user
+func notFoundIfMalformed(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "22P02" {
+		return wrappers.NewNonExistentErr(err)
 	}
+	return err
+}
 
-	var result []string
-	for _, entity := range users {
-		u := entity.(entities.User)
-
-		q := `
-		INSERT INTO users (name, surnames, email, password_hash, claim_ids, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
-			RETURNING id;`
-
-		// Here, the query is executed on the transaction instance, and not applied to the database yet
-		row := tx.QueryRowContext(
-			ctx, q, u.Name, u.Surnames, u.Email, u.PasswordHash, pq.Array(u.ClaimIDs), u.CreatedAt, u.UpdatedAt,
-		)
-		err := row.Scan(&u.ID)
-		if err != nil {
-			// Incase we find any error in the query execution, rollback the transaction
-			tx.Rollback()
-			return nil, err
-		}
-		result = append(result, u.ID)
+// notFoundIfNoUser reports the foreign key violations of the rows referencing the given user as not found, as the user does not exist
+func notFoundIfNoUser(err error, userID string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", userID))
 	}
+	return err
+}
 
-	// Finally, if no errors are recieved from the queries, commit the transaction
-	// this applies the above changes to our database
-	if err := tx.Commit(); err != nil {
-		return nil, err
+// emailInUseIfDuplicate maps the unique violations, only raised by the normalized emails of the users, to an AlreadyExistsErr
+func emailInUseIfDuplicate(err error, emailNormalized string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return entities.NewEmailInUseErr(emailNormalized)
 	}
-	return result, nil
+	return err
+}
+
+// withID adds the affected ID to the message of NonExistentErr errors
+func withID(err error, ID string) error {
+	if errors.Is(err, wrappers.NonExistentErr) {
+		return wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ID))
+	}
+	return err
 }
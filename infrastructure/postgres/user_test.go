@@ -4,11 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/lib/pq"
 	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
 	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
 	"github.com/sergicanet9/scv-go-tools/v4/mocks"
 	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
@@ -22,7 +25,7 @@ func TestNewUserRepository_Ok(t *testing.T) {
 	defer db.Close()
 
 	// Act
-	repo := NewUserRepository(db)
+	repo := NewUserRepository(db, nil)
 
 	// Assert
 	assert.NotEmpty(t, repo)
@@ -35,14 +38,17 @@ func TestCreate_Ok(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
 
 	newUser := entities.User{}
 	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
+	mock.ExpectBegin()
 	mock.ExpectQuery("INSERT INTO users").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
+	mock.ExpectExec("INSERT INTO outbox_events").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
 	// Act
 	id, err := repo.Create(context.Background(), newUser)
@@ -59,14 +65,16 @@ func TestCreate_InsertError(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
 
 	newUser := entities.User{}
 	expectedError := "insert error"
+	mock.ExpectBegin()
 	mock.ExpectQuery("INSERT INTO users").WillReturnError(errors.New(expectedError))
+	mock.ExpectRollback()
 
 	// Act
 	_, err := repo.Create(context.Background(), newUser)
@@ -75,6 +83,31 @@ func TestCreate_InsertError(t *testing.T) {
 	assert.Equal(t, expectedError, err.Error())
 }
 
+// TestCreate_EmailInUse checks that Create returns an AlreadyExistsErr including the email when it is already in use
+func TestCreate_EmailInUse(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	newUser := entities.User{EmailNormalized: "test@test.com"}
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO users").WillReturnError(&pq.Error{Code: "23505", Constraint: "email_normalized_unique"})
+	mock.ExpectRollback()
+
+	// Act
+	_, err := repo.Create(context.Background(), newUser)
+
+	// Assert
+	assert.ErrorIs(t, err, entities.AlreadyExistsErr)
+	assert.Equal(t, "email test@test.com is already in use", err.Error())
+}
+
 // TestGet_Ok checks that Get returns the expected response when a valid filter is received
 func TestGet_Ok(t *testing.T) {
 	// Arrange
@@ -82,7 +115,7 @@ func TestGet_Ok(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
@@ -90,11 +123,11 @@ func TestGet_Ok(t *testing.T) {
 	expectedUser := entities.User{
 		ID: "f8352727-231e-4de1-8257-c235a0af5c4a",
 	}
-	filter := map[string]interface{}{"email": "test-email", "name": "test-name"}
+	filter := models.UserFilter{EmailNormalized: "test-email", Name: "test-name"}
 	skip := 1
 	take := 1
-	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "password_hash", "claim_ids", "created_at", "updated_at"}).
-		AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Surnames, expectedUser.Email, expectedUser.PasswordHash, pq.Array(expectedUser.ClaimIDs), expectedUser.CreatedAt, expectedUser.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "email_normalized", "password_hash", "claim_ids", "avatar_url", "created_at", "updated_at"}).
+		AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Surnames, expectedUser.Email, expectedUser.EmailNormalized, expectedUser.PasswordHash, pq.Array(expectedUser.ClaimIDs), expectedUser.AvatarURL, expectedUser.CreatedAt, expectedUser.UpdatedAt))
 
 	// Act
 	result, err := repo.Get(context.Background(), filter, &skip, &take)
@@ -103,7 +136,7 @@ func TestGet_Ok(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, len(result) == 1)
 
-	entity := *(result[0].(*entities.User))
+	entity := result[0]
 	assert.Equal(t, expectedUser, entity)
 }
 
@@ -114,7 +147,7 @@ func TestGet_SelectError(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
@@ -122,7 +155,7 @@ func TestGet_SelectError(t *testing.T) {
 	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnError(errors.New(expectedError))
 
 	// Act
-	_, err := repo.Get(context.Background(), map[string]interface{}{}, nil, nil)
+	_, err := repo.Get(context.Background(), models.UserFilter{}, nil, nil)
 
 	// Assert
 	assert.Equal(t, expectedError, err.Error())
@@ -135,14 +168,14 @@ func TestGet_NoResourcesFound(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
-	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "password_hash", "claim_ids", "created_at", "updated_at"}))
+	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "email_normalized", "password_hash", "claim_ids", "avatar_url", "created_at", "updated_at"}))
 
 	// Act
-	_, err := repo.Get(context.Background(), map[string]interface{}{}, nil, nil)
+	_, err := repo.Get(context.Background(), models.UserFilter{}, nil, nil)
 
 	// Assert
 	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
@@ -155,7 +188,7 @@ func TestGetByID_Ok(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
@@ -163,8 +196,8 @@ func TestGetByID_Ok(t *testing.T) {
 	expectedUser := entities.User{
 		ID: "f8352727-231e-4de1-8257-c235a0af5c4a",
 	}
-	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "password_hash", "claim_ids", "created_at", "updated_at"}).
-		AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Surnames, expectedUser.Email, expectedUser.PasswordHash, pq.Array(expectedUser.ClaimIDs), expectedUser.CreatedAt, expectedUser.UpdatedAt))
+	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "email_normalized", "password_hash", "claim_ids", "avatar_url", "created_at", "updated_at"}).
+		AddRow(expectedUser.ID, expectedUser.Name, expectedUser.Surnames, expectedUser.Email, expectedUser.EmailNormalized, expectedUser.PasswordHash, pq.Array(expectedUser.ClaimIDs), expectedUser.AvatarURL, expectedUser.CreatedAt, expectedUser.UpdatedAt))
 
 	// Act
 	result, err := repo.GetByID(context.Background(), expectedUser.ID)
@@ -172,7 +205,7 @@ func TestGetByID_Ok(t *testing.T) {
 	// Assert
 	assert.Nil(t, err)
 
-	entity := *(result.(*entities.User))
+	entity := result
 	assert.Equal(t, expectedUser, entity)
 }
 
@@ -183,7 +216,7 @@ func TestGetByID_SelectError(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
@@ -204,11 +237,11 @@ func TestGetByID_ResourceNotFound(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
-	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "password_hash", "claim_ids", "created_at", "updated_at"}))
+	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "email_normalized", "password_hash", "claim_ids", "avatar_url", "created_at", "updated_at"}))
 
 	// Act
 	_, err := repo.GetByID(context.Background(), "")
@@ -217,6 +250,26 @@ func TestGetByID_ResourceNotFound(t *testing.T) {
 	assert.Equal(t, wrappers.NewNonExistentErr(sql.ErrNoRows), err)
 }
 
+// TestGetByID_MalformedID checks that GetByID returns a non existent error when the ID is not a valid UUID
+func TestGetByID_MalformedID(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnError(&pq.Error{Code: "22P02"})
+
+	// Act
+	_, err := repo.GetByID(context.Background(), "malformed-id")
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+}
+
 // TestUpdate_Ok checks that Update does not return an error when the received ID has a valid format
 func TestUpdate_Ok(t *testing.T) {
 	// Arrange
@@ -224,12 +277,15 @@ func TestUpdate_Ok(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO outbox_events").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
 	// Act
 	err := repo.Update(context.Background(), "", entities.User{})
@@ -245,13 +301,15 @@ func TestUpdate_UpdateError(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
 
 	expectedError := "update error"
+	mock.ExpectBegin()
 	mock.ExpectExec("UPDATE users").WillReturnError(errors.New(expectedError))
+	mock.ExpectRollback()
 
 	// Act
 	err := repo.Update(context.Background(), "", entities.User{})
@@ -260,6 +318,30 @@ func TestUpdate_UpdateError(t *testing.T) {
 	assert.Equal(t, expectedError, err.Error())
 }
 
+// TestUpdate_EmailInUse checks that Update returns an AlreadyExistsErr including the email when it is already in use
+func TestUpdate_EmailInUse(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE users").WillReturnError(&pq.Error{Code: "23505", Constraint: "email_normalized_unique"})
+	mock.ExpectRollback()
+
+	// Act
+	err := repo.Update(context.Background(), "", entities.User{EmailNormalized: "test@test.com"})
+
+	// Assert
+	assert.ErrorIs(t, err, entities.AlreadyExistsErr)
+	assert.Equal(t, "email test@test.com is already in use", err.Error())
+}
+
 // TestUpdate_NotUpdatedError checks that Update returns an error when the update statement does not update any document
 func TestUpdate_NotUpdatedError(t *testing.T) {
 	// Arrange
@@ -267,12 +349,14 @@ func TestUpdate_NotUpdatedError(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(1, 0))
+	mock.ExpectRollback()
 
 	// Act
 	err := repo.Update(context.Background(), "", entities.User{})
@@ -288,12 +372,15 @@ func TestDelete_Ok(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectExec("DELETE FROM users").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO outbox_events").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
 	// Act
 	err := repo.Delete(context.Background(), "")
@@ -309,13 +396,15 @@ func TestDelte_DeleteError(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
 
 	expectedError := "delete error"
+	mock.ExpectBegin()
 	mock.ExpectExec("DELETE FROM users").WillReturnError(errors.New(expectedError))
+	mock.ExpectRollback()
 
 	// Act
 	err := repo.Delete(context.Background(), "")
@@ -331,12 +420,14 @@ func TestDelete_NotDeletedError(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectExec("DELETE FROM users").WillReturnResult(sqlmock.NewResult(1, 0))
+	mock.ExpectRollback()
 
 	// Act
 	err := repo.Delete(context.Background(), "")
@@ -352,15 +443,16 @@ func TestCreateMany_Ok(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
 
-	newUsers := []interface{}{entities.User{}}
+	newUsers := []entities.User{{}}
 	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
 	mock.ExpectBegin()
 	mock.ExpectQuery("INSERT INTO users").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
+	mock.ExpectExec("INSERT INTO outbox_events").WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectCommit()
 
 	// Act
@@ -379,12 +471,12 @@ func TestCreateMany_InsertError(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
 
-	newUsers := []interface{}{entities.User{}}
+	newUsers := []entities.User{{}}
 	expectedError := "insert error"
 	mock.ExpectBegin()
 	mock.ExpectQuery("INSERT INTO users").WillReturnError(errors.New(expectedError))
@@ -403,12 +495,12 @@ func TestCreateMany_BeginError(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
 
-	newUsers := []interface{}{entities.User{}}
+	newUsers := []entities.User{{}}
 	expectedError := "begin error"
 	mock.ExpectBegin().WillReturnError(errors.New(expectedError))
 
@@ -426,16 +518,17 @@ func TestCreateMany_CommitError(t *testing.T) {
 	defer db.Close()
 
 	repo := &userRepository{
-		infrastructure.PostgresRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
 			DB: db,
 		},
 	}
 
-	newUsers := []interface{}{entities.User{}}
+	newUsers := []entities.User{{}}
 	expectedID := "f8352727-231e-4de1-8257-c235a0af5c4a"
 	expectedError := "commit error"
 	mock.ExpectBegin()
 	mock.ExpectQuery("INSERT INTO users").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
+	mock.ExpectExec("INSERT INTO outbox_events").WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectCommit().WillReturnError(errors.New(expectedError))
 
 	// Act
@@ -444,3 +537,313 @@ func TestCreateMany_CommitError(t *testing.T) {
 	// Assert
 	assert.Equal(t, expectedError, err.Error())
 }
+
+// TestUpdateMany_Ok checks that UpdateMany does not return an error when everything goes as expected
+func TestUpdateMany_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO outbox_events").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.UpdateMany(context.Background(), []string{"test-id"}, []entities.User{{}})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdateMany_NotUpdatedError checks that UpdateMany rolls back and returns an error when one of the users does not exist
+func TestUpdateMany_NotUpdatedError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO outbox_events").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE users").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	// Act
+	err := repo.UpdateMany(context.Background(), []string{"test-id", "non-existent-id"}, []entities.User{{}, {}})
+
+	// Assert
+	assert.Equal(t, wrappers.NewNonExistentErr(errors.New("ID non-existent-id not found")), err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestUpdateMany_BeginError checks that UpdateMany returns an error when the begin statement fails
+func TestUpdateMany_BeginError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "begin error"
+	mock.ExpectBegin().WillReturnError(errors.New(expectedError))
+
+	// Act
+	err := repo.UpdateMany(context.Background(), []string{"test-id"}, []entities.User{{}})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestDeleteMany_Ok checks that DeleteMany does not return an error when everything goes as expected
+func TestDeleteMany_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM users").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO outbox_events").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM users").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO outbox_events").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.DeleteMany(context.Background(), []string{"id-1", "id-2"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestDeleteMany_DeleteError checks that DeleteMany rolls back and returns an error when the delete statement fails
+func TestDeleteMany_DeleteError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "delete error"
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM users").WillReturnError(errors.New(expectedError))
+	mock.ExpectRollback()
+
+	// Act
+	err := repo.DeleteMany(context.Background(), []string{"test-id"})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestIterate_Ok checks that Iterate calls the given function with every user matching the filter
+func TestIterate_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	claimID := int32(0)
+	filter := models.UserFilter{
+		CreatedFrom: time.Now().Add(-time.Hour),
+		CreatedTo:   time.Now(),
+		ClaimID:     &claimID,
+	}
+	expectedUsers := []entities.User{
+		{ID: "f8352727-231e-4de1-8257-c235a0af5c4a"},
+		{ID: "a4e2f0c1-4c7b-4a3e-9a58-3d1c8f1b6e2d"},
+	}
+	rows := sqlmock.NewRows([]string{"id", "name", "surnames", "email", "email_normalized", "password_hash", "claim_ids", "avatar_url", "created_at", "updated_at"})
+	for _, u := range expectedUsers {
+		rows.AddRow(u.ID, u.Name, u.Surnames, u.Email, u.EmailNormalized, u.PasswordHash, pq.Array(u.ClaimIDs), u.AvatarURL, u.CreatedAt, u.UpdatedAt)
+	}
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE created_at >= \\$1 AND created_at < \\$2 AND \\$3 = ANY\\(claim_ids\\) ORDER BY id LIMIT \\$4").
+		WithArgs(filter.CreatedFrom, filter.CreatedTo, claimID, userIteratePageSize).
+		WillReturnRows(rows)
+
+	var users []entities.User
+
+	// Act
+	err := repo.Iterate(context.Background(), filter, func(user entities.User) error {
+		users = append(users, user)
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedUsers, users)
+}
+
+// TestIterate_CallbackError checks that Iterate stops and returns the error of the given function
+func TestIterate_CallbackError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "callback error"
+	mock.ExpectQuery("SELECT (.+) FROM users ORDER BY id LIMIT \\$1").WithArgs(userIteratePageSize).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "email_normalized", "password_hash", "claim_ids", "avatar_url", "created_at", "updated_at"}).
+		AddRow("id-1", "", "", "", "", "", pq.Array([]int32{}), "", time.Time{}, time.Time{}).
+		AddRow("id-2", "", "", "", "", "", pq.Array([]int32{}), "", time.Time{}, time.Time{}))
+
+	calls := 0
+
+	// Act
+	err := repo.Iterate(context.Background(), models.UserFilter{}, func(user entities.User) error {
+		calls++
+		return errors.New(expectedError)
+	})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	assert.Equal(t, 1, calls)
+}
+
+// TestIterate_Pages checks that Iterate reads the next page after the ID of the last user of a full page, until a page is not full
+func TestIterate_Pages(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	columns := []string{"id", "name", "surnames", "email", "email_normalized", "password_hash", "claim_ids", "avatar_url", "created_at", "updated_at"}
+	firstPage := sqlmock.NewRows(columns)
+	for i := 0; i < userIteratePageSize; i++ {
+		firstPage.AddRow(fmt.Sprintf("id-%04d", i), "test", "", "", "", "", pq.Array([]int32{}), "", time.Time{}, time.Time{})
+	}
+	lastID := fmt.Sprintf("id-%04d", userIteratePageSize-1)
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE name = \\$1 ORDER BY id LIMIT \\$2").
+		WithArgs("test", userIteratePageSize).
+		WillReturnRows(firstPage)
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE name = \\$1 AND id > \\$2 ORDER BY id LIMIT \\$3").
+		WithArgs("test", lastID, userIteratePageSize).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("id-last", "test", "", "", "", "", pq.Array([]int32{}), "", time.Time{}, time.Time{}))
+
+	var IDs []string
+
+	// Act
+	err := repo.Iterate(context.Background(), models.UserFilter{Name: "test"}, func(user entities.User) error {
+		IDs = append(IDs, user.ID)
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, IDs, userIteratePageSize+1)
+	assert.Equal(t, "id-last", IDs[len(IDs)-1])
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// TestIterate_SelectError checks that Iterate returns an error when the select query fails
+func TestIterate_SelectError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "select error"
+	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnError(errors.New(expectedError))
+
+	// Act
+	err := repo.Iterate(context.Background(), models.UserFilter{}, func(user entities.User) error { return nil })
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestSearch_Ok checks that Search returns the users matched by the full-text or the fuzzy search, in the returned order
+func TestSearch_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE (.+) @@ plainto_tsquery(.+) OR search_normalize\\(\\$1\\) <% (.+) ORDER BY (.+) OFFSET \\$2 LIMIT \\$3").
+		WithArgs("jose", 20, 11).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surnames", "email", "email_normalized", "password_hash", "claim_ids", "avatar_url", "created_at", "updated_at"}).
+			AddRow("id-1", "José", "", "", "", "", pq.Array([]int32{}), "", time.Time{}, time.Time{}).
+			AddRow("id-2", "Josefa", "", "", "", "", pq.Array([]int32{}), "", time.Time{}, time.Time{}))
+
+	// Act
+	users, err := repo.Search(context.Background(), "jose", 20, 11)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "id-1", users[0].ID)
+	assert.Equal(t, "Josefa", users[1].Name)
+}
+
+// TestSearch_SelectError checks that Search returns an error when the select query fails
+func TestSearch_SelectError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &userRepository{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "select error"
+	mock.ExpectQuery("SELECT (.+) FROM users").WillReturnError(errors.New(expectedError))
+
+	// Act
+	_, err := repo.Search(context.Background(), "jose", 0, 10)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
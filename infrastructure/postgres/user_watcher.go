@@ -0,0 +1,337 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+const (
+	// userChangesChannel channel notified by the users_notify_change trigger with the sequence number of every change
+	userChangesChannel = "user_changes"
+	// userChangesBatchSize maximum number of changes read at once
+	userChangesBatchSize = 100
+	// userChangesPollInterval interval at which changes are read even without notifications, in case any got lost
+	userChangesPollInterval = 30 * time.Second
+	// userChangesPendingInterval interval at which changes are read while some are held back by an earlier transaction in progress
+	userChangesPendingInterval = time.Second
+)
+
+// listener subset of a pq.Listener
+type listener interface {
+	Listen(channel string) error
+	NotificationChannel() <-chan *pq.Notification
+	Close() error
+}
+
+// userWatcher adapter of a user watcher for postgres, based on LISTEN/NOTIFY.
+// The resume tokens are the positions of the changes of the user_changes table, written by a trigger on the users table.
+type userWatcher struct {
+	infrastructure.PostgresRepository
+	newListener func() listener
+}
+
+// NewUserWatcher creates a user watcher for postgres.
+// Every watch listens to the notifications on its own connection to the DSN, as they cannot be received through a pooled connection.
+func NewUserWatcher(db *sql.DB, dsn string) ports.UserWatcher {
+	return &userWatcher{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		newListener: func() listener {
+			return pq.NewListener(dsn, time.Second, time.Minute, nil)
+		},
+	}
+}
+
+func (w *userWatcher) Watch(ctx context.Context, resumeToken string, fn func(change entities.UserChange) error) error {
+	l := w.newListener()
+	defer l.Close()
+
+	// listening starts before reading the changes, so that the ones committed in between are not missed
+	if err := l.Listen(userChangesChannel); err != nil {
+		return err
+	}
+
+	position, err := w.resumePosition(ctx, resumeToken)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(userChangesPollInterval)
+	defer ticker.Stop()
+
+	for {
+		var pending bool
+		position, pending, err = w.changesAfter(ctx, position, fn)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		// the transactions holding back changes do not notify when they end unless they changed users
+		var retry <-chan time.Time
+		if pending {
+			retry = time.After(userChangesPendingInterval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-l.NotificationChannel():
+		case <-ticker.C:
+		case <-retry:
+		}
+	}
+}
+
+// Prune deletes the changes up to the last one that occurred before the given time, so that the remaining ones keep
+// following each other in order, and records the position of the last deleted one.
+// Only the changes that watchers can read are deleted, so that the ones of the transactions in progress always come after the recorded position.
+func (w *userWatcher) Prune(ctx context.Context, before time.Time) (int, error) {
+	q := `
+	WITH last AS (
+	    SELECT xid, seq FROM user_changes
+	        WHERE occurred_at < $1 AND xid < pg_snapshot_xmin(pg_current_snapshot())
+	        ORDER BY xid DESC, seq DESC LIMIT 1
+	), pruned AS (
+	    DELETE FROM user_changes c USING last
+	        WHERE (c.xid, c.seq) <= (last.xid, last.seq)
+	        RETURNING c.seq
+	), recorded AS (
+	    UPDATE user_changes_pruned p SET xid = last.xid, seq = last.seq FROM last
+	        WHERE (last.xid, last.seq) > (p.xid, p.seq)
+	)
+	SELECT COUNT(*) FROM pruned;
+	`
+
+	var pruned int
+	err := w.DB.QueryRowContext(ctx, q, before).Scan(&pruned)
+	return pruned, err
+}
+
+// userChangePosition position of a change, following the order of the transactions that made the changes and then their sequence numbers
+type userChangePosition struct {
+	xid uint64
+	seq int64
+}
+
+// after reports whether the position comes after the given one
+func (p userChangePosition) after(other userChangePosition) bool {
+	return p.xid > other.xid || p.xid == other.xid && p.seq > other.seq
+}
+
+// String returns the resume token of the position
+func (p userChangePosition) String() string {
+	return fmt.Sprintf("%d-%d", p.xid, p.seq)
+}
+
+// parseUserChangePosition parses a resume token, where a single sequence number is the position of a change recorded before
+// the changes were ordered by transaction, all of which come first
+func parseUserChangePosition(resumeToken string) (userChangePosition, error) {
+	var position userChangePosition
+	xid, seq, found := strings.Cut(resumeToken, "-")
+	if !found {
+		xid, seq = "0", resumeToken
+	}
+
+	var err error
+	position.xid, err = strconv.ParseUint(xid, 10, 64)
+	if err != nil {
+		return userChangePosition{}, err
+	}
+	position.seq, err = strconv.ParseInt(seq, 10, 64)
+	if err != nil || position.seq < 0 {
+		return userChangePosition{}, fmt.Errorf("sequence number %s is not valid", seq)
+	}
+	return position, nil
+}
+
+// resumePosition returns the position of the resume token, or when empty the one before the changes of the transactions that are still in progress
+func (w *userWatcher) resumePosition(ctx context.Context, resumeToken string) (userChangePosition, error) {
+	if resumeToken == "" {
+		var xmin string
+		err := w.DB.QueryRowContext(ctx, `SELECT pg_snapshot_xmin(pg_current_snapshot())::text;`).Scan(&xmin)
+		if err != nil {
+			return userChangePosition{}, err
+		}
+		return parseUserChangePosition(xmin + "-0")
+	}
+
+	position, err := parseUserChangePosition(resumeToken)
+	if err != nil {
+		return userChangePosition{}, wrappers.NewValidationErr(fmt.Errorf("resume token %s is not valid", resumeToken))
+	}
+	return position, nil
+}
+
+// changesAfter calls fn for every change after the given position, and returns the position of the last one.
+// It also reports whether there are later changes held back by an earlier transaction that is still in progress.
+func (w *userWatcher) changesAfter(ctx context.Context, position userChangePosition, fn func(change entities.UserChange) error) (userChangePosition, bool, error) {
+	for {
+		changes, pending, err := w.readChanges(ctx, position)
+		if err != nil {
+			return position, false, err
+		}
+		if err = w.checkPruned(ctx, position, changes); err != nil {
+			return position, false, err
+		}
+
+		for _, change := range changes {
+			if err = fn(change); err != nil {
+				return position, false, err
+			}
+			position, _ = parseUserChangePosition(change.ResumeToken)
+		}
+
+		if pending || len(changes) < userChangesBatchSize {
+			return position, pending, nil
+		}
+	}
+}
+
+// checkPruned returns a validation error when the changes after the position may have been pruned before being read,
+// which is when the last pruned change comes after it and the read changes do not start before the last pruned one
+func (w *userWatcher) checkPruned(ctx context.Context, position userChangePosition, changes []entities.UserChange) error {
+	var xid string
+	var pruned userChangePosition
+	if err := w.DB.QueryRowContext(ctx, `SELECT xid::text, seq FROM user_changes_pruned;`).Scan(&xid, &pruned.seq); err != nil {
+		return err
+	}
+	pruned.xid, _ = strconv.ParseUint(xid, 10, 64)
+	if !pruned.after(position) {
+		return nil
+	}
+
+	if len(changes) > 0 {
+		first, _ := parseUserChangePosition(changes[0].ResumeToken)
+		if !first.after(pruned) {
+			return nil
+		}
+	}
+	return wrappers.NewValidationErr(fmt.Errorf("resume token %s has expired", position))
+}
+
+// userChangeColumns columns of the changes read along with the state of the users after them
+const userChangeColumns = `c.xid::text, c.seq, c.xid < pg_snapshot_xmin(pg_current_snapshot()), c.type, c.user_id, c.occurred_at,
+	       c.name, c.surnames, c.email, c.email_normalized, c.claim_ids, c.avatar_url, c.created_at, c.updated_at`
+
+// readChanges reads a batch of changes, along with the state of the changed users after every change, stopping at the first one
+// made by a transaction that is not older than every transaction in progress, as an earlier one could still add changes before it.
+// The rows are read before calling any callback, so that no connection is held while the changes are being sent.
+func (w *userWatcher) readChanges(ctx context.Context, position userChangePosition) ([]entities.UserChange, bool, error) {
+	q := `
+	SELECT ` + userChangeColumns + `
+	    FROM user_changes c
+	    WHERE (c.xid, c.seq) > ($1::xid8, $2) ORDER BY c.xid, c.seq LIMIT $3;
+	`
+
+	rows, err := w.DB.QueryContext(ctx, q, strconv.FormatUint(position.xid, 10), position.seq, userChangesBatchSize)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer rows.Close()
+
+	var changes []entities.UserChange
+	for rows.Next() {
+		change, readable, err := scanUserChange(rows)
+		if err != nil {
+			return nil, false, err
+		}
+		if !readable {
+			return changes, true, rows.Close()
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, false, rows.Err()
+}
+
+// GetByUserID returns the changes of the user still recorded, including the ones of the transactions in progress
+func (w *userWatcher) GetByUserID(ctx context.Context, userID string) ([]entities.UserChange, error) {
+	q := `
+	SELECT ` + userChangeColumns + `
+	    FROM user_changes c
+	    WHERE c.user_id = $1 ORDER BY c.xid, c.seq;
+	`
+
+	rows, err := conn(ctx, w.DB).QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var changes []entities.UserChange
+	for rows.Next() {
+		change, _, err := scanUserChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// EraseStates clears the states of the user recorded along with its changes, as the trigger does when the user is deleted
+func (w *userWatcher) EraseStates(ctx context.Context, userID string) error {
+	q := `
+	UPDATE user_changes
+	    SET name = NULL, surnames = NULL, email = NULL, email_normalized = NULL,
+	        claim_ids = NULL, avatar_url = NULL, created_at = NULL, updated_at = NULL
+	    WHERE user_id = $1;
+	`
+
+	_, err := conn(ctx, w.DB).ExecContext(ctx, q, userID)
+	return err
+}
+
+// scanUserChange scans a change read with userChangeColumns, reporting whether watchers can read it already
+func scanUserChange(rows *sql.Rows) (entities.UserChange, bool, error) {
+	var c entities.UserChange
+	var xid string
+	var position userChangePosition
+	var readable bool
+	var name, surnames, email, emailNormalized, avatarURL sql.NullString
+	var claimIDs []int32
+	var createdAt, updatedAt sql.NullTime
+	err := rows.Scan(&xid, &position.seq, &readable, &c.Type, &c.UserID, &c.OccurredAt, &name, &surnames, &email, &emailNormalized, pq.Array(&claimIDs), &avatarURL, &createdAt, &updatedAt)
+	if err != nil {
+		return entities.UserChange{}, false, err
+	}
+
+	position.xid, err = strconv.ParseUint(xid, 10, 64)
+	if err != nil {
+		return entities.UserChange{}, false, err
+	}
+
+	c.ResumeToken = position.String()
+	// the changes of a deleted or erased user have no state
+	if email.Valid {
+		c.User = &entities.User{
+			ID:              c.UserID,
+			Name:            name.String,
+			Surnames:        surnames.String,
+			Email:           email.String,
+			EmailNormalized: emailNormalized.String,
+			ClaimIDs:        claimIDs,
+			AvatarURL:       avatarURL.String,
+			CreatedAt:       createdAt.Time,
+			UpdatedAt:       updatedAt.Time,
+		}
+	}
+	return c, readable, nil
+}
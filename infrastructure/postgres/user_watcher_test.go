@@ -0,0 +1,439 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+var userChangesColumns = []string{"xid", "seq", "readable", "type", "user_id", "occurred_at", "name", "surnames", "email", "email_normalized", "claim_ids", "avatar_url", "created_at", "updated_at"}
+
+var userChangesPrunedColumns = []string{"xid", "seq"}
+
+// TestNewUserWatcher_Ok checks that NewUserWatcher creates a new userWatcher struct
+func TestNewUserWatcher_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	watcher := NewUserWatcher(db, "test-dsn")
+
+	// Assert
+	assert.NotEmpty(t, watcher)
+}
+
+// TestWatch_Ok checks that Watch listens to the changes channel and calls fn for the changes after the resume token, with the state of the users after them
+func TestWatch_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	l := &listenerMock{notifications: make(chan *pq.Notification, 1)}
+	watcher := &userWatcher{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		newListener: func() listener { return l },
+	}
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM user_changes c WHERE \(c.xid, c.seq\) > \(\$1::xid8, \$2\) ORDER BY c.xid, c.seq LIMIT \$3`).
+		WithArgs("100", 5, userChangesBatchSize).
+		WillReturnRows(sqlmock.NewRows(userChangesColumns).
+			AddRow("100", 6, true, "updated", "test-id", now, "test", "test", "test@test.com", "test@test.com", "{0}", "", now, now).
+			AddRow("101", 2, true, "deleted", "test-id-2", now, nil, nil, nil, nil, nil, nil, nil, nil))
+	mock.ExpectQuery("SELECT xid::text, seq FROM user_changes_pruned").WillReturnRows(sqlmock.NewRows(userChangesPrunedColumns).AddRow("0", 0))
+	mock.ExpectQuery("SELECT (.+) FROM user_changes").
+		WithArgs("101", 2, userChangesBatchSize).
+		WillReturnRows(sqlmock.NewRows(userChangesColumns))
+	mock.ExpectQuery("SELECT xid::text, seq FROM user_changes_pruned").WillReturnRows(sqlmock.NewRows(userChangesPrunedColumns).AddRow("0", 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var changes []entities.UserChange
+	fn := func(change entities.UserChange) error {
+		changes = append(changes, change)
+		if len(changes) == 2 {
+			l.notifications <- &pq.Notification{Extra: "8"}
+		}
+		return nil
+	}
+
+	// Act
+	go func() {
+		for mock.ExpectationsWereMet() != nil {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+	err := watcher.Watch(ctx, "100-5", fn)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, userChangesChannel, l.channel)
+	assert.True(t, l.closed)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, "100-6", changes[0].ResumeToken)
+	assert.Equal(t, "test-id", changes[0].User.ID)
+	assert.Equal(t, entities.UserChangeTypeUpdated, changes[0].Type)
+	assert.Equal(t, "test@test.com", changes[0].User.Email)
+	assert.Equal(t, []int32{0}, changes[0].User.ClaimIDs)
+	assert.Equal(t, "101-2", changes[1].ResumeToken)
+	assert.Equal(t, "test-id-2", changes[1].UserID)
+	assert.Nil(t, changes[1].User)
+}
+
+// TestWatch_EmptyResumeToken checks that Watch starts before the changes of the transactions in progress when no resume token is received
+func TestWatch_EmptyResumeToken(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	watcher := &userWatcher{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		newListener: func() listener { return &listenerMock{} },
+	}
+
+	mock.ExpectQuery(`SELECT pg_snapshot_xmin\(pg_current_snapshot\(\)\)::text`).WillReturnRows(sqlmock.NewRows([]string{"xmin"}).AddRow("100"))
+	mock.ExpectQuery("SELECT (.+) FROM user_changes").
+		WithArgs("100", 0, userChangesBatchSize).
+		WillReturnRows(sqlmock.NewRows(userChangesColumns))
+	mock.ExpectQuery("SELECT xid::text, seq FROM user_changes_pruned").WillReturnRows(sqlmock.NewRows(userChangesPrunedColumns).AddRow("0", 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for mock.ExpectationsWereMet() != nil {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+
+	// Act
+	err := watcher.Watch(ctx, "", func(change entities.UserChange) error {
+		return errors.New("no change expected")
+	})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestWatch_InvalidResumeToken checks that Watch returns a validation error when the resume token is not a sequence number
+func TestWatch_InvalidResumeToken(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	watcher := &userWatcher{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		newListener: func() listener { return &listenerMock{} },
+	}
+
+	// Act
+	err := watcher.Watch(context.Background(), "invalid", nil)
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "resume token invalid is not valid", err.Error())
+}
+
+// TestWatch_ListenError checks that Watch returns an error when listening to the changes channel fails
+func TestWatch_ListenError(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	expectedError := "listen error"
+	l := &listenerMock{err: errors.New(expectedError)}
+	watcher := &userWatcher{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		newListener: func() listener { return l },
+	}
+
+	// Act
+	err := watcher.Watch(context.Background(), "", nil)
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+	assert.True(t, l.closed)
+}
+
+// TestWatch_CallbackError checks that Watch returns the error of fn, stopping the watch
+func TestWatch_CallbackError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	watcher := &userWatcher{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		newListener: func() listener { return &listenerMock{} },
+	}
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM user_changes").
+		WithArgs("0", 0, userChangesBatchSize).
+		WillReturnRows(sqlmock.NewRows(userChangesColumns).
+			AddRow("100", 1, true, "created", "test-id", now, "test", "test", "test@test.com", "test@test.com", "{}", "", now, now))
+	mock.ExpectQuery("SELECT xid::text, seq FROM user_changes_pruned").WillReturnRows(sqlmock.NewRows(userChangesPrunedColumns).AddRow("0", 0))
+
+	expectedError := "send error"
+
+	// Act
+	err := watcher.Watch(context.Background(), "0-0", func(change entities.UserChange) error {
+		return errors.New(expectedError)
+	})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestWatch_PrunedResumeToken checks that Watch returns a validation error when the changes after the resume token have been pruned
+func TestWatch_PrunedResumeToken(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	watcher := &userWatcher{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		newListener: func() listener { return &listenerMock{} },
+	}
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM user_changes").
+		WithArgs("100", 5, userChangesBatchSize).
+		WillReturnRows(sqlmock.NewRows(userChangesColumns).
+			AddRow("101", 1, true, "created", "test-id", now, "test", "test", "test@test.com", "test@test.com", "{}", "", now, now))
+	mock.ExpectQuery("SELECT xid::text, seq FROM user_changes_pruned").WillReturnRows(sqlmock.NewRows(userChangesPrunedColumns).AddRow("100", 10))
+
+	// Act
+	err := watcher.Watch(context.Background(), "100-5", func(change entities.UserChange) error {
+		return errors.New("no change expected")
+	})
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.ValidationErr))
+	assert.Equal(t, "resume token 100-5 has expired", err.Error())
+}
+
+// TestWatch_PrunedAfterRead checks that Watch calls fn for the changes read before the ones after the resume token got pruned
+func TestWatch_PrunedAfterRead(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	watcher := &userWatcher{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		newListener: func() listener { return &listenerMock{} },
+	}
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM user_changes").
+		WithArgs("100", 5, userChangesBatchSize).
+		WillReturnRows(sqlmock.NewRows(userChangesColumns).
+			AddRow("100", 6, true, "created", "test-id", now, "test", "test", "test@test.com", "test@test.com", "{}", "", now, now))
+	mock.ExpectQuery("SELECT xid::text, seq FROM user_changes_pruned").WillReturnRows(sqlmock.NewRows(userChangesPrunedColumns).AddRow("100", 6))
+
+	expectedError := "send error"
+
+	// Act
+	err := watcher.Watch(context.Background(), "100-5", func(change entities.UserChange) error {
+		return errors.New(expectedError)
+	})
+
+	// Assert
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestWatch_Pending checks that Watch stops before the changes held back by a transaction in progress, and reads them again shortly without notifications
+func TestWatch_Pending(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	watcher := &userWatcher{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		newListener: func() listener { return &listenerMock{} },
+	}
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM user_changes").
+		WithArgs("100", 5, userChangesBatchSize).
+		WillReturnRows(sqlmock.NewRows(userChangesColumns).
+			AddRow("100", 6, true, "created", "test-id", now, "test", "test", "test@test.com", "test@test.com", "{}", "", now, now).
+			AddRow("102", 7, false, "created", "test-id-2", now, "test", "test", "test2@test.com", "test2@test.com", "{}", "", now, now))
+	mock.ExpectQuery("SELECT xid::text, seq FROM user_changes_pruned").WillReturnRows(sqlmock.NewRows(userChangesPrunedColumns).AddRow("0", 0))
+	mock.ExpectQuery("SELECT (.+) FROM user_changes").
+		WithArgs("100", 6, userChangesBatchSize).
+		WillReturnRows(sqlmock.NewRows(userChangesColumns).
+			AddRow("101", 3, true, "updated", "test-id", now, "test", "test", "test@test.com", "test@test.com", "{}", "", now, now).
+			AddRow("102", 7, true, "created", "test-id-2", now, "test", "test", "test2@test.com", "test2@test.com", "{}", "", now, now))
+	mock.ExpectQuery("SELECT xid::text, seq FROM user_changes_pruned").WillReturnRows(sqlmock.NewRows(userChangesPrunedColumns).AddRow("0", 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var tokens []string
+	fn := func(change entities.UserChange) error {
+		tokens = append(tokens, change.ResumeToken)
+		if len(tokens) == 3 {
+			cancel()
+		}
+		return nil
+	}
+
+	// Act
+	err := watcher.Watch(ctx, "100-5", fn)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"100-6", "101-3", "102-7"}, tokens)
+}
+
+// TestWatch_SequenceResumeToken checks that Watch resumes after a resume token made of a single sequence number, from before the changes were ordered by transaction
+func TestWatch_SequenceResumeToken(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	watcher := &userWatcher{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		newListener: func() listener { return &listenerMock{} },
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM user_changes").
+		WithArgs("0", 5, userChangesBatchSize).
+		WillReturnRows(sqlmock.NewRows(userChangesColumns))
+	mock.ExpectQuery("SELECT xid::text, seq FROM user_changes_pruned").WillReturnRows(sqlmock.NewRows(userChangesPrunedColumns).AddRow("0", 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for mock.ExpectationsWereMet() != nil {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+
+	// Act
+	err := watcher.Watch(ctx, "5", func(change entities.UserChange) error {
+		return errors.New("no change expected")
+	})
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestPrune_Ok checks that Prune deletes the readable changes up to the last one before the given time and returns how many got deleted
+func TestPrune_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	watcher := NewUserWatcher(db, "test-dsn")
+	before := time.Now().UTC()
+
+	mock.ExpectQuery(`WITH last AS \( SELECT xid, seq FROM user_changes WHERE occurred_at < \$1 AND xid < pg_snapshot_xmin\(pg_current_snapshot\(\)\) ORDER BY xid DESC, seq DESC LIMIT 1 \), pruned AS \( DELETE FROM user_changes c USING last WHERE \(c.xid, c.seq\) <= \(last.xid, last.seq\) RETURNING c.seq \), recorded AS \( UPDATE user_changes_pruned (.+) \) SELECT COUNT\(\*\) FROM pruned`).
+		WithArgs(before).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	// Act
+	pruned, err := watcher.Prune(context.Background(), before)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 3, pruned)
+}
+
+// TestUserChangesGetByUserID_Ok checks that GetByUserID returns the recorded changes of the user along with the states they left
+func TestUserChangesGetByUserID_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	watcher := NewUserWatcher(db, "test-dsn")
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM user_changes c WHERE c.user_id = \$1 ORDER BY c.xid, c.seq`).
+		WithArgs("test-id").
+		WillReturnRows(sqlmock.NewRows(userChangesColumns).
+			AddRow("100", 1, true, "created", "test-id", now, "test", "test", "test@test.com", "test@test.com", "{}", "", now, now).
+			AddRow("101", 2, false, "updated", "test-id", now, nil, nil, nil, nil, nil, nil, nil, nil))
+
+	// Act
+	changes, err := watcher.GetByUserID(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, "100-1", changes[0].ResumeToken)
+		assert.Equal(t, "test@test.com", changes[0].User.Email)
+		assert.Equal(t, "101-2", changes[1].ResumeToken)
+		assert.Nil(t, changes[1].User)
+	}
+}
+
+// TestUserChangesEraseStates_Ok checks that EraseStates clears the states recorded along with the changes of the user
+func TestUserChangesEraseStates_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	watcher := NewUserWatcher(db, "test-dsn")
+
+	mock.ExpectExec(`UPDATE user_changes SET name = NULL, (.+) WHERE user_id = \$1`).
+		WithArgs("test-id").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	// Act
+	err := watcher.EraseStates(context.Background(), "test-id")
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// listenerMock listener that records the listened channel and delivers the given notifications
+type listenerMock struct {
+	notifications chan *pq.Notification
+	err           error
+	channel       string
+	closed        bool
+}
+
+func (l *listenerMock) Listen(channel string) error {
+	l.channel = channel
+	return l.err
+}
+
+func (l *listenerMock) NotificationChannel() <-chan *pq.Notification {
+	return l.notifications
+}
+
+func (l *listenerMock) Close() error {
+	l.closed = true
+	return nil
+}
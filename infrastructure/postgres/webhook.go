@@ -0,0 +1,278 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// webhookSubscriptionRepository adapter of a webhook subscription repository for postgres
+type webhookSubscriptionRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewWebhookSubscriptionRepository creates a webhook subscription repository for postgres
+func NewWebhookSubscriptionRepository(db *sql.DB) ports.WebhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *webhookSubscriptionRepository) Create(ctx context.Context, subscription entities.WebhookSubscription) (string, error) {
+	q := `
+	INSERT INTO webhook_subscriptions (url, event_types, secret, created_at, updated_at)
+	    VALUES ($1, $2, $3, $4, $5)
+	    RETURNING id;
+	`
+
+	row := conn(ctx, r.DB).QueryRowContext(
+		ctx, q, subscription.URL, pq.Array(subscription.EventTypes), subscription.Secret, subscription.CreatedAt, subscription.UpdatedAt,
+	)
+
+	err := row.Scan(&subscription.ID)
+	if err != nil {
+		return "", err
+	}
+
+	return subscription.ID, nil
+}
+
+func (r *webhookSubscriptionRepository) GetAll(ctx context.Context) ([]entities.WebhookSubscription, error) {
+	q := `
+	SELECT id, url, event_types, secret, created_at, updated_at
+	    FROM webhook_subscriptions ORDER BY created_at;
+	`
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	subscriptions := []entities.WebhookSubscription{}
+	for rows.Next() {
+		var s entities.WebhookSubscription
+		err = rows.Scan(&s.ID, &s.URL, pq.Array(&s.EventTypes), &s.Secret, &s.CreatedAt, &s.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *webhookSubscriptionRepository) GetByID(ctx context.Context, ID string) (entities.WebhookSubscription, error) {
+	q := `
+	SELECT id, url, event_types, secret, created_at, updated_at
+	    FROM webhook_subscriptions WHERE id = $1;
+	`
+
+	row := conn(ctx, r.DB).QueryRowContext(ctx, q, ID)
+
+	var s entities.WebhookSubscription
+	err := row.Scan(&s.ID, &s.URL, pq.Array(&s.EventTypes), &s.Secret, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return entities.WebhookSubscription{}, err
+	}
+
+	return s, nil
+}
+
+func (r *webhookSubscriptionRepository) Update(ctx context.Context, ID string, subscription entities.WebhookSubscription) error {
+	q := `
+	UPDATE webhook_subscriptions SET url=$1, event_types=$2, secret=$3, updated_at=$4
+	    WHERE id=$5;
+	`
+
+	result, err := conn(ctx, r.DB).ExecContext(
+		ctx, q, subscription.URL, pq.Array(subscription.EventTypes), subscription.Secret, subscription.UpdatedAt, ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(result)
+}
+
+func (r *webhookSubscriptionRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM webhook_subscriptions WHERE id=$1;`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(result)
+}
+
+// webhookDeliveryRepository adapter of a webhook delivery repository for postgres
+type webhookDeliveryRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewWebhookDeliveryRepository creates a webhook delivery repository for postgres
+func NewWebhookDeliveryRepository(db *sql.DB) ports.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, aggregate_id, body, status, attempts, next_attempt_at, last_error, created_at, delivered_at`
+
+// CreateMany inserts all the deliveries in a single statement, so either all or none of them get enqueued
+func (r *webhookDeliveryRepository) CreateMany(ctx context.Context, deliveries []entities.WebhookDelivery) error {
+	var values []string
+	var args []interface{}
+	for _, d := range deliveries {
+		var placeholders []string
+		for _, arg := range []interface{}{d.SubscriptionID, d.EventID, string(d.EventType), d.AggregateID, d.Body, string(d.Status), d.Attempts, d.NextAttemptAt, d.LastError, d.CreatedAt} {
+			args = append(args, arg)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		values = append(values, fmt.Sprintf("(%s)", strings.Join(placeholders, ", ")))
+	}
+
+	q := fmt.Sprintf(`
+	INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, aggregate_id, body, status, attempts, next_attempt_at, last_error, created_at)
+	    VALUES %s;
+	`, strings.Join(values, ", "))
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, q, args...)
+	return err
+}
+
+func (r *webhookDeliveryRepository) GetByID(ctx context.Context, ID string) (entities.WebhookDelivery, error) {
+	q := fmt.Sprintf(`
+	SELECT %s
+	    FROM webhook_deliveries WHERE id = $1;
+	`, webhookDeliveryColumns)
+
+	delivery, err := scanWebhookDelivery(conn(ctx, r.DB).QueryRowContext(ctx, q, ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return entities.WebhookDelivery{}, err
+	}
+
+	return delivery, nil
+}
+
+// Claim skips the due deliveries locked by the claims of other instances, instead of waiting for them to commit
+func (r *webhookDeliveryRepository) Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]entities.WebhookDelivery, error) {
+	q := fmt.Sprintf(`
+	WITH claimed AS (
+	    UPDATE webhook_deliveries SET next_attempt_at = $4
+	        WHERE id IN (
+	            SELECT id FROM webhook_deliveries WHERE status = $1 AND next_attempt_at <= $2
+	                ORDER BY next_attempt_at LIMIT $3
+	                FOR UPDATE SKIP LOCKED
+	        )
+	        RETURNING *
+	)
+	SELECT %s FROM claimed ORDER BY created_at;
+	`, webhookDeliveryColumns)
+
+	return r.query(ctx, q, string(entities.WebhookDeliveryStatusPending), now, limit, leaseUntil)
+}
+
+func (r *webhookDeliveryRepository) GetByStatus(ctx context.Context, status entities.WebhookDeliveryStatus) ([]entities.WebhookDelivery, error) {
+	q := fmt.Sprintf(`
+	SELECT %s
+	    FROM webhook_deliveries WHERE status = $1
+	    ORDER BY created_at;
+	`, webhookDeliveryColumns)
+
+	return r.query(ctx, q, string(status))
+}
+
+func (r *webhookDeliveryRepository) GetByAggregateID(ctx context.Context, aggregateID string) ([]entities.WebhookDelivery, error) {
+	q := fmt.Sprintf(`
+	SELECT %s
+	    FROM webhook_deliveries WHERE aggregate_id = $1
+	    ORDER BY created_at;
+	`, webhookDeliveryColumns)
+
+	return r.query(ctx, q, aggregateID)
+}
+
+func (r *webhookDeliveryRepository) Update(ctx context.Context, delivery entities.WebhookDelivery) error {
+	q := `
+	UPDATE webhook_deliveries SET status=$1, attempts=$2, next_attempt_at=$3, last_error=$4, delivered_at=$5
+	    WHERE id=$6;
+	`
+
+	result, err := conn(ctx, r.DB).ExecContext(
+		ctx, q, string(delivery.Status), delivery.Attempts, delivery.NextAttemptAt, delivery.LastError, delivery.DeliveredAt, delivery.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(result)
+}
+
+func (r *webhookDeliveryRepository) UpdateBody(ctx context.Context, ID string, body []byte) error {
+	q := `UPDATE webhook_deliveries SET body=$1 WHERE id=$2;`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, q, body, ID)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(result)
+}
+
+func (r *webhookDeliveryRepository) DeleteBySubscription(ctx context.Context, subscriptionID string) error {
+	q := `DELETE FROM webhook_deliveries WHERE subscription_id=$1;`
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, q, subscriptionID)
+	return err
+}
+
+func (r *webhookDeliveryRepository) query(ctx context.Context, q string, args ...interface{}) ([]entities.WebhookDelivery, error) {
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	deliveries := []entities.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// scanWebhookDelivery scans a row holding the webhookDeliveryColumns
+func scanWebhookDelivery(row interface {
+	Scan(dest ...interface{}) error
+}) (entities.WebhookDelivery, error) {
+	var d entities.WebhookDelivery
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.AggregateID, &d.Body, &d.Status, &d.Attempts, &d.NextAttemptAt, &d.LastError, &d.CreatedAt, &d.DeliveredAt)
+	return d, err
+}
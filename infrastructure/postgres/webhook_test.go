@@ -0,0 +1,248 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/mocks"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewWebhookSubscriptionRepository_Ok checks that NewWebhookSubscriptionRepository creates a new webhookSubscriptionRepository struct
+func TestNewWebhookSubscriptionRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewWebhookSubscriptionRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestWebhookSubscriptionCreate_Ok checks that Create returns the ID of the inserted subscription
+func TestWebhookSubscriptionCreate_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &webhookSubscriptionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	subscription := entities.WebhookSubscription{
+		URL:        "https://test.com/hook",
+		EventTypes: []string{"user.created"},
+		Secret:     "test-secret",
+	}
+	mock.ExpectQuery("INSERT INTO webhook_subscriptions").
+		WithArgs(subscription.URL, pq.Array(subscription.EventTypes), subscription.Secret, subscription.CreatedAt, subscription.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("test-id"))
+
+	// Act
+	ID, err := repo.Create(context.Background(), subscription)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "test-id", ID)
+}
+
+// TestWebhookSubscriptionGetAll_Ok checks that GetAll returns the subscriptions found
+func TestWebhookSubscriptionGetAll_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &webhookSubscriptionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM webhook_subscriptions").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "event_types", "secret", "created_at", "updated_at"}).
+			AddRow("test-id", "https://test.com/hook", "{user.created,user.deleted}", "test-secret", now, now))
+
+	// Act
+	subscriptions, err := repo.GetAll(context.Background())
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, subscriptions, 1)
+	assert.Equal(t, []string{"user.created", "user.deleted"}, subscriptions[0].EventTypes)
+}
+
+// TestWebhookSubscriptionGetByID_NotFound checks that GetByID returns a non existent error when no subscription is found
+func TestWebhookSubscriptionGetByID_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &webhookSubscriptionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM webhook_subscriptions WHERE id").WithArgs("test-id").WillReturnError(sql.ErrNoRows)
+
+	// Act
+	_, err := repo.GetByID(context.Background(), "test-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestWebhookSubscriptionDelete_NotFound checks that Delete returns a non existent error when no rows are affected
+func TestWebhookSubscriptionDelete_NotFound(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &webhookSubscriptionRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	mock.ExpectExec("DELETE FROM webhook_subscriptions").WithArgs("test-id").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err := repo.Delete(context.Background(), "test-id")
+
+	// Assert
+	assert.True(t, errors.Is(err, wrappers.NonExistentErr))
+}
+
+// TestNewWebhookDeliveryRepository_Ok checks that NewWebhookDeliveryRepository creates a new webhookDeliveryRepository struct
+func TestNewWebhookDeliveryRepository_Ok(t *testing.T) {
+	// Arrange
+	_, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	// Act
+	repo := NewWebhookDeliveryRepository(db)
+
+	// Assert
+	assert.NotEmpty(t, repo)
+}
+
+// TestWebhookDeliveryCreateMany_Ok checks that CreateMany inserts all the deliveries in a single statement
+func TestWebhookDeliveryCreateMany_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &webhookDeliveryRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	deliveries := []entities.WebhookDelivery{
+		{SubscriptionID: "test-subscription-1", EventID: "test-event", Status: entities.WebhookDeliveryStatusPending},
+		{SubscriptionID: "test-subscription-2", EventID: "test-event", Status: entities.WebhookDeliveryStatusPending},
+	}
+	mock.ExpectExec(`INSERT INTO webhook_deliveries (.+) VALUES \(\$1, (.+), \$10\), \(\$11, (.+), \$20\)`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	// Act
+	err := repo.CreateMany(context.Background(), deliveries)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+// TestWebhookDeliveryClaim_Ok checks that Claim postpones and returns the due pending deliveries not locked by other claims
+func TestWebhookDeliveryClaim_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &webhookDeliveryRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	now := time.Now()
+	leaseUntil := now.Add(time.Minute)
+	mock.ExpectQuery(`UPDATE webhook_deliveries SET next_attempt_at = \$4 WHERE id IN \( SELECT id FROM webhook_deliveries WHERE status = \$1 AND next_attempt_at <= \$2 ORDER BY next_attempt_at LIMIT \$3 FOR UPDATE SKIP LOCKED \)`).
+		WithArgs("pending", now, 10, leaseUntil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "event_id", "event_type", "aggregate_id", "body", "status", "attempts", "next_attempt_at", "last_error", "created_at", "delivered_at"}).
+			AddRow("test-id", "test-subscription", "test-event", "user.created", "test-user", []byte(`{}`), "pending", 2, leaseUntil, "send error", now, nil))
+
+	// Act
+	deliveries, err := repo.Claim(context.Background(), now, leaseUntil, 10)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, entities.WebhookDeliveryStatusPending, deliveries[0].Status)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.Equal(t, leaseUntil, deliveries[0].NextAttemptAt)
+	assert.Nil(t, deliveries[0].DeliveredAt)
+}
+
+// TestWebhookDeliveryClaim_QueryError checks that Claim returns an error when the query fails
+func TestWebhookDeliveryClaim_QueryError(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &webhookDeliveryRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	expectedError := "query error"
+	mock.ExpectQuery("UPDATE webhook_deliveries").WillReturnError(errors.New(expectedError))
+
+	// Act
+	deliveries, err := repo.Claim(context.Background(), time.Now(), time.Now(), 10)
+
+	// Assert
+	assert.Nil(t, deliveries)
+	assert.Equal(t, expectedError, err.Error())
+}
+
+// TestWebhookDeliveryUpdate_Ok checks that Update stores the new state of the delivery
+func TestWebhookDeliveryUpdate_Ok(t *testing.T) {
+	// Arrange
+	mock, db := mocks.NewSqlDB(t)
+	defer db.Close()
+
+	repo := &webhookDeliveryRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+
+	delivery := entities.WebhookDelivery{
+		ID:       "test-id",
+		Status:   entities.WebhookDeliveryStatusDead,
+		Attempts: 8,
+	}
+	mock.ExpectExec("UPDATE webhook_deliveries SET").
+		WithArgs("dead", 8, delivery.NextAttemptAt, "", delivery.DeliveredAt, "test-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Act
+	err := repo.Update(context.Background(), delivery)
+
+	// Assert
+	assert.Nil(t, err)
+}
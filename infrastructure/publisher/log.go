@@ -0,0 +1,28 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/observability"
+)
+
+// logPublisher adapter of an event publisher that writes the events to the logger
+type logPublisher struct{}
+
+// NewLogPublisher creates an event publisher that writes the events to the logger
+func NewLogPublisher() ports.EventPublisher {
+	return &logPublisher{}
+}
+
+func (p *logPublisher) Publish(_ context.Context, event entities.Event) error {
+	body, err := json.Marshal(newMessage(event))
+	if err != nil {
+		return err
+	}
+
+	observability.Logger().Printf("event published: %s", body)
+	return nil
+}
@@ -0,0 +1,35 @@
+package publisher
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+)
+
+// MemoryPublisher adapter of an event publisher that keeps the events in memory, so that they can be inspected
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []entities.Event
+}
+
+// NewMemoryPublisher creates an event publisher that keeps the events in memory
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(_ context.Context, event entities.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, event)
+	return nil
+}
+
+// Events returns a copy of the events published so far
+func (p *MemoryPublisher) Events() []entities.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]entities.Event{}, p.events...)
+}
@@ -0,0 +1,28 @@
+package publisher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMemoryPublish_Ok checks that Publish keeps the events in the order they were published
+func TestMemoryPublish_Ok(t *testing.T) {
+	// Arrange
+	publisher := NewMemoryPublisher()
+	events := []entities.Event{
+		{ID: "1", Type: entities.EventTypeUserCreated},
+		{ID: "2", Type: entities.EventTypeUserDeleted},
+	}
+
+	// Act
+	for _, event := range events {
+		err := publisher.Publish(context.Background(), event)
+		assert.Nil(t, err)
+	}
+
+	// Assert
+	assert.Equal(t, events, publisher.Events())
+}
@@ -0,0 +1,26 @@
+package publisher
+
+import (
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+)
+
+// message published representation of an event, the ID allows consumers to discard the events delivered more than once
+type message struct {
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	AggregateID string                 `json:"aggregate_id"`
+	Payload     map[string]interface{} `json:"payload"`
+	OccurredAt  time.Time              `json:"occurred_at"`
+}
+
+func newMessage(event entities.Event) message {
+	return message{
+		ID:          event.ID,
+		Type:        string(event.Type),
+		AggregateID: event.AggregateID,
+		Payload:     event.Payload,
+		OccurredAt:  event.OccurredAt,
+	}
+}
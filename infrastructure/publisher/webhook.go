@@ -0,0 +1,55 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// webhookPublisher adapter of an event publisher that posts the events to an HTTP endpoint
+type webhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookPublisher creates an event publisher that posts every event as JSON to the given URL.
+// Any response other than 2xx is considered a failure, so the event gets published again.
+func NewWebhookPublisher(url string, client *http.Client) ports.EventPublisher {
+	return &webhookPublisher{
+		url:    url,
+		client: client,
+	}
+}
+
+func (p *webhookPublisher) Publish(ctx context.Context, event entities.Event) error {
+	body, err := json.Marshal(newMessage(event))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Id", event.ID)
+	req.Header.Set("X-Event-Type", string(event.Type))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook %s responded with status %d", p.url, resp.StatusCode)
+	}
+	return nil
+}
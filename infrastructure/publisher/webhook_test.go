@@ -0,0 +1,57 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWebhookPublish_Ok checks that Publish posts the event as JSON to the webhook
+func TestWebhookPublish_Ok(t *testing.T) {
+	// Arrange
+	event := entities.NewUserEvent(entities.EventTypeUserCreated, "test-id", &entities.User{Email: "test@test.com"})
+	event.ID = "test-event"
+
+	var received message
+	var eventType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		eventType = r.Header.Get("X-Event-Type")
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	publisher := NewWebhookPublisher(server.URL, server.Client())
+
+	// Act
+	err := publisher.Publish(context.Background(), event)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, string(entities.EventTypeUserCreated), eventType)
+	assert.Equal(t, event.ID, received.ID)
+	assert.Equal(t, "test@test.com", received.Payload["email"])
+	assert.NotContains(t, received.Payload, "password_hash")
+}
+
+// TestWebhookPublish_StatusError checks that Publish returns an error when the webhook does not respond with a 2xx status
+func TestWebhookPublish_StatusError(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	publisher := NewWebhookPublisher(server.URL, server.Client())
+
+	// Act
+	err := publisher.Publish(context.Background(), entities.Event{})
+
+	// Assert
+	assert.NotNil(t, err)
+}
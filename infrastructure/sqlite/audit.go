@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+)
+
+// auditRepository adapter of an audit repository for sqlite
+type auditRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewAuditRepository creates an audit repository for sqlite
+func NewAuditRepository(db *sql.DB) ports.AuditRepository {
+	return &auditRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *auditRepository) Create(ctx context.Context, event entities.AuditEvent) (string, error) {
+	q := `
+	INSERT INTO audit_events (id, actor_id, action, target_id, before, after, request_id, timestamp)
+	    VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8);
+	`
+
+	event.ID = uuid.NewString()
+	_, err := conn(ctx, r.DB).ExecContext(
+		ctx, q, event.ID, event.ActorID, event.Action, event.TargetID, asJSON(event.Before), asJSON(event.After), event.RequestID, event.Timestamp,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return event.ID, nil
+}
+
+// List returns the events from the newest to the oldest, the ones created later first when they share a timestamp
+func (r *auditRepository) List(ctx context.Context, filter models.AuditFilter, offset, limit int) ([]entities.AuditEvent, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.ActorID != "" {
+		args = append(args, filter.ActorID)
+		conditions = append(conditions, fmt.Sprintf("actor_id = ?%d", len(args)))
+	}
+	if filter.Action != "" {
+		args = append(args, string(filter.Action))
+		conditions = append(conditions, fmt.Sprintf("action = ?%d", len(args)))
+	}
+	if filter.TargetID != "" {
+		args = append(args, filter.TargetID)
+		conditions = append(conditions, fmt.Sprintf("target_id = ?%d", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From.UTC())
+		conditions = append(conditions, fmt.Sprintf("timestamp >= ?%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To.UTC())
+		conditions = append(conditions, fmt.Sprintf("timestamp < ?%d", len(args)))
+	}
+
+	var where string
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// sqlite only allows an offset after a limit, where a negative one means no limit
+	if limit <= 0 {
+		limit = -1
+	}
+	args = append(args, limit, offset)
+
+	q := fmt.Sprintf(`
+	SELECT id, actor_id, action, target_id, before, after, request_id, timestamp
+	    FROM audit_events %s ORDER BY timestamp DESC, rowid DESC LIMIT ?%d OFFSET ?%d;
+	`, where, len(args)-1, len(args))
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	events := []entities.AuditEvent{}
+	for rows.Next() {
+		var event entities.AuditEvent
+		err = rows.Scan(&event.ID, &event.ActorID, &event.Action, &event.TargetID, asJSON(&event.Before), asJSON(&event.After), &event.RequestID, &event.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *auditRepository) RedactTarget(ctx context.Context, targetID string) error {
+	q := `
+	UPDATE audit_events SET before = NULL, after = NULL
+	    WHERE target_id = ?1;
+	`
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, q, targetID)
+	return err
+}
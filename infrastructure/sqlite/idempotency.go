@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// idempotencyPruneBatchSize maximum number of expired records deleted by every reservation
+const idempotencyPruneBatchSize = 100
+
+// idempotencyRepository adapter of an idempotency repository for sqlite
+type idempotencyRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewIdempotencyRepository creates an idempotency repository for sqlite
+func NewIdempotencyRepository(db *sql.DB) ports.IdempotencyRepository {
+	return &idempotencyRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *idempotencyRepository) Get(ctx context.Context, key string) (entities.IdempotencyRecord, error) {
+	q := `
+	SELECT key, request_hash, response, created_at, expires_at
+	    FROM idempotency_keys WHERE key = ?1 AND expires_at > ?2;
+	`
+
+	row := conn(ctx, r.DB).QueryRowContext(ctx, q, key, time.Now().UTC())
+
+	var record entities.IdempotencyRecord
+	err := row.Scan(&record.Key, &record.RequestHash, &record.Response, &record.CreatedAt, &record.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return entities.IdempotencyRecord{}, err
+	}
+
+	return record, nil
+}
+
+// Reserve only replaces a record that expired, so that the key stays reserved by the request that inserted it.
+// Before that, it deletes a bounded batch of the expired records, which would otherwise be kept forever.
+func (r *idempotencyRepository) Reserve(ctx context.Context, record entities.IdempotencyRecord) error {
+	prune := `
+	DELETE FROM idempotency_keys WHERE key IN (
+	    SELECT key FROM idempotency_keys WHERE expires_at <= ?1 LIMIT ?2
+	);
+	`
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, prune, record.CreatedAt, idempotencyPruneBatchSize)
+	if err != nil {
+		return err
+	}
+
+	q := `
+	INSERT INTO idempotency_keys (key, token, request_hash, response, created_at, expires_at)
+	    VALUES (?1, ?2, ?3, NULL, ?4, ?5)
+	    ON CONFLICT (key) DO UPDATE
+	    SET token = excluded.token, request_hash = excluded.request_hash, response = NULL, created_at = excluded.created_at, expires_at = excluded.expires_at
+	    WHERE idempotency_keys.expires_at <= excluded.created_at;
+	`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, q, record.Key, record.Token, record.RequestHash, record.CreatedAt, record.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected < 1 {
+		return entities.NewAlreadyExistsErr(fmt.Errorf("idempotency key %s is reserved", record.Key))
+	}
+	return nil
+}
+
+// Complete only updates the record while it is reserved with the token, so that a request whose reservation expired does not overwrite the one of a retry
+func (r *idempotencyRepository) Complete(ctx context.Context, key, token string, response []byte, expiresAt time.Time) error {
+	q := `UPDATE idempotency_keys SET response = ?3, expires_at = ?4 WHERE key = ?1 AND token = ?2 AND response IS NULL;`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, q, key, token, response, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected < 1 {
+		return entities.NewConflictErr(fmt.Errorf("idempotency key %s is no longer reserved by the request", key))
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, key, token string) error {
+	q := `DELETE FROM idempotency_keys WHERE key = ?1 AND token = ?2 AND response IS NULL;`
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, q, key, token)
+	return err
+}
@@ -0,0 +1,135 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// invitationRepository adapter of an invitation repository for sqlite
+type invitationRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewInvitationRepository creates an invitation repository for sqlite
+func NewInvitationRepository(db *sql.DB) ports.InvitationRepository {
+	return &invitationRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation entities.Invitation) (string, error) {
+	q := `
+	INSERT INTO invitations (id, user_id, email, nonce, expires_at, created_at, updated_at)
+	    VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7);
+	`
+
+	invitation.ID = uuid.NewString()
+	_, err := conn(ctx, r.DB).ExecContext(
+		ctx, q, invitation.ID, invitation.UserID, invitation.Email, invitation.Nonce, invitation.ExpiresAt, invitation.CreatedAt, invitation.UpdatedAt,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return invitation.ID, nil
+}
+
+func (r *invitationRepository) GetAll(ctx context.Context) ([]entities.Invitation, error) {
+	q := `
+	SELECT id, user_id, email, nonce, expires_at, created_at, updated_at
+	    FROM invitations ORDER BY created_at;
+	`
+
+	return r.query(ctx, q)
+}
+
+func (r *invitationRepository) GetByUserID(ctx context.Context, userID string) ([]entities.Invitation, error) {
+	q := `
+	SELECT id, user_id, email, nonce, expires_at, created_at, updated_at
+	    FROM invitations WHERE user_id = ?1 ORDER BY created_at;
+	`
+
+	return r.query(ctx, q, userID)
+}
+
+func (r *invitationRepository) GetByID(ctx context.Context, ID string) (entities.Invitation, error) {
+	q := `
+	SELECT id, user_id, email, nonce, expires_at, created_at, updated_at
+	    FROM invitations WHERE id = ?1;
+	`
+
+	row := conn(ctx, r.DB).QueryRowContext(ctx, q, ID)
+
+	var i entities.Invitation
+	err := row.Scan(&i.ID, &i.UserID, &i.Email, &i.Nonce, &i.ExpiresAt, &i.CreatedAt, &i.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return entities.Invitation{}, err
+	}
+
+	return i, nil
+}
+
+func (r *invitationRepository) Update(ctx context.Context, ID string, invitation entities.Invitation) error {
+	q := `
+	UPDATE invitations SET nonce=?1, expires_at=?2, updated_at=?3
+	    WHERE id=?4;
+	`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, q, invitation.Nonce, invitation.ExpiresAt, invitation.UpdatedAt, ID)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(result)
+}
+
+func (r *invitationRepository) Delete(ctx context.Context, ID string) error {
+	q := `DELETE FROM invitations WHERE id=?1;`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(result)
+}
+
+func (r *invitationRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	q := `DELETE FROM invitations WHERE user_id=?1;`
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, q, userID)
+	return err
+}
+
+func (r *invitationRepository) query(ctx context.Context, q string, args ...interface{}) ([]entities.Invitation, error) {
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	invitations := []entities.Invitation{}
+	for rows.Next() {
+		var i entities.Invitation
+		err = rows.Scan(&i.ID, &i.UserID, &i.Email, &i.Nonce, &i.ExpiresAt, &i.CreatedAt, &i.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, i)
+	}
+
+	return invitations, rows.Err()
+}
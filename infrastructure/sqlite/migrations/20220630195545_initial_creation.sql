@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE users (
+    id text NOT NULL,
+    name text,
+    surnames text,
+    email text,
+    password_hash text,
+    claim_ids text,
+    created_at timestamp,
+    updated_at timestamp,
+    PRIMARY KEY(id)
+);
+
+-- uniqueness is enforced through an index, as sqlite cannot drop table constraints
+CREATE UNIQUE INDEX email_unique ON users (email);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE users;
+-- +goose StatementEnd
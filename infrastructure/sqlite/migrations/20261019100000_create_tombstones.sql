@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE tombstones (
+    id text NOT NULL,
+    user_id text NOT NULL,
+    erased_by text,
+    stores text,
+    erased_at timestamp,
+    PRIMARY KEY(id)
+);
+
+CREATE INDEX tombstones_user_id_idx ON tombstones (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE tombstones;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE audit_events (
+    id text NOT NULL,
+    actor_id text,
+    action text NOT NULL,
+    target_id text,
+    before text,
+    after text,
+    request_id text,
+    timestamp timestamp NOT NULL,
+    PRIMARY KEY(id)
+);
+
+CREATE INDEX audit_events_timestamp_idx ON audit_events (timestamp DESC);
+CREATE INDEX audit_events_actor_id_idx ON audit_events (actor_id);
+CREATE INDEX audit_events_target_id_idx ON audit_events (target_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE audit_events;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE outbox_events (
+    id text NOT NULL,
+    seq integer NOT NULL,
+    type text NOT NULL,
+    aggregate_id text NOT NULL,
+    payload text,
+    occurred_at timestamp NOT NULL,
+    published_at timestamp,
+    attempts integer NOT NULL DEFAULT 0,
+    last_error text NOT NULL DEFAULT '',
+    PRIMARY KEY(id)
+);
+
+CREATE INDEX outbox_events_pending_idx ON outbox_events (occurred_at, seq) WHERE published_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE outbox_events;
+-- +goose StatementEnd
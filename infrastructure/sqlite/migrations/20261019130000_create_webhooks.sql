@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE webhook_subscriptions (
+    id text NOT NULL,
+    url text NOT NULL,
+    event_types text,
+    secret text NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY(id)
+);
+
+CREATE TABLE webhook_deliveries (
+    id text NOT NULL,
+    subscription_id text NOT NULL,
+    event_id text NOT NULL,
+    event_type text NOT NULL,
+    body blob NOT NULL,
+    status text NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp NOT NULL,
+    last_error text NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL,
+    delivered_at timestamp,
+    PRIMARY KEY(id)
+);
+
+CREATE INDEX webhook_deliveries_status_idx ON webhook_deliveries (status, next_attempt_at);
+CREATE INDEX webhook_deliveries_subscription_id_idx ON webhook_deliveries (subscription_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE webhook_deliveries;
+DROP TABLE webhook_subscriptions;
+-- +goose StatementEnd
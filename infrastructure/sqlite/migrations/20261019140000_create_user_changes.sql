@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_changes (
+    seq integer PRIMARY KEY AUTOINCREMENT,
+    type text NOT NULL,
+    user_id text NOT NULL,
+    occurred_at timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+-- sqlite serializes the transactions writing to the database, so changes always commit in sequence order
+CREATE TRIGGER users_insert_change AFTER INSERT ON users
+BEGIN
+    INSERT INTO user_changes (type, user_id) VALUES ('created', NEW.id);
+END;
+
+CREATE TRIGGER users_update_change AFTER UPDATE ON users
+BEGIN
+    INSERT INTO user_changes (type, user_id) VALUES ('updated', NEW.id);
+END;
+
+CREATE TRIGGER users_delete_change AFTER DELETE ON users
+BEGIN
+    INSERT INTO user_changes (type, user_id) VALUES ('deleted', OLD.id);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER users_delete_change;
+DROP TRIGGER users_update_change;
+DROP TRIGGER users_insert_change;
+DROP TABLE user_changes;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN email_normalized text;
+
+UPDATE users SET email_normalized = lower(trim(email));
+
+-- creating the unique index aborts the migration when users share a normalized email, as they must be fixed by hand
+DROP INDEX email_unique;
+CREATE UNIQUE INDEX email_normalized_unique ON users (email_normalized);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX email_normalized_unique;
+CREATE UNIQUE INDEX email_unique ON users (email);
+ALTER TABLE users DROP COLUMN email_normalized;
+-- +goose StatementEnd
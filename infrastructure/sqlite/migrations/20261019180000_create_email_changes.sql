@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE email_changes (
+    id text NOT NULL,
+    user_id text NOT NULL,
+    previous_email text NOT NULL,
+    email text NOT NULL,
+    nonce text NOT NULL,
+    expires_at timestamp NOT NULL,
+    created_at timestamp NOT NULL,
+    PRIMARY KEY(id)
+);
+
+CREATE INDEX email_changes_user_id_idx ON email_changes (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE email_changes;
+-- +goose StatementEnd
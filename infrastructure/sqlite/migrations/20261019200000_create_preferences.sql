@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE preferences (
+    user_id text NOT NULL,
+    version integer NOT NULL,
+    language text,
+    timezone text,
+    email_notifications boolean,
+    push_notifications boolean,
+    marketing_notifications boolean,
+    updated_at timestamp NOT NULL,
+    PRIMARY KEY(user_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE preferences;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE webhook_deliveries ADD COLUMN aggregate_id text NOT NULL DEFAULT '';
+UPDATE webhook_deliveries SET aggregate_id = COALESCE(json_extract(CAST(body AS text), '$.aggregate_id'), '');
+
+CREATE INDEX webhook_deliveries_aggregate_id_idx ON webhook_deliveries (aggregate_id);
+CREATE INDEX outbox_events_aggregate_id_idx ON outbox_events (aggregate_id);
+CREATE INDEX invitations_user_id_idx ON invitations (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX invitations_user_id_idx;
+DROP INDEX outbox_events_aggregate_id_idx;
+DROP INDEX webhook_deliveries_aggregate_id_idx;
+ALTER TABLE webhook_deliveries DROP COLUMN aggregate_id;
+-- +goose StatementEnd
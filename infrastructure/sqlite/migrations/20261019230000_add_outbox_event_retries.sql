@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE outbox_events ADD COLUMN next_attempt_at timestamp NOT NULL DEFAULT '1970-01-01 00:00:00+00:00';
+ALTER TABLE outbox_events ADD COLUMN dead_at timestamp;
+UPDATE outbox_events SET next_attempt_at = occurred_at;
+
+DROP INDEX outbox_events_pending_idx;
+CREATE INDEX outbox_events_pending_idx ON outbox_events (occurred_at, seq) WHERE published_at IS NULL AND dead_at IS NULL;
+CREATE INDEX outbox_events_published_at_idx ON outbox_events (published_at) WHERE published_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX outbox_events_published_at_idx;
+DROP INDEX outbox_events_pending_idx;
+CREATE INDEX outbox_events_pending_idx ON outbox_events (occurred_at, seq) WHERE published_at IS NULL;
+ALTER TABLE outbox_events DROP COLUMN dead_at;
+ALTER TABLE outbox_events DROP COLUMN next_attempt_at;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- counterpart of the search indexes of postgres. fts4 is used as the driver is not built with fts5 by default,
+-- and its unicode61 tokenizer folds the case and the diacritics of the words like search_normalize does
+CREATE VIRTUAL TABLE users_search USING fts4(name, surnames, email, tokenize=unicode61 "remove_diacritics=1");
+
+INSERT INTO users_search (docid, name, surnames, email) SELECT rowid, name, surnames, email FROM users;
+
+-- the documents are keyed by the rowid of the users, as the full-text index cannot be looked up by other columns
+CREATE TRIGGER users_insert_search AFTER INSERT ON users
+BEGIN
+    INSERT INTO users_search (docid, name, surnames, email) VALUES (NEW.rowid, NEW.name, NEW.surnames, NEW.email);
+END;
+
+CREATE TRIGGER users_update_search AFTER UPDATE OF name, surnames, email ON users
+BEGIN
+    DELETE FROM users_search WHERE docid = OLD.rowid;
+    INSERT INTO users_search (docid, name, surnames, email) VALUES (NEW.rowid, NEW.name, NEW.surnames, NEW.email);
+END;
+
+CREATE TRIGGER users_delete_search AFTER DELETE ON users
+BEGIN
+    DELETE FROM users_search WHERE docid = OLD.rowid;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER users_delete_search;
+DROP TRIGGER users_update_search;
+DROP TRIGGER users_insert_search;
+DROP TABLE users_search;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the single row holds the sequence number of the last pruned change, so that watching can only be resumed after it
+CREATE TABLE user_changes_pruned (
+    id integer NOT NULL DEFAULT 1 CHECK (id = 1),
+    seq integer NOT NULL,
+    PRIMARY KEY(id)
+);
+INSERT INTO user_changes_pruned (seq) VALUES (0);
+
+CREATE INDEX user_changes_occurred_at_idx ON user_changes (occurred_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX user_changes_occurred_at_idx;
+DROP TABLE user_changes_pruned;
+-- +goose StatementEnd
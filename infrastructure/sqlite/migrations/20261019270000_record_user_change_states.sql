@@ -0,0 +1,77 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the changes record the state of the user they leave but its password hash, which is cleared when the user is deleted or erased
+ALTER TABLE user_changes ADD COLUMN name text;
+ALTER TABLE user_changes ADD COLUMN surnames text;
+ALTER TABLE user_changes ADD COLUMN email text;
+ALTER TABLE user_changes ADD COLUMN email_normalized text;
+ALTER TABLE user_changes ADD COLUMN claim_ids text;
+ALTER TABLE user_changes ADD COLUMN avatar_url text;
+ALTER TABLE user_changes ADD COLUMN created_at timestamp;
+ALTER TABLE user_changes ADD COLUMN updated_at timestamp;
+
+UPDATE user_changes
+    SET (name, surnames, email, email_normalized, claim_ids, avatar_url, created_at, updated_at) = (
+        SELECT u.name, u.surnames, u.email, u.email_normalized, u.claim_ids, u.avatar_url, u.created_at, u.updated_at
+            FROM users u WHERE u.id = user_changes.user_id
+    );
+
+CREATE INDEX user_changes_user_id_idx ON user_changes (user_id);
+
+DROP TRIGGER users_delete_change;
+DROP TRIGGER users_update_change;
+DROP TRIGGER users_insert_change;
+
+CREATE TRIGGER users_insert_change AFTER INSERT ON users
+BEGIN
+    INSERT INTO user_changes (type, user_id, name, surnames, email, email_normalized, claim_ids, avatar_url, created_at, updated_at)
+        VALUES ('created', NEW.id, NEW.name, NEW.surnames, NEW.email, NEW.email_normalized, NEW.claim_ids, NEW.avatar_url, NEW.created_at, NEW.updated_at);
+END;
+
+CREATE TRIGGER users_update_change AFTER UPDATE ON users
+BEGIN
+    INSERT INTO user_changes (type, user_id, name, surnames, email, email_normalized, claim_ids, avatar_url, created_at, updated_at)
+        VALUES ('updated', NEW.id, NEW.name, NEW.surnames, NEW.email, NEW.email_normalized, NEW.claim_ids, NEW.avatar_url, NEW.created_at, NEW.updated_at);
+END;
+
+CREATE TRIGGER users_delete_change AFTER DELETE ON users
+BEGIN
+    UPDATE user_changes
+        SET name = NULL, surnames = NULL, email = NULL, email_normalized = NULL,
+            claim_ids = NULL, avatar_url = NULL, created_at = NULL, updated_at = NULL
+        WHERE user_id = OLD.id;
+    INSERT INTO user_changes (type, user_id) VALUES ('deleted', OLD.id);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER users_delete_change;
+DROP TRIGGER users_update_change;
+DROP TRIGGER users_insert_change;
+
+CREATE TRIGGER users_insert_change AFTER INSERT ON users
+BEGIN
+    INSERT INTO user_changes (type, user_id) VALUES ('created', NEW.id);
+END;
+
+CREATE TRIGGER users_update_change AFTER UPDATE ON users
+BEGIN
+    INSERT INTO user_changes (type, user_id) VALUES ('updated', NEW.id);
+END;
+
+CREATE TRIGGER users_delete_change AFTER DELETE ON users
+BEGIN
+    INSERT INTO user_changes (type, user_id) VALUES ('deleted', OLD.id);
+END;
+
+DROP INDEX user_changes_user_id_idx;
+ALTER TABLE user_changes DROP COLUMN updated_at;
+ALTER TABLE user_changes DROP COLUMN created_at;
+ALTER TABLE user_changes DROP COLUMN avatar_url;
+ALTER TABLE user_changes DROP COLUMN claim_ids;
+ALTER TABLE user_changes DROP COLUMN email_normalized;
+ALTER TABLE user_changes DROP COLUMN email;
+ALTER TABLE user_changes DROP COLUMN surnames;
+ALTER TABLE user_changes DROP COLUMN name;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+DELETE FROM preferences WHERE user_id NOT IN (SELECT id FROM users);
+
+-- the preferences belong to their user, so they are deleted along with it
+CREATE TRIGGER users_delete_preferences AFTER DELETE ON users
+BEGIN
+    DELETE FROM preferences WHERE user_id = OLD.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER users_delete_preferences;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+DELETE FROM invitations WHERE user_id NOT IN (SELECT id FROM users);
+
+-- the invitations of a user are deleted along with it, so that none is left pending
+CREATE TRIGGER users_delete_invitations AFTER DELETE ON users
+BEGIN
+    DELETE FROM invitations WHERE user_id = OLD.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER users_delete_invitations;
+-- +goose StatementEnd
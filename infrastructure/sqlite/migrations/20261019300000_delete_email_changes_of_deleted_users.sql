@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+DELETE FROM email_changes WHERE user_id NOT IN (SELECT id FROM users);
+
+-- the pending email changes of a user are deleted along with it
+CREATE TRIGGER users_delete_email_changes AFTER DELETE ON users
+BEGIN
+    DELETE FROM email_changes WHERE user_id = OLD.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER users_delete_email_changes;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the events are claimed when no earlier event of their aggregate is pending
+CREATE INDEX outbox_events_pending_aggregate_idx ON outbox_events (aggregate_id, occurred_at, seq) WHERE published_at IS NULL AND dead_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX outbox_events_pending_aggregate_idx;
+-- +goose StatementEnd
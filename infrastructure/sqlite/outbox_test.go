@@ -0,0 +1,178 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOutboxPending_Ok checks that Pending returns the unpublished events in the order they occurred, up to the limit
+func TestOutboxPending_Ok(t *testing.T) {
+	// Arrange
+	repo := NewOutboxRepository(newTestDB(t))
+	now := time.Now().UTC()
+	for _, event := range []entities.Event{
+		{AggregateID: "3", OccurredAt: now.Add(2 * time.Second)},
+		{AggregateID: "1", OccurredAt: now},
+		{AggregateID: "2", OccurredAt: now.Add(time.Second)},
+	} {
+		if err := repo.Add(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first, _ := repo.Pending(context.Background(), 1)
+	if err := repo.MarkPublished(context.Background(), first[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	events, err := repo.Pending(context.Background(), 1)
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "2", events[0].AggregateID)
+	}
+}
+
+// TestOutboxClaim_Ok checks that Claim only claims the earliest pending event of every aggregate when it is due, postponing it until the lease,
+// so that the following events of an aggregate wait for it and no event is claimed twice
+func TestOutboxClaim_Ok(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewOutboxRepository(newTestDB(t))
+	now := time.Now().UTC()
+	for _, event := range []entities.Event{
+		{AggregateID: "a", OccurredAt: now.Add(-3 * time.Second)},
+		{AggregateID: "a", OccurredAt: now.Add(-2 * time.Second)},
+		{AggregateID: "b", OccurredAt: now.Add(-2 * time.Second)},
+		{AggregateID: "b", OccurredAt: now.Add(-time.Second)},
+		{AggregateID: "c", OccurredAt: now.Add(-time.Second)},
+	} {
+		if err := repo.Add(ctx, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pending, _ := repo.Pending(ctx, 10)
+	if err := repo.MarkFailed(ctx, pending[2].ID, errors.New("publish error"), now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	events, err := repo.Claim(ctx, now, now.Add(time.Minute), 10)
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, pending[0].ID, events[0].ID)
+		assert.Equal(t, pending[4].ID, events[1].ID)
+	}
+	claimed, _ := repo.Claim(ctx, now, now.Add(time.Minute), 10)
+	assert.Empty(t, claimed)
+}
+
+// TestOutboxMarkFailed_Ok checks that MarkFailed keeps the event pending along with the error and the attempt
+func TestOutboxMarkFailed_Ok(t *testing.T) {
+	// Arrange
+	repo := NewOutboxRepository(newTestDB(t))
+	if err := repo.Add(context.Background(), entities.Event{}); err != nil {
+		t.Fatal(err)
+	}
+	events, _ := repo.Pending(context.Background(), 1)
+
+	nextAttemptAt := time.Now().UTC().Add(time.Minute)
+
+	// Act
+	err := repo.MarkFailed(context.Background(), events[0].ID, errors.New("test-error"), nextAttemptAt)
+
+	// Assert
+	assert.Nil(t, err)
+	events, _ = repo.Pending(context.Background(), 1)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "test-error", events[0].LastError)
+		assert.Equal(t, 1, events[0].Attempts)
+		assert.True(t, nextAttemptAt.Equal(events[0].NextAttemptAt))
+	}
+}
+
+// TestOutboxMarkDead_Ok checks that MarkDead moves the event to the dead letters, out of the pending ones
+func TestOutboxMarkDead_Ok(t *testing.T) {
+	// Arrange
+	repo := NewOutboxRepository(newTestDB(t))
+	if err := repo.Add(context.Background(), entities.Event{AggregateID: "test-user"}); err != nil {
+		t.Fatal(err)
+	}
+	events, _ := repo.Pending(context.Background(), 1)
+
+	// Act
+	err := repo.MarkDead(context.Background(), events[0].ID, errors.New("test-error"))
+
+	// Assert
+	assert.Nil(t, err)
+	pending, _ := repo.Pending(context.Background(), 1)
+	assert.Empty(t, pending)
+	events, _ = repo.GetByAggregateID(context.Background(), "test-user")
+	if assert.Len(t, events, 1) {
+		assert.NotNil(t, events[0].DeadAt)
+		assert.Equal(t, "test-error", events[0].LastError)
+		assert.Equal(t, 1, events[0].Attempts)
+	}
+}
+
+// TestOutboxDeletePublished_Ok checks that DeletePublished only deletes the events published before the given time
+func TestOutboxDeletePublished_Ok(t *testing.T) {
+	// Arrange
+	repo := NewOutboxRepository(newTestDB(t))
+	for _, aggregateID := range []string{"published", "pending"} {
+		if err := repo.Add(context.Background(), entities.Event{AggregateID: aggregateID, OccurredAt: time.Now().UTC()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first, _ := repo.Pending(context.Background(), 1)
+	if err := repo.MarkPublished(context.Background(), first[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	deleted, err := repo.DeletePublished(context.Background(), time.Now().UTC().Add(time.Second))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, deleted)
+	published, _ := repo.GetByAggregateID(context.Background(), "published")
+	assert.Empty(t, published)
+	pending, _ := repo.GetByAggregateID(context.Background(), "pending")
+	assert.Len(t, pending, 1)
+}
+
+// TestOutboxRedactPayloads_Ok checks that RedactPayloads only keeps the ID in the payloads of the events of the aggregate
+func TestOutboxRedactPayloads_Ok(t *testing.T) {
+	// Arrange
+	repo := NewOutboxRepository(newTestDB(t))
+	for _, event := range []entities.Event{
+		entities.NewUserEvent(entities.EventTypeUserCreated, "test-user", &entities.User{Email: "test@test.com"}),
+		entities.NewUserEvent(entities.EventTypeUserCreated, "test-other", &entities.User{Email: "other@test.com"}),
+	} {
+		if err := repo.Add(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Act
+	err := repo.RedactPayloads(context.Background(), "test-user")
+
+	// Assert
+	assert.Nil(t, err)
+	events, _ := repo.GetByAggregateID(context.Background(), "test-user")
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, map[string]interface{}{"id": "test-user"}, events[0].Payload)
+	}
+	others, _ := repo.GetByAggregateID(context.Background(), "test-other")
+	if assert.Len(t, others, 1) {
+		assert.Equal(t, "other@test.com", others[0].Payload["email"])
+	}
+}
@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPreferencesSave_Ok checks that Save stores the next version of the preferences
+func TestPreferencesSave_Ok(t *testing.T) {
+	// Arrange
+	repo := NewPreferencesRepository(newTestDB(t))
+	if err := repo.Save(context.Background(), entities.Preferences{UserID: "user-id", Version: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err := repo.Save(context.Background(), entities.Preferences{UserID: "user-id", Version: 2})
+
+	// Assert
+	assert.Nil(t, err)
+	preferences, _ := repo.GetByUserID(context.Background(), "user-id")
+	assert.Equal(t, 2, preferences.Version)
+}
+
+// TestPreferencesSave_StaleVersion checks that Save returns a conflict error when the stored version is not the previous one
+func TestPreferencesSave_StaleVersion(t *testing.T) {
+	// Arrange
+	repo := NewPreferencesRepository(newTestDB(t))
+	if err := repo.Save(context.Background(), entities.Preferences{UserID: "user-id", Version: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err := repo.Save(context.Background(), entities.Preferences{UserID: "user-id", Version: 1})
+
+	// Assert
+	assert.ErrorIs(t, err, entities.ConflictErr)
+}
+
+// TestPreferencesGetByUserID_NotFound checks that GetByUserID returns a non existent error when the user has no preferences
+func TestPreferencesGetByUserID_NotFound(t *testing.T) {
+	// Arrange
+	repo := NewPreferencesRepository(newTestDB(t))
+
+	// Act
+	_, err := repo.GetByUserID(context.Background(), "user-id")
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+}
+
+// TestPreferences_DeletedUser checks that the preferences of a user are deleted along with it
+func TestPreferences_DeletedUser(t *testing.T) {
+	// Arrange
+	db := newTestDB(t)
+	users := NewUserRepository(db)
+	repo := NewPreferencesRepository(db)
+	ID, err := users.Create(context.Background(), entities.User{Email: "test@test.com", EmailNormalized: "test@test.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.Save(context.Background(), entities.Preferences{UserID: ID, Version: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = users.Delete(context.Background(), ID)
+
+	// Assert
+	assert.Nil(t, err)
+	_, err = repo.GetByUserID(context.Background(), ID)
+	assert.ErrorIs(t, err, wrappers.NonExistentErr)
+}
@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	// registers the sqlite3 driver, which requires cgo
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pressly/goose/v3"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+// dsnDefaults connection parameters set unless the DSN already sets them. WAL lets readers run while a transaction writes,
+// the busy timeout makes concurrent writers wait for each other instead of failing,
+// and immediate transactions take the write lock when they begin, so they never fail to upgrade a read lock.
+var dsnDefaults = [][2]string{
+	{"_journal_mode", "WAL"},
+	{"_busy_timeout", "5000"},
+	{"_txlock", "immediate"},
+}
+
+// Connect opens the sqlite database of the DSN, which is the path of the database file optionally followed by its connection parameters
+func Connect(ctx context.Context, dsn string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", withDefaults(dsn))
+	if err != nil {
+		return nil, err
+	}
+	return db, db.PingContext(ctx)
+}
+
+// Migrate runs all migrations found in the given directory against the db
+func Migrate(db *sql.DB, migrationsDir string) error {
+	if err := goose.SetDialect("sqlite3"); err != nil {
+		return err
+	}
+	goose.SetTableName("goose_db_version")
+	return goose.Up(db, migrationsDir)
+}
+
+func withDefaults(dsn string) string {
+	var params []string
+	for _, param := range dsnDefaults {
+		if !strings.Contains(dsn, param[0]+"=") {
+			params = append(params, fmt.Sprintf("%s=%s", param[0], param[1]))
+		}
+	}
+	if len(params) == 0 {
+		return dsn
+	}
+
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	return dsn + separator + strings.Join(params, "&")
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// jsonColumn stores a value as JSON, as sqlite has neither array nor JSON types
+type jsonColumn struct {
+	value interface{}
+}
+
+// asJSON returns a jsonColumn of the value, which has to be a pointer when scanning
+func asJSON(value interface{}) jsonColumn {
+	return jsonColumn{value: value}
+}
+
+func (c jsonColumn) Value() (driver.Value, error) {
+	b, err := json.Marshal(c.value)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (c jsonColumn) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), c.value)
+	case []byte:
+		return json.Unmarshal(src, c.value)
+	default:
+		return fmt.Errorf("cannot scan %T into a JSON column", src)
+	}
+}
+
+// checkAffected returns a NonExistentErr when the statement did not touch any row
+func checkAffected(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+	return nil
+}
+
+// withID adds the affected ID to the message of NonExistentErr errors
+func withID(err error, ID string) error {
+	if errors.Is(err, wrappers.NonExistentErr) {
+		return wrappers.NewNonExistentErr(fmt.Errorf("ID %s not found", ID))
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestDB returns a migrated sqlite database stored in a temporary directory removed after the test
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := Connect(context.Background(), filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err = Migrate(db, "migrations"); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// TestMigrate_Ok checks that Migrate creates the tables of every repository
+func TestMigrate_Ok(t *testing.T) {
+	// Arrange
+	db := newTestDB(t)
+	expectedTables := []string{
+		"audit_events", "email_changes", "idempotency_keys", "invitations", "outbox_events", "preferences",
+		"tombstones", "user_changes", "user_changes_pruned", "users", "users_search", "webhook_deliveries", "webhook_subscriptions",
+	}
+
+	// Act
+	// the shadow tables of the full-text indexes are left out, as they are created by sqlite along with them
+	rows, err := db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name NOT LIKE 'users_search_%' AND name != 'goose_db_version' ORDER BY name;`)
+
+	// Assert
+	assert.Nil(t, err)
+	defer rows.Close()
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err = rows.Scan(&table); err != nil {
+			t.Fatal(err)
+		}
+		tables = append(tables, table)
+	}
+	assert.Equal(t, expectedTables, tables)
+}
+
+// TestWithDefaults_Ok checks that withDefaults only adds the connection parameters missing from the DSN
+func TestWithDefaults_Ok(t *testing.T) {
+	tests := []struct {
+		name        string
+		dsn         string
+		expectedDSN string
+	}{
+		{
+			name:        "without parameters",
+			dsn:         "test.db",
+			expectedDSN: "test.db?_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate",
+		},
+		{
+			name:        "with some parameters",
+			dsn:         "file:test.db?_busy_timeout=100",
+			expectedDSN: "file:test.db?_busy_timeout=100&_journal_mode=WAL&_txlock=immediate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			dsn := withDefaults(tt.dsn)
+
+			// Assert
+			assert.Equal(t, tt.expectedDSN, dsn)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+)
+
+// tombstoneRepository adapter of a tombstone repository for sqlite
+type tombstoneRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewTombstoneRepository creates a tombstone repository for sqlite
+func NewTombstoneRepository(db *sql.DB) ports.TombstoneRepository {
+	return &tombstoneRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *tombstoneRepository) Create(ctx context.Context, tombstone entities.Tombstone) (string, error) {
+	q := `
+	INSERT INTO tombstones (id, user_id, erased_by, stores, erased_at)
+	    VALUES (?1, ?2, ?3, ?4, ?5);
+	`
+
+	tombstone.ID = uuid.NewString()
+	_, err := conn(ctx, r.DB).ExecContext(ctx, q, tombstone.ID, tombstone.UserID, tombstone.ErasedBy, asJSON(tombstone.Stores), tombstone.ErasedAt)
+	if err != nil {
+		return "", err
+	}
+
+	return tombstone.ID, nil
+}
@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUnitOfWorkDo_Ok checks that Do commits the calls of every repository when fn succeeds
+func TestUnitOfWorkDo_Ok(t *testing.T) {
+	// Arrange
+	db := newTestDB(t)
+	uow := NewUnitOfWork(db)
+	users := NewUserRepository(db)
+	audit := NewAuditRepository(db)
+
+	// Act
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		ID, err := users.Create(ctx, entities.User{Email: "test@test.com", EmailNormalized: "test@test.com"})
+		if err != nil {
+			return err
+		}
+		_, err = audit.Create(ctx, entities.AuditEvent{Action: "create", TargetID: ID, Timestamp: time.Now().UTC()})
+		return err
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	_, err = users.Get(context.Background(), models.UserFilter{EmailNormalized: "test@test.com"}, nil, nil)
+	assert.Nil(t, err)
+	events, err := audit.List(context.Background(), models.AuditFilter{Action: "create"}, 0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+}
+
+// TestUnitOfWorkDo_Rollback checks that Do rolls back the calls of every repository, including the ones of a nested Do, when fn fails
+func TestUnitOfWorkDo_Rollback(t *testing.T) {
+	// Arrange
+	db := newTestDB(t)
+	uow := NewUnitOfWork(db)
+	users := NewUserRepository(db)
+	audit := NewAuditRepository(db)
+	expectedError := errors.New("fn error")
+
+	// Act
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		err := uow.Do(ctx, func(ctx context.Context) error {
+			_, err := users.Create(ctx, entities.User{Email: "test@test.com", EmailNormalized: "test@test.com"})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if _, err = audit.Create(ctx, entities.AuditEvent{Action: "create", Timestamp: time.Now().UTC()}); err != nil {
+			return err
+		}
+		return expectedError
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, expectedError)
+	_, err = users.Get(context.Background(), models.UserFilter{EmailNormalized: "test@test.com"}, nil, nil)
+	assert.NotNil(t, err)
+	events, err := audit.List(context.Background(), models.AuditFilter{Action: "create"}, 0, 10)
+	assert.Nil(t, err)
+	assert.Empty(t, events)
+}
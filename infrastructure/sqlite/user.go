@@ -0,0 +1,361 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+const userColumns = `id, name, surnames, email, email_normalized, password_hash, claim_ids, avatar_url, created_at, updated_at`
+
+// userRepository adapter of an user repository for sqlite
+type userRepository struct {
+	infrastructure.PostgresRepository
+}
+
+// NewUserRepository creates a user repository for sqlite
+func NewUserRepository(db *sql.DB) ports.UserRepository {
+	return &userRepository{
+		infrastructure.PostgresRepository{
+			DB: db,
+		},
+	}
+}
+
+func (r *userRepository) Create(ctx context.Context, user entities.User) (string, error) {
+	var ID string
+	err := withTransaction(ctx, r.DB, func(tx *sql.Tx) (err error) {
+		ID, err = createUser(ctx, tx, user)
+		return
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return ID, nil
+}
+
+func (r *userRepository) Get(ctx context.Context, filter models.UserFilter, skip, take *int) ([]entities.User, error) {
+	where, args := userWhere(filter)
+
+	// sqlite only allows an offset after a limit, where a negative one means no limit
+	limit, offset := -1, 0
+	if take != nil {
+		limit = *take
+	}
+	if skip != nil {
+		offset = *skip
+	}
+
+	q := fmt.Sprintf(`
+	SELECT %s
+	    FROM users %s ORDER BY rowid LIMIT %d OFFSET %d;
+	`, userColumns, where, limit, offset)
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var users []entities.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(users) < 1 {
+		return nil, wrappers.NewNonExistentErr(sql.ErrNoRows)
+	}
+
+	return users, nil
+}
+
+func (r *userRepository) GetByID(ctx context.Context, ID string) (entities.User, error) {
+	q := fmt.Sprintf(`
+	SELECT %s
+	    FROM users WHERE id = ?1;
+	`, userColumns)
+
+	u, err := scanUser(conn(ctx, r.DB).QueryRowContext(ctx, q, ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = wrappers.NewNonExistentErr(err)
+		}
+		return entities.User{}, err
+	}
+
+	return u, nil
+}
+
+func (r *userRepository) Update(ctx context.Context, ID string, user entities.User) error {
+	return withTransaction(ctx, r.DB, func(tx *sql.Tx) error {
+		return updateUser(ctx, tx, ID, user)
+	})
+}
+
+func (r *userRepository) Delete(ctx context.Context, ID string) error {
+	return withTransaction(ctx, r.DB, func(tx *sql.Tx) error {
+		return deleteUser(ctx, tx, ID)
+	})
+}
+
+func (r *userRepository) CreateMany(ctx context.Context, users []entities.User) ([]string, error) {
+	var result []string
+	err := withTransaction(ctx, r.DB, func(tx *sql.Tx) error {
+		for _, user := range users {
+			ID, err := createUser(ctx, tx, user)
+			if err != nil {
+				return err
+			}
+			result = append(result, ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *userRepository) UpdateMany(ctx context.Context, IDs []string, users []entities.User) error {
+	return withTransaction(ctx, r.DB, func(tx *sql.Tx) error {
+		for i, user := range users {
+			if err := updateUser(ctx, tx, IDs[i], user); err != nil {
+				return withID(err, IDs[i])
+			}
+		}
+		return nil
+	})
+}
+
+func (r *userRepository) DeleteMany(ctx context.Context, IDs []string) error {
+	return withTransaction(ctx, r.DB, func(tx *sql.Tx) error {
+		for _, ID := range IDs {
+			if err := deleteUser(ctx, tx, ID); err != nil {
+				return withID(err, ID)
+			}
+		}
+		return nil
+	})
+}
+
+// Search matches the words of the query as prefixes of the words in the name, the surnames and the email of the users,
+// ignoring their case and diacritics, and ranks the matches in the name or the surnames above the ones in the email
+func (r *userRepository) Search(ctx context.Context, query string, offset, limit int) ([]entities.User, error) {
+	users := []entities.User{}
+
+	// every word scores once for the names and once for the email, through the full-text index kept in sync by the triggers
+	var matches []string
+	var args []interface{}
+	for _, term := range strings.Fields(query) {
+		tokens := searchTokens(term)
+		if len(tokens) == 0 {
+			continue
+		}
+		args = append(args, fmt.Sprintf("(%s) OR (%s)", searchExpr("name", tokens), searchExpr("surnames", tokens)), searchExpr("email", tokens))
+		matches = append(matches,
+			fmt.Sprintf(`SELECT docid, 3 AS score FROM users_search WHERE users_search MATCH ?%d`, len(args)-1),
+			fmt.Sprintf(`SELECT docid, 1 AS score FROM users_search WHERE users_search MATCH ?%d`, len(args)),
+		)
+	}
+	if len(matches) == 0 {
+		return users, nil
+	}
+	args = append(args, limit, offset)
+
+	q := fmt.Sprintf(`
+	SELECT %s
+	    FROM users
+	    JOIN (SELECT docid, SUM(score) AS score FROM (%s) GROUP BY docid) AS matches ON matches.docid = users.rowid
+	    ORDER BY matches.score DESC, id
+	    LIMIT ?%d OFFSET ?%d;
+	`, userColumns, strings.Join(matches, " UNION ALL "), len(args)-1, len(args))
+
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+// searchTokens splits a word of the query in the same tokens as the unicode61 tokenizer of the full-text index,
+// lowering them so that they are never read as operators of the full-text query syntax
+func searchTokens(term string) []string {
+	return strings.FieldsFunc(strings.ToLower(term), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// searchExpr builds the full-text expression matching all the tokens as prefixes of the words in the column
+func searchExpr(column string, tokens []string) string {
+	exprs := make([]string, len(tokens))
+	for i, token := range tokens {
+		exprs[i] = fmt.Sprintf("%s:%s*", column, token)
+	}
+	return strings.Join(exprs, " ")
+}
+
+func (r *userRepository) Iterate(ctx context.Context, filter models.UserFilter, fn func(user entities.User) error) error {
+	where, args := userWhere(filter)
+
+	q := fmt.Sprintf(`
+	SELECT %s
+	    FROM users %s ORDER BY id;
+	`, userColumns, where)
+
+	// rows are read as they are scanned, so the result set is never fully loaded into memory
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return err
+		}
+		if err = fn(u); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// userWhere builds the WHERE clause of the users matching the filter, along with its arguments
+func userWhere(filter models.UserFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Name != "" {
+		add("name = ?%d", filter.Name)
+	}
+	if filter.Surnames != "" {
+		add("surnames = ?%d", filter.Surnames)
+	}
+	if filter.EmailNormalized != "" {
+		add("email_normalized = ?%d", filter.EmailNormalized)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		add("created_at >= ?%d", filter.CreatedFrom.UTC())
+	}
+	if !filter.CreatedTo.IsZero() {
+		add("created_at < ?%d", filter.CreatedTo.UTC())
+	}
+	if filter.ClaimID != nil {
+		add("EXISTS (SELECT 1 FROM json_each(claim_ids) WHERE value = ?%d)", *filter.ClaimID)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// createUser inserts the user and its created event to the outbox in the given transaction
+func createUser(ctx context.Context, tx *sql.Tx, u entities.User) (string, error) {
+	q := `
+	INSERT INTO users (id, name, surnames, email, email_normalized, password_hash, claim_ids, avatar_url, created_at, updated_at)
+	    VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10);
+	`
+
+	u.ID = uuid.NewString()
+	_, err := tx.ExecContext(
+		ctx, q, u.ID, u.Name, u.Surnames, u.Email, u.EmailNormalized, u.PasswordHash, asJSON(u.ClaimIDs), u.AvatarURL, u.CreatedAt, u.UpdatedAt,
+	)
+	if err != nil {
+		return "", emailInUseIfDuplicate(err, u.EmailNormalized)
+	}
+
+	return u.ID, insertEvent(ctx, tx, entities.NewUserEvent(entities.EventTypeUserCreated, u.ID, &u))
+}
+
+// updateUser updates the user and writes its updated event to the outbox in the given transaction
+func updateUser(ctx context.Context, tx *sql.Tx, ID string, u entities.User) error {
+	q := `
+	UPDATE users SET name=?1, surnames=?2, email=?3, email_normalized=?4, password_hash=?5, claim_ids=?6, avatar_url=?7, updated_at=?8
+	    WHERE id=?9;
+	`
+
+	result, err := tx.ExecContext(
+		ctx, q, u.Name, u.Surnames, u.Email, u.EmailNormalized, u.PasswordHash, asJSON(u.ClaimIDs), u.AvatarURL, u.UpdatedAt, ID,
+	)
+	if err != nil {
+		return emailInUseIfDuplicate(err, u.EmailNormalized)
+	}
+	if err = checkAffected(result); err != nil {
+		return err
+	}
+
+	return insertEvent(ctx, tx, entities.NewUserEvent(entities.EventTypeUserUpdated, ID, &u))
+}
+
+// deleteUser deletes the user and writes its deleted event to the outbox in the given transaction
+func deleteUser(ctx context.Context, tx *sql.Tx, ID string) error {
+	q := `DELETE FROM users WHERE id=?1;`
+
+	result, err := tx.ExecContext(ctx, q, ID)
+	if err != nil {
+		return err
+	}
+	if err = checkAffected(result); err != nil {
+		return err
+	}
+
+	return insertEvent(ctx, tx, entities.NewUserEvent(entities.EventTypeUserDeleted, ID, nil))
+}
+
+// emailInUseIfDuplicate maps the unique violations, only raised by the normalized emails of the users, to an AlreadyExistsErr
+func emailInUseIfDuplicate(err error, emailNormalized string) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return entities.NewEmailInUseErr(emailNormalized)
+	}
+	return err
+}
+
+// scanUser scans a row holding the userColumns
+func scanUser(row interface {
+	Scan(dest ...interface{}) error
+}) (entities.User, error) {
+	var u entities.User
+	err := row.Scan(&u.ID, &u.Name, &u.Surnames, &u.Email, &u.EmailNormalized, &u.PasswordHash, asJSON(&u.ClaimIDs), &u.AvatarURL, &u.CreatedAt, &u.UpdatedAt)
+	return u, err
+}
@@ -0,0 +1,211 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/test/repositorytest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUserRepository_Suite checks that the sqlite user repository passes the conformance test suite of the user repositories
+func TestUserRepository_Suite(t *testing.T) {
+	repositorytest.UserRepository(t, NewUserRepository(newTestDB(t)))
+}
+
+// TestUserCreate_OutboxEvent checks that Create writes the created event of the user to the outbox
+func TestUserCreate_OutboxEvent(t *testing.T) {
+	// Arrange
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+
+	// Act
+	ID, err := repo.Create(context.Background(), entities.User{EmailNormalized: "test@test.com"})
+
+	// Assert
+	assert.Nil(t, err)
+	events, _ := NewOutboxRepository(db).Pending(context.Background(), 10)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, entities.EventTypeUserCreated, events[0].Type)
+		assert.Equal(t, ID, events[0].AggregateID)
+	}
+}
+
+// TestUserDeleteMany_Dependents checks that DeleteMany deletes the invitations and email changes of the deleted users, leaving none pending
+func TestUserDeleteMany_Dependents(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+	invitations := NewInvitationRepository(db)
+	emailChanges := NewEmailChangeRepository(db)
+	IDs, err := repo.CreateMany(ctx, []entities.User{{EmailNormalized: "deleted@test.com"}, {EmailNormalized: "kept@test.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ID := range IDs {
+		if _, err = invitations.Create(ctx, entities.Invitation{UserID: ID, ExpiresAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = emailChanges.Create(ctx, entities.EmailChange{UserID: ID, ExpiresAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Act
+	err = repo.DeleteMany(ctx, IDs[:1])
+
+	// Assert
+	assert.Nil(t, err)
+	pending, _ := invitations.GetAll(ctx)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, IDs[1], pending[0].UserID)
+	}
+	changes, _ := emailChanges.GetByUserID(ctx, IDs[0])
+	assert.Empty(t, changes)
+	changes, _ = emailChanges.GetByUserID(ctx, IDs[1])
+	assert.Len(t, changes, 1)
+}
+
+// TestUserCreateMany_RollbackDiscardsEvents checks that CreateMany writes no events to the outbox when it fails
+func TestUserCreateMany_RollbackDiscardsEvents(t *testing.T) {
+	// Arrange
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+	users := []entities.User{{EmailNormalized: "test@test.com"}, {EmailNormalized: "test@test.com"}}
+
+	// Act
+	_, err := repo.CreateMany(context.Background(), users)
+
+	// Assert
+	assert.NotNil(t, err)
+	events, _ := NewOutboxRepository(db).Pending(context.Background(), 10)
+	assert.Empty(t, events)
+}
+
+// TestUserIterate_Filter checks that Iterate only calls fn for the users matching the filter, sorted by ID
+func TestUserIterate_Filter(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository(newTestDB(t))
+	claimID := int32(0)
+	now := time.Now().UTC()
+	for _, user := range []entities.User{
+		{EmailNormalized: "a@test.com", ClaimIDs: []int32{claimID}, CreatedAt: now},
+		{EmailNormalized: "b@test.com", CreatedAt: now},
+		{EmailNormalized: "c@test.com", ClaimIDs: []int32{claimID}, CreatedAt: now.Add(-time.Hour)},
+	} {
+		if _, err := repo.Create(context.Background(), user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	filter := models.UserFilter{CreatedFrom: now.Add(-time.Minute), ClaimID: &claimID}
+
+	// Act
+	var emails []string
+	err := repo.Iterate(context.Background(), filter, func(user entities.User) error {
+		emails = append(emails, user.EmailNormalized)
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a@test.com"}, emails)
+}
+
+// TestUserSearch_Ranking checks that Search ranks the users matching the query in their names above the ones matching it in their emails
+func TestUserSearch_Ranking(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository(newTestDB(t))
+	emailMatchID, err := repo.Create(context.Background(), entities.User{Name: "Bob", Email: "alice@test.com", EmailNormalized: "alice@test.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nameMatchID, err := repo.Create(context.Background(), entities.User{Name: "Alice", Email: "a@test.com", EmailNormalized: "a@test.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = repo.Create(context.Background(), entities.User{Name: "Carol", Email: "c@test.com", EmailNormalized: "c@test.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	users, err := repo.Search(context.Background(), "ALICE", 0, 10)
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, users, 2) {
+		assert.Equal(t, nameMatchID, users[0].ID)
+		assert.Equal(t, emailMatchID, users[1].ID)
+	}
+}
+
+// TestUserSearch_Operators checks that Search matches the operators of the full-text query syntax literally
+func TestUserSearch_Operators(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository(newTestDB(t))
+	ID, err := repo.Create(context.Background(), entities.User{Name: "Alice", EmailNormalized: "a@test.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	users, err := repo.Search(context.Background(), `"Alice* OR NOT % (`, 0, 10)
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, users, 1) {
+		assert.Equal(t, ID, users[0].ID)
+	}
+}
+
+// TestUserSearch_Diacritics checks that Search matches the words of the users regardless of their diacritics
+func TestUserSearch_Diacritics(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository(newTestDB(t))
+	ID, err := repo.Create(context.Background(), entities.User{Name: "José", Surnames: "Muñoz", EmailNormalized: "a@test.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	users, err := repo.Search(context.Background(), "jose munoz", 0, 10)
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, users, 1) {
+		assert.Equal(t, ID, users[0].ID)
+	}
+}
+
+// TestUserSearch_Sync checks that the full-text index of Search follows the updates and the deletions of the users
+func TestUserSearch_Sync(t *testing.T) {
+	// Arrange
+	repo := NewUserRepository(newTestDB(t))
+	updatedID, err := repo.Create(context.Background(), entities.User{Name: "Alice", EmailNormalized: "a@test.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deletedID, err := repo.Create(context.Background(), entities.User{Name: "Bob", EmailNormalized: "b@test.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = repo.Update(context.Background(), updatedID, entities.User{Name: "Carol", EmailNormalized: "a@test.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.Delete(context.Background(), deletedID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	for query, expectedLen := range map[string]int{"alice": 0, "bob": 0, "carol": 1} {
+		users, err := repo.Search(context.Background(), query, 0, 10)
+		assert.Nil(t, err)
+		assert.Len(t, users, expectedLen, query)
+	}
+}
@@ -0,0 +1,231 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+)
+
+const (
+	// userChangesBatchSize maximum number of changes read at once
+	userChangesBatchSize = 100
+	// userChangesPollInterval interval at which the changes are read, as sqlite cannot notify them
+	userChangesPollInterval = time.Second
+)
+
+// userWatcher adapter of a user watcher for sqlite, based on polling.
+// The resume tokens are the sequence numbers of the user_changes table, written by triggers on the users table.
+type userWatcher struct {
+	infrastructure.PostgresRepository
+	interval time.Duration
+}
+
+// NewUserWatcher creates a user watcher for sqlite
+func NewUserWatcher(db *sql.DB) ports.UserWatcher {
+	return &userWatcher{
+		PostgresRepository: infrastructure.PostgresRepository{
+			DB: db,
+		},
+		interval: userChangesPollInterval,
+	}
+}
+
+func (w *userWatcher) Watch(ctx context.Context, resumeToken string, fn func(change entities.UserChange) error) error {
+	seq, err := w.resumeSeq(ctx, resumeToken)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		seq, err = w.changesAfter(ctx, seq, fn)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune deletes the changes up to the last one that occurred before the given time, so that the remaining ones keep
+// following each other in sequence order, and records the sequence number of the last deleted one
+func (w *userWatcher) Prune(ctx context.Context, before time.Time) (int, error) {
+	var pruned int
+	err := withTransaction(ctx, w.DB, func(tx *sql.Tx) error {
+		var seq sql.NullInt64
+		err := tx.QueryRowContext(ctx, `SELECT MAX(seq) FROM user_changes WHERE occurred_at < ?1;`, before.UTC()).Scan(&seq)
+		if err != nil || !seq.Valid {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM user_changes WHERE seq <= ?1;`, seq.Int64)
+		if err != nil {
+			return err
+		}
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		pruned = int(deleted)
+
+		_, err = tx.ExecContext(ctx, `UPDATE user_changes_pruned SET seq = MAX(seq, ?1);`, seq.Int64)
+		return err
+	})
+	return pruned, err
+}
+
+// resumeSeq returns the sequence number of the resume token, or the one of the last change when empty
+func (w *userWatcher) resumeSeq(ctx context.Context, resumeToken string) (int64, error) {
+	if resumeToken == "" {
+		var seq int64
+		err := w.DB.QueryRowContext(ctx, `SELECT MAX(COALESCE(MAX(seq), 0), (SELECT seq FROM user_changes_pruned)) FROM user_changes;`).Scan(&seq)
+		return seq, err
+	}
+
+	seq, err := strconv.ParseInt(resumeToken, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, wrappers.NewValidationErr(fmt.Errorf("resume token %s is not valid", resumeToken))
+	}
+	return seq, nil
+}
+
+// changesAfter calls fn for every change after the given sequence number, and returns the sequence number of the last one
+func (w *userWatcher) changesAfter(ctx context.Context, seq int64, fn func(change entities.UserChange) error) (int64, error) {
+	for {
+		changes, err := w.readChanges(ctx, seq)
+		if err != nil {
+			return seq, err
+		}
+		if err = w.checkPruned(ctx, seq, changes); err != nil {
+			return seq, err
+		}
+
+		for _, change := range changes {
+			if err = fn(change); err != nil {
+				return seq, err
+			}
+			seq, _ = strconv.ParseInt(change.ResumeToken, 10, 64)
+		}
+
+		if len(changes) < userChangesBatchSize {
+			return seq, nil
+		}
+	}
+}
+
+// checkPruned returns a validation error when the changes after the sequence number may have been pruned before being read,
+// which is when the last pruned change comes after it and the read changes do not start before the last pruned one
+func (w *userWatcher) checkPruned(ctx context.Context, seq int64, changes []entities.UserChange) error {
+	var pruned int64
+	if err := w.DB.QueryRowContext(ctx, `SELECT seq FROM user_changes_pruned;`).Scan(&pruned); err != nil {
+		return err
+	}
+	if pruned <= seq {
+		return nil
+	}
+
+	if len(changes) > 0 {
+		first, _ := strconv.ParseInt(changes[0].ResumeToken, 10, 64)
+		if first <= pruned {
+			return nil
+		}
+	}
+	return wrappers.NewValidationErr(fmt.Errorf("resume token %d has expired", seq))
+}
+
+// userChangeColumns columns of the changes read along with the state of the users after them
+const userChangeColumns = `c.seq, c.type, c.user_id, c.occurred_at,
+	       c.name, c.surnames, c.email, c.email_normalized, c.claim_ids, c.avatar_url, c.created_at, c.updated_at`
+
+// readChanges reads a batch of changes, along with the state of the changed users after every change.
+// The rows are read before calling any callback, so that no connection is held while the changes are being sent.
+func (w *userWatcher) readChanges(ctx context.Context, seq int64) ([]entities.UserChange, error) {
+	q := `
+	SELECT ` + userChangeColumns + `
+	    FROM user_changes c
+	    WHERE c.seq > ?1 ORDER BY c.seq LIMIT ?2;
+	`
+
+	return w.queryChanges(w.DB.QueryContext(ctx, q, seq, userChangesBatchSize))
+}
+
+// GetByUserID returns the changes of the user still recorded
+func (w *userWatcher) GetByUserID(ctx context.Context, userID string) ([]entities.UserChange, error) {
+	q := `
+	SELECT ` + userChangeColumns + `
+	    FROM user_changes c
+	    WHERE c.user_id = ?1 ORDER BY c.seq;
+	`
+
+	return w.queryChanges(conn(ctx, w.DB).QueryContext(ctx, q, userID))
+}
+
+// EraseStates clears the states of the user recorded along with its changes, as the trigger does when the user is deleted
+func (w *userWatcher) EraseStates(ctx context.Context, userID string) error {
+	q := `
+	UPDATE user_changes
+	    SET name = NULL, surnames = NULL, email = NULL, email_normalized = NULL,
+	        claim_ids = NULL, avatar_url = NULL, created_at = NULL, updated_at = NULL
+	    WHERE user_id = ?1;
+	`
+
+	_, err := conn(ctx, w.DB).ExecContext(ctx, q, userID)
+	return err
+}
+
+// queryChanges scans the changes read with userChangeColumns
+func (w *userWatcher) queryChanges(rows *sql.Rows, err error) ([]entities.UserChange, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var changes []entities.UserChange
+	for rows.Next() {
+		var c entities.UserChange
+		var changeSeq int64
+		var name, surnames, email, emailNormalized, avatarURL sql.NullString
+		var claimIDs []int32
+		var createdAt, updatedAt sql.NullTime
+		err = rows.Scan(&changeSeq, &c.Type, &c.UserID, &c.OccurredAt, &name, &surnames, &email, &emailNormalized, asJSON(&claimIDs), &avatarURL, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		c.ResumeToken = strconv.FormatInt(changeSeq, 10)
+		// the changes of a deleted or erased user have no state
+		if email.Valid {
+			c.User = &entities.User{
+				ID:              c.UserID,
+				Name:            name.String,
+				Surnames:        surnames.String,
+				Email:           email.String,
+				EmailNormalized: emailNormalized.String,
+				ClaimIDs:        claimIDs,
+				AvatarURL:       avatarURL.String,
+				CreatedAt:       createdAt.Time,
+				UpdatedAt:       updatedAt.Time,
+			}
+		}
+		changes = append(changes, c)
+	}
+
+	return changes, rows.Err()
+}
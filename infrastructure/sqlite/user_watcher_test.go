@@ -0,0 +1,238 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewUserWatcher_Ok checks that NewUserWatcher creates a new userWatcher struct
+func TestNewUserWatcher_Ok(t *testing.T) {
+	// Act
+	watcher := NewUserWatcher(newTestDB(t))
+
+	// Assert
+	assert.NotEmpty(t, watcher)
+}
+
+// TestUserWatch_Ok checks that Watch calls fn for the changes after the resume token, along with the state of the users after every change
+func TestUserWatch_Ok(t *testing.T) {
+	// Arrange
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+	ID, err := repo.Create(context.Background(), entities.User{Name: "test", EmailNormalized: "test@test.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.Update(context.Background(), ID, entities.User{Name: "updated", EmailNormalized: "test@test.com"}); err != nil {
+		t.Fatal(err)
+	}
+	watcher := &userWatcher{
+		PostgresRepository: NewUserWatcher(db).(*userWatcher).PostgresRepository,
+		interval:           time.Millisecond,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Act
+	var changes []entities.UserChange
+	err = watcher.Watch(ctx, "0", func(change entities.UserChange) error {
+		changes = append(changes, change)
+		if len(changes) == 2 {
+			cancel()
+		}
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, entities.UserChangeTypeCreated, changes[0].Type)
+		assert.Equal(t, "1", changes[0].ResumeToken)
+		if assert.NotNil(t, changes[0].User) {
+			assert.Equal(t, ID, changes[0].User.ID)
+			assert.Equal(t, "test", changes[0].User.Name)
+		}
+		assert.Equal(t, entities.UserChangeTypeUpdated, changes[1].Type)
+		assert.Equal(t, "2", changes[1].ResumeToken)
+		assert.Equal(t, ID, changes[1].UserID)
+		if assert.NotNil(t, changes[1].User) {
+			assert.Equal(t, "updated", changes[1].User.Name)
+		}
+		assert.False(t, changes[1].OccurredAt.IsZero())
+	}
+}
+
+// TestUserWatch_Deleted checks that Watch notifies the deleted users without their state
+func TestUserWatch_Deleted(t *testing.T) {
+	// Arrange
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+	ID, err := repo.Create(context.Background(), entities.User{EmailNormalized: "test@test.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.Delete(context.Background(), ID); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Act
+	var changes []entities.UserChange
+	err = NewUserWatcher(db).Watch(ctx, "1", func(change entities.UserChange) error {
+		changes = append(changes, change)
+		cancel()
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, changes, 1) {
+		assert.Equal(t, entities.UserChangeTypeDeleted, changes[0].Type)
+		assert.Nil(t, changes[0].User)
+	}
+}
+
+// TestUserWatch_DeletedStates checks that deleting a user clears its state from its previous changes
+func TestUserWatch_DeletedStates(t *testing.T) {
+	// Arrange
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+	ID, err := repo.Create(context.Background(), entities.User{Name: "test", EmailNormalized: "test@test.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.Delete(context.Background(), ID); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Act
+	var changes []entities.UserChange
+	err = NewUserWatcher(db).Watch(ctx, "0", func(change entities.UserChange) error {
+		changes = append(changes, change)
+		if len(changes) == 2 {
+			cancel()
+		}
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, entities.UserChangeTypeCreated, changes[0].Type)
+		assert.Nil(t, changes[0].User)
+		assert.Equal(t, entities.UserChangeTypeDeleted, changes[1].Type)
+		assert.Nil(t, changes[1].User)
+	}
+}
+
+// TestUserChangesEraseStates_Ok checks that EraseStates clears the states of the user recorded along with its changes, keeping the changes
+func TestUserChangesEraseStates_Ok(t *testing.T) {
+	// Arrange
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+	ID, err := repo.Create(context.Background(), entities.User{Name: "test", EmailNormalized: "test@test.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	watcher := NewUserWatcher(db)
+	recorded, err := watcher.GetByUserID(context.Background(), ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	err = watcher.EraseStates(context.Background(), ID)
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, recorded, 1) {
+		assert.Equal(t, "test", recorded[0].User.Name)
+	}
+	erased, err := watcher.GetByUserID(context.Background(), ID)
+	assert.Nil(t, err)
+	if assert.Len(t, erased, 1) {
+		assert.Equal(t, entities.UserChangeTypeCreated, erased[0].Type)
+		assert.Nil(t, erased[0].User)
+	}
+}
+
+// TestUserWatch_InvalidResumeToken checks that Watch returns a validation error when the resume token is not valid
+func TestUserWatch_InvalidResumeToken(t *testing.T) {
+	// Arrange
+	watcher := NewUserWatcher(newTestDB(t))
+
+	// Act
+	err := watcher.Watch(context.Background(), "invalid", func(change entities.UserChange) error { return nil })
+
+	// Assert
+	assert.ErrorIs(t, err, wrappers.ValidationErr)
+}
+
+// TestUserPrune_Ok checks that Prune deletes the changes before the given time, after which watching can no longer be resumed
+func TestUserPrune_Ok(t *testing.T) {
+	// Arrange
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+	for _, email := range []string{"a@test.com", "b@test.com", "c@test.com"} {
+		if _, err := repo.Create(context.Background(), entities.User{EmailNormalized: email}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec(`UPDATE user_changes SET occurred_at = '2020-01-01 00:00:00.000+00:00' WHERE seq <= 2;`); err != nil {
+		t.Fatal(err)
+	}
+	watcher := &userWatcher{
+		PostgresRepository: NewUserWatcher(db).(*userWatcher).PostgresRepository,
+		interval:           time.Millisecond,
+	}
+
+	// Act
+	pruned, err := watcher.Prune(context.Background(), time.Now().Add(-time.Hour))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 2, pruned)
+
+	err = watcher.Watch(context.Background(), "1", func(change entities.UserChange) error { return nil })
+	assert.ErrorIs(t, err, wrappers.ValidationErr)
+	assert.Equal(t, "resume token 1 has expired", err.Error())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var tokens []string
+	err = watcher.Watch(ctx, "2", func(change entities.UserChange) error {
+		tokens = append(tokens, change.ResumeToken)
+		cancel()
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"3"}, tokens)
+}
+
+// TestUserPrune_EmptyResumeToken checks that Watch starts after the last pruned change when every change got pruned
+func TestUserPrune_EmptyResumeToken(t *testing.T) {
+	// Arrange
+	db := newTestDB(t)
+	if _, err := NewUserRepository(db).Create(context.Background(), entities.User{EmailNormalized: "a@test.com"}); err != nil {
+		t.Fatal(err)
+	}
+	watcher := NewUserWatcher(db).(*userWatcher)
+	if _, err := watcher.Prune(context.Background(), time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	seq, err := watcher.resumeSeq(context.Background(), "")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), seq)
+}
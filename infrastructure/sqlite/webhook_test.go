@@ -0,0 +1,44 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWebhookDeliveryClaim_Ok checks that Claim returns the due pending deliveries up to the limit and postpones them until the lease expires
+func TestWebhookDeliveryClaim_Ok(t *testing.T) {
+	// Arrange
+	repo := NewWebhookDeliveryRepository(newTestDB(t))
+	now := time.Now().UTC()
+	if err := repo.CreateMany(context.Background(), []entities.WebhookDelivery{
+		{SubscriptionID: "2", Body: []byte(`{}`), Status: entities.WebhookDeliveryStatusPending, NextAttemptAt: now.Add(-time.Minute), CreatedAt: now.Add(time.Second)},
+		{SubscriptionID: "1", Body: []byte(`{}`), Status: entities.WebhookDeliveryStatusPending, NextAttemptAt: now.Add(-2 * time.Minute), CreatedAt: now},
+		{SubscriptionID: "3", Body: []byte(`{}`), Status: entities.WebhookDeliveryStatusPending, NextAttemptAt: now.Add(-time.Second), CreatedAt: now.Add(2 * time.Second)},
+		{SubscriptionID: "4", Body: []byte(`{}`), Status: entities.WebhookDeliveryStatusPending, NextAttemptAt: now.Add(time.Minute), CreatedAt: now},
+		{SubscriptionID: "5", Body: []byte(`{}`), Status: entities.WebhookDeliveryStatusDead, NextAttemptAt: now.Add(-time.Minute), CreatedAt: now},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	leaseUntil := now.Add(5 * time.Minute)
+
+	// Act
+	deliveries, err := repo.Claim(context.Background(), now, leaseUntil, 2)
+
+	// Assert
+	assert.Nil(t, err)
+	if assert.Len(t, deliveries, 2) {
+		assert.Equal(t, "1", deliveries[0].SubscriptionID)
+		assert.Equal(t, "2", deliveries[1].SubscriptionID)
+		assert.True(t, leaseUntil.Equal(deliveries[0].NextAttemptAt))
+	}
+	deliveries, _ = repo.Claim(context.Background(), now, leaseUntil, 10)
+	if assert.Len(t, deliveries, 1) {
+		assert.Equal(t, "3", deliveries[0].SubscriptionID)
+	}
+	deliveries, _ = repo.Claim(context.Background(), leaseUntil, leaseUntil.Add(5*time.Minute), 10)
+	assert.Len(t, deliveries, 4)
+}
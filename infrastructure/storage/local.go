@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// localStorage adapter of a blob storage that keeps the objects as files of a local directory
+type localStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage creates a blob storage that writes every object to a file of the given directory, named after its key.
+// The directory is expected to be served under the given base URL, so the content type is not stored but sniffed when served.
+func NewLocalStorage(dir, baseURL string) ports.BlobStorage {
+	return &localStorage{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Put writes the object to a temporary file and then renames it, so that a partially written object is never served
+func (s *localStorage) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	if err = os.Chmod(file.Name(), 0644); err != nil {
+		return "", err
+	}
+	if err = os.Rename(file.Name(), path); err != nil {
+		return "", err
+	}
+
+	return objectURL(s.baseURL, key), nil
+}
+
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	}
+	return err
+}
+
+// path returns the path of the file of the key, which cannot point outside of the directory
+func (s *localStorage) path(key string) (string, error) {
+	if !fs.ValidPath(key) || key == "." {
+		return "", fmt.Errorf("key %s is not valid", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// objectURL returns the URL of the key under the base URL, escaping every segment of the key
+func objectURL(baseURL, key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return baseURL + "/" + strings.Join(segments, "/")
+}
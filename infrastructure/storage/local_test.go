@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLocalPut_Ok checks that Put writes the object to the file of its key and returns its URL under the base URL
+func TestLocalPut_Ok(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	storage := NewLocalStorage(dir, "/v1/blobs/")
+	data := []byte("test")
+
+	// Act
+	url, err := storage.Put(context.Background(), "avatars/test id/avatar", "image/png", data)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "/v1/blobs/avatars/test%20id/avatar", url)
+	written, err := os.ReadFile(filepath.Join(dir, "avatars", "test id", "avatar"))
+	assert.Nil(t, err)
+	assert.Equal(t, data, written)
+}
+
+// TestLocalPut_InvalidKey checks that Put returns an error when the key points outside of the directory
+func TestLocalPut_InvalidKey(t *testing.T) {
+	// Arrange
+	storage := NewLocalStorage(t.TempDir(), "/v1/blobs")
+
+	// Act
+	_, err := storage.Put(context.Background(), "../avatar", "image/png", []byte("test"))
+
+	// Assert
+	assert.Equal(t, "key ../avatar is not valid", err.Error())
+}
+
+// TestLocalDelete_Ok checks that Delete removes the file of the key and does not fail when it no longer exists
+func TestLocalDelete_Ok(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	storage := NewLocalStorage(dir, "/v1/blobs")
+	_, err := storage.Put(context.Background(), "avatars/test-id/avatar", "image/png", []byte("test"))
+	assert.Nil(t, err)
+
+	// Act
+	err = storage.Delete(context.Background(), "avatars/test-id/avatar")
+	secondErr := storage.Delete(context.Background(), "avatars/test-id/avatar")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, secondErr)
+	_, err = os.Stat(filepath.Join(dir, "avatars", "test-id", "avatar"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+const (
+	// s3Algorithm signing algorithm of the requests, AWS Signature Version 4
+	s3Algorithm = "AWS4-HMAC-SHA256"
+	// s3Service name of the service in the scope of the signatures
+	s3Service = "s3"
+	// s3DateFormat format of the x-amz-date header
+	s3DateFormat = "20060102T150405Z"
+	// s3DefaultRegion region of the requests when none is given, MinIO accepts it unless configured otherwise
+	s3DefaultRegion = "us-east-1"
+)
+
+// S3Options options of an S3-compatible blob storage
+type S3Options struct {
+	// Endpoint base URL of the S3 API, such as https://s3.eu-west-1.amazonaws.com or http://localhost:9000 for a local MinIO
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PublicURL base URL the objects are downloaded from, such as a CDN, defaults to the URL of the bucket in the endpoint
+	PublicURL string
+}
+
+// s3Storage adapter of a blob storage that keeps the objects in a bucket of an S3-compatible service, such as AWS S3 or MinIO
+type s3Storage struct {
+	options S3Options
+	client  *http.Client
+	now     func() time.Time
+}
+
+// NewS3Storage creates a blob storage that puts every object in the bucket with path-style requests signed with AWS Signature Version 4,
+// so that it works with any S3-compatible service. The bucket is created when it does not exist yet,
+// and it is expected to allow anonymous reads of the objects unless a public URL is given.
+func NewS3Storage(ctx context.Context, options S3Options, client *http.Client) (ports.BlobStorage, error) {
+	options.Endpoint = strings.TrimSuffix(options.Endpoint, "/")
+	options.PublicURL = strings.TrimSuffix(options.PublicURL, "/")
+	if options.Region == "" {
+		options.Region = s3DefaultRegion
+	}
+	if options.PublicURL == "" {
+		options.PublicURL = options.Endpoint + "/" + options.Bucket
+	}
+
+	s := &s3Storage{
+		options: options,
+		client:  client,
+		now:     time.Now,
+	}
+	return s, s.ensureBucket(ctx)
+}
+
+func (s *s3Storage) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	headers := map[string]string{"Content-Type": contentType}
+	if err := s.do(ctx, http.MethodPut, "/"+s.options.Bucket+"/"+key, headers, data, http.StatusOK); err != nil {
+		return "", err
+	}
+	return objectURL(s.options.PublicURL, key), nil
+}
+
+// Delete removes the object, S3 responds with no content even when there is no such object
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	return s.do(ctx, http.MethodDelete, "/"+s.options.Bucket+"/"+key, nil, nil, http.StatusNoContent, http.StatusNotFound)
+}
+
+// ensureBucket creates the bucket when it does not exist, in the region of the storage
+func (s *s3Storage) ensureBucket(ctx context.Context) error {
+	err := s.do(ctx, http.MethodHead, "/"+s.options.Bucket, nil, nil, http.StatusOK)
+	if err == nil {
+		return nil
+	}
+
+	var body []byte
+	if s.options.Region != s3DefaultRegion {
+		body = []byte(fmt.Sprintf(`<CreateBucketConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><LocationConstraint>%s</LocationConstraint></CreateBucketConfiguration>`, s.options.Region))
+	}
+	if err = s.do(ctx, http.MethodPut, "/"+s.options.Bucket, nil, body, http.StatusOK); err != nil {
+		return fmt.Errorf("cannot create bucket %s: %w", s.options.Bucket, err)
+	}
+	return nil
+}
+
+// do sends a signed request and fails when the response status is not one of the expected ones
+func (s *s3Storage) do(ctx context.Context, method, path string, headers map[string]string, body []byte, expected ...int) error {
+	escapedPath := s3EscapePath(path)
+	req, err := http.NewRequestWithContext(ctx, method, s.options.Endpoint+escapedPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	s.sign(req, escapedPath, body, s.now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("s3 %s %s responded with status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(message)))
+}
+
+// sign adds the AWS Signature Version 4 headers to the request, signing all of its headers along with the host
+func (s *s3Storage) sign(req *http.Request, escapedPath string, body []byte, now time.Time) {
+	amzDate := now.Format(s3DateFormat)
+	date := amzDate[:8]
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapedPath,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.options.Region, s3Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{s3Algorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.options.SecretAccessKey), date)
+	key = hmacSHA256(key, s.options.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", s3Algorithm, s.options.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// s3EscapePath escapes every segment of the path as required by the signatures, where only the unreserved characters are kept
+func s3EscapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c == '/' || c == '-' || c == '_' || c == '.' || c == '~' ||
+			('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
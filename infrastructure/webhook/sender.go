@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+)
+
+// httpSender adapter of a webhook sender that posts the deliveries over HTTP
+type httpSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender creates a webhook sender that posts the deliveries as JSON with the given client.
+// Any response other than 2xx is considered a failure, so the delivery gets retried.
+func NewHTTPSender(client *http.Client) ports.WebhookSender {
+	return &httpSender{
+		client: client,
+	}
+}
+
+func (s *httpSender) Send(ctx context.Context, URL string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook %s responded with status %d", URL, resp.StatusCode)
+	}
+	return nil
+}
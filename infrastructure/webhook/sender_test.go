@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSend_Ok checks that Send posts the body with the given headers
+func TestSend_Ok(t *testing.T) {
+	// Arrange
+	var received []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get("X-Webhook-Signature")
+		received, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := NewHTTPSender(server.Client())
+	body := []byte(`{"id":"test-event"}`)
+
+	// Act
+	err := sender.Send(context.Background(), server.URL, map[string]string{"X-Webhook-Signature": "sha256=test"}, body)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, body, received)
+	assert.Equal(t, "sha256=test", signature)
+}
+
+// TestSend_StatusError checks that Send returns an error when the webhook does not respond with a 2xx status
+func TestSend_StatusError(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sender := NewHTTPSender(server.Client())
+
+	// Act
+	err := sender.Send(context.Background(), server.URL, nil, []byte("{}"))
+
+	// Assert
+	assert.NotNil(t, err)
+}
+
+// TestSend_RequestError checks that Send returns an error when the webhook cannot be reached
+func TestSend_RequestError(t *testing.T) {
+	// Arrange
+	sender := NewHTTPSender(http.DefaultClient)
+
+	// Act
+	err := sender.Send(context.Background(), "http://127.0.0.1:0", nil, []byte("{}"))
+
+	// Assert
+	assert.NotNil(t, err)
+}
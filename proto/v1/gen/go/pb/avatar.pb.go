@@ -0,0 +1,319 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: avatar.proto
+
+package pb
+
+import (
+	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type UploadAvatarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Content       []byte                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadAvatarRequest) Reset() {
+	*x = UploadAvatarRequest{}
+	mi := &file_avatar_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadAvatarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadAvatarRequest) ProtoMessage() {}
+
+func (x *UploadAvatarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_avatar_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadAvatarRequest.ProtoReflect.Descriptor instead.
+func (*UploadAvatarRequest) Descriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UploadAvatarRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UploadAvatarRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *UploadAvatarRequest) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type UploadAvatarResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AvatarUrl     string                 `protobuf:"bytes,1,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	Thumbnails    []*AvatarThumbnail     `protobuf:"bytes,2,rep,name=thumbnails,proto3" json:"thumbnails,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadAvatarResponse) Reset() {
+	*x = UploadAvatarResponse{}
+	mi := &file_avatar_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadAvatarResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadAvatarResponse) ProtoMessage() {}
+
+func (x *UploadAvatarResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_avatar_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadAvatarResponse.ProtoReflect.Descriptor instead.
+func (*UploadAvatarResponse) Descriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UploadAvatarResponse) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *UploadAvatarResponse) GetThumbnails() []*AvatarThumbnail {
+	if x != nil {
+		return x.Thumbnails
+	}
+	return nil
+}
+
+type AvatarThumbnail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Size          int32                  `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AvatarThumbnail) Reset() {
+	*x = AvatarThumbnail{}
+	mi := &file_avatar_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AvatarThumbnail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AvatarThumbnail) ProtoMessage() {}
+
+func (x *AvatarThumbnail) ProtoReflect() protoreflect.Message {
+	mi := &file_avatar_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AvatarThumbnail.ProtoReflect.Descriptor instead.
+func (*AvatarThumbnail) Descriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AvatarThumbnail) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *AvatarThumbnail) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type DeleteAvatarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAvatarRequest) Reset() {
+	*x = DeleteAvatarRequest{}
+	mi := &file_avatar_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAvatarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAvatarRequest) ProtoMessage() {}
+
+func (x *DeleteAvatarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_avatar_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAvatarRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAvatarRequest) Descriptor() ([]byte, []int) {
+	return file_avatar_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DeleteAvatarRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_avatar_proto protoreflect.FileDescriptor
+
+const file_avatar_proto_rawDesc = "" +
+	"\n" +
+	"\favatar.proto\x12\x06avatar\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"b\n" +
+	"\x13UploadAvatarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\fR\acontent\"n\n" +
+	"\x14UploadAvatarResponse\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x01 \x01(\tR\tavatarUrl\x127\n" +
+	"\n" +
+	"thumbnails\x18\x02 \x03(\v2\x17.avatar.AvatarThumbnailR\n" +
+	"thumbnails\"7\n" +
+	"\x0fAvatarThumbnail\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x05R\x04size\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\"%\n" +
+	"\x13DeleteAvatarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id2\x94\x02\n" +
+	"\rAvatarService\x12I\n" +
+	"\fUploadAvatar\x12\x1b.avatar.UploadAvatarRequest\x1a\x1c.avatar.UploadAvatarResponse\x12\xb7\x01\n" +
+	"\fDeleteAvatar\x12\x1b.avatar.DeleteAvatarRequest\x1a\x16.google.protobuf.Empty\"r\x92AU\x12\rDelete avatar\x1a6Deletes the avatar of a user along with its thumbnailsb\f\n" +
+	"\n" +
+	"\n" +
+	"\x06Bearer\x12\x00\x82\xd3\xe4\x93\x02\x14*\x12/users/{id}/avatarB\x8d\x01\n" +
+	"\n" +
+	"com.avatarB\vAvatarProtoP\x01Z:github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb\xa2\x02\x03AXX\xaa\x02\x06Avatar\xca\x02\x06Avatar\xe2\x02\x12Avatar\\GPBMetadata\xea\x02\x06Avatarb\x06proto3"
+
+var (
+	file_avatar_proto_rawDescOnce sync.Once
+	file_avatar_proto_rawDescData []byte
+)
+
+func file_avatar_proto_rawDescGZIP() []byte {
+	file_avatar_proto_rawDescOnce.Do(func() {
+		file_avatar_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_avatar_proto_rawDesc), len(file_avatar_proto_rawDesc)))
+	})
+	return file_avatar_proto_rawDescData
+}
+
+var file_avatar_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_avatar_proto_goTypes = []any{
+	(*UploadAvatarRequest)(nil),  // 0: avatar.UploadAvatarRequest
+	(*UploadAvatarResponse)(nil), // 1: avatar.UploadAvatarResponse
+	(*AvatarThumbnail)(nil),      // 2: avatar.AvatarThumbnail
+	(*DeleteAvatarRequest)(nil),  // 3: avatar.DeleteAvatarRequest
+	(*emptypb.Empty)(nil),        // 4: google.protobuf.Empty
+}
+var file_avatar_proto_depIdxs = []int32{
+	2, // 0: avatar.UploadAvatarResponse.thumbnails:type_name -> avatar.AvatarThumbnail
+	0, // 1: avatar.AvatarService.UploadAvatar:input_type -> avatar.UploadAvatarRequest
+	3, // 2: avatar.AvatarService.DeleteAvatar:input_type -> avatar.DeleteAvatarRequest
+	1, // 3: avatar.AvatarService.UploadAvatar:output_type -> avatar.UploadAvatarResponse
+	4, // 4: avatar.AvatarService.DeleteAvatar:output_type -> google.protobuf.Empty
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_avatar_proto_init() }
+func file_avatar_proto_init() {
+	if File_avatar_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_avatar_proto_rawDesc), len(file_avatar_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_avatar_proto_goTypes,
+		DependencyIndexes: file_avatar_proto_depIdxs,
+		MessageInfos:      file_avatar_proto_msgTypes,
+	}.Build()
+	File_avatar_proto = out.File
+	file_avatar_proto_goTypes = nil
+	file_avatar_proto_depIdxs = nil
+}
@@ -61,13 +61,17 @@ func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
 }
 
 type HealthCheckResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
-	Environment   string                 `protobuf:"bytes,2,opt,name=environment,proto3" json:"environment,omitempty"`
-	Database      string                 `protobuf:"bytes,3,opt,name=database,proto3" json:"database,omitempty"`
-	HttpPort      int32                  `protobuf:"varint,4,opt,name=httpPort,proto3" json:"httpPort,omitempty"`
-	GrpcPort      int32                  `protobuf:"varint,5,opt,name=grpcPort,proto3" json:"grpcPort,omitempty"`
-	Dsn           string                 `protobuf:"bytes,6,opt,name=dsn,proto3" json:"dsn,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Version     string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Environment string                 `protobuf:"bytes,2,opt,name=environment,proto3" json:"environment,omitempty"`
+	Database    string                 `protobuf:"bytes,3,opt,name=database,proto3" json:"database,omitempty"`
+	HttpPort    int32                  `protobuf:"varint,4,opt,name=httpPort,proto3" json:"httpPort,omitempty"`
+	GrpcPort    int32                  `protobuf:"varint,5,opt,name=grpcPort,proto3" json:"grpcPort,omitempty"`
+	Dsn         string                 `protobuf:"bytes,6,opt,name=dsn,proto3" json:"dsn,omitempty"`
+	// lookups of the user cache, only set when it is enabled
+	UserCache *UserCacheStats `protobuf:"bytes,7,opt,name=userCache,proto3" json:"userCache,omitempty"`
+	// read replicas of the postgres database, only set when they are configured
+	Replicas      []*ReplicaStatus `protobuf:"bytes,8,rep,name=replicas,proto3" json:"replicas,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -144,19 +148,174 @@ func (x *HealthCheckResponse) GetDsn() string {
 	return ""
 }
 
+func (x *HealthCheckResponse) GetUserCache() *UserCacheStats {
+	if x != nil {
+		return x.UserCache
+	}
+	return nil
+}
+
+func (x *HealthCheckResponse) GetReplicas() []*ReplicaStatus {
+	if x != nil {
+		return x.Replicas
+	}
+	return nil
+}
+
+type UserCacheStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Store         string                 `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	Hits          int64                  `protobuf:"varint,2,opt,name=hits,proto3" json:"hits,omitempty"`
+	Misses        int64                  `protobuf:"varint,3,opt,name=misses,proto3" json:"misses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserCacheStats) Reset() {
+	*x = UserCacheStats{}
+	mi := &file_health_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserCacheStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserCacheStats) ProtoMessage() {}
+
+func (x *UserCacheStats) ProtoReflect() protoreflect.Message {
+	mi := &file_health_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserCacheStats.ProtoReflect.Descriptor instead.
+func (*UserCacheStats) Descriptor() ([]byte, []int) {
+	return file_health_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UserCacheStats) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+func (x *UserCacheStats) GetHits() int64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *UserCacheStats) GetMisses() int64 {
+	if x != nil {
+		return x.Misses
+	}
+	return 0
+}
+
+type ReplicaStatus struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Name    string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Healthy bool                   `protobuf:"varint,2,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	// replication lag in milliseconds as of the last health check
+	LagMillis int64 `protobuf:"varint,3,opt,name=lagMillis,proto3" json:"lagMillis,omitempty"`
+	// reason of the ejection of an unhealthy replica
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplicaStatus) Reset() {
+	*x = ReplicaStatus{}
+	mi := &file_health_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplicaStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicaStatus) ProtoMessage() {}
+
+func (x *ReplicaStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_health_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicaStatus.ProtoReflect.Descriptor instead.
+func (*ReplicaStatus) Descriptor() ([]byte, []int) {
+	return file_health_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ReplicaStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ReplicaStatus) GetHealthy() bool {
+	if x != nil {
+		return x.Healthy
+	}
+	return false
+}
+
+func (x *ReplicaStatus) GetLagMillis() int64 {
+	if x != nil {
+		return x.LagMillis
+	}
+	return 0
+}
+
+func (x *ReplicaStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 var File_health_proto protoreflect.FileDescriptor
 
 const file_health_proto_rawDesc = "" +
 	"\n" +
 	"\fhealth.proto\x12\x06health\x1a\x1cgoogle/api/annotations.proto\x1a\x15google/api/http.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"\x14\n" +
-	"\x12HealthCheckRequest\"\xb7\x01\n" +
+	"\x12HealthCheckRequest\"\xa0\x02\n" +
 	"\x13HealthCheckResponse\x12\x18\n" +
 	"\aversion\x18\x01 \x01(\tR\aversion\x12 \n" +
 	"\venvironment\x18\x02 \x01(\tR\venvironment\x12\x1a\n" +
 	"\bdatabase\x18\x03 \x01(\tR\bdatabase\x12\x1a\n" +
 	"\bhttpPort\x18\x04 \x01(\x05R\bhttpPort\x12\x1a\n" +
 	"\bgrpcPort\x18\x05 \x01(\x05R\bgrpcPort\x12\x10\n" +
-	"\x03dsn\x18\x06 \x01(\tR\x03dsn2\xbb\x01\n" +
+	"\x03dsn\x18\x06 \x01(\tR\x03dsn\x124\n" +
+	"\tuserCache\x18\a \x01(\v2\x16.health.UserCacheStatsR\tuserCache\x121\n" +
+	"\breplicas\x18\b \x03(\v2\x15.health.ReplicaStatusR\breplicas\"R\n" +
+	"\x0eUserCacheStats\x12\x14\n" +
+	"\x05store\x18\x01 \x01(\tR\x05store\x12\x12\n" +
+	"\x04hits\x18\x02 \x01(\x03R\x04hits\x12\x16\n" +
+	"\x06misses\x18\x03 \x01(\x03R\x06misses\"q\n" +
+	"\rReplicaStatus\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\ahealthy\x18\x02 \x01(\bR\ahealthy\x12\x1c\n" +
+	"\tlagMillis\x18\x03 \x01(\x03R\tlagMillis\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error2\xbb\x01\n" +
 	"\rHealthService\x12\xa9\x01\n" +
 	"\vHealthCheck\x12\x16.google.protobuf.Empty\x1a\x1b.health.HealthCheckResponse\"e\x92AS\x12\fHealth check\x1aCReturns basic runtime information of the API when the service is up\x82\xd3\xe4\x93\x02\t\x12\a/healthB\x8d\x01\n" +
 	"\n" +
@@ -174,20 +333,24 @@ func file_health_proto_rawDescGZIP() []byte {
 	return file_health_proto_rawDescData
 }
 
-var file_health_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_health_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_health_proto_goTypes = []any{
 	(*HealthCheckRequest)(nil),  // 0: health.HealthCheckRequest
 	(*HealthCheckResponse)(nil), // 1: health.HealthCheckResponse
-	(*emptypb.Empty)(nil),       // 2: google.protobuf.Empty
+	(*UserCacheStats)(nil),      // 2: health.UserCacheStats
+	(*ReplicaStatus)(nil),       // 3: health.ReplicaStatus
+	(*emptypb.Empty)(nil),       // 4: google.protobuf.Empty
 }
 var file_health_proto_depIdxs = []int32{
-	2, // 0: health.HealthService.HealthCheck:input_type -> google.protobuf.Empty
-	1, // 1: health.HealthService.HealthCheck:output_type -> health.HealthCheckResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: health.HealthCheckResponse.userCache:type_name -> health.UserCacheStats
+	3, // 1: health.HealthCheckResponse.replicas:type_name -> health.ReplicaStatus
+	4, // 2: health.HealthService.HealthCheck:input_type -> google.protobuf.Empty
+	1, // 3: health.HealthService.HealthCheck:output_type -> health.HealthCheckResponse
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_health_proto_init() }
@@ -201,7 +364,7 @@ func file_health_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_health_proto_rawDesc), len(file_health_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
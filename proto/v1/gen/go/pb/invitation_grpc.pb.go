@@ -0,0 +1,274 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: invitation.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	InvitationService_InviteUser_FullMethodName     = "/invitation.InvitationService/InviteUser"
+	InvitationService_AcceptInvite_FullMethodName   = "/invitation.InvitationService/AcceptInvite"
+	InvitationService_ResendInvite_FullMethodName   = "/invitation.InvitationService/ResendInvite"
+	InvitationService_RevokeInvite_FullMethodName   = "/invitation.InvitationService/RevokeInvite"
+	InvitationService_GetInvitations_FullMethodName = "/invitation.InvitationService/GetInvitations"
+)
+
+// InvitationServiceClient is the client API for InvitationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type InvitationServiceClient interface {
+	InviteUser(ctx context.Context, in *InviteUserRequest, opts ...grpc.CallOption) (*InviteUserResponse, error)
+	AcceptInvite(ctx context.Context, in *AcceptInviteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ResendInvite(ctx context.Context, in *ResendInviteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RevokeInvite(ctx context.Context, in *RevokeInviteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetInvitations(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetInvitationsResponse, error)
+}
+
+type invitationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInvitationServiceClient(cc grpc.ClientConnInterface) InvitationServiceClient {
+	return &invitationServiceClient{cc}
+}
+
+func (c *invitationServiceClient) InviteUser(ctx context.Context, in *InviteUserRequest, opts ...grpc.CallOption) (*InviteUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InviteUserResponse)
+	err := c.cc.Invoke(ctx, InvitationService_InviteUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invitationServiceClient) AcceptInvite(ctx context.Context, in *AcceptInviteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, InvitationService_AcceptInvite_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invitationServiceClient) ResendInvite(ctx context.Context, in *ResendInviteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, InvitationService_ResendInvite_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invitationServiceClient) RevokeInvite(ctx context.Context, in *RevokeInviteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, InvitationService_RevokeInvite_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invitationServiceClient) GetInvitations(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetInvitationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetInvitationsResponse)
+	err := c.cc.Invoke(ctx, InvitationService_GetInvitations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InvitationServiceServer is the server API for InvitationService service.
+// All implementations must embed UnimplementedInvitationServiceServer
+// for forward compatibility.
+type InvitationServiceServer interface {
+	InviteUser(context.Context, *InviteUserRequest) (*InviteUserResponse, error)
+	AcceptInvite(context.Context, *AcceptInviteRequest) (*emptypb.Empty, error)
+	ResendInvite(context.Context, *ResendInviteRequest) (*emptypb.Empty, error)
+	RevokeInvite(context.Context, *RevokeInviteRequest) (*emptypb.Empty, error)
+	GetInvitations(context.Context, *emptypb.Empty) (*GetInvitationsResponse, error)
+	mustEmbedUnimplementedInvitationServiceServer()
+}
+
+// UnimplementedInvitationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedInvitationServiceServer struct{}
+
+func (UnimplementedInvitationServiceServer) InviteUser(context.Context, *InviteUserRequest) (*InviteUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteUser not implemented")
+}
+func (UnimplementedInvitationServiceServer) AcceptInvite(context.Context, *AcceptInviteRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptInvite not implemented")
+}
+func (UnimplementedInvitationServiceServer) ResendInvite(context.Context, *ResendInviteRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResendInvite not implemented")
+}
+func (UnimplementedInvitationServiceServer) RevokeInvite(context.Context, *RevokeInviteRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeInvite not implemented")
+}
+func (UnimplementedInvitationServiceServer) GetInvitations(context.Context, *emptypb.Empty) (*GetInvitationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInvitations not implemented")
+}
+func (UnimplementedInvitationServiceServer) mustEmbedUnimplementedInvitationServiceServer() {}
+func (UnimplementedInvitationServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeInvitationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InvitationServiceServer will
+// result in compilation errors.
+type UnsafeInvitationServiceServer interface {
+	mustEmbedUnimplementedInvitationServiceServer()
+}
+
+func RegisterInvitationServiceServer(s grpc.ServiceRegistrar, srv InvitationServiceServer) {
+	// If the following call pancis, it indicates UnimplementedInvitationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&InvitationService_ServiceDesc, srv)
+}
+
+func _InvitationService_InviteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvitationServiceServer).InviteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvitationService_InviteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvitationServiceServer).InviteUser(ctx, req.(*InviteUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvitationService_AcceptInvite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptInviteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvitationServiceServer).AcceptInvite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvitationService_AcceptInvite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvitationServiceServer).AcceptInvite(ctx, req.(*AcceptInviteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvitationService_ResendInvite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResendInviteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvitationServiceServer).ResendInvite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvitationService_ResendInvite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvitationServiceServer).ResendInvite(ctx, req.(*ResendInviteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvitationService_RevokeInvite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeInviteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvitationServiceServer).RevokeInvite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvitationService_RevokeInvite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvitationServiceServer).RevokeInvite(ctx, req.(*RevokeInviteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvitationService_GetInvitations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvitationServiceServer).GetInvitations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvitationService_GetInvitations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvitationServiceServer).GetInvitations(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InvitationService_ServiceDesc is the grpc.ServiceDesc for InvitationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InvitationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "invitation.InvitationService",
+	HandlerType: (*InvitationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "InviteUser",
+			Handler:    _InvitationService_InviteUser_Handler,
+		},
+		{
+			MethodName: "AcceptInvite",
+			Handler:    _InvitationService_AcceptInvite_Handler,
+		},
+		{
+			MethodName: "ResendInvite",
+			Handler:    _InvitationService_ResendInvite_Handler,
+		},
+		{
+			MethodName: "RevokeInvite",
+			Handler:    _InvitationService_RevokeInvite_Handler,
+		},
+		{
+			MethodName: "GetInvitations",
+			Handler:    _InvitationService_GetInvitations_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "invitation.proto",
+}
@@ -0,0 +1,459 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: preferences.proto
+
+package pb
+
+import (
+	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetPreferencesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPreferencesRequest) Reset() {
+	*x = GetPreferencesRequest{}
+	mi := &file_preferences_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPreferencesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPreferencesRequest) ProtoMessage() {}
+
+func (x *GetPreferencesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_preferences_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPreferencesRequest.ProtoReflect.Descriptor instead.
+func (*GetPreferencesRequest) Descriptor() ([]byte, []int) {
+	return file_preferences_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetPreferencesRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UpdatePreferencesRequest struct {
+	state          protoimpl.MessageState         `protogen:"open.v1"`
+	Id             string                         `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version        *int32                         `protobuf:"varint,2,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	Language       *string                        `protobuf:"bytes,3,opt,name=language,proto3,oneof" json:"language,omitempty"`
+	Timezone       *string                        `protobuf:"bytes,4,opt,name=timezone,proto3,oneof" json:"timezone,omitempty"`
+	Notifications  *UpdateNotificationPreferences `protobuf:"bytes,5,opt,name=notifications,proto3" json:"notifications,omitempty"`
+	ResetToDefault []string                       `protobuf:"bytes,6,rep,name=reset_to_default,json=resetToDefault,proto3" json:"reset_to_default,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdatePreferencesRequest) Reset() {
+	*x = UpdatePreferencesRequest{}
+	mi := &file_preferences_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePreferencesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePreferencesRequest) ProtoMessage() {}
+
+func (x *UpdatePreferencesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_preferences_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePreferencesRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePreferencesRequest) Descriptor() ([]byte, []int) {
+	return file_preferences_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UpdatePreferencesRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdatePreferencesRequest) GetVersion() int32 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+func (x *UpdatePreferencesRequest) GetLanguage() string {
+	if x != nil && x.Language != nil {
+		return *x.Language
+	}
+	return ""
+}
+
+func (x *UpdatePreferencesRequest) GetTimezone() string {
+	if x != nil && x.Timezone != nil {
+		return *x.Timezone
+	}
+	return ""
+}
+
+func (x *UpdatePreferencesRequest) GetNotifications() *UpdateNotificationPreferences {
+	if x != nil {
+		return x.Notifications
+	}
+	return nil
+}
+
+func (x *UpdatePreferencesRequest) GetResetToDefault() []string {
+	if x != nil {
+		return x.ResetToDefault
+	}
+	return nil
+}
+
+type UpdateNotificationPreferences struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         *bool                  `protobuf:"varint,1,opt,name=email,proto3,oneof" json:"email,omitempty"`
+	Push          *bool                  `protobuf:"varint,2,opt,name=push,proto3,oneof" json:"push,omitempty"`
+	Marketing     *bool                  `protobuf:"varint,3,opt,name=marketing,proto3,oneof" json:"marketing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateNotificationPreferences) Reset() {
+	*x = UpdateNotificationPreferences{}
+	mi := &file_preferences_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateNotificationPreferences) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateNotificationPreferences) ProtoMessage() {}
+
+func (x *UpdateNotificationPreferences) ProtoReflect() protoreflect.Message {
+	mi := &file_preferences_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateNotificationPreferences.ProtoReflect.Descriptor instead.
+func (*UpdateNotificationPreferences) Descriptor() ([]byte, []int) {
+	return file_preferences_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UpdateNotificationPreferences) GetEmail() bool {
+	if x != nil && x.Email != nil {
+		return *x.Email
+	}
+	return false
+}
+
+func (x *UpdateNotificationPreferences) GetPush() bool {
+	if x != nil && x.Push != nil {
+		return *x.Push
+	}
+	return false
+}
+
+func (x *UpdateNotificationPreferences) GetMarketing() bool {
+	if x != nil && x.Marketing != nil {
+		return *x.Marketing
+	}
+	return false
+}
+
+type PreferencesResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Version       int32                    `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Language      string                   `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	Timezone      string                   `protobuf:"bytes,3,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Notifications *NotificationPreferences `protobuf:"bytes,4,opt,name=notifications,proto3" json:"notifications,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp   `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreferencesResponse) Reset() {
+	*x = PreferencesResponse{}
+	mi := &file_preferences_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreferencesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreferencesResponse) ProtoMessage() {}
+
+func (x *PreferencesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_preferences_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreferencesResponse.ProtoReflect.Descriptor instead.
+func (*PreferencesResponse) Descriptor() ([]byte, []int) {
+	return file_preferences_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PreferencesResponse) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *PreferencesResponse) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *PreferencesResponse) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *PreferencesResponse) GetNotifications() *NotificationPreferences {
+	if x != nil {
+		return x.Notifications
+	}
+	return nil
+}
+
+func (x *PreferencesResponse) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type NotificationPreferences struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         bool                   `protobuf:"varint,1,opt,name=email,proto3" json:"email,omitempty"`
+	Push          bool                   `protobuf:"varint,2,opt,name=push,proto3" json:"push,omitempty"`
+	Marketing     bool                   `protobuf:"varint,3,opt,name=marketing,proto3" json:"marketing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationPreferences) Reset() {
+	*x = NotificationPreferences{}
+	mi := &file_preferences_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationPreferences) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationPreferences) ProtoMessage() {}
+
+func (x *NotificationPreferences) ProtoReflect() protoreflect.Message {
+	mi := &file_preferences_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationPreferences.ProtoReflect.Descriptor instead.
+func (*NotificationPreferences) Descriptor() ([]byte, []int) {
+	return file_preferences_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *NotificationPreferences) GetEmail() bool {
+	if x != nil {
+		return x.Email
+	}
+	return false
+}
+
+func (x *NotificationPreferences) GetPush() bool {
+	if x != nil {
+		return x.Push
+	}
+	return false
+}
+
+func (x *NotificationPreferences) GetMarketing() bool {
+	if x != nil {
+		return x.Marketing
+	}
+	return false
+}
+
+var File_preferences_proto protoreflect.FileDescriptor
+
+const file_preferences_proto_rawDesc = "" +
+	"\n" +
+	"\x11preferences.proto\x12\vpreferences\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"'\n" +
+	"\x15GetPreferencesRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xad\x02\n" +
+	"\x18UpdatePreferencesRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\aversion\x18\x02 \x01(\x05H\x00R\aversion\x88\x01\x01\x12\x1f\n" +
+	"\blanguage\x18\x03 \x01(\tH\x01R\blanguage\x88\x01\x01\x12\x1f\n" +
+	"\btimezone\x18\x04 \x01(\tH\x02R\btimezone\x88\x01\x01\x12P\n" +
+	"\rnotifications\x18\x05 \x01(\v2*.preferences.UpdateNotificationPreferencesR\rnotifications\x12(\n" +
+	"\x10reset_to_default\x18\x06 \x03(\tR\x0eresetToDefaultB\n" +
+	"\n" +
+	"\b_versionB\v\n" +
+	"\t_languageB\v\n" +
+	"\t_timezone\"\x97\x01\n" +
+	"\x1dUpdateNotificationPreferences\x12\x19\n" +
+	"\x05email\x18\x01 \x01(\bH\x00R\x05email\x88\x01\x01\x12\x17\n" +
+	"\x04push\x18\x02 \x01(\bH\x01R\x04push\x88\x01\x01\x12!\n" +
+	"\tmarketing\x18\x03 \x01(\bH\x02R\tmarketing\x88\x01\x01B\b\n" +
+	"\x06_emailB\a\n" +
+	"\x05_pushB\f\n" +
+	"\n" +
+	"_marketing\"\xee\x01\n" +
+	"\x13PreferencesResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x1a\n" +
+	"\btimezone\x18\x03 \x01(\tR\btimezone\x12J\n" +
+	"\rnotifications\x18\x04 \x01(\v2$.preferences.NotificationPreferencesR\rnotifications\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"a\n" +
+	"\x17NotificationPreferences\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\bR\x05email\x12\x12\n" +
+	"\x04push\x18\x02 \x01(\bR\x04push\x12\x1c\n" +
+	"\tmarketing\x18\x03 \x01(\bR\tmarketing2\xd5\x04\n" +
+	"\x12PreferencesService\x12\xf5\x01\n" +
+	"\x0eGetPreferences\x12\".preferences.GetPreferencesRequest\x1a .preferences.PreferencesResponse\"\x9c\x01\x92Az\x12\x0fGet preferences\x1aYGets the preferences of a user, merging the defaults with the ones overridden by the userb\f\n" +
+	"\n" +
+	"\n" +
+	"\x06Bearer\x12\x00\x82\xd3\xe4\x93\x02\x19\x12\x17/users/{id}/preferences\x12\xc6\x02\n" +
+	"\x11UpdatePreferences\x12%.preferences.UpdatePreferencesRequest\x1a .preferences.PreferencesResponse\"\xe7\x01\x92A\xc1\x01\x12\x12Update preferences\x1a\x9c\x01Overrides the given preferences of a user and resets the ones in reset_to_default to their defaults, rejecting the update when the given version is outdatedb\f\n" +
+	"\n" +
+	"\n" +
+	"\x06Bearer\x12\x00\x82\xd3\xe4\x93\x02\x1c:\x01*2\x17/users/{id}/preferencesB\xab\x01\n" +
+	"\x0fcom.preferencesB\x10PreferencesProtoP\x01Z:github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb\xa2\x02\x03PXX\xaa\x02\vPreferences\xca\x02\vPreferences\xe2\x02\x17Preferences\\GPBMetadata\xea\x02\vPreferencesb\x06proto3"
+
+var (
+	file_preferences_proto_rawDescOnce sync.Once
+	file_preferences_proto_rawDescData []byte
+)
+
+func file_preferences_proto_rawDescGZIP() []byte {
+	file_preferences_proto_rawDescOnce.Do(func() {
+		file_preferences_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_preferences_proto_rawDesc), len(file_preferences_proto_rawDesc)))
+	})
+	return file_preferences_proto_rawDescData
+}
+
+var file_preferences_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_preferences_proto_goTypes = []any{
+	(*GetPreferencesRequest)(nil),         // 0: preferences.GetPreferencesRequest
+	(*UpdatePreferencesRequest)(nil),      // 1: preferences.UpdatePreferencesRequest
+	(*UpdateNotificationPreferences)(nil), // 2: preferences.UpdateNotificationPreferences
+	(*PreferencesResponse)(nil),           // 3: preferences.PreferencesResponse
+	(*NotificationPreferences)(nil),       // 4: preferences.NotificationPreferences
+	(*timestamppb.Timestamp)(nil),         // 5: google.protobuf.Timestamp
+}
+var file_preferences_proto_depIdxs = []int32{
+	2, // 0: preferences.UpdatePreferencesRequest.notifications:type_name -> preferences.UpdateNotificationPreferences
+	4, // 1: preferences.PreferencesResponse.notifications:type_name -> preferences.NotificationPreferences
+	5, // 2: preferences.PreferencesResponse.updated_at:type_name -> google.protobuf.Timestamp
+	0, // 3: preferences.PreferencesService.GetPreferences:input_type -> preferences.GetPreferencesRequest
+	1, // 4: preferences.PreferencesService.UpdatePreferences:input_type -> preferences.UpdatePreferencesRequest
+	3, // 5: preferences.PreferencesService.GetPreferences:output_type -> preferences.PreferencesResponse
+	3, // 6: preferences.PreferencesService.UpdatePreferences:output_type -> preferences.PreferencesResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_preferences_proto_init() }
+func file_preferences_proto_init() {
+	if File_preferences_proto != nil {
+		return
+	}
+	file_preferences_proto_msgTypes[1].OneofWrappers = []any{}
+	file_preferences_proto_msgTypes[2].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_preferences_proto_rawDesc), len(file_preferences_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_preferences_proto_goTypes,
+		DependencyIndexes: file_preferences_proto_depIdxs,
+		MessageInfos:      file_preferences_proto_msgTypes,
+	}.Build()
+	File_preferences_proto = out.File
+	file_preferences_proto_goTypes = nil
+	file_preferences_proto_depIdxs = nil
+}
@@ -0,0 +1,256 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: privacy.proto
+
+package pb
+
+import (
+	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExportUserDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUserDataRequest) Reset() {
+	*x = ExportUserDataRequest{}
+	mi := &file_privacy_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUserDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserDataRequest) ProtoMessage() {}
+
+func (x *ExportUserDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_privacy_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserDataRequest.ProtoReflect.Descriptor instead.
+func (*ExportUserDataRequest) Descriptor() ([]byte, []int) {
+	return file_privacy_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExportUserDataRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type EraseUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseUserRequest) Reset() {
+	*x = EraseUserRequest{}
+	mi := &file_privacy_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseUserRequest) ProtoMessage() {}
+
+func (x *EraseUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_privacy_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseUserRequest.ProtoReflect.Descriptor instead.
+func (*EraseUserRequest) Descriptor() ([]byte, []int) {
+	return file_privacy_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EraseUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type EraseUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TombstoneId   string                 `protobuf:"bytes,1,opt,name=tombstone_id,json=tombstoneId,proto3" json:"tombstone_id,omitempty"`
+	Stores        []string               `protobuf:"bytes,2,rep,name=stores,proto3" json:"stores,omitempty"`
+	ErasedAt      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=erased_at,json=erasedAt,proto3" json:"erased_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseUserResponse) Reset() {
+	*x = EraseUserResponse{}
+	mi := &file_privacy_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseUserResponse) ProtoMessage() {}
+
+func (x *EraseUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_privacy_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseUserResponse.ProtoReflect.Descriptor instead.
+func (*EraseUserResponse) Descriptor() ([]byte, []int) {
+	return file_privacy_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *EraseUserResponse) GetTombstoneId() string {
+	if x != nil {
+		return x.TombstoneId
+	}
+	return ""
+}
+
+func (x *EraseUserResponse) GetStores() []string {
+	if x != nil {
+		return x.Stores
+	}
+	return nil
+}
+
+func (x *EraseUserResponse) GetErasedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ErasedAt
+	}
+	return nil
+}
+
+var File_privacy_proto protoreflect.FileDescriptor
+
+const file_privacy_proto_rawDesc = "" +
+	"\n" +
+	"\rprivacy.proto\x12\aprivacy\x1a\x1cgoogle/api/annotations.proto\x1a\x19google/api/httpbody.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"'\n" +
+	"\x15ExportUserDataRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\"\n" +
+	"\x10EraseUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x87\x01\n" +
+	"\x11EraseUserResponse\x12!\n" +
+	"\ftombstone_id\x18\x01 \x01(\tR\vtombstoneId\x12\x16\n" +
+	"\x06stores\x18\x02 \x03(\tR\x06stores\x127\n" +
+	"\terased_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\berasedAt2\xdf\x03\n" +
+	"\x0ePrivacyService\x12\xdf\x01\n" +
+	"\x0eExportUserData\x12\x1e.privacy.ExportUserDataRequest\x1a\x14.google.api.HttpBody\"\x96\x01\x92A{\x12\x10Export user data\x1aYReturns a zip archive with everything stored for a user, as a data subject access requestb\f\n" +
+	"\n" +
+	"\n" +
+	"\x06Bearer\x12\x00\x82\xd3\xe4\x93\x02\x12\x12\x10/users/{id}/data\x12\xea\x01\n" +
+	"\tEraseUser\x12\x19.privacy.EraseUserRequest\x1a\x1a.privacy.EraseUserResponse\"\xa5\x01\x92A\x88\x01\x12\n" +
+	"Erase user\x1alAnonymizes the personal data of a user in every store and records a tombstone, as a right to erasure requestb\f\n" +
+	"\n" +
+	"\n" +
+	"\x06Bearer\x12\x00\x82\xd3\xe4\x93\x02\x13\"\x11/users/{id}/eraseB\x93\x01\n" +
+	"\vcom.privacyB\fPrivacyProtoP\x01Z:github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb\xa2\x02\x03PXX\xaa\x02\aPrivacy\xca\x02\aPrivacy\xe2\x02\x13Privacy\\GPBMetadata\xea\x02\aPrivacyb\x06proto3"
+
+var (
+	file_privacy_proto_rawDescOnce sync.Once
+	file_privacy_proto_rawDescData []byte
+)
+
+func file_privacy_proto_rawDescGZIP() []byte {
+	file_privacy_proto_rawDescOnce.Do(func() {
+		file_privacy_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_privacy_proto_rawDesc), len(file_privacy_proto_rawDesc)))
+	})
+	return file_privacy_proto_rawDescData
+}
+
+var file_privacy_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_privacy_proto_goTypes = []any{
+	(*ExportUserDataRequest)(nil), // 0: privacy.ExportUserDataRequest
+	(*EraseUserRequest)(nil),      // 1: privacy.EraseUserRequest
+	(*EraseUserResponse)(nil),     // 2: privacy.EraseUserResponse
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+	(*httpbody.HttpBody)(nil),     // 4: google.api.HttpBody
+}
+var file_privacy_proto_depIdxs = []int32{
+	3, // 0: privacy.EraseUserResponse.erased_at:type_name -> google.protobuf.Timestamp
+	0, // 1: privacy.PrivacyService.ExportUserData:input_type -> privacy.ExportUserDataRequest
+	1, // 2: privacy.PrivacyService.EraseUser:input_type -> privacy.EraseUserRequest
+	4, // 3: privacy.PrivacyService.ExportUserData:output_type -> google.api.HttpBody
+	2, // 4: privacy.PrivacyService.EraseUser:output_type -> privacy.EraseUserResponse
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_privacy_proto_init() }
+func file_privacy_proto_init() {
+	if File_privacy_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_privacy_proto_rawDesc), len(file_privacy_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_privacy_proto_goTypes,
+		DependencyIndexes: file_privacy_proto_depIdxs,
+		MessageInfos:      file_privacy_proto_msgTypes,
+	}.Build()
+	File_privacy_proto = out.File
+	file_privacy_proto_goTypes = nil
+	file_privacy_proto_depIdxs = nil
+}
@@ -25,6 +25,159 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ImportFormat int32
+
+const (
+	ImportFormat_IMPORT_FORMAT_UNSPECIFIED ImportFormat = 0
+	ImportFormat_IMPORT_FORMAT_CSV         ImportFormat = 1
+	ImportFormat_IMPORT_FORMAT_NDJSON      ImportFormat = 2
+)
+
+// Enum value maps for ImportFormat.
+var (
+	ImportFormat_name = map[int32]string{
+		0: "IMPORT_FORMAT_UNSPECIFIED",
+		1: "IMPORT_FORMAT_CSV",
+		2: "IMPORT_FORMAT_NDJSON",
+	}
+	ImportFormat_value = map[string]int32{
+		"IMPORT_FORMAT_UNSPECIFIED": 0,
+		"IMPORT_FORMAT_CSV":         1,
+		"IMPORT_FORMAT_NDJSON":      2,
+	}
+)
+
+func (x ImportFormat) Enum() *ImportFormat {
+	p := new(ImportFormat)
+	*p = x
+	return p
+}
+
+func (x ImportFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ImportFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_enumTypes[0].Descriptor()
+}
+
+func (ImportFormat) Type() protoreflect.EnumType {
+	return &file_user_proto_enumTypes[0]
+}
+
+func (x ImportFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ImportFormat.Descriptor instead.
+func (ImportFormat) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{0}
+}
+
+type ExportFormat int32
+
+const (
+	ExportFormat_EXPORT_FORMAT_UNSPECIFIED ExportFormat = 0
+	ExportFormat_EXPORT_FORMAT_CSV         ExportFormat = 1
+	ExportFormat_EXPORT_FORMAT_NDJSON      ExportFormat = 2
+	ExportFormat_EXPORT_FORMAT_PARQUET     ExportFormat = 3
+)
+
+// Enum value maps for ExportFormat.
+var (
+	ExportFormat_name = map[int32]string{
+		0: "EXPORT_FORMAT_UNSPECIFIED",
+		1: "EXPORT_FORMAT_CSV",
+		2: "EXPORT_FORMAT_NDJSON",
+		3: "EXPORT_FORMAT_PARQUET",
+	}
+	ExportFormat_value = map[string]int32{
+		"EXPORT_FORMAT_UNSPECIFIED": 0,
+		"EXPORT_FORMAT_CSV":         1,
+		"EXPORT_FORMAT_NDJSON":      2,
+		"EXPORT_FORMAT_PARQUET":     3,
+	}
+)
+
+func (x ExportFormat) Enum() *ExportFormat {
+	p := new(ExportFormat)
+	*p = x
+	return p
+}
+
+func (x ExportFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExportFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_enumTypes[1].Descriptor()
+}
+
+func (ExportFormat) Type() protoreflect.EnumType {
+	return &file_user_proto_enumTypes[1]
+}
+
+func (x ExportFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExportFormat.Descriptor instead.
+func (ExportFormat) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{1}
+}
+
+type UserChangeType int32
+
+const (
+	UserChangeType_USER_CHANGE_TYPE_UNSPECIFIED UserChangeType = 0
+	UserChangeType_USER_CHANGE_TYPE_CREATED     UserChangeType = 1
+	UserChangeType_USER_CHANGE_TYPE_UPDATED     UserChangeType = 2
+	UserChangeType_USER_CHANGE_TYPE_DELETED     UserChangeType = 3
+)
+
+// Enum value maps for UserChangeType.
+var (
+	UserChangeType_name = map[int32]string{
+		0: "USER_CHANGE_TYPE_UNSPECIFIED",
+		1: "USER_CHANGE_TYPE_CREATED",
+		2: "USER_CHANGE_TYPE_UPDATED",
+		3: "USER_CHANGE_TYPE_DELETED",
+	}
+	UserChangeType_value = map[string]int32{
+		"USER_CHANGE_TYPE_UNSPECIFIED": 0,
+		"USER_CHANGE_TYPE_CREATED":     1,
+		"USER_CHANGE_TYPE_UPDATED":     2,
+		"USER_CHANGE_TYPE_DELETED":     3,
+	}
+)
+
+func (x UserChangeType) Enum() *UserChangeType {
+	p := new(UserChangeType)
+	*p = x
+	return p
+}
+
+func (x UserChangeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UserChangeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_enumTypes[2].Descriptor()
+}
+
+func (UserChangeType) Type() protoreflect.EnumType {
+	return &file_user_proto_enumTypes[2]
+}
+
+func (x UserChangeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UserChangeType.Descriptor instead.
+func (UserChangeType) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{2}
+}
+
 type LoginUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
@@ -165,68 +318,680 @@ func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
-func (*CreateUserRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{2}
-}
-
-func (x *CreateUserRequest) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *CreateUserRequest) GetSurnames() string {
-	if x != nil {
-		return x.Surnames
-	}
-	return ""
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateUserRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetSurnames() string {
+	if x != nil {
+		return x.Surnames
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetClaimIds() []int32 {
+	if x != nil {
+		return x.ClaimIds
+	}
+	return nil
+}
+
+type CreateUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserResponse) Reset() {
+	*x = CreateUserResponse{}
+	mi := &file_user_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserResponse) ProtoMessage() {}
+
+func (x *CreateUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserResponse.ProtoReflect.Descriptor instead.
+func (*CreateUserResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateUserResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CreateManyUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*CreateUserRequest   `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Partial       bool                   `protobuf:"varint,2,opt,name=partial,proto3" json:"partial,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateManyUsersRequest) Reset() {
+	*x = CreateManyUsersRequest{}
+	mi := &file_user_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateManyUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateManyUsersRequest) ProtoMessage() {}
+
+func (x *CreateManyUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateManyUsersRequest.ProtoReflect.Descriptor instead.
+func (*CreateManyUsersRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateManyUsersRequest) GetUsers() []*CreateUserRequest {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *CreateManyUsersRequest) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+type CreateManyUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	Results       []*BulkItemResult      `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateManyUsersResponse) Reset() {
+	*x = CreateManyUsersResponse{}
+	mi := &file_user_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateManyUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateManyUsersResponse) ProtoMessage() {}
+
+func (x *CreateManyUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateManyUsersResponse.ProtoReflect.Descriptor instead.
+func (*CreateManyUsersResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateManyUsersResponse) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *CreateManyUsersResponse) GetResults() []*BulkItemResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type UpdateManyUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*UpdateUserRequest   `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Partial       bool                   `protobuf:"varint,2,opt,name=partial,proto3" json:"partial,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateManyUsersRequest) Reset() {
+	*x = UpdateManyUsersRequest{}
+	mi := &file_user_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateManyUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateManyUsersRequest) ProtoMessage() {}
+
+func (x *UpdateManyUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateManyUsersRequest.ProtoReflect.Descriptor instead.
+func (*UpdateManyUsersRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateManyUsersRequest) GetUsers() []*UpdateUserRequest {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *UpdateManyUsersRequest) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+type DeleteManyUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	Partial       bool                   `protobuf:"varint,2,opt,name=partial,proto3" json:"partial,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteManyUsersRequest) Reset() {
+	*x = DeleteManyUsersRequest{}
+	mi := &file_user_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteManyUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteManyUsersRequest) ProtoMessage() {}
+
+func (x *DeleteManyUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteManyUsersRequest.ProtoReflect.Descriptor instead.
+func (*DeleteManyUsersRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteManyUsersRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *DeleteManyUsersRequest) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+type BulkUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BulkItemResult      `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkUsersResponse) Reset() {
+	*x = BulkUsersResponse{}
+	mi := &file_user_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkUsersResponse) ProtoMessage() {}
+
+func (x *BulkUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkUsersResponse.ProtoReflect.Descriptor instead.
+func (*BulkUsersResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BulkUsersResponse) GetResults() []*BulkItemResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type BulkItemResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Code          string                 `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkItemResult) Reset() {
+	*x = BulkItemResult{}
+	mi := &file_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkItemResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkItemResult) ProtoMessage() {}
+
+func (x *BulkItemResult) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkItemResult.ProtoReflect.Descriptor instead.
+func (*BulkItemResult) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BulkItemResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BulkItemResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BulkItemResult) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *BulkItemResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ImportUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        ImportFormat           `protobuf:"varint,1,opt,name=format,proto3,enum=user.ImportFormat" json:"format,omitempty"`
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	Chunk         []byte                 `protobuf:"bytes,3,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportUsersRequest) Reset() {
+	*x = ImportUsersRequest{}
+	mi := &file_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportUsersRequest) ProtoMessage() {}
+
+func (x *ImportUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportUsersRequest.ProtoReflect.Descriptor instead.
+func (*ImportUsersRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ImportUsersRequest) GetFormat() ImportFormat {
+	if x != nil {
+		return x.Format
+	}
+	return ImportFormat_IMPORT_FORMAT_UNSPECIFIED
+}
+
+func (x *ImportUsersRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ImportUsersRequest) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+type ImportUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         int32                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	Imported      int32                  `protobuf:"varint,2,opt,name=imported,proto3" json:"imported,omitempty"`
+	DryRun        bool                   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	Errors        []*BulkItemResult      `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportUsersResponse) Reset() {
+	*x = ImportUsersResponse{}
+	mi := &file_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportUsersResponse) ProtoMessage() {}
+
+func (x *ImportUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportUsersResponse.ProtoReflect.Descriptor instead.
+func (*ImportUsersResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ImportUsersResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ImportUsersResponse) GetImported() int32 {
+	if x != nil {
+		return x.Imported
+	}
+	return 0
+}
+
+func (x *ImportUsersResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ImportUsersResponse) GetErrors() []*BulkItemResult {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type ExportUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        ExportFormat           `protobuf:"varint,1,opt,name=format,proto3,enum=user.ExportFormat" json:"format,omitempty"`
+	Columns       []string               `protobuf:"bytes,2,rep,name=columns,proto3" json:"columns,omitempty"`
+	Filter        *UserFilter            `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUsersRequest) Reset() {
+	*x = ExportUsersRequest{}
+	mi := &file_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUsersRequest) ProtoMessage() {}
+
+func (x *ExportUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUsersRequest.ProtoReflect.Descriptor instead.
+func (*ExportUsersRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ExportUsersRequest) GetFormat() ExportFormat {
+	if x != nil {
+		return x.Format
+	}
+	return ExportFormat_EXPORT_FORMAT_UNSPECIFIED
+}
+
+func (x *ExportUsersRequest) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *ExportUsersRequest) GetFilter() *UserFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type UserFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CreatedFrom   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	ClaimId       *int32                 `protobuf:"varint,3,opt,name=claim_id,json=claimId,proto3,oneof" json:"claim_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserFilter) Reset() {
+	*x = UserFilter{}
+	mi := &file_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserFilter) ProtoMessage() {}
+
+func (x *UserFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserFilter.ProtoReflect.Descriptor instead.
+func (*UserFilter) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *CreateUserRequest) GetEmail() string {
+func (x *UserFilter) GetCreatedFrom() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Email
+		return x.CreatedFrom
 	}
-	return ""
+	return nil
 }
 
-func (x *CreateUserRequest) GetPassword() string {
+func (x *UserFilter) GetCreatedTo() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Password
+		return x.CreatedTo
 	}
-	return ""
+	return nil
 }
 
-func (x *CreateUserRequest) GetClaimIds() []int32 {
-	if x != nil {
-		return x.ClaimIds
+func (x *UserFilter) GetClaimId() int32 {
+	if x != nil && x.ClaimId != nil {
+		return *x.ClaimId
 	}
-	return nil
+	return 0
 }
 
-type CreateUserResponse struct {
+type ExportUsersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Chunk         []byte                 `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateUserResponse) Reset() {
-	*x = CreateUserResponse{}
-	mi := &file_user_proto_msgTypes[3]
+func (x *ExportUsersResponse) Reset() {
+	*x = ExportUsersResponse{}
+	mi := &file_user_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateUserResponse) String() string {
+func (x *ExportUsersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateUserResponse) ProtoMessage() {}
+func (*ExportUsersResponse) ProtoMessage() {}
 
-func (x *CreateUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[3]
+func (x *ExportUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -237,40 +1002,40 @@ func (x *CreateUserResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateUserResponse.ProtoReflect.Descriptor instead.
-func (*CreateUserResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ExportUsersResponse.ProtoReflect.Descriptor instead.
+func (*ExportUsersResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *CreateUserResponse) GetId() string {
+func (x *ExportUsersResponse) GetChunk() []byte {
 	if x != nil {
-		return x.Id
+		return x.Chunk
 	}
-	return ""
+	return nil
 }
 
-type CreateManyUsersRequest struct {
+type WatchUsersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Users         []*CreateUserRequest   `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	ResumeToken   string                 `protobuf:"bytes,1,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateManyUsersRequest) Reset() {
-	*x = CreateManyUsersRequest{}
-	mi := &file_user_proto_msgTypes[4]
+func (x *WatchUsersRequest) Reset() {
+	*x = WatchUsersRequest{}
+	mi := &file_user_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateManyUsersRequest) String() string {
+func (x *WatchUsersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateManyUsersRequest) ProtoMessage() {}
+func (*WatchUsersRequest) ProtoMessage() {}
 
-func (x *CreateManyUsersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[4]
+func (x *WatchUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -281,40 +1046,44 @@ func (x *CreateManyUsersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateManyUsersRequest.ProtoReflect.Descriptor instead.
-func (*CreateManyUsersRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use WatchUsersRequest.ProtoReflect.Descriptor instead.
+func (*WatchUsersRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *CreateManyUsersRequest) GetUsers() []*CreateUserRequest {
+func (x *WatchUsersRequest) GetResumeToken() string {
 	if x != nil {
-		return x.Users
+		return x.ResumeToken
 	}
-	return nil
+	return ""
 }
 
-type CreateManyUsersResponse struct {
+type UserChange struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	ResumeToken   string                 `protobuf:"bytes,1,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	Type          UserChangeType         `protobuf:"varint,2,opt,name=type,proto3,enum=user.UserChangeType" json:"type,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	User          *GetUserResponse       `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateManyUsersResponse) Reset() {
-	*x = CreateManyUsersResponse{}
-	mi := &file_user_proto_msgTypes[5]
+func (x *UserChange) Reset() {
+	*x = UserChange{}
+	mi := &file_user_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateManyUsersResponse) String() string {
+func (x *UserChange) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateManyUsersResponse) ProtoMessage() {}
+func (*UserChange) ProtoMessage() {}
 
-func (x *CreateManyUsersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[5]
+func (x *UserChange) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -325,14 +1094,42 @@ func (x *CreateManyUsersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateManyUsersResponse.ProtoReflect.Descriptor instead.
-func (*CreateManyUsersResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use UserChange.ProtoReflect.Descriptor instead.
+func (*UserChange) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *CreateManyUsersResponse) GetIds() []string {
+func (x *UserChange) GetResumeToken() string {
 	if x != nil {
-		return x.Ids
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *UserChange) GetType() UserChangeType {
+	if x != nil {
+		return x.Type
+	}
+	return UserChangeType_USER_CHANGE_TYPE_UNSPECIFIED
+}
+
+func (x *UserChange) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserChange) GetUser() *GetUserResponse {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *UserChange) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
 	}
 	return nil
 }
@@ -346,7 +1143,7 @@ type GetUserByEmailRequest struct {
 
 func (x *GetUserByEmailRequest) Reset() {
 	*x = GetUserByEmailRequest{}
-	mi := &file_user_proto_msgTypes[6]
+	mi := &file_user_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -358,7 +1155,7 @@ func (x *GetUserByEmailRequest) String() string {
 func (*GetUserByEmailRequest) ProtoMessage() {}
 
 func (x *GetUserByEmailRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[6]
+	mi := &file_user_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -371,7 +1168,7 @@ func (x *GetUserByEmailRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserByEmailRequest.ProtoReflect.Descriptor instead.
 func (*GetUserByEmailRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{6}
+	return file_user_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetUserByEmailRequest) GetEmail() string {
@@ -390,7 +1187,7 @@ type GetUserByIDRequest struct {
 
 func (x *GetUserByIDRequest) Reset() {
 	*x = GetUserByIDRequest{}
-	mi := &file_user_proto_msgTypes[7]
+	mi := &file_user_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -402,7 +1199,7 @@ func (x *GetUserByIDRequest) String() string {
 func (*GetUserByIDRequest) ProtoMessage() {}
 
 func (x *GetUserByIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[7]
+	mi := &file_user_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -415,7 +1212,7 @@ func (x *GetUserByIDRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserByIDRequest.ProtoReflect.Descriptor instead.
 func (*GetUserByIDRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{7}
+	return file_user_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *GetUserByIDRequest) GetId() string {
@@ -434,13 +1231,14 @@ type GetUserResponse struct {
 	ClaimIds      []int32                `protobuf:"varint,5,rep,packed,name=claim_ids,json=claimIds,proto3" json:"claim_ids,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	AvatarUrl     string                 `protobuf:"bytes,8,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetUserResponse) Reset() {
 	*x = GetUserResponse{}
-	mi := &file_user_proto_msgTypes[8]
+	mi := &file_user_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -452,7 +1250,7 @@ func (x *GetUserResponse) String() string {
 func (*GetUserResponse) ProtoMessage() {}
 
 func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[8]
+	mi := &file_user_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -465,7 +1263,7 @@ func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserResponse.ProtoReflect.Descriptor instead.
 func (*GetUserResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{8}
+	return file_user_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *GetUserResponse) GetId() string {
@@ -517,6 +1315,13 @@ func (x *GetUserResponse) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *GetUserResponse) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
 type UpdateUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -532,7 +1337,7 @@ type UpdateUserRequest struct {
 
 func (x *UpdateUserRequest) Reset() {
 	*x = UpdateUserRequest{}
-	mi := &file_user_proto_msgTypes[9]
+	mi := &file_user_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -544,7 +1349,7 @@ func (x *UpdateUserRequest) String() string {
 func (*UpdateUserRequest) ProtoMessage() {}
 
 func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[9]
+	mi := &file_user_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -557,7 +1362,7 @@ func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
 func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{9}
+	return file_user_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *UpdateUserRequest) GetId() string {
@@ -609,6 +1414,50 @@ func (x *UpdateUserRequest) GetClaims() *ClaimIds {
 	return nil
 }
 
+type ConfirmEmailChangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmEmailChangeRequest) Reset() {
+	*x = ConfirmEmailChangeRequest{}
+	mi := &file_user_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmEmailChangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmEmailChangeRequest) ProtoMessage() {}
+
+func (x *ConfirmEmailChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmEmailChangeRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmEmailChangeRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ConfirmEmailChangeRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
 type ClaimIds struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ids           []int32                `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
@@ -618,7 +1467,7 @@ type ClaimIds struct {
 
 func (x *ClaimIds) Reset() {
 	*x = ClaimIds{}
-	mi := &file_user_proto_msgTypes[10]
+	mi := &file_user_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -630,7 +1479,7 @@ func (x *ClaimIds) String() string {
 func (*ClaimIds) ProtoMessage() {}
 
 func (x *ClaimIds) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[10]
+	mi := &file_user_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -643,7 +1492,7 @@ func (x *ClaimIds) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ClaimIds.ProtoReflect.Descriptor instead.
 func (*ClaimIds) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{10}
+	return file_user_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *ClaimIds) GetIds() []int32 {
@@ -662,7 +1511,7 @@ type GetAllUsersResponse struct {
 
 func (x *GetAllUsersResponse) Reset() {
 	*x = GetAllUsersResponse{}
-	mi := &file_user_proto_msgTypes[11]
+	mi := &file_user_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -674,7 +1523,7 @@ func (x *GetAllUsersResponse) String() string {
 func (*GetAllUsersResponse) ProtoMessage() {}
 
 func (x *GetAllUsersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[11]
+	mi := &file_user_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -687,7 +1536,7 @@ func (x *GetAllUsersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAllUsersResponse.ProtoReflect.Descriptor instead.
 func (*GetAllUsersResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{11}
+	return file_user_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *GetAllUsersResponse) GetUsers() []*GetUserResponse {
@@ -697,6 +1546,118 @@ func (x *GetAllUsersResponse) GetUsers() []*GetUserResponse {
 	return nil
 }
 
+type SearchUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersRequest) Reset() {
+	*x = SearchUsersRequest{}
+	mi := &file_user_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersRequest) ProtoMessage() {}
+
+func (x *SearchUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersRequest.ProtoReflect.Descriptor instead.
+func (*SearchUsersRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SearchUsersRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchUsersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchUsersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type SearchUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*GetUserResponse     `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersResponse) Reset() {
+	*x = SearchUsersResponse{}
+	mi := &file_user_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersResponse) ProtoMessage() {}
+
+func (x *SearchUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersResponse.ProtoReflect.Descriptor instead.
+func (*SearchUsersResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SearchUsersResponse) GetUsers() []*GetUserResponse {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *SearchUsersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 type GetClaimsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Claims        []*Claim               `protobuf:"bytes,1,rep,name=claims,proto3" json:"claims,omitempty"`
@@ -706,7 +1667,7 @@ type GetClaimsResponse struct {
 
 func (x *GetClaimsResponse) Reset() {
 	*x = GetClaimsResponse{}
-	mi := &file_user_proto_msgTypes[12]
+	mi := &file_user_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -718,7 +1679,7 @@ func (x *GetClaimsResponse) String() string {
 func (*GetClaimsResponse) ProtoMessage() {}
 
 func (x *GetClaimsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[12]
+	mi := &file_user_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -731,7 +1692,7 @@ func (x *GetClaimsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetClaimsResponse.ProtoReflect.Descriptor instead.
 func (*GetClaimsResponse) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{12}
+	return file_user_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *GetClaimsResponse) GetClaims() []*Claim {
@@ -751,7 +1712,7 @@ type Claim struct {
 
 func (x *Claim) Reset() {
 	*x = Claim{}
-	mi := &file_user_proto_msgTypes[13]
+	mi := &file_user_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -763,7 +1724,7 @@ func (x *Claim) String() string {
 func (*Claim) ProtoMessage() {}
 
 func (x *Claim) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[13]
+	mi := &file_user_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -776,7 +1737,7 @@ func (x *Claim) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Claim.ProtoReflect.Descriptor instead.
 func (*Claim) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{13}
+	return file_user_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *Claim) GetId() int32 {
@@ -802,7 +1763,7 @@ type DeleteUserRequest struct {
 
 func (x *DeleteUserRequest) Reset() {
 	*x = DeleteUserRequest{}
-	mi := &file_user_proto_msgTypes[14]
+	mi := &file_user_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -814,7 +1775,7 @@ func (x *DeleteUserRequest) String() string {
 func (*DeleteUserRequest) ProtoMessage() {}
 
 func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_msgTypes[14]
+	mi := &file_user_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -827,7 +1788,7 @@ func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
 func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
-	return file_user_proto_rawDescGZIP(), []int{14}
+	return file_user_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *DeleteUserRequest) GetId() string {
@@ -856,15 +1817,62 @@ const file_user_proto_rawDesc = "" +
 	"\bpassword\x18\x04 \x01(\tR\bpassword\x12\x1b\n" +
 	"\tclaim_ids\x18\x05 \x03(\x05R\bclaimIds\"$\n" +
 	"\x12CreateUserResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"G\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"a\n" +
 	"\x16CreateManyUsersRequest\x12-\n" +
-	"\x05users\x18\x01 \x03(\v2\x17.user.CreateUserRequestR\x05users\"+\n" +
+	"\x05users\x18\x01 \x03(\v2\x17.user.CreateUserRequestR\x05users\x12\x18\n" +
+	"\apartial\x18\x02 \x01(\bR\apartial\"[\n" +
 	"\x17CreateManyUsersResponse\x12\x10\n" +
-	"\x03ids\x18\x01 \x03(\tR\x03ids\"-\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12.\n" +
+	"\aresults\x18\x02 \x03(\v2\x14.user.BulkItemResultR\aresults\"a\n" +
+	"\x16UpdateManyUsersRequest\x12-\n" +
+	"\x05users\x18\x01 \x03(\v2\x17.user.UpdateUserRequestR\x05users\x12\x18\n" +
+	"\apartial\x18\x02 \x01(\bR\apartial\"D\n" +
+	"\x16DeleteManyUsersRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12\x18\n" +
+	"\apartial\x18\x02 \x01(\bR\apartial\"C\n" +
+	"\x11BulkUsersResponse\x12.\n" +
+	"\aresults\x18\x01 \x03(\v2\x14.user.BulkItemResultR\aresults\"d\n" +
+	"\x0eBulkItemResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"o\n" +
+	"\x12ImportUsersRequest\x12*\n" +
+	"\x06format\x18\x01 \x01(\x0e2\x12.user.ImportFormatR\x06format\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\x12\x14\n" +
+	"\x05chunk\x18\x03 \x01(\fR\x05chunk\"\x8e\x01\n" +
+	"\x13ImportUsersResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x05R\x05total\x12\x1a\n" +
+	"\bimported\x18\x02 \x01(\x05R\bimported\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\x12,\n" +
+	"\x06errors\x18\x04 \x03(\v2\x14.user.BulkItemResultR\x06errors\"\x84\x01\n" +
+	"\x12ExportUsersRequest\x12*\n" +
+	"\x06format\x18\x01 \x01(\x0e2\x12.user.ExportFormatR\x06format\x12\x18\n" +
+	"\acolumns\x18\x02 \x03(\tR\acolumns\x12(\n" +
+	"\x06filter\x18\x03 \x01(\v2\x10.user.UserFilterR\x06filter\"\xb3\x01\n" +
+	"\n" +
+	"UserFilter\x12=\n" +
+	"\fcreated_from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedFrom\x129\n" +
+	"\n" +
+	"created_to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedTo\x12\x1e\n" +
+	"\bclaim_id\x18\x03 \x01(\x05H\x00R\aclaimId\x88\x01\x01B\v\n" +
+	"\t_claim_id\"+\n" +
+	"\x13ExportUsersResponse\x12\x14\n" +
+	"\x05chunk\x18\x01 \x01(\fR\x05chunk\"6\n" +
+	"\x11WatchUsersRequest\x12!\n" +
+	"\fresume_token\x18\x01 \x01(\tR\vresumeToken\"\xda\x01\n" +
+	"\n" +
+	"UserChange\x12!\n" +
+	"\fresume_token\x18\x01 \x01(\tR\vresumeToken\x12(\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x14.user.UserChangeTypeR\x04type\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12)\n" +
+	"\x04user\x18\x04 \x01(\v2\x15.user.GetUserResponseR\x04user\x12;\n" +
+	"\voccurred_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\"-\n" +
 	"\x15GetUserByEmailRequest\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\"$\n" +
 	"\x12GetUserByIDRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\xfa\x01\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x99\x02\n" +
 	"\x0fGetUserResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
@@ -874,7 +1882,9 @@ const file_user_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xc2\x02\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\b \x01(\tR\tavatarUrl\"\xc2\x02\n" +
 	"\x11UpdateUserRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x88\x01\x01\x12\x1f\n" +
@@ -888,24 +1898,48 @@ const file_user_proto_rawDesc = "" +
 	"\x06_emailB\x0f\n" +
 	"\r_old_passwordB\x0f\n" +
 	"\r_new_passwordB\t\n" +
-	"\a_claims\"\x1c\n" +
+	"\a_claims\"1\n" +
+	"\x19ConfirmEmailChangeRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"\x1c\n" +
 	"\bClaimIds\x12\x10\n" +
 	"\x03ids\x18\x01 \x03(\x05R\x03ids\"B\n" +
 	"\x13GetAllUsersResponse\x12+\n" +
-	"\x05users\x18\x01 \x03(\v2\x15.user.GetUserResponseR\x05users\"8\n" +
+	"\x05users\x18\x01 \x03(\v2\x15.user.GetUserResponseR\x05users\"f\n" +
+	"\x12SearchUsersRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"j\n" +
+	"\x13SearchUsersResponse\x12+\n" +
+	"\x05users\x18\x01 \x03(\v2\x15.user.GetUserResponseR\x05users\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"8\n" +
 	"\x11GetClaimsResponse\x12#\n" +
 	"\x06claims\x18\x01 \x03(\v2\v.user.ClaimR\x06claims\"-\n" +
 	"\x05Claim\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value\"#\n" +
 	"\x11DeleteUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id2\xb4\t\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id*^\n" +
+	"\fImportFormat\x12\x1d\n" +
+	"\x19IMPORT_FORMAT_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11IMPORT_FORMAT_CSV\x10\x01\x12\x18\n" +
+	"\x14IMPORT_FORMAT_NDJSON\x10\x02*y\n" +
+	"\fExportFormat\x12\x1d\n" +
+	"\x19EXPORT_FORMAT_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11EXPORT_FORMAT_CSV\x10\x01\x12\x18\n" +
+	"\x14EXPORT_FORMAT_NDJSON\x10\x02\x12\x19\n" +
+	"\x15EXPORT_FORMAT_PARQUET\x10\x03*\x8c\x01\n" +
+	"\x0eUserChangeType\x12 \n" +
+	"\x1cUSER_CHANGE_TYPE_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18USER_CHANGE_TYPE_CREATED\x10\x01\x12\x1c\n" +
+	"\x18USER_CHANGE_TYPE_UPDATED\x10\x02\x12\x1c\n" +
+	"\x18USER_CHANGE_TYPE_DELETED\x10\x032\xae\x12\n" +
 	"\vUserService\x12p\n" +
 	"\x05Login\x12\x16.user.LoginUserRequest\x1a\x17.user.LoginUserResponse\"6\x92A\x1c\x12\n" +
 	"Login user\x1a\x0eLogs in a user\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/users/login\x12r\n" +
-	"\x06Create\x12\x17.user.CreateUserRequest\x1a\x18.user.CreateUserResponse\"5\x92A!\x12\vCreate user\x1a\x12Creates a new user\x82\xd3\xe4\x93\x02\v:\x01*\"\x06/users\x12\x9a\x01\n" +
+	"\x06Create\x12\x17.user.CreateUserRequest\x1a\x18.user.CreateUserResponse\"5\x92A!\x12\vCreate user\x1a\x12Creates a new user\x82\xd3\xe4\x93\x02\v:\x01*\"\x06/users\x12\xbf\x01\n" +
 	"\n" +
-	"CreateMany\x12\x1c.user.CreateManyUsersRequest\x1a\x1d.user.CreateManyUsersResponse\"O\x92A6\x12\x11Create many users\x1a!Creates multiple users atomically\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/users/many\x12\x7f\n" +
+	"CreateMany\x12\x1c.user.CreateManyUsersRequest\x1a\x1d.user.CreateManyUsersResponse\"t\x92A[\x12\x11Create many users\x1aFCreates multiple users atomically, or item by item when partial is set\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/users/many\x12\x7f\n" +
 	"\x06GetAll\x12\x16.google.protobuf.Empty\x1a\x19.user.GetAllUsersResponse\"B\x92A1\x12\rGet all users\x1a\x12Gets all the usersb\f\n" +
 	"\n" +
 	"\n" +
@@ -918,11 +1952,17 @@ const file_user_proto_rawDesc = "" +
 	"\aGetByID\x12\x18.user.GetUserByIDRequest\x1a\x15.user.GetUserResponse\"G\x92A1\x12\x0eGet user by ID\x1a\x11Gets a user by IDb\f\n" +
 	"\n" +
 	"\n" +
-	"\x06Bearer\x12\x00\x82\xd3\xe4\x93\x02\r\x12\v/users/{id}\x12\x7f\n" +
-	"\x06Update\x12\x17.user.UpdateUserRequest\x1a\x16.google.protobuf.Empty\"D\x92A+\x12\vUpdate user\x1a\x0eUpdates a userb\f\n" +
+	"\x06Bearer\x12\x00\x82\xd3\xe4\x93\x02\r\x12\v/users/{id}\x12\xc2\x01\n" +
+	"\x06Update\x12\x17.user.UpdateUserRequest\x1a\x16.google.protobuf.Empty\"\x86\x01\x92Am\x12\vUpdate user\x1aPUpdates a user. A new email only replaces the current one once confirmed from itb\f\n" +
+	"\n" +
 	"\n" +
+	"\x06Bearer\x12\x00\x82\xd3\xe4\x93\x02\x10:\x01*2\v/users/{id}\x12\xdd\x01\n" +
+	"\x12ConfirmEmailChange\x12\x1f.user.ConfirmEmailChangeRequest\x1a\x16.google.protobuf.Empty\"\x8d\x01\x92Ak\x12\x14Confirm email change\x1aSReplaces the email of a user by the new one, with the confirmation token sent to it\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/users/email/confirm\x12\xc8\x01\n" +
 	"\n" +
-	"\x06Bearer\x12\x00\x82\xd3\xe4\x93\x02\x10:\x01*2\v/users/{id}\x12\x80\x01\n" +
+	"UpdateMany\x12\x1c.user.UpdateManyUsersRequest\x1a\x17.user.BulkUsersResponse\"\x82\x01\x92Ai\x12\x11Update many users\x1aFUpdates multiple users atomically, or item by item when partial is setb\f\n" +
+	"\n" +
+	"\n" +
+	"\x06Bearer\x12\x00\x82\xd3\xe4\x93\x02\x10:\x01*2\v/users/many\x12\x80\x01\n" +
 	"\tGetClaims\x12\x16.google.protobuf.Empty\x1a\x17.user.GetClaimsResponse\"B\x92A0\x12\x0fGet user claims\x1a\x0fGets all claimsb\f\n" +
 	"\n" +
 	"\n" +
@@ -930,7 +1970,20 @@ const file_user_proto_rawDesc = "" +
 	"\x06Delete\x12\x17.user.DeleteUserRequest\x1a\x16.google.protobuf.Empty\"A\x92A+\x12\vDelete user\x1a\x0eDeletes a userb\f\n" +
 	"\n" +
 	"\n" +
-	"\x06Bearer\x12\x00\x82\xd3\xe4\x93\x02\r*\v/users/{id}B\x81\x01\n" +
+	"\x06Bearer\x12\x00\x82\xd3\xe4\x93\x02\r*\v/users/{id}\x12\xcf\x01\n" +
+	"\n" +
+	"DeleteMany\x12\x1c.user.DeleteManyUsersRequest\x1a\x17.user.BulkUsersResponse\"\x89\x01\x92Ai\x12\x11Delete many users\x1aFDeletes multiple users atomically, or item by item when partial is setb\f\n" +
+	"\n" +
+	"\n" +
+	"\x06Bearer\x12\x00\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/users/many/delete\x12D\n" +
+	"\vImportUsers\x12\x18.user.ImportUsersRequest\x1a\x19.user.ImportUsersResponse(\x01\x12D\n" +
+	"\vExportUsers\x12\x18.user.ExportUsersRequest\x1a\x19.user.ExportUsersResponse0\x01\x129\n" +
+	"\n" +
+	"WatchUsers\x12\x17.user.WatchUsersRequest\x1a\x10.user.UserChange0\x01\x12\xca\x01\n" +
+	"\vSearchUsers\x12\x18.user.SearchUsersRequest\x1a\x19.user.SearchUsersResponse\"\x85\x01\x92Am\x12\fSearch users\x1aOSearches users by name, surnames and email, from the most to the least relevantb\f\n" +
+	"\n" +
+	"\n" +
+	"\x06Bearer\x12\x00\x82\xd3\xe4\x93\x02\x0f\x12\r/users/searchB\x81\x01\n" +
 	"\bcom.userB\tUserProtoP\x01Z:github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb\xa2\x02\x03UXX\xaa\x02\x04User\xca\x02\x04User\xe2\x02\x10User\\GPBMetadata\xea\x02\x04Userb\x06proto3"
 
 var (
@@ -945,57 +1998,102 @@ func file_user_proto_rawDescGZIP() []byte {
 	return file_user_proto_rawDescData
 }
 
-var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_user_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
 var file_user_proto_goTypes = []any{
-	(*LoginUserRequest)(nil),        // 0: user.LoginUserRequest
-	(*LoginUserResponse)(nil),       // 1: user.LoginUserResponse
-	(*CreateUserRequest)(nil),       // 2: user.CreateUserRequest
-	(*CreateUserResponse)(nil),      // 3: user.CreateUserResponse
-	(*CreateManyUsersRequest)(nil),  // 4: user.CreateManyUsersRequest
-	(*CreateManyUsersResponse)(nil), // 5: user.CreateManyUsersResponse
-	(*GetUserByEmailRequest)(nil),   // 6: user.GetUserByEmailRequest
-	(*GetUserByIDRequest)(nil),      // 7: user.GetUserByIDRequest
-	(*GetUserResponse)(nil),         // 8: user.GetUserResponse
-	(*UpdateUserRequest)(nil),       // 9: user.UpdateUserRequest
-	(*ClaimIds)(nil),                // 10: user.ClaimIds
-	(*GetAllUsersResponse)(nil),     // 11: user.GetAllUsersResponse
-	(*GetClaimsResponse)(nil),       // 12: user.GetClaimsResponse
-	(*Claim)(nil),                   // 13: user.Claim
-	(*DeleteUserRequest)(nil),       // 14: user.DeleteUserRequest
-	(*timestamppb.Timestamp)(nil),   // 15: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),           // 16: google.protobuf.Empty
+	(ImportFormat)(0),                 // 0: user.ImportFormat
+	(ExportFormat)(0),                 // 1: user.ExportFormat
+	(UserChangeType)(0),               // 2: user.UserChangeType
+	(*LoginUserRequest)(nil),          // 3: user.LoginUserRequest
+	(*LoginUserResponse)(nil),         // 4: user.LoginUserResponse
+	(*CreateUserRequest)(nil),         // 5: user.CreateUserRequest
+	(*CreateUserResponse)(nil),        // 6: user.CreateUserResponse
+	(*CreateManyUsersRequest)(nil),    // 7: user.CreateManyUsersRequest
+	(*CreateManyUsersResponse)(nil),   // 8: user.CreateManyUsersResponse
+	(*UpdateManyUsersRequest)(nil),    // 9: user.UpdateManyUsersRequest
+	(*DeleteManyUsersRequest)(nil),    // 10: user.DeleteManyUsersRequest
+	(*BulkUsersResponse)(nil),         // 11: user.BulkUsersResponse
+	(*BulkItemResult)(nil),            // 12: user.BulkItemResult
+	(*ImportUsersRequest)(nil),        // 13: user.ImportUsersRequest
+	(*ImportUsersResponse)(nil),       // 14: user.ImportUsersResponse
+	(*ExportUsersRequest)(nil),        // 15: user.ExportUsersRequest
+	(*UserFilter)(nil),                // 16: user.UserFilter
+	(*ExportUsersResponse)(nil),       // 17: user.ExportUsersResponse
+	(*WatchUsersRequest)(nil),         // 18: user.WatchUsersRequest
+	(*UserChange)(nil),                // 19: user.UserChange
+	(*GetUserByEmailRequest)(nil),     // 20: user.GetUserByEmailRequest
+	(*GetUserByIDRequest)(nil),        // 21: user.GetUserByIDRequest
+	(*GetUserResponse)(nil),           // 22: user.GetUserResponse
+	(*UpdateUserRequest)(nil),         // 23: user.UpdateUserRequest
+	(*ConfirmEmailChangeRequest)(nil), // 24: user.ConfirmEmailChangeRequest
+	(*ClaimIds)(nil),                  // 25: user.ClaimIds
+	(*GetAllUsersResponse)(nil),       // 26: user.GetAllUsersResponse
+	(*SearchUsersRequest)(nil),        // 27: user.SearchUsersRequest
+	(*SearchUsersResponse)(nil),       // 28: user.SearchUsersResponse
+	(*GetClaimsResponse)(nil),         // 29: user.GetClaimsResponse
+	(*Claim)(nil),                     // 30: user.Claim
+	(*DeleteUserRequest)(nil),         // 31: user.DeleteUserRequest
+	(*timestamppb.Timestamp)(nil),     // 32: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),             // 33: google.protobuf.Empty
 }
 var file_user_proto_depIdxs = []int32{
-	8,  // 0: user.LoginUserResponse.user:type_name -> user.GetUserResponse
-	2,  // 1: user.CreateManyUsersRequest.users:type_name -> user.CreateUserRequest
-	15, // 2: user.GetUserResponse.created_at:type_name -> google.protobuf.Timestamp
-	15, // 3: user.GetUserResponse.updated_at:type_name -> google.protobuf.Timestamp
-	10, // 4: user.UpdateUserRequest.claims:type_name -> user.ClaimIds
-	8,  // 5: user.GetAllUsersResponse.users:type_name -> user.GetUserResponse
-	13, // 6: user.GetClaimsResponse.claims:type_name -> user.Claim
-	0,  // 7: user.UserService.Login:input_type -> user.LoginUserRequest
-	2,  // 8: user.UserService.Create:input_type -> user.CreateUserRequest
-	4,  // 9: user.UserService.CreateMany:input_type -> user.CreateManyUsersRequest
-	16, // 10: user.UserService.GetAll:input_type -> google.protobuf.Empty
-	6,  // 11: user.UserService.GetByEmail:input_type -> user.GetUserByEmailRequest
-	7,  // 12: user.UserService.GetByID:input_type -> user.GetUserByIDRequest
-	9,  // 13: user.UserService.Update:input_type -> user.UpdateUserRequest
-	16, // 14: user.UserService.GetClaims:input_type -> google.protobuf.Empty
-	14, // 15: user.UserService.Delete:input_type -> user.DeleteUserRequest
-	1,  // 16: user.UserService.Login:output_type -> user.LoginUserResponse
-	3,  // 17: user.UserService.Create:output_type -> user.CreateUserResponse
-	5,  // 18: user.UserService.CreateMany:output_type -> user.CreateManyUsersResponse
-	11, // 19: user.UserService.GetAll:output_type -> user.GetAllUsersResponse
-	8,  // 20: user.UserService.GetByEmail:output_type -> user.GetUserResponse
-	8,  // 21: user.UserService.GetByID:output_type -> user.GetUserResponse
-	16, // 22: user.UserService.Update:output_type -> google.protobuf.Empty
-	12, // 23: user.UserService.GetClaims:output_type -> user.GetClaimsResponse
-	16, // 24: user.UserService.Delete:output_type -> google.protobuf.Empty
-	16, // [16:25] is the sub-list for method output_type
-	7,  // [7:16] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	22, // 0: user.LoginUserResponse.user:type_name -> user.GetUserResponse
+	5,  // 1: user.CreateManyUsersRequest.users:type_name -> user.CreateUserRequest
+	12, // 2: user.CreateManyUsersResponse.results:type_name -> user.BulkItemResult
+	23, // 3: user.UpdateManyUsersRequest.users:type_name -> user.UpdateUserRequest
+	12, // 4: user.BulkUsersResponse.results:type_name -> user.BulkItemResult
+	0,  // 5: user.ImportUsersRequest.format:type_name -> user.ImportFormat
+	12, // 6: user.ImportUsersResponse.errors:type_name -> user.BulkItemResult
+	1,  // 7: user.ExportUsersRequest.format:type_name -> user.ExportFormat
+	16, // 8: user.ExportUsersRequest.filter:type_name -> user.UserFilter
+	32, // 9: user.UserFilter.created_from:type_name -> google.protobuf.Timestamp
+	32, // 10: user.UserFilter.created_to:type_name -> google.protobuf.Timestamp
+	2,  // 11: user.UserChange.type:type_name -> user.UserChangeType
+	22, // 12: user.UserChange.user:type_name -> user.GetUserResponse
+	32, // 13: user.UserChange.occurred_at:type_name -> google.protobuf.Timestamp
+	32, // 14: user.GetUserResponse.created_at:type_name -> google.protobuf.Timestamp
+	32, // 15: user.GetUserResponse.updated_at:type_name -> google.protobuf.Timestamp
+	25, // 16: user.UpdateUserRequest.claims:type_name -> user.ClaimIds
+	22, // 17: user.GetAllUsersResponse.users:type_name -> user.GetUserResponse
+	22, // 18: user.SearchUsersResponse.users:type_name -> user.GetUserResponse
+	30, // 19: user.GetClaimsResponse.claims:type_name -> user.Claim
+	3,  // 20: user.UserService.Login:input_type -> user.LoginUserRequest
+	5,  // 21: user.UserService.Create:input_type -> user.CreateUserRequest
+	7,  // 22: user.UserService.CreateMany:input_type -> user.CreateManyUsersRequest
+	33, // 23: user.UserService.GetAll:input_type -> google.protobuf.Empty
+	20, // 24: user.UserService.GetByEmail:input_type -> user.GetUserByEmailRequest
+	21, // 25: user.UserService.GetByID:input_type -> user.GetUserByIDRequest
+	23, // 26: user.UserService.Update:input_type -> user.UpdateUserRequest
+	24, // 27: user.UserService.ConfirmEmailChange:input_type -> user.ConfirmEmailChangeRequest
+	9,  // 28: user.UserService.UpdateMany:input_type -> user.UpdateManyUsersRequest
+	33, // 29: user.UserService.GetClaims:input_type -> google.protobuf.Empty
+	31, // 30: user.UserService.Delete:input_type -> user.DeleteUserRequest
+	10, // 31: user.UserService.DeleteMany:input_type -> user.DeleteManyUsersRequest
+	13, // 32: user.UserService.ImportUsers:input_type -> user.ImportUsersRequest
+	15, // 33: user.UserService.ExportUsers:input_type -> user.ExportUsersRequest
+	18, // 34: user.UserService.WatchUsers:input_type -> user.WatchUsersRequest
+	27, // 35: user.UserService.SearchUsers:input_type -> user.SearchUsersRequest
+	4,  // 36: user.UserService.Login:output_type -> user.LoginUserResponse
+	6,  // 37: user.UserService.Create:output_type -> user.CreateUserResponse
+	8,  // 38: user.UserService.CreateMany:output_type -> user.CreateManyUsersResponse
+	26, // 39: user.UserService.GetAll:output_type -> user.GetAllUsersResponse
+	22, // 40: user.UserService.GetByEmail:output_type -> user.GetUserResponse
+	22, // 41: user.UserService.GetByID:output_type -> user.GetUserResponse
+	33, // 42: user.UserService.Update:output_type -> google.protobuf.Empty
+	33, // 43: user.UserService.ConfirmEmailChange:output_type -> google.protobuf.Empty
+	11, // 44: user.UserService.UpdateMany:output_type -> user.BulkUsersResponse
+	29, // 45: user.UserService.GetClaims:output_type -> user.GetClaimsResponse
+	33, // 46: user.UserService.Delete:output_type -> google.protobuf.Empty
+	11, // 47: user.UserService.DeleteMany:output_type -> user.BulkUsersResponse
+	14, // 48: user.UserService.ImportUsers:output_type -> user.ImportUsersResponse
+	17, // 49: user.UserService.ExportUsers:output_type -> user.ExportUsersResponse
+	19, // 50: user.UserService.WatchUsers:output_type -> user.UserChange
+	28, // 51: user.UserService.SearchUsers:output_type -> user.SearchUsersResponse
+	36, // [36:52] is the sub-list for method output_type
+	20, // [20:36] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_user_proto_init() }
@@ -1003,19 +2101,21 @@ func file_user_proto_init() {
 	if File_user_proto != nil {
 		return
 	}
-	file_user_proto_msgTypes[9].OneofWrappers = []any{}
+	file_user_proto_msgTypes[13].OneofWrappers = []any{}
+	file_user_proto_msgTypes[20].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_proto_rawDesc), len(file_user_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   15,
+			NumEnums:      3,
+			NumMessages:   29,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_user_proto_goTypes,
 		DependencyIndexes: file_user_proto_depIdxs,
+		EnumInfos:         file_user_proto_enumTypes,
 		MessageInfos:      file_user_proto_msgTypes,
 	}.Build()
 	File_user_proto = out.File
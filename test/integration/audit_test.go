@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// TestListAuditEvents_Ok checks that ListAuditEvents endpoint returns the audit events of the user mutations
+func TestListAuditEvents_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, _ := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		deleteURL := fmt.Sprintf("http://:%d/v1/users/%s", cfg.HTTPPort, testUser.ID)
+		deleteReq, err := http.NewRequest(http.MethodDelete, deleteURL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		deleteReq.Header.Set("Authorization", nonExpiryToken)
+		deleteReq.Header.Set("X-Request-Id", "test-request")
+
+		deleteResp, err := http.DefaultClient.Do(deleteReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		deleteResp.Body.Close()
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/audit/events?target_id=%s", cfg.HTTPPort, testUser.ID)
+
+		req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		var response pb.ListAuditEventsResponse
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading the response while calling %s: %s", resp.Request.URL, err)
+		}
+		if err := protojson.Unmarshal(bodyBytes, &response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+
+		assert.Len(t, response.Events, 1)
+		assert.Equal(t, "delete", response.Events[0].Action)
+		assert.Equal(t, "test-request", response.Events[0].RequestId)
+		assert.Equal(t, testUser.Email, response.Events[0].Before.AsMap()["email"])
+		assert.Equal(t, "[REDACTED]", response.Events[0].Before.AsMap()["password_hash"])
+	})
+}
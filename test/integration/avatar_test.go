@@ -0,0 +1,210 @@
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// TestUploadAvatar_Ok checks that an uploaded avatar is served under the URL set to the user, along with its thumbnails
+func TestUploadAvatar_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, _ := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		resp := uploadAvatar(t, cfg, testUser.ID, testAvatar(t))
+
+		// Assert
+		assert.True(t, strings.HasPrefix(resp.AvatarUrl, fmt.Sprintf("/v1/blobs/avatars/%s/avatar?v=", testUser.ID)))
+		assert.Len(t, resp.Thumbnails, 1)
+		assert.Equal(t, int32(64), resp.Thumbnails[0].Size)
+
+		user, err := findUser(testUser.ID, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, resp.AvatarUrl, user.AvatarURL)
+
+		for _, url := range []string{resp.AvatarUrl, resp.Thumbnails[0].Url} {
+			blobResp, err := http.Get(fmt.Sprintf("http://:%d%s", cfg.HTTPPort, url))
+			if err != nil {
+				t.Fatal(err)
+			}
+			blobResp.Body.Close()
+			assert.Equal(t, http.StatusOK, blobResp.StatusCode)
+			assert.Equal(t, "image/png", blobResp.Header.Get("Content-Type"))
+		}
+	})
+}
+
+// TestUploadAvatar_S3 checks that an uploaded avatar is stored in the bucket of an S3-compatible storage
+func TestUploadAvatar_S3(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		endpoint := os.Getenv(minioEndpointEnv)
+		cfg := New(t, database, func(c *config.Config) {
+			c.Avatars.Storage = "s3"
+			c.Avatars.S3.Endpoint = endpoint
+			c.Avatars.S3.Bucket = "avatars"
+			c.Avatars.S3.AccessKeyID = minioUser
+			c.Avatars.S3.SecretAccessKey = minioPassword
+		})
+		testUser, _ := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		resp := uploadAvatar(t, cfg, testUser.ID, testAvatar(t))
+
+		// Assert
+		assert.True(t, strings.HasPrefix(resp.AvatarUrl, fmt.Sprintf("%s/avatars/avatars/%s/avatar?v=", endpoint, testUser.ID)))
+		assert.True(t, strings.HasPrefix(resp.Thumbnails[0].Url, fmt.Sprintf("%s/avatars/avatars/%s/avatar_64.png?v=", endpoint, testUser.ID)))
+	}, "mongo")
+}
+
+// TestUploadAvatar_InvalidContentType checks that an avatar whose content type is not accepted is rejected
+func TestUploadAvatar_InvalidContentType(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, _ := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		resp := doAvatarUpload(t, cfg, testUser.ID, "image/gif", []byte("GIF89a"))
+		defer resp.Body.Close()
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+// TestDeleteAvatar_Ok checks that a deleted avatar is no longer served nor set to the user
+func TestDeleteAvatar_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, _ := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		uploadResp := uploadAvatar(t, cfg, testUser.ID, testAvatar(t))
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/users/%s/avatar", cfg.HTTPPort, testUser.ID)
+		req, err := http.NewRequest(http.MethodDelete, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		user, err := findUser(testUser.ID, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, user.AvatarURL)
+
+		blobResp, err := http.Get(fmt.Sprintf("http://:%d%s", cfg.HTTPPort, uploadResp.AvatarUrl))
+		if err != nil {
+			t.Fatal(err)
+		}
+		blobResp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, blobResp.StatusCode)
+	})
+}
+
+func testAvatar(t *testing.T) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 128, 96))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// uploadAvatar uploads a PNG avatar for the user and returns the parsed response, failing when it is not uploaded
+func uploadAvatar(t *testing.T, cfg config.Config, userID string, content []byte) *pb.UploadAvatarResponse {
+	resp := doAvatarUpload(t, cfg, userID, "image/png", content)
+	defer resp.Body.Close()
+
+	if want, got := http.StatusOK, resp.StatusCode; want != got {
+		t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+	}
+
+	var response pb.UploadAvatarResponse
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error reading the response while calling %s: %s", resp.Request.URL, err)
+	}
+	if err := protojson.Unmarshal(bodyBytes, &response); err != nil {
+		t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+	}
+	return &response
+}
+
+func doAvatarUpload(t *testing.T, cfg config.Config, userID, contentType string, content []byte) *http.Response {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="avatar"`)
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = part.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	url := fmt.Sprintf("http://:%d/v1/users/%s/avatar", cfg.HTTPPort, userID)
+	req, err := http.NewRequest(http.MethodPut, url, &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", nonExpiryToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
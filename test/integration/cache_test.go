@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// TestUserCache_Redis checks that the users are served from a redis cache and invalidated when updated
+func TestUserCache_Redis(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		addr := os.Getenv(redisAddrEnv)
+		cfg := New(t, database, func(c *config.Config) {
+			c.UserCache.Store = "redis"
+			c.UserCache.TTL = utils.Duration{Duration: time.Minute}
+			c.UserCache.EmailTTL = utils.Duration{Duration: time.Minute}
+			c.UserCache.Redis.Addr = addr
+			c.UserCache.Redis.Prefix = fmt.Sprintf("integration-%s:", database)
+		})
+		testUser, _ := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doJSONRequest(t, cfg, http.MethodGet, fmt.Sprintf("/v1/users/%s", testUser.ID), nil, &pb.GetUserResponse{})
+
+		// Act
+		var cached pb.GetUserResponse
+		doJSONRequest(t, cfg, http.MethodGet, fmt.Sprintf("/v1/users/%s", testUser.ID), nil, &cached)
+
+		name := "modified"
+		doJSONRequest(t, cfg, http.MethodPatch, fmt.Sprintf("/v1/users/%s", testUser.ID), &pb.UpdateUserRequest{Name: &name}, &emptypb.Empty{})
+
+		var updated pb.GetUserResponse
+		doJSONRequest(t, cfg, http.MethodGet, fmt.Sprintf("/v1/users/%s", testUser.ID), nil, &updated)
+
+		// Assert
+		assert.Equal(t, testUser.Name, cached.Name)
+		assert.Equal(t, name, updated.Name)
+
+		var health pb.HealthCheckResponse
+		doJSONRequest(t, cfg, http.MethodGet, "/v1/health", nil, &health)
+		assert.Equal(t, "redis", health.UserCache.Store)
+		assert.GreaterOrEqual(t, health.UserCache.Hits, int64(1))
+		assert.GreaterOrEqual(t, health.UserCache.Misses, int64(2))
+	})
+}
+
+// doJSONRequest calls the given endpoint of the API, expecting an OK response to be decoded into response
+func doJSONRequest(t *testing.T, cfg config.Config, method, path string, body, response proto.Message) {
+	t.Helper()
+
+	var b []byte
+	if body != nil {
+		var err error
+		b, err = protojson.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	url := fmt.Sprintf("http://:%d%s", cfg.HTTPPort, path)
+	req, err := http.NewRequest(method, url, bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", nonExpiryToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	if want, got := http.StatusOK, resp.StatusCode; want != got {
+		t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error reading the response while calling %s: %s", resp.Request.URL, err)
+	}
+	if err := protojson.Unmarshal(bodyBytes, response); err != nil {
+		t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+	}
+}
@@ -0,0 +1,62 @@
+package integration
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConfirmEmailChange_Ok checks that a new email only replaces the current one once confirmed with the notified confirmation token
+func TestConfirmEmailChange_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, _ := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		newEmail := fmt.Sprintf("new%d@test.com", rand.Int())
+
+		// Act
+		updateURL := fmt.Sprintf("http://:%d/v1/users/%s", cfg.HTTPPort, testUser.ID)
+		resp := doInvitationRequest(t, http.MethodPatch, updateURL, &pb.UpdateUserRequest{Email: &newEmail}, nonExpiryToken)
+		resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		user, err := findUser(testUser.ID, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, testUser.Email, user.Email)
+
+		token, ok := emailChangeTokens.Load(newEmail)
+		if !ok {
+			t.Fatalf("no confirmation token notified to %s", newEmail)
+		}
+
+		confirmURL := fmt.Sprintf("http://:%d/v1/users/email/confirm", cfg.HTTPPort)
+		confirmResp := doInvitationRequest(t, http.MethodPost, confirmURL, &pb.ConfirmEmailChangeRequest{Token: token.(string)}, "")
+		confirmResp.Body.Close()
+		assert.Equal(t, http.StatusOK, confirmResp.StatusCode)
+
+		user, err = findUser(testUser.ID, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, newEmail, user.Email)
+		assert.Equal(t, newEmail, user.EmailNormalized)
+
+		confirmResp = doInvitationRequest(t, http.MethodPost, confirmURL, &pb.ConfirmEmailChangeRequest{Token: token.(string)}, "")
+		confirmResp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, confirmResp.StatusCode)
+	})
+}
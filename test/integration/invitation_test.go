@@ -0,0 +1,141 @@
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestInviteUser_Ok checks that an invited user cannot log in until it accepts the invitation with the notified invite token
+func TestInviteUser_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		email := fmt.Sprintf("test%d@test.com", rand.Int())
+
+		// Act
+		resp := doInvitationRequest(t, http.MethodPost, fmt.Sprintf("http://:%d/v1/invitations", cfg.HTTPPort), &pb.InviteUserRequest{Email: email}, nonExpiryToken)
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		var response pb.InviteUserResponse
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading the response while calling %s: %s", resp.Request.URL, err)
+		}
+		if err := protojson.Unmarshal(bodyBytes, &response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+		assert.NotEmpty(t, response.Id)
+		assert.NotEmpty(t, response.UserId)
+
+		loginURL := fmt.Sprintf("http://:%d/v1/users/login", cfg.HTTPPort)
+		loginResp := doInvitationRequest(t, http.MethodPost, loginURL, &pb.LoginUserRequest{Email: email, Password: "test"}, "")
+		loginResp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, loginResp.StatusCode)
+
+		token, ok := invitationTokens.Load(email)
+		if !ok {
+			t.Fatalf("no invite token notified to %s", email)
+		}
+
+		acceptURL := fmt.Sprintf("http://:%d/v1/invitations/accept", cfg.HTTPPort)
+		acceptResp := doInvitationRequest(t, http.MethodPost, acceptURL, &pb.AcceptInviteRequest{Token: token.(string), Password: "test"}, "")
+		acceptResp.Body.Close()
+		assert.Equal(t, http.StatusOK, acceptResp.StatusCode)
+
+		loginResp = doInvitationRequest(t, http.MethodPost, loginURL, &pb.LoginUserRequest{Email: email, Password: "test"}, "")
+		loginResp.Body.Close()
+		assert.Equal(t, http.StatusOK, loginResp.StatusCode)
+
+		acceptResp = doInvitationRequest(t, http.MethodPost, acceptURL, &pb.AcceptInviteRequest{Token: token.(string), Password: "other"}, "")
+		acceptResp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, acceptResp.StatusCode)
+	})
+}
+
+// TestRevokeInvite_Ok checks that a revoked invitation is no longer listed and its invited user is deleted
+func TestRevokeInvite_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		email := fmt.Sprintf("test%d@test.com", rand.Int())
+
+		inviteResp := doInvitationRequest(t, http.MethodPost, fmt.Sprintf("http://:%d/v1/invitations", cfg.HTTPPort), &pb.InviteUserRequest{Email: email}, nonExpiryToken)
+		defer inviteResp.Body.Close()
+
+		var invitation pb.InviteUserResponse
+		bodyBytes, err := io.ReadAll(inviteResp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := protojson.Unmarshal(bodyBytes, &invitation); err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		resp := doInvitationRequest(t, http.MethodDelete, fmt.Sprintf("http://:%d/v1/invitations/%s", cfg.HTTPPort, invitation.Id), nil, nonExpiryToken)
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		getResp := doInvitationRequest(t, http.MethodGet, fmt.Sprintf("http://:%d/v1/invitations", cfg.HTTPPort), nil, nonExpiryToken)
+		defer getResp.Body.Close()
+
+		var invitations pb.GetInvitationsResponse
+		bodyBytes, err = io.ReadAll(getResp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := protojson.Unmarshal(bodyBytes, &invitations); err != nil {
+			t.Fatal(err)
+		}
+		for _, i := range invitations.Invitations {
+			assert.NotEqual(t, invitation.Id, i.Id)
+		}
+
+		_, err = findUser(invitation.UserId, cfg)
+		assert.NotNil(t, err)
+	})
+}
+
+func doInvitationRequest(t *testing.T, method, url string, body proto.Message, authorization string) *http.Response {
+	var b []byte
+	if body != nil {
+		var err error
+		b, err = protojson.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
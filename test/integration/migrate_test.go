@@ -0,0 +1,39 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/app/api"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestMigrate_AlreadyMigrated checks that Migrate succeeds without changes when the database was migrated at startup
+func TestMigrate_AlreadyMigrated(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+
+		// Act
+		err := api.Migrate(context.Background(), cfg)
+
+		// Assert
+		assert.Nil(t, err)
+		if database != "mongo" {
+			return
+		}
+
+		db, err := infrastructure.ConnectMongoDB(context.Background(), cfg.DSN)
+		if err != nil {
+			t.Fatal(err)
+		}
+		applied, err := db.Collection("schema_migrations").CountDocuments(context.Background(), bson.M{})
+		assert.Nil(t, err)
+		assert.NotZero(t, applied)
+		locks, err := db.Collection("schema_migrations_lock").CountDocuments(context.Background(), bson.M{})
+		assert.Nil(t, err)
+		assert.Zero(t, locks)
+	})
+}
@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// TestCreateUser_OutboxEvent checks that creating a user writes its created event to the outbox
+func TestCreateUser_OutboxEvent(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, password := getNewTestUser()
+
+		// Act
+		body := mapUserToCreateUserReq(testUser, password)
+		b, err := protojson.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		url := fmt.Sprintf("http://:%d/v1/users", cfg.HTTPPort)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		var response pb.CreateUserResponse
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading the response while calling %s: %s", resp.Request.URL, err)
+		}
+		if err := protojson.Unmarshal(bodyBytes, &response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+
+		eventTypes, err := findEventTypes(response.Id, cfg)
+		if err != nil {
+			t.Fatalf("unexpected error while finding the outbox events: %s", err)
+		}
+		assert.Equal(t, []entities.EventType{entities.EventTypeUserCreated}, eventTypes)
+	})
+}
+
+func findEventTypes(aggregateID string, cfg config.Config) ([]entities.EventType, error) {
+	var eventTypes []entities.EventType
+
+	switch cfg.Database {
+	case "mongo":
+		db, err := infrastructure.ConnectMongoDB(context.Background(), cfg.DSN)
+		if err != nil {
+			return nil, err
+		}
+
+		cursor, err := db.Collection(entities.EntityNameEvent).Find(context.Background(), bson.M{"aggregate_id": aggregateID})
+		if err != nil {
+			return nil, err
+		}
+
+		var events []entities.Event
+		if err = cursor.All(context.Background(), &events); err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			eventTypes = append(eventTypes, event.Type)
+		}
+		return eventTypes, nil
+
+	case "postgres":
+		db, err := infrastructure.ConnectPostgresDB(context.Background(), cfg.DSN)
+		if err != nil {
+			return nil, err
+		}
+
+		rows, err := db.QueryContext(context.Background(), `SELECT type FROM outbox_events WHERE aggregate_id = $1 ORDER BY seq;`, aggregateID)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var eventType entities.EventType
+			if err = rows.Scan(&eventType); err != nil {
+				return nil, err
+			}
+			eventTypes = append(eventTypes, eventType)
+		}
+		return eventTypes, rows.Err()
+
+	default:
+		return nil, fmt.Errorf("database flag %s not valid", cfg.Database)
+	}
+}
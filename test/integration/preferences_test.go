@@ -0,0 +1,170 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/mongo"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/postgres"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// TestGetPreferences_Defaults checks that the preferences of a user who has not updated any are the defaults
+func TestGetPreferences_Defaults(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, _ := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		resp := doPreferences(t, cfg, http.MethodGet, testUser.ID, "", http.StatusOK)
+
+		// Assert
+		assert.Equal(t, int32(0), resp.Version)
+		assert.Equal(t, "en", resp.Language)
+		assert.Equal(t, "UTC", resp.Timezone)
+		assert.True(t, resp.Notifications.Email)
+		assert.True(t, resp.Notifications.Push)
+		assert.False(t, resp.Notifications.Marketing)
+	})
+}
+
+// TestUpdatePreferences_Ok checks that updated preferences are merged with the defaults and kept across reads
+func TestUpdatePreferences_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, _ := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doPreferences(t, cfg, http.MethodPatch, testUser.ID, `{"version": 0, "language": "es", "timezone": "Europe/Madrid"}`, http.StatusOK)
+
+		// Act
+		doPreferences(t, cfg, http.MethodPatch, testUser.ID, `{"version": 1, "notifications": {"push": false}, "reset_to_default": ["timezone"]}`, http.StatusOK)
+
+		// Assert
+		resp := doPreferences(t, cfg, http.MethodGet, testUser.ID, "", http.StatusOK)
+		assert.Equal(t, int32(2), resp.Version)
+		assert.Equal(t, "es", resp.Language)
+		assert.Equal(t, "UTC", resp.Timezone)
+		assert.True(t, resp.Notifications.Email)
+		assert.False(t, resp.Notifications.Push)
+		assert.NotNil(t, resp.UpdatedAt)
+	})
+}
+
+// TestUpdatePreferences_OutdatedVersion checks that an update based on an outdated version of the preferences is rejected as a conflict
+func TestUpdatePreferences_OutdatedVersion(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, _ := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doPreferences(t, cfg, http.MethodPatch, testUser.ID, `{"version": 0, "language": "es"}`, http.StatusOK)
+
+		// Act
+		doPreferences(t, cfg, http.MethodPatch, testUser.ID, `{"version": 0, "language": "ca"}`, http.StatusConflict)
+
+		// Assert
+		resp := doPreferences(t, cfg, http.MethodGet, testUser.ID, "", http.StatusOK)
+		assert.Equal(t, int32(1), resp.Version)
+		assert.Equal(t, "es", resp.Language)
+	})
+}
+
+// TestPreferencesRepository_StaleVersion checks that the preferences repositories of the databases reject saving preferences
+// on top of a version that is no longer the stored one with a conflict error, which is what concurrent updates run into
+func TestPreferencesRepository_StaleVersion(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, _ := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var repo ports.PreferencesRepository
+		switch database {
+		case "mongo":
+			db, err := infrastructure.ConnectMongoDB(context.Background(), cfg.DSN)
+			if err != nil {
+				t.Fatal(err)
+			}
+			repo = mongo.NewPreferencesRepository(db)
+		case "postgres":
+			db, err := infrastructure.ConnectPostgresDB(context.Background(), cfg.DSN)
+			if err != nil {
+				t.Fatal(err)
+			}
+			repo = postgres.NewPreferencesRepository(db)
+		}
+		doPreferences(t, cfg, http.MethodPatch, testUser.ID, `{"version": 0, "language": "es"}`, http.StatusOK)
+		doPreferences(t, cfg, http.MethodPatch, testUser.ID, `{"version": 1, "language": "ca"}`, http.StatusOK)
+
+		// Act
+		firstErr := repo.Save(context.Background(), entities.Preferences{UserID: testUser.ID, Version: 1, UpdatedAt: time.Now().UTC()})
+		staleErr := repo.Save(context.Background(), entities.Preferences{UserID: testUser.ID, Version: 2, UpdatedAt: time.Now().UTC()})
+
+		// Assert
+		assert.ErrorIs(t, firstErr, entities.ConflictErr)
+		assert.ErrorIs(t, staleErr, entities.ConflictErr)
+		resp := doPreferences(t, cfg, http.MethodGet, testUser.ID, "", http.StatusOK)
+		assert.Equal(t, int32(2), resp.Version)
+		assert.Equal(t, "ca", resp.Language)
+	})
+}
+
+// doPreferences calls the preferences endpoint of the user, failing when the status is not the expected one,
+// and returns the parsed response when it succeeds
+func doPreferences(t *testing.T, cfg config.Config, method, userID, body string, expectedStatus int) *pb.PreferencesResponse {
+	url := fmt.Sprintf("http://:%d/v1/users/%s/preferences", cfg.HTTPPort, userID)
+	req, err := http.NewRequest(method, url, bytes.NewReader([]byte(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", nonExpiryToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if want, got := expectedStatus, resp.StatusCode; want != got {
+		t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+	}
+
+	var response pb.PreferencesResponse
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error reading the response while calling %s: %s", resp.Request.URL, err)
+	}
+	if expectedStatus == http.StatusOK {
+		if err := protojson.Unmarshal(bodyBytes, &response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+	}
+	return &response
+}
@@ -0,0 +1,88 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExportUserData_Ok checks that ExportUserData endpoint returns the expected response when everything goes as expected
+func TestExportUserData_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, _ := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/users/%s/data", cfg.HTTPPort, testUser.ID)
+
+		req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+		assert.Equal(t, "application/zip", resp.Header.Get("Content-Type"))
+	})
+}
+
+// TestEraseUser_Ok checks that EraseUser endpoint returns the expected response when everything goes as expected
+func TestEraseUser_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, _ := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/users/%s/erase", cfg.HTTPPort, testUser.ID)
+
+		req, err := http.NewRequest(http.MethodPost, url, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+		erasedUser, err := findUser(testUser.ID, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, fmt.Sprintf("%s@erased.invalid", testUser.ID), erasedUser.Email)
+		assert.Empty(t, erasedUser.Name)
+		assert.Empty(t, erasedUser.Surnames)
+	})
+}
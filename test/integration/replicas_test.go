@@ -0,0 +1,53 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/config"
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/sergicanet9/scv-go-tools/v4/api/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPostgresReplicas_Ok checks that the users are served when read replicas are configured, and that the health endpoint reports them,
+// using the primary as its own replica and an unreachable one that gets ejected
+func TestPostgresReplicas_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		unreachable := fmt.Sprintf("postgres://%s:%s@localhost:1/%s?sslmode=disable", postgresUser, postgresPassword, dbName)
+		var primary string
+		cfg := New(t, database, func(c *config.Config) {
+			primary = c.DSN
+			c.PostgresReplicas.DSNs = []string{c.DSN, unreachable}
+			c.PostgresReplicas.CheckInterval = utils.Duration{Duration: time.Second}
+			c.PostgresReplicas.MaxLag = utils.Duration{Duration: 10 * time.Second}
+		})
+		testUser, _ := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		var user pb.GetUserResponse
+		doJSONRequest(t, cfg, http.MethodGet, fmt.Sprintf("/v1/users/%s", testUser.ID), nil, &user)
+
+		var health pb.HealthCheckResponse
+		doJSONRequest(t, cfg, http.MethodGet, "/v1/health", nil, &health)
+
+		// Assert
+		assert.Equal(t, testUser.Email, user.Email)
+		if assert.Len(t, health.Replicas, 2) {
+			assert.True(t, strings.Contains(primary, health.Replicas[0].Name))
+			assert.True(t, health.Replicas[0].Healthy)
+			assert.Equal(t, int64(0), health.Replicas[0].LagMillis)
+			assert.Equal(t, "localhost:1", health.Replicas[1].Name)
+			assert.False(t, health.Replicas[1].Healthy)
+			assert.NotEmpty(t, health.Replicas[1].Error)
+		}
+	}, "postgres")
+}
@@ -0,0 +1,38 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/mongo"
+	"github.com/sergicanet9/go-hexagonal-api/infrastructure/postgres"
+	"github.com/sergicanet9/go-hexagonal-api/test/repositorytest"
+	"github.com/sergicanet9/scv-go-tools/v4/infrastructure"
+)
+
+// TestUserRepository_Suite checks that the user repositories of the databases pass the conformance test suite of the user repositories
+func TestUserRepository_Suite(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// the API gets started so that the database is migrated
+		cfg := New(t, database)
+
+		var repo ports.UserRepository
+		switch database {
+		case "mongo":
+			db, err := infrastructure.ConnectMongoDB(context.Background(), cfg.DSN)
+			if err != nil {
+				t.Fatal(err)
+			}
+			repo = mongo.NewUserRepository(db)
+		case "postgres":
+			db, err := infrastructure.ConnectPostgresDB(context.Background(), cfg.DSN)
+			if err != nil {
+				t.Fatal(err)
+			}
+			repo = postgres.NewUserRepository(db, nil)
+		}
+
+		repositorytest.UserRepository(t, repo)
+	})
+}
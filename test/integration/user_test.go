@@ -1,13 +1,16 @@
 package integration
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
 	"io"
 	"math/rand"
+	"mime/multipart"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -79,6 +82,62 @@ func TestLoginUser_Ok(t *testing.T) {
 	})
 }
 
+// TestLoginUser_CaseInsensitiveEmail checks that Login endpoint authenticates the user regardless of the case of the email
+func TestLoginUser_CaseInsensitiveEmail(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, password := getNewTestUser()
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		body := &pb.LoginUserRequest{
+			Email:    strings.ToUpper(testUser.Email),
+			Password: password,
+		}
+		b, err := protojson.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		url := fmt.Sprintf("http://:%d/v1/users/login", cfg.HTTPPort)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		var response pb.LoginUserResponse
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading the response while calling %s: %s", resp.Request.URL, err)
+		}
+		if err := protojson.Unmarshal(bodyBytes, &response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+
+		assert.Equal(t, testUser.ID, response.User.Id)
+		assert.Equal(t, testUser.Email, response.User.Email)
+	})
+}
+
 // TestCreateUser checks that CreateUser endpoint returns the expected response when everything goes as expected
 func TestCreateUser_Ok(t *testing.T) {
 	Databases(t, func(t *testing.T, database string) {
@@ -138,6 +197,61 @@ func TestCreateUser_Ok(t *testing.T) {
 	})
 }
 
+// TestCreateUser_IdempotentRetry checks that retrying Create endpoint with the same idempotency key returns the original response without creating a second user
+func TestCreateUser_IdempotentRetry(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, password := getNewTestUser()
+		idempotencyKey := fmt.Sprintf("key-%d", rand.Int())
+
+		body := mapUserToCreateUserReq(testUser, password)
+		b, err := protojson.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		url := fmt.Sprintf("http://:%d/v1/users", cfg.HTTPPort)
+
+		// Act
+		var ids []string
+		for i := 0; i < 2; i++ {
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req.Header.Set("Content-Type", contentType)
+			req.Header.Set("Idempotency-Key", idempotencyKey)
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer resp.Body.Close()
+
+			if want, got := http.StatusOK, resp.StatusCode; want != got {
+				t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+			}
+
+			var response pb.CreateUserResponse
+			bodyBytes, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unexpected error reading the response while calling %s: %s", resp.Request.URL, err)
+			}
+			if err := protojson.Unmarshal(bodyBytes, &response); err != nil {
+				t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+			}
+			ids = append(ids, response.Id)
+		}
+
+		// Assert
+		assert.NotEmpty(t, ids[0])
+		assert.Equal(t, ids[0], ids[1])
+	})
+}
+
 // TestCreateManyUsers_Ok checks that CreateManyUsers endpoint returns the expected response when everything goes as expected
 func TestCreateManyUsers_Ok(t *testing.T) {
 	Databases(t, func(t *testing.T, database string) {
@@ -452,6 +566,352 @@ func TestDeleteUser_Ok(t *testing.T) {
 	})
 }
 
+// TestUpdateManyUsers_Ok checks that UpdateManyUsers endpoint returns the expected response when everything goes as expected
+func TestUpdateManyUsers_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		user1, _ := getNewTestUser()
+		user2, _ := getNewTestUser()
+		for _, u := range []*entities.User{&user1, &user2} {
+			if err := insertUser(u, cfg); err != nil {
+				t.Fatal(err)
+			}
+		}
+		newName := "modified"
+
+		// Act
+		body := &pb.UpdateManyUsersRequest{
+			Users: []*pb.UpdateUserRequest{
+				{Id: user1.ID, Name: &newName},
+				{Id: user2.ID, Name: &newName},
+			},
+		}
+		b, err := protojson.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		url := fmt.Sprintf("http://:%d/v1/users/many", cfg.HTTPPort)
+
+		req, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		var response pb.BulkUsersResponse
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading the response while calling %s: %s", resp.Request.URL, err)
+		}
+		if err := protojson.Unmarshal(bodyBytes, &response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+
+		assert.Equal(t, 2, len(response.Results))
+		for _, result := range response.Results {
+			assert.Equal(t, "OK", result.Code)
+			updatedUser, err := findUser(result.Id, cfg)
+			if err != nil {
+				t.Fatalf("unexpected error while finding the updated user: %s", err)
+			}
+			assert.Equal(t, newName, updatedUser.Name)
+		}
+	})
+}
+
+// TestDeleteManyUsers_Ok checks that DeleteManyUsers endpoint returns the expected response when everything goes as expected
+func TestDeleteManyUsers_Ok(t *testing.T) {
+	notFoundError := map[string]error{"mongo": mongo.ErrNoDocuments, "postgres": sql.ErrNoRows}
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		user1, _ := getNewTestUser()
+		user2, _ := getNewTestUser()
+		for _, u := range []*entities.User{&user1, &user2} {
+			if err := insertUser(u, cfg); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Act
+		body := &pb.DeleteManyUsersRequest{
+			Ids: []string{user1.ID, user2.ID},
+		}
+		b, err := protojson.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		url := fmt.Sprintf("http://:%d/v1/users/many/delete", cfg.HTTPPort)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+		for _, ID := range []string{user1.ID, user2.ID} {
+			_, err = findUser(ID, cfg)
+			assert.Equal(t, notFoundError[database], err)
+		}
+	})
+}
+
+// TestImportUsers_Ok checks that ImportUsers endpoint imports the rows of a CSV file and reports the invalid ones
+func TestImportUsers_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		user1, password1 := getNewTestUser()
+		user2, password2 := getNewTestUser()
+		csv := fmt.Sprintf("name,email,password\n%s,%s,%s\n%s,%s,%s\n%s,,%s\n",
+			user1.Name, user1.Email, password1, user2.Name, user2.Email, password2, user2.Name, password2)
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("file", "users.csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = part.Write([]byte(csv)); err != nil {
+			t.Fatal(err)
+		}
+		if err = writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/users/import", cfg.HTTPPort)
+
+		req, err := http.NewRequest(http.MethodPost, url, &body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		var response pb.ImportUsersResponse
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading the response while calling %s: %s", resp.Request.URL, err)
+		}
+		if err := protojson.Unmarshal(bodyBytes, &response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+
+		assert.Equal(t, int32(3), response.Total)
+		assert.Equal(t, int32(2), response.Imported)
+		assert.False(t, response.DryRun)
+		assert.Len(t, response.Errors, 1)
+		assert.Equal(t, int32(2), response.Errors[0].Index)
+		assert.Equal(t, "InvalidArgument", response.Errors[0].Code)
+	})
+}
+
+// TestExportUsers_Ok checks that ExportUsers endpoint downloads the selected columns of the users without their password hashes
+func TestExportUsers_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		user1, _ := getNewTestUser()
+		user2, _ := getNewTestUser()
+		for _, u := range []*entities.User{&user1, &user2} {
+			if err := insertUser(u, cfg); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/users/export?format=ndjson&columns=id,email", cfg.HTTPPort)
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading the response while calling %s: %s", resp.Request.URL, err)
+		}
+
+		assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+		for _, u := range []entities.User{user1, user2} {
+			assert.Contains(t, string(bodyBytes), fmt.Sprintf(`{"id":%q,"email":%q}`, u.ID, u.Email))
+		}
+		assert.NotContains(t, string(bodyBytes), user1.PasswordHash)
+	})
+}
+
+// TestWatchUsers_Ok checks that WatchUsers endpoint sends the changes of the users as server-sent events
+func TestWatchUsers_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, _ := getNewTestUser()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		url := fmt.Sprintf("http://:%d/v1/users/watch", cfg.HTTPPort)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		// Act
+		// the headers are sent before the change stream or listener gets opened, so it is given some time to
+		time.Sleep(time.Second)
+		if err := insertUser(&testUser, cfg); err != nil {
+			t.Fatal(err)
+		}
+
+		// Assert
+		var event, data string
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() && !strings.Contains(data, testUser.ID) {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+
+		var change pb.UserChange
+		if err := protojson.Unmarshal([]byte(data), &change); err != nil {
+			t.Fatalf("unexpected error parsing the event while calling %s: %s", resp.Request.URL, err)
+		}
+
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "created", event)
+		assert.Equal(t, testUser.ID, change.UserId)
+		assert.Equal(t, testUser.Email, change.User.Email)
+		assert.NotEmpty(t, change.ResumeToken)
+	})
+}
+
+// TestSearchUsers_Ok checks that SearchUsers endpoint returns the expected response when everything goes as expected
+func TestSearchUsers_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+		testUser, _ := getNewTestUser()
+		testUser.Surnames = fmt.Sprintf("searchable%d", rand.Int())
+		err := insertUser(&testUser, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/users/search?query=%s", cfg.HTTPPort, testUser.Surnames)
+
+		req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		var response pb.SearchUsersResponse
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading the response while calling %s: %s", resp.Request.URL, err)
+		}
+		if err := protojson.Unmarshal(bodyBytes, &response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+
+		if assert.NotEmpty(t, response.Users) {
+			assert.Equal(t, testUser.ID, response.Users[0].Id)
+			assert.Equal(t, testUser.Surnames, response.Users[0].Surnames)
+		}
+	})
+}
+
 // TestGetUserClaims_Ok checks that GetUserClaims endpoint returns the expected response when everything goes as expected
 func TestGetUserClaims_Ok(t *testing.T) {
 	Databases(t, func(t *testing.T, database string) {
@@ -509,6 +969,7 @@ func getNewTestUser() (u entities.User, pwd string) {
 
 func insertUser(u *entities.User, cfg config.Config) error {
 	now := time.Now().UTC()
+	u.EmailNormalized = entities.NormalizeEmail(u.Email)
 
 	switch cfg.Database {
 	case "mongo":
@@ -529,16 +990,16 @@ func insertUser(u *entities.User, cfg config.Config) error {
 		}
 
 		q := `
-		INSERT INTO users (name, surnames, email, password_hash, claim_ids, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
-			RETURNING id, name, surnames, email, password_hash, claim_ids, created_at, updated_at;
+		INSERT INTO users (name, surnames, email, email_normalized, password_hash, claim_ids, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id, name, surnames, email, email_normalized, password_hash, claim_ids, created_at, updated_at;
 		`
 
 		row := db.QueryRowContext(
-			context.Background(), q, u.Name, u.Surnames, u.Email, u.PasswordHash, pq.Array(u.ClaimIDs), now, now,
+			context.Background(), q, u.Name, u.Surnames, u.Email, u.EmailNormalized, u.PasswordHash, pq.Array(u.ClaimIDs), now, now,
 		)
 
-		err = row.Scan(&u.ID, &u.Name, &u.Surnames, &u.Email, &u.PasswordHash, pq.Array(&u.ClaimIDs), &u.CreatedAt, &u.UpdatedAt)
+		err = row.Scan(&u.ID, &u.Name, &u.Surnames, &u.Email, &u.EmailNormalized, &u.PasswordHash, pq.Array(&u.ClaimIDs), &u.CreatedAt, &u.UpdatedAt)
 		return err
 
 	default:
@@ -570,14 +1031,14 @@ func findUser(ID string, cfg config.Config) (entities.User, error) {
 		}
 
 		q := `
-		SELECT id, name, surnames, email, password_hash, claim_ids, created_at, updated_at
+		SELECT id, name, surnames, email, email_normalized, password_hash, claim_ids, avatar_url, created_at, updated_at
 			FROM users WHERE id = $1;
 		`
 
 		row := db.QueryRowContext(context.Background(), q, ID)
 
 		var u entities.User
-		err = row.Scan(&u.ID, &u.Name, &u.Surnames, &u.Email, &u.PasswordHash, pq.Array(&u.ClaimIDs), &u.CreatedAt, &u.UpdatedAt)
+		err = row.Scan(&u.ID, &u.Name, &u.Surnames, &u.Email, &u.EmailNormalized, &u.PasswordHash, pq.Array(&u.ClaimIDs), &u.AvatarURL, &u.CreatedAt, &u.UpdatedAt)
 		return u, err
 
 	default:
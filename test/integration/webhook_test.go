@@ -0,0 +1,123 @@
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sergicanet9/go-hexagonal-api/proto/v1/gen/go/pb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// TestCreateSubscription_Ok checks that CreateSubscription endpoint returns the ID and the generated secret, and the subscription is then retrievable without it
+func TestCreateSubscription_Ok(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+
+		b, err := protojson.Marshal(&pb.CreateSubscriptionRequest{
+			Url:        "https://test.com/hook",
+			EventTypes: []string{"user.created"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/webhooks/subscriptions", cfg.HTTPPort)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", nonExpiryToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusOK, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+
+		var response pb.CreateSubscriptionResponse
+		bodyBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading the response while calling %s: %s", resp.Request.URL, err)
+		}
+		if err := protojson.Unmarshal(bodyBytes, &response); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", resp.Request.URL, err)
+		}
+
+		assert.NotEmpty(t, response.Id)
+		assert.Len(t, response.Secret, 64)
+
+		getURL := fmt.Sprintf("http://:%d/v1/webhooks/subscriptions/%s", cfg.HTTPPort, response.Id)
+		getReq, err := http.NewRequest(http.MethodGet, getURL, http.NoBody)
+		if err != nil {
+			t.Fatal(err)
+		}
+		getReq.Header.Set("Authorization", nonExpiryToken)
+
+		getResp, err := http.DefaultClient.Do(getReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer getResp.Body.Close()
+
+		if want, got := http.StatusOK, getResp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", getResp.Request.URL, want, got)
+		}
+
+		var subscription pb.Subscription
+		bodyBytes, err = io.ReadAll(getResp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading the response while calling %s: %s", getResp.Request.URL, err)
+		}
+		if err := protojson.Unmarshal(bodyBytes, &subscription); err != nil {
+			t.Fatalf("unexpected error parsing the response while calling %s: %s", getResp.Request.URL, err)
+		}
+
+		assert.Equal(t, "https://test.com/hook", subscription.Url)
+		assert.Equal(t, []string{"user.created"}, subscription.EventTypes)
+	})
+}
+
+// TestCreateSubscription_Unauthorized checks that CreateSubscription endpoint returns an unauthorized error when no JWT is received
+func TestCreateSubscription_Unauthorized(t *testing.T) {
+	Databases(t, func(t *testing.T, database string) {
+		// Arrange
+		cfg := New(t, database)
+
+		// Act
+		url := fmt.Sprintf("http://:%d/v1/webhooks/subscriptions", cfg.HTTPPort)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader([]byte(`{"url":"https://test.com/hook"}`)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", contentType)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		// Assert
+		if want, got := http.StatusUnauthorized, resp.StatusCode; want != got {
+			t.Fatalf("unexpected http status code while calling %s: want=%d but got=%d", resp.Request.URL, want, got)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entities "github.com/sergicanet9/go-hexagonal-api/core/entities"
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/sergicanet9/go-hexagonal-api/core/models"
+)
+
+// AuditRepository is an autogenerated mock type for the AuditRepository type
+type AuditRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, event
+func (_m *AuditRepository) Create(ctx context.Context, event entities.AuditEvent) (string, error) {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.AuditEvent) (string, error)); ok {
+		return rf(ctx, event)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entities.AuditEvent) string); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entities.AuditEvent) error); ok {
+		r1 = rf(ctx, event)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, filter, offset, limit
+func (_m *AuditRepository) List(ctx context.Context, filter models.AuditFilter, offset int, limit int) ([]entities.AuditEvent, error) {
+	ret := _m.Called(ctx, filter, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []entities.AuditEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.AuditFilter, int, int) ([]entities.AuditEvent, error)); ok {
+		return rf(ctx, filter, offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.AuditFilter, int, int) []entities.AuditEvent); ok {
+		r0 = rf(ctx, filter, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.AuditEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.AuditFilter, int, int) error); ok {
+		r1 = rf(ctx, filter, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedactTarget provides a mock function with given fields: ctx, targetID
+func (_m *AuditRepository) RedactTarget(ctx context.Context, targetID string) error {
+	ret := _m.Called(ctx, targetID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedactTarget")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, targetID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAuditRepository creates a new instance of AuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditRepository {
+	mock := &AuditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
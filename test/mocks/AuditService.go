@@ -0,0 +1,123 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/sergicanet9/go-hexagonal-api/core/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditService is an autogenerated mock type for the AuditService type
+type AuditService struct {
+	mock.Mock
+}
+
+// EraseUserData provides a mock function with given fields: ctx, userID
+func (_m *AuditService) EraseUserData(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EraseUserData")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportUserData provides a mock function with given fields: ctx, userID
+func (_m *AuditService) ExportUserData(ctx context.Context, userID string) (interface{}, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportUserData")
+	}
+
+	var r0 interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (interface{}, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) interface{}); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, req
+func (_m *AuditService) List(ctx context.Context, req models.ListAuditEventsReq) (models.ListAuditEventsResp, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 models.ListAuditEventsResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ListAuditEventsReq) (models.ListAuditEventsResp, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.ListAuditEventsReq) models.ListAuditEventsResp); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(models.ListAuditEventsResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.ListAuditEventsReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Name provides a mock function with no fields
+func (_m *AuditService) Name() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Name")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// NewAuditService creates a new instance of AuditService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditService {
+	mock := &AuditService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
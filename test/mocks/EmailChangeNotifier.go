@@ -0,0 +1,65 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entities "github.com/sergicanet9/go-hexagonal-api/core/entities"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailChangeNotifier is an autogenerated mock type for the EmailChangeNotifier type
+type EmailChangeNotifier struct {
+	mock.Mock
+}
+
+// NotifyConfirmation provides a mock function with given fields: ctx, change, token
+func (_m *EmailChangeNotifier) NotifyConfirmation(ctx context.Context, change entities.EmailChange, token string) error {
+	ret := _m.Called(ctx, change, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NotifyConfirmation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.EmailChange, string) error); ok {
+		r0 = rf(ctx, change, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NotifyRequest provides a mock function with given fields: ctx, change
+func (_m *EmailChangeNotifier) NotifyRequest(ctx context.Context, change entities.EmailChange) error {
+	ret := _m.Called(ctx, change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NotifyRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.EmailChange) error); ok {
+		r0 = rf(ctx, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewEmailChangeNotifier creates a new instance of EmailChangeNotifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailChangeNotifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailChangeNotifier {
+	mock := &EmailChangeNotifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
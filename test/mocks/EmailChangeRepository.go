@@ -0,0 +1,151 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entities "github.com/sergicanet9/go-hexagonal-api/core/entities"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailChangeRepository is an autogenerated mock type for the EmailChangeRepository type
+type EmailChangeRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, change
+func (_m *EmailChangeRepository) Create(ctx context.Context, change entities.EmailChange) (string, error) {
+	ret := _m.Called(ctx, change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.EmailChange) (string, error)); ok {
+		return rf(ctx, change)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entities.EmailChange) string); ok {
+		r0 = rf(ctx, change)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entities.EmailChange) error); ok {
+		r1 = rf(ctx, change)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, ID
+func (_m *EmailChangeRepository) Delete(ctx context.Context, ID string) error {
+	ret := _m.Called(ctx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteByUserID provides a mock function with given fields: ctx, userID
+func (_m *EmailChangeRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteByUserID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByID provides a mock function with given fields: ctx, ID
+func (_m *EmailChangeRepository) GetByID(ctx context.Context, ID string) (entities.EmailChange, error) {
+	ret := _m.Called(ctx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 entities.EmailChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entities.EmailChange, error)); ok {
+		return rf(ctx, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entities.EmailChange); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		r0 = ret.Get(0).(entities.EmailChange)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByUserID provides a mock function with given fields: ctx, userID
+func (_m *EmailChangeRepository) GetByUserID(ctx context.Context, userID string) ([]entities.EmailChange, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUserID")
+	}
+
+	var r0 []entities.EmailChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entities.EmailChange, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entities.EmailChange); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.EmailChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewEmailChangeRepository creates a new instance of EmailChangeRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailChangeRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailChangeRepository {
+	mock := &EmailChangeRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
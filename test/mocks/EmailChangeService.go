@@ -0,0 +1,141 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/sergicanet9/go-hexagonal-api/core/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailChangeService is an autogenerated mock type for the EmailChangeService type
+type EmailChangeService struct {
+	mock.Mock
+}
+
+// Confirm provides a mock function with given fields: ctx, req
+func (_m *EmailChangeService) Confirm(ctx context.Context, req models.ConfirmEmailChangeReq) (models.ConfirmEmailChangeResp, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Confirm")
+	}
+
+	var r0 models.ConfirmEmailChangeResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ConfirmEmailChangeReq) (models.ConfirmEmailChangeResp, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.ConfirmEmailChangeReq) models.ConfirmEmailChangeResp); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(models.ConfirmEmailChangeResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.ConfirmEmailChangeReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EraseUserData provides a mock function with given fields: ctx, userID
+func (_m *EmailChangeService) EraseUserData(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EraseUserData")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportUserData provides a mock function with given fields: ctx, userID
+func (_m *EmailChangeService) ExportUserData(ctx context.Context, userID string) (interface{}, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportUserData")
+	}
+
+	var r0 interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (interface{}, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) interface{}); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Name provides a mock function with no fields
+func (_m *EmailChangeService) Name() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Name")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// Request provides a mock function with given fields: ctx, userID, email
+func (_m *EmailChangeService) Request(ctx context.Context, userID string, email string) error {
+	ret := _m.Called(ctx, userID, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Request")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewEmailChangeService creates a new instance of EmailChangeService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailChangeService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailChangeService {
+	mock := &EmailChangeService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,108 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IdempotencyService is an autogenerated mock type for the IdempotencyService type
+type IdempotencyService struct {
+	mock.Mock
+}
+
+// Release provides a mock function with given fields: ctx, subject, key, token
+func (_m *IdempotencyService) Release(ctx context.Context, subject string, key string, token string) error {
+	ret := _m.Called(ctx, subject, key, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, subject, key, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reserve provides a mock function with given fields: ctx, subject, key, requestHash
+func (_m *IdempotencyService) Reserve(ctx context.Context, subject string, key string, requestHash string) (string, []byte, bool, error) {
+	ret := _m.Called(ctx, subject, key, requestHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reserve")
+	}
+
+	var r0 string
+	var r1 []byte
+	var r2 bool
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (string, []byte, bool, error)); ok {
+		return rf(ctx, subject, key, requestHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(ctx, subject, key, requestHash)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) []byte); ok {
+		r1 = rf(ctx, subject, key, requestHash)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, string) bool); ok {
+		r2 = rf(ctx, subject, key, requestHash)
+	} else {
+		r2 = ret.Get(2).(bool)
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, string, string, string) error); ok {
+		r3 = rf(ctx, subject, key, requestHash)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// Store provides a mock function with given fields: ctx, subject, key, token, response
+func (_m *IdempotencyService) Store(ctx context.Context, subject string, key string, token string, response []byte) error {
+	ret := _m.Called(ctx, subject, key, token, response)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Store")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []byte) error); ok {
+		r0 = rf(ctx, subject, key, token, response)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIdempotencyService creates a new instance of IdempotencyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyService {
+	mock := &IdempotencyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
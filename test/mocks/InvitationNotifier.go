@@ -0,0 +1,47 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entities "github.com/sergicanet9/go-hexagonal-api/core/entities"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// InvitationNotifier is an autogenerated mock type for the InvitationNotifier type
+type InvitationNotifier struct {
+	mock.Mock
+}
+
+// Notify provides a mock function with given fields: ctx, invitation, token
+func (_m *InvitationNotifier) Notify(ctx context.Context, invitation entities.Invitation, token string) error {
+	ret := _m.Called(ctx, invitation, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Notify")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Invitation, string) error); ok {
+		r0 = rf(ctx, invitation, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewInvitationNotifier creates a new instance of InvitationNotifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewInvitationNotifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *InvitationNotifier {
+	mock := &InvitationNotifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,239 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entities "github.com/sergicanet9/go-hexagonal-api/core/entities"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OutboxRepository is an autogenerated mock type for the OutboxRepository type
+type OutboxRepository struct {
+	mock.Mock
+}
+
+// Add provides a mock function with given fields: ctx, event
+func (_m *OutboxRepository) Add(ctx context.Context, event entities.Event) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Add")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Event) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Claim provides a mock function with given fields: ctx, now, leaseUntil, limit
+func (_m *OutboxRepository) Claim(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]entities.Event, error) {
+	ret := _m.Called(ctx, now, leaseUntil, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Claim")
+	}
+
+	var r0 []entities.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) ([]entities.Event, error)); ok {
+		return rf(ctx, now, leaseUntil, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) []entities.Event); ok {
+		r0 = rf(ctx, now, leaseUntil, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, int) error); ok {
+		r1 = rf(ctx, now, leaseUntil, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeletePublished provides a mock function with given fields: ctx, before
+func (_m *OutboxRepository) DeletePublished(ctx context.Context, before time.Time) (int, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePublished")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByAggregateID provides a mock function with given fields: ctx, aggregateID
+func (_m *OutboxRepository) GetByAggregateID(ctx context.Context, aggregateID string) ([]entities.Event, error) {
+	ret := _m.Called(ctx, aggregateID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByAggregateID")
+	}
+
+	var r0 []entities.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entities.Event, error)); ok {
+		return rf(ctx, aggregateID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entities.Event); ok {
+		r0 = rf(ctx, aggregateID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, aggregateID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkDead provides a mock function with given fields: ctx, ID, cause
+func (_m *OutboxRepository) MarkDead(ctx context.Context, ID string, cause error) error {
+	ret := _m.Called(ctx, ID, cause)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkDead")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, error) error); ok {
+		r0 = rf(ctx, ID, cause)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkFailed provides a mock function with given fields: ctx, ID, cause, nextAttemptAt
+func (_m *OutboxRepository) MarkFailed(ctx context.Context, ID string, cause error, nextAttemptAt time.Time) error {
+	ret := _m.Called(ctx, ID, cause, nextAttemptAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkFailed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, error, time.Time) error); ok {
+		r0 = rf(ctx, ID, cause, nextAttemptAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkPublished provides a mock function with given fields: ctx, ID
+func (_m *OutboxRepository) MarkPublished(ctx context.Context, ID string) error {
+	ret := _m.Called(ctx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkPublished")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Pending provides a mock function with given fields: ctx, limit
+func (_m *OutboxRepository) Pending(ctx context.Context, limit int) ([]entities.Event, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pending")
+	}
+
+	var r0 []entities.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]entities.Event, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []entities.Event); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedactPayloads provides a mock function with given fields: ctx, aggregateID
+func (_m *OutboxRepository) RedactPayloads(ctx context.Context, aggregateID string) error {
+	ret := _m.Called(ctx, aggregateID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedactPayloads")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, aggregateID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewOutboxRepository creates a new instance of OutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OutboxRepository {
+	mock := &OutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,93 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entities "github.com/sergicanet9/go-hexagonal-api/core/entities"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PreferencesRepository is an autogenerated mock type for the PreferencesRepository type
+type PreferencesRepository struct {
+	mock.Mock
+}
+
+// DeleteByUserID provides a mock function with given fields: ctx, userID
+func (_m *PreferencesRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteByUserID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByUserID provides a mock function with given fields: ctx, userID
+func (_m *PreferencesRepository) GetByUserID(ctx context.Context, userID string) (entities.Preferences, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUserID")
+	}
+
+	var r0 entities.Preferences
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entities.Preferences, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entities.Preferences); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(entities.Preferences)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: ctx, preferences
+func (_m *PreferencesRepository) Save(ctx context.Context, preferences entities.Preferences) error {
+	ret := _m.Called(ctx, preferences)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Preferences) error); ok {
+		r0 = rf(ctx, preferences)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPreferencesRepository creates a new instance of PreferencesRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPreferencesRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PreferencesRepository {
+	mock := &PreferencesRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,85 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/sergicanet9/go-hexagonal-api/core/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PrivacyService is an autogenerated mock type for the PrivacyService type
+type PrivacyService struct {
+	mock.Mock
+}
+
+// EraseUser provides a mock function with given fields: ctx, userID, erasedBy
+func (_m *PrivacyService) EraseUser(ctx context.Context, userID string, erasedBy string) (models.EraseUserResp, error) {
+	ret := _m.Called(ctx, userID, erasedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EraseUser")
+	}
+
+	var r0 models.EraseUserResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (models.EraseUserResp, error)); ok {
+		return rf(ctx, userID, erasedBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) models.EraseUserResp); ok {
+		r0 = rf(ctx, userID, erasedBy)
+	} else {
+		r0 = ret.Get(0).(models.EraseUserResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, erasedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportUserData provides a mock function with given fields: ctx, userID
+func (_m *PrivacyService) ExportUserData(ctx context.Context, userID string) (models.UserDataArchive, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportUserData")
+	}
+
+	var r0 models.UserDataArchive
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.UserDataArchive, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.UserDataArchive); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(models.UserDataArchive)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPrivacyService creates a new instance of PrivacyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPrivacyService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PrivacyService {
+	mock := &PrivacyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
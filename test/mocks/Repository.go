@@ -0,0 +1,218 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository[T interface{}, F interface{}] struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, entity
+func (_m *Repository[T, F]) Create(ctx context.Context, entity T) (string, error) {
+	ret := _m.Called(ctx, entity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, T) (string, error)); ok {
+		return rf(ctx, entity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, T) string); ok {
+		r0 = rf(ctx, entity)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, T) error); ok {
+		r1 = rf(ctx, entity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateMany provides a mock function with given fields: ctx, entities
+func (_m *Repository[T, F]) CreateMany(ctx context.Context, entities []T) ([]string, error) {
+	ret := _m.Called(ctx, entities)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateMany")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []T) ([]string, error)); ok {
+		return rf(ctx, entities)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []T) []string); ok {
+		r0 = rf(ctx, entities)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []T) error); ok {
+		r1 = rf(ctx, entities)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, ID
+func (_m *Repository[T, F]) Delete(ctx context.Context, ID string) error {
+	ret := _m.Called(ctx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteMany provides a mock function with given fields: ctx, IDs
+func (_m *Repository[T, F]) DeleteMany(ctx context.Context, IDs []string) error {
+	ret := _m.Called(ctx, IDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMany")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = rf(ctx, IDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, filter, skip, take
+func (_m *Repository[T, F]) Get(ctx context.Context, filter F, skip *int, take *int) ([]T, error) {
+	ret := _m.Called(ctx, filter, skip, take)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 []T
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, F, *int, *int) ([]T, error)); ok {
+		return rf(ctx, filter, skip, take)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, F, *int, *int) []T); ok {
+		r0 = rf(ctx, filter, skip, take)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]T)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, F, *int, *int) error); ok {
+		r1 = rf(ctx, filter, skip, take)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, ID
+func (_m *Repository[T, F]) GetByID(ctx context.Context, ID string) (T, error) {
+	ret := _m.Called(ctx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 T
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (T, error)); ok {
+		return rf(ctx, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) T); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(T)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, ID, entity
+func (_m *Repository[T, F]) Update(ctx context.Context, ID string, entity T) error {
+	ret := _m.Called(ctx, ID, entity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, T) error); ok {
+		r0 = rf(ctx, ID, entity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateMany provides a mock function with given fields: ctx, IDs, entities
+func (_m *Repository[T, F]) UpdateMany(ctx context.Context, IDs []string, entities []T) error {
+	ret := _m.Called(ctx, IDs, entities)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMany")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, []T) error); ok {
+		r0 = rf(ctx, IDs, entities)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRepository[T interface{}, F interface{}](t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Repository[T, F] {
+	mock := &Repository[T, F]{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entities "github.com/sergicanet9/go-hexagonal-api/core/entities"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TombstoneRepository is an autogenerated mock type for the TombstoneRepository type
+type TombstoneRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, tombstone
+func (_m *TombstoneRepository) Create(ctx context.Context, tombstone entities.Tombstone) (string, error) {
+	ret := _m.Called(ctx, tombstone)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Tombstone) (string, error)); ok {
+		return rf(ctx, tombstone)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Tombstone) string); ok {
+		r0 = rf(ctx, tombstone)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entities.Tombstone) error); ok {
+		r1 = rf(ctx, tombstone)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTombstoneRepository creates a new instance of TombstoneRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTombstoneRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TombstoneRepository {
+	mock := &TombstoneRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
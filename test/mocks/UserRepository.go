@@ -5,7 +5,10 @@ package mocks
 import (
 	context "context"
 
+	entities "github.com/sergicanet9/go-hexagonal-api/core/entities"
 	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/sergicanet9/go-hexagonal-api/core/models"
 )
 
 // UserRepository is an autogenerated mock type for the UserRepository type
@@ -14,7 +17,7 @@ type UserRepository struct {
 }
 
 // Create provides a mock function with given fields: ctx, entity
-func (_m *UserRepository) Create(ctx context.Context, entity interface{}) (string, error) {
+func (_m *UserRepository) Create(ctx context.Context, entity entities.User) (string, error) {
 	ret := _m.Called(ctx, entity)
 
 	if len(ret) == 0 {
@@ -23,16 +26,16 @@ func (_m *UserRepository) Create(ctx context.Context, entity interface{}) (strin
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}) (string, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, entities.User) (string, error)); ok {
 		return rf(ctx, entity)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}) string); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, entities.User) string); ok {
 		r0 = rf(ctx, entity)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, interface{}) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, entities.User) error); ok {
 		r1 = rf(ctx, entity)
 	} else {
 		r1 = ret.Error(1)
@@ -41,9 +44,9 @@ func (_m *UserRepository) Create(ctx context.Context, entity interface{}) (strin
 	return r0, r1
 }
 
-// CreateMany provides a mock function with given fields: ctx, entities
-func (_m *UserRepository) CreateMany(ctx context.Context, entities []interface{}) ([]string, error) {
-	ret := _m.Called(ctx, entities)
+// CreateMany provides a mock function with given fields: ctx, _a1
+func (_m *UserRepository) CreateMany(ctx context.Context, _a1 []entities.User) ([]string, error) {
+	ret := _m.Called(ctx, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateMany")
@@ -51,19 +54,19 @@ func (_m *UserRepository) CreateMany(ctx context.Context, entities []interface{}
 
 	var r0 []string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []interface{}) ([]string, error)); ok {
-		return rf(ctx, entities)
+	if rf, ok := ret.Get(0).(func(context.Context, []entities.User) ([]string, error)); ok {
+		return rf(ctx, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []interface{}) []string); ok {
-		r0 = rf(ctx, entities)
+	if rf, ok := ret.Get(0).(func(context.Context, []entities.User) []string); ok {
+		r0 = rf(ctx, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []interface{}) error); ok {
-		r1 = rf(ctx, entities)
+	if rf, ok := ret.Get(1).(func(context.Context, []entities.User) error); ok {
+		r1 = rf(ctx, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -89,28 +92,46 @@ func (_m *UserRepository) Delete(ctx context.Context, ID string) error {
 	return r0
 }
 
+// DeleteMany provides a mock function with given fields: ctx, IDs
+func (_m *UserRepository) DeleteMany(ctx context.Context, IDs []string) error {
+	ret := _m.Called(ctx, IDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMany")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = rf(ctx, IDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Get provides a mock function with given fields: ctx, filter, skip, take
-func (_m *UserRepository) Get(ctx context.Context, filter map[string]interface{}, skip *int, take *int) ([]interface{}, error) {
+func (_m *UserRepository) Get(ctx context.Context, filter models.UserFilter, skip *int, take *int) ([]entities.User, error) {
 	ret := _m.Called(ctx, filter, skip, take)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Get")
 	}
 
-	var r0 []interface{}
+	var r0 []entities.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}, *int, *int) ([]interface{}, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, models.UserFilter, *int, *int) ([]entities.User, error)); ok {
 		return rf(ctx, filter, skip, take)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}, *int, *int) []interface{}); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, models.UserFilter, *int, *int) []entities.User); ok {
 		r0 = rf(ctx, filter, skip, take)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]interface{})
+			r0 = ret.Get(0).([]entities.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, map[string]interface{}, *int, *int) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, models.UserFilter, *int, *int) error); ok {
 		r1 = rf(ctx, filter, skip, take)
 	} else {
 		r1 = ret.Error(1)
@@ -120,24 +141,22 @@ func (_m *UserRepository) Get(ctx context.Context, filter map[string]interface{}
 }
 
 // GetByID provides a mock function with given fields: ctx, ID
-func (_m *UserRepository) GetByID(ctx context.Context, ID string) (interface{}, error) {
+func (_m *UserRepository) GetByID(ctx context.Context, ID string) (entities.User, error) {
 	ret := _m.Called(ctx, ID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByID")
 	}
 
-	var r0 interface{}
+	var r0 entities.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (interface{}, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entities.User, error)); ok {
 		return rf(ctx, ID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) interface{}); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) entities.User); ok {
 		r0 = rf(ctx, ID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(interface{})
-		}
+		r0 = ret.Get(0).(entities.User)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
@@ -149,8 +168,56 @@ func (_m *UserRepository) GetByID(ctx context.Context, ID string) (interface{},
 	return r0, r1
 }
 
+// Iterate provides a mock function with given fields: ctx, filter, fn
+func (_m *UserRepository) Iterate(ctx context.Context, filter models.UserFilter, fn func(entities.User) error) error {
+	ret := _m.Called(ctx, filter, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Iterate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.UserFilter, func(entities.User) error) error); ok {
+		r0 = rf(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, query, offset, limit
+func (_m *UserRepository) Search(ctx context.Context, query string, offset int, limit int) ([]entities.User, error) {
+	ret := _m.Called(ctx, query, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []entities.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) ([]entities.User, error)); ok {
+		return rf(ctx, query, offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []entities.User); ok {
+		r0 = rf(ctx, query, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, query, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, ID, entity
-func (_m *UserRepository) Update(ctx context.Context, ID string, entity interface{}) error {
+func (_m *UserRepository) Update(ctx context.Context, ID string, entity entities.User) error {
 	ret := _m.Called(ctx, ID, entity)
 
 	if len(ret) == 0 {
@@ -158,7 +225,7 @@ func (_m *UserRepository) Update(ctx context.Context, ID string, entity interfac
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, entities.User) error); ok {
 		r0 = rf(ctx, ID, entity)
 	} else {
 		r0 = ret.Error(0)
@@ -167,6 +234,24 @@ func (_m *UserRepository) Update(ctx context.Context, ID string, entity interfac
 	return r0
 }
 
+// UpdateMany provides a mock function with given fields: ctx, IDs, _a2
+func (_m *UserRepository) UpdateMany(ctx context.Context, IDs []string, _a2 []entities.User) error {
+	ret := _m.Called(ctx, IDs, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMany")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, []entities.User) error); ok {
+		r0 = rf(ctx, IDs, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserRepository creates a new instance of UserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepository(t interface {
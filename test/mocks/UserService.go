@@ -4,9 +4,11 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 
-	models "github.com/sergicanet9/go-hexagonal-api/core/models"
 	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/sergicanet9/go-hexagonal-api/core/models"
 )
 
 // UserService is an autogenerated mock type for the UserService type
@@ -14,6 +16,34 @@ type UserService struct {
 	mock.Mock
 }
 
+// ConfirmEmailChange provides a mock function with given fields: ctx, req
+func (_m *UserService) ConfirmEmailChange(ctx context.Context, req models.ConfirmEmailChangeReq) (models.ConfirmEmailChangeResp, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmEmailChange")
+	}
+
+	var r0 models.ConfirmEmailChangeResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ConfirmEmailChangeReq) (models.ConfirmEmailChangeResp, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.ConfirmEmailChangeReq) models.ConfirmEmailChangeResp); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(models.ConfirmEmailChangeResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.ConfirmEmailChangeReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, user
 func (_m *UserService) Create(ctx context.Context, user models.CreateUserReq) (models.CreateUserResp, error) {
 	ret := _m.Called(ctx, user)
@@ -42,9 +72,9 @@ func (_m *UserService) Create(ctx context.Context, user models.CreateUserReq) (m
 	return r0, r1
 }
 
-// CreateMany provides a mock function with given fields: ctx, users
-func (_m *UserService) CreateMany(ctx context.Context, users []models.CreateUserReq) (models.CreateManyUserResp, error) {
-	ret := _m.Called(ctx, users)
+// CreateMany provides a mock function with given fields: ctx, users, partial
+func (_m *UserService) CreateMany(ctx context.Context, users []models.CreateUserReq, partial bool) (models.CreateManyUserResp, error) {
+	ret := _m.Called(ctx, users, partial)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateMany")
@@ -52,17 +82,17 @@ func (_m *UserService) CreateMany(ctx context.Context, users []models.CreateUser
 
 	var r0 models.CreateManyUserResp
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []models.CreateUserReq) (models.CreateManyUserResp, error)); ok {
-		return rf(ctx, users)
+	if rf, ok := ret.Get(0).(func(context.Context, []models.CreateUserReq, bool) (models.CreateManyUserResp, error)); ok {
+		return rf(ctx, users, partial)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []models.CreateUserReq) models.CreateManyUserResp); ok {
-		r0 = rf(ctx, users)
+	if rf, ok := ret.Get(0).(func(context.Context, []models.CreateUserReq, bool) models.CreateManyUserResp); ok {
+		r0 = rf(ctx, users, partial)
 	} else {
 		r0 = ret.Get(0).(models.CreateManyUserResp)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []models.CreateUserReq) error); ok {
-		r1 = rf(ctx, users)
+	if rf, ok := ret.Get(1).(func(context.Context, []models.CreateUserReq, bool) error); ok {
+		r1 = rf(ctx, users, partial)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -88,6 +118,100 @@ func (_m *UserService) Delete(ctx context.Context, ID string) error {
 	return r0
 }
 
+// DeleteMany provides a mock function with given fields: ctx, IDs, partial
+func (_m *UserService) DeleteMany(ctx context.Context, IDs []string, partial bool) (models.BulkUserResp, error) {
+	ret := _m.Called(ctx, IDs, partial)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMany")
+	}
+
+	var r0 models.BulkUserResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, bool) (models.BulkUserResp, error)); ok {
+		return rf(ctx, IDs, partial)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, bool) models.BulkUserResp); ok {
+		r0 = rf(ctx, IDs, partial)
+	} else {
+		r0 = ret.Get(0).(models.BulkUserResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, bool) error); ok {
+		r1 = rf(ctx, IDs, partial)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EraseUserData provides a mock function with given fields: ctx, userID
+func (_m *UserService) EraseUserData(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EraseUserData")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Export provides a mock function with given fields: ctx, req, w
+func (_m *UserService) Export(ctx context.Context, req models.ExportUsersReq, w io.Writer) error {
+	ret := _m.Called(ctx, req, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ExportUsersReq, io.Writer) error); ok {
+		r0 = rf(ctx, req, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportUserData provides a mock function with given fields: ctx, userID
+func (_m *UserService) ExportUserData(ctx context.Context, userID string) (interface{}, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportUserData")
+	}
+
+	var r0 interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (interface{}, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) interface{}); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAll provides a mock function with given fields: ctx
 func (_m *UserService) GetAll(ctx context.Context) ([]models.GetUserResp, error) {
 	ret := _m.Called(ctx)
@@ -194,6 +318,34 @@ func (_m *UserService) GetUserClaims(ctx context.Context) map[int]string {
 	return r0
 }
 
+// Import provides a mock function with given fields: ctx, req
+func (_m *UserService) Import(ctx context.Context, req models.ImportUsersReq) (models.ImportUsersResp, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 models.ImportUsersResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.ImportUsersReq) (models.ImportUsersResp, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.ImportUsersReq) models.ImportUsersResp); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(models.ImportUsersResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.ImportUsersReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Login provides a mock function with given fields: ctx, credentials
 func (_m *UserService) Login(ctx context.Context, credentials models.LoginUserReq) (models.LoginUserResp, error) {
 	ret := _m.Called(ctx, credentials)
@@ -222,6 +374,80 @@ func (_m *UserService) Login(ctx context.Context, credentials models.LoginUserRe
 	return r0, r1
 }
 
+// Name provides a mock function with no fields
+func (_m *UserService) Name() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Name")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// PruneChanges provides a mock function with given fields: ctx
+func (_m *UserService) PruneChanges(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PruneChanges")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, req
+func (_m *UserService) Search(ctx context.Context, req models.SearchUsersReq) (models.SearchUsersResp, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 models.SearchUsersResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.SearchUsersReq) (models.SearchUsersResp, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.SearchUsersReq) models.SearchUsersResp); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(models.SearchUsersResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.SearchUsersReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, ID, user
 func (_m *UserService) Update(ctx context.Context, ID string, user models.UpdateUserReq) error {
 	ret := _m.Called(ctx, ID, user)
@@ -240,6 +466,52 @@ func (_m *UserService) Update(ctx context.Context, ID string, user models.Update
 	return r0
 }
 
+// UpdateMany provides a mock function with given fields: ctx, users, partial
+func (_m *UserService) UpdateMany(ctx context.Context, users []models.UpdateManyUserReq, partial bool) (models.BulkUserResp, error) {
+	ret := _m.Called(ctx, users, partial)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMany")
+	}
+
+	var r0 models.BulkUserResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.UpdateManyUserReq, bool) (models.BulkUserResp, error)); ok {
+		return rf(ctx, users, partial)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.UpdateManyUserReq, bool) models.BulkUserResp); ok {
+		r0 = rf(ctx, users, partial)
+	} else {
+		r0 = ret.Get(0).(models.BulkUserResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.UpdateManyUserReq, bool) error); ok {
+		r1 = rf(ctx, users, partial)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Watch provides a mock function with given fields: ctx, resumeToken, fn
+func (_m *UserService) Watch(ctx context.Context, resumeToken string, fn func(models.UserChangeResp) error) error {
+	ret := _m.Called(ctx, resumeToken, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Watch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, func(models.UserChangeResp) error) error); ok {
+		r0 = rf(ctx, resumeToken, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserService creates a new instance of UserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserService(t interface {
@@ -0,0 +1,221 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entities "github.com/sergicanet9/go-hexagonal-api/core/entities"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// WebhookDeliveryRepository is an autogenerated mock type for the WebhookDeliveryRepository type
+type WebhookDeliveryRepository struct {
+	mock.Mock
+}
+
+// Claim provides a mock function with given fields: ctx, now, leaseUntil, limit
+func (_m *WebhookDeliveryRepository) Claim(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]entities.WebhookDelivery, error) {
+	ret := _m.Called(ctx, now, leaseUntil, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Claim")
+	}
+
+	var r0 []entities.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) ([]entities.WebhookDelivery, error)); ok {
+		return rf(ctx, now, leaseUntil, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) []entities.WebhookDelivery); ok {
+		r0 = rf(ctx, now, leaseUntil, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, int) error); ok {
+		r1 = rf(ctx, now, leaseUntil, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateMany provides a mock function with given fields: ctx, deliveries
+func (_m *WebhookDeliveryRepository) CreateMany(ctx context.Context, deliveries []entities.WebhookDelivery) error {
+	ret := _m.Called(ctx, deliveries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateMany")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []entities.WebhookDelivery) error); ok {
+		r0 = rf(ctx, deliveries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteBySubscription provides a mock function with given fields: ctx, subscriptionID
+func (_m *WebhookDeliveryRepository) DeleteBySubscription(ctx context.Context, subscriptionID string) error {
+	ret := _m.Called(ctx, subscriptionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBySubscription")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, subscriptionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByAggregateID provides a mock function with given fields: ctx, aggregateID
+func (_m *WebhookDeliveryRepository) GetByAggregateID(ctx context.Context, aggregateID string) ([]entities.WebhookDelivery, error) {
+	ret := _m.Called(ctx, aggregateID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByAggregateID")
+	}
+
+	var r0 []entities.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]entities.WebhookDelivery, error)); ok {
+		return rf(ctx, aggregateID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []entities.WebhookDelivery); ok {
+		r0 = rf(ctx, aggregateID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, aggregateID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, ID
+func (_m *WebhookDeliveryRepository) GetByID(ctx context.Context, ID string) (entities.WebhookDelivery, error) {
+	ret := _m.Called(ctx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 entities.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entities.WebhookDelivery, error)); ok {
+		return rf(ctx, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entities.WebhookDelivery); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		r0 = ret.Get(0).(entities.WebhookDelivery)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByStatus provides a mock function with given fields: ctx, status
+func (_m *WebhookDeliveryRepository) GetByStatus(ctx context.Context, status entities.WebhookDeliveryStatus) ([]entities.WebhookDelivery, error) {
+	ret := _m.Called(ctx, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByStatus")
+	}
+
+	var r0 []entities.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.WebhookDeliveryStatus) ([]entities.WebhookDelivery, error)); ok {
+		return rf(ctx, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entities.WebhookDeliveryStatus) []entities.WebhookDelivery); ok {
+		r0 = rf(ctx, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entities.WebhookDeliveryStatus) error); ok {
+		r1 = rf(ctx, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, delivery
+func (_m *WebhookDeliveryRepository) Update(ctx context.Context, delivery entities.WebhookDelivery) error {
+	ret := _m.Called(ctx, delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.WebhookDelivery) error); ok {
+		r0 = rf(ctx, delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateBody provides a mock function with given fields: ctx, ID, body
+func (_m *WebhookDeliveryRepository) UpdateBody(ctx context.Context, ID string, body []byte) error {
+	ret := _m.Called(ctx, ID, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBody")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte) error); ok {
+		r0 = rf(ctx, ID, body)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWebhookDeliveryRepository creates a new instance of WebhookDeliveryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookDeliveryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookDeliveryRepository {
+	mock := &WebhookDeliveryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
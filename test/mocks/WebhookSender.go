@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookSender is an autogenerated mock type for the WebhookSender type
+type WebhookSender struct {
+	mock.Mock
+}
+
+// Send provides a mock function with given fields: ctx, URL, headers, body
+func (_m *WebhookSender) Send(ctx context.Context, URL string, headers map[string]string, body []byte) error {
+	ret := _m.Called(ctx, URL, headers, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Send")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]string, []byte) error); ok {
+		r0 = rf(ctx, URL, headers, body)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWebhookSender creates a new instance of WebhookSender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookSender(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookSender {
+	mock := &WebhookSender{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
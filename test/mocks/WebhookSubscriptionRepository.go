@@ -0,0 +1,151 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entities "github.com/sergicanet9/go-hexagonal-api/core/entities"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookSubscriptionRepository is an autogenerated mock type for the WebhookSubscriptionRepository type
+type WebhookSubscriptionRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, subscription
+func (_m *WebhookSubscriptionRepository) Create(ctx context.Context, subscription entities.WebhookSubscription) (string, error) {
+	ret := _m.Called(ctx, subscription)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.WebhookSubscription) (string, error)); ok {
+		return rf(ctx, subscription)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entities.WebhookSubscription) string); ok {
+		r0 = rf(ctx, subscription)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entities.WebhookSubscription) error); ok {
+		r1 = rf(ctx, subscription)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, ID
+func (_m *WebhookSubscriptionRepository) Delete(ctx context.Context, ID string) error {
+	ret := _m.Called(ctx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx
+func (_m *WebhookSubscriptionRepository) GetAll(ctx context.Context) ([]entities.WebhookSubscription, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []entities.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entities.WebhookSubscription, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entities.WebhookSubscription); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, ID
+func (_m *WebhookSubscriptionRepository) GetByID(ctx context.Context, ID string) (entities.WebhookSubscription, error) {
+	ret := _m.Called(ctx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 entities.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (entities.WebhookSubscription, error)); ok {
+		return rf(ctx, ID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) entities.WebhookSubscription); ok {
+		r0 = rf(ctx, ID)
+	} else {
+		r0 = ret.Get(0).(entities.WebhookSubscription)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, ID, subscription
+func (_m *WebhookSubscriptionRepository) Update(ctx context.Context, ID string, subscription entities.WebhookSubscription) error {
+	ret := _m.Called(ctx, ID, subscription)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entities.WebhookSubscription) error); ok {
+		r0 = rf(ctx, ID, subscription)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWebhookSubscriptionRepository creates a new instance of WebhookSubscriptionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookSubscriptionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookSubscriptionRepository {
+	mock := &WebhookSubscriptionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,565 @@
+// Package repositorytest holds the conformance test suites that every adapter of a repository port has to pass,
+// so that the adapters can be swapped without changing the behavior of the API.
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergicanet9/go-hexagonal-api/core/entities"
+	"github.com/sergicanet9/go-hexagonal-api/core/models"
+	"github.com/sergicanet9/go-hexagonal-api/core/ports"
+	"github.com/sergicanet9/scv-go-tools/v4/wrappers"
+	"github.com/stretchr/testify/assert"
+)
+
+// UserRepository runs the conformance test suite of the user repository port against repo.
+// Every test creates its own users with unique emails and names, so repo can hold other users.
+func UserRepository(t *testing.T, repo ports.UserRepository) {
+	t.Run("CRUD", func(t *testing.T) { userCRUD(t, repo) })
+	t.Run("NotFound", func(t *testing.T) { userNotFound(t, repo) })
+	t.Run("UniqueViolation", func(t *testing.T) { userUniqueViolation(t, repo) })
+	t.Run("Rollback", func(t *testing.T) { userRollback(t, repo) })
+	t.Run("Pagination", func(t *testing.T) { userPagination(t, repo) })
+	t.Run("Search", func(t *testing.T) { userSearch(t, repo) })
+}
+
+// userCRUD checks that the users are stored, read, updated and deleted as given
+func userCRUD(t *testing.T, repo ports.UserRepository) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T)
+	}{
+		{
+			name: "Create",
+			run: func(t *testing.T) {
+				// Arrange
+				user := newUser()
+
+				// Act
+				ID, err := repo.Create(context.Background(), user)
+
+				// Assert
+				assert.Nil(t, err)
+				got := getByID(t, repo, ID)
+				user.ID = ID
+				assertUser(t, user, got)
+			},
+		},
+		{
+			name: "CreateMany",
+			run: func(t *testing.T) {
+				// Arrange
+				users := []entities.User{newUser(), newUser()}
+
+				// Act
+				IDs, err := repo.CreateMany(context.Background(), users)
+
+				// Assert
+				assert.Nil(t, err)
+				if assert.Len(t, IDs, 2) {
+					assert.Equal(t, users[0].Email, getByID(t, repo, IDs[0]).Email)
+					assert.Equal(t, users[1].Email, getByID(t, repo, IDs[1]).Email)
+				}
+			},
+		},
+		{
+			name: "Get_Filter",
+			run: func(t *testing.T) {
+				// Arrange
+				user := create(t, repo)
+				create(t, repo)
+				filter := models.UserFilter{Name: user.Name, EmailNormalized: user.EmailNormalized}
+
+				// Act
+				got, err := repo.Get(context.Background(), filter, nil, nil)
+
+				// Assert
+				assert.Nil(t, err)
+				if assert.Len(t, got, 1) {
+					assertUser(t, user, got[0])
+				}
+			},
+		},
+		{
+			name: "Get_FilterQuotes",
+			run: func(t *testing.T) {
+				// Arrange
+				user := newUser()
+				user.Surnames = "O'Brien"
+				ID, err := repo.Create(context.Background(), user)
+				if err != nil {
+					t.Fatal(err)
+				}
+				filter := models.UserFilter{Name: user.Name, Surnames: user.Surnames}
+
+				// Act
+				got, err := repo.Get(context.Background(), filter, nil, nil)
+
+				// Assert
+				assert.Nil(t, err)
+				if assert.Len(t, got, 1) {
+					assert.Equal(t, ID, got[0].ID)
+				}
+			},
+		},
+		{
+			name: "Update",
+			run: func(t *testing.T) {
+				// Arrange
+				user := create(t, repo)
+				user.Name = uniqueName()
+				user.ClaimIDs = []int32{0, 1}
+				user.UpdatedAt = time.Now().UTC().Truncate(time.Millisecond)
+
+				// Act
+				err := repo.Update(context.Background(), user.ID, user)
+
+				// Assert
+				assert.Nil(t, err)
+				assertUser(t, user, getByID(t, repo, user.ID))
+			},
+		},
+		{
+			name: "Update_Unchanged",
+			run: func(t *testing.T) {
+				// Arrange
+				user := create(t, repo)
+
+				// Act
+				err := repo.Update(context.Background(), user.ID, user)
+
+				// Assert
+				assert.Nil(t, err)
+				assertUser(t, user, getByID(t, repo, user.ID))
+			},
+		},
+		{
+			name: "UpdateMany",
+			run: func(t *testing.T) {
+				// Arrange
+				first, second := create(t, repo), create(t, repo)
+				first.Name, second.Name = uniqueName(), uniqueName()
+
+				// Act
+				err := repo.UpdateMany(context.Background(), []string{first.ID, second.ID}, []entities.User{first, second})
+
+				// Assert
+				assert.Nil(t, err)
+				assert.Equal(t, first.Name, getByID(t, repo, first.ID).Name)
+				assert.Equal(t, second.Name, getByID(t, repo, second.ID).Name)
+			},
+		},
+		{
+			name: "Delete",
+			run: func(t *testing.T) {
+				// Arrange
+				user := create(t, repo)
+
+				// Act
+				err := repo.Delete(context.Background(), user.ID)
+
+				// Assert
+				assert.Nil(t, err)
+				_, err = repo.GetByID(context.Background(), user.ID)
+				assert.ErrorIs(t, err, wrappers.NonExistentErr)
+			},
+		},
+		{
+			name: "DeleteMany",
+			run: func(t *testing.T) {
+				// Arrange
+				first, second := create(t, repo), create(t, repo)
+
+				// Act
+				err := repo.DeleteMany(context.Background(), []string{first.ID, second.ID})
+
+				// Assert
+				assert.Nil(t, err)
+				for _, ID := range []string{first.ID, second.ID} {
+					_, err = repo.GetByID(context.Background(), ID)
+					assert.ErrorIs(t, err, wrappers.NonExistentErr)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
+
+// userNotFound checks that every operation on a missing user returns a non existent error,
+// for the IDs that no longer exist as well as for the ones that cannot exist
+func userNotFound(t *testing.T, repo ports.UserRepository) {
+	IDs := []struct {
+		name string
+		ID   func(t *testing.T) string
+	}{
+		{name: "Deleted", ID: func(t *testing.T) string { return deleted(t, repo) }},
+		{name: "Malformed", ID: func(t *testing.T) string { return "malformed-id" }},
+	}
+
+	operations := []struct {
+		name string
+		act  func(ID string) error
+	}{
+		{
+			name: "GetByID",
+			act: func(ID string) error {
+				_, err := repo.GetByID(context.Background(), ID)
+				return err
+			},
+		},
+		{
+			name: "Update",
+			act: func(ID string) error {
+				return repo.Update(context.Background(), ID, newUser())
+			},
+		},
+		{
+			name: "UpdateMany",
+			act: func(ID string) error {
+				return repo.UpdateMany(context.Background(), []string{ID}, []entities.User{newUser()})
+			},
+		},
+		{
+			name: "Delete",
+			act: func(ID string) error {
+				return repo.Delete(context.Background(), ID)
+			},
+		},
+		{
+			name: "DeleteMany",
+			act: func(ID string) error {
+				return repo.DeleteMany(context.Background(), []string{ID})
+			},
+		},
+	}
+
+	for _, tt := range IDs {
+		for _, op := range operations {
+			t.Run(fmt.Sprintf("%s_%s", op.name, tt.name), func(t *testing.T) {
+				// Arrange
+				ID := tt.ID(t)
+
+				// Act
+				err := op.act(ID)
+
+				// Assert
+				assert.ErrorIs(t, err, wrappers.NonExistentErr)
+			})
+		}
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		// Act
+		_, err := repo.Get(context.Background(), models.UserFilter{EmailNormalized: newUser().EmailNormalized}, nil, nil)
+
+		// Assert
+		assert.ErrorIs(t, err, wrappers.NonExistentErr)
+	})
+}
+
+// userUniqueViolation checks that the users cannot share their normalized email, reporting the email in use as an AlreadyExistsErr
+func userUniqueViolation(t *testing.T, repo ports.UserRepository) {
+	tests := []struct {
+		name string
+		act  func(t *testing.T, existing entities.User) error
+	}{
+		{
+			name: "Create",
+			act: func(t *testing.T, existing entities.User) error {
+				user := newUser()
+				user.EmailNormalized = existing.EmailNormalized
+				_, err := repo.Create(context.Background(), user)
+				return err
+			},
+		},
+		{
+			name: "CreateMany",
+			act: func(t *testing.T, existing entities.User) error {
+				user := newUser()
+				user.EmailNormalized = existing.EmailNormalized
+				_, err := repo.CreateMany(context.Background(), []entities.User{newUser(), user})
+				return err
+			},
+		},
+		{
+			name: "Update",
+			act: func(t *testing.T, existing entities.User) error {
+				user := create(t, repo)
+				user.EmailNormalized = existing.EmailNormalized
+				return repo.Update(context.Background(), user.ID, user)
+			},
+		},
+		{
+			name: "UpdateMany",
+			act: func(t *testing.T, existing entities.User) error {
+				user := create(t, repo)
+				user.EmailNormalized = existing.EmailNormalized
+				return repo.UpdateMany(context.Background(), []string{user.ID}, []entities.User{user})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			existing := create(t, repo)
+
+			// Act
+			err := tt.act(t, existing)
+
+			// Assert
+			assert.ErrorIs(t, err, entities.AlreadyExistsErr)
+			assert.EqualError(t, err, fmt.Sprintf("email %s is already in use", existing.EmailNormalized))
+			got, getErr := repo.Get(context.Background(), models.UserFilter{EmailNormalized: existing.EmailNormalized}, nil, nil)
+			assert.Nil(t, getErr)
+			if assert.Len(t, got, 1) {
+				assert.Equal(t, existing.ID, got[0].ID)
+			}
+		})
+	}
+}
+
+// userRollback checks that the bulk operations change nothing when one of the users fails
+func userRollback(t *testing.T, repo ports.UserRepository) {
+	tests := []struct {
+		name   string
+		act    func(t *testing.T, user entities.User) error
+		assert func(t *testing.T, user entities.User)
+	}{
+		{
+			name: "CreateMany",
+			act: func(t *testing.T, user entities.User) error {
+				first := newUser()
+				first.Name = user.Name
+				duplicated := newUser()
+				duplicated.EmailNormalized = user.EmailNormalized
+				_, err := repo.CreateMany(context.Background(), []entities.User{first, newUser(), duplicated})
+				return err
+			},
+			assert: func(t *testing.T, user entities.User) {
+				got, err := repo.Get(context.Background(), models.UserFilter{Name: user.Name}, nil, nil)
+				assert.Nil(t, err)
+				assert.Len(t, got, 1)
+			},
+		},
+		{
+			name: "UpdateMany",
+			act: func(t *testing.T, user entities.User) error {
+				updated := user
+				updated.Name = uniqueName()
+				return repo.UpdateMany(context.Background(), []string{user.ID, deleted(t, repo)}, []entities.User{updated, newUser()})
+			},
+			assert: func(t *testing.T, user entities.User) {
+				assert.Equal(t, user.Name, getByID(t, repo, user.ID).Name)
+			},
+		},
+		{
+			name: "DeleteMany",
+			act: func(t *testing.T, user entities.User) error {
+				return repo.DeleteMany(context.Background(), []string{user.ID, deleted(t, repo)})
+			},
+			assert: func(t *testing.T, user entities.User) {
+				getByID(t, repo, user.ID)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			user := create(t, repo)
+
+			// Act
+			err := tt.act(t, user)
+
+			// Assert
+			assert.NotNil(t, err)
+			tt.assert(t, user)
+		})
+	}
+}
+
+// userPagination checks that the pages of Get are consistent with each other
+func userPagination(t *testing.T, repo ports.UserRepository) {
+	name := uniqueName()
+	for i := 0; i < 3; i++ {
+		user := newUser()
+		user.Name = name
+		if _, err := repo.Create(context.Background(), user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	filter := models.UserFilter{Name: name}
+
+	all, err := repo.Get(context.Background(), filter, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		skip            *int
+		take            *int
+		expectedIndexes []int
+	}{
+		{name: "All", expectedIndexes: []int{0, 1, 2}},
+		{name: "Take", take: ptr(2), expectedIndexes: []int{0, 1}},
+		{name: "Skip", skip: ptr(1), expectedIndexes: []int{1, 2}},
+		{name: "SkipTake", skip: ptr(1), take: ptr(1), expectedIndexes: []int{1}},
+		{name: "SkipAll", skip: ptr(3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got, err := repo.Get(context.Background(), filter, tt.skip, tt.take)
+
+			// Assert
+			if len(tt.expectedIndexes) == 0 {
+				assert.ErrorIs(t, err, wrappers.NonExistentErr)
+				return
+			}
+			assert.Nil(t, err)
+			var expectedIDs, IDs []string
+			for _, i := range tt.expectedIndexes {
+				expectedIDs = append(expectedIDs, all[i].ID)
+			}
+			for _, user := range got {
+				IDs = append(IDs, user.ID)
+			}
+			assert.Equal(t, expectedIDs, IDs)
+		})
+	}
+}
+
+// userSearch checks that Search finds the users by the words of their names, surnames and emails
+func userSearch(t *testing.T, repo ports.UserRepository) {
+	tests := []struct {
+		name  string
+		query func(user entities.User) string
+	}{
+		{name: "Name", query: func(user entities.User) string { return user.Name }},
+		{name: "Surnames", query: func(user entities.User) string { return user.Surnames }},
+		{name: "CaseInsensitive", query: func(user entities.User) string { return strings.ToUpper(user.Surnames) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			user := newUser()
+			user.Surnames = uniqueName()
+			ID, err := repo.Create(context.Background(), user)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Act
+			got, err := repo.Search(context.Background(), tt.query(user), 0, 10)
+
+			// Assert
+			assert.Nil(t, err)
+			if assert.NotEmpty(t, got) {
+				assert.Equal(t, ID, got[0].ID)
+			}
+		})
+	}
+
+	t.Run("NoMatches", func(t *testing.T) {
+		// Act
+		got, err := repo.Search(context.Background(), uniqueName(), 0, 10)
+
+		// Assert
+		assert.Nil(t, err)
+		assert.NotNil(t, got)
+		assert.Empty(t, got)
+	})
+}
+
+// newUser returns a user with a unique name and email that is not stored yet
+func newUser() entities.User {
+	name := uniqueName()
+	email := fmt.Sprintf("%s@repositorytest.com", name)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	return entities.User{
+		Name:            name,
+		Surnames:        "test",
+		Email:           email,
+		EmailNormalized: entities.NormalizeEmail(email),
+		PasswordHash:    "hash",
+		ClaimIDs:        []int32{0},
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// uniqueName returns a random name made of letters only, so that it is a single word for the full-text searches
+func uniqueName() string {
+	name := []byte("user")
+	for i := 0; i < 12; i++ {
+		name = append(name, byte('a'+rand.IntN(26)))
+	}
+	return string(name)
+}
+
+// create stores a new user and returns it along with its ID
+func create(t *testing.T, repo ports.UserRepository) entities.User {
+	t.Helper()
+
+	user := newUser()
+	ID, err := repo.Create(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.ID = ID
+	return user
+}
+
+// deleted returns the ID of a user that no longer exists
+func deleted(t *testing.T, repo ports.UserRepository) string {
+	t.Helper()
+
+	user := create(t, repo)
+	if err := repo.Delete(context.Background(), user.ID); err != nil {
+		t.Fatal(err)
+	}
+	return user.ID
+}
+
+// assertUser checks that got holds the fields of expected, the timestamps being compared to the millisecond as not every database stores nanoseconds
+func assertUser(t *testing.T, expected, got entities.User) {
+	t.Helper()
+
+	assert.Equal(t, expected.ID, got.ID)
+	assert.Equal(t, expected.Name, got.Name)
+	assert.Equal(t, expected.Surnames, got.Surnames)
+	assert.Equal(t, expected.Email, got.Email)
+	assert.Equal(t, expected.EmailNormalized, got.EmailNormalized)
+	assert.Equal(t, expected.PasswordHash, got.PasswordHash)
+	assert.Equal(t, expected.ClaimIDs, got.ClaimIDs)
+	assert.Equal(t, expected.AvatarURL, got.AvatarURL)
+	assert.WithinDuration(t, expected.CreatedAt, got.CreatedAt, time.Millisecond)
+	assert.WithinDuration(t, expected.UpdatedAt, got.UpdatedAt, time.Millisecond)
+}
+
+// getByID returns the stored user with the given ID
+func getByID(t *testing.T, repo ports.UserRepository, ID string) entities.User {
+	t.Helper()
+
+	user, err := repo.GetByID(context.Background(), ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}